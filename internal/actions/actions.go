@@ -0,0 +1,182 @@
+// Package actions makes deterministic edits to a repo: text and regex
+// replacements, added and removed files, and YAML or JSON keys set or
+// deleted. A pipeline of actions runs before the AI tool, or instead of it.
+package actions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/util"
+)
+
+// Apply runs steps in order in repoPath and returns a line per step saying
+// what it changed. Each step sees the files the previous steps added or
+// removed.
+func Apply(ctx context.Context, repoPath string, steps []config.Action) ([]string, error) {
+	var lines []string
+	for i, step := range steps {
+		if ctx.Err() != nil {
+			return lines, ctx.Err()
+		}
+		line, err := apply(ctx, repoPath, step)
+		if err != nil {
+			return lines, fmt.Errorf("step %d (%s): %w", i+1, step.Type, err)
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// Summary describes what the pipeline changed, for the PR description.
+func Summary(pipeline string, lines []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Applies the %s actions:\n", pipeline)
+	for _, line := range lines {
+		b.WriteString("\n- " + line)
+	}
+	return b.String()
+}
+
+// PromptNote tells the AI tool what the actions already changed, so it builds
+// on the edits rather than redoing or undoing them.
+func PromptNote(lines []string) string {
+	return "\n\nThese deterministic edits have already been made to the repository. Keep them and build on them:\n- " + strings.Join(lines, "\n- ")
+}
+
+func apply(ctx context.Context, repoPath string, step config.Action) (string, error) {
+	if step.Type == config.ActionAddFile {
+		return addFile(repoPath, step)
+	}
+
+	files, err := matchingFiles(ctx, repoPath, step.Files)
+	if err != nil {
+		return "", err
+	}
+	switch step.Type {
+	case config.ActionReplace:
+		changed, err := editFiles(repoPath, files, func(name string, data []byte) ([]byte, error) {
+			return bytes.ReplaceAll(data, []byte(step.Search), []byte(step.Replace)), nil
+		})
+		return fmt.Sprintf("Replaced %q with %q in %s", step.Search, step.Replace, countFiles(changed)), err
+	case config.ActionRegexReplace:
+		re, err := regexp.Compile(step.Search)
+		if err != nil {
+			return "", err
+		}
+		changed, err := editFiles(repoPath, files, func(name string, data []byte) ([]byte, error) {
+			return re.ReplaceAll(data, []byte(step.Replace)), nil
+		})
+		return fmt.Sprintf("Replaced matches of `%s` with %q in %s", step.Search, step.Replace, countFiles(changed)), err
+	case config.ActionRemoveFile:
+		for _, name := range files {
+			if err := os.Remove(filepath.Join(repoPath, name)); err != nil {
+				return "", err
+			}
+		}
+		if len(files) == 0 {
+			return fmt.Sprintf("Removed no files (none matched %s)", strings.Join(step.Files, ", ")), nil
+		}
+		return "Removed " + strings.Join(files, ", "), nil
+	case config.ActionSetKey:
+		changed, err := editFiles(repoPath, files, func(name string, data []byte) ([]byte, error) {
+			return patchFile(name, data, step.Key, step.Value, false)
+		})
+		return fmt.Sprintf("Set `%s` in %s", step.Key, countFiles(changed)), err
+	case config.ActionDeleteKey:
+		changed, err := editFiles(repoPath, files, func(name string, data []byte) ([]byte, error) {
+			return patchFile(name, data, step.Key, nil, true)
+		})
+		return fmt.Sprintf("Deleted `%s` from %s", step.Key, countFiles(changed)), err
+	}
+	return "", fmt.Errorf("unknown action type %q", step.Type)
+}
+
+// addFile writes the step's content to its path, creating directories.
+func addFile(repoPath string, step config.Action) (string, error) {
+	full := filepath.Join(repoPath, step.Path)
+	if existing, err := os.ReadFile(full); err == nil && string(existing) == step.Content {
+		return fmt.Sprintf("Left %s as it was (already up to date)", step.Path), nil
+	}
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(full, []byte(step.Content), 0o644); err != nil {
+		return "", err
+	}
+	return "Wrote " + step.Path, nil
+}
+
+// matchingFiles lists the repo's files, tracked or new, that match any of
+// patterns and still exist.
+func matchingFiles(ctx context.Context, repoPath string, patterns []string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-files", "-z", "--cached", "--others", "--exclude-standard")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(strings.TrimRight(string(output), "\x00"), "\x00") {
+		if name == "" || seen[name] || !util.MatchAnyGlob(patterns, name) {
+			continue
+		}
+		seen[name] = true
+		info, err := os.Lstat(filepath.Join(repoPath, name))
+		if err != nil || !info.Mode().IsRegular() {
+			continue // deleted, or a symlink
+		}
+		files = append(files, name)
+	}
+	return files, nil
+}
+
+// editFiles rewrites each text file with edit and returns the files it changed.
+func editFiles(repoPath string, files []string, edit func(name string, data []byte) ([]byte, error)) ([]string, error) {
+	var changed []string
+	for _, name := range files {
+		full := filepath.Join(repoPath, name)
+		data, err := os.ReadFile(full)
+		if err != nil {
+			return changed, err
+		}
+		if bytes.IndexByte(data, 0) >= 0 {
+			continue // binary
+		}
+		updated, err := edit(name, data)
+		if err != nil {
+			return changed, fmt.Errorf("%s: %w", name, err)
+		}
+		if bytes.Equal(updated, data) {
+			continue
+		}
+		info, err := os.Stat(full)
+		if err != nil {
+			return changed, err
+		}
+		if err := os.WriteFile(full, updated, info.Mode().Perm()); err != nil {
+			return changed, err
+		}
+		changed = append(changed, name)
+	}
+	return changed, nil
+}
+
+func countFiles(files []string) string {
+	switch len(files) {
+	case 0:
+		return "no files"
+	case 1:
+		return files[0]
+	}
+	return fmt.Sprintf("%d files", len(files))
+}
@@ -0,0 +1,108 @@
+package actions
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestApply(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                "module example.com/payments\n\ngo 1.24\n",
+		"internal/http/http.go": "package http\n\nconst timeout = 10 // seconds\n",
+		"deploy/values.yaml":    "# Helm values\nreplicas: 2 # per region\nimage:\n  tag: v1\n",
+		"package.json":          "{\n    \"name\": \"web\",\n    \"version\": \"1.0.0\",\n    \"scripts\": {\n        \"lint\": \"eslint .\"\n    }\n}\n",
+		".travis.yml":           "language: go\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"add", "-A"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+
+	lines, err := Apply(context.Background(), dir, []config.Action{
+		{Type: config.ActionReplace, Files: []string{"go.mod"}, Search: "go 1.24", Replace: "go 1.25"},
+		{Type: config.ActionRegexReplace, Files: []string{"**/*.go"}, Search: `timeout = (\d+)`, Replace: "timeout = ${1}0"},
+		{Type: config.ActionAddFile, Path: ".github/CODEOWNERS", Content: "* @acme/payments\n"},
+		{Type: config.ActionRemoveFile, Files: []string{".travis.yml"}},
+		{Type: config.ActionSetKey, Files: []string{"deploy/*.yaml"}, Key: "image.tag", Value: "v2"},
+		{Type: config.ActionSetKey, Files: []string{"package.json"}, Key: "engines.node", Value: ">=20"},
+		{Type: config.ActionDeleteKey, Files: []string{"package.json"}, Key: "scripts.lint"},
+		{Type: config.ActionDeleteKey, Files: []string{"deploy/*.yaml"}, Key: "missing.key"},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	want := []string{
+		`Replaced "go 1.24" with "go 1.25" in go.mod`,
+		"Replaced matches of `timeout = (\\d+)` with \"timeout = ${1}0\" in internal/http/http.go",
+		"Wrote .github/CODEOWNERS",
+		"Removed .travis.yml",
+		"Set `image.tag` in deploy/values.yaml",
+		"Set `engines.node` in package.json",
+		"Deleted `scripts.lint` from package.json",
+		"Deleted `missing.key` from no files",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected summary:\n%s", strings.Join(lines, "\n"))
+	}
+
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		return string(data)
+	}
+	if got := read("internal/http/http.go"); !strings.Contains(got, "timeout = 100 // seconds") {
+		t.Errorf("regex replace: got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".travis.yml")); !os.IsNotExist(err) {
+		t.Error("expected .travis.yml to be removed")
+	}
+	if got, want := read("deploy/values.yaml"), "# Helm values\nreplicas: 2 # per region\nimage:\n  tag: v2\n"; got != want {
+		t.Errorf("values.yaml: got %q, want %q", got, want)
+	}
+	wantJSON := "{\n    \"name\": \"web\",\n    \"version\": \"1.0.0\",\n    \"scripts\": {},\n    \"engines\": {\n        \"node\": \">=20\"\n    }\n}\n"
+	if got := read("package.json"); got != wantJSON {
+		t.Errorf("package.json: got %q, want %q", got, wantJSON)
+	}
+}
+
+func TestApplyFailsOnNonMapping(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "list.yaml"), []byte("- a\n- b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v (%s)", err, out)
+	}
+
+	_, err := Apply(context.Background(), dir, []config.Action{{Type: config.ActionSetKey, Files: []string{"*.yaml"}, Key: "a", Value: 1}})
+	if err == nil || !strings.Contains(err.Error(), "step 1 (set_key): list.yaml") {
+		t.Errorf("expected the step and file in the error, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// patchFile sets key to value, or deletes it, in a YAML or JSON file. Keys are
+// dotted paths through nested mappings (e.g. "spec.replicas"); set creates
+// the mappings on the way. The file keeps its key order, indentation and,
+// for YAML, comments.
+func patchFile(name string, data []byte, key string, value any, del bool) ([]byte, error) {
+	isJSON := strings.EqualFold(path.Ext(name), ".json")
+
+	// JSON is YAML, so both are edited as YAML nodes
+	var doc yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse: %w", err)
+	}
+	var extra yaml.Node
+	if decoder.Decode(&extra) == nil {
+		return nil, errors.New("files with several YAML documents are not supported")
+	}
+	if len(doc.Content) == 0 {
+		if del {
+			return data, nil
+		}
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	changed, err := patchNode(doc.Content[0], strings.Split(key, "."), value, del)
+	if err != nil || !changed {
+		return data, err
+	}
+
+	indent := detectIndent(data)
+	var b bytes.Buffer
+	if isJSON {
+		if err := writeJSON(&b, doc.Content[0], indent, 0); err != nil {
+			return nil, err
+		}
+		if len(data) == 0 || bytes.HasSuffix(data, []byte("\n")) {
+			b.WriteString("\n")
+		}
+		return b.Bytes(), nil
+	}
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(max(len(indent), 2))
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte("---")) {
+		return append([]byte("---\n"), b.Bytes()...), nil
+	}
+	return b.Bytes(), nil
+}
+
+// patchNode walks the mapping node along keys and sets or deletes the last
+// one. It reports whether the node changed.
+func patchNode(node *yaml.Node, keys []string, value any, del bool) (bool, error) {
+	for i, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			if i == 0 {
+				return false, errors.New("the document is not a mapping")
+			}
+			return false, fmt.Errorf("%s is not a mapping", strings.Join(keys[:i], "."))
+		}
+		index := -1
+		for j := 0; j < len(node.Content); j += 2 {
+			if node.Content[j].Value == key {
+				index = j
+				break
+			}
+		}
+		last := i == len(keys)-1
+
+		if del {
+			if index < 0 {
+				return false, nil
+			}
+			if last {
+				node.Content = append(node.Content[:index], node.Content[index+2:]...)
+				return true, nil
+			}
+			node = node.Content[index+1]
+			continue
+		}
+
+		if !last {
+			if index < 0 {
+				child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+				node = child
+			} else {
+				node = node.Content[index+1]
+			}
+			continue
+		}
+
+		var replacement yaml.Node
+		if err := replacement.Encode(value); err != nil {
+			return false, err
+		}
+		if index < 0 {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &replacement)
+			return true, nil
+		}
+		old := node.Content[index+1]
+		var oldValue, newValue any
+		if old.Decode(&oldValue) == nil && replacement.Decode(&newValue) == nil && reflect.DeepEqual(oldValue, newValue) {
+			return false, nil
+		}
+		replacement.LineComment, replacement.FootComment = old.LineComment, old.FootComment
+		node.Content[index+1] = &replacement
+		return true, nil
+	}
+	return false, nil
+}
+
+// detectIndent returns the file's indentation unit: the shortest leading
+// whitespace of an indented line, or two spaces.
+func detectIndent(data []byte) string {
+	indent := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || trimmed == line || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if lead := line[:len(line)-len(trimmed)]; indent == "" || len(lead) < len(indent) {
+			indent = lead
+		}
+	}
+	if indent == "" {
+		return "  "
+	}
+	return indent
+}
+
+// writeJSON writes node as indented JSON. Scalars keep their original text,
+// so numbers are not reformatted.
+func writeJSON(b *bytes.Buffer, node *yaml.Node, indent string, depth int) error {
+	pad := strings.Repeat(indent, depth+1)
+	switch node.Kind {
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			b.WriteString("{}")
+			return nil
+		}
+		b.WriteString("{\n")
+		for i := 0; i < len(node.Content); i += 2 {
+			if i > 0 {
+				b.WriteString(",\n")
+			}
+			b.WriteString(pad + quoteJSON(node.Content[i].Value) + ": ")
+			if err := writeJSON(b, node.Content[i+1], indent, depth+1); err != nil {
+				return err
+			}
+		}
+		b.WriteString("\n" + strings.Repeat(indent, depth) + "}")
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			b.WriteString("[]")
+			return nil
+		}
+		b.WriteString("[\n")
+		for i, item := range node.Content {
+			if i > 0 {
+				b.WriteString(",\n")
+			}
+			b.WriteString(pad)
+			if err := writeJSON(b, item, indent, depth+1); err != nil {
+				return err
+			}
+		}
+		b.WriteString("\n" + strings.Repeat(indent, depth) + "]")
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			b.WriteString("null")
+		case "!!bool", "!!int", "!!float":
+			b.WriteString(node.Value)
+		default:
+			b.WriteString(quoteJSON(node.Value))
+		}
+	default:
+		return fmt.Errorf("cannot write a YAML %v as JSON", node.Kind)
+	}
+	return nil
+}
+
+// quoteJSON quotes s as a JSON string without escaping HTML characters.
+func quoteJSON(s string) string {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
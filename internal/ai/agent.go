@@ -0,0 +1,48 @@
+package ai
+
+import (
+	"context"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// Agent is everything a run asks of AI tools. CLI is the real agent, running
+// the configured tools; aitest.Fake stands in for it in tests.
+type Agent interface {
+	VibeCode(ctx context.Context, aiTool *config.AITool, prompt, targetPath, mcpConfigPath, sessionID, repoName string, timeout time.Duration, onLine func(line string)) (string, error)
+	Assess(ctx context.Context, aiTool *config.AITool, prompt, targetPath, sessionID, repoName string, timeout time.Duration, onLine func(line string)) (string, error)
+	RewritePromptForProject(ctx context.Context, aiTool *config.AITool, userPrompt string) (string, error)
+	SummarizeFindings(ctx context.Context, aiTool *config.AITool, findings map[string]string) (string, error)
+	GeneratePRDescription(ctx context.Context, aiTool *config.AITool, project config.Project, aiOutput, targetPath string) (string, error)
+	ReviewChanges(ctx context.Context, reviewer *config.AITool, prompt, diff, targetPath string) (ReviewVerdict, error)
+}
+
+// CLI runs the configured AI tools' commands.
+type CLI struct{}
+
+var _ Agent = CLI{}
+
+func (CLI) VibeCode(ctx context.Context, aiTool *config.AITool, prompt, targetPath, mcpConfigPath, sessionID, repoName string, timeout time.Duration, onLine func(line string)) (string, error) {
+	return VibeCode(ctx, aiTool, prompt, targetPath, mcpConfigPath, sessionID, repoName, timeout, onLine)
+}
+
+func (CLI) Assess(ctx context.Context, aiTool *config.AITool, prompt, targetPath, sessionID, repoName string, timeout time.Duration, onLine func(line string)) (string, error) {
+	return Assess(ctx, aiTool, prompt, targetPath, sessionID, repoName, timeout, onLine)
+}
+
+func (CLI) RewritePromptForProject(ctx context.Context, aiTool *config.AITool, userPrompt string) (string, error) {
+	return RewritePromptForProject(ctx, aiTool, userPrompt)
+}
+
+func (CLI) SummarizeFindings(ctx context.Context, aiTool *config.AITool, findings map[string]string) (string, error) {
+	return SummarizeFindings(ctx, aiTool, findings)
+}
+
+func (CLI) GeneratePRDescription(ctx context.Context, aiTool *config.AITool, project config.Project, aiOutput, targetPath string) (string, error) {
+	return GeneratePRDescription(ctx, aiTool, project, aiOutput, targetPath)
+}
+
+func (CLI) ReviewChanges(ctx context.Context, reviewer *config.AITool, prompt, diff, targetPath string) (ReviewVerdict, error) {
+	return ReviewChanges(ctx, reviewer, prompt, diff, targetPath)
+}
@@ -4,26 +4,50 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/exec"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/saltpay/copycat/v2/internal/config"
 )
 
-func VibeCode(ctx context.Context, aiTool *config.AITool, prompt string, targetPath string, mcpConfigPath string, repoName string) (string, error) {
-	var opts []config.CommandOptions
-	if mcpConfigPath != "" {
-		opts = append(opts, config.CommandOptions{MCPConfigPath: mcpConfigPath})
+// VibeCode runs the AI tool on the repo, giving up with ErrTimeout after
+// timeout (zero means no limit). A non-empty sessionID continues that earlier
+// session of the tool. onLine, if set, receives each line of output while the
+// tool runs.
+func VibeCode(ctx context.Context, aiTool *config.AITool, prompt string, targetPath string, mcpConfigPath string, sessionID string, repoName string, timeout time.Duration, onLine func(line string)) (string, error) {
+	if aiTool.IsAPI() {
+		return "", fmt.Errorf("%s %w", aiTool.Name, ErrAPIToolCannotEdit)
 	}
 
-	cmd := aiTool.BuildCommandContext(ctx, prompt, aiTool.CodeArgs, opts...)
-	cmd.Dir = targetPath
-	if repoName != "" {
-		cmd.Env = append(os.Environ(), "COPYCAT_REPO_NAME="+repoName)
+	var opts []config.CommandOptions
+	if mcpConfigPath != "" || sessionID != "" {
+		opts = append(opts, config.CommandOptions{MCPConfigPath: mcpConfigPath, SessionID: sessionID})
 	}
 
-	output, err := cmd.CombinedOutput()
+	return runWithTimeout(ctx, timeout, func(ctx context.Context) *exec.Cmd {
+		cmd := aiTool.BuildCommandContext(ctx, prompt, aiTool.CodeArgs, opts...)
+		cmd.Dir = targetPath
+		permissionEnv := aiTool.PermissionEnv(mcpConfigPath)
+		cmd.Env = append(toolEnv(repoName), permissionEnv...)
+		if aiTool.Sandbox != nil {
+			return sandboxed(ctx, aiTool.Sandbox, cmd, repoName, mcpConfigPath, permissionEnv...)
+		}
+		return cmd
+	}, onLine)
+}
 
-	return string(output), err
+// toolEnv is the environment the AI tool runs with: copycat's own, without
+// the Slack token so it can't end up in the tool's output, plus the repo name.
+func toolEnv(repoName string) []string {
+	env := slices.DeleteFunc(os.Environ(), func(kv string) bool {
+		return strings.HasPrefix(kv, "SLACK_BOT_TOKEN=")
+	})
+	if repoName != "" {
+		env = append(env, "COPYCAT_REPO_NAME="+repoName)
+	}
+	return env
 }
 
 func pickArgs(aiTool *config.AITool) []string {
@@ -36,8 +60,7 @@ func pickArgs(aiTool *config.AITool) []string {
 func RewritePromptForProject(ctx context.Context, aiTool *config.AITool, userPrompt string) (string, error) {
 	rewritePrompt := fmt.Sprintf("Rewrite this question so it applies to a single repository. Output ONLY the rewritten question.\n\nOriginal: %s", userPrompt)
 
-	cmd := aiTool.BuildCommandContext(ctx, rewritePrompt, pickArgs(aiTool))
-	output, err := cmd.Output()
+	output, err := runPrompt(ctx, aiTool, rewritePrompt, pickArgs(aiTool), "")
 	if err != nil {
 		return "", fmt.Errorf("failed to rewrite prompt: %v\nOutput: %s", err, string(output))
 	}
@@ -45,15 +68,24 @@ func RewritePromptForProject(ctx context.Context, aiTool *config.AITool, userPro
 	return strings.TrimSpace(string(output)), nil
 }
 
-func Assess(ctx context.Context, aiTool *config.AITool, prompt string, targetPath string, repoName string) (string, error) {
-	cmd := aiTool.BuildCommandContext(ctx, prompt, aiTool.CodeArgs)
-	cmd.Dir = targetPath
-	if repoName != "" {
-		cmd.Env = append(os.Environ(), "COPYCAT_REPO_NAME="+repoName)
+// Assess asks the AI tool a question about the repo, giving up with
+// ErrTimeout after timeout (zero means no limit). A non-empty sessionID
+// continues that earlier session of the tool. onLine, if set, receives each
+// line of output while the tool runs. API tools answer from a snapshot of
+// the repo's files.
+func Assess(ctx context.Context, aiTool *config.AITool, prompt string, targetPath string, sessionID string, repoName string, timeout time.Duration, onLine func(line string)) (string, error) {
+	if aiTool.IsAPI() {
+		return assessWithAPI(ctx, aiTool, prompt, targetPath, timeout, onLine)
 	}
-
-	output, err := cmd.CombinedOutput()
-	return string(output), err
+	return runWithTimeout(ctx, timeout, func(ctx context.Context) *exec.Cmd {
+		cmd := aiTool.BuildCommandContext(ctx, prompt, aiTool.CodeArgs, config.CommandOptions{SessionID: sessionID})
+		cmd.Dir = targetPath
+		cmd.Env = toolEnv(repoName)
+		if aiTool.Sandbox != nil {
+			return sandboxed(ctx, aiTool.Sandbox, cmd, repoName, "")
+		}
+		return cmd
+	}, onLine)
 }
 
 func SummarizeFindings(ctx context.Context, aiTool *config.AITool, findings map[string]string) (string, error) {
@@ -68,8 +100,7 @@ func SummarizeFindings(ctx context.Context, aiTool *config.AITool, findings map[
 
 	summaryPrompt := fmt.Sprintf("You are summarizing the results of an assessment across multiple repositories. Provide an executive summary of the findings, highlighting common patterns, outliers, and actionable insights. Output ONLY the summary.\n\n%s", input)
 
-	cmd := aiTool.BuildCommandContext(ctx, summaryPrompt, pickArgs(aiTool))
-	output, err := cmd.Output()
+	output, err := runPrompt(ctx, aiTool, summaryPrompt, pickArgs(aiTool), "")
 	if err != nil {
 		return "", fmt.Errorf("failed to summarize findings: %v\nOutput: %s", err, string(output))
 	}
@@ -83,12 +114,13 @@ func SummarizeFindings(ctx context.Context, aiTool *config.AITool, findings map[
 }
 
 func GeneratePRDescription(ctx context.Context, aiTool *config.AITool, project config.Project, aiOutput string, targetPath string) (string, error) {
-	summaryPrompt := fmt.Sprintf("Given the changes below, produce a 2-3 sentence PR description. Do not include any introductory text, headers, or commentary - respond with the description only.\n\nChanges:\n%s", aiOutput)
-
-	cmd := aiTool.BuildCommandContext(ctx, summaryPrompt, aiTool.SummaryArgs)
-	cmd.Dir = targetPath
+	scope := ""
+	if dir := strings.Trim(project.Path, "/"); dir != "" {
+		scope = fmt.Sprintf(" The changes are limited to %s/ of a monorepo; only describe those.", dir)
+	}
+	summaryPrompt := fmt.Sprintf("Given the changes below, produce a 2-3 sentence PR description.%s Do not include any introductory text, headers, or commentary - respond with the description only.\n\nChanges:\n%s", scope, aiOutput)
 
-	summaryOutput, err := cmd.Output()
+	summaryOutput, err := runPrompt(ctx, aiTool, summaryPrompt, aiTool.SummaryArgs, targetPath)
 	if err != nil {
 		return "", fmt.Errorf("Failed to generate PR description for %s: %v\nOutput: %s", project.Repo, err, string(summaryOutput))
 	}
@@ -0,0 +1,118 @@
+// Package aitest provides a scripted AI tool for tests.
+package aitest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/ai"
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// Call is a request made to the fake.
+type Call struct {
+	Method string
+	Repo   string
+	Prompt string
+}
+
+// Fake is an ai.Agent that answers instantly without running any tool. Code
+// changes write Files into the repo; repos listed in Errors fail instead. It is
+// safe for concurrent use.
+type Fake struct {
+	Files    map[string]string // written into the repo by each code change, by path
+	Output   string            // the tool's answer to code changes and assessments
+	Errors   map[string]error  // makes code changes and assessments of a repo fail
+	Rejected map[string]string // review rejection reasons, by repo
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ ai.Agent = (*Fake)(nil)
+
+func (f *Fake) VibeCode(ctx context.Context, aiTool *config.AITool, prompt, targetPath, mcpConfigPath, sessionID, repoName string, timeout time.Duration, onLine func(line string)) (string, error) {
+	f.record("VibeCode", repoName, prompt)
+	if err := f.Errors[repoName]; err != nil {
+		return "", err
+	}
+	for name, content := range f.Files {
+		file := filepath.Join(targetPath, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			return "", err
+		}
+		if onLine != nil {
+			onLine("Wrote " + name)
+		}
+	}
+	return f.output(), nil
+}
+
+func (f *Fake) Assess(ctx context.Context, aiTool *config.AITool, prompt, targetPath, sessionID, repoName string, timeout time.Duration, onLine func(line string)) (string, error) {
+	f.record("Assess", repoName, prompt)
+	if err := f.Errors[repoName]; err != nil {
+		return "", err
+	}
+	return f.output(), nil
+}
+
+// RewritePromptForProject returns the prompt unchanged.
+func (f *Fake) RewritePromptForProject(ctx context.Context, aiTool *config.AITool, userPrompt string) (string, error) {
+	f.record("RewritePromptForProject", "", userPrompt)
+	return userPrompt, nil
+}
+
+// SummarizeFindings lists the repos that answered.
+func (f *Fake) SummarizeFindings(ctx context.Context, aiTool *config.AITool, findings map[string]string) (string, error) {
+	f.record("SummarizeFindings", "", "")
+	repos := make([]string, 0, len(findings))
+	for repo := range findings {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return fmt.Sprintf("Findings from %s.", strings.Join(repos, ", ")), nil
+}
+
+func (f *Fake) GeneratePRDescription(ctx context.Context, aiTool *config.AITool, project config.Project, aiOutput, targetPath string) (string, error) {
+	f.record("GeneratePRDescription", project.Repo, "")
+	return "Changes made by Copycat.\n\n" + aiOutput, nil
+}
+
+// ReviewChanges approves every diff, except for the repos in Rejected.
+func (f *Fake) ReviewChanges(ctx context.Context, reviewer *config.AITool, prompt, diff, targetPath string) (ai.ReviewVerdict, error) {
+	repo := filepath.Base(targetPath)
+	f.record("ReviewChanges", repo, prompt)
+	if reasons, ok := f.Rejected[repo]; ok {
+		return ai.ReviewVerdict{Approved: false, Reasons: reasons}, nil
+	}
+	return ai.ReviewVerdict{Approved: true}, nil
+}
+
+// Calls returns the requests made so far, in order.
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+func (f *Fake) record(method, repo, prompt string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: method, Repo: repo, Prompt: prompt})
+}
+
+func (f *Fake) output() string {
+	if f.Output == "" {
+		return "Done."
+	}
+	return f.Output
+}
@@ -0,0 +1,323 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// ErrAPIToolCannotEdit is returned when an API tool is asked to change code.
+var ErrAPIToolCannotEdit = errors.New("calls an API and cannot change code; choose a command-line tool")
+
+const (
+	anthropicBaseURL = "https://api.anthropic.com"
+	openAIBaseURL    = "https://api.openai.com/v1"
+	ollamaBaseURL    = "http://localhost:11434"
+
+	// apiMaxTokens caps the length of an API answer.
+	apiMaxTokens = 8192
+
+	// repoSnapshotLimit is how much of a repo's content is sent with an
+	// assessment question, in bytes.
+	repoSnapshotLimit = 200_000
+
+	// maxSnapshotListing is how many paths the snapshot lists.
+	maxSnapshotListing = 2000
+
+	// Ollama truncates prompts to a small context window unless asked for a
+	// larger one, so local models get a larger window and a smaller snapshot
+	// that fits in it.
+	ollamaContextTokens = 32768
+	ollamaSnapshotLimit = 100_000
+)
+
+// apiClient is shared by every API call; requests carry their own deadlines.
+var apiClient = &http.Client{Timeout: 10 * time.Minute}
+
+// callAPI sends prompt to the tool's provider and returns the answer and what
+// it used.
+func callAPI(ctx context.Context, tool *config.AITool, prompt string) (string, Usage, error) {
+	var key string
+	if keyVar := tool.APIKeyVar(); keyVar != "" {
+		key = os.Getenv(keyVar)
+		if key == "" {
+			return "", Usage{}, fmt.Errorf("%s is not set; %s needs it to call the %s API", keyVar, tool.Name, tool.APIProvider)
+		}
+	}
+	switch tool.APIProvider {
+	case config.APIProviderOpenAI:
+		return callOpenAI(ctx, tool, key, prompt)
+	case config.APIProviderOllama:
+		return callOllama(ctx, tool, key, prompt)
+	}
+	return callAnthropic(ctx, tool, key, prompt)
+}
+
+func callAnthropic(ctx context.Context, tool *config.AITool, key, prompt string) (string, Usage, error) {
+	body := map[string]any{
+		"model":      tool.Model,
+		"max_tokens": apiMaxTokens,
+		"messages":   []map[string]string{{"role": "user", "content": prompt}},
+	}
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens              int `json:"input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	headers := map[string]string{"x-api-key": key, "anthropic-version": "2023-06-01"}
+	if err := postJSON(ctx, apiURL(tool, anthropicBaseURL, "/v1/messages"), headers, body, &resp); err != nil {
+		return "", Usage{}, err
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	usage := Usage{
+		InputTokens:  resp.Usage.InputTokens + resp.Usage.CacheCreationInputTokens + resp.Usage.CacheReadInputTokens,
+		OutputTokens: resp.Usage.OutputTokens,
+	}
+	return text.String(), usage, nil
+}
+
+func callOpenAI(ctx context.Context, tool *config.AITool, key, prompt string) (string, Usage, error) {
+	body := map[string]any{
+		"model":    tool.Model,
+		"messages": []map[string]string{{"role": "user", "content": prompt}},
+	}
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	headers := map[string]string{"Authorization": "Bearer " + key}
+	if err := postJSON(ctx, apiURL(tool, openAIBaseURL, "/chat/completions"), headers, body, &resp); err != nil {
+		return "", Usage{}, err
+	}
+	if len(resp.Choices) == 0 {
+		return "", Usage{}, errors.New("the API returned no answer")
+	}
+	usage := Usage{InputTokens: resp.Usage.PromptTokens, OutputTokens: resp.Usage.CompletionTokens}
+	return resp.Choices[0].Message.Content, usage, nil
+}
+
+func callOllama(ctx context.Context, tool *config.AITool, key, prompt string) (string, Usage, error) {
+	body := map[string]any{
+		"model":    tool.Model,
+		"messages": []map[string]string{{"role": "user", "content": prompt}},
+		"stream":   false,
+		"options":  map[string]any{"num_ctx": ollamaContextTokens},
+	}
+	var resp struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		PromptEvalCount int `json:"prompt_eval_count"`
+		EvalCount       int `json:"eval_count"`
+	}
+	// Only a server behind an authenticating proxy needs a key
+	headers := map[string]string{}
+	if key != "" {
+		headers["Authorization"] = "Bearer " + key
+	}
+	if err := postJSON(ctx, apiURL(tool, ollamaBaseURL, "/api/chat"), headers, body, &resp); err != nil {
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return "", Usage{}, fmt.Errorf("no Ollama server at %s; start it with `ollama serve`", apiURL(tool, ollamaBaseURL, ""))
+		}
+		return "", Usage{}, err
+	}
+	usage := Usage{InputTokens: resp.PromptEvalCount, OutputTokens: resp.EvalCount}
+	return stripThinking(resp.Message.Content), usage, nil
+}
+
+// stripThinking drops the <think> block reasoning models such as DeepSeek-R1
+// and Qwen3 put before their answer.
+func stripThinking(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "<think>") {
+		return text
+	}
+	_, answer, found := strings.Cut(trimmed, "</think>")
+	if !found {
+		return text
+	}
+	return strings.TrimSpace(answer)
+}
+
+// apiURL joins the tool's base URL, or the provider's, with path.
+func apiURL(tool *config.AITool, defaultBase, path string) string {
+	base := defaultBase
+	if tool.APIBaseURL != "" {
+		base = tool.APIBaseURL
+	}
+	return strings.TrimSuffix(base, "/") + path
+}
+
+// postJSON posts body to url and decodes the response into out. Error
+// responses are reported with the provider's message.
+func postJSON(ctx context.Context, url string, headers map[string]string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// Both providers put the reason in error.message
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("API request failed (%s): %s", resp.Status, apiErr.Error.Message)
+		}
+		return fmt.Errorf("API request failed (%s): %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse the API response: %w", err)
+	}
+	return nil
+}
+
+// assessWithAPI answers an assessment question from a snapshot of the repo,
+// since an API tool cannot look around the repo itself. The answer comes
+// wrapped like Claude's JSON output, so ParseUsage reads it the same way.
+func assessWithAPI(ctx context.Context, tool *config.AITool, prompt, targetPath string, timeout time.Duration, onLine func(line string)) (string, error) {
+	limit := repoSnapshotLimit
+	if tool.APIProvider == config.APIProviderOllama {
+		limit = ollamaSnapshotLimit
+	}
+	snapshot, err := repoSnapshot(ctx, targetPath, limit)
+	if err != nil {
+		return "", err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	question := fmt.Sprintf("Answer the question below about a repository. You cannot run commands or open files: answer from the snapshot of the repository that follows the question.\n\n%s\n\n%s", prompt, snapshot)
+	text, usage, err := callAPI(ctx, tool, question)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("%w after %s", ErrTimeout, formatTimeout(timeout))
+		}
+		return "", err
+	}
+	if onLine != nil {
+		for _, line := range strings.Split(text, "\n") {
+			onLine(line)
+		}
+	}
+
+	var result claudeResult
+	result.Type = "result"
+	result.Result = text
+	result.Usage.InputTokens = usage.InputTokens
+	result.Usage.OutputTokens = usage.OutputTokens
+	output, err := json.Marshal(result)
+	return string(output), err
+}
+
+// repoSnapshot lists the repo's tracked files and includes the contents of
+// as many text files as fit in limit bytes: top-level files (READMEs, build
+// files) first, then deeper ones.
+func repoSnapshot(ctx context.Context, repoPath string, limit int) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-files")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to list files in %s: %v", repoPath, err)
+	}
+	files := strings.Fields(string(output))
+
+	var b strings.Builder
+	b.WriteString("Files:\n")
+	for i, file := range files {
+		if i == maxSnapshotListing {
+			fmt.Fprintf(&b, "... and %d more\n", len(files)-i)
+			break
+		}
+		b.WriteString(file + "\n")
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return strings.Count(files[i], "/") < strings.Count(files[j], "/")
+	})
+	skipped := 0
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(repoPath, file))
+		if err != nil || bytes.IndexByte(data, 0) >= 0 {
+			continue // unreadable or binary
+		}
+		entry := fmt.Sprintf("\n==> %s <==\n%s\n", file, data)
+		if b.Len()+len(entry) > limit {
+			skipped++
+			continue
+		}
+		b.WriteString(entry)
+	}
+	if skipped > 0 {
+		fmt.Fprintf(&b, "\n(%d more files left out for size)\n", skipped)
+	}
+	return b.String(), nil
+}
+
+// runPrompt runs a one-off prompt through the tool, in dir if set: a command
+// with args, or an API call.
+func runPrompt(ctx context.Context, tool *config.AITool, prompt string, args []string, dir string) ([]byte, error) {
+	if tool.IsAPI() {
+		text, _, err := callAPI(ctx, tool, prompt)
+		return []byte(text), err
+	}
+	cmd := tool.BuildCommandContext(ctx, prompt, args)
+	cmd.Dir = dir
+	return cmd.Output()
+}
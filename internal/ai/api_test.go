@@ -0,0 +1,140 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestAssessWithAnthropicAPI(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "go.mod"), []byte("module example.com/payments\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"add", "-A"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v (%s)", args, err, output)
+		}
+	}
+
+	var prompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" || r.Header.Get("x-api-key") != "secret" {
+			t.Errorf("unexpected request %s with key %q", r.URL.Path, r.Header.Get("x-api-key"))
+		}
+		var body struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Model != "claude-sonnet-4-5" || len(body.Messages) != 1 {
+			t.Errorf("unexpected body %+v", body)
+		}
+		prompt = body.Messages[0].Content
+		w.Write([]byte(`{"content":[{"type":"text","text":"Yes, Go 1.25."}],"usage":{"input_tokens":1000,"output_tokens":20}}`))
+	}))
+	defer server.Close()
+	t.Setenv("ANTHROPIC_API_KEY", "secret")
+	tool := &config.AITool{Name: "claude-api", APIProvider: "anthropic", Model: "claude-sonnet-4-5", APIBaseURL: server.URL}
+
+	var lines []string
+	output, err := Assess(context.Background(), tool, "Which Go version?", repo, "", "payments", 0, func(line string) { lines = append(lines, line) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(prompt, "Which Go version?") || !strings.Contains(prompt, "==> go.mod <==\nmodule example.com/payments") {
+		t.Errorf("expected the question and the repo's files in the prompt, got %q", prompt)
+	}
+	usage, text := ParseUsage(output, &config.Pricing{InputPerMillion: 3, OutputPerMillion: 15})
+	if text != "Yes, Go 1.25." || usage.InputTokens != 1000 || usage.OutputTokens != 20 || !usage.Estimated {
+		t.Errorf("unexpected answer %q with usage %+v", text, usage)
+	}
+	if len(lines) != 1 || lines[0] != "Yes, Go 1.25." {
+		t.Errorf("expected the answer in the live log, got %q", lines)
+	}
+}
+
+func TestOpenAIAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"Most repos use circuit breakers."}}],"usage":{"prompt_tokens":50,"completion_tokens":8}}`))
+	}))
+	defer server.Close()
+	t.Setenv("LLM_KEY", "key")
+	tool := &config.AITool{Name: "gpt", APIProvider: "openai", Model: "gpt-4.1", APIKeyEnv: "LLM_KEY", APIBaseURL: server.URL + "/v1"}
+
+	summary, err := SummarizeFindings(context.Background(), tool, map[string]string{"payments": "Uses a breaker."})
+	if err != nil || summary != "Most repos use circuit breakers." {
+		t.Errorf("unexpected summary %q (%v)", summary, err)
+	}
+}
+
+func TestOllamaAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model   string `json:"model"`
+			Stream  bool   `json:"stream"`
+			Options struct {
+				NumCtx int `json:"num_ctx"`
+			} `json:"options"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if r.URL.Path != "/api/chat" || r.Header.Get("Authorization") != "" || body.Model != "qwen3" || body.Stream || body.Options.NumCtx != ollamaContextTokens {
+			t.Errorf("unexpected request %s with body %+v", r.URL.Path, body)
+		}
+		w.Write([]byte(`{"message":{"role":"assistant","content":"<think>\nThe repo logs card numbers.\n</think>\n\nYes, in the payment logger."},"done":true,"prompt_eval_count":40,"eval_count":12}`))
+	}))
+	defer server.Close()
+	tool := &config.AITool{Name: "ollama", APIProvider: "ollama", Model: "qwen3", APIBaseURL: server.URL}
+
+	answer, usage, err := callAPI(context.Background(), tool, "Do we log PII?")
+	if err != nil || answer != "Yes, in the payment logger." || usage.InputTokens != 40 || usage.OutputTokens != 12 {
+		t.Errorf("unexpected answer %q with usage %+v (%v)", answer, usage, err)
+	}
+
+	server.Close()
+	if _, _, err := callAPI(context.Background(), tool, "Do we log PII?"); err == nil || !strings.Contains(err.Error(), "ollama serve") {
+		t.Errorf("expected a hint to start Ollama, got %v", err)
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`))
+	}))
+	defer server.Close()
+	tool := &config.AITool{Name: "claude-api", APIProvider: "anthropic", Model: "claude-sonnet-4-5", APIBaseURL: server.URL}
+
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	if _, err := RewritePromptForProject(context.Background(), tool, "Do we log PII?"); err == nil || !strings.Contains(err.Error(), "ANTHROPIC_API_KEY is not set") {
+		t.Errorf("expected a missing key error, got %v", err)
+	}
+
+	t.Setenv("ANTHROPIC_API_KEY", "wrong")
+	if _, err := RewritePromptForProject(context.Background(), tool, "Do we log PII?"); err == nil || !strings.Contains(err.Error(), "invalid x-api-key") {
+		t.Errorf("expected the provider's message, got %v", err)
+	}
+
+	if _, err := VibeCode(context.Background(), tool, "Fix it", t.TempDir(), "", "", "payments", 0, nil); !errors.Is(err, ErrAPIToolCannotEdit) {
+		t.Errorf("expected API tools to refuse code changes, got %v", err)
+	}
+}
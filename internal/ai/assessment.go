@@ -0,0 +1,76 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Assessment statuses a structured answer can report.
+const (
+	StatusCompliant    = "compliant"
+	StatusPartial      = "partial"
+	StatusNonCompliant = "non_compliant"
+	StatusUnknown      = "unknown"
+)
+
+// StatusRank orders statuses from worst to best, so sorting by status puts
+// the repos needing attention first.
+var StatusRank = map[string]int{
+	StatusNonCompliant: 0,
+	StatusPartial:      1,
+	StatusUnknown:      2,
+	StatusCompliant:    3,
+}
+
+// Assessment is a repo's structured answer to an assessment question.
+type Assessment struct {
+	Score    int    `json:"score"`  // 0-100, higher is better
+	Status   string `json:"status"` // one of the Status constants
+	Evidence string `json:"evidence"`
+}
+
+// StructuredAssessmentPrompt asks the tool to end its answer with a JSON
+// object that ParseAssessment can read.
+const StructuredAssessmentPrompt = `
+
+End your answer with a single JSON object on its own, in this exact shape:
+{"score": <0-100, higher is better>, "status": "compliant" | "partial" | "non_compliant" | "unknown", "evidence": "<one or two sentences citing files or code>"}`
+
+// ParseAssessment reads the last JSON object with a known status from a tool's
+// output. It reports false when the output has none.
+func ParseAssessment(output string) (Assessment, bool) {
+	for end := len(output); end > 0; {
+		start := strings.LastIndex(output[:end], "{")
+		if start < 0 {
+			break
+		}
+		end = start
+
+		var a Assessment
+		if err := json.NewDecoder(strings.NewReader(output[start:])).Decode(&a); err != nil {
+			continue
+		}
+		a.Status = normalizeStatus(a.Status)
+		if a.Status == "" {
+			continue
+		}
+		a.Score = min(max(a.Score, 0), 100)
+		a.Evidence = strings.TrimSpace(a.Evidence)
+		return a, true
+	}
+	return Assessment{}, false
+}
+
+// normalizeStatus maps spellings like "Non-Compliant" onto the Status
+// constants, returning "" for anything else.
+func normalizeStatus(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	status = strings.NewReplacer("-", "_", " ", "_").Replace(status)
+	switch status {
+	case StatusCompliant, StatusPartial, StatusNonCompliant, StatusUnknown:
+		return status
+	case "noncompliant":
+		return StatusNonCompliant
+	}
+	return ""
+}
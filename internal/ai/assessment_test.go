@@ -0,0 +1,49 @@
+package ai
+
+import "testing"
+
+func TestParseAssessment(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   Assessment
+		ok     bool
+	}{
+		{
+			name:   "trailing object",
+			output: "The service uses gobreaker.\n\n{\"score\": 90, \"status\": \"compliant\", \"evidence\": \"client.go wraps calls\"}",
+			want:   Assessment{Score: 90, Status: StatusCompliant, Evidence: "client.go wraps calls"},
+			ok:     true,
+		},
+		{
+			name:   "fenced with nested braces earlier",
+			output: "Config: {\"a\": 1}\n```json\n{\"score\": 40, \"status\": \"Non-Compliant\", \"evidence\": \" none \"}\n```\n",
+			want:   Assessment{Score: 40, Status: StatusNonCompliant, Evidence: "none"},
+			ok:     true,
+		},
+		{
+			name:   "score clamped",
+			output: `{"score": 150, "status": "partial", "evidence": "x"}`,
+			want:   Assessment{Score: 100, Status: StatusPartial, Evidence: "x"},
+			ok:     true,
+		},
+		{
+			name:   "unknown status ignored",
+			output: `{"score": 50, "status": "maybe"}`,
+			ok:     false,
+		},
+		{
+			name:   "no json",
+			output: "Yes, it does.",
+			ok:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseAssessment(tt.output)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ParseAssessment() = %+v, %v; want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// ReviewVerdict is a reviewer tool's decision on the changes made for a prompt.
+type ReviewVerdict struct {
+	Approved bool
+	Reasons  string
+}
+
+// ReviewChanges asks a second AI tool to check the diff against the original
+// prompt. Output that contains no clear verdict is treated as a rejection so a
+// human makes the final call.
+func ReviewChanges(ctx context.Context, reviewer *config.AITool, prompt, diff, targetPath string) (ReviewVerdict, error) {
+	if len(diff) > 50000 {
+		diff = truncateBytes(diff, 50000) + "\n...(truncated)"
+	}
+
+	reviewPrompt := fmt.Sprintf("You are reviewing changes another AI agent made to this repository. Check whether the diff correctly and safely does what the original request asked, and nothing else. Reply with APPROVE or REJECT on the first line, followed by your reasons.\n\nOriginal request:\n%s\n\nDiff:\n%s", prompt, diff)
+
+	output, err := runPrompt(ctx, reviewer, reviewPrompt, pickArgs(reviewer), targetPath)
+	if err != nil {
+		return ReviewVerdict{}, fmt.Errorf("review by %s failed: %v\nOutput: %s", reviewer.Name, err, string(output))
+	}
+
+	return parseReviewVerdict(string(output)), nil
+}
+
+// parseReviewVerdict reads the verdict from the first non-empty line of the
+// reviewer's output; the remaining lines are kept as reasons.
+func parseReviewVerdict(output string) ReviewVerdict {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i, line := range lines {
+		verdict := strings.ToUpper(strings.Trim(strings.TrimSpace(line), "*#:` "))
+		if verdict == "" {
+			continue
+		}
+
+		reasons := strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+		switch {
+		case strings.HasPrefix(verdict, "APPROVE"):
+			return ReviewVerdict{Approved: true, Reasons: reasons}
+		case strings.HasPrefix(verdict, "REJECT"):
+			if reasons == "" {
+				reasons = "no reasons given"
+			}
+			return ReviewVerdict{Approved: false, Reasons: reasons}
+		}
+		break
+	}
+
+	output = strings.TrimSpace(output)
+	if len(output) > 500 {
+		output = truncateBytes(output, 497) + "..."
+	}
+	return ReviewVerdict{Approved: false, Reasons: "reviewer gave no clear verdict:\n" + output}
+}
+
+// truncateBytes returns at most the first n bytes of s, cut between
+// characters so none is split.
+func truncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
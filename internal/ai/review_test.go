@@ -0,0 +1,56 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParseReviewVerdict(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		approved bool
+		reasons  string
+	}{
+		{"approve", "APPROVE\nLooks good.", true, "Looks good."},
+		{"markdown reject", "\n**REJECT**\nTouches unrelated files.\n", false, "Touches unrelated files."},
+		{"reject without reasons", "Reject", false, "no reasons given"},
+		{"no verdict", "I think this is fine", false, "reviewer gave no clear verdict:\nI think this is fine"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := parseReviewVerdict(tt.output)
+			if verdict.Approved != tt.approved {
+				t.Errorf("expected approved=%v, got %v", tt.approved, verdict.Approved)
+			}
+			if verdict.Reasons != tt.reasons {
+				t.Errorf("expected reasons %q, got %q", tt.reasons, verdict.Reasons)
+			}
+		})
+	}
+}
+
+func TestParseReviewVerdictCutsBetweenCharacters(t *testing.T) {
+	// "é" is two bytes, the second of them at the cut
+	output := strings.Repeat("a", 496) + "é" + strings.Repeat("a", 100)
+	verdict := parseReviewVerdict(output)
+	if !utf8.ValidString(verdict.Reasons) {
+		t.Fatalf("expected the reasons cut between characters, got %q", verdict.Reasons)
+	}
+	if want := "reviewer gave no clear verdict:\n" + strings.Repeat("a", 496) + "..."; verdict.Reasons != want {
+		t.Errorf("expected %q, got %q", want, verdict.Reasons)
+	}
+}
+
+func TestTruncateBytes(t *testing.T) {
+	diff := strings.Repeat("+", 49999) + "日本"
+	got := truncateBytes(diff, 50000)
+	if got != strings.Repeat("+", 49999) {
+		t.Errorf("expected the cut before the multi-byte character, got %d bytes ending %q", len(got), got[len(got)-3:])
+	}
+	if got := truncateBytes("short", 50000); got != "short" {
+		t.Errorf("expected short text unchanged, got %q", got)
+	}
+}
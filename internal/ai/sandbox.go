@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// sandboxCount numbers the containers started by this process, so each run
+// gets a name it can be removed by.
+var sandboxCount atomic.Int64
+
+// sandboxed rewrites cmd, built to run the tool on the host, to run it in a
+// container of the sandbox image instead. Only the repo (cmd.Dir) is mounted,
+// at the same path, and only the listed environment variables are passed in.
+// With a permission prompt (mcpConfigPath set), the copycat binary and the MCP
+// config are mounted read-only too, and the container shares the host network
+// unless the sandbox names one, so the handler can reach the permission server.
+// env is passed in on top of the sandbox's variables.
+func sandboxed(ctx context.Context, sandbox *config.Sandbox, cmd *exec.Cmd, repoName string, mcpConfigPath string, env ...string) *exec.Cmd {
+	runtime := sandbox.Runtime
+	if runtime == "" {
+		runtime = "docker"
+	}
+	dir, err := filepath.Abs(cmd.Dir)
+	if err != nil {
+		dir = cmd.Dir
+	}
+	name := fmt.Sprintf("copycat-%d-%d", os.Getpid(), sandboxCount.Add(1))
+
+	args := []string{"run", "--rm", "-i", "--init", "--name", name,
+		"-v", dir + ":" + dir, "-w", dir, "-e", "HOME=/tmp"}
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 {
+		// Files the tool writes stay owned by the user running copycat
+		args = append(args, "--user", fmt.Sprintf("%d:%d", uid, gid))
+	}
+	network := sandbox.Network
+	if mcpConfigPath != "" {
+		if exe, err := os.Executable(); err == nil {
+			args = append(args, "-v", exe+":"+exe+":ro")
+		}
+		args = append(args, "-v", mcpConfigPath+":"+mcpConfigPath+":ro")
+		if network == "" {
+			network = "host"
+		}
+	}
+	if network != "" {
+		args = append(args, "--network", network)
+	}
+	if repoName != "" {
+		args = append(args, "-e", "COPYCAT_REPO_NAME="+repoName)
+	}
+	for _, env := range append(slices.Clone(sandbox.Env), env...) {
+		args = append(args, "-e", env)
+	}
+	args = append(args, sandbox.Args...)
+	args = append(args, sandbox.Image)
+	args = append(args, cmd.Args...)
+
+	wrapped := exec.CommandContext(ctx, runtime, args...)
+	wrapped.Dir = cmd.Dir
+	// Killing the client leaves the container running, so remove it too
+	wrapped.Cancel = func() error {
+		exec.Command(runtime, "rm", "-f", name).Run()
+		return wrapped.Process.Kill()
+	}
+	return wrapped
+}
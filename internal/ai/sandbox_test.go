@@ -0,0 +1,62 @@
+package ai
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestSandboxed(t *testing.T) {
+	dir := t.TempDir()
+	cmd := exec.Command("claude", "--print", "fix it")
+	cmd.Dir = dir
+	sandbox := &config.Sandbox{Image: "tools:latest", Env: []string{"ANTHROPIC_API_KEY"}, Network: "none", Args: []string{"--memory", "2g"}}
+
+	wrapped := sandboxed(context.Background(), sandbox, cmd, "payments-api", "")
+
+	if wrapped.Args[0] != "docker" {
+		t.Fatalf("expected docker, got %v", wrapped.Args)
+	}
+	args := strings.Join(wrapped.Args, " ")
+	for _, want := range []string{
+		"-v " + dir + ":" + dir,
+		"-w " + dir,
+		"--network none",
+		"-e COPYCAT_REPO_NAME=payments-api",
+		"-e ANTHROPIC_API_KEY",
+		"--memory 2g tools:latest claude --print fix it",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("expected %q in %q", want, args)
+		}
+	}
+	if wrapped.Dir != dir || wrapped.Cancel == nil {
+		t.Errorf("expected the repo dir and a cancel that removes the container")
+	}
+}
+
+func TestSandboxedPermissionPrompt(t *testing.T) {
+	cmd := exec.Command("claude", "--print", "fix it")
+	cmd.Dir = t.TempDir()
+	mcpConfig := filepath.Join(t.TempDir(), "mcp.json")
+
+	wrapped := sandboxed(context.Background(), &config.Sandbox{Image: "tools:latest", Runtime: "podman"}, cmd, "", mcpConfig, "GEMINI_CLI_SYSTEM_SETTINGS_PATH="+mcpConfig)
+
+	if wrapped.Args[0] != "podman" {
+		t.Errorf("expected podman, got %v", wrapped.Args)
+	}
+	if !slices.Contains(wrapped.Args, mcpConfig+":"+mcpConfig+":ro") {
+		t.Errorf("expected the MCP config mounted read-only, got %v", wrapped.Args)
+	}
+	if i := slices.Index(wrapped.Args, "GEMINI_CLI_SYSTEM_SETTINGS_PATH="+mcpConfig); i < 1 || wrapped.Args[i-1] != "-e" {
+		t.Errorf("expected the settings variable passed in, got %v", wrapped.Args)
+	}
+	if i := slices.Index(wrapped.Args, "--network"); i < 0 || wrapped.Args[i+1] != "host" {
+		t.Errorf("expected host networking for the permission server, got %v", wrapped.Args)
+	}
+}
@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// lineWriter collects everything written to it and passes each complete line
+// to onLine as soon as it arrives.
+type lineWriter struct {
+	mu      sync.Mutex
+	output  bytes.Buffer
+	pending []byte
+	onLine  func(line string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.output.Write(p)
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(w.pending[:i]))
+		w.pending = w.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush passes on a final line that had no trailing newline.
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) > 0 {
+		w.emit(string(w.pending))
+		w.pending = nil
+	}
+}
+
+func (w *lineWriter) emit(line string) {
+	if line = strings.TrimRight(line, "\r"); strings.TrimSpace(line) != "" {
+		w.onLine(line)
+	}
+}
+
+// runStreaming runs cmd like CombinedOutput, additionally passing each output
+// line to onLine while the command runs. A nil onLine just collects output.
+func runStreaming(cmd *exec.Cmd, onLine func(line string)) (string, error) {
+	if onLine == nil {
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	}
+
+	w := &lineWriter{onLine: onLine}
+	cmd.Stdout = w
+	cmd.Stderr = w
+	err := cmd.Run()
+	w.flush()
+	return w.output.String(), err
+}
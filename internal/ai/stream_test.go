@@ -0,0 +1,34 @@
+package ai
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestRunStreaming(t *testing.T) {
+	var lines []string
+	cmd := exec.Command("sh", "-c", "echo one; echo; printf 'two\\r\\n'; echo three >&2; printf four")
+	output, err := runStreaming(cmd, func(line string) {
+		lines = append(lines, line)
+	})
+	if err != nil {
+		t.Fatalf("runStreaming failed: %v", err)
+	}
+	if got := strings.Join(lines, ","); got != "one,two,three,four" {
+		t.Errorf("unexpected lines: %q", got)
+	}
+	if output != "one\n\ntwo\r\nthree\nfour" {
+		t.Errorf("unexpected output: %q", output)
+	}
+}
+
+func TestRunStreamingWithoutCallback(t *testing.T) {
+	output, err := runStreaming(exec.Command("sh", "-c", "echo out; echo err >&2; exit 3"), nil)
+	if err == nil {
+		t.Error("expected the exit status to be returned")
+	}
+	if !strings.Contains(output, "out") || !strings.Contains(output, "err") {
+		t.Errorf("expected combined output, got %q", output)
+	}
+}
@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// TempBackup tracks a temporarily changed file and how to restore it.
+type TempBackup struct {
+	RelPath   string // relative path within the repo
+	BackupDir string // temp dir holding the original (empty if git-tracked or new)
+	Tracked   bool   // whether git tracks the original
+	Written   bool   // whether the change wrote Content rather than removing the file
+	Content   []byte
+}
+
+// ApplyTempChanges removes or writes the listed files in targetPath for the
+// AI step (e.g. agent instruction files it should not follow). Originals that
+// git does not track (symlinks, gitignored files, etc.) are backed up to a
+// temp dir so they can be restored later. Returns metadata needed for restore.
+func ApplyTempChanges(ctx context.Context, targetPath string, changes []config.TempChange) ([]TempBackup, error) {
+	paths := make([]string, len(changes))
+	for i, c := range changes {
+		paths[i] = c.Path
+	}
+	tracked := gitTrackedFiles(ctx, targetPath, paths)
+
+	var backups []TempBackup
+	for _, c := range changes {
+		p := filepath.Join(targetPath, c.Path)
+		_, statErr := os.Stat(p)
+		exists := statErr == nil
+		if c.Remove && !exists {
+			continue
+		}
+
+		backup := TempBackup{RelPath: c.Path}
+		if exists {
+			if tracked[c.Path] {
+				if err := os.RemoveAll(p); err != nil {
+					continue
+				}
+				backup.Tracked = true
+			} else {
+				// Back up untracked file to a temp dir before removing
+				tmpDir, err := os.MkdirTemp("", "copycat-backup-*")
+				if err != nil {
+					continue
+				}
+				dst := filepath.Join(tmpDir, filepath.Base(c.Path))
+				if err := os.Rename(p, dst); err != nil {
+					os.RemoveAll(tmpDir)
+					continue
+				}
+				backup.BackupDir = tmpDir
+			}
+		}
+		if !c.Remove {
+			backup.Written, backup.Content = true, []byte(c.Content)
+			if err := writeTempFile(p, backup.Content); err != nil {
+				backups = append(backups, backup)
+				return backups, fmt.Errorf("failed to write %s: %w", c.Path, err)
+			}
+		}
+		backups = append(backups, backup)
+	}
+	return backups, nil
+}
+
+// ReapplyTempChanges makes the changes again after the working tree was
+// reset: git-tracked originals that came back are removed and written files
+// rewritten. Untracked originals stay in their backup.
+func ReapplyTempChanges(targetPath string, backups []TempBackup) {
+	for _, b := range backups {
+		p := filepath.Join(targetPath, b.RelPath)
+		if b.Tracked {
+			os.RemoveAll(p)
+		}
+		if b.Written {
+			writeTempFile(p, b.Content)
+		}
+	}
+}
+
+// RestoreTempChanges undoes temporary changes. Written files are removed,
+// then git-tracked originals are restored via git checkout and untracked
+// ones from their temporary backup.
+func RestoreTempChanges(ctx context.Context, targetPath string, backups []TempBackup) error {
+	var trackedPaths []string
+	var errs []string
+
+	for _, b := range backups {
+		if b.Written {
+			if err := os.RemoveAll(filepath.Join(targetPath, b.RelPath)); err != nil {
+				errs = append(errs, fmt.Sprintf("remove %s: %v", b.RelPath, err))
+				continue
+			}
+		}
+		if b.Tracked {
+			trackedPaths = append(trackedPaths, b.RelPath)
+		} else if b.BackupDir != "" {
+			src := filepath.Join(b.BackupDir, filepath.Base(b.RelPath))
+			dst := filepath.Join(targetPath, b.RelPath)
+			if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+				errs = append(errs, fmt.Sprintf("mkdir for %s: %v", b.RelPath, err))
+				continue
+			}
+			if err := os.Rename(src, dst); err != nil {
+				errs = append(errs, fmt.Sprintf("restore %s: %v", b.RelPath, err))
+			}
+			os.RemoveAll(b.BackupDir)
+		}
+	}
+
+	if len(trackedPaths) > 0 {
+		args := append([]string{"checkout", "--"}, trackedPaths...)
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = targetPath
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("git checkout: %v (%s)", err, string(output)))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("restore failures: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// writeTempFile writes content to p, creating directories.
+func writeTempFile(p string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, content, 0o644)
+}
+
+// gitTrackedFiles checks which of the given files are tracked by git.
+func gitTrackedFiles(ctx context.Context, repoPath string, files []string) map[string]bool {
+	result := make(map[string]bool)
+	if len(files) == 0 {
+		return result
+	}
+	args := append([]string{"ls-files", "--"}, files...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return result
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			result[line] = true
+		}
+	}
+	return result
+}
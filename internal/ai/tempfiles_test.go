@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestTempChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"CLAUDE.md":            "Always answer in French\n",
+		"docs/ARCHITECTURE.md": "Layers\n",
+		".env":                 "SECRET=1\n", // untracked
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "CLAUDE.md", "docs"},
+		{"-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-qm", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+
+	read := func(name string) (string, bool) {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		return string(data), err == nil
+	}
+	ctx := context.Background()
+	backups, err := ApplyTempChanges(ctx, dir, []config.TempChange{
+		{Path: "CLAUDE.md", Remove: true},
+		{Path: "missing.md", Remove: true},
+		{Path: "docs/ARCHITECTURE.md", Content: "Hexagonal\n"},
+		{Path: ".env", Content: "SECRET=test\n"},
+		{Path: "fixtures/data.json", Content: "{}\n"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyTempChanges failed: %v", err)
+	}
+	if len(backups) != 4 {
+		t.Fatalf("expected 4 backups (missing files are skipped), got %d", len(backups))
+	}
+	check := func(stage string) {
+		t.Helper()
+		if _, ok := read("CLAUDE.md"); ok {
+			t.Errorf("%s: expected CLAUDE.md to be removed", stage)
+		}
+		for name, want := range map[string]string{"docs/ARCHITECTURE.md": "Hexagonal\n", ".env": "SECRET=test\n", "fixtures/data.json": "{}\n"} {
+			if got, _ := read(name); got != want {
+				t.Errorf("%s: %s = %q, want %q", stage, name, got, want)
+			}
+		}
+	}
+	check("applied")
+
+	// A fallback tool starts from a reset working tree
+	for _, args := range [][]string{{"reset", "-q", "--hard"}, {"clean", "-fdq"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+	ReapplyTempChanges(dir, backups)
+	check("reapplied")
+
+	if err := RestoreTempChanges(ctx, dir, backups); err != nil {
+		t.Fatalf("RestoreTempChanges failed: %v", err)
+	}
+	for name, want := range files {
+		if got, _ := read(name); got != want {
+			t.Errorf("restored %s = %q, want %q", name, got, want)
+		}
+	}
+	if _, ok := read("fixtures/data.json"); ok {
+		t.Error("expected the added file to be removed")
+	}
+}
@@ -0,0 +1,43 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// ErrTimeout is returned when the AI tool runs longer than its timeout.
+var ErrTimeout = errors.New("timed out")
+
+// killGracePeriod is how long a timed-out tool's output pipes may stay open
+// (e.g. held by its child processes) before they are closed.
+const killGracePeriod = 10 * time.Second
+
+// runWithTimeout runs the command built for ctx, cancelling it once timeout
+// has passed. A zero timeout means no limit.
+func runWithTimeout(ctx context.Context, timeout time.Duration, build func(ctx context.Context) *exec.Cmd, onLine func(line string)) (string, error) {
+	if timeout <= 0 {
+		return runStreaming(build(ctx), onLine)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := build(ctx)
+	cmd.WaitDelay = killGracePeriod
+	output, err := runStreaming(cmd, onLine)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return output, fmt.Errorf("%w after %s", ErrTimeout, formatTimeout(timeout))
+	}
+	return output, err
+}
+
+// formatTimeout renders whole minutes as "30m" rather than "30m0s".
+func formatTimeout(d time.Duration) string {
+	if d >= time.Minute && d%time.Minute == 0 {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return d.String()
+}
@@ -0,0 +1,53 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestVibeCodeTimeout(t *testing.T) {
+	aiTool := &config.AITool{
+		Name:     "stalled-ai",
+		Command:  "sh",
+		CodeArgs: []string{"-c", "echo started; exec sleep 5", "sh"},
+	}
+
+	start := time.Now()
+	output, err := VibeCode(context.Background(), aiTool, "prompt", t.TempDir(), "", "", "", 100*time.Millisecond, nil)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("tool was not stopped at the timeout, ran for %s", elapsed)
+	}
+	if !strings.Contains(output, "started") {
+		t.Errorf("expected output before the timeout to be kept, got %q", output)
+	}
+}
+
+func TestVibeCodeWithinTimeout(t *testing.T) {
+	aiTool := &config.AITool{
+		Name:     "quick-ai",
+		Command:  "sh",
+		CodeArgs: []string{"-c", "exit 2", "sh"},
+	}
+
+	_, err := VibeCode(context.Background(), aiTool, "prompt", t.TempDir(), "", "", "", time.Minute, nil)
+	if err == nil || errors.Is(err, ErrTimeout) {
+		t.Errorf("expected the tool's own failure, got %v", err)
+	}
+}
+
+func TestFormatTimeout(t *testing.T) {
+	if got := formatTimeout(30 * time.Minute); got != "30m" {
+		t.Errorf("formatTimeout(30m) = %q", got)
+	}
+	if got := formatTimeout(1500 * time.Millisecond); got != "1.5s" {
+		t.Errorf("formatTimeout(1.5s) = %q", got)
+	}
+}
@@ -0,0 +1,184 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// Usage is what AI tool runs consumed, as far as the tools report it.
+type Usage struct {
+	InputTokens  int     `json:"input_tokens,omitempty"`
+	OutputTokens int     `json:"output_tokens,omitempty"`
+	Tokens       int     `json:"tokens,omitempty"` // total, for tools that do not split it
+	CostUSD      float64 `json:"cost_usd,omitempty"`
+	Estimated    bool    `json:"estimated,omitempty"` // cost derived from configured prices
+}
+
+// Add returns the combined usage of two runs.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		InputTokens:  u.InputTokens + other.InputTokens,
+		OutputTokens: u.OutputTokens + other.OutputTokens,
+		Tokens:       u.Tokens + other.Tokens,
+		CostUSD:      u.CostUSD + other.CostUSD,
+		Estimated:    u.Estimated || other.Estimated,
+	}
+}
+
+// TotalTokens is every token counted, split or not.
+func (u Usage) TotalTokens() int {
+	return u.InputTokens + u.OutputTokens + u.Tokens
+}
+
+// IsZero reports whether nothing was reported.
+func (u Usage) IsZero() bool {
+	return u.TotalTokens() == 0 && u.CostUSD == 0
+}
+
+// String renders the usage as e.g. "$0.42 · 12.3k tokens", with "~" marking
+// an estimated cost.
+func (u Usage) String() string {
+	var parts []string
+	if u.CostUSD > 0 {
+		cost := fmt.Sprintf("$%.2f", u.CostUSD)
+		if u.Estimated {
+			cost = "~" + cost
+		}
+		parts = append(parts, cost)
+	}
+	if tokens := u.TotalTokens(); tokens > 0 {
+		parts = append(parts, formatTokens(tokens)+" tokens")
+	}
+	return strings.Join(parts, " · ")
+}
+
+func formatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	}
+	return strconv.Itoa(n)
+}
+
+// claudeResult is the final message of `claude --print --output-format json`
+// (or the last line of stream-json).
+type claudeResult struct {
+	Type         string  `json:"type"`
+	Result       string  `json:"result"`
+	SessionID    string  `json:"session_id"`
+	TotalCostUSD float64 `json:"total_cost_usd"`
+	Usage        struct {
+		InputTokens              int `json:"input_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// codexEvent is a line of `codex exec --json` output.
+type codexEvent struct {
+	Type  string `json:"type"`
+	Usage struct {
+		InputTokens       int `json:"input_tokens"`
+		CachedInputTokens int `json:"cached_input_tokens"`
+		OutputTokens      int `json:"output_tokens"`
+	} `json:"usage"`
+	Item struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"item"`
+}
+
+// codexTokensUsed matches the "tokens used: 12,345" line codex prints in text mode.
+var codexTokensUsed = regexp.MustCompile(`(?im)^\s*(?:\[[^\]]*\]\s*)?tokens used:?\s*([\d,]+)\s*$`)
+
+// ParseUsage reads the usage a tool reported in its output and returns it with
+// the tool's answer: for JSON output formats that is the text inside the JSON,
+// otherwise the output itself. Costs the tool does not report are estimated
+// from pricing, when set.
+func ParseUsage(output string, pricing *config.Pricing) (Usage, string) {
+	usage, text, ok := parseClaude(output)
+	if !ok {
+		usage, text, ok = parseCodexJSON(output)
+	}
+	if !ok {
+		text = output
+		for _, m := range codexTokensUsed.FindAllStringSubmatch(output, -1) {
+			n, _ := strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+			usage.Tokens = n // the last count is the total
+		}
+	}
+
+	if usage.CostUSD == 0 && pricing != nil && usage.TotalTokens() > 0 {
+		usage.CostUSD = (float64(usage.InputTokens)*pricing.InputPerMillion +
+			float64(usage.OutputTokens)*pricing.OutputPerMillion +
+			float64(usage.Tokens)*(pricing.InputPerMillion+pricing.OutputPerMillion)/2) / 1_000_000
+		usage.Estimated = true
+	}
+	return usage, text
+}
+
+// SessionID returns the id of the session a tool's output came from, for
+// continuing it later. Only Claude's JSON output formats report one.
+func SessionID(output string) string {
+	r, _ := findClaudeResult(output)
+	return r.SessionID
+}
+
+func parseClaude(output string) (Usage, string, bool) {
+	r, ok := findClaudeResult(output)
+	if !ok {
+		return Usage{}, "", false
+	}
+	return Usage{
+		InputTokens:  r.Usage.InputTokens + r.Usage.CacheCreationInputTokens + r.Usage.CacheReadInputTokens,
+		OutputTokens: r.Usage.OutputTokens,
+		CostUSD:      r.TotalCostUSD,
+	}, r.Result, true
+}
+
+// findClaudeResult reads the result message that is either the whole output
+// (json) or its last line (stream-json).
+func findClaudeResult(output string) (claudeResult, bool) {
+	trimmed := strings.TrimSpace(output)
+	candidates := []string{trimmed}
+	if i := strings.LastIndex(trimmed, "\n"); i >= 0 {
+		candidates = append(candidates, trimmed[i+1:])
+	}
+	for _, candidate := range candidates {
+		var r claudeResult
+		if strings.HasPrefix(candidate, "{") && json.Unmarshal([]byte(candidate), &r) == nil && r.Type == "result" {
+			return r, true
+		}
+	}
+	return claudeResult{}, false
+}
+
+func parseCodexJSON(output string) (Usage, string, bool) {
+	var usage Usage
+	var messages []string
+	found := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		var e codexEvent
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &e) != nil {
+			continue
+		}
+		switch {
+		case e.Type == "turn.completed":
+			found = true
+			usage.InputTokens += e.Usage.InputTokens + e.Usage.CachedInputTokens
+			usage.OutputTokens += e.Usage.OutputTokens
+		case e.Type == "item.completed" && e.Item.Type == "agent_message":
+			messages = append(messages, e.Item.Text)
+		}
+	}
+	return usage, strings.Join(messages, "\n\n"), found
+}
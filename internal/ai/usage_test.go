@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestParseUsageClaude(t *testing.T) {
+	output := `{"type":"result","subtype":"success","is_error":false,"result":"Updated go.mod.\nCOPYCAT_OUTPUT version=1.2.0","total_cost_usd":0.4213,"usage":{"input_tokens":120,"cache_creation_input_tokens":3000,"cache_read_input_tokens":9000,"output_tokens":850}}`
+
+	usage, text := ParseUsage(output, &config.Pricing{InputPerMillion: 3, OutputPerMillion: 15})
+	if text != "Updated go.mod.\nCOPYCAT_OUTPUT version=1.2.0" {
+		t.Errorf("unexpected text %q", text)
+	}
+	want := Usage{InputTokens: 12120, OutputTokens: 850, CostUSD: 0.4213}
+	if usage != want {
+		t.Errorf("usage = %+v, want %+v", usage, want)
+	}
+	if got := usage.String(); got != "$0.42 · 13.0k tokens" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestParseUsageClaudeStream(t *testing.T) {
+	output := `{"type":"system","subtype":"init"}
+{"type":"assistant","message":{"content":[{"type":"text","text":"Working"}]}}
+{"type":"result","result":"Done","session_id":"9f1c2a","total_cost_usd":0.1,"usage":{"input_tokens":10,"output_tokens":5}}`
+
+	usage, text := ParseUsage(output, nil)
+	if text != "Done" || usage.CostUSD != 0.1 || usage.TotalTokens() != 15 {
+		t.Errorf("unexpected usage %+v and text %q", usage, text)
+	}
+	if id := SessionID(output); id != "9f1c2a" {
+		t.Errorf("SessionID() = %q, want 9f1c2a", id)
+	}
+	if id := SessionID("plain text output"); id != "" {
+		t.Errorf("expected no session in text output, got %q", id)
+	}
+}
+
+func TestParseUsageCodexJSON(t *testing.T) {
+	output := `{"type":"thread.started","thread_id":"abc"}
+{"type":"item.completed","item":{"id":"item_1","type":"agent_message","text":"Bumped the dependency."}}
+{"type":"turn.completed","usage":{"input_tokens":2000,"cached_input_tokens":1000,"output_tokens":500}}`
+
+	usage, text := ParseUsage(output, &config.Pricing{InputPerMillion: 1, OutputPerMillion: 10})
+	if text != "Bumped the dependency." {
+		t.Errorf("unexpected text %q", text)
+	}
+	want := Usage{InputTokens: 3000, OutputTokens: 500, CostUSD: 0.008, Estimated: true}
+	if usage != want {
+		t.Errorf("usage = %+v, want %+v", usage, want)
+	}
+	if got := usage.String(); got != "~$0.01 · 3.5k tokens" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestParseUsageCodexText(t *testing.T) {
+	output := "Applied the change.\n[2025-01-01T10:00:00] tokens used: 1,234\n"
+
+	usage, text := ParseUsage(output, nil)
+	if text != output {
+		t.Errorf("expected plain output to be kept, got %q", text)
+	}
+	if usage != (Usage{Tokens: 1234}) {
+		t.Errorf("usage = %+v", usage)
+	}
+
+	if usage, _ := ParseUsage("no usage here", nil); !usage.IsZero() {
+		t.Errorf("expected no usage, got %+v", usage)
+	}
+}
@@ -0,0 +1,167 @@
+// Package alerts turns open code scanning and Dependabot alerts into prompt
+// context and reads back what the AI decided for each one.
+package alerts
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/git"
+)
+
+// marker prefixes the lines in AI output that report an alert's outcome.
+const marker = "COPYCAT_ALERT "
+
+// Actions an alert can end up with.
+const (
+	ActionFixed     = "fixed"
+	ActionDismissed = "dismissed"
+	ActionOpen      = "open" // the AI did not report on it, or dismissing failed
+)
+
+// dismissReasons maps the reasons the AI may give to the values GitHub
+// accepts for each alert kind.
+var dismissReasons = map[string]map[string]string{
+	git.AlertCodeScanning: {
+		"false_positive": "false positive",
+		"wont_fix":       "won't fix",
+		"used_in_tests":  "used in tests",
+	},
+	git.AlertDependabot: {
+		"inaccurate":     "inaccurate",
+		"not_used":       "not_used",
+		"tolerable_risk": "tolerable_risk",
+	},
+}
+
+// Outcome is what happened to one alert.
+type Outcome struct {
+	Alert         git.Alert
+	Action        string
+	Reason        string // GitHub's dismissal reason, for dismissed alerts
+	Justification string
+	Error         string // why the alert is still open, if it went wrong
+}
+
+// Prompt renders the alerts and the reporting instructions appended to the
+// user's prompt.
+func Prompt(alerts []git.Alert) string {
+	var b strings.Builder
+	b.WriteString("\n\nOpen security alerts in this repository:\n")
+	for _, a := range alerts {
+		location := a.File
+		if a.Line > 0 {
+			location = fmt.Sprintf("%s:%d", a.File, a.Line)
+		}
+		fmt.Fprintf(&b, "- %s [%s] %s at %s: %s\n", a.ID(), a.Severity, a.Rule, location, a.Summary)
+	}
+	b.WriteString("\nFix each alert in the code where you can. If an alert should not be fixed, do not change code for it. ")
+	b.WriteString("When done, print exactly one line per alert:\n")
+	b.WriteString("COPYCAT_ALERT <id> fixed\n")
+	b.WriteString("COPYCAT_ALERT <id> dismiss <reason>: <one-sentence justification>\n")
+	b.WriteString("Reasons for code-scanning alerts: false_positive, wont_fix, used_in_tests. ")
+	b.WriteString("Reasons for dependabot alerts: inaccurate, not_used, tolerable_risk.")
+	return b.String()
+}
+
+// ParseOutcomes reads the AI's COPYCAT_ALERT lines. Alerts it did not report
+// on, or dismissed with an unknown reason, stay open.
+func ParseOutcomes(aiOutput string, alerts []git.Alert) []Outcome {
+	byID := make(map[string]int, len(alerts))
+	outcomes := make([]Outcome, len(alerts))
+	for i, a := range alerts {
+		byID[a.ID()] = i
+		outcomes[i] = Outcome{Alert: a, Action: ActionOpen, Error: "no decision reported"}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(aiOutput))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		idx := strings.Index(line, marker)
+		if idx < 0 {
+			continue
+		}
+		fields := strings.Fields(strings.Trim(line[idx+len(marker):], "`"))
+		if len(fields) < 2 {
+			continue
+		}
+		i, ok := byID[fields[0]]
+		if !ok {
+			continue
+		}
+		out := &outcomes[i]
+
+		switch strings.ToLower(fields[1]) {
+		case "fixed":
+			*out = Outcome{Alert: out.Alert, Action: ActionFixed}
+		case "dismiss", "dismissed":
+			rest := strings.Join(fields[2:], " ")
+			reason, justification, _ := strings.Cut(rest, ":")
+			reason = strings.ToLower(strings.TrimSpace(reason))
+			justification = strings.TrimSpace(justification)
+			apiReason, known := dismissReasons[out.Alert.Kind][reason]
+			switch {
+			case !known:
+				*out = Outcome{Alert: out.Alert, Action: ActionOpen, Error: fmt.Sprintf("unknown dismissal reason %q", reason)}
+			case justification == "":
+				*out = Outcome{Alert: out.Alert, Action: ActionOpen, Error: "dismissal without justification"}
+			default:
+				*out = Outcome{Alert: out.Alert, Action: ActionDismissed, Reason: apiReason, Justification: justification}
+			}
+		}
+	}
+	return outcomes
+}
+
+// Summary counts outcomes by action, e.g. "2 fixed, 1 dismissed, 1 open".
+func Summary(outcomes []Outcome) string {
+	counts := make(map[string]int)
+	for _, o := range outcomes {
+		counts[o.Action]++
+	}
+	var parts []string
+	for _, action := range []string{ActionFixed, ActionDismissed, ActionOpen} {
+		if counts[action] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[action], action))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Details lists each alert's outcome on its own line.
+func Details(outcomes []Outcome) string {
+	var lines []string
+	for _, o := range outcomes {
+		line := fmt.Sprintf("%s %s: %s", o.Alert.ID(), o.Alert.Rule, o.Action)
+		switch {
+		case o.Action == ActionDismissed:
+			line += fmt.Sprintf(" (%s) %s", o.Reason, o.Justification)
+		case o.Error != "":
+			line += " — " + o.Error
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// Table renders the outcomes as a markdown table for the PR description.
+func Table(outcomes []Outcome) string {
+	var b strings.Builder
+	b.WriteString("### Security alerts\n\n| Alert | Rule | Severity | Outcome |\n|---|---|---|---|\n")
+	for _, o := range outcomes {
+		outcome := o.Action
+		switch {
+		case o.Action == ActionFixed:
+			outcome = "fixed in this PR"
+		case o.Action == ActionDismissed:
+			outcome = fmt.Sprintf("dismissed (%s): %s", o.Reason, o.Justification)
+		case o.Error != "":
+			outcome = "open: " + o.Error
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", o.Alert.ID(), o.Alert.Rule, o.Alert.Severity, strings.ReplaceAll(outcome, "|", "\\|"))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,64 @@
+package alerts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/git"
+)
+
+var testAlerts = []git.Alert{
+	{Kind: git.AlertCodeScanning, Number: 3, Rule: "go/sql-injection", Severity: "high", File: "db.go", Line: 42},
+	{Kind: git.AlertCodeScanning, Number: 4, Rule: "go/unused", Severity: "note", File: "util.go"},
+	{Kind: git.AlertDependabot, Number: 12, Rule: "GHSA-xxxx", Severity: "critical", File: "package-lock.json"},
+	{Kind: git.AlertDependabot, Number: 13, Rule: "GHSA-yyyy", Severity: "low", File: "package-lock.json"},
+	{Kind: git.AlertDependabot, Number: 14, Rule: "GHSA-zzzz", Severity: "low", File: "package-lock.json"},
+}
+
+func TestPrompt(t *testing.T) {
+	prompt := Prompt(testAlerts[:1])
+	if !strings.Contains(prompt, "- code-scanning#3 [high] go/sql-injection at db.go:42") {
+		t.Errorf("prompt does not list the alert:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "COPYCAT_ALERT <id> fixed") {
+		t.Errorf("prompt does not explain how to report outcomes:\n%s", prompt)
+	}
+}
+
+func TestParseOutcomes(t *testing.T) {
+	aiOutput := strings.Join([]string{
+		"Fixed the injection.",
+		"COPYCAT_ALERT code-scanning#3 fixed",
+		"  COPYCAT_ALERT code-scanning#4 dismiss used_in_tests: only referenced from test helpers",
+		"COPYCAT_ALERT dependabot#12 dismiss not_used: lodash is a dev dependency of the docs site",
+		"COPYCAT_ALERT dependabot#13 dismiss false_positive: wrong reason for this kind",
+		"COPYCAT_ALERT unknown#1 fixed",
+	}, "\n")
+
+	outcomes := ParseOutcomes(aiOutput, testAlerts)
+	want := []struct {
+		action, reason, errText string
+	}{
+		{ActionFixed, "", ""},
+		{ActionDismissed, "used in tests", ""},
+		{ActionDismissed, "not_used", ""},
+		{ActionOpen, "", `unknown dismissal reason "false_positive"`},
+		{ActionOpen, "", "no decision reported"},
+	}
+	for i, w := range want {
+		o := outcomes[i]
+		if o.Action != w.action || o.Reason != w.reason || o.Error != w.errText {
+			t.Errorf("outcome %d = %+v, want %+v", i, o, w)
+		}
+	}
+	if outcomes[1].Justification != "only referenced from test helpers" {
+		t.Errorf("unexpected justification %q", outcomes[1].Justification)
+	}
+
+	if got := Summary(outcomes); got != "1 fixed, 2 dismissed, 2 open" {
+		t.Errorf("Summary() = %q", got)
+	}
+	if table := Table(outcomes); !strings.Contains(table, "| code-scanning#3 | go/sql-injection | high | fixed in this PR |") {
+		t.Errorf("unexpected table:\n%s", table)
+	}
+}
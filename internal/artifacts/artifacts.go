@@ -0,0 +1,119 @@
+package artifacts
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// outputMarker prefixes lines in AI output that declare a value, e.g.
+// "COPYCAT_OUTPUT version=1.4.0".
+const outputMarker = "COPYCAT_OUTPUT "
+
+// placeholderPattern matches {{repo.name}}; the repo part may itself contain dots.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\.([A-Za-z0-9_]+)\s*\}\}`)
+
+// Ref identifies a value produced by a repo.
+type Ref struct {
+	Repo string
+	Name string
+}
+
+func (r Ref) String() string {
+	return r.Repo + "." + r.Name
+}
+
+// References returns the unique {{repo.name}} placeholders in prompt.
+func References(prompt string) []Ref {
+	seen := make(map[Ref]bool)
+	var refs []Ref
+	for _, m := range placeholderPattern.FindAllStringSubmatch(prompt, -1) {
+		ref := Ref{Repo: m[1], Name: m[2]}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// Expand replaces {{repo.name}} placeholders with values from lookup. Placeholders
+// for skipRepo are left untouched (a repo cannot consume its own outputs); any
+// other placeholder without a value is returned in missing.
+func Expand(prompt, skipRepo string, lookup func(Ref) (string, bool)) (expanded string, missing []string) {
+	expanded = placeholderPattern.ReplaceAllStringFunc(prompt, func(match string) string {
+		m := placeholderPattern.FindStringSubmatch(match)
+		ref := Ref{Repo: m[1], Name: m[2]}
+		if ref.Repo == skipRepo {
+			return match
+		}
+		if value, ok := lookup(ref); ok {
+			return value
+		}
+		missing = append(missing, ref.String())
+		return match
+	})
+	return expanded, missing
+}
+
+// ParseOutputs extracts "COPYCAT_OUTPUT name=value" lines from AI output.
+func ParseOutputs(aiOutput string) map[string]string {
+	outputs := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(aiOutput))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		idx := strings.Index(line, outputMarker)
+		if idx < 0 {
+			continue
+		}
+		name, value, ok := strings.Cut(line[idx+len(outputMarker):], "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		outputs[name] = strings.Trim(strings.TrimSpace(value), "`\"'")
+	}
+	return outputs
+}
+
+// Capture runs each configured output command in repoPath and merges the
+// results with the values the AI declared in its output. Command values win.
+func Capture(ctx context.Context, repoPath string, commands map[string]string, aiOutput string) (map[string]string, error) {
+	outputs := ParseOutputs(aiOutput)
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cmd := exec.CommandContext(ctx, "sh", "-c", commands[name])
+		cmd.Dir = repoPath
+		output, err := cmd.Output()
+		if err != nil {
+			return outputs, fmt.Errorf("output %q command failed: %v", name, err)
+		}
+		outputs[name] = strings.TrimSpace(string(output))
+	}
+	return outputs, nil
+}
+
+// Summary renders outputs as "name=value" pairs in name order.
+func Summary(outputs map[string]string) string {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+outputs[name])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,54 @@
+package artifacts
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReferences(t *testing.T) {
+	refs := References("Bump to {{shared-lib.version}} and pin {{ go.tools.sha }}; again {{shared-lib.version}}")
+
+	expected := []Ref{{Repo: "shared-lib", Name: "version"}, {Repo: "go.tools", Name: "sha"}}
+	if len(refs) != len(expected) {
+		t.Fatalf("expected %d refs, got %v", len(expected), refs)
+	}
+	for i, want := range expected {
+		if refs[i] != want {
+			t.Errorf("ref %d: expected %v, got %v", i, want, refs[i])
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	values := map[Ref]string{{Repo: "lib", Name: "version"}: "1.4.0"}
+	lookup := func(ref Ref) (string, bool) {
+		v, ok := values[ref]
+		return v, ok
+	}
+
+	expanded, missing := Expand("Use lib {{lib.version}} and {{lib.sha}}", "app", lookup)
+	if expanded != "Use lib 1.4.0 and {{lib.sha}}" {
+		t.Errorf("unexpected expansion %q", expanded)
+	}
+	if len(missing) != 1 || missing[0] != "lib.sha" {
+		t.Errorf("expected lib.sha to be missing, got %v", missing)
+	}
+
+	expanded, missing = Expand("Publish {{lib.version}}", "lib", lookup)
+	if expanded != "Publish {{lib.version}}" || len(missing) != 0 {
+		t.Errorf("expected own placeholders to be left alone, got %q %v", expanded, missing)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	aiOutput := "Bumped version.\nCOPYCAT_OUTPUT version=`1.4.0`\nCOPYCAT_OUTPUT changelog = updated\nCOPYCAT_OUTPUT broken\n"
+
+	outputs, err := Capture(context.Background(), t.TempDir(), map[string]string{"sha": "echo abc123"}, aiOutput)
+	if err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+
+	if got := Summary(outputs); got != "changelog=updated, sha=abc123, version=1.4.0" {
+		t.Errorf("unexpected outputs %q", got)
+	}
+}
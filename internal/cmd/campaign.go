@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/history"
+)
+
+// RunCampaign handles the `copycat campaign report <id>` subcommand.
+func RunCampaign(args []string) error {
+	if len(args) != 2 || args[0] != "report" {
+		return fmt.Errorf("usage: copycat campaign report <id>")
+	}
+
+	path, err := config.HistoryPath()
+	if err != nil {
+		return fmt.Errorf("failed to resolve history path: %w", err)
+	}
+	records, err := history.Load(path)
+	if err != nil {
+		return err
+	}
+	report, ok := history.BuildCampaignReport(records, args[1])
+	if !ok {
+		return fmt.Errorf("no runs tagged with campaign %q; tag runs with 'copycat -campaign %s'", args[1], args[1])
+	}
+
+	printCampaignReport(report)
+	return nil
+}
+
+// printCampaignReport writes the report as a summary for stakeholders,
+// followed by where each repo stands.
+func printCampaignReport(report history.CampaignReport) {
+	fmt.Printf("Campaign %s\n\n", report.ID)
+
+	var actions []string
+	for action, runs := range report.Actions {
+		actions = append(actions, fmt.Sprintf("%s: %d", action, runs))
+	}
+	sort.Strings(actions)
+	fmt.Printf("  Runs:      %d (%s)\n", report.Runs, strings.Join(actions, ", "))
+	fmt.Printf("  Period:    %s to %s\n", report.First.Local().Format("2006-01-02 15:04"), report.Last.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("  Duration:  %s running\n", report.Duration.Round(time.Second))
+	cost := report.Usage.String()
+	if cost == "" {
+		cost = "not reported"
+	}
+	fmt.Printf("  Cost:      %s\n", cost)
+
+	outcomes := report.Outcomes()
+	var counts []string
+	for _, outcome := range []string{"succeeded", "failed", "skipped", "cancelled"} {
+		if outcomes[outcome] > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", outcomes[outcome], outcome))
+		}
+	}
+	fmt.Printf("  Repos:     %d (%s)\n", len(report.Repos), strings.Join(counts, ", "))
+	fmt.Printf("  PRs:       %d\n\n", report.PRs())
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, repo := range report.Repos {
+		line := fmt.Sprintf("  %s\t%s\t%s", repo.Repo, repo.Outcome, repo.PRURL)
+		if repo.Attempts > 1 {
+			line += fmt.Sprintf("\t%d runs", repo.Attempts)
+		}
+		fmt.Fprintln(w, line)
+	}
+	w.Flush()
+}
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/ai"
+	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/input"
+	"github.com/saltpay/copycat/v2/internal/permission"
+)
+
+// demoOrg is the made-up organization the demo's projects belong to.
+const demoOrg = "copycat-demo"
+
+// demoProjects are the repos offered for selection in the demo.
+var demoProjects = []config.Project{
+	{Repo: "payments-api", SlackRoom: "#team-payments"},
+	{Repo: "ledger-service", SlackRoom: "#team-ledger"},
+	{Repo: "checkout-web", SlackRoom: "#team-checkout"},
+	{Repo: "fraud-detector", SlackRoom: "#team-risk"},
+	{Repo: "notifications", SlackRoom: "#team-platform"},
+	{Repo: "merchant-portal", SlackRoom: "#team-merchants"},
+	{Repo: "settlement-jobs", SlackRoom: "#team-ledger"},
+	{Repo: "docs-site"},
+}
+
+// RunDemo walks through the whole TUI (project selection, the wizard,
+// permission prompts, checkpoints and results) against made-up repos and a
+// simulated AI tool. Nothing is cloned, pushed, opened or posted.
+func RunDemo() error {
+	appConfig := config.DefaultConfig(demoOrg)
+	appConfig.Parallelism = 3
+	appConfig.Tools = []config.AITool{{Name: "demo-ai", Command: "demo-ai"}}
+	appConfig.Default = "demo-ai"
+
+	fmt.Println("Starting the Copycat demo. The repos and the AI tool are simulated: nothing is cloned, pushed or posted.")
+	time.Sleep(time.Second)
+
+	result, err := input.RunDashboard(input.DashboardConfig{
+		Projects:      demoProjects,
+		AIToolsConfig: &appConfig.AIToolsConfig,
+		GitHubConfig:  appConfig.GitHub,
+		AppConfig:     *appConfig,
+		Parallelism:   appConfig.Parallelism,
+		FetchProjects: func(discovery config.DiscoveryFilter) ([]config.Project, error) {
+			time.Sleep(500 * time.Millisecond)
+			var projects []config.Project
+			for _, p := range demoProjects {
+				if discovery.Matches(p, time.Now()) {
+					projects = append(projects, p)
+				}
+			}
+			return projects, nil
+		},
+		ProcessRepos: func(sender *input.StatusSender, projects []config.Project, setup *input.WizardResult) {
+			runDemo(sender, projects, appConfig.Parallelism, func(ctx context.Context, i int, p config.Project) input.ProjectDoneMsg {
+				return demoChange(ctx, sender, i, p, setup)
+			})
+		},
+		AssessRepos: func(sender *input.StatusSender, projects []config.Project, setup *input.WizardResult) {
+			var mu sync.Mutex
+			findings := make(map[string]string)
+			assessments := make(map[string]ai.Assessment)
+			runDemo(sender, projects, appConfig.Parallelism, func(ctx context.Context, i int, p config.Project) input.ProjectDoneMsg {
+				msg, finding, assessment := demoAssess(ctx, sender, i, p, setup)
+				if msg.Success {
+					mu.Lock()
+					findings[p.Key()] = finding
+					if setup.StructuredAssessment {
+						assessments[p.Key()] = assessment
+					}
+					mu.Unlock()
+				}
+				return msg
+			})
+			sender.PostStatus("Summarizing findings across all projects...")
+			time.Sleep(time.Second)
+			sender.AssessmentResult(fmt.Sprintf("(demo) %d of %d repos answered. In a real run, the AI tool summarizes their findings here: common patterns, outliers and what to do next.", len(findings), len(projects)), findings, assessments)
+		},
+		SlackToken: "demo",
+		SendSlackNotifications: func(projects []config.Project, prTitle, prompt string, prURLs, ciStatus map[string]string, token string, onStatus func(string)) {
+			demoSlack(projects, onStatus)
+		},
+		SendSlackAssessmentFindings: func(projects []config.Project, question string, findings map[string]string, token string, onStatus func(string)) {
+			demoSlack(projects, onStatus)
+		},
+		// Profiles live for the demo only; config.yaml is never written
+		SaveProfile: func(name string, repos []string) error { return nil },
+	})
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		fmt.Println("Demo cancelled.")
+		return nil
+	}
+
+	fmt.Println("\nThat was the demo. Run `copycat` to set up your own organization, or see the README for what each step can do.")
+	return nil
+}
+
+// runDemo processes projects in batches like a real run, pausing at each
+// checkpoint until the user continues, and reports each result.
+func runDemo(sender *input.StatusSender, projects []config.Project, parallelism int, process func(ctx context.Context, i int, p config.Project) input.ProjectDoneMsg) {
+	checkpoint := parallelism
+	if checkpoint < 5 {
+		checkpoint = 5
+	}
+
+	for batchStart := 0; batchStart < len(projects); batchStart += checkpoint {
+		batchEnd := min(batchStart+checkpoint, len(projects))
+
+		sem := make(chan struct{}, parallelism)
+		var wg sync.WaitGroup
+		for i := batchStart; i < batchEnd; i++ {
+			p := projects[i]
+			ctx, cancel := context.WithCancel(context.Background())
+			if sender.CancelRegistry != nil {
+				sender.CancelRegistry.Register(p.Key(), cancel)
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer cancel()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				sender.Done(process(ctx, i, p))
+			}()
+		}
+		wg.Wait()
+
+		if batchEnd < len(projects) && sender.ResumeCh != nil {
+			<-sender.ResumeCh
+		}
+	}
+}
+
+// demoStep shows status for a moment, streaming log lines into the live
+// log. It returns false if the repo was cancelled meanwhile.
+func demoStep(ctx context.Context, sender *input.StatusSender, repo, status string, lines ...string) bool {
+	sender.UpdateStatus(repo, status)
+	for _, line := range append(lines, "") {
+		if line != "" {
+			sender.Log(repo, line)
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(700 * time.Millisecond):
+		}
+	}
+	return true
+}
+
+var demoCancelled = input.ProjectDoneMsg{Status: "Cancelled ✗", Error: fmt.Errorf("cancelled")}
+
+// demoChange simulates a code change: the first repo asks for permission to
+// run its tests, and every few repos one fails or makes no changes, so each
+// kind of result shows up.
+func demoChange(ctx context.Context, sender *input.StatusSender, i int, p config.Project, setup *input.WizardResult) input.ProjectDoneMsg {
+	repo := p.Key()
+	cancelled := demoCancelled
+	cancelled.Repo = repo
+
+	if !demoStep(ctx, sender, repo, "Cloning...") {
+		return cancelled
+	}
+	if !demoStep(ctx, sender, repo, "Running AI agent...",
+		"Reading the repository layout...",
+		"Found 3 files relevant to the prompt.",
+		"Editing src/config.go...") {
+		return cancelled
+	}
+
+	testsRan := false
+	if i == 0 {
+		responseCh := make(chan permission.PermissionResponse, 1)
+		sender.RequestPermission(permission.PermissionRequest{
+			ID:         "demo-permission",
+			Repo:       repo,
+			ToolName:   "Bash",
+			Command:    "make test",
+			ResponseCh: responseCh,
+		})
+		sender.UpdateStatus(repo, "Waiting for permission...")
+		select {
+		case <-ctx.Done():
+			return cancelled
+		case resp := <-responseCh:
+			testsRan = resp.Approved
+		}
+	}
+	testLine := "Skipped the tests: permission denied."
+	if i != 0 || testsRan {
+		testLine = "Tests passed (42 passed, 0 failed)."
+	}
+	if !demoStep(ctx, sender, repo, "Running AI agent...", testLine) {
+		return cancelled
+	}
+
+	output := fmt.Sprintf("Applied the change to %s:\n- Updated src/config.go\n- Added a test in src/config_test.go\n%s", repo, testLine)
+	usage := ai.Usage{InputTokens: 18000 + 1500*i, OutputTokens: 1200 + 100*i, CostUSD: 0.21 + 0.03*float64(i), Estimated: true}
+	switch i % 4 {
+	case 2:
+		err := fmt.Errorf("AI tool failed: exit status 1 (simulated)")
+		return input.ProjectDoneMsg{Repo: repo, Status: fmt.Sprintf("Failed ⚠️ %v 💲 %s", err, usage), Error: err, AIOutput: output, Usage: usage}
+	case 3:
+		err := fmt.Errorf("no changes detected")
+		return input.ProjectDoneMsg{Repo: repo, Status: fmt.Sprintf("Skipped ⊘ %v 💲 %s", err, usage), Skipped: true, Error: err, AIOutput: "The repository already does this; nothing to change.", Usage: usage}
+	}
+
+	if !demoStep(ctx, sender, repo, "Pushing branch "+setup.BranchName+"...") || !demoStep(ctx, sender, repo, "Creating PR...") {
+		return cancelled
+	}
+	prURL := fmt.Sprintf("https://github.com/%s/%s/pull/%d", demoOrg, p.Repo, 100+i)
+	return input.ProjectDoneMsg{
+		Repo:     repo,
+		Status:   fmt.Sprintf("Completed ✅ PR: %s 💲 %s", prURL, usage),
+		Success:  true,
+		PRURL:    prURL,
+		AIOutput: output,
+		Diff:     "--- a/src/config.go\n+++ b/src/config.go\n@@ -12,3 +12,3 @@\n-\tTimeout: 10,\n+\tTimeout: 30,\n",
+		Usage:    usage,
+	}
+}
+
+// demoAssess simulates an assessment, with a spread of structured answers.
+func demoAssess(ctx context.Context, sender *input.StatusSender, i int, p config.Project, setup *input.WizardResult) (input.ProjectDoneMsg, string, ai.Assessment) {
+	repo := p.Key()
+	cancelled := demoCancelled
+	cancelled.Repo = repo
+
+	if !demoStep(ctx, sender, repo, "Cloning...") ||
+		!demoStep(ctx, sender, repo, "Running assessment...", "Searching the code...", "Reading the build files...") {
+		return cancelled, "", ai.Assessment{}
+	}
+
+	statuses := []string{ai.StatusCompliant, ai.StatusPartial, ai.StatusNonCompliant}
+	assessment := ai.Assessment{
+		Status:   statuses[i%len(statuses)],
+		Score:    90 - 35*(i%len(statuses)),
+		Evidence: "src/config.go",
+	}
+	finding := fmt.Sprintf("(demo) %s: a made-up answer to %q, pointing at src/config.go.", repo, strings.TrimSpace(setup.Prompt))
+	status := "Assessed ✅"
+	if setup.StructuredAssessment {
+		status = fmt.Sprintf("Assessed ✅ %s (%d)", assessment.Status, assessment.Score)
+	}
+	return input.ProjectDoneMsg{Repo: repo, Status: status, Success: true}, finding, assessment
+}
+
+// demoSlack reports the messages a real run would post, without posting.
+func demoSlack(projects []config.Project, onStatus func(string)) {
+	byRoom := make(map[string][]string)
+	for _, p := range projects {
+		if p.SlackRoom != "" {
+			byRoom[p.SlackRoom] = append(byRoom[p.SlackRoom], p.Key())
+		}
+	}
+	rooms := make([]string, 0, len(byRoom))
+	for room := range byRoom {
+		rooms = append(rooms, room)
+	}
+	sort.Strings(rooms)
+	for _, room := range rooms {
+		time.Sleep(300 * time.Millisecond)
+		onStatus(fmt.Sprintf("✓ (demo) Would notify %s about %s", room, strings.Join(byRoom[room], ", ")))
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/slack"
+)
+
+// RunSlack handles the `copycat slack <connect|disconnect|status>` subcommand.
+func RunSlack(target string) error {
+	switch target {
+	case "connect":
+		return runSlackConnect()
+	case "disconnect":
+		if err := slack.DeleteToken(); err != nil {
+			return err
+		}
+		fmt.Println("✓ Slack token removed.")
+		return nil
+	case "status":
+		// $SLACK_BOT_TOKEN wins over the stored token, as in a run
+		if strings.TrimSpace(os.Getenv("SLACK_BOT_TOKEN")) != "" {
+			fmt.Println("✓ Slack is connected with the token in $SLACK_BOT_TOKEN.")
+			return nil
+		}
+		if slack.ResolveToken() == "" {
+			fmt.Println("Slack is not connected. Run 'copycat slack connect' to connect.")
+			return nil
+		}
+		fmt.Println("✓ Slack is connected.")
+		return nil
+	default:
+		return fmt.Errorf("unknown slack command %q\n\nUsage: copycat slack <connect|disconnect|status>", target)
+	}
+}
+
+func runSlackConnect() error {
+	configPath, err := config.ConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to resolve config path: %w", err)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w\n\nRun 'copycat' to set up your configuration", err)
+	}
+
+	result, err := slack.Connect(context.Background(), cfg.Slack, func(line string) {
+		fmt.Println(line)
+	})
+	if err != nil {
+		return fmt.Errorf("slack connect failed: %w", err)
+	}
+
+	if err := slack.StoreToken(result.Token); err != nil {
+		return err
+	}
+
+	if result.TeamName != "" {
+		fmt.Printf("✓ Connected to Slack workspace %s\n", result.TeamName)
+	} else {
+		fmt.Println("✓ Connected to Slack")
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ActionPipeline is a named list of deterministic edits offered by the "Run
+// Actions" wizard option. An AI tool may follow it with a prompt of its own.
+type ActionPipeline struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description,omitempty"`
+	Steps       []Action `yaml:"steps"`
+}
+
+// Action is one deterministic edit. Files selects the tracked files it
+// applies to; add_file writes Path instead.
+type Action struct {
+	Type    string   `yaml:"type"`              // one of the Action* types
+	Files   []string `yaml:"files,omitempty"`   // glob patterns of tracked files
+	Search  string   `yaml:"search,omitempty"`  // text, or regex for regex_replace
+	Replace string   `yaml:"replace,omitempty"` // replacement; regex_replace expands $1
+	Path    string   `yaml:"path,omitempty"`    // file written by add_file
+	Content string   `yaml:"content,omitempty"` // content written by add_file
+	Key     string   `yaml:"key,omitempty"`     // dotted YAML or JSON key for set_key and delete_key
+	Value   any      `yaml:"value,omitempty"`   // value written by set_key
+}
+
+// Action types.
+const (
+	ActionReplace      = "replace"
+	ActionRegexReplace = "regex_replace"
+	ActionAddFile      = "add_file"
+	ActionRemoveFile   = "remove_file"
+	ActionSetKey       = "set_key"
+	ActionDeleteKey    = "delete_key"
+)
+
+// validate reports what is missing or wrong in the action.
+func (a Action) validate() error {
+	needFiles := func() error {
+		if len(a.Files) == 0 {
+			return fmt.Errorf("%s needs files", a.Type)
+		}
+		return nil
+	}
+	switch a.Type {
+	case ActionReplace, ActionRegexReplace:
+		if a.Search == "" {
+			return fmt.Errorf("%s needs search", a.Type)
+		}
+		if a.Type == ActionRegexReplace {
+			if _, err := regexp.Compile(a.Search); err != nil {
+				return fmt.Errorf("invalid regex %q: %v", a.Search, err)
+			}
+		}
+		return needFiles()
+	case ActionAddFile:
+		if a.Path == "" || !filepath.IsLocal(a.Path) {
+			return errors.New("add_file needs a path inside the repo")
+		}
+		return nil
+	case ActionRemoveFile:
+		return needFiles()
+	case ActionSetKey, ActionDeleteKey:
+		if a.Key == "" {
+			return fmt.Errorf("%s needs a key", a.Type)
+		}
+		if a.Type == ActionSetKey && a.Value == nil {
+			return errors.New("set_key needs a value")
+		}
+		for _, pattern := range a.Files {
+			switch strings.ToLower(path.Ext(pattern)) {
+			case ".json", ".yaml", ".yml":
+			default:
+				return fmt.Errorf("%s only edits .json, .yaml and .yml files, not %q", a.Type, pattern)
+			}
+		}
+		return needFiles()
+	case "":
+		return errors.New("missing type")
+	}
+	return fmt.Errorf("unknown type %q", a.Type)
+}
+
+// ActionPipeline returns the pipeline named name.
+func (c *Config) ActionPipeline(name string) (*ActionPipeline, bool) {
+	for i := range c.Actions {
+		if c.Actions[i].Name == name {
+			return &c.Actions[i], true
+		}
+	}
+	return nil, false
+}
+
+// validateActions checks every pipeline has a unique name and valid steps.
+func validateActions(pipelines []ActionPipeline, filename string) error {
+	names := make(map[string]bool, len(pipelines))
+	for _, p := range pipelines {
+		if p.Name == "" || len(p.Steps) == 0 {
+			return fmt.Errorf("action pipelines in %s need a name and steps", filename)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("duplicate action pipeline %q in %s", p.Name, filename)
+		}
+		names[p.Name] = true
+		for i, step := range p.Steps {
+			if err := step.validate(); err != nil {
+				return fmt.Errorf("action pipeline %q step %d in %s: %v", p.Name, i+1, filename, err)
+			}
+		}
+	}
+	return nil
+}
+
+// TempChange is a file change made for a single run's AI step and undone
+// before committing: the file at Path is removed, or written with Content.
+type TempChange struct {
+	Path    string `json:"path"`
+	Remove  bool   `json:"remove,omitempty"`
+	Content string `json:"content,omitempty"`
+	Source  string `json:"source,omitempty"` // local file Content was read from, for display
+}
+
+// String describes the change, e.g. "- CLAUDE.md" or "+ .env (from ~/test.env)".
+func (c TempChange) String() string {
+	if c.Remove {
+		return "- " + c.Path
+	}
+	if c.Source != "" {
+		return fmt.Sprintf("+ %s (from %s)", c.Path, c.Source)
+	}
+	return "+ " + c.Path
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// CommitMessage templates the message of the commits a run pushes, separately
+// from the PR title. Subject and Body are Go templates over
+// CommitMessageData, e.g. "chore(deps): {{.PRTitle}}". The commit message is
+// the PR title when it is not set.
+type CommitMessage struct {
+	Subject  string   `yaml:"subject,omitempty"`  // the PR title when empty
+	Body     string   `yaml:"body,omitempty"`     // written after a blank line
+	Trailers []string `yaml:"trailers,omitempty"` // "Key: value" lines, e.g. Co-authored-by
+}
+
+// CommitMessageData is what commit message templates can refer to.
+type CommitMessageData struct {
+	PRTitle string
+	Repo    string
+	Branch  string
+}
+
+// Render builds the commit message for data.
+func (c CommitMessage) Render(data CommitMessageData) (string, error) {
+	subject := data.PRTitle
+	if c.Subject != "" {
+		var err error
+		if subject, err = renderTemplate("subject", c.Subject, data); err != nil {
+			return "", err
+		}
+	}
+	parts := []string{strings.TrimSpace(subject)}
+	if c.Body != "" {
+		body, err := renderTemplate("body", c.Body, data)
+		if err != nil {
+			return "", err
+		}
+		if body = strings.TrimSpace(body); body != "" {
+			parts = append(parts, body)
+		}
+	}
+	if len(c.Trailers) > 0 {
+		parts = append(parts, strings.Join(c.Trailers, "\n"))
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// validate checks the templates render and the trailers are "Key: value"
+// lines, so mistakes show up when the config loads rather than at push time.
+func (c CommitMessage) validate() error {
+	if _, err := c.Render(CommitMessageData{}); err != nil {
+		return err
+	}
+	for _, trailer := range c.Trailers {
+		key, value, ok := strings.Cut(trailer, ": ")
+		if !ok || key == "" || strings.ContainsAny(key, " \n") || strings.TrimSpace(value) == "" {
+			return fmt.Errorf("commit_message trailer %q must look like \"Key: value\"", trailer)
+		}
+	}
+	return nil
+}
+
+func renderTemplate(name, text string, data CommitMessageData) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("commit_message %s: %v", name, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("commit_message %s: %v", name, err)
+	}
+	return b.String(), nil
+}
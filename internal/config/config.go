@@ -2,10 +2,22 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"net"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/saltpay/copycat/v2/internal/i18n"
+	"github.com/saltpay/copycat/v2/internal/issuetemplate"
+	"github.com/saltpay/copycat/v2/internal/permission"
 	"gopkg.in/yaml.v3"
 )
 
@@ -13,18 +25,823 @@ type Project struct {
 	Repo      string   `yaml:"repo"`
 	SlackRoom string   `yaml:"slack_room"`
 	Topics    []string `yaml:"topics,omitempty"`
+	// DefaultBranch, Language and PushedAt are recorded from GitHub each time
+	// the repo list is fetched.
+	DefaultBranch string    `yaml:"default_branch,omitempty"`
+	Language      string    `yaml:"language,omitempty"`
+	PushedAt      time.Time `yaml:"pushed_at,omitempty"`
+	// Team owns the repo; follow-up Jira tickets can be grouped by it.
+	Team string `yaml:"team,omitempty"`
+	// Reviewers are GitHub users or org/team slugs asked to review the PRs
+	// when the repo has no Slack room (see GitHubConfig.NotifyReviewers).
+	Reviewers []string `yaml:"reviewers,omitempty"`
+	// DependsOn lists repos whose changes must land first when selected in the same run.
+	DependsOn []string `yaml:"depends_on,omitempty"`
+	// Outputs maps value names to shell commands run in the repo after the AI step;
+	// later repos in the run can use them in prompts as {{repo.name}}.
+	Outputs map[string]string `yaml:"outputs,omitempty"`
+	// TargetBranches lists base branches to open PRs against instead of the
+	// default branch; each becomes its own job sharing one clone via worktrees.
+	TargetBranches []string `yaml:"target_branches,omitempty"`
+	// PromptNotes is appended to every prompt run against this repo, for
+	// repo-specific context such as "this repo uses Gradle, not Maven".
+	PromptNotes string `yaml:"prompt_notes,omitempty"`
+	// Services lists the services of a monorepo; each becomes its own project
+	// for selection, prompts and notifications (see ExpandServices).
+	Services []Service `yaml:"services,omitempty"`
+	// Hooks override the configured hooks for this repo, hook by hook.
+	Hooks *Hooks `yaml:"hooks,omitempty"`
+	// VerifyCommand overrides the configured verify_command for this repo.
+	VerifyCommand string `yaml:"verify_command,omitempty"`
+	// PushURL is where branches are pushed when the repo's canonical remote
+	// is a mirror and GitHub is read-only; PRs are still opened on GitHub once
+	// the branch has synced there.
+	PushURL string `yaml:"push_url,omitempty"`
+	// TargetBranch is the base branch of an expanded job (see ExpandTargetBranches).
+	TargetBranch string `yaml:"-"`
+	// Path scopes the entry to a subdirectory of a monorepo: the prompt, the
+	// diff and the PR stay within it. Several entries of one repo with
+	// different paths run as separate projects.
+	Path string `yaml:"path,omitempty"`
+	// Service names an expanded service, or the path of a subdirectory entry.
+	Service string `yaml:"-"`
+}
+
+// Service is one service in a monorepo, living at Path within the repo.
+type Service struct {
+	Name        string `yaml:"name"`
+	Path        string `yaml:"path"`
+	SlackRoom   string `yaml:"slack_room,omitempty"`   // defaults to the repo's room
+	PromptNotes string `yaml:"prompt_notes,omitempty"` // added to the repo's prompt notes
+}
+
+// Key uniquely identifies a project job within a run: the repo name, plus
+// "/service" for a monorepo service and "@branch" when it targets a specific
+// base branch.
+func (p Project) Key() string {
+	key := p.Repo
+	if p.Service != "" {
+		key += "/" + p.Service
+	}
+	if p.TargetBranch != "" {
+		key += "@" + p.TargetBranch
+	}
+	return key
+}
+
+// WithPromptNotes returns prompt followed by the project's prompt notes, if
+// any, and for a monorepo service, which directory the changes belong in.
+func (p Project) WithPromptNotes(prompt string) string {
+	switch dir := strings.Trim(p.Path, "/"); {
+	case dir == "":
+	case p.Service == dir:
+		prompt += fmt.Sprintf("\n\nThis repository is a monorepo. Only work in %s/ and leave the rest of the repository unchanged.", dir)
+	default:
+		prompt += fmt.Sprintf("\n\nThis repository is a monorepo. Only work on the %s service in %s/ and leave other services unchanged.", p.Service, dir)
+	}
+	notes := strings.TrimSpace(p.PromptNotes)
+	if notes == "" {
+		return prompt
+	}
+	return prompt + "\n\nNotes for this repository:\n" + notes
+}
+
+// ExpandServices returns one project per service for monorepos that declare
+// services, leaving the rest unchanged. Services inherit the repo's settings
+// and override its Slack room when they set their own. Entries scoped to a
+// path become a service named after it.
+func ExpandServices(projects []Project) []Project {
+	var expanded []Project
+	for _, p := range projects {
+		if dir := strings.Trim(p.Path, "/"); dir != "" && p.Service == "" {
+			p.Path = dir
+			p.Service = dir
+		}
+		if len(p.Services) == 0 || p.Service != "" {
+			expanded = append(expanded, p)
+			continue
+		}
+		for _, s := range p.Services {
+			service := p
+			service.Services = nil
+			service.Service = s.Name
+			service.Path = s.Path
+			if s.SlackRoom != "" {
+				service.SlackRoom = s.SlackRoom
+			}
+			if notes := strings.TrimSpace(s.PromptNotes); notes != "" {
+				service.PromptNotes = strings.TrimSpace(p.PromptNotes + "\n" + notes)
+			}
+			expanded = append(expanded, service)
+		}
+	}
+	return expanded
+}
+
+// ExpandTargetBranches returns one project per target branch for projects that
+// declare target_branches, leaving the rest unchanged.
+func ExpandTargetBranches(projects []Project) []Project {
+	var expanded []Project
+	for _, p := range projects {
+		if len(p.TargetBranches) == 0 || p.TargetBranch != "" {
+			expanded = append(expanded, p)
+			continue
+		}
+		for _, branch := range p.TargetBranches {
+			target := p
+			target.TargetBranch = branch
+			expanded = append(expanded, target)
+		}
+	}
+	return expanded
 }
 
 type GitHubConfig struct {
+	Host               string `yaml:"host,omitempty"` // GitHub Enterprise host, e.g. github.mycorp.com; github.com when empty
 	Organization       string `yaml:"organization"`
 	AutoDiscoveryTopic string `yaml:"auto_discovery_topic"`
+	OptOutTopic        string `yaml:"opt_out_topic,omitempty"`    // repos with this topic are never selected; defaults to copycat-exclude
+	NotifyReviewers    bool   `yaml:"notify_reviewers,omitempty"` // request reviews from a repo's reviewers when it has no slack_room
+	// Discovery narrows the listed repos by language, last push and topics
+	Discovery DiscoveryFilter `yaml:"discovery,omitempty"`
+	// ExcludeRepos lists repo names or globs, e.g. legacy-*, that are left out
+	// of discovery and the cached project list.
+	ExcludeRepos []string `yaml:"exclude_repos,omitempty"`
+	// CloneProtocol is how repos are cloned: ssh (the default), https with
+	// the GitHub token, or gh for `gh repo clone`.
+	CloneProtocol string `yaml:"clone_protocol,omitempty"`
+}
+
+// NotifiesOnGitHub reports whether the project's owners hear about its PRs
+// through GitHub review requests instead of Slack.
+func (g GitHubConfig) NotifiesOnGitHub(p Project) bool {
+	return g.NotifyReviewers && strings.TrimSpace(p.SlackRoom) == "" && len(p.Reviewers) > 0
+}
+
+// DefaultHost is the GitHub host used when none is configured.
+const DefaultHost = "github.com"
+
+// HostName returns the configured GitHub host, or github.com.
+func (g GitHubConfig) HostName() string {
+	if host := strings.TrimSpace(g.Host); host != "" {
+		return host
+	}
+	return DefaultHost
+}
+
+// Clone protocols for github.clone_protocol.
+const (
+	CloneSSH   = "ssh"
+	CloneHTTPS = "https"
+	CloneGH    = "gh"
+)
+
+// Protocol returns the configured clone protocol, or ssh.
+func (g GitHubConfig) Protocol() string {
+	if protocol := strings.ToLower(strings.TrimSpace(g.CloneProtocol)); protocol != "" {
+		return protocol
+	}
+	return CloneSSH
+}
+
+// CloneURL returns the URL of a repo in the organization: its SSH URL, or
+// its HTTPS URL when cloning over HTTPS or with gh.
+func (g GitHubConfig) CloneURL(repo string) string {
+	if g.Protocol() == CloneSSH {
+		return fmt.Sprintf("git@%s:%s/%s.git", g.HostName(), g.Organization, repo)
+	}
+	return fmt.Sprintf("https://%s/%s/%s.git", g.HostName(), g.Organization, repo)
+}
+
+// DefaultOptOutTopic is the topic repo owners add to keep their repo out of
+// copycat runs when no opt_out_topic is configured.
+const DefaultOptOutTopic = "copycat-exclude"
+
+// OptOutReason explains why a project cannot be selected, or returns "" when
+// it has not opted out.
+func (g GitHubConfig) OptOutReason(p Project) string {
+	topic := strings.TrimSpace(g.OptOutTopic)
+	if topic == "" {
+		topic = DefaultOptOutTopic
+	}
+	for _, t := range p.Topics {
+		if strings.EqualFold(t, topic) {
+			return fmt.Sprintf("opted out via the %s topic", topic)
+		}
+	}
+	return ""
+}
+
+// IsExcluded reports whether repo matches one of the exclude_repos names or globs.
+func (g GitHubConfig) IsExcluded(repo string) bool {
+	for _, pattern := range g.ExcludeRepos {
+		if matched, _ := path.Match(strings.TrimSpace(pattern), repo); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutExcluded returns the projects whose repos are not excluded.
+func (g GitHubConfig) WithoutExcluded(projects []Project) []Project {
+	if len(g.ExcludeRepos) == 0 {
+		return projects
+	}
+	var kept []Project
+	for _, p := range projects {
+		if !g.IsExcluded(p.Repo) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// SlackConfig holds the Slack app credentials used by `copycat slack connect`.
+type SlackConfig struct {
+	ClientID     string         `yaml:"client_id,omitempty"`
+	ClientSecret string         `yaml:"client_secret,omitempty"`
+	RedirectPort int            `yaml:"redirect_port,omitempty"`
+	QuietHours   []QuietHours   `yaml:"quiet_hours,omitempty"` // when notifications are held back
+	Approvals    SlackApprovals `yaml:"approvals,omitempty"`   // where permission requests can be answered too
+	Messages     SlackMessages  `yaml:"messages,omitempty"`    // templates for the notifications about PRs and issues
+	RunChannel   string         `yaml:"run_channel,omitempty"` // gets a thread per run, with a reply per repo
+}
+
+// DefaultApprovalsAddr is where Slack's button clicks on permission requests
+// are received unless configured.
+const DefaultApprovalsAddr = "127.0.0.1:3001"
+
+// SlackApprovals post each permission request to a Slack channel with
+// Approve and Deny buttons, so long runs can be answered from a phone. The
+// Slack app's interactivity request URL must reach ListenAddr, e.g. through
+// a tunnel. Requests can still be answered in the dashboard.
+type SlackApprovals struct {
+	Channel          string `yaml:"channel,omitempty"`
+	ListenAddr       string `yaml:"listen_addr,omitempty"`        // DefaultApprovalsAddr when empty
+	SigningSecretEnv string `yaml:"signing_secret_env,omitempty"` // holds the app's signing secret; SLACK_SIGNING_SECRET when empty
+}
+
+// Addr returns where Slack's button clicks are received.
+func (a SlackApprovals) Addr() string {
+	if a.ListenAddr == "" {
+		return DefaultApprovalsAddr
+	}
+	return a.ListenAddr
+}
+
+// SecretEnv returns the environment variable holding the signing secret.
+func (a SlackApprovals) SecretEnv() string {
+	if a.SigningSecretEnv == "" {
+		return "SLACK_SIGNING_SECRET"
+	}
+	return a.SigningSecretEnv
+}
+
+// QuietHours holds notifications to its channels back during a daily window
+// (and optionally weekends) in a timezone. They are scheduled for the end of
+// the window instead.
+type QuietHours struct {
+	Channels []string `yaml:"channels,omitempty"` // all channels when empty
+	Timezone string   `yaml:"timezone,omitempty"` // IANA name, e.g. Europe/Lisbon; local time by default
+	Start    string   `yaml:"start"`              // 24-hour time, e.g. "19:00"
+	End      string   `yaml:"end"`                // e.g. "08:00"; before Start for windows spanning midnight
+	Weekends bool     `yaml:"weekends,omitempty"` // quiet all Saturday and Sunday too
+}
+
+// QuietUntil returns when the quiet hours for channel covering t end, or the
+// zero time if notifications may be sent at t.
+func (s SlackConfig) QuietUntil(channel string, t time.Time) time.Time {
+	channel = strings.TrimPrefix(strings.TrimSpace(channel), "#")
+	var rules []QuietHours
+	for _, q := range s.QuietHours {
+		if len(q.Channels) == 0 || slices.ContainsFunc(q.Channels, func(c string) bool { return strings.TrimPrefix(c, "#") == channel }) {
+			rules = append(rules, q)
+		}
+	}
+
+	// Windows may overlap, so keep going until none covers the time
+	until := t
+	for moved := true; moved; {
+		moved = false
+		for _, q := range rules {
+			if end := q.until(until); !end.IsZero() {
+				until, moved = end, true
+			}
+		}
+	}
+	if until.Equal(t) {
+		return time.Time{}
+	}
+	return until
+}
+
+// until returns when the quiet period covering t ends, or the zero time if
+// t is outside it. Periods end at the end of the daily window or, for
+// weekends, at midnight on Monday, so those are the only candidates.
+func (q QuietHours) until(t time.Time) time.Time {
+	loc, start, end, err := q.parse()
+	if err != nil || !q.covers(t.In(loc), start, end) {
+		return time.Time{}
+	}
+	local := t.In(loc)
+	for day := 0; day <= 7; day++ {
+		date := local.AddDate(0, 0, day)
+		midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+		windowEnd := time.Date(date.Year(), date.Month(), date.Day(), int(end/time.Hour), int(end%time.Hour/time.Minute), 0, 0, loc)
+		for _, candidate := range []time.Time{midnight, windowEnd} {
+			if candidate.After(local) && !q.covers(candidate, start, end) {
+				return candidate
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// covers reports whether local, a time in the window's timezone, is quiet.
+func (q QuietHours) covers(local time.Time, start, end time.Duration) bool {
+	if q.Weekends && (local.Weekday() == time.Saturday || local.Weekday() == time.Sunday) {
+		return true
+	}
+	clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if start < end {
+		return clock >= start && clock < end
+	}
+	return clock >= start || clock < end
+}
+
+// parse returns the window's timezone and its start and end as times of day.
+func (q QuietHours) parse() (*time.Location, time.Duration, time.Duration, error) {
+	loc := time.Local
+	if q.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(q.Timezone); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+	var clock [2]time.Duration
+	for i, value := range []string{q.Start, q.End} {
+		parsed, err := time.Parse("15:04", value)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("%q is not a time like 19:00", value)
+		}
+		clock[i] = time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute
+	}
+	if clock[0] == clock[1] {
+		return nil, 0, 0, errors.New("start and end are the same")
+	}
+	return loc, clock[0], clock[1], nil
 }
 
 type Config struct {
 	GitHub            GitHubConfig `yaml:"github"`
 	Parallelism       int          `yaml:"parallelism,omitempty"`
 	AgentInstructions []string     `yaml:"agent_instructions,omitempty"`
-	AIToolsConfig     `yaml:",inline"`
+	ProtectedPaths    []string     `yaml:"protected_paths,omitempty"`
+	// AllowLineEndingChanges keeps line-ending-only edits instead of reverting them
+	AllowLineEndingChanges bool                 `yaml:"allow_line_ending_changes,omitempty"`
+	ReviewTool             string               `yaml:"review_tool,omitempty"`
+	TrivialChanges         TrivialChangesConfig `yaml:"trivial_changes,omitempty"`
+	LicenseHeader          LicenseHeaderConfig  `yaml:"license_header,omitempty"`
+	Prompts                []PromptTemplate     `yaml:"prompts,omitempty"`           // named prompt templates offered in the wizard
+	Actions                []ActionPipeline     `yaml:"actions,omitempty"`           // deterministic edits offered in the wizard
+	Verify                 []string             `yaml:"verify,omitempty"`            // built-in verifications run before pushing
+	Hooks                  Hooks                `yaml:"hooks,omitempty"`             // shell commands run in each repo around the AI step
+	VerifyCommand          string               `yaml:"verify_command,omitempty"`    // must pass on each repo's changes, e.g. the test suite
+	FlakyTestRuns          int                  `yaml:"flaky_test_runs,omitempty"`   // test suite runs per repo when detecting flaky tests
+	TimeoutMinutes         int                  `yaml:"timeout_minutes,omitempty"`   // per-repo limit on an AI tool run (0 = none)
+	FallbackTools          []string             `yaml:"fallback_tools,omitempty"`    // tried in order when the selected tool fails or changes nothing
+	HealthChecks           []HealthCheck        `yaml:"health_checks,omitempty"`     // scheduled read-only assessments
+	Budget                 Budget               `yaml:"budget,omitempty"`            // pauses a run once its AI spend passes a limit
+	RunLimits              RunLimits            `yaml:"run_limits,omitempty"`        // guards against runs on more repos than intended
+	PRPacing               PRPacing             `yaml:"pr_pacing,omitempty"`         // spaces out PR creation for org automation
+	PRMetadata             PRMetadata           `yaml:"pr_metadata,omitempty"`       // labels and milestone put on every PR
+	PRPolicy               PRPolicy             `yaml:"pr_policy,omitempty"`         // what PR titles and descriptions must look like
+	Permissions            Permissions          `yaml:"permissions,omitempty"`       // how the AI tool's permission requests are answered
+	Issues                 Issues               `yaml:"issues,omitempty"`            // who the issues a run opens are assigned to
+	EmptyRepos             string               `yaml:"empty_repos,omitempty"`       // what to do with repos without commits: skip or scaffold
+	CIChecks               CIChecks             `yaml:"ci_checks,omitempty"`         // waits for the checks on the PRs a run opened
+	CommitSigning          CommitSigning        `yaml:"commit_signing,omitempty"`    // signs the commits a run pushes
+	CommitMessage          CommitMessage        `yaml:"commit_message,omitempty"`    // templates the commit message; the PR title when unset
+	ConsistencyPaths       []string             `yaml:"consistency_paths,omitempty"` // files compared across repos after a run; all when empty
+	Profiles               map[string][]string  `yaml:"profiles,omitempty"`          // named project selections
+	Slack                  SlackConfig          `yaml:"slack,omitempty"`
+	Jira                   JiraConfig           `yaml:"jira,omitempty"`                 // where follow-up tickets from assessments go
+	PolicyReview           PolicyReview         `yaml:"policy_review,omitempty"`        // service that must approve each diff before it is pushed
+	Webhook                Webhook              `yaml:"webhook,omitempty"`              // gets a JSON event as runs start, repos finish and runs end
+	Retries                Retries              `yaml:"retries,omitempty"`              // attempts per network step on transient failures
+	RateLimit              RateLimit            `yaml:"rate_limit,omitempty"`           // when to wait for GitHub's API quota to reset
+	AdaptiveParallelism    AdaptiveParallelism  `yaml:"adaptive_parallelism,omitempty"` // sheds workers when the machine or GitHub is struggling
+	Language               string               `yaml:"language,omitempty"`             // of the dashboard and reports: en (default), pt-PT or is
+	AIToolsConfig          `yaml:",inline"`
+}
+
+// Built-in verifications that can be enabled under `verify:`.
+const (
+	VerifyAPIBreakingChanges = "api_breaking_changes"
+	VerifyTerraformPlan      = "terraform_plan"
+	VerifyHelmTemplate       = "helm_template"
+	VerifyDockerBuild        = "docker_build"
+)
+
+var knownVerifications = map[string]bool{
+	VerifyAPIBreakingChanges: true,
+	VerifyTerraformPlan:      true,
+	VerifyHelmTemplate:       true,
+	VerifyDockerBuild:        true,
+}
+
+// Hooks are shell commands run in each repo around the AI step. A failing
+// hook fails the repo.
+type Hooks struct {
+	Pre  string `yaml:"pre,omitempty"`  // before the AI step, e.g. "make generate"
+	Post string `yaml:"post,omitempty"` // after it and before committing, e.g. "make fmt && make test"
+}
+
+// HooksFor returns the hooks to run in project's repo: the configured ones,
+// overridden by the project's own.
+func (c *Config) HooksFor(project Project) Hooks {
+	hooks := c.Hooks
+	if project.Hooks != nil {
+		if project.Hooks.Pre != "" {
+			hooks.Pre = project.Hooks.Pre
+		}
+		if project.Hooks.Post != "" {
+			hooks.Post = project.Hooks.Post
+		}
+	}
+	return hooks
+}
+
+// What happens to a repo whose changes fail the verify command, as picked in
+// the wizard.
+const (
+	VerifyFailureBlock = "block" // no PR is opened (default)
+	VerifyFailureDraft = "draft" // the PR is opened as a draft with a note
+)
+
+// What happens to a repo without any commits, which has no branch to start from.
+const (
+	EmptyReposSkip     = "skip"     // the repo is skipped as uninitialized (default)
+	EmptyReposScaffold = "scaffold" // a README is pushed as the initial commit first
+)
+
+// VerifyCommandFor returns the command that must pass on project's changes
+// before its PR is opened: the project's own, or the configured one.
+func (c *Config) VerifyCommandFor(project Project) string {
+	if project.VerifyCommand != "" {
+		return project.VerifyCommand
+	}
+	return c.VerifyCommand
+}
+
+// Trivial change actions for diffs that only touch whitespace or comments.
+const (
+	TrivialChangesFlag  = "flag"  // push, with a warning on the result (default)
+	TrivialChangesSkip  = "skip"  // skip the repo without pushing
+	TrivialChangesAllow = "allow" // push without checking
+)
+
+// TrivialChangesConfig controls how diffs that only change whitespace or
+// comments are handled.
+type TrivialChangesConfig struct {
+	Action          string   `yaml:"action,omitempty"`
+	CommentPrefixes []string `yaml:"comment_prefixes,omitempty"` // defaults to common line comment markers
+}
+
+// Health check schedules.
+const (
+	HealthCheckDaily  = "daily"
+	HealthCheckWeekly = "weekly"
+)
+
+// HealthCheck is a read-only assessment that `copycat health-check` runs on a
+// schedule, posting how the answers changed since the previous run to Slack.
+type HealthCheck struct {
+	Name      string `yaml:"name"`
+	Question  string `yaml:"question"`
+	SlackRoom string `yaml:"slack_room"`        // where the digest is posted
+	Profile   string `yaml:"profile,omitempty"` // projects to assess; all when empty
+	Tool      string `yaml:"tool,omitempty"`    // defaults to the default tool
+	Every     string `yaml:"every,omitempty"`   // daily or weekly (default)
+}
+
+// Interval is how long after a run the health check is due again.
+func (h HealthCheck) Interval() time.Duration {
+	if h.Every == HealthCheckDaily {
+		return 24 * time.Hour
+	}
+	return 7 * 24 * time.Hour
+}
+
+// RunLimits guard against runs on more repos than intended, such as a stray
+// "select all". Zero means no limit.
+type RunLimits struct {
+	ConfirmAbove int `yaml:"confirm_above,omitempty"` // larger runs are confirmed by typing the PR title
+	MaxRepos     int `yaml:"max_repos,omitempty"`     // larger runs need a manifest listing their repos
+}
+
+// Permission modes, see Permissions.
+const (
+	PermissionModePrompt = "prompt"
+	PermissionModePolicy = "policy"
+)
+
+// Permissions decide how the AI tool's permission requests are answered:
+// asked in the dashboard, or, in policy mode, from the rules alone with
+// everything else denied, so runs can go unattended. The rules are checked
+// together with the decisions saved in permissions.yaml in both modes.
+type Permissions struct {
+	Mode  string            `yaml:"mode,omitempty"` // prompt (default) or policy
+	Allow []permission.Rule `yaml:"allow,omitempty"`
+	Deny  []permission.Rule `yaml:"deny,omitempty"`
+}
+
+// Policy returns the configured rules.
+func (p Permissions) Policy() permission.Policy {
+	return permission.Policy{Allow: p.Allow, Deny: p.Deny}
+}
+
+// PRMetadata is put on every PR a run opens, so they can be filtered and
+// counted downstream. The wizard starts from it and can change it per run.
+type PRMetadata struct {
+	Labels    []string `yaml:"labels,omitempty"`    // added to the copycat label
+	Milestone string   `yaml:"milestone,omitempty"` // title of an existing milestone
+}
+
+// DefaultIssueAssignee is who issues are assigned to unless configured
+// otherwise: Copilot's coding agent, which picks them up and opens PRs.
+const DefaultIssueAssignee = "copilot"
+
+// Issues configures the "Create GitHub Issues" workflow. The wizard starts
+// from it and can change it per run.
+type Issues struct {
+	// Assignees are GitHub users, or org/team slugs standing for the team's
+	// members. Unset means copilot; an empty list leaves issues unassigned.
+	Assignees []string `yaml:"assignees"`
+
+	// Templates are offered next to the first repo's own issue templates.
+	Templates []issuetemplate.Template `yaml:"templates,omitempty"`
+}
+
+// MarshalYAML leaves out unset assignees, so they are not saved as an empty
+// list, which would leave issues unassigned.
+func (i Issues) MarshalYAML() (any, error) {
+	type plain Issues
+	if i.Assignees != nil {
+		return plain(i), nil
+	}
+	return struct {
+		Templates []issuetemplate.Template `yaml:"templates,omitempty"`
+	}{i.Templates}, nil
+}
+
+// DefaultAssignees is who issues are assigned to unless changed in the wizard.
+func (i Issues) DefaultAssignees() []string {
+	if i.Assignees == nil {
+		return []string{DefaultIssueAssignee}
+	}
+	return i.Assignees
+}
+
+// PRPacing spaces out the PRs a run opens, so org automation reacting to each
+// PR is not flooded. It only delays PR creation; AI work keeps its parallelism.
+type PRPacing struct {
+	PerMinute     int `yaml:"per_minute,omitempty"`     // PRs opened per minute at most (0 = no limit)
+	JitterSeconds int `yaml:"jitter_seconds,omitempty"` // random extra delay before each PR, up to this
+}
+
+// Interval is the least time between two PRs.
+func (p PRPacing) Interval() time.Duration {
+	if p.PerMinute <= 0 {
+		return 0
+	}
+	return time.Minute / time.Duration(p.PerMinute)
+}
+
+// CIChecks waits, once a run is done, for the status checks on the PRs it
+// opened, so the done screen and Slack show which ones are red.
+type CIChecks struct {
+	TimeoutMinutes int `yaml:"timeout_minutes,omitempty"` // how long to wait (0 = don't wait)
+	PollSeconds    int `yaml:"poll_seconds,omitempty"`    // between checks, 30 by default
+}
+
+// PollInterval is how long to wait between two looks at the checks.
+func (c CIChecks) PollInterval() time.Duration {
+	if c.PollSeconds <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(c.PollSeconds) * time.Second
+}
+
+// DefaultAttempts is how many times a network step is tried when retries
+// don't set it.
+const DefaultAttempts = 3
+
+// Retries sets how many times each network step is tried before a repo fails
+// on a transient error, such as a dropped connection, a 5xx response or a
+// secondary rate limit. Unset steps are tried DefaultAttempts times; 1 turns
+// retries off.
+type Retries struct {
+	Clone int `yaml:"clone,omitempty"`
+	Fetch int `yaml:"fetch,omitempty"`
+	Push  int `yaml:"push,omitempty"`
+	API   int `yaml:"api,omitempty"` // GitHub API requests
+}
+
+// Attempts returns n, or DefaultAttempts when it is unset.
+func (r Retries) Attempts(n int) int {
+	if n <= 0 {
+		return DefaultAttempts
+	}
+	return n
+}
+
+// RateLimit paces GitHub API requests: once a quota is nearly used up, every
+// request waits for it to reset instead of failing mid-run.
+type RateLimit struct {
+	MinRemaining   int `yaml:"min_remaining,omitempty"`    // requests left at which to wait for the reset, 50 by default
+	MaxWaitMinutes int `yaml:"max_wait_minutes,omitempty"` // longest wait for a reset before requests fail, 60 by default
+}
+
+// Floor is the number of requests left below which requests wait.
+func (r RateLimit) Floor() int {
+	if r.MinRemaining <= 0 {
+		return 50
+	}
+	return r.MinRemaining
+}
+
+// MaxWait is the longest requests wait for a quota to reset.
+func (r RateLimit) MaxWait() time.Duration {
+	if r.MaxWaitMinutes <= 0 {
+		return time.Hour
+	}
+	return time.Duration(r.MaxWaitMinutes) * time.Minute
+}
+
+// AdaptiveParallelism lets a run use fewer workers than parallelism while the
+// machine is short of CPU or memory, or while jobs and GitHub requests keep
+// failing, and go back up once things recover.
+type AdaptiveParallelism struct {
+	Enabled         bool    `yaml:"enabled,omitempty"`
+	MaxLoad         float64 `yaml:"max_load,omitempty"`           // 1-minute load average per CPU, 1.5 by default
+	MinFreeMemoryMB int     `yaml:"min_free_memory_mb,omitempty"` // available memory, 1024 by default
+	MaxErrorRate    float64 `yaml:"max_error_rate,omitempty"`     // share of recent jobs and requests failing, 0.5 by default
+}
+
+// LoadLimit is the load average per CPU above which workers are shed.
+func (a AdaptiveParallelism) LoadLimit() float64 {
+	if a.MaxLoad <= 0 {
+		return 1.5
+	}
+	return a.MaxLoad
+}
+
+// MemoryLimit is the available memory, in bytes, below which workers are shed.
+func (a AdaptiveParallelism) MemoryLimit() uint64 {
+	if a.MinFreeMemoryMB <= 0 {
+		return 1024 << 20
+	}
+	return uint64(a.MinFreeMemoryMB) << 20
+}
+
+// ErrorRateLimit is the share of failures above which workers are shed.
+func (a AdaptiveParallelism) ErrorRateLimit() float64 {
+	if a.MaxErrorRate <= 0 {
+		return 0.5
+	}
+	return a.MaxErrorRate
+}
+
+// Commit signing formats, as in git's gpg.format ("gpg" is openpgp).
+const (
+	SigningGPG  = "gpg"
+	SigningSSH  = "ssh"
+	SigningX509 = "x509"
+)
+
+// CommitSigning signs the commits a run pushes, for orgs that require signed
+// commits. Pushes fail rather than send an unsigned commit. When it is not
+// set, git's own configuration decides.
+type CommitSigning struct {
+	Format string `yaml:"format,omitempty"` // gpg (default), ssh or x509
+	Key    string `yaml:"key,omitempty"`    // GPG key ID or SSH key path; git's user.signingkey when empty
+}
+
+// JiraConfig is where follow-up tickets for assessment findings are created.
+// The API token is read from $JIRA_API_TOKEN.
+type JiraConfig struct {
+	BaseURL   string   `yaml:"base_url"`             // e.g. https://acme.atlassian.net
+	Email     string   `yaml:"email"`                // account the API token belongs to
+	Project   string   `yaml:"project"`              // key of the Jira project
+	IssueType string   `yaml:"issue_type,omitempty"` // Task by default
+	Labels    []string `yaml:"labels,omitempty"`
+}
+
+// PolicyReview is an organization's review service that every diff is POSTed
+// to before it is pushed. PRs are only opened for diffs it approves.
+type PolicyReview struct {
+	URL            string `yaml:"url"`
+	TokenEnv       string `yaml:"token_env,omitempty"`       // variable holding a bearer token for the service
+	TimeoutSeconds int    `yaml:"timeout_seconds,omitempty"` // 60 by default
+}
+
+// Timeout returns how long to wait for the service's verdict.
+func (p PolicyReview) Timeout() time.Duration {
+	if p.TimeoutSeconds <= 0 {
+		return time.Minute
+	}
+	return time.Duration(p.TimeoutSeconds) * time.Second
+}
+
+// Webhook receives a JSON event, POSTed as it happens, for each step of a
+// run, so runs can be followed by observability and change management
+// systems.
+type Webhook struct {
+	URL      string   `yaml:"url"`
+	TokenEnv string   `yaml:"token_env,omitempty"` // variable holding a bearer token for the receiver
+	Events   []string `yaml:"events,omitempty"`    // the event types sent; all of them when empty
+}
+
+// Run events a webhook can receive.
+const (
+	EventRunStarted    = "run.started"
+	EventRepoSucceeded = "repo.succeeded"
+	EventRepoFailed    = "repo.failed"
+	EventRepoSkipped   = "repo.skipped"
+	EventRunFinished   = "run.finished"
+)
+
+// Wants reports whether the webhook receives events of type event.
+func (w Webhook) Wants(event string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+// Budget pauses a run each time its reported AI spend passes another LimitUSD.
+type Budget struct {
+	LimitUSD float64 `yaml:"limit_usd"`
+	// ReplaceRepoCheckpoints pauses only for the budget, not after every
+	// batch of repos.
+	ReplaceRepoCheckpoints bool `yaml:"replace_repo_checkpoints,omitempty"`
+}
+
+// Next returns the spend at which the run pauses again, once spent has been
+// reached: the next multiple of the limit.
+func (b Budget) Next(spent float64) float64 {
+	return (math.Floor(spent/b.LimitUSD) + 1) * b.LimitUSD
+}
+
+// PromptTemplate is a named, reusable prompt. Title and Prompt may contain
+// {{variable}} placeholders that the wizard asks for before use.
+type PromptTemplate struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Title       string `yaml:"title,omitempty"` // PR title template
+	Prompt      string `yaml:"prompt"`
+}
+
+// templateVariable matches {{name}} placeholders. Dotted references such as
+// {{repo.value}} are left alone for upstream outputs.
+var templateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// Variables returns the placeholder names used in the template, in order of
+// first appearance.
+func (t PromptTemplate) Variables() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, match := range templateVariable.FindAllStringSubmatch(t.Title+"\n"+t.Prompt, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// Render returns the title and prompt with placeholders replaced by values.
+func (t PromptTemplate) Render(values map[string]string) (title, prompt string) {
+	replace := func(s string) string {
+		return templateVariable.ReplaceAllStringFunc(s, func(match string) string {
+			return values[templateVariable.FindStringSubmatch(match)[1]]
+		})
+	}
+	return replace(t.Title), replace(t.Prompt)
+}
+
+// LicenseHeaderConfig configures the "Add License Headers" action, which adds
+// a header to every source file missing one without running an AI tool.
+type LicenseHeaderConfig struct {
+	Text    string                  `yaml:"text"`              // header text; {{year}} becomes the current year
+	Exclude []string                `yaml:"exclude,omitempty"` // glob patterns of files to leave alone
+	Styles  map[string]CommentStyle `yaml:"styles,omitempty"`  // comment styles by extension (e.g. ".go") or file name
+}
+
+// CommentStyle describes how a header is written as a comment. Line comment
+// styles only set Prefix; block comments also set Start and End.
+type CommentStyle struct {
+	Start  string `yaml:"start,omitempty"`
+	Prefix string `yaml:"prefix"`
+	End    string `yaml:"end,omitempty"`
 }
 
 type AITool struct {
@@ -35,11 +852,103 @@ type AITool struct {
 	AllowedTools             []string `yaml:"allowed_tools,omitempty"`
 	DisallowedTools          []string `yaml:"disallowed_tools,omitempty"`
 	SupportsPermissionPrompt bool     `yaml:"supports_permission_prompt,omitempty"`
+	PermissionAdapter        string   `yaml:"permission_adapter,omitempty"`      // how permission requests reach copycat: mcp (default) or hook
+	PermissionSettingsEnv    string   `yaml:"permission_settings_env,omitempty"` // with the hook adapter, the variable the tool reads the hook's settings file from
+	TimeoutMinutes           int      `yaml:"timeout_minutes,omitempty"`         // overrides the global timeout_minutes
+	Pricing                  *Pricing `yaml:"pricing,omitempty"`                 // estimates cost when the tool only reports tokens
+	ResumeFlag               string   `yaml:"resume_flag,omitempty"`             // continues a session given its id, e.g. --resume
+	Sandbox                  *Sandbox `yaml:"sandbox,omitempty"`                 // runs code changes and assessments in a container
+
+	// An API tool calls a provider's HTTP API instead of running a command.
+	// It can assess repos, summarize, write PR descriptions and review, but
+	// cannot change code.
+	APIProvider string `yaml:"api_provider,omitempty"` // anthropic, openai or ollama
+	Model       string `yaml:"model,omitempty"`        // e.g. claude-sonnet-4-5, gpt-4.1 or llama3.1
+	APIKeyEnv   string `yaml:"api_key_env,omitempty"`  // variable holding the key; the provider's usual one by default
+	APIBaseURL  string `yaml:"api_base_url,omitempty"` // for proxies and compatible servers
+}
+
+// API providers an AI tool can call directly.
+const (
+	APIProviderAnthropic = "anthropic"
+	APIProviderOpenAI    = "openai"
+	// Ollama runs models locally, so nothing leaves the machine
+	APIProviderOllama = "ollama"
+)
+
+// Ways an AI tool can hand its permission requests to copycat.
+const (
+	// The tool asks an MCP server's tool, as Claude's --permission-prompt-tool does
+	PermissionAdapterMCP = "mcp"
+	// The tool runs a command before each of its tool calls, as Gemini's
+	// BeforeTool hooks do, set up in a settings file it is pointed at
+	PermissionAdapterHook = "hook"
+)
+
+// Adapter returns how the tool's permission requests reach copycat.
+func (t *AITool) Adapter() string {
+	if t.PermissionAdapter == "" {
+		return PermissionAdapterMCP
+	}
+	return t.PermissionAdapter
+}
+
+// PermissionEnv returns the environment pointing a hook adapter tool at the
+// permission settings file at path, or nothing for other tools.
+func (t *AITool) PermissionEnv(path string) []string {
+	if !t.SupportsPermissionPrompt || t.Adapter() != PermissionAdapterHook || path == "" {
+		return nil
+	}
+	return []string{t.PermissionSettingsEnv + "=" + path}
+}
+
+// IsAPI reports whether the tool calls an HTTP API rather than a command.
+func (t *AITool) IsAPI() bool {
+	return t.APIProvider != ""
+}
+
+// Describe names what the tool runs: its command, or its provider and model.
+func (t *AITool) Describe() string {
+	if t.IsAPI() {
+		return t.APIProvider + " API, " + t.Model
+	}
+	return t.Command
+}
+
+// APIKeyVar is the environment variable holding the tool's API key, or ""
+// for a local Ollama server, which needs none.
+func (t *AITool) APIKeyVar() string {
+	switch {
+	case t.APIKeyEnv != "":
+		return t.APIKeyEnv
+	case t.APIProvider == APIProviderOpenAI:
+		return "OPENAI_API_KEY"
+	case t.APIProvider == APIProviderOllama:
+		return ""
+	}
+	return "ANTHROPIC_API_KEY"
+}
+
+// Sandbox runs an AI tool in a container with only the repo mounted, so the
+// commands it runs cannot touch the rest of the host.
+type Sandbox struct {
+	Image   string   `yaml:"image"`             // must provide the tool's command
+	Runtime string   `yaml:"runtime,omitempty"` // docker (default) or podman
+	Network string   `yaml:"network,omitempty"` // e.g. none; host when permission prompts are on
+	Env     []string `yaml:"env,omitempty"`     // host variables passed in, e.g. ANTHROPIC_API_KEY
+	Args    []string `yaml:"args,omitempty"`    // extra run arguments, e.g. a mount for the tool's login
+}
+
+// Pricing is what a tool's model costs, in USD per million tokens.
+type Pricing struct {
+	InputPerMillion  float64 `yaml:"input_per_million"`
+	OutputPerMillion float64 `yaml:"output_per_million"`
 }
 
 // CommandOptions holds optional flags for BuildCommand.
 type CommandOptions struct {
 	MCPConfigPath string
+	SessionID     string // session to continue, with the tool's ResumeFlag
 }
 
 func (t *AITool) BuildCommand(prompt string, baseArgs []string, opts ...CommandOptions) *exec.Cmd {
@@ -53,10 +962,13 @@ func (t *AITool) BuildCommand(prompt string, baseArgs []string, opts ...CommandO
 		args = append(args, "--disallowedTools")
 		args = append(args, t.DisallowedTools...)
 	}
-	if t.SupportsPermissionPrompt && len(opts) > 0 && opts[0].MCPConfigPath != "" {
+	if t.SupportsPermissionPrompt && t.Adapter() == PermissionAdapterMCP && len(opts) > 0 && opts[0].MCPConfigPath != "" {
 		args = append(args, "--mcp-config", opts[0].MCPConfigPath)
 		args = append(args, "--permission-prompt-tool", "mcp__copycat-auth__handle_permission")
 	}
+	if t.ResumeFlag != "" && len(opts) > 0 && opts[0].SessionID != "" {
+		args = append(args, t.ResumeFlag, opts[0].SessionID)
+	}
 	return exec.Command(t.Command, args...)
 }
 
@@ -71,10 +983,13 @@ func (t *AITool) BuildCommandContext(ctx context.Context, prompt string, baseArg
 		args = append(args, "--disallowedTools")
 		args = append(args, t.DisallowedTools...)
 	}
-	if t.SupportsPermissionPrompt && len(opts) > 0 && opts[0].MCPConfigPath != "" {
+	if t.SupportsPermissionPrompt && t.Adapter() == PermissionAdapterMCP && len(opts) > 0 && opts[0].MCPConfigPath != "" {
 		args = append(args, "--mcp-config", opts[0].MCPConfigPath)
 		args = append(args, "--permission-prompt-tool", "mcp__copycat-auth__handle_permission")
 	}
+	if t.ResumeFlag != "" && len(opts) > 0 && opts[0].SessionID != "" {
+		args = append(args, t.ResumeFlag, opts[0].SessionID)
+	}
 	return exec.CommandContext(ctx, t.Command, args...)
 }
 
@@ -94,10 +1009,28 @@ func Load(filename string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
 
+	if strings.ContainsAny(cfg.GitHub.Host, "/:@ ") {
+		return nil, fmt.Errorf("github.host %q in %s must be a host name like github.mycorp.com", cfg.GitHub.Host, filename)
+	}
+
 	if cfg.GitHub.Organization == "" {
 		return nil, fmt.Errorf("organization is required in %s", filename)
 	}
 
+	if err := cfg.GitHub.Discovery.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid github.discovery in %s: %w", filename, err)
+	}
+	for _, pattern := range cfg.GitHub.ExcludeRepos {
+		if _, err := path.Match(strings.TrimSpace(pattern), ""); err != nil {
+			return nil, fmt.Errorf("invalid github.exclude_repos pattern %q in %s: %w", pattern, filename, err)
+		}
+	}
+	switch cfg.GitHub.Protocol() {
+	case CloneSSH, CloneHTTPS, CloneGH:
+	default:
+		return nil, fmt.Errorf("invalid github.clone_protocol %q in %s: must be ssh, https or gh", cfg.GitHub.CloneProtocol, filename)
+	}
+
 	if cfg.Parallelism <= 0 {
 		cfg.Parallelism = 3
 	}
@@ -109,17 +1042,48 @@ func Load(filename string) (*Config, error) {
 		return nil, fmt.Errorf("no AI tools defined in %s", filename)
 	}
 
+	if cfg.TimeoutMinutes < 0 {
+		return nil, fmt.Errorf("timeout_minutes in %s must not be negative", filename)
+	}
+
 	toolNames := make(map[string]struct{}, len(cfg.AIToolsConfig.Tools))
 	for _, tool := range cfg.AIToolsConfig.Tools {
 		if tool.Name == "" {
 			return nil, fmt.Errorf("an AI tool in %s is missing a name", filename)
 		}
-		if tool.Command == "" {
-			return nil, fmt.Errorf("AI tool %q is missing a command in %s", tool.Name, filename)
+		switch tool.APIProvider {
+		case "":
+			if tool.Command == "" {
+				return nil, fmt.Errorf("AI tool %q is missing a command in %s", tool.Name, filename)
+			}
+		case APIProviderAnthropic, APIProviderOpenAI, APIProviderOllama:
+			if tool.Model == "" {
+				return nil, fmt.Errorf("AI tool %q calls the %s API without a model in %s", tool.Name, tool.APIProvider, filename)
+			}
+		default:
+			return nil, fmt.Errorf("AI tool %q has unknown api_provider %q in %s (use %s, %s or %s)", tool.Name, tool.APIProvider, filename, APIProviderAnthropic, APIProviderOpenAI, APIProviderOllama)
 		}
 		if _, exists := toolNames[tool.Name]; exists {
 			return nil, fmt.Errorf("duplicate AI tool name %q in %s", tool.Name, filename)
 		}
+		if tool.TimeoutMinutes < 0 {
+			return nil, fmt.Errorf("AI tool %q has a negative timeout_minutes in %s", tool.Name, filename)
+		}
+		if tool.Sandbox != nil && tool.Sandbox.Image == "" {
+			return nil, fmt.Errorf("AI tool %q has a sandbox without an image in %s", tool.Name, filename)
+		}
+		if p := tool.Pricing; p != nil && (p.InputPerMillion < 0 || p.OutputPerMillion < 0) {
+			return nil, fmt.Errorf("AI tool %q has a negative price in %s", tool.Name, filename)
+		}
+		switch tool.PermissionAdapter {
+		case "", PermissionAdapterMCP:
+		case PermissionAdapterHook:
+			if tool.PermissionSettingsEnv == "" {
+				return nil, fmt.Errorf("AI tool %q uses the hook permission adapter without permission_settings_env in %s", tool.Name, filename)
+			}
+		default:
+			return nil, fmt.Errorf("AI tool %q has unknown permission_adapter %q in %s (use %s or %s)", tool.Name, tool.PermissionAdapter, filename, PermissionAdapterMCP, PermissionAdapterHook)
+		}
 		toolNames[tool.Name] = struct{}{}
 	}
 
@@ -129,9 +1093,231 @@ func Load(filename string) (*Config, error) {
 		return nil, fmt.Errorf("default AI tool %q is not defined in %s", cfg.AIToolsConfig.Default, filename)
 	}
 
+	if cfg.ReviewTool != "" {
+		if _, exists := toolNames[cfg.ReviewTool]; !exists {
+			return nil, fmt.Errorf("review tool %q is not defined in %s", cfg.ReviewTool, filename)
+		}
+		// A tool reviewing its own changes is no independent check
+		if cfg.ReviewTool == cfg.AIToolsConfig.Default || slices.Contains(cfg.FallbackTools, cfg.ReviewTool) {
+			return nil, fmt.Errorf("review tool %q also makes the changes, as the default or a fallback tool, in %s; review with another tool", cfg.ReviewTool, filename)
+		}
+	}
+
+	for _, name := range cfg.FallbackTools {
+		tool, exists := cfg.AIToolsConfig.ToolByName(name)
+		if !exists {
+			return nil, fmt.Errorf("fallback tool %q is not defined in %s", name, filename)
+		}
+		if tool.IsAPI() {
+			return nil, fmt.Errorf("fallback tool %q in %s calls an API and cannot change code", name, filename)
+		}
+	}
+
+	if cfg.Budget.LimitUSD < 0 {
+		return nil, fmt.Errorf("budget limit_usd in %s must not be negative", filename)
+	}
+	if cfg.Budget.ReplaceRepoCheckpoints && cfg.Budget.LimitUSD == 0 {
+		return nil, fmt.Errorf("budget replace_repo_checkpoints in %s needs a limit_usd", filename)
+	}
+
+	if cfg.RunLimits.ConfirmAbove < 0 || cfg.RunLimits.MaxRepos < 0 {
+		return nil, fmt.Errorf("run_limits in %s must not be negative", filename)
+	}
+
+	switch cfg.Permissions.Mode {
+	case "", PermissionModePrompt, PermissionModePolicy:
+	default:
+		return nil, fmt.Errorf("permissions mode %q in %s must be prompt or policy", cfg.Permissions.Mode, filename)
+	}
+	for _, rule := range append(slices.Clone(cfg.Permissions.Allow), cfg.Permissions.Deny...) {
+		if strings.TrimSpace(rule.Command) == "" {
+			return nil, fmt.Errorf("every permissions rule in %s needs a command", filename)
+		}
+	}
+
+	if cfg.PRPacing.PerMinute < 0 || cfg.PRPacing.JitterSeconds < 0 {
+		return nil, fmt.Errorf("pr_pacing in %s must not be negative", filename)
+	}
+
+	for _, label := range cfg.PRMetadata.Labels {
+		if strings.TrimSpace(label) == "" || strings.Contains(label, ",") {
+			return nil, fmt.Errorf("pr_metadata label %q in %s must be non-empty and contain no commas", label, filename)
+		}
+	}
+
+	if !i18n.Supported(cfg.Language) {
+		return nil, fmt.Errorf("language %q in %s must be one of %s", cfg.Language, filename, strings.Join(i18n.Languages, ", "))
+	}
+
+	for _, assignee := range cfg.Issues.Assignees {
+		if login := strings.TrimPrefix(strings.TrimSpace(assignee), "@"); login == "" || strings.ContainsAny(login, ", ") {
+			return nil, fmt.Errorf("issues assignee %q in %s must be a GitHub user or org/team slug", assignee, filename)
+		}
+	}
+
+	issueTemplateNames := make(map[string]bool, len(cfg.Issues.Templates))
+	for _, t := range cfg.Issues.Templates {
+		if t.Name == "" || (strings.TrimSpace(t.Body) == "" && len(t.Fields) == 0) {
+			return nil, fmt.Errorf("issue templates in %s need a name and a body", filename)
+		}
+		if issueTemplateNames[t.Name] {
+			return nil, fmt.Errorf("duplicate issue template %q in %s", t.Name, filename)
+		}
+		issueTemplateNames[t.Name] = true
+	}
+
+	switch cfg.CommitSigning.Format {
+	case "", SigningGPG, "openpgp", SigningSSH, SigningX509:
+	default:
+		return nil, fmt.Errorf("commit_signing.format %q in %s must be one of gpg, ssh, x509", cfg.CommitSigning.Format, filename)
+	}
+
+	if cfg.Jira.BaseURL != "" && (cfg.Jira.Email == "" || cfg.Jira.Project == "") {
+		return nil, fmt.Errorf("jira in %s needs an email and a project", filename)
+	}
+
+	if r := cfg.Retries; r.Clone < 0 || r.Fetch < 0 || r.Push < 0 || r.API < 0 {
+		return nil, fmt.Errorf("retries in %s must not be negative", filename)
+	}
+	if r := cfg.RateLimit; r.MinRemaining < 0 || r.MaxWaitMinutes < 0 {
+		return nil, fmt.Errorf("rate_limit in %s must not be negative", filename)
+	}
+	if a := cfg.AdaptiveParallelism; a.MaxLoad < 0 || a.MinFreeMemoryMB < 0 || a.MaxErrorRate < 0 || a.MaxErrorRate > 1 {
+		return nil, fmt.Errorf("adaptive_parallelism in %s must not be negative, and max_error_rate must be at most 1", filename)
+	}
+
+	if policyURL := cfg.PolicyReview.URL; policyURL != "" {
+		if u, err := url.Parse(policyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("policy_review.url %q in %s must be an http or https URL", policyURL, filename)
+		}
+	}
+
+	if hookURL := cfg.Webhook.URL; hookURL != "" {
+		if u, err := url.Parse(hookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("webhook.url %q in %s must be an http or https URL", hookURL, filename)
+		}
+	} else if cfg.Webhook.TokenEnv != "" || len(cfg.Webhook.Events) > 0 {
+		return nil, fmt.Errorf("webhook in %s needs a url", filename)
+	}
+	for _, event := range cfg.Webhook.Events {
+		if !slices.Contains([]string{EventRunStarted, EventRepoSucceeded, EventRepoFailed, EventRepoSkipped, EventRunFinished}, event) {
+			return nil, fmt.Errorf("unknown webhook event %q in %s (use %s, %s, %s, %s or %s)", event, filename, EventRunStarted, EventRepoSucceeded, EventRepoFailed, EventRepoSkipped, EventRunFinished)
+		}
+	}
+
+	if err := cfg.CommitMessage.validate(); err != nil {
+		return nil, fmt.Errorf("%v in %s", err, filename)
+	}
+
+	if err := cfg.PRPolicy.validate(); err != nil {
+		return nil, fmt.Errorf("%v in %s", err, filename)
+	}
+
+	if cfg.CIChecks.TimeoutMinutes < 0 || cfg.CIChecks.PollSeconds < 0 {
+		return nil, fmt.Errorf("ci_checks in %s must not be negative", filename)
+	}
+
+	for _, q := range cfg.Slack.QuietHours {
+		if _, _, _, err := q.parse(); err != nil {
+			return nil, fmt.Errorf("slack quiet_hours in %s: %v", filename, err)
+		}
+	}
+	if err := cfg.Slack.Messages.validate(); err != nil {
+		return nil, fmt.Errorf("%v in %s", err, filename)
+	}
+	if approvals := cfg.Slack.Approvals; approvals != (SlackApprovals{}) {
+		if approvals.Channel == "" {
+			return nil, fmt.Errorf("slack approvals in %s needs a channel", filename)
+		}
+		if _, _, err := net.SplitHostPort(approvals.Addr()); err != nil {
+			return nil, fmt.Errorf("slack approvals listen_addr %q in %s must be host:port: %v", approvals.ListenAddr, filename, err)
+		}
+	}
+
+	checkNames := make(map[string]bool, len(cfg.HealthChecks))
+	for _, h := range cfg.HealthChecks {
+		if h.Name == "" || strings.TrimSpace(h.Question) == "" || h.SlackRoom == "" {
+			return nil, fmt.Errorf("health checks in %s need a name, a question and a slack_room", filename)
+		}
+		if checkNames[h.Name] {
+			return nil, fmt.Errorf("duplicate health check %q in %s", h.Name, filename)
+		}
+		checkNames[h.Name] = true
+		if h.Tool != "" {
+			if _, exists := toolNames[h.Tool]; !exists {
+				return nil, fmt.Errorf("health check %q uses tool %q, which is not defined in %s", h.Name, h.Tool, filename)
+			}
+		}
+		if h.Profile != "" {
+			if _, exists := cfg.Profiles[h.Profile]; !exists {
+				return nil, fmt.Errorf("health check %q uses profile %q, which is not defined in %s", h.Name, h.Profile, filename)
+			}
+		}
+		switch h.Every {
+		case "", HealthCheckDaily, HealthCheckWeekly:
+		default:
+			return nil, fmt.Errorf("health check %q in %s must run daily or weekly, not %q", h.Name, filename, h.Every)
+		}
+	}
+
+	promptNames := make(map[string]bool, len(cfg.Prompts))
+	for _, p := range cfg.Prompts {
+		if p.Name == "" || strings.TrimSpace(p.Prompt) == "" {
+			return nil, fmt.Errorf("prompt templates in %s need a name and a prompt", filename)
+		}
+		if promptNames[p.Name] {
+			return nil, fmt.Errorf("duplicate prompt template %q in %s", p.Name, filename)
+		}
+		promptNames[p.Name] = true
+	}
+
+	if err := validateActions(cfg.Actions, filename); err != nil {
+		return nil, err
+	}
+
+	for _, name := range cfg.Verify {
+		if !knownVerifications[name] {
+			return nil, fmt.Errorf("unknown verification %q in %s", name, filename)
+		}
+	}
+
+	switch cfg.EmptyRepos {
+	case "", EmptyReposSkip, EmptyReposScaffold:
+	default:
+		return nil, fmt.Errorf("empty_repos %q in %s must be one of skip, scaffold", cfg.EmptyRepos, filename)
+	}
+
+	switch cfg.TrivialChanges.Action {
+	case "", TrivialChangesFlag, TrivialChangesSkip, TrivialChangesAllow:
+	default:
+		return nil, fmt.Errorf("trivial_changes.action %q in %s must be one of flag, skip, allow", cfg.TrivialChanges.Action, filename)
+	}
+
 	return &cfg, nil
 }
 
+// ToolTimeout is how long tool may run on a single repo: its own
+// timeout_minutes, or the global one. Zero means no limit.
+func (c *Config) ToolTimeout(tool *AITool) time.Duration {
+	minutes := c.TimeoutMinutes
+	if tool != nil && tool.TimeoutMinutes > 0 {
+		minutes = tool.TimeoutMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// ToolChain returns primary followed by the fallback tools, in order, that
+// are not primary itself.
+func (c *Config) ToolChain(primary *AITool) []*AITool {
+	chain := []*AITool{primary}
+	for _, name := range c.FallbackTools {
+		if tool, ok := c.ToolByName(name); ok && tool.Name != primary.Name {
+			chain = append(chain, tool)
+		}
+	}
+	return chain
+}
+
 func (c *AIToolsConfig) ToolByName(name string) (*AITool, bool) {
 	for i := range c.Tools {
 		if c.Tools[i].Name == name {
@@ -150,12 +1336,62 @@ func (c *Config) Save(filename string) error {
 		return fmt.Errorf("failed to encode github config: %w", err)
 	}
 
-	var agentData []byte
-	if len(c.AgentInstructions) > 0 {
-		agentData, err = yaml.Marshal(map[string][]string{"agent_instructions": c.AgentInstructions})
+	// Optional sections are only written when set
+	optional := []struct {
+		key   string
+		value any
+		set   bool
+	}{
+		{"parallelism", c.Parallelism, c.Parallelism > 0},
+		{"default", c.Default, c.Default != ""},
+		{"agent_instructions", c.AgentInstructions, len(c.AgentInstructions) > 0},
+		{"protected_paths", c.ProtectedPaths, len(c.ProtectedPaths) > 0},
+		{"allow_line_ending_changes", c.AllowLineEndingChanges, c.AllowLineEndingChanges},
+		{"review_tool", c.ReviewTool, c.ReviewTool != ""},
+		{"trivial_changes", c.TrivialChanges, c.TrivialChanges.Action != "" || len(c.TrivialChanges.CommentPrefixes) > 0},
+		{"license_header", c.LicenseHeader, c.LicenseHeader.Text != ""},
+		{"prompts", c.Prompts, len(c.Prompts) > 0},
+		{"actions", c.Actions, len(c.Actions) > 0},
+		{"verify", c.Verify, len(c.Verify) > 0},
+		{"hooks", c.Hooks, c.Hooks != (Hooks{})},
+		{"verify_command", c.VerifyCommand, c.VerifyCommand != ""},
+		{"flaky_test_runs", c.FlakyTestRuns, c.FlakyTestRuns > 0},
+		{"timeout_minutes", c.TimeoutMinutes, c.TimeoutMinutes > 0},
+		{"fallback_tools", c.FallbackTools, len(c.FallbackTools) > 0},
+		{"health_checks", c.HealthChecks, len(c.HealthChecks) > 0},
+		{"budget", c.Budget, c.Budget != (Budget{})},
+		{"run_limits", c.RunLimits, c.RunLimits != (RunLimits{})},
+		{"pr_pacing", c.PRPacing, c.PRPacing != (PRPacing{})},
+		{"empty_repos", c.EmptyRepos, c.EmptyRepos != ""},
+		{"pr_metadata", c.PRMetadata, len(c.PRMetadata.Labels) > 0 || c.PRMetadata.Milestone != ""},
+		{"pr_policy", c.PRPolicy, c.PRPolicy.TitlePattern != "" || c.PRPolicy.MaxTitleLength != 0 || len(c.PRPolicy.RequiredTags) > 0 || c.PRPolicy.DescriptionPattern != ""},
+		{"issues", c.Issues, c.Issues.Assignees != nil || len(c.Issues.Templates) > 0},
+		{"permissions", c.Permissions, c.Permissions.Mode != "" || len(c.Permissions.Allow) > 0 || len(c.Permissions.Deny) > 0},
+		{"ci_checks", c.CIChecks, c.CIChecks != (CIChecks{})},
+		{"commit_signing", c.CommitSigning, c.CommitSigning != (CommitSigning{})},
+		{"commit_message", c.CommitMessage, c.CommitMessage.Subject != "" || c.CommitMessage.Body != "" || len(c.CommitMessage.Trailers) > 0},
+		{"consistency_paths", c.ConsistencyPaths, len(c.ConsistencyPaths) > 0},
+		{"profiles", c.Profiles, len(c.Profiles) > 0},
+		{"jira", c.Jira, c.Jira.BaseURL != ""},
+		{"policy_review", c.PolicyReview, c.PolicyReview.URL != ""},
+		{"webhook", c.Webhook, c.Webhook.URL != ""},
+		{"retries", c.Retries, c.Retries != (Retries{})},
+		{"rate_limit", c.RateLimit, c.RateLimit != (RateLimit{})},
+		{"adaptive_parallelism", c.AdaptiveParallelism, c.AdaptiveParallelism != (AdaptiveParallelism{})},
+		{"language", c.Language, c.Language != ""},
+		{"slack", c.Slack, c.Slack.ClientID != "" || c.Slack.ClientSecret != "" || c.Slack.RedirectPort != 0 || len(c.Slack.QuietHours) > 0 || c.Slack.Approvals != (SlackApprovals{}) || c.Slack.Messages != (SlackMessages{}) || c.Slack.RunChannel != ""},
+	}
+
+	var optionalData []string
+	for _, section := range optional {
+		if !section.set {
+			continue
+		}
+		sectionData, err := yaml.Marshal(map[string]any{section.key: section.value})
 		if err != nil {
-			return fmt.Errorf("failed to encode agent_instructions config: %w", err)
+			return fmt.Errorf("failed to encode %s config: %w", section.key, err)
 		}
+		optionalData = append(optionalData, string(sectionData))
 	}
 
 	toolsData, err := yaml.Marshal(map[string][]AITool{"tools": c.Tools})
@@ -165,8 +1401,8 @@ func (c *Config) Save(filename string) error {
 
 	// Combine with blank lines between sections
 	data := string(githubData) + "\n"
-	if len(agentData) > 0 {
-		data += string(agentData) + "\n"
+	for _, sectionData := range optionalData {
+		data += sectionData + "\n"
 	}
 	data += string(toolsData)
 
@@ -191,6 +1427,33 @@ func LoadProjects(filename string) ([]Project, error) {
 		return nil, fmt.Errorf("failed to parse projects file %s: %w", filename, err)
 	}
 
+	paths := make(map[string]bool)
+	for _, p := range wrapper.Projects {
+		if p.Path != "" {
+			if len(p.Services) > 0 {
+				return nil, fmt.Errorf("%s in %s sets both a path and services", p.Repo, filename)
+			}
+			if dir := strings.Trim(p.Path, "/"); dir == "" || strings.HasPrefix(dir, "..") {
+				return nil, fmt.Errorf("path %q of %s in %s must be a directory within the repo", p.Path, p.Repo, filename)
+			}
+			key := p.Repo + "/" + strings.Trim(p.Path, "/")
+			if paths[key] {
+				return nil, fmt.Errorf("duplicate path %q of %s in %s", p.Path, p.Repo, filename)
+			}
+			paths[key] = true
+		}
+		names := make(map[string]bool, len(p.Services))
+		for _, s := range p.Services {
+			if s.Name == "" || s.Path == "" {
+				return nil, fmt.Errorf("services of %s in %s need a name and a path", p.Repo, filename)
+			}
+			if names[s.Name] {
+				return nil, fmt.Errorf("duplicate service %q in %s in %s", s.Name, p.Repo, filename)
+			}
+			names[s.Name] = true
+		}
+	}
+
 	return wrapper.Projects, nil
 }
 
@@ -1,9 +1,15 @@
 package config
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -29,6 +35,47 @@ func TestLoadConfig(t *testing.T) {
 	}
 }
 
+func TestSaveRoundTrip(t *testing.T) {
+	cfg, err := Load("../../config.yaml")
+	if err != nil {
+		t.Fatalf("failed to load config.yaml: %v", err)
+	}
+	cfg.Parallelism = 7
+	cfg.Default = cfg.Tools[len(cfg.Tools)-1].Name
+	cfg.Profiles = map[string][]string{"payments": {"svc-a", "svc-b"}}
+	cfg.TrivialChanges = TrivialChangesConfig{Action: TrivialChangesSkip, CommentPrefixes: []string{"REM"}}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to reload saved config: %v", err)
+	}
+	if loaded.Parallelism != 7 {
+		t.Errorf("expected parallelism 7, got %d", loaded.Parallelism)
+	}
+	if loaded.Default != cfg.Default {
+		t.Errorf("expected default %q, got %q", cfg.Default, loaded.Default)
+	}
+	if repos := loaded.Profiles["payments"]; len(repos) != 2 || repos[1] != "svc-b" {
+		t.Errorf("expected payments profile to round-trip, got %v", loaded.Profiles)
+	}
+	if loaded.TrivialChanges.Action != TrivialChangesSkip || len(loaded.TrivialChanges.CommentPrefixes) != 1 {
+		t.Errorf("expected trivial_changes to round-trip, got %+v", loaded.TrivialChanges)
+	}
+
+	loaded.TrivialChanges.Action = "ignore"
+	if err := loaded.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected an invalid trivial_changes action to be rejected")
+	}
+}
+
 func TestLoadProjects(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "projects.yaml")
@@ -122,3 +169,891 @@ func TestSaveProjectsEmpty(t *testing.T) {
 		t.Fatalf("expected 0 projects, got %d", len(loaded))
 	}
 }
+
+func TestExpandTargetBranches(t *testing.T) {
+	projects := []Project{
+		{Repo: "lib", TargetBranches: []string{"main", "release/1.x"}},
+		{Repo: "app"},
+	}
+
+	expanded := ExpandTargetBranches(projects)
+	var keys []string
+	for _, p := range expanded {
+		keys = append(keys, p.Key())
+	}
+
+	expected := []string{"lib@main", "lib@release/1.x", "app"}
+	if len(keys) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, keys)
+	}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Errorf("key %d: expected %q, got %q", i, expected[i], keys[i])
+		}
+	}
+
+	// Expanding twice must not multiply jobs
+	if again := ExpandTargetBranches(expanded); len(again) != 3 {
+		t.Errorf("expected expansion to be idempotent, got %d projects", len(again))
+	}
+}
+
+func TestExpandServices(t *testing.T) {
+	projects := []Project{
+		{
+			Repo:        "platform",
+			SlackRoom:   "#platform",
+			PromptNotes: "Uses Bazel.",
+			Services: []Service{
+				{Name: "payments", Path: "services/payments", SlackRoom: "#payments"},
+				{Name: "ledger", Path: "services/ledger/", PromptNotes: "Ledger is Kotlin."},
+			},
+		},
+		{Repo: "app"},
+		{Repo: "mono", Path: "apps/web/"},
+		{Repo: "mono", Path: "apps/admin"},
+	}
+
+	expanded := ExpandServices(projects)
+	var keys []string
+	for _, p := range expanded {
+		keys = append(keys, p.Key())
+	}
+	if got := strings.Join(keys, ","); got != "platform/payments,platform/ledger,app,mono/apps/web,mono/apps/admin" {
+		t.Fatalf("unexpected keys: %s", got)
+	}
+
+	payments, ledger := expanded[0], expanded[1]
+	if payments.SlackRoom != "#payments" || ledger.SlackRoom != "#platform" {
+		t.Errorf("expected service rooms to override the repo's, got %q and %q", payments.SlackRoom, ledger.SlackRoom)
+	}
+	if ledger.PromptNotes != "Uses Bazel.\nLedger is Kotlin." {
+		t.Errorf("unexpected prompt notes: %q", ledger.PromptNotes)
+	}
+	if prompt := ledger.WithPromptNotes("Bump deps"); !strings.Contains(prompt, "Only work on the ledger service in services/ledger/") {
+		t.Errorf("expected the prompt to be scoped to the service, got %q", prompt)
+	}
+
+	if prompt := expanded[3].WithPromptNotes("Bump deps"); !strings.Contains(prompt, "Only work in apps/web/ and leave the rest") {
+		t.Errorf("expected the prompt to be scoped to the path, got %q", prompt)
+	}
+
+	branch := payments
+	branch.TargetBranch = "release/1.x"
+	if branch.Key() != "platform/payments@release/1.x" {
+		t.Errorf("unexpected key %q", branch.Key())
+	}
+
+	if again := ExpandServices(expanded); len(again) != 5 {
+		t.Errorf("expected expansion to be idempotent, got %d projects", len(again))
+	}
+}
+
+func TestLoadProjectsRejectsInvalidServices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "projects.yaml")
+	data := "projects:\n  - repo: platform\n    services:\n      - name: payments\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProjects(path); err == nil {
+		t.Error("expected a service without a path to be rejected")
+	}
+
+	data = "projects:\n  - repo: mono\n    path: apps/web\n  - repo: mono\n    path: apps/web/\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProjects(path); err == nil {
+		t.Error("expected a repo path listed twice to be rejected")
+	}
+}
+
+func TestPromptTemplate(t *testing.T) {
+	tmpl := PromptTemplate{
+		Name:   "bump-go",
+		Title:  "Bump Go to {{version}}",
+		Prompt: "Update go.mod to Go {{ version }} and fix {{lint_tool}} findings. Use {{shared-lib.version}}.",
+	}
+
+	vars := tmpl.Variables()
+	if len(vars) != 2 || vars[0] != "version" || vars[1] != "lint_tool" {
+		t.Fatalf("unexpected variables: %v", vars)
+	}
+
+	title, prompt := tmpl.Render(map[string]string{"version": "1.25", "lint_tool": "golangci-lint"})
+	if title != "Bump Go to 1.25" {
+		t.Errorf("unexpected title: %q", title)
+	}
+	if prompt != "Update go.mod to Go 1.25 and fix golangci-lint findings. Use {{shared-lib.version}}." {
+		t.Errorf("unexpected prompt: %q", prompt)
+	}
+}
+
+func TestWithPromptNotes(t *testing.T) {
+	if got := (Project{Repo: "a"}).WithPromptNotes("Bump deps"); got != "Bump deps" {
+		t.Errorf("expected prompt unchanged without notes, got %q", got)
+	}
+
+	p := Project{Repo: "a", PromptNotes: "  This repo uses Gradle, not Maven.\n"}
+	want := "Bump deps\n\nNotes for this repository:\nThis repo uses Gradle, not Maven."
+	if got := p.WithPromptNotes("Bump deps"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestToolChain(t *testing.T) {
+	cfg := Config{
+		FallbackTools: []string{"claude", "codex", "gemini"},
+		AIToolsConfig: AIToolsConfig{Tools: []AITool{{Name: "claude"}, {Name: "codex"}, {Name: "gemini"}}},
+	}
+	var names []string
+	for _, tool := range cfg.ToolChain(&cfg.Tools[1]) {
+		names = append(names, tool.Name)
+	}
+	if got := strings.Join(names, ","); got != "codex,claude,gemini" {
+		t.Errorf("ToolChain() = %s, want codex,claude,gemini", got)
+	}
+}
+
+func TestToolTimeout(t *testing.T) {
+	cfg := Config{TimeoutMinutes: 30}
+	if got := cfg.ToolTimeout(&AITool{Name: "claude"}); got != 30*time.Minute {
+		t.Errorf("expected the global timeout, got %s", got)
+	}
+	if got := cfg.ToolTimeout(&AITool{Name: "codex", TimeoutMinutes: 90}); got != 90*time.Minute {
+		t.Errorf("expected the tool's own timeout, got %s", got)
+	}
+	if got := (&Config{}).ToolTimeout(&AITool{Name: "claude"}); got != 0 {
+		t.Errorf("expected no timeout by default, got %s", got)
+	}
+}
+
+func TestBudgetNext(t *testing.T) {
+	budget := Budget{LimitUSD: 20}
+	for spent, want := range map[float64]float64{0: 20, 21.3: 40, 40: 60} {
+		if got := budget.Next(spent); got != want {
+			t.Errorf("Next(%v) = %v, want %v", spent, got, want)
+		}
+	}
+}
+
+func TestHooksFor(t *testing.T) {
+	cfg := Config{Hooks: Hooks{Pre: "make generate", Post: "make test"}}
+	if got := cfg.HooksFor(Project{Repo: "payments-api"}); got != cfg.Hooks {
+		t.Errorf("expected the configured hooks, got %+v", got)
+	}
+	got := cfg.HooksFor(Project{Repo: "web", Hooks: &Hooks{Post: "npm test"}})
+	if want := (Hooks{Pre: "make generate", Post: "npm test"}); got != want {
+		t.Errorf("HooksFor = %+v, want %+v", got, want)
+	}
+}
+
+func TestBuildCommandContextResumesSession(t *testing.T) {
+	tool := &AITool{Name: "claude", Command: "claude", ResumeFlag: "--resume"}
+
+	cmd := tool.BuildCommandContext(context.Background(), "fix it", []string{"--print"}, CommandOptions{SessionID: "9f1c2a"})
+	if got := strings.Join(cmd.Args[1:], " "); got != "--print fix it --resume 9f1c2a" {
+		t.Errorf("args = %q", got)
+	}
+	cmd = tool.BuildCommandContext(context.Background(), "fix it", []string{"--print"})
+	if got := strings.Join(cmd.Args[1:], " "); got != "--print fix it" {
+		t.Errorf("args without a session = %q", got)
+	}
+}
+
+func TestLoadActions(t *testing.T) {
+	load := func(actions string) (*Config, error) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		data := "github:\n  organization: acme\ntools:\n  - name: claude\n    command: claude\nactions:\n" + actions
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return Load(path)
+	}
+
+	cfg, err := load("  - name: node-20\n    steps:\n      - type: set_key\n        files: [package.json]\n        key: engines.node\n        value: \">=20\"\n")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if p, ok := cfg.ActionPipeline("node-20"); !ok || p.Steps[0].Value != ">=20" {
+		t.Errorf("unexpected pipeline %+v", p)
+	}
+
+	for name, actions := range map[string]string{
+		"no steps":     "  - name: empty\n",
+		"unknown type": "  - name: x\n    steps:\n      - type: rename\n",
+		"bad regex":    "  - name: x\n    steps:\n      - type: regex_replace\n        files: [\"*.go\"]\n        search: \"(\"\n",
+		"escaping add": "  - name: x\n    steps:\n      - type: add_file\n        path: ../outside\n",
+		"set on go":    "  - name: x\n    steps:\n      - type: set_key\n        files: [go.mod]\n        key: go\n        value: 1\n",
+	} {
+		if _, err := load(actions); err == nil {
+			t.Errorf("%s: expected the config to be rejected", name)
+		}
+	}
+}
+
+func TestQuietUntil(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	if err != nil {
+		t.Skip("no timezone data")
+	}
+	cfg := SlackConfig{QuietHours: []QuietHours{
+		{Channels: []string{"#team-payments"}, Timezone: "Europe/Lisbon", Start: "19:00", End: "08:00", Weekends: true},
+		{Channels: []string{"team-ledger"}, Timezone: "Europe/Lisbon", Start: "12:00", End: "13:00"},
+	}}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.October, day, hour, minute, 0, 0, lisbon) // the 14th is a Wednesday
+	}
+
+	tests := []struct {
+		channel string
+		t       time.Time
+		want    time.Time
+	}{
+		{"#team-payments", at(14, 15, 0), time.Time{}},
+		{"#team-payments", at(14, 3, 0), at(14, 8, 0)},
+		{"#team-payments", at(14, 22, 30), at(15, 8, 0)},
+		{"#team-payments", at(16, 20, 0), at(19, 8, 0)}, // Friday night waits for Monday morning
+		{"#team-payments", at(17, 11, 0), at(19, 8, 0)},
+		{"#team-ledger", at(14, 12, 15), at(14, 13, 0)},
+		{"#team-ledger", at(14, 3, 0), time.Time{}},
+		{"#team-web", at(14, 3, 0), time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := cfg.QuietUntil(tt.channel, tt.t); !got.Equal(tt.want) {
+			t.Errorf("%s at %s: got %s, want %s", tt.channel, tt.t.Format("Mon 15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestLoadRunLimits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte("github:\n  organization: acme\ntools:\n  - name: claude\n    command: claude\n"+data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("run_limits:\n  confirm_above: 50\n  max_repos: 200\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.RunLimits != (RunLimits{ConfirmAbove: 50, MaxRepos: 200}) {
+		t.Errorf("unexpected run limits %+v", cfg.RunLimits)
+	}
+
+	write("run_limits:\n  max_repos: -1\n")
+	if _, err := Load(path); err == nil {
+		t.Error("expected a negative max_repos to be rejected")
+	}
+}
+
+func TestLoadPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte("github:\n  organization: acme\ntools:\n  - name: claude\n    command: claude\n"+data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("permissions:\n  mode: policy\n  allow:\n    - command: ./mvnw test *\n  deny:\n    - command: curl *\n      repo: payments-api\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	policy := cfg.Permissions.Policy()
+	if cfg.Permissions.Mode != PermissionModePolicy || len(policy.Allow) != 1 || policy.Deny[0].Repo != "payments-api" {
+		t.Errorf("unexpected permissions %+v", cfg.Permissions)
+	}
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if saved, err := Load(path); err != nil || saved.Permissions.Mode != PermissionModePolicy || len(saved.Permissions.Deny) != 1 {
+		t.Errorf("expected the permissions to be saved, got %+v (%v)", saved.Permissions, err)
+	}
+
+	write("permissions:\n  mode: yolo\n")
+	if _, err := Load(path); err == nil {
+		t.Error("expected an unknown mode to be rejected")
+	}
+	write("permissions:\n  allow:\n    - repo: ledger-service\n")
+	if _, err := Load(path); err == nil {
+		t.Error("expected a rule without a command to be rejected")
+	}
+}
+
+func TestLoadSlackApprovals(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte("github:\n  organization: acme\ntools:\n  - name: claude\n    command: claude\n"+data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("slack:\n  approvals:\n    channel: '#copycat-approvals'\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if approvals := cfg.Slack.Approvals; approvals.Addr() != DefaultApprovalsAddr || approvals.SecretEnv() != "SLACK_SIGNING_SECRET" {
+		t.Errorf("unexpected defaults %+v", approvals)
+	}
+
+	write("slack:\n  approvals:\n    listen_addr: ':3001'\n")
+	if _, err := Load(path); err == nil {
+		t.Error("expected approvals without a channel to be rejected")
+	}
+	write("slack:\n  approvals:\n    channel: '#copycat-approvals'\n    listen_addr: '3001'\n")
+	if _, err := Load(path); err == nil {
+		t.Error("expected a listen_addr without a host:port to be rejected")
+	}
+}
+
+func TestLoadSlackMessages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte("github:\n  organization: acme\ntools:\n  - name: claude\n    command: claude\n"+data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("slack:\n  messages:\n    prs: |\n      *{{.Title}}* by {{.Author}}\n      {{range .Repos}}- <{{.URL}}|{{.Repo}}>\n      {{end}}\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	message, err := RenderSlackMessage("prs", cfg.Slack.Messages.PRs, SlackMessageData{
+		Title:  "Bump Go to 1.23",
+		Author: "jane",
+		Repos:  []SlackMessageRepo{{Repo: "payments-api", URL: "https://github.com/acme/payments-api/pull/7"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "*Bump Go to 1.23* by jane\n- <https://github.com/acme/payments-api/pull/7|payments-api>\n\n"; message != want {
+		t.Errorf("message = %q, want %q", message, want)
+	}
+
+	write("slack:\n  messages:\n    issues: '{{.Titel}}'\n")
+	if _, err := Load(path); err == nil {
+		t.Error("expected a template referring to a missing field to be rejected")
+	}
+	write("slack:\n  messages:\n    prs: '{{range .Repos}}'\n")
+	if _, err := Load(path); err == nil {
+		t.Error("expected a template that doesn't parse to be rejected")
+	}
+}
+
+func TestLoadWebhook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte("github:\n  organization: acme\ntools:\n  - name: claude\n    command: claude\n"+data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("webhook:\n  url: https://events.acme.internal/copycat\n  events: [run.started, run.finished]\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Webhook.Wants(EventRunFinished) || cfg.Webhook.Wants(EventRepoFailed) {
+		t.Errorf("unexpected events %v", cfg.Webhook.Events)
+	}
+
+	for name, data := range map[string]string{
+		"no url":        "webhook:\n  token_env: HOOK_TOKEN\n",
+		"bad url":       "webhook:\n  url: events.acme.internal\n",
+		"unknown event": "webhook:\n  url: https://events.acme.internal/copycat\n  events: [repo.merged]\n",
+	} {
+		write(data)
+		if _, err := Load(path); err == nil {
+			t.Errorf("%s: expected the webhook to be rejected", name)
+		}
+	}
+}
+
+func TestLoadReviewTool(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		tools := "tools:\n  - name: claude\n    command: claude\n  - name: codex\n    command: codex\n  - name: gemini\n    command: gemini\n"
+		if err := os.WriteFile(path, []byte("github:\n  organization: acme\n"+tools+data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("default: claude\nfallback_tools: [gemini]\nreview_tool: codex\n")
+	if cfg, err := Load(path); err != nil || cfg.ReviewTool != "codex" {
+		t.Fatalf("expected a review tool of its own to load, got %v", err)
+	}
+
+	for name, data := range map[string]string{
+		"default tool":  "default: codex\nreview_tool: codex\n",
+		"first tool":    "review_tool: claude\n",
+		"fallback tool": "default: claude\nfallback_tools: [codex]\nreview_tool: codex\n",
+	} {
+		write(data)
+		if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "also makes the changes") {
+			t.Errorf("%s: expected a review tool making the changes to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestLoadIssueAssignees(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte("github:\n  organization: acme\ntools:\n  - name: claude\n    command: claude\n"+data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		data string
+		want []string
+	}{
+		{"", []string{"copilot"}},
+		{"issues:\n  assignees: [jane, acme/payments]\n", []string{"jane", "acme/payments"}},
+		{"issues:\n  assignees: []\n", []string{}},
+	} {
+		write(tc.data)
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%q): %v", tc.data, err)
+		}
+		if got := cfg.Issues.DefaultAssignees(); !slices.Equal(got, tc.want) {
+			t.Errorf("Load(%q) assignees = %v, want %v", tc.data, got, tc.want)
+		}
+
+		// an empty list survives a save, so issues stay unassigned
+		if err := cfg.Save(path); err != nil {
+			t.Fatal(err)
+		}
+		if saved, err := Load(path); err != nil || !slices.Equal(saved.Issues.DefaultAssignees(), tc.want) {
+			t.Errorf("after saving %q, assignees = %v (%v), want %v", tc.data, saved.Issues.DefaultAssignees(), err, tc.want)
+		}
+	}
+
+	write("issues:\n  assignees: [\"jane, joe\"]\n")
+	if _, err := Load(path); err == nil {
+		t.Error("expected an assignee with a comma to be rejected")
+	}
+}
+
+func TestLoadIssueTemplates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte("github:\n  organization: acme\ntools:\n  - name: claude\n    command: claude\n"+data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`issues:
+  templates:
+    - name: Upgrade
+      title: "[upgrade] "
+      labels: dependencies
+      body: Upgrade the runtime.
+    - name: Bump
+      body:
+        - type: input
+          attributes:
+            label: Package
+          validations:
+            required: true
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	templates := cfg.Issues.Templates
+	if len(templates) != 2 || templates[0].Body != "Upgrade the runtime." || !slices.Equal(templates[0].Labels, []string{"dependencies"}) || len(templates[1].Fields) != 1 {
+		t.Fatalf("unexpected templates %+v", templates)
+	}
+
+	// saving the templates keeps the assignees unset, so copilot
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Issues.Templates) != 2 || !saved.Issues.Templates[1].Fields[0].Required || !slices.Equal(saved.Issues.DefaultAssignees(), []string{"copilot"}) {
+		t.Errorf("after saving, got templates %+v and assignees %v", saved.Issues.Templates, saved.Issues.DefaultAssignees())
+	}
+
+	write("issues:\n  templates:\n    - name: Upgrade\n      body: a\n    - name: Upgrade\n      body: b\n")
+	if _, err := Load(path); err == nil {
+		t.Error("expected duplicate template names to be rejected")
+	}
+}
+
+func TestLoadLanguage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte("github:\n  organization: acme\ntools:\n  - name: claude\n    command: claude\n"+data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("language: pt-PT\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Language != "pt-PT" {
+		t.Errorf("expected pt-PT, got %q", cfg.Language)
+	}
+
+	write("language: fr\n")
+	if _, err := Load(path); err == nil {
+		t.Error("expected an unsupported language to be rejected")
+	}
+}
+
+func TestLoadAPITools(t *testing.T) {
+	load := func(tools string) (*Config, error) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		data := "github:\n  organization: acme\ntools:\n" + tools
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return Load(path)
+	}
+
+	cfg, err := load("  - name: claude-api\n    api_provider: anthropic\n    model: claude-sonnet-4-5\n")
+	if err != nil {
+		t.Fatalf("expected an API tool without a command to load: %v", err)
+	}
+	tool := cfg.AIToolsConfig.Tools[0]
+	if !tool.IsAPI() || tool.APIKeyVar() != "ANTHROPIC_API_KEY" || tool.Describe() != "anthropic API, claude-sonnet-4-5" {
+		t.Errorf("unexpected API tool %+v", tool)
+	}
+
+	for name, tools := range map[string]string{
+		"no model":         "  - name: gpt\n    api_provider: openai\n",
+		"unknown provider": "  - name: gemini\n    api_provider: gemini\n    model: gemini-2.5-pro\n",
+		"api fallback": "  - name: claude\n    command: claude\n  - name: gpt\n    api_provider: openai\n    model: gpt-4.1\n" +
+			"fallback_tools: [gpt]\n",
+	} {
+		if _, err := load(tools); err == nil {
+			t.Errorf("%s: expected the config to be rejected", name)
+		}
+	}
+}
+
+func TestLoadPermissionAdapter(t *testing.T) {
+	load := func(tool string) (*Config, error) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		data := "github:\n  organization: acme\ntools:\n  - name: gemini\n    command: gemini\n    supports_permission_prompt: true\n" + tool
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return Load(path)
+	}
+
+	cfg, err := load("    permission_adapter: hook\n    permission_settings_env: GEMINI_CLI_SYSTEM_SETTINGS_PATH\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tool := cfg.AIToolsConfig.Tools[0]
+	if env := tool.PermissionEnv("/tmp/hooks.json"); !slices.Equal(env, []string{"GEMINI_CLI_SYSTEM_SETTINGS_PATH=/tmp/hooks.json"}) {
+		t.Errorf("PermissionEnv = %v", env)
+	}
+	cmd := tool.BuildCommandContext(context.Background(), "fix it", nil, CommandOptions{MCPConfigPath: "/tmp/hooks.json"})
+	if slices.Contains(cmd.Args, "--permission-prompt-tool") {
+		t.Errorf("expected no MCP flags for a hook adapter tool, got %v", cmd.Args)
+	}
+
+	for name, tool := range map[string]string{
+		"hook without settings env": "    permission_adapter: hook\n",
+		"unknown adapter":           "    permission_adapter: acp\n",
+	} {
+		if _, err := load(tool); err == nil {
+			t.Errorf("%s: expected the config to be rejected", name)
+		}
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"repos.txt":    "# checkout team\npayments-api\n\nledger-service@main\n",
+		"rfc-42.md":    "# RFC 42\n\nUse 30s timeouts.\n",
+		"example.diff": "-timeout: 10s\n+timeout: 30s\n",
+		"campaign.yaml": `repos: [payments-api]
+context:
+  - file: rfc-42.md
+  - file: example.diff
+    title: Example change
+  - file: rfc-42.md
+    copy: true
+  - url: https://wiki.example.com/rfc-42
+    title: Discussion
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := LoadManifest(filepath.Join(dir, "repos.txt"))
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if strings.Join(m.Repos, ",") != "payments-api,ledger-service@main" || m.PromptContext() != "" || m.Files() != nil {
+		t.Errorf("unexpected text manifest %+v", m)
+	}
+
+	m, err = LoadManifest(filepath.Join(dir, "campaign.yaml"))
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	want := "\n\nThis campaign comes with the context below. Follow it rather than your own assumptions." +
+		"\n\n### rfc-42.md\n\n# RFC 42\n\nUse 30s timeouts." +
+		"\n\n### Example change\n\n```\n-timeout: 10s\n+timeout: 30s\n```" +
+		"\n\nRead these files in the repository (they are removed before committing):\n- .copycat/context/rfc-42.md" +
+		"\n\nLinks:\n- Discussion: https://wiki.example.com/rfc-42"
+	if got := m.PromptContext(); got != want {
+		t.Errorf("PromptContext:\n%s\nwant:\n%s", got, want)
+	}
+	if copied := m.Files(); len(copied) != 1 || copied[0].Path != ".copycat/context/rfc-42.md" || copied[0].Content != files["rfc-42.md"] {
+		t.Errorf("unexpected files %+v", copied)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte("repos: [a]\ncontext:\n  - file: missing.md\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadManifest(filepath.Join(dir, "bad.yaml")); err == nil {
+		t.Error("expected a missing context file to be rejected")
+	}
+}
+
+func TestLoadManifestDependencies(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "campaign.yaml")
+	data := `repos: [shared-lib, payments-api, ledger-service@main]
+depends_on:
+  payments-api: [shared-lib]
+  ledger-service@main: [shared-lib, payments-api]
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if !reflect.DeepEqual(m.DependsOn, map[string][]string{"payments-api": {"shared-lib"}, "ledger-service@main": {"shared-lib", "payments-api"}}) {
+		t.Errorf("unexpected dependencies %+v", m.DependsOn)
+	}
+
+	projects := m.WithDependencies([]Project{
+		{Repo: "shared-lib"},
+		{Repo: "payments-api", DependsOn: []string{"shared-lib", "auth"}},
+		{Repo: "ledger-service", TargetBranch: "main"},
+		{Repo: "ledger-service", TargetBranch: "release"},
+	})
+	for i, want := range [][]string{nil, {"shared-lib", "auth"}, {"shared-lib", "payments-api"}, nil} {
+		if !slices.Equal(projects[i].DependsOn, want) {
+			t.Errorf("expected %s to depend on %v, got %v", projects[i].Key(), want, projects[i].DependsOn)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("repos: [payments-api]\ndepends_on:\n  ledger-service: [payments-api]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadManifest(path); err == nil || !strings.Contains(err.Error(), "ledger-service, which is not in repos") {
+		t.Errorf("expected dependencies of an unlisted repo to be rejected, got %v", err)
+	}
+}
+
+func TestCommitMessageRender(t *testing.T) {
+	data := CommitMessageData{PRTitle: "Bump the timeout", Repo: "payments-api", Branch: "copycat-timeout"}
+
+	if got, err := (CommitMessage{}).Render(data); err != nil || got != "Bump the timeout" {
+		t.Errorf("expected the PR title without a template, got %q (%v)", got, err)
+	}
+
+	msg := CommitMessage{
+		Subject:  "chore(deps): {{.PRTitle}}",
+		Body:     "Changed in {{.Repo}} on {{.Branch}}.",
+		Trailers: []string{"Co-authored-by: Jane <jane@example.com>"},
+	}
+	want := "chore(deps): Bump the timeout\n\nChanged in payments-api on copycat-timeout.\n\nCo-authored-by: Jane <jane@example.com>"
+	if got, err := msg.Render(data); err != nil || got != want {
+		t.Errorf("expected %q, got %q (%v)", want, got, err)
+	}
+
+	if err := (CommitMessage{Subject: "{{.Title}}"}).validate(); err == nil {
+		t.Error("expected an unknown field to be rejected")
+	}
+	if err := (CommitMessage{Subject: "{{.PRTitle"}).validate(); err == nil {
+		t.Error("expected an unclosed action to be rejected")
+	}
+	if err := (CommitMessage{Trailers: []string{"Co-authored-by Jane"}}).validate(); err == nil {
+		t.Error("expected a trailer without a colon to be rejected")
+	}
+}
+
+func TestPRPolicy(t *testing.T) {
+	policy := PRPolicy{
+		TitlePattern:   `^[A-Z]+-[0-9]+ - `,
+		TitleHint:      "start with a ticket, e.g. PROJ-123 - Bump deps",
+		MaxTitleLength: 40,
+		RequiredTags:   []string{"[automated]"},
+	}
+	for title, want := range map[string]string{
+		"PROJ-123 - Bump deps [automated]":                     "",
+		"PROJ-123 - Bump every dependency we have [automated]": "PR title is 52 characters, more than the 40 allowed",
+		"PROJ-123 - Bump deps":                                 "PR title must include [automated]",
+		"Bump deps [automated]":                                "PR title must start with a ticket, e.g. PROJ-123 - Bump deps",
+	} {
+		err := policy.CheckTitle(title)
+		if got := fmt.Sprint(err); (want == "" && err != nil) || (want != "" && got != want) {
+			t.Errorf("CheckTitle(%q) = %v, want %q", title, err, want)
+		}
+	}
+
+	policy = PRPolicy{DescriptionPattern: `(?m)^Ticket: `}
+	if err := policy.Check("Bump deps", "Bumped.\nTicket: PROJ-1"); err != nil {
+		t.Errorf("expected the description to pass, got %v", err)
+	}
+	if err := policy.Check("Bump deps", "Bumped."); err == nil || !strings.Contains(err.Error(), "description") {
+		t.Errorf("expected the description to be rejected, got %v", err)
+	}
+
+	if err := (PRPolicy{TitlePattern: "("}).validate(); err == nil {
+		t.Error("expected an invalid pattern to be rejected")
+	}
+}
+
+func TestGitHubCloneURL(t *testing.T) {
+	if got := (GitHubConfig{Organization: "acme"}).CloneURL("payments-api"); got != "git@github.com:acme/payments-api.git" {
+		t.Errorf("unexpected github.com clone URL %q", got)
+	}
+	ghe := GitHubConfig{Host: "github.mycorp.com", Organization: "acme"}
+	if got := ghe.CloneURL("payments-api"); got != "git@github.mycorp.com:acme/payments-api.git" {
+		t.Errorf("unexpected GitHub Enterprise clone URL %q", got)
+	}
+}
+
+func TestExcludeRepos(t *testing.T) {
+	g := GitHubConfig{ExcludeRepos: []string{"frozen-ledger", "legacy-*"}}
+	projects := []Project{{Repo: "payments-api"}, {Repo: "frozen-ledger"}, {Repo: "legacy-billing"}, {Repo: "ledger"}}
+	var kept []string
+	for _, p := range g.WithoutExcluded(projects) {
+		kept = append(kept, p.Repo)
+	}
+	if !reflect.DeepEqual(kept, []string{"payments-api", "ledger"}) {
+		t.Errorf("expected exact names and globs to be excluded, got %v", kept)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "github:\n  organization: acme\n  exclude_repos: [\"legacy-[\"]\ntools:\n  - name: claude\n    command: claude\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected a malformed exclude_repos glob to be rejected")
+	}
+}
+
+func TestCloneURL(t *testing.T) {
+	tests := []struct {
+		protocol string
+		host     string
+		want     string
+	}{
+		{"", "", "git@github.com:acme/payments-api.git"},
+		{"ssh", "github.mycorp.com", "git@github.mycorp.com:acme/payments-api.git"},
+		{"https", "", "https://github.com/acme/payments-api.git"},
+		{"gh", "github.mycorp.com", "https://github.mycorp.com/acme/payments-api.git"},
+	}
+	for _, tt := range tests {
+		g := GitHubConfig{Host: tt.host, Organization: "acme", CloneProtocol: tt.protocol}
+		if got := g.CloneURL("payments-api"); got != tt.want {
+			t.Errorf("CloneURL with protocol %q = %q, expected %q", tt.protocol, got, tt.want)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "github:\n  organization: acme\n  clone_protocol: ftp\ntools:\n  - name: claude\n    command: claude\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected an unknown clone_protocol to be rejected")
+	}
+}
+
+func TestParseTopicExpr(t *testing.T) {
+	tests := []struct {
+		expr   string
+		topics []string
+		want   bool
+	}{
+		{"", nil, true},
+		{"copycat AND java AND NOT deprecated", []string{"copycat", "java"}, true},
+		{"copycat AND java AND NOT deprecated", []string{"copycat", "java", "deprecated"}, false},
+		{"copycat java", []string{"copycat"}, false},
+		{"copycat and (java or kotlin)", []string{"copycat", "Kotlin"}, true},
+		{"NOT copycat OR java", []string{"copycat", "java"}, true},
+		{"NOT (copycat OR java)", []string{"java"}, false},
+	}
+	for _, tt := range tests {
+		match, err := ParseTopicExpr(tt.expr)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.expr, err)
+		}
+		if got := match(tt.topics); got != tt.want {
+			t.Errorf("%q on %v: expected %v, got %v", tt.expr, tt.topics, tt.want, got)
+		}
+	}
+
+	for _, expr := range []string{"copycat AND", "(java", "OR java", "java )"} {
+		if _, err := ParseTopicExpr(expr); err == nil {
+			t.Errorf("expected %q to be rejected", expr)
+		}
+	}
+}
+
+func TestDiscoveryFilter(t *testing.T) {
+	filter, err := ParseDiscoveryFilter("language:java,kotlin age:<1y age:>30d copycat AND NOT deprecated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DiscoveryFilter{Languages: []string{"java", "kotlin"}, MinAge: "30d", MaxAge: "1y", Topics: "copycat AND NOT deprecated"}
+	if !reflect.DeepEqual(filter, want) {
+		t.Fatalf("expected %+v, got %+v", want, filter)
+	}
+	if filter.String() != "language:java,kotlin age:>30d age:<1y copycat AND NOT deprecated" {
+		t.Errorf("unexpected query %q", filter.String())
+	}
+
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	project := Project{Repo: "ledger", Language: "Java", Topics: []string{"copycat"}, PushedAt: now.AddDate(0, -2, 0)}
+	if !filter.Matches(project, now) {
+		t.Error("expected a Java repo pushed two months ago to match")
+	}
+	for name, p := range map[string]Project{
+		"other language": {Language: "Go", Topics: []string{"copycat"}, PushedAt: project.PushedAt},
+		"too recent":     {Language: "Java", Topics: []string{"copycat"}, PushedAt: now.AddDate(0, 0, -7)},
+		"too old":        {Language: "Java", Topics: []string{"copycat"}, PushedAt: now.AddDate(-2, 0, 0)},
+		"never pushed":   {Language: "Java", Topics: []string{"copycat"}},
+		"deprecated":     {Language: "Java", Topics: []string{"copycat", "deprecated"}, PushedAt: project.PushedAt},
+	} {
+		if filter.Matches(p, now) {
+			t.Errorf("%s: expected no match", name)
+		}
+	}
+
+	if _, err := ParseDiscoveryFilter("age:90d"); err == nil {
+		t.Error("expected an age without a direction to be rejected")
+	}
+	if _, err := ParseDiscoveryFilter("age:<soon"); err == nil {
+		t.Error("expected an invalid age to be rejected")
+	}
+}
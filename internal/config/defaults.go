@@ -45,6 +45,7 @@ tools:
       - WebFetch
       - Task
     supports_permission_prompt: true
+    resume_flag: --resume
   - name: codex
     command: codex
     code_args:
@@ -64,6 +65,10 @@ tools:
       - --approval-mode
       - auto_edit
     summary_args: []
+  # Answers assessments with a local model, so no code leaves the machine:
+  # - name: ollama
+  #   api_provider: ollama
+  #   model: llama3.1
 `
 
 // DefaultConfigContent returns the default config content with the given org.
@@ -106,6 +111,7 @@ func DefaultConfig(org string) *Config {
 					},
 					DisallowedTools:          []string{"WebFetch", "Task"},
 					SupportsPermissionPrompt: true,
+					ResumeFlag:               "--resume",
 				},
 				{
 					Name:        "codex",
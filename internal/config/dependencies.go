@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OrderByDependencies returns projects sorted so every project comes after the
+// projects it depends on. Dependencies on repos outside the list are ignored,
+// and projects without dependencies keep their relative order.
+func OrderByDependencies(projects []Project) ([]Project, error) {
+	// A repo may appear once per target branch; dependents wait for all of them
+	index := make(map[string][]int, len(projects))
+	for i, p := range projects {
+		index[p.Repo] = append(index[p.Repo], i)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(projects))
+	ordered := make([]Project, 0, len(projects))
+
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, projects[i].Repo), " -> "))
+		}
+		state[i] = visiting
+		for _, dep := range projects[i].DependsOn {
+			if dep == projects[i].Repo {
+				continue
+			}
+			for _, j := range index[dep] {
+				if err := visit(j, append(path, projects[i].Repo)); err != nil {
+					return err
+				}
+			}
+		}
+		state[i] = visited
+		ordered = append(ordered, projects[i])
+		return nil
+	}
+
+	for i := range projects {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// SelectedDependencies returns the dependencies of p that are part of projects.
+func SelectedDependencies(p Project, projects []Project) []string {
+	selected := make(map[string]bool, len(projects))
+	for _, other := range projects {
+		selected[other.Repo] = true
+	}
+
+	var deps []string
+	for _, dep := range p.DependsOn {
+		if selected[dep] && dep != p.Repo {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOrderByDependencies(t *testing.T) {
+	projects := []Project{
+		{Repo: "consumer-a", DependsOn: []string{"shared-lib"}},
+		{Repo: "standalone"},
+		{Repo: "consumer-b", DependsOn: []string{"consumer-a", "not-selected"}},
+		{Repo: "shared-lib"},
+	}
+
+	ordered, err := OrderByDependencies(projects)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var repos []string
+	for _, p := range ordered {
+		repos = append(repos, p.Repo)
+	}
+	expected := "shared-lib,consumer-a,standalone,consumer-b"
+	if got := strings.Join(repos, ","); got != expected {
+		t.Errorf("expected order %s, got %s", expected, got)
+	}
+}
+
+func TestOrderByDependenciesCycle(t *testing.T) {
+	projects := []Project{
+		{Repo: "a", DependsOn: []string{"b"}},
+		{Repo: "b", DependsOn: []string{"a"}},
+	}
+
+	_, err := OrderByDependencies(projects)
+	if err == nil || !strings.Contains(err.Error(), "a -> b -> a") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+}
+
+func TestSelectedDependencies(t *testing.T) {
+	projects := []Project{{Repo: "lib"}, {Repo: "app", DependsOn: []string{"lib", "other", "app"}}}
+
+	deps := SelectedDependencies(projects[1], projects)
+	if len(deps) != 1 || deps[0] != "lib" {
+		t.Errorf("expected [lib], got %v", deps)
+	}
+}
+
+func TestOrderByDependenciesTargetBranches(t *testing.T) {
+	projects := []Project{
+		{Repo: "app", DependsOn: []string{"lib"}},
+		{Repo: "lib", TargetBranch: "main"},
+		{Repo: "lib", TargetBranch: "release/1.x"},
+	}
+
+	ordered, err := OrderByDependencies(projects)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ordered[2].Repo != "app" {
+		t.Errorf("expected app after every lib target, got %v", ordered)
+	}
+}
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiscoveryFilter narrows the repos listed from GitHub, on top of
+// auto_discovery_topic. Empty fields do not filter.
+type DiscoveryFilter struct {
+	Languages []string `yaml:"languages,omitempty"` // primary languages, any of them, e.g. [Java, Kotlin]
+	MinAge    string   `yaml:"min_age,omitempty"`   // last push at least this long ago, e.g. 30d
+	MaxAge    string   `yaml:"max_age,omitempty"`   // last push at most this long ago, e.g. 1y
+	Topics    string   `yaml:"topics,omitempty"`    // topic expression, e.g. "copycat AND java AND NOT deprecated"
+}
+
+// IsZero reports whether the filter lets every repo through.
+func (f DiscoveryFilter) IsZero() bool {
+	return len(f.Languages) == 0 && f.MinAge == "" && f.MaxAge == "" && strings.TrimSpace(f.Topics) == ""
+}
+
+// Validate checks the ages and the topic expression.
+func (f DiscoveryFilter) Validate() error {
+	for _, age := range []string{f.MinAge, f.MaxAge} {
+		if age == "" {
+			continue
+		}
+		if _, err := ParseAge(age); err != nil {
+			return err
+		}
+	}
+	_, err := ParseTopicExpr(f.Topics)
+	return err
+}
+
+// Matches reports whether the project passes the filter at now. Projects
+// without a recorded push only pass filters without ages. The filter must
+// be valid.
+func (f DiscoveryFilter) Matches(p Project, now time.Time) bool {
+	if len(f.Languages) > 0 && !slices.ContainsFunc(f.Languages, func(l string) bool { return strings.EqualFold(l, p.Language) }) {
+		return false
+	}
+	if f.MinAge != "" || f.MaxAge != "" {
+		if p.PushedAt.IsZero() {
+			return false
+		}
+		age := now.Sub(p.PushedAt)
+		if minAge, _ := ParseAge(f.MinAge); f.MinAge != "" && age < minAge {
+			return false
+		}
+		if maxAge, _ := ParseAge(f.MaxAge); f.MaxAge != "" && age > maxAge {
+			return false
+		}
+	}
+	match, _ := ParseTopicExpr(f.Topics)
+	return match(p.Topics)
+}
+
+// String writes the filter in the selector's query syntax, e.g.
+// "language:java,kotlin age:<1y copycat AND NOT deprecated".
+func (f DiscoveryFilter) String() string {
+	var parts []string
+	if len(f.Languages) > 0 {
+		parts = append(parts, "language:"+strings.Join(f.Languages, ","))
+	}
+	if f.MinAge != "" {
+		parts = append(parts, "age:>"+f.MinAge)
+	}
+	if f.MaxAge != "" {
+		parts = append(parts, "age:<"+f.MaxAge)
+	}
+	if topics := strings.TrimSpace(f.Topics); topics != "" {
+		parts = append(parts, topics)
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseDiscoveryFilter reads a filter in the selector's query syntax:
+// language:<a,b>, age:>N and age:<N terms, and a topic expression made of
+// the remaining words.
+func ParseDiscoveryFilter(query string) (DiscoveryFilter, error) {
+	var f DiscoveryFilter
+	var topics []string
+	for _, word := range strings.Fields(query) {
+		key, value, ok := strings.Cut(word, ":")
+		switch {
+		case ok && (key == "language" || key == "lang"):
+			for _, language := range strings.Split(value, ",") {
+				if language != "" {
+					f.Languages = append(f.Languages, language)
+				}
+			}
+		case ok && key == "age" && strings.HasPrefix(value, ">"):
+			f.MinAge = strings.TrimPrefix(value, ">")
+		case ok && key == "age" && strings.HasPrefix(value, "<"):
+			f.MaxAge = strings.TrimPrefix(value, "<")
+		case ok && key == "age":
+			return DiscoveryFilter{}, fmt.Errorf("age %q must start with > or <, e.g. age:<90d", value)
+		default:
+			topics = append(topics, word)
+		}
+	}
+	f.Topics = strings.Join(topics, " ")
+	return f, f.Validate()
+}
+
+var agePattern = regexp.MustCompile(`^(\d+)(d|w|y)$`)
+
+// ParseAge reads an age in days (30d), weeks (2w) or years (1y), or a Go
+// duration such as 36h.
+func ParseAge(s string) (time.Duration, error) {
+	if m := agePattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		day := 24 * time.Hour
+		switch m[2] {
+		case "w":
+			return time.Duration(n) * 7 * day, nil
+		case "y":
+			return time.Duration(n) * 365 * day, nil
+		}
+		return time.Duration(n) * day, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("age %q must be like 30d, 2w, 1y or 36h", s)
+	}
+	return d, nil
+}
+
+// ParseTopicExpr compiles a boolean expression over topics, e.g.
+// "copycat AND (java OR kotlin) AND NOT deprecated". AND, OR and NOT are
+// case-insensitive; words next to each other are ANDed; NOT binds tightest,
+// then AND, then OR. An empty expression matches every repo.
+func ParseTopicExpr(expr string) (func(topics []string) bool, error) {
+	p := topicParser{tokens: tokenizeTopicExpr(expr)}
+	if len(p.tokens) == 0 {
+		return func([]string) bool { return true }, nil
+	}
+	match, err := p.or()
+	if err != nil {
+		return nil, fmt.Errorf("topic expression %q: %w", expr, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("topic expression %q: unexpected %q", expr, p.tokens[p.pos])
+	}
+	return match, nil
+}
+
+func tokenizeTopicExpr(expr string) []string {
+	expr = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)
+	return strings.Fields(expr)
+}
+
+type topicParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *topicParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *topicParser) or() (func([]string) bool, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "OR") {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(topics []string) bool { return l(topics) || right(topics) }
+	}
+	return left, nil
+}
+
+func (p *topicParser) and() (func([]string) bool, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		next := p.peek()
+		if next == "" || next == ")" || strings.EqualFold(next, "OR") {
+			return left, nil
+		}
+		if strings.EqualFold(next, "AND") {
+			p.pos++
+		}
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(topics []string) bool { return l(topics) && right(topics) }
+	}
+}
+
+func (p *topicParser) not() (func([]string) bool, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("expected a topic at the end")
+	case strings.EqualFold(token, "NOT"):
+		p.pos++
+		inner, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return func(topics []string) bool { return !inner(topics) }, nil
+	case token == "(":
+		p.pos++
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return inner, nil
+	case token == ")" || strings.EqualFold(token, "AND") || strings.EqualFold(token, "OR"):
+		return nil, fmt.Errorf("expected a topic, got %q", token)
+	}
+	p.pos++
+	topic := strings.ToLower(token)
+	return func(topics []string) bool {
+		return slices.ContainsFunc(topics, func(t string) bool { return strings.ToLower(t) == topic })
+	}, nil
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ContextDir is where copied manifest attachments are put in each repo. Like
+// every temporary file change, they are removed before committing.
+const ContextDir = ".copycat/context"
+
+// Manifest lists the repos of a run and, in its YAML form, the context
+// attachments every repo's AI step gets and the order repos must land in.
+type Manifest struct {
+	Repos   []string     `yaml:"repos"`
+	Context []Attachment `yaml:"context,omitempty"`
+	// DependsOn lists, by repo name or project key, the repos whose changes
+	// must land first, on top of each project's own depends_on.
+	DependsOn map[string][]string `yaml:"depends_on,omitempty"`
+}
+
+// Attachment is campaign context: a local file (e.g. the RFC or an example
+// diff) inlined into the prompt or copied into each repo, or a link.
+type Attachment struct {
+	File  string `yaml:"file,omitempty"`  // relative to the manifest
+	Copy  bool   `yaml:"copy,omitempty"`  // copy into ContextDir instead of inlining
+	URL   string `yaml:"url,omitempty"`   // listed in the prompt
+	Title string `yaml:"title,omitempty"` // defaults to the file name
+
+	content string
+}
+
+// LoadManifest reads a manifest. A .yaml or .yml file holds repos and
+// context; any other file lists repos one name or project key per line, with
+// blank lines and # comments ignored. Attached files are read now.
+func LoadManifest(filename string) (Manifest, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return Manifest{}, fmt.Errorf("failed to parse manifest %s: %w", filename, err)
+		}
+	default:
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") {
+				m.Repos = append(m.Repos, line)
+			}
+		}
+	}
+
+	for repo := range m.DependsOn {
+		if !slices.Contains(m.Repos, repo) {
+			return Manifest{}, fmt.Errorf("depends_on in %s is for %s, which is not in repos", filename, repo)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i := range m.Context {
+		a := &m.Context[i]
+		if (a.File == "") == (a.URL == "") {
+			return Manifest{}, fmt.Errorf("context %d in %s needs either a file or a url", i+1, filename)
+		}
+		if a.URL != "" {
+			if a.Copy {
+				return Manifest{}, fmt.Errorf("context %s in %s is a link and cannot be copied", a.URL, filename)
+			}
+			continue
+		}
+		p := a.File
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(filepath.Dir(filename), p)
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("failed to read context file: %w", err)
+		}
+		a.content = string(content)
+		if a.Copy {
+			name := filepath.Base(a.File)
+			if names[name] {
+				return Manifest{}, fmt.Errorf("copied context files in %s need different names, %s is used twice", filename, name)
+			}
+			names[name] = true
+		}
+	}
+	return m, nil
+}
+
+// Files returns the attachments to copy into each repo, as temporary file
+// changes.
+func (m Manifest) Files() []TempChange {
+	var changes []TempChange
+	for _, a := range m.Context {
+		if a.Copy {
+			changes = append(changes, TempChange{
+				Path:    path.Join(ContextDir, filepath.Base(a.File)),
+				Content: a.content,
+				Source:  a.File,
+			})
+		}
+	}
+	return changes
+}
+
+// WithDependencies returns projects with the dependencies the manifest lists
+// for each, by key or by repo name, added to the project's own.
+func (m Manifest) WithDependencies(projects []Project) []Project {
+	if len(m.DependsOn) == 0 {
+		return projects
+	}
+	result := make([]Project, len(projects))
+	for i, project := range projects {
+		deps := slices.Clone(project.DependsOn)
+		for _, dep := range slices.Concat(m.DependsOn[project.Repo], m.DependsOn[project.Key()]) {
+			if !slices.Contains(deps, dep) {
+				deps = append(deps, dep)
+			}
+		}
+		project.DependsOn = deps
+		result[i] = project
+	}
+	return result
+}
+
+// PromptContext renders the attachments for the prompt: inlined files in
+// full, and the copied files and links by name.
+func (m Manifest) PromptContext() string {
+	if len(m.Context) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\nThis campaign comes with the context below. Follow it rather than your own assumptions.")
+	var copied, links []string
+	for _, a := range m.Context {
+		switch {
+		case a.URL != "" && a.Title != "":
+			links = append(links, fmt.Sprintf("- %s: %s", a.Title, a.URL))
+		case a.URL != "":
+			links = append(links, "- "+a.URL)
+		case a.Copy:
+			copied = append(copied, "- "+path.Join(ContextDir, filepath.Base(a.File)))
+		case isProse(a.File):
+			fmt.Fprintf(&b, "\n\n### %s\n\n%s", a.title(), strings.TrimRight(a.content, "\n"))
+		default:
+			fmt.Fprintf(&b, "\n\n### %s\n\n```\n%s\n```", a.title(), strings.TrimRight(a.content, "\n"))
+		}
+	}
+	if len(copied) > 0 {
+		b.WriteString("\n\nRead these files in the repository (they are removed before committing):\n" + strings.Join(copied, "\n"))
+	}
+	if len(links) > 0 {
+		b.WriteString("\n\nLinks:\n" + strings.Join(links, "\n"))
+	}
+	return b.String()
+}
+
+// isProse reports whether a file is inlined as is rather than fenced as code.
+func isProse(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown", ".txt", "":
+		return true
+	}
+	return false
+}
+
+func (a Attachment) title() string {
+	if a.Title != "" {
+		return a.Title
+	}
+	return filepath.Base(a.File)
+}
@@ -67,3 +67,78 @@ func ConfigExists() (bool, string, error) {
 
 	return false, "", nil
 }
+
+// ReportsDir returns the directory where per-run HTML reports are written.
+func ReportsDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "reports"), nil
+}
+
+// HandoffsDir returns the directory where paused runs are exported for
+// another operator to resume.
+func HandoffsDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "handoffs"), nil
+}
+
+// HistoryPath returns the path of the run history log.
+func HistoryPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// FlakyTestsPath returns the file where the last flaky test detection run's
+// results are saved.
+func FlakyTestsPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "flaky-tests.json"), nil
+}
+
+// PermissionPolicyPath returns the file where permission decisions to
+// repeat on every run are saved.
+func PermissionPolicyPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "permissions.yaml"), nil
+}
+
+// PermissionAuditPath returns the log of the permission requests answered
+// from the policy alone, see Permissions.
+func PermissionAuditPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "permission-audit.jsonl"), nil
+}
+
+// PermissionQueuePath returns the file where the permission server keeps the
+// requests AI tools are waiting on, so a restarted copycat can answer them.
+func PermissionQueuePath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "permission-queue.json"), nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// PRPolicy is what an organization requires of PR titles and descriptions,
+// so runs fail fast instead of having branch-protection bots reject their
+// PRs. Titles are checked in the wizard and before any repo is worked on,
+// and titles and descriptions again before each repo's changes are pushed.
+type PRPolicy struct {
+	TitlePattern       string   `yaml:"title_pattern,omitempty"`       // regexp titles must match, e.g. ^[A-Z]+-[0-9]+ for a ticket prefix
+	TitleHint          string   `yaml:"title_hint,omitempty"`          // explains the pattern, e.g. "start with a Jira ticket: PROJ-123 - ..."
+	MaxTitleLength     int      `yaml:"max_title_length,omitempty"`    // in characters
+	RequiredTags       []string `yaml:"required_tags,omitempty"`       // must appear in titles, e.g. [automated]
+	DescriptionPattern string   `yaml:"description_pattern,omitempty"` // regexp descriptions must match
+}
+
+// CheckTitle returns why title breaks the policy, or nil if it doesn't.
+func (p PRPolicy) CheckTitle(title string) error {
+	if p.MaxTitleLength > 0 {
+		if n := utf8.RuneCountInString(title); n > p.MaxTitleLength {
+			return fmt.Errorf("PR title is %d characters, more than the %d allowed", n, p.MaxTitleLength)
+		}
+	}
+	var missing []string
+	for _, tag := range p.RequiredTags {
+		if !strings.Contains(title, tag) {
+			missing = append(missing, tag)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("PR title must include %s", strings.Join(missing, " and "))
+	}
+	if p.TitlePattern != "" && !regexp.MustCompile(p.TitlePattern).MatchString(title) {
+		if p.TitleHint != "" {
+			return fmt.Errorf("PR title must %s", p.TitleHint)
+		}
+		return fmt.Errorf("PR title must match %s", p.TitlePattern)
+	}
+	return nil
+}
+
+// Check returns why a PR's title or description breaks the policy, or nil
+// if neither does.
+func (p PRPolicy) Check(title, description string) error {
+	if err := p.CheckTitle(title); err != nil {
+		return err
+	}
+	if p.DescriptionPattern != "" && !regexp.MustCompile(p.DescriptionPattern).MatchString(description) {
+		return fmt.Errorf("PR description must match %s", p.DescriptionPattern)
+	}
+	return nil
+}
+
+func (p PRPolicy) validate() error {
+	for name, pattern := range map[string]string{"title_pattern": p.TitlePattern, "description_pattern": p.DescriptionPattern} {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("pr_policy.%s %q is not a valid regular expression: %v", name, pattern, err)
+		}
+	}
+	if p.MaxTitleLength < 0 {
+		return fmt.Errorf("pr_policy.max_title_length must not be negative")
+	}
+	for _, tag := range p.RequiredTags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("pr_policy.required_tags must not be empty")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// SlackMessages templates the Slack notifications sent about a run's PRs and
+// issues, so they can follow a channel's conventions. Each is a Go template
+// over SlackMessageData, e.g. "{{range .Repos}}:pr: <{{.URL}}|{{.Repo}}>\n{{end}}".
+// The built-in message is sent when a template is not set.
+type SlackMessages struct {
+	PRs    string `yaml:"prs,omitempty"`
+	Issues string `yaml:"issues,omitempty"`
+}
+
+// SlackMessageData is what Slack message templates can refer to. One
+// message goes to each channel, listing that channel's repos.
+type SlackMessageData struct {
+	Title  string // of the PRs or issues
+	Prompt string // the start of the run's prompt
+	Author string // who ran copycat
+	Repos  []SlackMessageRepo
+}
+
+// SlackMessageRepo is a repo listed in a Slack message.
+type SlackMessageRepo struct {
+	Repo string
+	URL  string // of its PR or issue
+	CI   string // its PR's CI status, when the run waited for checks
+}
+
+// RenderSlackMessage executes the Slack message template text, named after
+// its setting, with data.
+func RenderSlackMessage(name, text string, data SlackMessageData) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("slack messages %s: %v", name, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("slack messages %s: %v", name, err)
+	}
+	return b.String(), nil
+}
+
+// validate checks the templates render, so mistakes show up when the config
+// loads rather than when the notification is sent.
+func (s SlackMessages) validate() error {
+	sample := SlackMessageData{Repos: []SlackMessageRepo{{}}}
+	for name, text := range map[string]string{"prs": s.PRs, "issues": s.Issues} {
+		if text == "" {
+			continue
+		}
+		if _, err := RenderSlackMessage(name, text, sample); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package example turns a reference pull request, where a change was once
+// made by hand, into prompt context showing the AI how to make it elsewhere.
+package example
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxDiffBytes caps the example diff in the prompt; larger diffs are cut at a
+// file boundary where possible.
+const maxDiffBytes = 40_000
+
+// pullRequestURL matches https://<host>/<owner>/<repo>/pull/<number>, on
+// github.com or a GitHub Enterprise host.
+var pullRequestURL = regexp.MustCompile(`^https://[^/]+/([^/]+)/([^/]+)/pull/(\d+)/?$`)
+
+// Repo returns the repo name of the pull request at url, or an error if url
+// is not a GitHub pull request URL.
+func Repo(url string) (string, error) {
+	match := pullRequestURL.FindStringSubmatch(strings.TrimSpace(url))
+	if match == nil {
+		return "", fmt.Errorf("%q is not a pull request URL like https://github.com/org/repo/pull/123", url)
+	}
+	return match[2], nil
+}
+
+// PromptContext renders the reference PR's diff as an example of the change
+// to make.
+func PromptContext(url, diff string) string {
+	diff = strings.TrimSpace(diff)
+	truncated := ""
+	if len(diff) > maxDiffBytes {
+		cut := diff[:maxDiffBytes]
+		if i := strings.LastIndex(cut, "\ndiff --git "); i > 0 {
+			cut = cut[:i]
+		}
+		truncated = fmt.Sprintf("\n(%d more bytes of the diff were left out)", len(diff)-len(cut))
+		diff = cut
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\nThis change was already made by hand in %s. Use its diff below as the canonical example: ", url)
+	b.WriteString("make the equivalent change in this repository, following the same approach, naming and style, ")
+	b.WriteString("and adapting it to this repository's layout rather than copying it line by line.\n\n")
+	b.WriteString("```diff\n")
+	b.WriteString(diff)
+	b.WriteString("\n```")
+	b.WriteString(truncated)
+	return b.String()
+}
@@ -0,0 +1,41 @@
+package example
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRepo(t *testing.T) {
+	repo, err := Repo("https://github.com/acme/payments-api/pull/42")
+	if err != nil || repo != "payments-api" {
+		t.Errorf("expected payments-api, got %q (%v)", repo, err)
+	}
+	for _, url := range []string{"https://github.com/acme/payments-api", "payments-api#42", ""} {
+		if _, err := Repo(url); err == nil {
+			t.Errorf("expected %q to be rejected", url)
+		}
+	}
+}
+
+func TestPromptContext(t *testing.T) {
+	diff := "diff --git a/go.mod b/go.mod\n-go 1.24\n+go 1.25\n"
+	got := PromptContext("https://github.com/acme/payments-api/pull/42", diff)
+	if !strings.Contains(got, "already made by hand in https://github.com/acme/payments-api/pull/42") {
+		t.Errorf("expected the reference PR to be named, got %q", got)
+	}
+	if !strings.Contains(got, "```diff\n"+strings.TrimSpace(diff)+"\n```") {
+		t.Errorf("expected the diff in a code block, got %q", got)
+	}
+}
+
+func TestPromptContextTruncatesAtFileBoundary(t *testing.T) {
+	file := "diff --git a/f b/f\n" + strings.Repeat("+line\n", maxDiffBytes/12)
+	got := PromptContext("https://github.com/acme/api/pull/1", file+file+file)
+
+	if n := strings.Count(got, "diff --git"); n != 1 {
+		t.Errorf("expected the diff cut after the first file, got %d files", n)
+	}
+	if !strings.Contains(got, "more bytes of the diff were left out") {
+		t.Errorf("expected a note about the truncation, got %q", got[len(got)-100:])
+	}
+}
@@ -0,0 +1,178 @@
+// Package flaky asks each repo to look for flaky tests, collects what the AI
+// reports, and turns the fleet-wide results into a follow-up fix campaign.
+package flaky
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// marker prefixes the lines in AI output that report a suspected flaky test.
+const marker = "COPYCAT_FLAKY "
+
+// DefaultRuns is how many times the test suite is run when no count is configured.
+const DefaultRuns = 5
+
+// maxPromptTests caps the tests listed in a repo's fix prompt.
+const maxPromptTests = 50
+
+// Test is a test suspected of being flaky.
+type Test struct {
+	Name     string `json:"name"`
+	File     string `json:"file,omitempty"`
+	Failures int    `json:"failures"` // failed runs (or CI jobs) out of Runs
+	Runs     int    `json:"runs"`
+}
+
+// Rate is the fraction of runs in which the test failed.
+func (t Test) Rate() float64 {
+	if t.Runs == 0 {
+		return 0
+	}
+	return float64(t.Failures) / float64(t.Runs)
+}
+
+// Prompt renders the instructions appended to the detection question: run the
+// suite runs times, fall back to CI history, and report each suspect.
+func Prompt(runs int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\nFind the repository's test command and run the full test suite %d times without changing any code. ", runs)
+	b.WriteString("A test is suspected flaky when it both passes and fails across those runs. ")
+	b.WriteString("If the suite cannot run here (missing services, credentials or toolchains), use `gh run list` and `gh run view --log-failed` to find tests that failed and later passed on the same commit in recent CI runs instead.\n")
+	b.WriteString("Print exactly one line per suspected flaky test:\n")
+	b.WriteString("COPYCAT_FLAKY <failed>/<runs> <file> <test name>\n")
+	b.WriteString("Use - for the file when unknown. Print no COPYCAT_FLAKY lines if no test is suspected.")
+	return b.String()
+}
+
+// Parse reads the AI's COPYCAT_FLAKY lines, ignoring malformed ones and
+// tests that never failed.
+func Parse(aiOutput string) []Test {
+	var tests []Test
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(aiOutput))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		idx := strings.Index(line, marker)
+		if idx < 0 {
+			continue
+		}
+		fields := strings.Fields(strings.Trim(line[idx+len(marker):], "`"))
+		if len(fields) < 3 {
+			continue
+		}
+		failed, runs, ok := strings.Cut(fields[0], "/")
+		if !ok {
+			continue
+		}
+		t := Test{File: fields[1], Name: strings.Join(fields[2:], " ")}
+		var err error
+		if t.Failures, err = strconv.Atoi(failed); err != nil {
+			continue
+		}
+		if t.Runs, err = strconv.Atoi(runs); err != nil || t.Runs <= 0 || t.Failures <= 0 {
+			continue
+		}
+		if t.Failures > t.Runs {
+			t.Failures = t.Runs
+		}
+		if t.File == "-" {
+			t.File = ""
+		}
+		if key := t.File + "\x00" + t.Name; !seen[key] {
+			seen[key] = true
+			tests = append(tests, t)
+		}
+	}
+	return tests
+}
+
+// Report renders the fleet-wide results as markdown, most often failing first.
+func Report(byRepo map[string][]Test) string {
+	type row struct {
+		repo string
+		test Test
+	}
+	var rows []row
+	for repo, tests := range byRepo {
+		for _, t := range tests {
+			rows = append(rows, row{repo, t})
+		}
+	}
+	if len(rows) == 0 {
+		return "No suspected flaky tests found."
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].test.Rate() != rows[j].test.Rate() {
+			return rows[i].test.Rate() > rows[j].test.Rate()
+		}
+		if rows[i].repo != rows[j].repo {
+			return rows[i].repo < rows[j].repo
+		}
+		return rows[i].test.Name < rows[j].test.Name
+	})
+
+	repos := 0
+	for _, tests := range byRepo {
+		if len(tests) > 0 {
+			repos++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "### Suspected flaky tests\n\n%d tests in %d repositories.\n\n", len(rows), repos)
+	b.WriteString("| Repo | Test | File | Failed |\n|---|---|---|---|\n")
+	for _, r := range rows {
+		fmt.Fprintf(&b, "| %s | %s | %s | %d/%d |\n", r.repo, strings.ReplaceAll(r.test.Name, "|", "\\|"), r.test.File, r.test.Failures, r.test.Runs)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Save writes the repos' suspected flaky tests as JSON, so a later run can
+// fix them with Load.
+func Save(path string, byRepo map[string][]Test) error {
+	data, err := json.MarshalIndent(byRepo, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write flaky tests to %s: %w", path, err)
+	}
+	return nil
+}
+
+// Load reads suspected flaky tests saved by Save, keyed by repo.
+func Load(path string) (map[string][]Test, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flaky tests: %w", err)
+	}
+	var byRepo map[string][]Test
+	if err := json.Unmarshal(data, &byRepo); err != nil {
+		return nil, fmt.Errorf("failed to parse flaky tests in %s: %w", path, err)
+	}
+	return byRepo, nil
+}
+
+// PromptContext renders a repo's flaky tests for appending to a fix prompt.
+func PromptContext(tests []Test) string {
+	var b strings.Builder
+	b.WriteString("\n\nSuspected flaky tests in this repository:\n")
+	for i, t := range tests {
+		if i == maxPromptTests {
+			fmt.Fprintf(&b, "- ... and %d more\n", len(tests)-maxPromptTests)
+			break
+		}
+		location := ""
+		if t.File != "" {
+			location = " in " + t.File
+		}
+		fmt.Fprintf(&b, "- %s%s (failed %d of %d runs)\n", t.Name, location, t.Failures, t.Runs)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,72 @@
+package flaky
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	aiOutput := strings.Join([]string{
+		"Ran go test ./... 5 times.",
+		"COPYCAT_FLAKY 2/5 internal/cache/cache_test.go TestExpiry",
+		"  `COPYCAT_FLAKY 1/5 - checkout flow retries payment`",
+		"COPYCAT_FLAKY 2/5 internal/cache/cache_test.go TestExpiry",
+		"COPYCAT_FLAKY 0/5 api_test.go TestStable",
+		"COPYCAT_FLAKY 7/5 api_test.go TestOvercounted",
+		"COPYCAT_FLAKY often api_test.go TestVague",
+		"COPYCAT_FLAKY 1/5 api_test.go",
+	}, "\n")
+
+	want := []Test{
+		{Name: "TestExpiry", File: "internal/cache/cache_test.go", Failures: 2, Runs: 5},
+		{Name: "checkout flow retries payment", Failures: 1, Runs: 5},
+		{Name: "TestOvercounted", File: "api_test.go", Failures: 5, Runs: 5},
+	}
+	if got := Parse(aiOutput); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReport(t *testing.T) {
+	report := Report(map[string][]Test{
+		"payments-api": {{Name: "TestExpiry", File: "cache_test.go", Failures: 1, Runs: 5}},
+		"web-app":      {{Name: "a | b", Failures: 3, Runs: 5}},
+		"ledger":       nil,
+	})
+	if !strings.Contains(report, "2 tests in 2 repositories.") {
+		t.Errorf("report does not count tests and repos:\n%s", report)
+	}
+	webApp := strings.Index(report, "| web-app | a \\| b |  | 3/5 |")
+	payments := strings.Index(report, "| payments-api | TestExpiry | cache_test.go | 1/5 |")
+	if webApp < 0 || payments < 0 || webApp > payments {
+		t.Errorf("report does not list the most often failing test first:\n%s", report)
+	}
+
+	if got := Report(nil); got != "No suspected flaky tests found." {
+		t.Errorf("Report(nil) = %q", got)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flaky-tests.json")
+	byRepo := map[string][]Test{
+		"payments-api": {{Name: "TestExpiry", File: "cache_test.go", Failures: 1, Runs: 5}},
+	}
+	if err := Save(path, byRepo); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, byRepo) {
+		t.Errorf("Load() = %+v, want %+v", got, byRepo)
+	}
+
+	context := PromptContext(got["payments-api"])
+	if !strings.Contains(context, "- TestExpiry in cache_test.go (failed 1 of 5 runs)") {
+		t.Errorf("prompt context does not list the test:\n%s", context)
+	}
+}
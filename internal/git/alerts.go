@@ -0,0 +1,192 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Alert kinds, as used in GitHub's API paths.
+const (
+	AlertCodeScanning = "code-scanning"
+	AlertDependabot   = "dependabot"
+)
+
+// Alert is an open code scanning or Dependabot alert.
+type Alert struct {
+	Kind     string
+	Number   int
+	Rule     string // rule ID or advisory ID
+	Severity string
+	Summary  string
+	File     string
+	Line     int
+}
+
+// ID identifies the alert within its repo, e.g. "code-scanning#12".
+func (a Alert) ID() string {
+	return fmt.Sprintf("%s#%d", a.Kind, a.Number)
+}
+
+type codeScanningAlert struct {
+	Number int `json:"number"`
+	Rule   struct {
+		ID                    string `json:"id"`
+		Severity              string `json:"severity"`
+		SecuritySeverityLevel string `json:"security_severity_level"`
+		Description           string `json:"description"`
+	} `json:"rule"`
+	MostRecentInstance struct {
+		Location struct {
+			Path      string `json:"path"`
+			StartLine int    `json:"start_line"`
+		} `json:"location"`
+		Message struct {
+			Text string `json:"text"`
+		} `json:"message"`
+	} `json:"most_recent_instance"`
+}
+
+type dependabotAlert struct {
+	Number     int `json:"number"`
+	Dependency struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		ManifestPath string `json:"manifest_path"`
+	} `json:"dependency"`
+	SecurityAdvisory struct {
+		GHSAID   string `json:"ghsa_id"`
+		Summary  string `json:"summary"`
+		Severity string `json:"severity"`
+	} `json:"security_advisory"`
+	SecurityVulnerability struct {
+		FirstPatchedVersion *struct {
+			Identifier string `json:"identifier"`
+		} `json:"first_patched_version"`
+	} `json:"security_vulnerability"`
+}
+
+// FetchOpenAlerts lists a repo's open code scanning and Dependabot alerts.
+// Alert types that are disabled or not visible to the token are reported as
+// warnings rather than errors.
+func FetchOpenAlerts(ctx context.Context, owner, repo string) ([]Alert, []string, error) {
+	var alerts, found []Alert
+	var warnings []string
+	var err error
+
+	for _, kind := range []string{AlertCodeScanning, AlertDependabot} {
+		path := fmt.Sprintf("repos/%s/%s/%s/alerts?state=open&per_page=100", owner, repo, kind)
+		output, apiErr := getPages(ctx, path)
+		if apiErr != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			var e *APIError
+			if errors.As(apiErr, &e) && (e.StatusCode == http.StatusForbidden || e.StatusCode == http.StatusNotFound) {
+				warnings = append(warnings, fmt.Sprintf("%s alerts unavailable", kind))
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to list %s alerts: %w", kind, apiErr)
+		}
+
+		if kind == AlertCodeScanning {
+			found, err = parseCodeScanningAlerts(output)
+		} else {
+			found, err = parseDependabotAlerts(output)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		alerts = append(alerts, found...)
+	}
+	return alerts, warnings, nil
+}
+
+// decodePages decodes the output of getPages, which is one JSON array per page.
+func decodePages[T any](output []byte) ([]T, error) {
+	var items []T
+	dec := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var page []T
+		if err := dec.Decode(&page); errors.Is(err, io.EOF) {
+			return items, nil
+		} else if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+	}
+}
+
+func parseCodeScanningAlerts(output []byte) ([]Alert, error) {
+	raw, err := decodePages[codeScanningAlert](output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse code scanning alerts: %w", err)
+	}
+	alerts := make([]Alert, 0, len(raw))
+	for _, a := range raw {
+		severity := a.Rule.SecuritySeverityLevel
+		if severity == "" {
+			severity = a.Rule.Severity
+		}
+		summary := a.Rule.Description
+		if text := strings.Join(strings.Fields(a.MostRecentInstance.Message.Text), " "); text != "" {
+			summary = text
+		}
+		alerts = append(alerts, Alert{
+			Kind:     AlertCodeScanning,
+			Number:   a.Number,
+			Rule:     a.Rule.ID,
+			Severity: severity,
+			Summary:  summary,
+			File:     a.MostRecentInstance.Location.Path,
+			Line:     a.MostRecentInstance.Location.StartLine,
+		})
+	}
+	return alerts, nil
+}
+
+func parseDependabotAlerts(output []byte) ([]Alert, error) {
+	raw, err := decodePages[dependabotAlert](output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Dependabot alerts: %w", err)
+	}
+	alerts := make([]Alert, 0, len(raw))
+	for _, a := range raw {
+		pkg := a.Dependency.Package
+		summary := fmt.Sprintf("%s (%s): %s", pkg.Name, pkg.Ecosystem, a.SecurityAdvisory.Summary)
+		if patched := a.SecurityVulnerability.FirstPatchedVersion; patched != nil && patched.Identifier != "" {
+			summary += "; fixed in " + patched.Identifier
+		}
+		alerts = append(alerts, Alert{
+			Kind:     AlertDependabot,
+			Number:   a.Number,
+			Rule:     a.SecurityAdvisory.GHSAID,
+			Severity: a.SecurityAdvisory.Severity,
+			Summary:  summary,
+			File:     a.Dependency.ManifestPath,
+		})
+	}
+	return alerts, nil
+}
+
+// DismissAlert dismisses an alert with one of the reasons GitHub accepts for
+// its kind, recording comment as the justification.
+func DismissAlert(ctx context.Context, owner, repo string, alert Alert, reason, comment string) error {
+	path := fmt.Sprintf("repos/%s/%s/%s/alerts/%d", owner, repo, alert.Kind, alert.Number)
+	err := apiCall(ctx, http.MethodPatch, path, map[string]string{
+		"state":             "dismissed",
+		"dismissed_reason":  reason,
+		"dismissed_comment": comment,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to dismiss %s: %w", alert.ID(), err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package git
+
+import "testing"
+
+func TestParseCodeScanningAlerts(t *testing.T) {
+	// getPages returns one array per page
+	output := []byte(`[{"number": 3, "rule": {"id": "go/sql-injection", "severity": "error", "security_severity_level": "high", "description": "SQL injection"},
+  "most_recent_instance": {"location": {"path": "db.go", "start_line": 42}, "message": {"text": "This query depends on\na user-provided value."}}}]
+[{"number": 7, "rule": {"id": "go/unused", "severity": "note", "description": "Unused variable"}, "most_recent_instance": {"location": {"path": "main.go"}}}]`)
+
+	alerts, err := parseCodeScanningAlerts(output)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	want := []Alert{
+		{Kind: AlertCodeScanning, Number: 3, Rule: "go/sql-injection", Severity: "high", Summary: "This query depends on a user-provided value.", File: "db.go", Line: 42},
+		{Kind: AlertCodeScanning, Number: 7, Rule: "go/unused", Severity: "note", Summary: "Unused variable", File: "main.go"},
+	}
+	if len(alerts) != len(want) {
+		t.Fatalf("expected %d alerts, got %+v", len(want), alerts)
+	}
+	for i := range want {
+		if alerts[i] != want[i] {
+			t.Errorf("alert %d = %+v, want %+v", i, alerts[i], want[i])
+		}
+	}
+	if alerts[0].ID() != "code-scanning#3" {
+		t.Errorf("unexpected ID %q", alerts[0].ID())
+	}
+}
+
+func TestParseDependabotAlerts(t *testing.T) {
+	output := []byte(`[{"number": 12, "dependency": {"package": {"ecosystem": "npm", "name": "lodash"}, "manifest_path": "package-lock.json"},
+  "security_advisory": {"ghsa_id": "GHSA-xxxx", "summary": "Prototype pollution", "severity": "critical"},
+  "security_vulnerability": {"first_patched_version": {"identifier": "4.17.21"}}}]`)
+
+	alerts, err := parseDependabotAlerts(output)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	want := Alert{Kind: AlertDependabot, Number: 12, Rule: "GHSA-xxxx", Severity: "critical", Summary: "lodash (npm): Prototype pollution; fixed in 4.17.21", File: "package-lock.json"}
+	if len(alerts) != 1 || alerts[0] != want {
+		t.Errorf("got %+v, want %+v", alerts, want)
+	}
+
+	if _, err := parseDependabotAlerts([]byte("not json")); err == nil {
+		t.Error("expected an error for invalid output")
+	}
+}
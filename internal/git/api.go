@@ -0,0 +1,368 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// ErrNoToken is returned when no GitHub token can be found.
+var ErrNoToken = errors.New("no GitHub token: set GH_TOKEN, or log in with 'gh auth login'")
+
+// ErrNotFound matches API errors for things that do not exist or are not
+// visible to the token, e.g. errors.Is(err, ErrNotFound).
+var ErrNotFound = errors.New("not found")
+
+// APIError is an error response from the GitHub REST or GraphQL API.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Type       string // GraphQL error type, e.g. NOT_FOUND
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("GitHub API %s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && (e.StatusCode == http.StatusNotFound || e.Type == "NOT_FOUND")
+}
+
+// RateLimitError is returned when GitHub's rate limit resets too late to
+// wait for (see config.RateLimit).
+type RateLimitError struct {
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("GitHub API rate limit exceeded until %s", e.Reset.Local().Format("15:04:05"))
+}
+
+var (
+	// apiMu guards the host and token requests are sent with.
+	apiMu sync.Mutex
+	// apiSlot lets one API request be sent at a time, as GitHub advises to
+	// stay clear of its secondary rate limits. It isn't held while requests
+	// wait to be retried or for a quota to reset.
+	apiSlot    = make(chan struct{}, 1)
+	apiClient  = &http.Client{Timeout: 60 * time.Second}
+	apiHost    string // GitHub Enterprise host, or "" for github.com
+	apiURL     = "https://api.github.com/"
+	graphqlURL = "https://api.github.com/graphql"
+	apiToken   string
+)
+
+// SetHost points every API request at a GitHub Enterprise host. Empty or
+// github.com leaves them on github.com.
+func SetHost(host string) {
+	apiMu.Lock()
+	defer apiMu.Unlock()
+
+	if host == config.DefaultHost {
+		host = ""
+	}
+	apiHost, apiToken = host, ""
+	if host == "" {
+		apiURL, graphqlURL = "https://api.github.com/", "https://api.github.com/graphql"
+	} else {
+		apiURL, graphqlURL = "https://"+host+"/api/v3/", "https://"+host+"/api/graphql"
+	}
+}
+
+// resolveToken returns the token for the API host from the environment, or
+// from gh's login when gh is installed. apiMu must be held.
+func resolveToken(ctx context.Context) (string, error) {
+	if apiToken != "" {
+		return apiToken, nil
+	}
+	envs := []string{"GH_TOKEN", "GITHUB_TOKEN"}
+	if apiHost != "" {
+		envs = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN", "GH_TOKEN", "GITHUB_TOKEN"}
+	}
+	for _, env := range envs {
+		if token := strings.TrimSpace(os.Getenv(env)); token != "" {
+			apiToken = token
+			return apiToken, nil
+		}
+	}
+
+	host := apiHost
+	if host == "" {
+		host = config.DefaultHost
+	}
+	if output, err := exec.CommandContext(ctx, "gh", "auth", "token", "--hostname", host).Output(); err == nil {
+		if token := strings.TrimSpace(string(output)); token != "" {
+			apiToken = token
+			return apiToken, nil
+		}
+	}
+	return "", ErrNoToken
+}
+
+// apiRequest sends a request to path, relative to the API root or a full
+// URL, and returns the response body and headers. in is sent as JSON.
+// Requests wait for a nearly used up quota to reset, requests that hit the
+// rate limit are retried once it resets, if that is soon enough, and
+// transient failures are retried with backoff.
+func apiRequest(ctx context.Context, method, path string, in any, accept string) ([]byte, http.Header, error) {
+	apiMu.Lock()
+	token, err := resolveToken(ctx)
+	url := path
+	if !strings.HasPrefix(path, "https://") && !strings.HasPrefix(path, "http://") {
+		url = apiURL + strings.TrimPrefix(path, "/")
+	}
+	resource := quotaResource(url)
+	apiMu.Unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var body []byte
+	if in != nil {
+		if body, err = json.Marshal(in); err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+	if accept == "" {
+		accept = "application/vnd.github+json"
+	}
+
+	r := currentRetries()
+	attempts, failures := r.Attempts(r.API), 0
+	// retry waits before the next attempt after a transient failure, unless
+	// the attempts are used up
+	retry := func(status int, err error) bool {
+		failures++
+		transientFailures.Add(1)
+		if failures >= attempts || ctx.Err() != nil {
+			return false
+		}
+		delay := retryDelay(failures)
+		recordAPI(ctx, method, path, status, fmt.Errorf("%v, retrying in %s", err, delay.Round(time.Millisecond)))
+		return sleep(ctx, delay)
+	}
+
+	for {
+		if err := waitForQuota(ctx, method, path, resource); err != nil {
+			recordAPI(ctx, method, path, 0, err)
+			return nil, nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Accept", accept)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, data, err := send(ctx, req)
+		if err != nil {
+			if retry(0, err) {
+				continue
+			}
+			err = fmt.Errorf("GitHub API %s %s: %w", method, path, err)
+			recordAPI(ctx, method, path, 0, err)
+			return nil, nil, err
+		}
+		if resource != "" {
+			observeQuota(resp.Header, resource)
+		}
+		if resp.StatusCode < 300 {
+			recordAPI(ctx, method, path, resp.StatusCode, nil)
+			return data, resp.Header, nil
+		}
+
+		if reset, limited := rateLimitReset(resp, time.Now()); limited {
+			transientFailures.Add(1)
+			wait := time.Until(reset)
+			if wait > currentRateLimit().MaxWait() {
+				err := &RateLimitError{Reset: reset}
+				recordAPI(ctx, method, path, resp.StatusCode, err)
+				return nil, nil, err
+			}
+			recordAPI(ctx, method, path, resp.StatusCode, fmt.Errorf("rate limited, retrying in %s", wait.Round(time.Second)))
+			select {
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			case <-time.After(wait):
+			}
+			continue
+		}
+		apiErr := &APIError{Method: method, Path: path, StatusCode: resp.StatusCode, Message: errorMessage(data, resp.Status)}
+		if isTransientStatus(apiErr) && retry(resp.StatusCode, apiErr) {
+			continue
+		}
+		recordAPI(ctx, method, path, resp.StatusCode, apiErr)
+		return nil, nil, apiErr
+	}
+}
+
+// send sends req once the API slot is free, holding it until the response
+// has been read, and returns the response with its body.
+func send(ctx context.Context, req *http.Request) (*http.Response, []byte, error) {
+	select {
+	case apiSlot <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+	defer func() { <-apiSlot }()
+
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return resp, data, nil
+}
+
+// rateLimitReset reports whether resp was refused by the primary or a
+// secondary rate limit, and when to try again.
+func rateLimitReset(resp *http.Response, now time.Time) (time.Time, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}, false
+	}
+	if after := resp.Header.Get("Retry-After"); after != "" {
+		if seconds, err := strconv.Atoi(after); err == nil {
+			return now.Add(time.Duration(seconds) * time.Second), true
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return time.Unix(reset, 0), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isTransientStatus reports whether an error response is worth another try:
+// GitHub briefly unavailable, or a secondary rate limit that didn't say when
+// it resets.
+func isTransientStatus(err *APIError) bool {
+	switch err.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		return strings.Contains(strings.ToLower(err.Message), "secondary rate limit")
+	}
+	return false
+}
+
+// errorMessage returns the message and validation errors of an error
+// response, or status if it has none.
+func errorMessage(data []byte, status string) string {
+	var resp struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+			Field   string `json:"field"`
+			Code    string `json:"code"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil || resp.Message == "" {
+		return status
+	}
+	messages := []string{resp.Message}
+	for _, e := range resp.Errors {
+		switch {
+		case e.Message != "":
+			messages = append(messages, e.Message)
+		case e.Field != "":
+			messages = append(messages, e.Field+" "+e.Code)
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// apiCall sends in as JSON to path and decodes the response into out, if
+// not nil.
+func apiCall(ctx context.Context, method, path string, in, out any) error {
+	data, _, err := apiRequest(ctx, method, path, in, "")
+	if err != nil {
+		return err
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+	return nil
+}
+
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// getPages fetches every page of a list at path, following the Link
+// header. It returns one JSON array per page.
+func getPages(ctx context.Context, path string) ([]byte, error) {
+	var pages []byte
+	for path != "" {
+		data, header, err := apiRequest(ctx, http.MethodGet, path, nil, "")
+		if err != nil {
+			return nil, err
+		}
+		pages = append(append(pages, data...), '\n')
+
+		path = ""
+		if m := nextLinkPattern.FindStringSubmatch(header.Get("Link")); m != nil {
+			path = m[1]
+		}
+	}
+	return pages, nil
+}
+
+// graphQL runs query with vars and decodes its data into out.
+func graphQL(ctx context.Context, query string, vars map[string]any, out any) error {
+	data, _, err := apiRequest(ctx, http.MethodPost, graphqlURL, map[string]any{"query": query, "variables": vars}, "")
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		messages := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			messages[i] = e.Message
+		}
+		err := &APIError{Method: http.MethodPost, Path: "graphql", StatusCode: http.StatusOK, Type: resp.Errors[0].Type, Message: strings.Join(messages, "; ")}
+		recordAPIFailure(ctx, err)
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+	return nil
+}
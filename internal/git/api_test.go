@@ -0,0 +1,254 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// useTestAPI points API requests at handler for the duration of the test.
+func useTestAPI(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	oldURL, oldGraphQL, oldToken := apiURL, graphqlURL, apiToken
+	apiURL, graphqlURL, apiToken = server.URL+"/", server.URL+"/graphql", "secret"
+	quotas = make(map[string]Quota)
+	t.Cleanup(func() {
+		server.Close()
+		apiURL, graphqlURL, apiToken = oldURL, oldGraphQL, oldToken
+		quotas = make(map[string]Quota)
+	})
+	return server
+}
+
+func TestFetchRepositories(t *testing.T) {
+	requests := 0
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if requests == 1 {
+			// A secondary rate limit is waited out and retried
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"You have exceeded a secondary rate limit"}`))
+			return
+		}
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Variables["cursor"] == nil {
+			_, _ = w.Write([]byte(`{"data":{"repositoryOwner":{"repositories":{
+  "nodes":[
+    {"name":"ledger","pushedAt":"2026-09-01T10:00:00Z","defaultBranchRef":{"name":"master"},"primaryLanguage":{"name":"Go"},
+     "repositoryTopics":{"nodes":[{"topic":{"name":"copycat"}},{"topic":{"name":"go"}}]}},
+    {"name":"old-api","isArchived":true,"repositoryTopics":{"nodes":[{"topic":{"name":"copycat"}}]}}],
+  "pageInfo":{"hasNextPage":true,"endCursor":"Y3Vyc29y"}}}}}`))
+			return
+		}
+		if req.Variables["cursor"] != "Y3Vyc29y" {
+			t.Errorf("expected the second page to start at the end of the first, got %v", req.Variables["cursor"])
+		}
+		_, _ = w.Write([]byte(`{"data":{"repositoryOwner":{"repositories":{
+  "nodes":[
+    {"name":"payments-api","defaultBranchRef":{"name":"main"},"repositoryTopics":{"nodes":[{"topic":{"name":"copycat"}}]}},
+    {"name":"website","defaultBranchRef":{"name":"main"},"repositoryTopics":{"nodes":[]}}],
+  "pageInfo":{"hasNextPage":false,"endCursor":"Y3Vyc29yMg"}}}}}`))
+	})
+
+	projects, err := FetchRepositories(config.GitHubConfig{Organization: "acme", AutoDiscoveryTopic: "copycat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Repo)
+	}
+	if !slices.Equal(names, []string{"ledger", "payments-api"}) {
+		t.Errorf("expected the unarchived repos with the topic across both pages, got %v", names)
+	}
+	ledger := projects[0]
+	if ledger.DefaultBranch != "master" || ledger.Language != "Go" || !ledger.PushedAt.Equal(time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the default branch, language and last push to be recorded, got %+v", ledger)
+	}
+	if requests != 3 {
+		t.Errorf("expected the rate-limited request to be retried, got %d requests", requests)
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	reset := time.Now().Add(2 * time.Hour).Unix()
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		case "/repos/acme/busy":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+			w.WriteHeader(http.StatusForbidden)
+		case "/repos/acme/invalid/pulls":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message":"Validation Failed","errors":[{"message":"No commits between main and copycat"}]}`))
+		case "/graphql":
+			_, _ = w.Write([]byte(`{"data":null,"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a Repository"}]}`))
+		}
+	})
+	ctx := context.Background()
+
+	err := apiCall(ctx, http.MethodGet, "repos/acme/missing", nil, nil)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a 404 to be ErrNotFound, got %v", err)
+	}
+
+	var apiErr *APIError
+	err = apiCall(ctx, http.MethodPost, "repos/acme/invalid/pulls", map[string]string{}, nil)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity || apiErr.Message != "Validation Failed; No commits between main and copycat" {
+		t.Errorf("expected the validation errors in the APIError, got %v", err)
+	}
+
+	if err := graphQL(ctx, "query { viewer { login } }", nil, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a NOT_FOUND GraphQL error to be ErrNotFound, got %v", err)
+	}
+
+	var rateLimit *RateLimitError
+	if err := apiCall(ctx, http.MethodGet, "repos/acme/busy", nil, nil); !errors.As(err, &rateLimit) || rateLimit.Reset.Unix() != reset {
+		t.Errorf("expected a rate limit too far off to wait for to be a RateLimitError, got %v", err)
+	}
+	// the used up quota holds back the next request instead of sending it
+	if err := apiCall(ctx, http.MethodGet, "repos/acme/missing", nil, nil); !errors.As(err, &rateLimit) {
+		t.Errorf("expected a request against a used up quota to be a RateLimitError, got %v", err)
+	}
+}
+
+func TestWaitingRequestsDontHoldUpOthers(t *testing.T) {
+	limited := make(chan struct{}, 1)
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/acme/limited" {
+			select {
+			case limited <- struct{}{}:
+				// The first request waits a second for the limit to reset
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			default:
+			}
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+	ctx := context.Background()
+
+	waited := make(chan error)
+	go func() { waited <- apiCall(ctx, http.MethodGet, "repos/acme/limited", nil, nil) }()
+	<-limited
+	started := time.Now()
+	if err := apiCall(ctx, http.MethodGet, "repos/acme/other", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("expected a request not to wait for another's rate limit, took %s", elapsed)
+	}
+	if err := <-waited; err != nil {
+		t.Errorf("expected the rate limited request to go through once reset, got %v", err)
+	}
+
+	// A request waiting for its turn gives up when cancelled
+	apiSlot <- struct{}{}
+	defer func() { <-apiSlot }()
+	cancelled, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := apiCall(cancelled, http.MethodGet, "repos/acme/other", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a cancelled request to stop waiting, got %v", err)
+	}
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repoPath := t.TempDir()
+	for _, args := range [][]string{{"init", "-q", "-b", "main"}, {"remote", "add", "origin", "git@github.com:acme/payments-api.git"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+
+	bodies := make(map[string]map[string]any)
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies[r.Method+" "+r.URL.Path] = body
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repos/acme/payments-api/milestones":
+			_, _ = w.Write([]byte(`[{"number":4,"title":"Q3"},{"number":5,"title":"Q4"}]`))
+		case "POST /repos/acme/payments-api/pulls":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number":12,"html_url":"https://github.com/acme/payments-api/pull/12"}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	project := config.Project{Repo: "payments-api", TargetBranch: "develop"}
+	opts := PullRequestOptions{Draft: true, Reviewers: []string{"@jane", "acme/payments"}, Labels: []string{"security"}, Milestone: "Q4"}
+	output, err := CreatePullRequest(context.Background(), project, repoPath, "copycat-123", "Bump deps", "Body", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(output) != "https://github.com/acme/payments-api/pull/12\n" {
+		t.Errorf("unexpected output %q", output)
+	}
+
+	pr := bodies["POST /repos/acme/payments-api/pulls"]
+	if pr["base"] != "develop" || pr["head"] != "copycat-123" || pr["draft"] != true {
+		t.Errorf("unexpected PR request %v", pr)
+	}
+	issue := bodies["PATCH /repos/acme/payments-api/issues/12"]
+	if fmt.Sprint(issue["labels"]) != "[copycat security]" || issue["milestone"] != float64(5) {
+		t.Errorf("expected the labels and milestone to be set in one request, got %v", issue)
+	}
+	reviews := bodies["POST /repos/acme/payments-api/pulls/12/requested_reviewers"]
+	if fmt.Sprint(reviews["reviewers"]) != "[jane]" || fmt.Sprint(reviews["team_reviewers"]) != "[payments]" {
+		t.Errorf("expected users and teams to be requested apart, got %v", reviews)
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+	})
+
+	transcript := &Transcript{}
+	ctx := WithTranscript(context.Background(), transcript)
+	_ = apiCall(ctx, http.MethodGet, "repos/acme/missing", nil, nil)
+	if _, err := runGit(ctx, t.TempDir(), "init", "-q", "-b", "main"); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = runGit(context.Background(), t.TempDir(), "status")
+
+	if got := transcript.API(); got != "GET repos/acme/missing → 404\n✗ GitHub API GET repos/acme/missing: 404 Not Found\n" {
+		t.Errorf("unexpected API transcript %q", got)
+	}
+	if got := transcript.Git(); got != "$ git init -q -b main\n" {
+		t.Errorf("expected only the git command run with the transcript, got %q", got)
+	}
+}
@@ -18,14 +18,18 @@ var ErrBranchExists = errors.New("branch already exists")
 func CheckLocalChanges(ctx context.Context, targetPath string) ([]byte, error) {
 	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain")
 	cmd.Dir = targetPath
-	return cmd.CombinedOutput()
+	return combinedOutput(ctx, cmd)
 }
 
-func PushChanges(ctx context.Context, project config.Project, targetPath string, branchName string, prTitle string) error {
+// PushChanges commits every change in targetPath with commitMessage, signed
+// when signing is configured, and pushes branchName. Repos with a push URL
+// are pushed there; repos the user cannot push to are forked and the branch
+// pushed to the fork.
+func PushChanges(ctx context.Context, project config.Project, targetPath string, branchName string, commitMessage string, signing config.CommitSigning) error {
 	// Check if there are changes to commit
 	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain")
 	cmd.Dir = targetPath
-	output, err := cmd.CombinedOutput()
+	output, err := combinedOutput(ctx, cmd)
 	if err != nil {
 		return fmt.Errorf("failed to check git status in %s: %v", project.Repo, err)
 	}
@@ -37,24 +41,35 @@ func PushChanges(ctx context.Context, project config.Project, targetPath string,
 	// Add all changes
 	cmd = exec.CommandContext(ctx, "git", "add", "-A")
 	cmd.Dir = targetPath
-	_, err = cmd.CombinedOutput()
+	_, err = combinedOutput(ctx, cmd)
 	if err != nil {
 		return fmt.Errorf("failed to add changes in %s: %v", project.Repo, err)
 	}
 
 	// Commit changes
-	commitMessage := prTitle
-	cmd = exec.CommandContext(ctx, "git", "commit", "-m", commitMessage)
+	cmd = exec.CommandContext(ctx, "git", append(signingArgs(signing), "commit", "-m", commitMessage)...)
 	cmd.Dir = targetPath
-	output, err = cmd.CombinedOutput()
+	output, err = combinedOutput(ctx, cmd)
 	if err != nil {
 		return fmt.Errorf("Failed to commit changes in %s: %v\nOutput: %s", project.Repo, err, string(output))
 	}
+	if signing != (config.CommitSigning{}) {
+		if err := verifySigned(ctx, targetPath); err != nil {
+			return fmt.Errorf("not pushing %s: %v", project.Repo, err)
+		}
+	}
 
 	// Push branch
-	cmd = exec.CommandContext(ctx, "git", "push", "-u", "origin", branchName)
-	cmd.Dir = targetPath
-	output, err = cmd.CombinedOutput()
+	remote, err := pushRemote(ctx, project, targetPath)
+	if err != nil {
+		return fmt.Errorf("Failed to push branch in %s: %v", project.Repo, err)
+	}
+	r := currentRetries()
+	output, err = retryGit(ctx, r.Attempts(r.Push), func() *exec.Cmd {
+		cmd := exec.CommandContext(ctx, "git", "push", "-u", remote, branchName)
+		cmd.Dir = targetPath
+		return cmd
+	})
 	if err != nil {
 		return fmt.Errorf("Failed to push branch in %s: %v\nOutput: %s", project.Repo, err, string(output))
 	}
@@ -64,9 +79,12 @@ func PushChanges(ctx context.Context, project config.Project, targetPath string,
 
 func SelectOrCreateBranch(ctx context.Context, repoPath, prTitle, branchStrategy, specifiedBranch string) (string, error) {
 	// Fetch latest branches from remote
-	fetchCmd := exec.CommandContext(ctx, "git", "fetch", "origin")
-	fetchCmd.Dir = repoPath
-	fetchCmd.CombinedOutput()
+	r := currentRetries()
+	retryGit(ctx, r.Attempts(r.Fetch), func() *exec.Cmd {
+		fetchCmd := exec.CommandContext(ctx, "git", "fetch", "origin")
+		fetchCmd.Dir = repoPath
+		return fetchCmd
+	})
 
 	// Handle "Specify branch name (reuse if exists)" strategy
 	if strings.Contains(branchStrategy, "reuse if exists") {
@@ -87,17 +105,17 @@ func checkoutOrCreateBranch(ctx context.Context, repoPath, branchName string) (s
 	// Try to checkout the branch
 	checkoutCmd := exec.CommandContext(ctx, "git", "checkout", branchName)
 	checkoutCmd.Dir = repoPath
-	output, err := checkoutCmd.CombinedOutput()
+	output, err := combinedOutput(ctx, checkoutCmd)
 	if err != nil {
 		// If local checkout fails, try checking out from remote
 		checkoutCmd = exec.CommandContext(ctx, "git", "checkout", "-b", branchName, fmt.Sprintf("origin/%s", branchName))
 		checkoutCmd.Dir = repoPath
-		output, err = checkoutCmd.CombinedOutput()
+		output, err = combinedOutput(ctx, checkoutCmd)
 		if err != nil {
 			// Branch doesn't exist locally or remotely, create it
 			createCmd := exec.CommandContext(ctx, "git", "checkout", "-b", branchName)
 			createCmd.Dir = repoPath
-			output, err = createCmd.CombinedOutput()
+			output, err = combinedOutput(ctx, createCmd)
 			if err != nil {
 				return "", fmt.Errorf("failed to create branch: %w\nOutput: %s", err, string(output))
 			}
@@ -106,9 +124,12 @@ func checkoutOrCreateBranch(ctx context.Context, repoPath, branchName string) (s
 	}
 
 	// Pull latest changes if branch already existed
-	pullCmd := exec.CommandContext(ctx, "git", "pull", "origin", branchName)
-	pullCmd.Dir = repoPath
-	pullCmd.CombinedOutput()
+	r := currentRetries()
+	retryGit(ctx, r.Attempts(r.Fetch), func() *exec.Cmd {
+		pullCmd := exec.CommandContext(ctx, "git", "pull", "origin", branchName)
+		pullCmd.Dir = repoPath
+		return pullCmd
+	})
 
 	return branchName, nil
 }
@@ -121,7 +142,7 @@ func createBranchOrSkip(ctx context.Context, repoPath, branchName string) (strin
 
 	createCmd := exec.CommandContext(ctx, "git", "checkout", "-b", branchName)
 	createCmd.Dir = repoPath
-	output, err := createCmd.CombinedOutput()
+	output, err := combinedOutput(ctx, createCmd)
 	if err != nil {
 		return "", fmt.Errorf("failed to create branch: %w\nOutput: %s", err, string(output))
 	}
@@ -155,10 +176,40 @@ func createNewBranch(ctx context.Context, repoPath, prTitle string) (string, err
 
 	cmd := exec.CommandContext(ctx, "git", "checkout", "-b", newBranch)
 	cmd.Dir = repoPath
-	output, err := cmd.CombinedOutput()
+	output, err := combinedOutput(ctx, cmd)
 	if err != nil {
 		return "", fmt.Errorf("failed to create branch: %w\nOutput: %s", err, string(output))
 	}
 
 	return newBranch, nil
 }
+
+// DiscardChanges resets the working tree to HEAD and deletes untracked files,
+// leaving ignored files alone.
+func DiscardChanges(ctx context.Context, targetPath string) error {
+	for _, args := range [][]string{{"reset", "--hard", "-q"}, {"clean", "-fdq"}} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = targetPath
+		if output, err := combinedOutput(ctx, cmd); err != nil {
+			return fmt.Errorf("failed to discard changes: %v (%s)", err, string(output))
+		}
+	}
+	return nil
+}
+
+// CaptureDiff stages all working tree changes and returns the staged diff.
+func CaptureDiff(ctx context.Context, targetPath string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "add", "-A")
+	cmd.Dir = targetPath
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %v (%s)", err, string(output))
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "diff", "--cached")
+	cmd.Dir = targetPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to capture diff: %w", err)
+	}
+	return string(output), nil
+}
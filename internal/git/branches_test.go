@@ -0,0 +1,96 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestDiscardChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("main.go", "package main\n")
+	write(".gitignore", "local.env\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "init")
+
+	write("main.go", "package broken\n")
+	write("new.go", "package main\n")
+	write("local.env", "TOKEN=1\n")
+
+	if err := DiscardChanges(context.Background(), repo); err != nil {
+		t.Fatalf("DiscardChanges failed: %v", err)
+	}
+	if status, _ := CheckLocalChanges(context.Background(), repo); len(status) != 0 {
+		t.Errorf("expected a clean tree, got %s", status)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "local.env")); err != nil {
+		t.Error("expected ignored files to be kept")
+	}
+}
+
+func TestPushChangesToPushURL(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	mirror := filepath.Join(root, "mirror.git")
+	clone := filepath.Join(root, "clone")
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+		return string(out)
+	}
+	run(root, "init", "-q", "--bare", "-b", "main", origin)
+	run(root, "init", "-q", "--bare", "-b", "main", mirror)
+	run(root, "clone", "-q", origin, clone)
+	run(clone, "checkout", "-q", "-b", "copycat-1")
+	if err := os.WriteFile(filepath.Join(clone, "README.md"), []byte("# api\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	project := config.Project{Repo: "api", PushURL: mirror}
+	if err := PushChanges(context.Background(), project, clone, "copycat-1", "Add README", config.CommitSigning{}); err != nil {
+		t.Fatalf("PushChanges failed: %v", err)
+	}
+	if out := run(mirror, "branch", "--list", "copycat-1"); out == "" {
+		t.Error("expected the branch to be pushed to the push URL")
+	}
+	if out := run(origin, "branch", "--list", "copycat-1"); out != "" {
+		t.Error("expected origin to be left alone")
+	}
+}
@@ -0,0 +1,125 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Checks summarizes the status checks reported on a pull request.
+type Checks struct {
+	Passed  int
+	Failed  int
+	Pending int
+	Failing []string // names of the failed checks
+}
+
+// Done reports whether every check has finished.
+func (c Checks) Done() bool {
+	return c.Pending == 0
+}
+
+// Summary describes the checks in a few words, e.g. "CI green ✅".
+func (c Checks) Summary() string {
+	switch {
+	case c.Failed > 0:
+		return fmt.Sprintf("CI red ❌ %s", strings.Join(c.Failing, ", "))
+	case c.Pending > 0:
+		return fmt.Sprintf("CI pending ⏳ %d of %d", c.Pending, c.Passed+c.Pending)
+	case c.Passed > 0:
+		return "CI green ✅"
+	}
+	return "no CI checks"
+}
+
+// FetchChecks returns the check runs and commit statuses on the head commit
+// of the PR at url.
+func FetchChecks(ctx context.Context, url string) (Checks, error) {
+	pr, err := fetchPullRequest(ctx, url)
+	if err != nil {
+		return Checks{}, fmt.Errorf("failed to fetch checks for %s: %w", url, err)
+	}
+	path, _ := pullRequestPath(url)
+	commit := fmt.Sprintf("%s/commits/%s", path[:strings.LastIndex(path, "/pulls/")], pr.Head.SHA)
+
+	var runs struct {
+		CheckRuns []struct {
+			Name       string `json:"name"`
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+		} `json:"check_runs"`
+	}
+	if err := apiCall(ctx, http.MethodGet, commit+"/check-runs?per_page=100", nil, &runs); err != nil {
+		return Checks{}, fmt.Errorf("failed to fetch checks for %s: %w", url, err)
+	}
+	var status struct {
+		Statuses []struct {
+			Context string `json:"context"`
+			State   string `json:"state"`
+		} `json:"statuses"`
+	}
+	if err := apiCall(ctx, http.MethodGet, commit+"/status?per_page=100", nil, &status); err != nil {
+		return Checks{}, fmt.Errorf("failed to fetch checks for %s: %w", url, err)
+	}
+
+	var results []checkResult
+	for _, run := range runs.CheckRuns {
+		results = append(results, checkResult{Name: run.Name, Bucket: checkRunBucket(run.Status, run.Conclusion)})
+	}
+	for _, s := range status.Statuses {
+		results = append(results, checkResult{Name: s.Context, Bucket: statusBucket(s.State)})
+	}
+	return countChecks(results), nil
+}
+
+// checkResult is a check run or commit status, sorted into a bucket: pass,
+// fail, pending, skipping or cancel.
+type checkResult struct {
+	Name   string
+	Bucket string
+}
+
+// checkRunBucket sorts a check run by its status and conclusion.
+func checkRunBucket(status, conclusion string) string {
+	if status != "completed" {
+		return "pending"
+	}
+	switch conclusion {
+	case "success", "neutral":
+		return "pass"
+	case "skipped":
+		return "skipping"
+	case "cancelled":
+		return "cancel"
+	}
+	return "fail"
+}
+
+// statusBucket sorts a commit status by its state.
+func statusBucket(state string) string {
+	switch state {
+	case "success":
+		return "pass"
+	case "pending":
+		return "pending"
+	}
+	return "fail"
+}
+
+// countChecks tallies the results by bucket.
+func countChecks(results []checkResult) Checks {
+	var checks Checks
+	for _, run := range results {
+		switch run.Bucket {
+		case "pass":
+			checks.Passed++
+		case "fail", "cancel":
+			checks.Failed++
+			checks.Failing = append(checks.Failing, run.Name)
+		case "pending":
+			checks.Pending++
+		}
+	}
+	return checks
+}
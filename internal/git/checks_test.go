@@ -0,0 +1,41 @@
+package git
+
+import "testing"
+
+func TestCountChecks(t *testing.T) {
+	checks := countChecks([]checkResult{
+		{"build", checkRunBucket("completed", "success")},
+		{"lint", checkRunBucket("completed", "failure")},
+		{"e2e", checkRunBucket("in_progress", "")},
+		{"docs", checkRunBucket("completed", "skipped")},
+		{"deploy", checkRunBucket("completed", "cancelled")},
+		{"ci/jenkins", statusBucket("error")},
+		{"coverage", statusBucket("success")},
+	})
+	if checks.Passed != 2 || checks.Failed != 3 || checks.Pending != 1 {
+		t.Errorf("unexpected counts %+v", checks)
+	}
+	if checks.Done() {
+		t.Error("expected checks with a pending run not to be done")
+	}
+	if got := checks.Summary(); got != "CI red ❌ lint, deploy, ci/jenkins" {
+		t.Errorf("unexpected summary %q", got)
+	}
+}
+
+func TestChecksSummary(t *testing.T) {
+	tests := []struct {
+		checks Checks
+		want   string
+	}{
+		{Checks{Passed: 3}, "CI green ✅"},
+		{Checks{Passed: 1, Pending: 2}, "CI pending ⏳ 2 of 3"},
+		{Checks{Failed: 1, Pending: 1, Failing: []string{"test"}}, "CI red ❌ test"},
+		{Checks{}, "no CI checks"},
+	}
+	for _, tt := range tests {
+		if got := tt.checks.Summary(); got != tt.want {
+			t.Errorf("%+v: expected %q, got %q", tt.checks, tt.want, got)
+		}
+	}
+}
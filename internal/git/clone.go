@@ -0,0 +1,110 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+var (
+	cloneMu       sync.Mutex
+	cloneProtocol = config.CloneSSH
+)
+
+// SetCloneProtocol sets how Clone fetches repos: config.CloneSSH,
+// config.CloneHTTPS or config.CloneGH.
+func SetCloneProtocol(protocol string) {
+	cloneMu.Lock()
+	defer cloneMu.Unlock()
+	cloneProtocol = protocol
+}
+
+// Clone clones repoURL into path, passing args (e.g. --no-checkout) to git
+// clone. HTTPS clones are set up to authenticate with the GitHub token, and
+// keep doing so for later fetches and pushes, so no SSH agent is needed.
+func Clone(ctx context.Context, repoURL, path string, args ...string) error {
+	cloneMu.Lock()
+	protocol := cloneProtocol
+	cloneMu.Unlock()
+
+	if protocol == config.CloneGH {
+		// gh authenticates the clone itself; the helper makes later fetches
+		// and pushes use its login too
+		args = append([]string{"-c", "credential.helper=", "-c", "credential.helper=!gh auth git-credential"}, args...)
+		return runClone(ctx, func() *exec.Cmd {
+			return exec.CommandContext(ctx, "gh", append([]string{"repo", "clone", repoURL, path, "--"}, args...)...)
+		})
+	}
+	if protocol == config.CloneHTTPS {
+		helper, err := credentialHelper()
+		if err != nil {
+			return err
+		}
+		args = append(helper, args...)
+	}
+	return runClone(ctx, func() *exec.Cmd {
+		return exec.CommandContext(ctx, "git", append(append([]string{"clone"}, args...), repoURL, path)...)
+	})
+}
+
+// runClone runs the clone built by newCmd, again on transient failures; git
+// removes the directory of a failed clone, so each attempt starts afresh.
+func runClone(ctx context.Context, newCmd func() *exec.Cmd) error {
+	r := currentRetries()
+	if output, err := retryGit(ctx, r.Attempts(r.Clone), newCmd); err != nil {
+		return fmt.Errorf("clone failed: %v (%s)", err, string(output))
+	}
+	return nil
+}
+
+// credentialHelper returns the clone options that make git ask copycat for
+// the GitHub token, replacing any helper configured globally. The token
+// itself never lands in the clone's config or the transcript.
+func credentialHelper() ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate copycat for the git credential helper: %w", err)
+	}
+	quoted := "'" + strings.ReplaceAll(exe, "'", `'\''`) + "'"
+	return []string{"-c", "credential.helper=", "-c", "credential.helper=!" + quoted + " git-credential"}, nil
+}
+
+// RunCredentialHelper answers a git credential request (see
+// gitcredentials(7)) read from in with the GitHub token for the requested
+// host. Only "get" is answered; git's "store" and "erase" are ignored.
+func RunCredentialHelper(ctx context.Context, operation string, in io.Reader, out io.Writer) error {
+	request := make(map[string]string)
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			request[key] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if operation != "get" || request["protocol"] != "https" {
+		return nil
+	}
+
+	SetHost(request["host"])
+	apiMu.Lock()
+	token, err := resolveToken(ctx)
+	apiMu.Unlock()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "username=x-access-token\npassword=%s\n", token)
+	return err
+}
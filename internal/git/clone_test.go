@@ -0,0 +1,63 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestRunCredentialHelper(t *testing.T) {
+	t.Setenv("GH_TOKEN", "ghp_secret")
+	SetHost("")
+	defer SetHost("")
+
+	var out strings.Builder
+	request := "protocol=https\nhost=github.com\npath=acme/payments-api.git\n\n"
+	if err := RunCredentialHelper(context.Background(), "get", strings.NewReader(request), &out); err != nil {
+		t.Fatalf("RunCredentialHelper failed: %v", err)
+	}
+	if out.String() != "username=x-access-token\npassword=ghp_secret\n" {
+		t.Errorf("expected the token as the password, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := RunCredentialHelper(context.Background(), "store", strings.NewReader(request), &out); err != nil || out.Len() != 0 {
+		t.Errorf("expected store to be ignored, got %q (%v)", out.String(), err)
+	}
+}
+
+func TestHTTPSCloneUsesCredentialHelper(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	SetCloneProtocol(config.CloneHTTPS)
+	defer SetCloneProtocol(config.CloneSSH)
+
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin")
+	clone := filepath.Join(root, "clone")
+	if err := os.MkdirAll(origin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "init", "-q", origin).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v (%s)", err, out)
+	}
+
+	if err := Clone(context.Background(), origin, clone); err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+	cmd := exec.Command("git", "config", "--local", "--get-all", "credential.helper")
+	cmd.Dir = clone
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("expected a credential helper in the clone's config: %v", err)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(string(out)), " git-credential") {
+		t.Errorf("expected copycat to be the clone's credential helper, got %q", out)
+	}
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// ErrEmptyRepo is returned for a repo that has no commits yet.
+var ErrEmptyRepo = errors.New("uninitialized repo: no commits yet")
+
+// CheckHead returns an error when the clone at repoPath has nothing checked
+// out to branch from: ErrEmptyRepo for a repo without commits, or an error
+// naming the branch when the remote HEAD points to a branch that does not
+// exist.
+func CheckHead(ctx context.Context, repoPath string) error {
+	if gitOutput(ctx, repoPath, "rev-parse", "--verify", "-q", "HEAD^{commit}") != "" {
+		return nil
+	}
+	if gitOutput(ctx, repoPath, "for-each-ref", "--count=1", "refs/remotes", "refs/heads") == "" {
+		return ErrEmptyRepo
+	}
+	head := gitOutput(ctx, repoPath, "symbolic-ref", "--short", "HEAD")
+	return fmt.Errorf("default branch %s has no commits; point the repo's default branch at an existing branch", head)
+}
+
+// InitializeRepo commits a README to the empty repo cloned at targetPath and
+// pushes it as the default branch, so branches can be created from it.
+func InitializeRepo(ctx context.Context, project config.Project, targetPath string, signing config.CommitSigning) error {
+	readme := filepath.Join(targetPath, "README.md")
+	if _, err := os.Stat(readme); os.IsNotExist(err) {
+		if err := os.WriteFile(readme, []byte("# "+project.Repo+"\n"), 0o644); err != nil {
+			return fmt.Errorf("failed to scaffold %s: %w", project.Repo, err)
+		}
+	}
+
+	branch := gitOutput(ctx, targetPath, "symbolic-ref", "--short", "HEAD")
+	r := currentRetries()
+	for _, args := range [][]string{
+		{"add", "README.md"},
+		append(signingArgs(signing), "commit", "-m", "Initial commit"),
+		{"push", "-u", "origin", branch},
+		{"remote", "set-head", "origin", branch},
+	} {
+		attempts := 1
+		if args[0] == "push" {
+			attempts = r.Attempts(r.Push)
+		}
+		output, err := retryGit(ctx, attempts, func() *exec.Cmd {
+			cmd := exec.CommandContext(ctx, "git", args...)
+			cmd.Dir = targetPath
+			return cmd
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create the initial commit of %s: %v (%s)", project.Repo, err, strings.TrimSpace(string(output)))
+		}
+	}
+	return nil
+}
+
+// gitOutput runs git in repoPath and returns its trimmed output, or "" if
+// it fails.
+func gitOutput(ctx context.Context, repoPath string, args ...string) string {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,60 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestCheckHeadAndInitializeRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	clone := filepath.Join(root, "clone")
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+	run(root, "init", "-q", "--bare", "-b", "main", origin)
+	run(root, "clone", "-q", origin, clone)
+
+	ctx := context.Background()
+	if err := CheckHead(ctx, clone); !errors.Is(err, ErrEmptyRepo) {
+		t.Fatalf("expected a clone of an empty repo to be uninitialized, got %v", err)
+	}
+
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	if err := InitializeRepo(ctx, config.Project{Repo: "payments-api"}, clone, config.CommitSigning{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckHead(ctx, clone); err != nil {
+		t.Errorf("expected the initialized repo to have a HEAD, got %v", err)
+	}
+	if readme, _ := os.ReadFile(filepath.Join(clone, "README.md")); string(readme) != "# payments-api\n" {
+		t.Errorf("unexpected README %q", readme)
+	}
+
+	// A remote HEAD pointing to a missing branch leaves nothing checked out
+	run(origin, "symbolic-ref", "HEAD", "refs/heads/develop")
+	other := filepath.Join(root, "other")
+	run(root, "clone", "-q", origin, other)
+	if err := CheckHead(ctx, other); err == nil || errors.Is(err, ErrEmptyRepo) || !strings.Contains(err.Error(), "develop") {
+		t.Errorf("expected the missing default branch to be named, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// forkRemote is the remote of the user's fork, which branches are pushed to
+// for repos the user cannot push to.
+const forkRemote = "fork"
+
+// mirrorRemote is the remote of a project's push URL, which branches are
+// pushed to for repos whose canonical remote is not GitHub.
+const mirrorRemote = "mirror"
+
+// pushRemote returns the remote to push to from repoPath: the project's push
+// URL when it has one, origin, or the user's fork of it when they lack write
+// access, forking the repo first if needed.
+func pushRemote(ctx context.Context, project config.Project, repoPath string) (string, error) {
+	if project.PushURL != "" {
+		// GitHub is a read-only mirror of such repos, so its permission is moot
+		return mirrorRemote, setRemote(ctx, repoPath, mirrorRemote, project.PushURL)
+	}
+	if forkOwner(ctx, repoPath) != "" {
+		return forkRemote, nil
+	}
+	// When the permission cannot be read the push to origin reports the problem
+	origin := remoteURL(ctx, repoPath, "origin")
+	owner, name := parseRemote(origin)
+	if owner == "" {
+		return "origin", nil
+	}
+	var result struct {
+		Repository struct {
+			ViewerPermission string `json:"viewerPermission"`
+		} `json:"repository"`
+	}
+	err := graphQL(ctx, `query($owner: String!, $name: String!) { repository(owner: $owner, name: $name) { viewerPermission } }`,
+		map[string]any{"owner": owner, "name": name}, &result)
+	if err != nil || canPush(result.Repository.ViewerPermission) {
+		return "origin", nil
+	}
+
+	var fork GitHubRepo
+	if err := apiCall(ctx, http.MethodPost, fmt.Sprintf("repos/%s/%s/forks", owner, name), map[string]any{}, &fork); err != nil {
+		return "", fmt.Errorf("no write access, and forking failed: %w", err)
+	}
+	// The fork is pushed to the same way as origin: over SSH or HTTPS
+	forkURL := fork.SSHURL
+	if strings.HasPrefix(origin, "https://") {
+		forkURL = fork.CloneURL
+	}
+	if err := setRemote(ctx, repoPath, forkRemote, forkURL); err != nil {
+		return "", err
+	}
+	return forkRemote, nil
+}
+
+// setRemote points remote in repoPath at url, adding it if needed.
+func setRemote(ctx context.Context, repoPath, remote, url string) error {
+	args := []string{"remote", "add", remote, url}
+	if remoteURL(ctx, repoPath, remote) != "" {
+		args[1] = "set-url"
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to add the %s remote: %v (%s)", remote, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// canPush reports whether a repository permission allows pushing branches.
+func canPush(permission string) bool {
+	switch strings.TrimSpace(permission) {
+	case "ADMIN", "MAINTAIN", "WRITE":
+		return true
+	}
+	return false
+}
+
+// forkOwner returns the owner of the fork remote in repoPath, or "" when the
+// branch is pushed to origin.
+func forkOwner(ctx context.Context, repoPath string) string {
+	return remoteOwner(remoteURL(ctx, repoPath, forkRemote))
+}
+
+// remoteURL returns the URL of remote in repoPath, or "" if it has none.
+func remoteURL(ctx context.Context, repoPath, remote string) string {
+	return gitOutput(ctx, repoPath, "remote", "get-url", remote)
+}
+
+// originRepo returns the owner and name of the origin remote in repoPath.
+func originRepo(ctx context.Context, repoPath string) (string, string, error) {
+	url := remoteURL(ctx, repoPath, "origin")
+	owner, name := parseRemote(url)
+	if owner == "" {
+		return "", "", fmt.Errorf("origin %q is not a GitHub repository", url)
+	}
+	return owner, name, nil
+}
+
+var remotePattern = regexp.MustCompile(`^(?:[a-z+]+://[^/]+/|[^@/]+@[^:/]+:)([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// parseRemote returns the owner and name of the repo at an HTTPS or SSH
+// remote URL, on github.com or a GitHub Enterprise host.
+func parseRemote(url string) (owner, name string) {
+	if m := remotePattern.FindStringSubmatch(strings.TrimSpace(url)); m != nil {
+		return m[1], m[2]
+	}
+	return "", ""
+}
+
+// remoteOwner returns the owner in an HTTPS or SSH remote URL.
+func remoteOwner(url string) string {
+	owner, _ := parseRemote(url)
+	return owner
+}
@@ -0,0 +1,45 @@
+package git
+
+import "testing"
+
+func TestCanPush(t *testing.T) {
+	for _, permission := range []string{"ADMIN", "MAINTAIN", "WRITE\n"} {
+		if !canPush(permission) {
+			t.Errorf("expected %q to allow pushing", permission)
+		}
+	}
+	for _, permission := range []string{"TRIAGE", "READ", ""} {
+		if canPush(permission) {
+			t.Errorf("expected %q not to allow pushing", permission)
+		}
+	}
+}
+
+func TestRemoteOwner(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/jane/payments-api.git\n":    "jane",
+		"git@github.com:jane/payments-api.git":          "jane",
+		"git@github.mycorp.com:jane/payments-api.git":   "jane",
+		"ssh://git@github.mycorp.com/jane/payments-api": "jane",
+		"/tmp/payments-api":                             "",
+	}
+	for url, want := range tests {
+		if got := remoteOwner(url); got != want {
+			t.Errorf("%q: expected %q, got %q", url, want, got)
+		}
+	}
+}
+
+func TestParseRemote(t *testing.T) {
+	tests := map[string][2]string{
+		"https://github.com/jane/payments-api.git\n":    {"jane", "payments-api"},
+		"git@github.mycorp.com:jane/payments-api.git":   {"jane", "payments-api"},
+		"ssh://git@github.mycorp.com/jane/payments-api": {"jane", "payments-api"},
+		"/tmp/payments-api":                             {"", ""},
+	}
+	for url, want := range tests {
+		if owner, name := parseRemote(url); owner != want[0] || name != want[1] {
+			t.Errorf("%q: expected %v, got %q %q", url, want, owner, name)
+		}
+	}
+}
@@ -0,0 +1,404 @@
+// Package gittest provides an in-memory GitHub for tests.
+package gittest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/git"
+	"github.com/saltpay/copycat/v2/internal/issuetemplate"
+)
+
+// Push is a branch pushed to the fake.
+type Push struct {
+	Repo    string
+	Branch  string
+	Message string   // commit message
+	Files   []string // paths changed in the pushed commit
+	Signed  bool     // commit signing was asked for
+}
+
+// PullRequest is a pull request opened on the fake.
+type PullRequest struct {
+	Repo      string
+	Branch    string
+	Base      string
+	Title     string
+	Body      string
+	URL       string
+	Draft     bool
+	Reviewers []string
+	Labels    []string
+	Milestone string
+	AutoMerge bool // auto-merge was enabled
+}
+
+// Issue is an issue opened on the fake.
+type Issue struct {
+	Repo      string
+	Title     string
+	Body      string
+	URL       string
+	Labels    []string
+	Milestone string
+	Assignees []string
+}
+
+// Fake is a git.Provider that keeps GitHub in memory. Clones are fresh local
+// repos seeded with Files, so the git commands run on them work as usual;
+// pushes and pull requests are only recorded. It is safe for concurrent use.
+type Fake struct {
+	Projects    []config.Project                    // returned by FetchRepositories, if they pass the discovery filter and are not excluded
+	Files       map[string]string                   // committed to every clone, by path
+	Empty       map[string]bool                     // repos cloned without any commits
+	Moved       map[string]bool                     // repos whose base branch moves before the push
+	Alerts      map[string][]git.Alert              // open alerts by repo
+	Errors      map[string]error                    // makes pushes to a repo fail
+	Checks      map[string]git.Checks               // status checks on a repo's pull requests
+	Diffs       map[string]string                   // diffs of existing pull requests, by URL
+	Quotas      []git.Quota                         // the token's API rate limits
+	IssueErrors map[string]error                    // makes issue creation in a repo fail
+	Teams       map[string][]string                 // members of org/team slugs
+	Unassigned  map[string]bool                     // users who cannot be assigned issues
+	Templates   map[string][]issuetemplate.Template // issue templates by repo
+	IssueStates map[string]git.IssueState           // issues by URL; open when missing
+
+	mu           sync.Mutex
+	pushes       []Push
+	pullRequests []PullRequest
+	dismissed    []git.Alert
+	issues       []Issue
+}
+
+var _ git.Provider = (*Fake)(nil)
+
+func (f *Fake) FetchRepositories(githubCfg config.GitHubConfig) ([]config.Project, error) {
+	var projects []config.Project
+	for _, p := range f.Projects {
+		if githubCfg.Discovery.Matches(p, time.Now()) && !githubCfg.IsExcluded(p.Repo) {
+			projects = append(projects, p)
+		}
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("no unarchived repositories found in organization '%s'", githubCfg.Organization)
+	}
+	return projects, nil
+}
+
+// Clone creates a repo at path with Files committed on main, whatever the
+// URL, or a repo without commits for the repos in Empty.
+func (f *Fake) Clone(ctx context.Context, repoURL, path string, args ...string) error {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return err
+	}
+	if f.Empty[strings.TrimSuffix(filepath.Base(repoURL), ".git")] {
+		cmd := exec.CommandContext(ctx, "git", "init", "-q", "-b", "main")
+		cmd.Dir = path
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("clone failed: %v (%s)", err, string(output))
+		}
+		return nil
+	}
+	for name, content := range f.Files {
+		file := filepath.Join(path, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "-A"},
+		{"-c", "user.name=Copycat", "-c", "user.email=copycat@example.com", "commit", "-q", "--allow-empty", "-m", "Initial commit"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = path
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("clone failed: %v (%s)", err, string(output))
+		}
+	}
+	return nil
+}
+
+// InitializeRepo commits a README locally and records its push to main.
+func (f *Fake) InitializeRepo(ctx context.Context, project config.Project, targetPath string, signing config.CommitSigning) error {
+	if err := os.WriteFile(filepath.Join(targetPath, "README.md"), []byte("# "+project.Repo+"\n"), 0o644); err != nil {
+		return err
+	}
+	for _, args := range [][]string{
+		{"add", "README.md"},
+		{"-c", "user.name=Copycat", "-c", "user.email=copycat@example.com", "commit", "-q", "-m", "Initial commit"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = targetPath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to create the initial commit of %s: %v (%s)", project.Repo, err, string(output))
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pushes = append(f.pushes, Push{Repo: project.Repo, Branch: "main", Message: "Initial commit", Files: []string{"README.md"}, Signed: signing != (config.CommitSigning{})})
+	return nil
+}
+
+// RebaseOntoBase reports whether the repo's base branch moved, leaving the
+// clone as it is.
+func (f *Fake) RebaseOntoBase(ctx context.Context, project config.Project, targetPath string) (bool, error) {
+	return f.Moved[project.Repo], nil
+}
+
+// PushChanges commits the changes locally and records the push.
+func (f *Fake) PushChanges(ctx context.Context, project config.Project, targetPath, branchName, commitMessage string, signing config.CommitSigning) error {
+	if err := f.Errors[project.Repo]; err != nil {
+		return err
+	}
+	status, err := git.CheckLocalChanges(ctx, targetPath)
+	if err != nil {
+		return err
+	}
+	if len(status) == 0 {
+		return fmt.Errorf("no changes detected in %s, skipping PR creation", project.Repo)
+	}
+	var files []string
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"-c", "user.name=Copycat", "-c", "user.email=copycat@example.com", "commit", "-q", "-m", commitMessage},
+		{"diff", "--name-only", "HEAD~1", "HEAD"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = targetPath
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to push branch in %s: %v (%s)", project.Repo, err, string(output))
+		}
+		if args[0] == "diff" {
+			files = strings.Fields(string(output))
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pushes = append(f.pushes, Push{Repo: project.Repo, Branch: branchName, Message: commitMessage, Files: files, Signed: signing != (config.CommitSigning{})})
+	return nil
+}
+
+// CreatePullRequest records the pull request and returns its URL, as gh does.
+func (f *Fake) CreatePullRequest(ctx context.Context, project config.Project, targetPath, branchName, prTitle, prDescription string, opts git.PullRequestOptions) ([]byte, error) {
+	base := project.TargetBranch
+	if base == "" {
+		base = "main"
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	url := fmt.Sprintf("https://github.com/fake/%s/pull/%d", project.Repo, len(f.pullRequests)+1)
+	f.pullRequests = append(f.pullRequests, PullRequest{
+		Repo:      project.Repo,
+		Branch:    branchName,
+		Base:      base,
+		Title:     prTitle,
+		Body:      prDescription,
+		URL:       url,
+		Draft:     opts.Draft,
+		Reviewers: opts.Reviewers,
+		Labels:    opts.Labels,
+		Milestone: opts.Milestone,
+	})
+	return []byte(url + "\n"), nil
+}
+
+// FindPullRequest returns the URL of the pull request opened from branchName
+// on the project's repo, if any.
+func (f *Fake) FindPullRequest(ctx context.Context, project config.Project, targetPath, branchName string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, pr := range f.pullRequests {
+		if pr.Repo == project.Repo && pr.Branch == branchName {
+			return pr.URL, nil
+		}
+	}
+	return "", nil
+}
+
+// UpdatePullRequest replaces the title and body of the pull request at url.
+func (f *Fake) UpdatePullRequest(ctx context.Context, url, prTitle, prDescription string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.pullRequests {
+		if f.pullRequests[i].URL == url {
+			f.pullRequests[i].Title = prTitle
+			f.pullRequests[i].Body = prDescription
+			return nil
+		}
+	}
+	return fmt.Errorf("no pull request %s", url)
+}
+
+// EnableAutoMerge marks the pull request at url for auto-merge.
+func (f *Fake) EnableAutoMerge(ctx context.Context, url string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.pullRequests {
+		if f.pullRequests[i].URL == url {
+			f.pullRequests[i].AutoMerge = true
+			return nil
+		}
+	}
+	return fmt.Errorf("no pull request %s", url)
+}
+
+// FetchPullRequestTimes reports every pull request as opened an hour ago and
+// not merged.
+func (f *Fake) FetchPullRequestTimes(ctx context.Context, url string) (time.Time, time.Time, error) {
+	return time.Now().Add(-time.Hour), time.Time{}, nil
+}
+
+// FetchPullRequestDiff returns the diff in Diffs for url.
+func (f *Fake) FetchPullRequestDiff(ctx context.Context, url string) (string, error) {
+	diff, ok := f.Diffs[url]
+	if !ok {
+		return "", fmt.Errorf("failed to fetch the diff of %s: not found", url)
+	}
+	return diff, nil
+}
+
+// FetchChecks returns the Checks of the repo the pull request at url was
+// opened on.
+func (f *Fake) FetchChecks(ctx context.Context, url string) (git.Checks, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, pr := range f.pullRequests {
+		if pr.URL == url {
+			return f.Checks[pr.Repo], nil
+		}
+	}
+	return git.Checks{}, fmt.Errorf("no pull request %s", url)
+}
+
+func (f *Fake) FetchOpenAlerts(ctx context.Context, owner, repo string) ([]git.Alert, []string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var open []git.Alert
+	for _, alert := range f.Alerts[repo] {
+		if !f.isDismissed(alert) {
+			open = append(open, alert)
+		}
+	}
+	return open, nil, nil
+}
+
+func (f *Fake) DismissAlert(ctx context.Context, owner, repo string, alert git.Alert, reason, comment string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.dismissed = append(f.dismissed, alert)
+	return nil
+}
+
+// FetchRateLimits returns Quotas.
+func (f *Fake) FetchRateLimits(ctx context.Context) ([]git.Quota, error) {
+	return f.Quotas, nil
+}
+
+// CreateIssue records the issue and returns its URL, or fails with the
+// repo's error in IssueErrors.
+func (f *Fake) CreateIssue(ctx context.Context, owner, repo, title, body string, opts git.IssueOptions) (string, error) {
+	if err := f.IssueErrors[repo]; err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	url := fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, repo, len(f.issues)+1)
+	f.issues = append(f.issues, Issue{
+		Repo:      repo,
+		Title:     title,
+		Body:      body,
+		URL:       url,
+		Labels:    opts.Labels,
+		Milestone: opts.Milestone,
+		Assignees: opts.Assignees,
+	})
+	return url, nil
+}
+
+// ResolveAssignees expands the org/team slugs in Teams, failing for users
+// in Unassigned and unknown teams.
+func (f *Fake) ResolveAssignees(ctx context.Context, owner, repo string, assignees []string) ([]string, error) {
+	var logins []string
+	for _, assignee := range assignees {
+		assignee = strings.TrimPrefix(assignee, "@")
+		members := []string{assignee}
+		if strings.Contains(assignee, "/") {
+			var ok bool
+			if members, ok = f.Teams[assignee]; !ok {
+				return nil, fmt.Errorf("no team %s", assignee)
+			}
+		}
+		for _, login := range members {
+			if f.Unassigned[login] {
+				return nil, fmt.Errorf("%s cannot be assigned issues in %s/%s", login, owner, repo)
+			}
+			logins = append(logins, login)
+		}
+	}
+	return logins, nil
+}
+
+// FetchIssueState returns the issue's IssueStates, or open.
+func (f *Fake) FetchIssueState(ctx context.Context, url string) (git.IssueState, error) {
+	if state, ok := f.IssueStates[url]; ok {
+		return state, nil
+	}
+	return git.IssueState{State: "open"}, nil
+}
+
+// IssueTemplates returns the repo's Templates.
+func (f *Fake) IssueTemplates(ctx context.Context, owner, repo string) ([]issuetemplate.Template, error) {
+	return f.Templates[repo], nil
+}
+
+func (f *Fake) isDismissed(alert git.Alert) bool {
+	for _, d := range f.dismissed {
+		if d.ID() == alert.ID() {
+			return true
+		}
+	}
+	return false
+}
+
+// Pushes returns the branches pushed so far, in order.
+func (f *Fake) Pushes() []Push {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Push(nil), f.pushes...)
+}
+
+// PullRequests returns the pull requests opened so far, in order.
+func (f *Fake) PullRequests() []PullRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]PullRequest(nil), f.pullRequests...)
+}
+
+// Dismissed returns the alerts dismissed so far, in order.
+func (f *Fake) Dismissed() []git.Alert {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]git.Alert(nil), f.dismissed...)
+}
+
+// Issues returns the issues opened so far, in order.
+func (f *Fake) Issues() []Issue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Issue(nil), f.issues...)
+}
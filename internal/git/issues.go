@@ -0,0 +1,205 @@
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/issuetemplate"
+)
+
+// maxAssignees is the most assignees GitHub keeps on an issue.
+const maxAssignees = 10
+
+// IssueOptions are how an issue is opened, beyond its content.
+type IssueOptions struct {
+	Labels    []string // added to the copycat label
+	Milestone string   // title of an existing milestone
+	Assignees []string // GitHub users, e.g. copilot to hand it to the coding agent
+}
+
+// CreateIssue opens an issue in owner/repo, labelled copycat, and returns
+// its URL.
+func CreateIssue(ctx context.Context, owner, repo, title, body string, opts IssueOptions) (string, error) {
+	fullName := owner + "/" + repo
+	ensureLabelExists(ctx, fullName)
+	ensureLabelsExist(ctx, fullName, opts.Labels)
+
+	labels := []string{"copycat"}
+	for _, label := range opts.Labels {
+		if label != "copycat" {
+			labels = append(labels, label)
+		}
+	}
+	issue := map[string]any{
+		"title":  title,
+		"body":   body,
+		"labels": labels,
+	}
+	if len(opts.Assignees) > 0 {
+		issue["assignees"] = opts.Assignees
+	}
+	if opts.Milestone != "" {
+		milestone, err := findMilestone(ctx, fullName, opts.Milestone)
+		if err != nil {
+			return "", err
+		}
+		issue["milestone"] = milestone
+	}
+
+	var created struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := apiCall(ctx, http.MethodPost, "repos/"+fullName+"/issues", issue, &created); err != nil {
+		return "", err
+	}
+	return created.HTMLURL, nil
+}
+
+// IssueState is where an issue stands on GitHub.
+type IssueState struct {
+	State       string // open or closed
+	StateReason string // why it was closed: completed or not_planned
+	Assignees   []string
+}
+
+var issueURLPattern = regexp.MustCompile(`^https?://[^/]+/([^/]+)/([^/]+)/issues/(\d+)`)
+
+// FetchIssueState returns the state of the issue at url.
+func FetchIssueState(ctx context.Context, url string) (IssueState, error) {
+	m := issueURLPattern.FindStringSubmatch(strings.TrimSpace(url))
+	if m == nil {
+		return IssueState{}, fmt.Errorf("%q is not an issue URL", url)
+	}
+	var issue struct {
+		State       string `json:"state"`
+		StateReason string `json:"state_reason"`
+		Assignees   []struct {
+			Login string `json:"login"`
+		} `json:"assignees"`
+	}
+	if err := apiCall(ctx, http.MethodGet, fmt.Sprintf("repos/%s/%s/issues/%s", m[1], m[2], m[3]), nil, &issue); err != nil {
+		return IssueState{}, fmt.Errorf("failed to view %s: %w", url, err)
+	}
+	state := IssueState{State: issue.State, StateReason: issue.StateReason}
+	for _, a := range issue.Assignees {
+		state.Assignees = append(state.Assignees, a.Login)
+	}
+	return state, nil
+}
+
+// ResolveAssignees turns assignees into the logins to assign issues in
+// owner/repo to: org/team slugs stand for the team's members. It fails when
+// one of them cannot be assigned issues there, so a run can check them
+// before opening any issue.
+func ResolveAssignees(ctx context.Context, owner, repo string, assignees []string) ([]string, error) {
+	var logins []string
+	for _, assignee := range assignees {
+		assignee = strings.TrimPrefix(strings.TrimSpace(assignee), "@")
+		org, team, isTeam := strings.Cut(assignee, "/")
+		if !isTeam {
+			if !slices.Contains(logins, assignee) {
+				logins = append(logins, assignee)
+			}
+			continue
+		}
+		members, err := teamMembers(ctx, org, team)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if !slices.Contains(logins, member) {
+				logins = append(logins, member)
+			}
+		}
+	}
+	if len(logins) > maxAssignees {
+		return nil, fmt.Errorf("%d assignees is more than the %d GitHub allows on an issue", len(logins), maxAssignees)
+	}
+
+	for _, login := range logins {
+		err := apiCall(ctx, http.MethodGet, fmt.Sprintf("repos/%s/%s/assignees/%s", owner, repo, login), nil, nil)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("%s cannot be assigned issues in %s/%s", login, owner, repo)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to check assignee %s: %w", login, err)
+		}
+	}
+	return logins, nil
+}
+
+// teamMembers lists the logins of the members of org/team.
+func teamMembers(ctx context.Context, org, team string) ([]string, error) {
+	output, err := getPages(ctx, fmt.Sprintf("orgs/%s/teams/%s/members?per_page=100", org, team))
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("no team %s/%s", org, team)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the members of %s/%s: %w", org, team, err)
+	}
+	members, err := decodePages[struct {
+		Login string `json:"login"`
+	}](output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the members of %s/%s: %w", org, team, err)
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("team %s/%s has no members", org, team)
+	}
+	logins := make([]string, len(members))
+	for i, m := range members {
+		logins[i] = m.Login
+	}
+	return logins, nil
+}
+
+// issueTemplateDir is where a repo keeps its issue templates.
+const issueTemplateDir = ".github/ISSUE_TEMPLATE"
+
+// IssueTemplates reads the issue templates in owner/repo, in the order GitHub
+// lists them. A repo without any has none.
+func IssueTemplates(ctx context.Context, owner, repo string) ([]issuetemplate.Template, error) {
+	fullName := owner + "/" + repo
+	var files []struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+	err := apiCall(ctx, http.MethodGet, "repos/"+fullName+"/contents/"+issueTemplateDir, nil, &files)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the issue templates of %s: %w", fullName, err)
+	}
+
+	var templates []issuetemplate.Template
+	for _, file := range files {
+		if file.Type != "file" || !issuetemplate.IsTemplateFile(file.Name) {
+			continue
+		}
+		var content struct {
+			Content string `json:"content"`
+		}
+		if err := apiCall(ctx, http.MethodGet, "repos/"+fullName+"/contents/"+file.Path, nil, &content); err != nil {
+			return nil, fmt.Errorf("failed to read %s in %s: %w", file.Path, fullName, err)
+		}
+		data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(content.Content, "\n", ""))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s in %s: %w", file.Path, fullName, err)
+		}
+		tmpl, err := issuetemplate.Parse(file.Name, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fullName, err)
+		}
+		tmpl.Source = fullName
+		templates = append(templates, tmpl)
+	}
+	return templates, nil
+}
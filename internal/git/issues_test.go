@@ -0,0 +1,138 @@
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCreateIssue(t *testing.T) {
+	bodies := make(map[string]map[string]any)
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies[r.Method+" "+r.URL.Path] = body
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repos/acme/payments-api/milestones":
+			_, _ = w.Write([]byte(`[{"number":5,"title":"Q4"}]`))
+		case "POST /repos/acme/payments-api/issues":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number":7,"html_url":"https://github.com/acme/payments-api/issues/7"}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+
+	opts := IssueOptions{Labels: []string{"security", "copycat"}, Milestone: "Q4", Assignees: []string{"copilot"}}
+	url, err := CreateIssue(context.Background(), "acme", "payments-api", "Bump deps", "Body", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "https://github.com/acme/payments-api/issues/7" {
+		t.Errorf("unexpected URL %q", url)
+	}
+
+	issue := bodies["POST /repos/acme/payments-api/issues"]
+	if issue["title"] != "Bump deps" || issue["body"] != "Body" {
+		t.Errorf("unexpected issue request %v", issue)
+	}
+	if fmt.Sprint(issue["labels"]) != "[copycat security]" || issue["milestone"] != float64(5) || fmt.Sprint(issue["assignees"]) != "[copilot]" {
+		t.Errorf("expected the labels, milestone and assignees to be set on creation, got %v", issue)
+	}
+	if _, ok := bodies["POST /repos/acme/payments-api/labels"]; !ok {
+		t.Error("expected the labels to be created first")
+	}
+}
+
+func TestFetchIssueState(t *testing.T) {
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/payments-api/issues/7" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"state":"closed","state_reason":"completed","assignees":[{"login":"jane"}]}`))
+	})
+
+	state, err := FetchIssueState(context.Background(), "https://github.com/acme/payments-api/issues/7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.State != "closed" || state.StateReason != "completed" || fmt.Sprint(state.Assignees) != "[jane]" {
+		t.Errorf("unexpected state %+v", state)
+	}
+	if _, err := FetchIssueState(context.Background(), "https://github.com/acme/payments-api/pull/7"); err == nil {
+		t.Error("expected a PR URL to be rejected")
+	}
+}
+
+func TestResolveAssignees(t *testing.T) {
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/acme/teams/payments/members":
+			_, _ = w.Write([]byte(`[{"login":"jane"},{"login":"joe"}]`))
+		case "/repos/acme/ledger/assignees/jane", "/repos/acme/ledger/assignees/joe", "/repos/acme/ledger/assignees/copilot":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	})
+
+	ctx := context.Background()
+	got, err := ResolveAssignees(ctx, "acme", "ledger", []string{"@copilot", "acme/payments", "jane"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != "[copilot jane joe]" {
+		t.Errorf("expected the team expanded to its members once each, got %v", got)
+	}
+
+	if _, err := ResolveAssignees(ctx, "acme", "ledger", []string{"ghost"}); err == nil || !strings.Contains(err.Error(), "ghost cannot be assigned issues in acme/ledger") {
+		t.Errorf("expected an unknown user to be rejected, got %v", err)
+	}
+	if _, err := ResolveAssignees(ctx, "acme", "ledger", []string{"acme/nobody"}); err == nil || !strings.Contains(err.Error(), "no team acme/nobody") {
+		t.Errorf("expected an unknown team to be rejected, got %v", err)
+	}
+}
+
+func TestIssueTemplates(t *testing.T) {
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/ledger/contents/.github/ISSUE_TEMPLATE":
+			_, _ = w.Write([]byte(`[
+				{"name":"bug.md","path":".github/ISSUE_TEMPLATE/bug.md","type":"file"},
+				{"name":"config.yml","path":".github/ISSUE_TEMPLATE/config.yml","type":"file"},
+				{"name":"deps.yml","path":".github/ISSUE_TEMPLATE/deps.yml","type":"file"}
+			]`))
+		case "/repos/acme/ledger/contents/.github/ISSUE_TEMPLATE/bug.md":
+			_ = json.NewEncoder(w).Encode(map[string]string{"content": encode("---\nname: Bug\nlabels: bug\n---\nSteps")})
+		case "/repos/acme/ledger/contents/.github/ISSUE_TEMPLATE/deps.yml":
+			_ = json.NewEncoder(w).Encode(map[string]string{"content": encode("name: Deps\nbody:\n  - type: input\n    attributes:\n      label: Package\n")})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	})
+
+	templates, err := IssueTemplates(context.Background(), "acme", "ledger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 || templates[0].Name != "Bug" || templates[0].Body != "Steps" || templates[1].Name != "Deps" || len(templates[1].Fields) != 1 {
+		t.Fatalf("expected the Markdown template and the form but not the config, got %+v", templates)
+	}
+	if templates[0].Source != "acme/ledger" {
+		t.Errorf("expected templates to name their repo, got %q", templates[0].Source)
+	}
+
+	templates, err = IssueTemplates(context.Background(), "acme", "bare")
+	if err != nil || templates != nil {
+		t.Errorf("expected a repo without templates to have none, got %v (%v)", templates, err)
+	}
+}
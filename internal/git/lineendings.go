@@ -0,0 +1,150 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Line ending styles reported by detectLineEnding.
+const (
+	eolNone  = ""      // no line breaks
+	eolLF    = "lf"    // every line ends in \n
+	eolCRLF  = "crlf"  // every line ends in \r\n
+	eolMixed = "mixed" // both styles present
+)
+
+// detectLineEnding reports which line ending style data uses consistently.
+func detectLineEnding(data []byte) string {
+	lines := bytes.Count(data, []byte("\n"))
+	if lines == 0 {
+		return eolNone
+	}
+	crlf := bytes.Count(data, []byte("\r\n"))
+	switch crlf {
+	case 0:
+		return eolLF
+	case lines:
+		return eolCRLF
+	}
+	return eolMixed
+}
+
+// convertLineEndings rewrites every line break in data to \r\n when crlf is
+// set, or to \n otherwise.
+func convertLineEndings(data []byte, crlf bool) []byte {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	if crlf {
+		data = bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))
+	}
+	return data
+}
+
+// RestoreLineEndings undoes line ending churn in modified files, which AI
+// tools produce when they rewrite CRLF files with LF or vice versa. Files whose
+// only changes are line endings are restored from HEAD. Files with real changes
+// are converted back to the line ending style they had in HEAD, unless
+// .gitattributes assigns them a text or eol attribute, in which case git
+// renormalizes them when they are staged. Returns the restored and converted paths.
+func RestoreLineEndings(ctx context.Context, repoPath string) (restored, converted []string, err error) {
+	files, err := listChangedFiles(ctx, repoPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var errs []string
+	for _, f := range files {
+		// Only tracked files modified in place can have line ending churn
+		if !strings.Contains(f.Status, "M") || strings.ContainsAny(f.Status, "ADR?") {
+			continue
+		}
+
+		cmd := exec.CommandContext(ctx, "git", "diff", "--quiet", "--ignore-cr-at-eol", "HEAD", "--", f.Path)
+		cmd.Dir = repoPath
+		if cmd.Run() == nil {
+			cmd = exec.CommandContext(ctx, "git", "checkout", "HEAD", "--", f.Path)
+			cmd.Dir = repoPath
+			if output, err := combinedOutput(ctx, cmd); err != nil {
+				errs = append(errs, fmt.Sprintf("restore %s: %v (%s)", f.Path, err, strings.TrimSpace(string(output))))
+				continue
+			}
+			restored = append(restored, f.Path)
+			continue
+		}
+
+		if hasLineEndingAttributes(ctx, repoPath, f.Path) {
+			continue
+		}
+		ok, err := matchHeadLineEndings(ctx, repoPath, f.Path)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("convert %s: %v", f.Path, err))
+			continue
+		}
+		if ok {
+			converted = append(converted, f.Path)
+		}
+	}
+
+	if len(errs) > 0 {
+		return restored, converted, fmt.Errorf("failed to restore line endings: %s", strings.Join(errs, "; "))
+	}
+	return restored, converted, nil
+}
+
+// hasLineEndingAttributes reports whether .gitattributes sets text or eol for path.
+func hasLineEndingAttributes(ctx context.Context, repoPath, path string) bool {
+	cmd := exec.CommandContext(ctx, "git", "check-attr", "text", "eol", "--", path)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		// Each line is "<path>: <attribute>: <value>"
+		if value := line[strings.LastIndex(line, ": ")+2:]; value != "unspecified" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHeadLineEndings converts the working copy of path to the line ending
+// style it had in HEAD. It reports whether the file was rewritten.
+func matchHeadLineEndings(ctx context.Context, repoPath, path string) (bool, error) {
+	cmd := exec.CommandContext(ctx, "git", "show", "HEAD:"+path)
+	cmd.Dir = repoPath
+	head, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to read HEAD version: %w", err)
+	}
+
+	full := filepath.Join(repoPath, path)
+	current, err := os.ReadFile(full)
+	if err != nil {
+		return false, err
+	}
+	// Leave binary files and files without a consistent style alone
+	if bytes.IndexByte(head, 0) >= 0 || bytes.IndexByte(current, 0) >= 0 {
+		return false, nil
+	}
+	want := detectLineEnding(head)
+	if want != eolLF && want != eolCRLF {
+		return false, nil
+	}
+	if detectLineEnding(current) == want {
+		return false, nil
+	}
+
+	info, err := os.Stat(full)
+	if err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(full, convertLineEndings(current, want == eolCRLF), info.Mode().Perm()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
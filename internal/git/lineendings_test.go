@@ -0,0 +1,99 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectLineEnding(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"no newline", eolNone},
+		{"a\nb\n", eolLF},
+		{"a\r\nb\r\n", eolCRLF},
+		{"a\r\nb\n", eolMixed},
+	}
+	for _, tt := range tests {
+		if got := detectLineEnding([]byte(tt.data)); got != tt.want {
+			t.Errorf("detectLineEnding(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestConvertLineEndings(t *testing.T) {
+	if got := string(convertLineEndings([]byte("a\r\nb\nc"), true)); got != "a\r\nb\r\nc" {
+		t.Errorf("expected CRLF conversion, got %q", got)
+	}
+	if got := string(convertLineEndings([]byte("a\r\nb\nc"), false)); got != "a\nb\nc" {
+		t.Errorf("expected LF conversion, got %q", got)
+	}
+}
+
+func TestRestoreLineEndings(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com", "-c", "core.autocrlf=false"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+	write := func(rel, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(rel string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	run("init", "-q")
+	run("config", "core.autocrlf", "false")
+	write("churn.txt", "one\r\ntwo\r\n")
+	write("edited.txt", "one\r\ntwo\r\n")
+	write("plain.txt", "one\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "initial")
+
+	// Simulate an AI tool rewriting CRLF files with LF
+	write("churn.txt", "one\ntwo\n")
+	write("edited.txt", "one\ntwo\nthree\n")
+	write("plain.txt", "one\ntwo\n")
+
+	restored, converted, err := RestoreLineEndings(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("RestoreLineEndings failed: %v", err)
+	}
+	if len(restored) != 1 || restored[0] != "churn.txt" {
+		t.Errorf("expected churn.txt to be restored, got %v", restored)
+	}
+	if len(converted) != 1 || converted[0] != "edited.txt" {
+		t.Errorf("expected edited.txt to be converted, got %v", converted)
+	}
+
+	if got := read("churn.txt"); got != "one\r\ntwo\r\n" {
+		t.Errorf("expected original content, got %q", got)
+	}
+	if got := read("edited.txt"); got != "one\r\ntwo\r\nthree\r\n" {
+		t.Errorf("expected edit with CRLF endings, got %q", got)
+	}
+	if got := read("plain.txt"); got != "one\ntwo\n" {
+		t.Errorf("expected LF change to be kept, got %q", got)
+	}
+}
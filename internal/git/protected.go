@@ -0,0 +1,168 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/util"
+)
+
+// changedFile is a single entry from `git status --porcelain`.
+type changedFile struct {
+	Status   string // two-letter XY status code
+	Path     string // path relative to the repo root
+	OrigPath string // the path a renamed or copied file came from
+}
+
+// renamed reports whether the file was renamed or copied from OrigPath.
+func (f changedFile) renamed() bool {
+	return f.Status[0] == 'R' || f.Status[0] == 'C'
+}
+
+// listChangedFiles returns every modified, deleted, or untracked file in the working tree.
+func listChangedFiles(ctx context.Context, repoPath string) ([]changedFile, error) {
+	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain", "--untracked-files=all")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	return parsePorcelain(string(output)), nil
+}
+
+// parsePorcelain parses `git status --porcelain` (v1) output.
+func parsePorcelain(output string) []changedFile {
+	var files []changedFile
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		f := changedFile{Status: line[:2], Path: line[3:]}
+		// Renames and copies are reported as "old -> new"
+		if f.renamed() {
+			if orig, path, ok := strings.Cut(f.Path, " -> "); ok {
+				f.OrigPath, f.Path = unquotePath(orig), path
+			}
+		}
+		f.Path = unquotePath(f.Path)
+		files = append(files, f)
+	}
+	return files
+}
+
+// unquotePath decodes a path git quoted because of unusual characters, e.g.
+// "caf\303\251.txt".
+func unquotePath(p string) string {
+	if strings.HasPrefix(p, `"`) {
+		if unquoted, err := strconv.Unquote(p); err == nil {
+			return unquoted
+		}
+	}
+	return p
+}
+
+// RevertProtectedPaths discards any working tree changes touching paths that
+// match one of the protected glob patterns. Modified and deleted files are
+// restored from HEAD; newly added files are removed. Returns the reverted paths.
+func RevertProtectedPaths(ctx context.Context, repoPath string, patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	reverted, err := revertChanges(ctx, repoPath, func(p string) bool { return util.MatchAnyGlob(patterns, p) })
+	if err != nil {
+		return reverted, fmt.Errorf("failed to revert protected paths: %w", err)
+	}
+	return reverted, nil
+}
+
+// RevertOutsidePath discards any working tree changes outside dir, the
+// subdirectory a monorepo job is scoped to, the same way as
+// RevertProtectedPaths. Returns the reverted paths.
+func RevertOutsidePath(ctx context.Context, repoPath, dir string) ([]string, error) {
+	dir = strings.Trim(dir, "/")
+	if dir == "" {
+		return nil, nil
+	}
+	reverted, err := revertChanges(ctx, repoPath, func(p string) bool { return p != dir && !strings.HasPrefix(p, dir+"/") })
+	if err != nil {
+		return reverted, fmt.Errorf("failed to revert changes outside %s/: %w", dir, err)
+	}
+	return reverted, nil
+}
+
+// revertChanges restores or removes the changed files that match. A rename
+// or copy that matches on either side is undone: the original path is
+// restored and the new one removed.
+func revertChanges(ctx context.Context, repoPath string, match func(path string) bool) ([]string, error) {
+	files, err := listChangedFiles(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []string
+	var errs []string
+	for _, f := range files {
+		switch {
+		case f.renamed():
+			if !match(f.Path) && !match(f.OrigPath) {
+				continue
+			}
+			if err := restoreFile(ctx, repoPath, f.OrigPath); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			if err := removeFile(ctx, repoPath, f.Path); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			reverted = append(reverted, f.OrigPath, f.Path)
+		case !match(f.Path):
+			continue
+		case f.Status == "??" || f.Status[0] == 'A':
+			if err := removeFile(ctx, repoPath, f.Path); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			reverted = append(reverted, f.Path)
+		default:
+			if err := restoreFile(ctx, repoPath, f.Path); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			reverted = append(reverted, f.Path)
+		}
+	}
+	if len(errs) > 0 {
+		return reverted, errors.New(strings.Join(errs, "; "))
+	}
+	return reverted, nil
+}
+
+// restoreFile puts path back as it is in HEAD, in the index and on disk.
+func restoreFile(ctx context.Context, repoPath, path string) error {
+	cmd := exec.CommandContext(ctx, "git", "checkout", "HEAD", "--", path)
+	cmd.Dir = repoPath
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		return fmt.Errorf("restore %s: %v (%s)", path, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// removeFile unstages path, if staged, and deletes it.
+func removeFile(ctx context.Context, repoPath, path string) error {
+	cmd := exec.CommandContext(ctx, "git", "rm", "--cached", "--quiet", "--ignore-unmatch", "--", path)
+	cmd.Dir = repoPath
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		return fmt.Errorf("unstage %s: %v (%s)", path, err, strings.TrimSpace(string(output)))
+	}
+	if err := os.RemoveAll(filepath.Join(repoPath, path)); err != nil {
+		return fmt.Errorf("remove %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePorcelain(t *testing.T) {
+	output := " M src/main.go\n?? secrets/new.env\nR  old.txt -> new.txt\n D removed.go\n" +
+		"?? \"caf\\303\\251 \\\"menu\\\".txt\"\nRM \"d\\303\\251j\\303\\240.txt\" -> vu.txt\n"
+
+	files := parsePorcelain(output)
+	if len(files) != 6 {
+		t.Fatalf("expected 6 files, got %d: %v", len(files), files)
+	}
+
+	expected := []changedFile{
+		{Status: " M", Path: "src/main.go"},
+		{Status: "??", Path: "secrets/new.env"},
+		{Status: "R ", Path: "new.txt", OrigPath: "old.txt"},
+		{Status: " D", Path: "removed.go"},
+		{Status: "??", Path: `café "menu".txt`},
+		{Status: "RM", Path: "vu.txt", OrigPath: "déjà.txt"},
+	}
+	for i, want := range expected {
+		if files[i] != want {
+			t.Errorf("file %d: expected %+v, got %+v", i, want, files[i])
+		}
+	}
+}
+
+func TestRevertProtectedPaths(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write(".github/workflows/ci.yml", "original")
+	write("src/main.go", "package main")
+	run("add", "-A")
+	run("commit", "-q", "-m", "initial")
+
+	// Simulate AI edits: one protected modification, one protected addition, one allowed change
+	write(".github/workflows/ci.yml", "tampered")
+	write("config/secrets/token", "s3cret")
+	write("src/main.go", "package main // edited")
+
+	reverted, err := RevertProtectedPaths(context.Background(), dir, []string{".github/workflows/*", "**/secrets/**"})
+	if err != nil {
+		t.Fatalf("RevertProtectedPaths failed: %v", err)
+	}
+	if len(reverted) != 2 {
+		t.Fatalf("expected 2 reverted paths, got %v", reverted)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, ".github/workflows/ci.yml"))
+	if string(data) != "original" {
+		t.Errorf("expected workflow to be restored, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config/secrets/token")); !os.IsNotExist(err) {
+		t.Error("expected new secrets file to be removed")
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "src/main.go"))
+	if string(data) != "package main // edited" {
+		t.Errorf("expected unprotected change to be kept, got %q", data)
+	}
+}
+
+func TestRevertProtectedPathsRenamed(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write(".github/workflows/ci.yml", "original workflow")
+	write("scripts/deploy.sh", "original script")
+	run("add", "-A")
+	run("commit", "-q", "-m", "initial")
+
+	// Simulate AI renames: a protected file moved away, a file moved into a protected path
+	if err := os.MkdirAll(filepath.Join(dir, "old"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	run("mv", ".github/workflows/ci.yml", "old/ci.yml")
+	run("mv", "scripts/deploy.sh", ".github/workflows/deploy.sh")
+
+	reverted, err := RevertProtectedPaths(context.Background(), dir, []string{".github/workflows/*"})
+	if err != nil {
+		t.Fatalf("RevertProtectedPaths failed: %v", err)
+	}
+	if len(reverted) != 4 {
+		t.Fatalf("expected both sides of both renames to be reverted, got %v", reverted)
+	}
+
+	for rel, want := range map[string]string{".github/workflows/ci.yml": "original workflow", "scripts/deploy.sh": "original script"} {
+		if data, _ := os.ReadFile(filepath.Join(dir, rel)); string(data) != want {
+			t.Errorf("expected %s to be restored, got %q", rel, data)
+		}
+	}
+	for _, rel := range []string{"old/ci.yml", ".github/workflows/deploy.sh"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", rel)
+		}
+	}
+	if files, err := listChangedFiles(context.Background(), dir); err != nil || len(files) != 0 {
+		t.Errorf("expected a clean tree, got %v (%v)", files, err)
+	}
+}
+
+func TestRevertOutsidePath(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("services/payments/pom.xml", "original")
+	write("services/payments-worker/pom.xml", "original")
+	for _, args := range [][]string{{"init", "-q"}, {"add", "-A"}, {"commit", "-q", "-m", "initial"}} {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+
+	write("services/payments/pom.xml", "edited")
+	write("services/payments-worker/pom.xml", "edited")
+	write("README.md", "new")
+
+	reverted, err := RevertOutsidePath(context.Background(), dir, "services/payments/")
+	if err != nil {
+		t.Fatalf("RevertOutsidePath failed: %v", err)
+	}
+	if len(reverted) != 2 {
+		t.Fatalf("expected the sibling service and the root file to be reverted, got %v", reverted)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "services/payments/pom.xml"))
+	if string(data) != "edited" {
+		t.Errorf("expected the change within the path to be kept, got %q", data)
+	}
+}
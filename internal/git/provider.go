@@ -0,0 +1,117 @@
+package git
+
+import (
+	"context"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/issuetemplate"
+)
+
+// Provider is everything a run does on GitHub: discovering and cloning repos,
+// pushing branches, opening pull requests, watching their checks and handling security alerts. Work
+// on the local clone (branches, diffs, reverts) is plain git and stays outside.
+// GH is the real provider; gittest.Fake stands in for it in tests.
+type Provider interface {
+	FetchRepositories(githubCfg config.GitHubConfig) ([]config.Project, error)
+	Clone(ctx context.Context, repoURL, path string, args ...string) error
+	InitializeRepo(ctx context.Context, project config.Project, targetPath string, signing config.CommitSigning) error
+	RebaseOntoBase(ctx context.Context, project config.Project, targetPath string) (bool, error)
+	PushChanges(ctx context.Context, project config.Project, targetPath, branchName, commitMessage string, signing config.CommitSigning) error
+	CreatePullRequest(ctx context.Context, project config.Project, targetPath, branchName, prTitle, prDescription string, opts PullRequestOptions) ([]byte, error)
+	FindPullRequest(ctx context.Context, project config.Project, targetPath, branchName string) (string, error)
+	UpdatePullRequest(ctx context.Context, url, prTitle, prDescription string) error
+	FetchPullRequestTimes(ctx context.Context, url string) (createdAt, mergedAt time.Time, err error)
+	FetchPullRequestDiff(ctx context.Context, url string) (string, error)
+	FetchChecks(ctx context.Context, url string) (Checks, error)
+	EnableAutoMerge(ctx context.Context, url string) error
+	FetchOpenAlerts(ctx context.Context, owner, repo string) ([]Alert, []string, error)
+	DismissAlert(ctx context.Context, owner, repo string, alert Alert, reason, comment string) error
+	FetchRateLimits(ctx context.Context) ([]Quota, error)
+	CreateIssue(ctx context.Context, owner, repo, title, body string, opts IssueOptions) (string, error)
+	ResolveAssignees(ctx context.Context, owner, repo string, assignees []string) ([]string, error)
+	IssueTemplates(ctx context.Context, owner, repo string) ([]issuetemplate.Template, error)
+	FetchIssueState(ctx context.Context, url string) (IssueState, error)
+}
+
+// GH talks to GitHub through the git CLI and the GitHub API.
+type GH struct{}
+
+var _ Provider = GH{}
+
+func (GH) FetchRepositories(githubCfg config.GitHubConfig) ([]config.Project, error) {
+	return FetchRepositories(githubCfg)
+}
+
+// Clone clones repoURL into path, passing args (e.g. --no-checkout) to git clone.
+func (GH) Clone(ctx context.Context, repoURL, path string, args ...string) error {
+	return Clone(ctx, repoURL, path, args...)
+}
+
+func (GH) InitializeRepo(ctx context.Context, project config.Project, targetPath string, signing config.CommitSigning) error {
+	return InitializeRepo(ctx, project, targetPath, signing)
+}
+
+func (GH) RebaseOntoBase(ctx context.Context, project config.Project, targetPath string) (bool, error) {
+	return RebaseOntoBase(ctx, project, targetPath)
+}
+
+func (GH) PushChanges(ctx context.Context, project config.Project, targetPath, branchName, commitMessage string, signing config.CommitSigning) error {
+	return PushChanges(ctx, project, targetPath, branchName, commitMessage, signing)
+}
+
+func (GH) CreatePullRequest(ctx context.Context, project config.Project, targetPath, branchName, prTitle, prDescription string, opts PullRequestOptions) ([]byte, error) {
+	return CreatePullRequest(ctx, project, targetPath, branchName, prTitle, prDescription, opts)
+}
+
+func (GH) FindPullRequest(ctx context.Context, project config.Project, targetPath, branchName string) (string, error) {
+	return FindPullRequest(ctx, targetPath, branchName)
+}
+
+func (GH) UpdatePullRequest(ctx context.Context, url, prTitle, prDescription string) error {
+	return UpdatePullRequest(ctx, url, prTitle, prDescription)
+}
+
+func (GH) FetchPullRequestTimes(ctx context.Context, url string) (time.Time, time.Time, error) {
+	return FetchPullRequestTimes(ctx, url)
+}
+
+func (GH) FetchPullRequestDiff(ctx context.Context, url string) (string, error) {
+	return FetchPullRequestDiff(ctx, url)
+}
+
+func (GH) FetchChecks(ctx context.Context, url string) (Checks, error) {
+	return FetchChecks(ctx, url)
+}
+
+func (GH) EnableAutoMerge(ctx context.Context, url string) error {
+	return EnableAutoMerge(ctx, url)
+}
+
+func (GH) FetchOpenAlerts(ctx context.Context, owner, repo string) ([]Alert, []string, error) {
+	return FetchOpenAlerts(ctx, owner, repo)
+}
+
+func (GH) DismissAlert(ctx context.Context, owner, repo string, alert Alert, reason, comment string) error {
+	return DismissAlert(ctx, owner, repo, alert, reason, comment)
+}
+
+func (GH) FetchRateLimits(ctx context.Context) ([]Quota, error) {
+	return FetchRateLimits(ctx)
+}
+
+func (GH) CreateIssue(ctx context.Context, owner, repo, title, body string, opts IssueOptions) (string, error) {
+	return CreateIssue(ctx, owner, repo, title, body, opts)
+}
+
+func (GH) ResolveAssignees(ctx context.Context, owner, repo string, assignees []string) ([]string, error) {
+	return ResolveAssignees(ctx, owner, repo, assignees)
+}
+
+func (GH) IssueTemplates(ctx context.Context, owner, repo string) ([]issuetemplate.Template, error) {
+	return IssueTemplates(ctx, owner, repo)
+}
+
+func (GH) FetchIssueState(ctx context.Context, url string) (IssueState, error) {
+	return FetchIssueState(ctx, url)
+}
@@ -2,36 +2,317 @@ package git
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"os/exec"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/saltpay/copycat/v2/internal/config"
 )
 
-// ensureLabelExists creates the 'copycat' label in the repository if it doesn't exist
-func ensureLabelExists(ctx context.Context, targetPath string) {
-	_, _ = runGhContext(ctx, targetPath, "label", "create", "copycat",
-		"--description", "Created by Copycat",
-		"--color", "6f42c1",
-		"--force")
+// ensureLabelExists creates the 'copycat' label in the repository, or
+// resets its colour and description if it exists.
+func ensureLabelExists(ctx context.Context, repo string) {
+	label := map[string]string{"name": "copycat", "description": "Created by Copycat", "color": "6f42c1"}
+	if err := apiCall(ctx, http.MethodPost, "repos/"+repo+"/labels", label, nil); err != nil {
+		_ = apiCall(ctx, http.MethodPatch, "repos/"+repo+"/labels/copycat", label, nil)
+	}
+}
+
+// ensureLabelsExist creates the configured labels missing from the
+// repository, leaving the colour and description of existing ones alone.
+func ensureLabelsExist(ctx context.Context, repo string, labels []string) {
+	for _, label := range labels {
+		_ = apiCall(ctx, http.MethodPost, "repos/"+repo+"/labels", map[string]string{"name": label}, nil)
+	}
+}
+
+// PullRequestOptions are how a pull request is opened, beyond its content.
+type PullRequestOptions struct {
+	Draft     bool     // opened as a draft, e.g. because its checks failed
+	Reviewers []string // GitHub users or org/team slugs asked to review
+	Labels    []string // added to the copycat label
+	Milestone string   // title of an existing milestone
+}
+
+// ReviewerMentions mentions each reviewer, so they are notified even where
+// a review cannot be requested, e.g. on a PR from a fork.
+func ReviewerMentions(reviewers []string) string {
+	mentions := make([]string, len(reviewers))
+	for i, reviewer := range reviewers {
+		mentions[i] = "@" + strings.TrimPrefix(strings.TrimSpace(reviewer), "@")
+	}
+	return "cc " + strings.Join(mentions, " ")
+}
+
+// RelatedPRs is a PR description section linking the other PRs of a change
+// made across repos. GitHub shows each link with its PR's title and state.
+func RelatedPRs(urls []string) string {
+	var b strings.Builder
+	b.WriteString("### Related PRs\n\n")
+	fmt.Fprintf(&b, "This change was also made in %d other repo(s):\n\n", len(urls))
+	for _, url := range urls {
+		fmt.Fprintf(&b, "- %s\n", url)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// pullRequest is a pull request as returned by the REST API.
+type pullRequest struct {
+	Number    int        `json:"number"`
+	NodeID    string     `json:"node_id"`
+	HTMLURL   string     `json:"html_url"`
+	CreatedAt time.Time  `json:"created_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+	Head      struct {
+		SHA string `json:"sha"`
+	} `json:"head"`
+}
+
+// CreatePullRequest opens a PR from branchName and returns its URL.
+func CreatePullRequest(ctx context.Context, project config.Project, targetPath string, branchName string, prTitle string, prDescription string, opts PullRequestOptions) ([]byte, error) {
+	owner, name, err := originRepo(ctx, targetPath)
+	if err != nil {
+		return nil, err
+	}
+	repo := owner + "/" + name
+
+	// A branch pushed to a mirror can only be opened as a PR once it syncs
+	if project.PushURL != "" {
+		if err := waitForBranch(ctx, repo, branchName); err != nil {
+			return nil, err
+		}
+	}
+
+	// A branch pushed to the user's fork is opened as a cross-repo PR, which
+	// cannot be labelled or have reviews requested without write access
+	head, fork := branchName, forkOwner(ctx, targetPath)
+	milestone := 0
+	if fork != "" {
+		head = fork + ":" + branchName
+	} else {
+		ensureLabelExists(ctx, repo)
+		ensureLabelsExist(ctx, repo, opts.Labels)
+		if opts.Milestone != "" {
+			if milestone, err = findMilestone(ctx, repo, opts.Milestone); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Target the configured base branch, or the repository's default branch
+	defaultBranch := project.TargetBranch
+	if defaultBranch == "" {
+		cmd := exec.CommandContext(ctx, "git", "symbolic-ref", "refs/remotes/origin/HEAD", "--short")
+		cmd.Dir = targetPath
+		defaultBranchOutput, err := combinedOutput(ctx, cmd)
+		if err != nil {
+			defaultBranchOutput = []byte("origin/main")
+		}
+		defaultBranch = strings.TrimPrefix(strings.TrimSpace(string(defaultBranchOutput)), "origin/")
+	}
+
+	var pr pullRequest
+	err = apiCall(ctx, http.MethodPost, "repos/"+repo+"/pulls", map[string]any{
+		"title": prTitle,
+		"body":  prDescription,
+		"base":  defaultBranch,
+		"head":  head,
+		"draft": opts.Draft,
+	}, &pr)
+	if err != nil {
+		return nil, err
+	}
+	output := []byte(pr.HTMLURL + "\n")
+	if fork != "" {
+		return output, nil
+	}
+
+	// Labels and the milestone are set on the PR's issue in one request
+	labels := []string{"copycat"}
+	for _, label := range opts.Labels {
+		if label != "copycat" {
+			labels = append(labels, label)
+		}
+	}
+	issue := map[string]any{"labels": labels}
+	if milestone != 0 {
+		issue["milestone"] = milestone
+	}
+	if err := apiCall(ctx, http.MethodPatch, fmt.Sprintf("repos/%s/issues/%d", repo, pr.Number), issue, nil); err != nil {
+		return output, fmt.Errorf("failed to label %s: %w", pr.HTMLURL, err)
+	}
+
+	if len(opts.Reviewers) > 0 {
+		users, teams := splitReviewers(opts.Reviewers)
+		err := apiCall(ctx, http.MethodPost, fmt.Sprintf("repos/%s/pulls/%d/requested_reviewers", repo, pr.Number),
+			map[string][]string{"reviewers": users, "team_reviewers": teams}, nil)
+		if err != nil {
+			return output, fmt.Errorf("failed to request reviews on %s: %w", pr.HTMLURL, err)
+		}
+	}
+	return output, nil
+}
+
+// branchSyncTimeout is how long PR creation waits for a branch pushed to a
+// mirror to show up on GitHub.
+const branchSyncTimeout = 5 * time.Minute
+
+// branchSyncInterval is how often GitHub is checked for the branch.
+var branchSyncInterval = 10 * time.Second
+
+// waitForBranch waits until branch exists in repo on GitHub.
+func waitForBranch(ctx context.Context, repo, branch string) error {
+	deadline := time.Now().Add(branchSyncTimeout)
+	for {
+		err := apiCall(ctx, http.MethodGet, "repos/"+repo+"/branches/"+branch, nil, nil)
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("branch %s was pushed to the mirror but has not reached %s on GitHub after %s", branch, repo, branchSyncTimeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(branchSyncInterval):
+		}
+	}
+}
+
+// findMilestone returns the number of the open milestone with title.
+func findMilestone(ctx context.Context, repo, title string) (int, error) {
+	output, err := getPages(ctx, "repos/"+repo+"/milestones?state=open&per_page=100")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list milestones: %w", err)
+	}
+	milestones, err := decodePages[struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	}](output)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse milestones: %w", err)
+	}
+	for _, m := range milestones {
+		if m.Title == title {
+			return m.Number, nil
+		}
+	}
+	return 0, fmt.Errorf("no open milestone %q in %s", title, repo)
+}
+
+// splitReviewers separates users from org/team slugs, which are requested
+// by team name.
+func splitReviewers(reviewers []string) (users, teams []string) {
+	users, teams = []string{}, []string{}
+	for _, reviewer := range reviewers {
+		reviewer = strings.TrimPrefix(strings.TrimSpace(reviewer), "@")
+		if _, team, ok := strings.Cut(reviewer, "/"); ok {
+			teams = append(teams, team)
+		} else {
+			users = append(users, reviewer)
+		}
+	}
+	return users, teams
+}
+
+// FindPullRequest returns the URL of the open PR from branchName in the repo
+// checked out at targetPath, or "" when there is none.
+func FindPullRequest(ctx context.Context, targetPath, branchName string) (string, error) {
+	owner, name, err := originRepo(ctx, targetPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to look for an open PR from %s: %w", branchName, err)
+	}
+	headOwner := owner
+	if fork := forkOwner(ctx, targetPath); fork != "" {
+		headOwner = fork
+	}
+
+	var prs []pullRequest
+	path := fmt.Sprintf("repos/%s/%s/pulls?state=open&per_page=1&head=%s", owner, name, url.QueryEscape(headOwner+":"+branchName))
+	if err := apiCall(ctx, http.MethodGet, path, nil, &prs); err != nil {
+		return "", fmt.Errorf("failed to look for an open PR from %s: %w", branchName, err)
+	}
+	if len(prs) == 0 {
+		return "", nil
+	}
+	return prs[0].HTMLURL, nil
+}
+
+var pullRequestURLPattern = regexp.MustCompile(`^https?://[^/]+/([^/]+)/([^/]+)/pull/(\d+)`)
+
+// pullRequestPath returns the API path of the PR at url.
+func pullRequestPath(url string) (string, error) {
+	m := pullRequestURLPattern.FindStringSubmatch(strings.TrimSpace(url))
+	if m == nil {
+		return "", fmt.Errorf("%q is not a pull request URL", url)
+	}
+	return fmt.Sprintf("repos/%s/%s/pulls/%s", m[1], m[2], m[3]), nil
+}
+
+// fetchPullRequest returns the PR at url.
+func fetchPullRequest(ctx context.Context, url string) (pullRequest, error) {
+	var pr pullRequest
+	path, err := pullRequestPath(url)
+	if err != nil {
+		return pr, err
+	}
+	err = apiCall(ctx, http.MethodGet, path, nil, &pr)
+	return pr, err
+}
+
+// UpdatePullRequest replaces the title and description of the PR at url.
+func UpdatePullRequest(ctx context.Context, url, prTitle, prDescription string) error {
+	path, err := pullRequestPath(url)
+	if err == nil {
+		err = apiCall(ctx, http.MethodPatch, path, map[string]string{"title": prTitle, "body": prDescription}, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update the PR: %w", err)
+	}
+	return nil
 }
 
-func CreatePullRequest(ctx context.Context, project config.Project, targetPath string, branchName string, prTitle string, prDescription string) ([]byte, error) {
-	ensureLabelExists(ctx, targetPath)
+// FetchPullRequestDiff returns the diff of the PR at url.
+func FetchPullRequestDiff(ctx context.Context, url string) (string, error) {
+	path, err := pullRequestPath(url)
+	if err != nil {
+		return "", err
+	}
+	output, _, err := apiRequest(ctx, http.MethodGet, path, nil, "application/vnd.github.diff")
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch the diff of %s: %w", url, err)
+	}
+	return string(output), nil
+}
 
-	// Get the default branch for this repository
-	cmd := exec.CommandContext(ctx, "git", "symbolic-ref", "refs/remotes/origin/HEAD", "--short")
-	cmd.Dir = targetPath
-	defaultBranchOutput, err := cmd.CombinedOutput()
+// EnableAutoMerge has GitHub squash-merge the PR at url once branch
+// protection allows it: its required checks and reviews pass.
+func EnableAutoMerge(ctx context.Context, url string) error {
+	pr, err := fetchPullRequest(ctx, url)
+	if err == nil {
+		err = graphQL(ctx, `mutation($id: ID!) { enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: SQUASH}) { clientMutationId } }`,
+			map[string]any{"id": pr.NodeID}, nil)
+	}
 	if err != nil {
-		defaultBranchOutput = []byte("origin/main")
+		return fmt.Errorf("failed to enable auto-merge: %w", err)
 	}
-	defaultBranch := strings.TrimPrefix(strings.TrimSpace(string(defaultBranchOutput)), "origin/")
+	return nil
+}
 
-	return runGhContext(ctx, targetPath, "pr", "create",
-		"--title", prTitle,
-		"--body", prDescription,
-		"--base", defaultBranch,
-		"--head", branchName,
-		"--label", "copycat")
+// FetchPullRequestTimes returns when the PR at url was created and merged.
+// mergedAt is the zero time if the PR has not been merged.
+func FetchPullRequestTimes(ctx context.Context, url string) (createdAt, mergedAt time.Time, err error) {
+	pr, err := fetchPullRequest(ctx, url)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to view %s: %w", url, err)
+	}
+	if pr.MergedAt != nil {
+		mergedAt = *pr.MergedAt
+	}
+	return pr.CreatedAt, mergedAt, nil
 }
@@ -0,0 +1,170 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// Quota is what GitHub last reported about one of its API rate limits.
+type Quota struct {
+	Resource  string // core, graphql, search...
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+var (
+	quotaMu   sync.Mutex
+	quotas    = make(map[string]Quota)
+	rateLimit config.RateLimit
+	onWait    func(q Quota, waiting bool)
+)
+
+// SetRateLimit sets when API requests wait for a quota to reset.
+func SetRateLimit(r config.RateLimit) {
+	quotaMu.Lock()
+	defer quotaMu.Unlock()
+	rateLimit = r
+}
+
+// OnQuotaWait sets f to be told when API requests start waiting for q to
+// reset (waiting is true) and when they go on again. Passing nil stops it.
+func OnQuotaWait(f func(q Quota, waiting bool)) {
+	quotaMu.Lock()
+	defer quotaMu.Unlock()
+	onWait = f
+}
+
+func currentRateLimit() config.RateLimit {
+	quotaMu.Lock()
+	defer quotaMu.Unlock()
+	return rateLimit
+}
+
+// quotaResource is the rate limit a request to url counts against, or ""
+// for requests that don't count.
+func quotaResource(url string) string {
+	switch {
+	case url == graphqlURL:
+		return "graphql"
+	case strings.HasPrefix(url, apiURL+"search/"):
+		return "search"
+	case url == apiURL+"rate_limit":
+		return ""
+	}
+	return "core"
+}
+
+// observeQuota records the rate limit headers of a response to a request
+// against resource.
+func observeQuota(header http.Header, resource string) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if r := header.Get("X-RateLimit-Resource"); r != "" {
+		resource = r
+	}
+	quotaMu.Lock()
+	defer quotaMu.Unlock()
+	quotas[resource] = Quota{Resource: resource, Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+}
+
+// Low reports whether requests against q wait for it to reset: it has yet to
+// reset and fewer requests are left than the configured floor, or a tenth of
+// the limit for small quotas such as search.
+func (q Quota) Low() bool {
+	return q.low(currentRateLimit().Floor(), time.Now())
+}
+
+func (q Quota) low(floor int, now time.Time) bool {
+	if q.Limit > 0 {
+		floor = min(floor, q.Limit/10)
+	}
+	return q.Remaining < floor && q.Reset.After(now)
+}
+
+// lowQuota returns resource's quota if requests against it must wait.
+func lowQuota(resource string, now time.Time) (Quota, bool) {
+	quotaMu.Lock()
+	defer quotaMu.Unlock()
+	q, ok := quotas[resource]
+	if !ok || !q.low(rateLimit.Floor(), now) {
+		return Quota{}, false
+	}
+	return q, true
+}
+
+// waitForQuota holds a request against resource back until its quota
+// resets, when the quota is nearly used up. A reset further off than the
+// configured wait is a RateLimitError.
+func waitForQuota(ctx context.Context, method, path, resource string) error {
+	q, low := lowQuota(resource, time.Now())
+	if !low {
+		return nil
+	}
+	wait := time.Until(q.Reset)
+	if wait > currentRateLimit().MaxWait() {
+		return &RateLimitError{Reset: q.Reset}
+	}
+	recordAPI(ctx, method, path, 0, fmt.Errorf("%s quota low (%d left), waiting %s for it to reset", q.Resource, q.Remaining, wait.Round(time.Second)))
+
+	quotaMu.Lock()
+	notify := onWait
+	quotaMu.Unlock()
+	if notify != nil {
+		notify(q, true)
+		defer notify(q, false)
+	}
+	// GitHub counts the reset second itself against the old window
+	if !sleep(ctx, wait+time.Second) {
+		return ctx.Err()
+	}
+
+	quotaMu.Lock()
+	defer quotaMu.Unlock()
+	if quotas[resource] == q {
+		delete(quotas, resource)
+	}
+	return nil
+}
+
+// FetchRateLimits returns the token's API quotas, which GitHub reports
+// without counting the request against them. Requests made afterwards wait
+// for any quota that is already nearly used up.
+func FetchRateLimits(ctx context.Context) ([]Quota, error) {
+	var resp struct {
+		Resources map[string]struct {
+			Limit     int   `json:"limit"`
+			Remaining int   `json:"remaining"`
+			Reset     int64 `json:"reset"`
+		} `json:"resources"`
+	}
+	if err := apiCall(ctx, http.MethodGet, "rate_limit", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	quotaMu.Lock()
+	defer quotaMu.Unlock()
+	var result []Quota
+	for resource, r := range resp.Resources {
+		q := Quota{Resource: resource, Limit: r.Limit, Remaining: r.Remaining, Reset: time.Unix(r.Reset, 0)}
+		quotas[resource] = q
+		result = append(result, q)
+	}
+	slices.SortFunc(result, func(a, b Quota) int { return strings.Compare(a.Resource, b.Resource) })
+	return result, nil
+}
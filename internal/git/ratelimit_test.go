@@ -0,0 +1,80 @@
+package git
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestRequestsWaitForALowQuotaToReset(t *testing.T) {
+	reset := time.Now().Add(time.Second).Unix()
+	var sent []time.Time
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, time.Now())
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "3")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		_, _ = w.Write([]byte(`{}`))
+	})
+	SetRateLimit(config.RateLimit{MinRemaining: 10})
+	t.Cleanup(func() { SetRateLimit(config.RateLimit{}) })
+
+	var waits []bool
+	var waitedFor Quota
+	OnQuotaWait(func(q Quota, waiting bool) {
+		waits = append(waits, waiting)
+		waitedFor = q
+	})
+	t.Cleanup(func() { OnQuotaWait(nil) })
+
+	ctx := context.Background()
+	for range 2 {
+		if err := apiCall(ctx, http.MethodGet, "repos/acme/ledger", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(sent) != 2 || sent[1].Unix() <= reset {
+		t.Errorf("expected the second request to wait for the reset at %d, got %v", reset, sent)
+	}
+	if len(waits) != 2 || !waits[0] || waits[1] {
+		t.Errorf("expected to be told when requests paused and resumed, got %v", waits)
+	}
+	if waitedFor.Resource != "core" || waitedFor.Remaining != 3 || waitedFor.Limit != 5000 {
+		t.Errorf("expected the low core quota to be reported, got %+v", waitedFor)
+	}
+}
+
+func TestFetchRateLimits(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rate_limit" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"resources":{
+  "core":{"limit":5000,"remaining":20,"reset":` + strconv.FormatInt(reset, 10) + `},
+  "graphql":{"limit":5000,"remaining":4999,"reset":` + strconv.FormatInt(reset, 10) + `},
+  "search":{"limit":30,"remaining":30,"reset":` + strconv.FormatInt(reset, 10) + `}}}`))
+	})
+
+	got, err := FetchRateLimits(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].Resource != "core" || got[0].Remaining != 20 || got[1].Resource != "graphql" {
+		t.Errorf("expected the core, graphql and search quotas, got %+v", got)
+	}
+	if _, low := lowQuota("core", time.Now()); !low {
+		t.Error("expected the core quota, below the default floor, to hold requests back")
+	}
+	if _, low := lowQuota("graphql", time.Now()); low {
+		t.Error("expected the graphql quota to leave requests alone")
+	}
+	if _, low := lowQuota("search", time.Now()); low {
+		t.Error("expected the small but unused search quota to leave requests alone")
+	}
+}
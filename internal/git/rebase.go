@@ -0,0 +1,68 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// RebaseOntoBase fetches the base branch of the clone at repoPath and, when
+// it has moved since the branch was created, rebases the branch and its
+// uncommitted changes onto it. It reports whether the base moved; an error
+// means the changes conflict with the new commits and were left as they were.
+func RebaseOntoBase(ctx context.Context, project config.Project, repoPath string) (bool, error) {
+	base := project.TargetBranch
+	if base == "" {
+		base = strings.TrimPrefix(gitOutput(ctx, repoPath, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"), "origin/")
+		if base == "" {
+			return false, nil
+		}
+	}
+	r := currentRetries()
+	fetch := func() *exec.Cmd {
+		cmd := exec.CommandContext(ctx, "git", "fetch", "origin", base)
+		cmd.Dir = repoPath
+		return cmd
+	}
+	if output, err := retryGit(ctx, r.Attempts(r.Fetch), fetch); err != nil {
+		return false, fmt.Errorf("failed to fetch %s: %v (%s)", base, err, output)
+	}
+	upstream := "origin/" + base
+	if _, err := runGit(ctx, repoPath, "merge-base", "--is-ancestor", upstream, "HEAD"); err == nil {
+		return false, nil
+	}
+
+	stashed := gitOutput(ctx, repoPath, "status", "--porcelain") != ""
+	if stashed {
+		if output, err := runGit(ctx, repoPath, "stash", "push", "-u", "-q"); err != nil {
+			return true, fmt.Errorf("failed to set the changes aside: %v (%s)", err, output)
+		}
+	}
+	if output, err := runGit(ctx, repoPath, "rebase", "-q", upstream); err != nil {
+		_, _ = runGit(ctx, repoPath, "rebase", "--abort")
+		if stashed {
+			_, _ = runGit(ctx, repoPath, "stash", "pop", "-q")
+		}
+		return true, fmt.Errorf("%s moved and the branch's commits conflict with it: %s", base, output)
+	}
+	if stashed {
+		if output, err := runGit(ctx, repoPath, "stash", "pop", "-q"); err != nil {
+			// Back to the commit the changes were made on, where they apply cleanly
+			_, _ = runGit(ctx, repoPath, "reset", "-q", "--hard", "ORIG_HEAD")
+			_, _ = runGit(ctx, repoPath, "stash", "pop", "-q")
+			return true, fmt.Errorf("%s moved and the changes conflict with it: %s", base, output)
+		}
+	}
+	return true, nil
+}
+
+// runGit runs git in repoPath and returns its trimmed combined output.
+func runGit(ctx context.Context, repoPath string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+	output, err := combinedOutput(ctx, cmd)
+	return strings.TrimSpace(string(output)), err
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestRebaseOntoBase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	clone := filepath.Join(root, "clone")
+	other := filepath.Join(root, "other")
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+	write := func(dir, name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run(root, "init", "-q", "--bare", "-b", "main", origin)
+	run(root, "clone", "-q", origin, other)
+	write(other, "README.md", "# api\n")
+	run(other, "add", "-A")
+	run(other, "commit", "-q", "-m", "init")
+	run(other, "push", "-q", "origin", "main")
+	run(root, "clone", "-q", origin, clone)
+	run(clone, "checkout", "-q", "-b", "copycat-1")
+
+	ctx := context.Background()
+	project := config.Project{Repo: "api"}
+	write(clone, "CHANGELOG.md", "Bumped.\n")
+	if moved, err := RebaseOntoBase(ctx, project, clone); moved || err != nil {
+		t.Fatalf("expected an unmoved base to be left alone, got %v %v", moved, err)
+	}
+
+	// Someone else lands a commit while the run is going
+	write(other, "go.mod", "module api\n")
+	run(other, "add", "-A")
+	run(other, "commit", "-q", "-m", "add go.mod")
+	run(other, "push", "-q", "origin", "main")
+
+	if moved, err := RebaseOntoBase(ctx, project, clone); !moved || err != nil {
+		t.Fatalf("expected the changes to be rebased onto the moved base, got %v %v", moved, err)
+	}
+	for _, name := range []string{"go.mod", "CHANGELOG.md"} {
+		if _, err := os.Stat(filepath.Join(clone, name)); err != nil {
+			t.Errorf("expected %s after the rebase: %v", name, err)
+		}
+	}
+
+	// A change to the same lines cannot be rebased
+	write(clone, "README.md", "# api service\n")
+	write(other, "README.md", "# the api\n")
+	run(other, "commit", "-q", "-am", "rename")
+	run(other, "push", "-q", "origin", "main")
+	if moved, err := RebaseOntoBase(ctx, project, clone); !moved || err == nil {
+		t.Fatalf("expected a conflict to be reported, got %v %v", moved, err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(clone, "README.md")); string(content) != "# api service\n" {
+		t.Errorf("expected the changes to be left as they were, got %q", content)
+	}
+}
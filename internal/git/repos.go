@@ -1,60 +1,62 @@
 package git
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/saltpay/copycat/v2/internal/config"
 )
 
-// GitHubRepo represents the JSON response from gh repo list
+// GitHubRepo is a repository as listed by discovery, or as returned by the
+// REST API when forking.
 type GitHubRepo struct {
-	Name             string  `json:"name"`
-	IsArchived       bool    `json:"isArchived"`
-	RepositoryTopics []Topic `json:"repositoryTopics"`
+	Name          string    `json:"name"`
+	Archived      bool      `json:"archived"`
+	Topics        []string  `json:"topics"`
+	DefaultBranch string    `json:"default_branch"`
+	Language      string    `json:"language"`
+	PushedAt      time.Time `json:"pushed_at"`
+	SSHURL        string    `json:"ssh_url"`
+	CloneURL      string    `json:"clone_url"`
 }
 
-type Topic struct {
-	Topic string `json:"name"`
-}
-
-// FetchRepositories fetches unarchived repositories with the specified topic from GitHub
+// FetchRepositories fetches unarchived repositories with the specified topic
+// from GitHub that pass the discovery filter and are not excluded
 func FetchRepositories(githubCfg config.GitHubConfig) ([]config.Project, error) {
-	// Use gh CLI to fetch repositories
-	args := []string{
-		"repo", "list", githubCfg.Organization,
-		"--json", "name,isArchived,repositoryTopics",
-	}
-	if githubCfg.AutoDiscoveryTopic != "" {
-		args = append(args, "--topic", githubCfg.AutoDiscoveryTopic)
-	}
-	args = append(args, "--no-archived", "--limit", "1000")
-
-	output, err := runGh("", args...)
+	repos, err := listRepositories(context.Background(), githubCfg.Organization)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch repositories from GitHub: %w\nOutput: %s", err, string(output))
-	}
-
-	var repos []GitHubRepo
-	if err := json.Unmarshal(output, &repos); err != nil {
-		return nil, fmt.Errorf("failed to parse GitHub response: %w", err)
+		return nil, fmt.Errorf("failed to fetch repositories from GitHub: %w", err)
 	}
 
+	now := time.Now()
 	var projects []config.Project
 	for _, repo := range repos {
-		var topics []string
-		for _, topic := range repo.RepositoryTopics {
-			topics = append(topics, topic.Topic)
+		if repo.Archived || githubCfg.IsExcluded(repo.Name) {
+			continue
+		}
+		if githubCfg.AutoDiscoveryTopic != "" && !slices.Contains(repo.Topics, githubCfg.AutoDiscoveryTopic) {
+			continue
 		}
 
 		project := config.Project{
-			Repo:   repo.Name,
-			Topics: topics,
+			Repo:          repo.Name,
+			Topics:        repo.Topics,
+			DefaultBranch: repo.DefaultBranch,
+			Language:      repo.Language,
+			PushedAt:      repo.PushedAt,
+		}
+		if !githubCfg.Discovery.Matches(project, now) {
+			continue
 		}
 		projects = append(projects, project)
 	}
 
 	if len(projects) == 0 {
+		if !githubCfg.Discovery.IsZero() {
+			return nil, fmt.Errorf("no unarchived repositories in organization '%s' match the discovery filter '%s'", githubCfg.Organization, githubCfg.Discovery)
+		}
 		if githubCfg.AutoDiscoveryTopic == "" {
 			return nil, fmt.Errorf("no unarchived repositories found in organization '%s'", githubCfg.Organization)
 		}
@@ -63,3 +65,86 @@ func FetchRepositories(githubCfg config.GitHubConfig) ([]config.Project, error)
 
 	return projects, nil
 }
+
+// repositoriesQuery lists a page of an organization's or user's repos with
+// everything discovery records, so each page is a single request.
+const repositoriesQuery = `query($owner: String!, $cursor: String) {
+  repositoryOwner(login: $owner) {
+    repositories(first: 100, after: $cursor, ownerAffiliations: [OWNER], orderBy: {field: NAME, direction: ASC}) {
+      nodes {
+        name
+        isArchived
+        pushedAt
+        defaultBranchRef { name }
+        primaryLanguage { name }
+        repositoryTopics(first: 20) { nodes { topic { name } } }
+      }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}`
+
+type repositoriesPage struct {
+	RepositoryOwner *struct {
+		Repositories struct {
+			Nodes []struct {
+				Name             string    `json:"name"`
+				IsArchived       bool      `json:"isArchived"`
+				PushedAt         time.Time `json:"pushedAt"`
+				DefaultBranchRef *struct {
+					Name string `json:"name"`
+				} `json:"defaultBranchRef"`
+				PrimaryLanguage *struct {
+					Name string `json:"name"`
+				} `json:"primaryLanguage"`
+				RepositoryTopics struct {
+					Nodes []struct {
+						Topic struct {
+							Name string `json:"name"`
+						} `json:"topic"`
+					} `json:"nodes"`
+				} `json:"repositoryTopics"`
+			} `json:"nodes"`
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"repositories"`
+	} `json:"repositoryOwner"`
+}
+
+// listRepositories lists every repository of an organization or user with
+// their topics, default branch, primary language and last push, following
+// the pages to the end.
+func listRepositories(ctx context.Context, owner string) ([]GitHubRepo, error) {
+	var repos []GitHubRepo
+	vars := map[string]any{"owner": owner, "cursor": nil}
+	for {
+		var page repositoriesPage
+		if err := graphQL(ctx, repositoriesQuery, vars, &page); err != nil {
+			return nil, err
+		}
+		if page.RepositoryOwner == nil {
+			return nil, fmt.Errorf("%w: no organization or user '%s'", ErrNotFound, owner)
+		}
+
+		conn := page.RepositoryOwner.Repositories
+		for _, node := range conn.Nodes {
+			repo := GitHubRepo{Name: node.Name, Archived: node.IsArchived, PushedAt: node.PushedAt}
+			if node.DefaultBranchRef != nil {
+				repo.DefaultBranch = node.DefaultBranchRef.Name
+			}
+			if node.PrimaryLanguage != nil {
+				repo.Language = node.PrimaryLanguage.Name
+			}
+			for _, topic := range node.RepositoryTopics.Nodes {
+				repo.Topics = append(repo.Topics, topic.Topic.Name)
+			}
+			repos = append(repos, repo)
+		}
+		if !conn.PageInfo.HasNextPage {
+			return repos, nil
+		}
+		vars["cursor"] = conn.PageInfo.EndCursor
+	}
+}
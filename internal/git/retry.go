@@ -0,0 +1,117 @@
+package git
+
+import (
+	"context"
+	"math/rand/v2"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+var (
+	retryMu sync.Mutex
+	retries config.Retries
+
+	// retryBaseDelay is the wait before the first retry; each retry after it
+	// waits twice as long, up to maxRetryDelay.
+	retryBaseDelay = time.Second
+	maxRetryDelay  = 30 * time.Second
+
+	// transientFailures counts the transient failures and rate limits hit
+	// by every job, whether or not a retry then got through.
+	transientFailures atomic.Int64
+)
+
+// TransientFailures is how many transient failures and rate limits clones,
+// fetches, pushes and API requests have hit so far. A run compares it over
+// time to tell when GitHub or the network is struggling.
+func TransientFailures() int64 {
+	return transientFailures.Load()
+}
+
+// SetRetries sets how many times clones, fetches, pushes and API requests
+// are tried on transient failures.
+func SetRetries(r config.Retries) {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	retries = r
+}
+
+func currentRetries() config.Retries {
+	retryMu.Lock()
+	defer retryMu.Unlock()
+	return retries
+}
+
+// transientGitOutput is what git prints for failures worth another try:
+// network blips and the server being briefly unavailable.
+var transientGitOutput = []string{
+	"could not resolve host",
+	"connection reset",
+	"connection refused",
+	"connection timed out",
+	"operation timed out",
+	"early eof",
+	"the remote end hung up unexpectedly",
+	"unexpected disconnect",
+	"rpc failed",
+	"returned error: 5",
+	"temporary failure",
+	"broken pipe",
+	"secondary rate limit",
+}
+
+func isTransientGitFailure(output []byte) bool {
+	out := strings.ToLower(string(output))
+	for _, s := range transientGitOutput {
+		if strings.Contains(out, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryGit runs the command built by newCmd, running a fresh one up to
+// attempts times in all while it fails with a transient error.
+func retryGit(ctx context.Context, attempts int, newCmd func() *exec.Cmd) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		output, err := combinedOutput(ctx, newCmd())
+		if err == nil || !isTransientGitFailure(output) {
+			return output, err
+		}
+		transientFailures.Add(1)
+		if attempt >= attempts {
+			return output, err
+		}
+		delay := retryDelay(attempt)
+		recordGitRetry(ctx, delay)
+		if !sleep(ctx, delay) {
+			return output, err
+		}
+	}
+}
+
+// retryDelay is the exponential backoff before retry number attempt, with
+// jitter so parallel jobs that failed together don't retry together.
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// sleep waits for d, reporting false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
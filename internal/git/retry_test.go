@@ -0,0 +1,90 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// fastRetries makes retries wait a millisecond instead of seconds.
+func fastRetries(t *testing.T, r config.Retries) {
+	t.Helper()
+	oldBase := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	SetRetries(r)
+	t.Cleanup(func() {
+		retryBaseDelay = oldBase
+		SetRetries(config.Retries{})
+	})
+}
+
+func TestRetryGit(t *testing.T) {
+	fastRetries(t, config.Retries{})
+	run := func(attempts int, failures int, output string) (int, error) {
+		calls := 0
+		_, err := retryGit(context.Background(), attempts, func() *exec.Cmd {
+			calls++
+			if calls <= failures {
+				return exec.Command("sh", "-c", "echo '"+output+"'; exit 128")
+			}
+			return exec.Command("true")
+		})
+		return calls, err
+	}
+
+	if calls, err := run(3, 2, "fatal: the remote end hung up unexpectedly"); err != nil || calls != 3 {
+		t.Errorf("expected a transient failure to succeed on the third attempt, got %d attempts (%v)", calls, err)
+	}
+	if calls, err := run(2, 2, "error: RPC failed; HTTP 502"); err == nil || calls != 2 {
+		t.Errorf("expected to give up after 2 attempts, got %d attempts (%v)", calls, err)
+	}
+	if calls, err := run(3, 1, "error: failed to push some refs (non-fast-forward)"); err == nil || calls != 1 {
+		t.Errorf("expected a rejected push not to be retried, got %d attempts (%v)", calls, err)
+	}
+}
+
+func TestAPIRetriesTransientFailures(t *testing.T) {
+	fastRetries(t, config.Retries{API: 2})
+	calls := map[string]int{}
+	useTestAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		switch r.URL.Path {
+		case "/repos/acme/flaky":
+			if calls[r.URL.Path] == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		case "/repos/acme/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/repos/acme/throttled":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"You have exceeded a secondary rate limit."}`))
+		case "/repos/acme/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"Resource not accessible by integration"}`))
+		}
+	})
+	ctx := context.Background()
+
+	if err := apiCall(ctx, http.MethodGet, "repos/acme/flaky", nil, nil); err != nil || calls["/repos/acme/flaky"] != 2 {
+		t.Errorf("expected a 502 to be retried, got %d calls (%v)", calls["/repos/acme/flaky"], err)
+	}
+	var apiErr *APIError
+	if err := apiCall(ctx, http.MethodGet, "repos/acme/down", nil, nil); !errors.As(err, &apiErr) || calls["/repos/acme/down"] != 2 {
+		t.Errorf("expected to give up after the configured attempts, got %d calls (%v)", calls["/repos/acme/down"], err)
+	}
+	_ = apiCall(ctx, http.MethodGet, "repos/acme/throttled", nil, nil)
+	if calls["/repos/acme/throttled"] != 2 {
+		t.Errorf("expected a secondary rate limit to be retried, got %d calls", calls["/repos/acme/throttled"])
+	}
+	_ = apiCall(ctx, http.MethodGet, "repos/acme/forbidden", nil, nil)
+	if calls["/repos/acme/forbidden"] != 1 {
+		t.Errorf("expected a plain 403 not to be retried, got %d calls", calls["/repos/acme/forbidden"])
+	}
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// signingArgs returns the git -c options that make a commit signed as
+// configured, or none when commit signing is not configured.
+func signingArgs(signing config.CommitSigning) []string {
+	if signing == (config.CommitSigning{}) {
+		return nil
+	}
+	format := signing.Format
+	if format == "" || format == config.SigningGPG {
+		format = "openpgp"
+	}
+	args := []string{"-c", "commit.gpgsign=true", "-c", "gpg.format=" + format}
+	if key := signing.Key; key != "" {
+		// git does not expand ~ in values given with -c
+		if rest, ok := strings.CutPrefix(key, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				key = filepath.Join(home, rest)
+			}
+		}
+		args = append(args, "-c", "user.signingkey="+key)
+	}
+	return args
+}
+
+// verifySigned checks that HEAD in repoPath carries a signature, so an
+// unsigned commit is never pushed to an org that rejects them.
+func verifySigned(ctx context.Context, repoPath string) error {
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "commit", "HEAD")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to read the commit: %v", err)
+	}
+	header, _, _ := bytes.Cut(output, []byte("\n\n"))
+	for _, line := range bytes.Split(header, []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("gpgsig ")) || bytes.HasPrefix(line, []byte("gpgsig-sha256 ")) {
+			return nil
+		}
+	}
+	return fmt.Errorf("the commit is not signed; check commit_signing and the signing key")
+}
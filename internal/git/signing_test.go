@@ -0,0 +1,64 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestSigningArgs(t *testing.T) {
+	if args := signingArgs(config.CommitSigning{}); args != nil {
+		t.Errorf("expected no args without signing, got %v", args)
+	}
+
+	args := signingArgs(config.CommitSigning{Format: config.SigningGPG, Key: "ABCD1234"})
+	want := []string{"-c", "commit.gpgsign=true", "-c", "gpg.format=openpgp", "-c", "user.signingkey=ABCD1234"}
+	if !slices.Equal(args, want) {
+		t.Errorf("expected %v, got %v", want, args)
+	}
+}
+
+func TestVerifySigned(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+
+	dir := t.TempDir()
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	if output, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v\n%s", err, output)
+	}
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	ctx := context.Background()
+
+	run("init", "-q")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "-A")
+	run("commit", "-q", "--no-gpg-sign", "-m", "unsigned")
+	if err := verifySigned(ctx, dir); err == nil {
+		t.Error("expected an unsigned commit to fail verification")
+	}
+
+	signing := config.CommitSigning{Format: config.SigningSSH, Key: key}
+	run(append(signingArgs(signing), "commit", "-q", "--allow-empty", "-m", "signed")...)
+	if err := verifySigned(ctx, dir); err != nil {
+		t.Errorf("expected the signed commit to pass verification: %v", err)
+	}
+}
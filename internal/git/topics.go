@@ -1,30 +1,49 @@
 package git
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/saltpay/copycat/v2/internal/config"
 )
 
-type repoTopicsResponse struct {
-	Names []string `json:"names"`
-}
-
-var errRepoNotFound = errors.New("repository not found")
-
 // SyncTopicsWithCache ensures GitHub topics reflect the cached project metadata.
+// The current topics of every repo come from a single listing of the
+// organization's repositories.
 func SyncTopicsWithCache(projects []config.Project, githubCfg config.GitHubConfig) error {
 	if len(projects) == 0 {
 		return nil
 	}
 
+	ctx := context.Background()
 	owner := githubCfg.Organization
+	repos, err := listRepositories(ctx, owner)
+	if err != nil {
+		return fmt.Errorf("failed to fetch topics for %s: %w", owner, err)
+	}
+	topicsByRepo := make(map[string][]string, len(repos))
+	for _, repo := range repos {
+		topicsByRepo[repo.Name] = repo.Topics
+	}
+
+	synced := make(map[string]bool)
 	for _, project := range projects {
-		if err := syncProjectTopics(project, owner, githubCfg); err != nil {
+		// Entries for several paths of a monorepo share its topics
+		if synced[project.Repo] {
+			continue
+		}
+		synced[project.Repo] = true
+		existingTopics, ok := topicsByRepo[project.Repo]
+		if !ok {
+			reportTopicFailure(project.Repo)
+			continue
+		}
+		if err := syncProjectTopics(ctx, project, owner, existingTopics, githubCfg); err != nil {
 			return err
 		}
 	}
@@ -32,83 +51,37 @@ func SyncTopicsWithCache(projects []config.Project, githubCfg config.GitHubConfi
 	return nil
 }
 
-func syncProjectTopics(project config.Project, owner string, githubCfg config.GitHubConfig) error {
+func syncProjectTopics(ctx context.Context, project config.Project, owner string, existingTopics []string, githubCfg config.GitHubConfig) error {
 	repoSlug := fmt.Sprintf("%s/%s", owner, project.Repo)
 
-	existingTopics, err := fetchRepositoryTopics(owner, project.Repo)
-	if err != nil {
-		if errors.Is(err, errRepoNotFound) {
-			reportTopicFailure(project.Repo)
-			return nil
-		}
-		return fmt.Errorf("failed to fetch topics for %s: %w", repoSlug, err)
-	}
-
 	addTopics, removeTopics := computeTopicChanges(existingTopics, project, githubCfg)
 	if len(addTopics) == 0 && len(removeTopics) == 0 {
 		fmt.Printf("✓ %s topics already up to date\n", project.Repo)
 		return nil
 	}
 
-	args := []string{"repo", "edit", repoSlug}
-	for _, t := range addTopics {
-		args = append(args, "--add-topic", t)
-	}
-	for _, t := range removeTopics {
-		args = append(args, "--remove-topic", t)
+	// The API replaces the whole list of topics
+	names := append(slices.Clone(existingTopics), addTopics...)
+	names = slices.DeleteFunc(names, func(t string) bool { return slices.Contains(removeTopics, t) })
+	names = deduplicate(names)
+	sort.Strings(names)
+	if names == nil {
+		names = []string{}
 	}
 
-	output, err := runGh("", args...)
+	err := apiCall(ctx, http.MethodPut, fmt.Sprintf("repos/%s/topics", repoSlug), map[string][]string{"names": names}, nil)
 	if err != nil {
-		if isNotFoundResponse(string(output)) {
+		if errors.Is(err, ErrNotFound) {
 			reportTopicFailure(project.Repo)
 			return nil
 		}
-		return fmt.Errorf("failed to update topics for %s: %w\nOutput: %s", repoSlug, err, strings.TrimSpace(string(output)))
+		return fmt.Errorf("failed to update topics for %s: %w", repoSlug, err)
 	}
 
 	fmt.Printf("✓ Synced topics for %s (added: %s removed: %s)\n", project.Repo, summarizeTopics(addTopics), summarizeTopics(removeTopics))
 	return nil
 }
 
-func fetchRepositoryTopics(owner, repo string) ([]string, error) {
-	args := []string{
-		"api",
-		fmt.Sprintf("repos/%s/%s/topics", owner, repo),
-		"-H", "Accept: application/vnd.github+json",
-	}
-
-	output, err := runGh("", args...)
-	if err != nil {
-		outputStr := strings.TrimSpace(string(output))
-		if isNotFoundResponse(outputStr) {
-			return nil, fmt.Errorf("%w: %s", errRepoNotFound, outputStr)
-		}
-		return nil, fmt.Errorf("gh api fetch topics failed: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
-	}
-
-	var resp repoTopicsResponse
-	if err := json.Unmarshal(output, &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse topics response: %w", err)
-	}
-
-	// Deduplicate and sort for consistency.
-	unique := make(map[string]struct{}, len(resp.Names))
-	for _, name := range resp.Names {
-		if name != "" {
-			unique[name] = struct{}{}
-		}
-	}
-
-	topics := make([]string, 0, len(unique))
-	for name := range unique {
-		topics = append(topics, name)
-	}
-
-	sort.Strings(topics)
-	return topics, nil
-}
-
 func computeTopicChanges(existing []string, project config.Project, githubCfg config.GitHubConfig) (addTopics []string, removeTopics []string) {
 	existingSet := make(map[string]struct{}, len(existing))
 	for _, topic := range existing {
@@ -193,15 +166,6 @@ func summarizeTopics(items []string) string {
 	return strings.Join(items, ", ")
 }
 
-func isNotFoundResponse(output string) bool {
-	if output == "" {
-		return false
-	}
-
-	lower := strings.ToLower(output)
-	return strings.Contains(lower, "404") && strings.Contains(lower, "not found")
-}
-
 func reportTopicFailure(repo string) {
 	fmt.Printf("✘ %s (could not update topics in repository)\n", repo)
 }
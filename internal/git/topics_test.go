@@ -87,59 +87,6 @@ func TestSummarizeTopics(t *testing.T) {
 	}
 }
 
-func TestIsNotFoundResponse(t *testing.T) {
-	tests := []struct {
-		name   string
-		output string
-		want   bool
-	}{
-		{
-			name:   "empty string",
-			output: "",
-			want:   false,
-		},
-		{
-			name:   "404 not found",
-			output: "HTTP 404: Not Found (https://api.github.com/repos/org/repo)",
-			want:   true,
-		},
-		{
-			name:   "404 lowercase",
-			output: "http 404: not found",
-			want:   true,
-		},
-		{
-			name:   "404 only",
-			output: "404",
-			want:   false,
-		},
-		{
-			name:   "not found only",
-			output: "not found",
-			want:   false,
-		},
-		{
-			name:   "success message",
-			output: "Successfully updated topics",
-			want:   false,
-		},
-		{
-			name:   "other error",
-			output: "permission denied",
-			want:   false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := isNotFoundResponse(tt.output)
-			if got != tt.want {
-				t.Errorf("isNotFoundResponse() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
 func TestComputeTopicChanges(t *testing.T) {
 	tests := []struct {
 		name        string
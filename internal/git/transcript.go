@@ -0,0 +1,118 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transcript records the git commands run and the GitHub API requests made
+// for one project, with their output, so failures can be read apart from the
+// AI tool's transcript. Attach it to a context with WithTranscript.
+type Transcript struct {
+	mu  sync.Mutex
+	git strings.Builder
+	api strings.Builder
+}
+
+type transcriptKey struct{}
+
+// WithTranscript returns a context whose git commands and API requests are
+// recorded in t.
+func WithTranscript(ctx context.Context, t *Transcript) context.Context {
+	return context.WithValue(ctx, transcriptKey{}, t)
+}
+
+func transcriptFrom(ctx context.Context) *Transcript {
+	t, _ := ctx.Value(transcriptKey{}).(*Transcript)
+	return t
+}
+
+// Git returns the git commands run so far, each followed by its output.
+func (t *Transcript) Git() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.git.String()
+}
+
+// API returns the GitHub API requests made so far, with their status and,
+// for failures, the response.
+func (t *Transcript) API() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.api.String()
+}
+
+// recordGit adds a finished git (or gh) command to the context's transcript.
+func recordGit(ctx context.Context, args []string, output []byte, err error) {
+	t := transcriptFrom(ctx)
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.git.WriteString("$ " + strings.Join(args, " ") + "\n")
+	if out := strings.TrimSpace(string(output)); out != "" {
+		t.git.WriteString(out + "\n")
+	}
+	if err != nil {
+		t.git.WriteString(fmt.Sprintf("✗ %v\n", err))
+	}
+}
+
+// recordGitRetry notes in the context's transcript that the last git
+// command failed transiently and is tried again after delay.
+func recordGitRetry(ctx context.Context, delay time.Duration) {
+	t := transcriptFrom(ctx)
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.git.WriteString(fmt.Sprintf("↻ retrying in %s\n", delay.Round(time.Millisecond)))
+}
+
+// recordAPI adds a finished API request to the context's transcript, with
+// its status when a response came back.
+func recordAPI(ctx context.Context, method, path string, status int, err error) {
+	t := transcriptFrom(ctx)
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if path == graphqlURL {
+		path = "graphql"
+	}
+	line := method + " " + path
+	if status != 0 {
+		line += fmt.Sprintf(" → %d", status)
+	}
+	t.api.WriteString(line + "\n")
+	if err != nil {
+		t.api.WriteString(fmt.Sprintf("✗ %v\n", err))
+	}
+}
+
+// recordAPIFailure adds an error found in a successful response, such as a
+// GraphQL error, to the context's transcript.
+func recordAPIFailure(ctx context.Context, err error) {
+	t := transcriptFrom(ctx)
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.api.WriteString(fmt.Sprintf("✗ %v\n", err))
+}
+
+// combinedOutput runs a git command like cmd.CombinedOutput, recording it in
+// the context's transcript.
+func combinedOutput(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	output, err := cmd.CombinedOutput()
+	recordGit(ctx, cmd.Args, output, err)
+	return output, err
+}
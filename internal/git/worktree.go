@@ -0,0 +1,77 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+)
+
+// AddWorktree checks out origin/<branch> of the clone at repoPath into a new
+// detached worktree at worktreePath, so several base branches can be worked on
+// from one clone. When sparsePath is set, only that directory and the files
+// at the root and along the way to it are checked out.
+func AddWorktree(ctx context.Context, repoPath, worktreePath, branch, sparsePath string) error {
+	// git resolves relative worktree paths against the clone, not our working directory
+	worktreePath, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return err
+	}
+
+	r := currentRetries()
+	output, err := retryGit(ctx, r.Attempts(r.Fetch), func() *exec.Cmd {
+		cmd := exec.CommandContext(ctx, "git", "fetch", "origin", branch)
+		cmd.Dir = repoPath
+		return cmd
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch branch %s: %v (%s)", branch, err, string(output))
+	}
+
+	args := []string{"worktree", "add", "--detach", worktreePath, "origin/" + branch}
+	if sparsePath != "" {
+		args = []string{"worktree", "add", "--no-checkout", "--detach", worktreePath, "origin/" + branch}
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to create worktree for %s: %v (%s)", branch, err, string(output))
+	}
+	if sparsePath == "" {
+		return nil
+	}
+
+	// The sparse-checkout patterns are per worktree, so jobs sharing the
+	// clone for another path or service keep their own checkout
+	cmd = exec.CommandContext(ctx, "git", "sparse-checkout", "set", "--cone", sparsePath)
+	cmd.Dir = worktreePath
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to limit checkout to %s: %v (%s)", sparsePath, err, string(output))
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "checkout")
+	cmd.Dir = worktreePath
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to check out %s: %v (%s)", sparsePath, err, string(output))
+	}
+	return nil
+}
+
+// RemoveWorktree deletes the worktree at worktreePath and prunes its metadata from repoPath.
+func RemoveWorktree(ctx context.Context, repoPath, worktreePath string) error {
+	worktreePath, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "worktree", "remove", "--force", worktreePath)
+	cmd.Dir = repoPath
+	if output, err := combinedOutput(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to remove worktree %s: %v (%s)", worktreePath, err, string(output))
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "worktree", "prune")
+	cmd.Dir = repoPath
+	_ = cmd.Run()
+	return nil
+}
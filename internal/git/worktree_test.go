@@ -0,0 +1,118 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorktrees(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin")
+	clone := filepath.Join(root, "clone")
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+
+	if err := os.MkdirAll(origin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	run(origin, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(origin, "VERSION"), []byte("2.0"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run(origin, "add", "-A")
+	run(origin, "commit", "-q", "-m", "main")
+	run(origin, "checkout", "-q", "-b", "release/1.x")
+	if err := os.WriteFile(filepath.Join(origin, "VERSION"), []byte("1.9"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run(origin, "commit", "-q", "-am", "release")
+	run(origin, "checkout", "-q", "main")
+	run(root, "clone", "-q", origin, clone)
+
+	worktree := filepath.Join(root, "clone@release")
+	if err := AddWorktree(context.Background(), clone, worktree, "release/1.x", ""); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(worktree, "VERSION"))
+	if err != nil || string(data) != "1.9" {
+		t.Fatalf("expected release branch content in worktree, got %q (%v)", data, err)
+	}
+
+	if err := RemoveWorktree(context.Background(), clone, worktree); err != nil {
+		t.Fatalf("RemoveWorktree failed: %v", err)
+	}
+	if _, err := os.Stat(worktree); !os.IsNotExist(err) {
+		t.Error("expected worktree directory to be removed")
+	}
+}
+
+func TestSparseWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin")
+	clone := filepath.Join(root, "clone")
+	run := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+		return string(out)
+	}
+
+	for _, file := range []string{"go.mod", "services/README.md", "services/payments/main.go", "services/ledger/main.go"} {
+		path := filepath.Join(origin, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run(origin, "init", "-q", "-b", "main")
+	run(origin, "add", "-A")
+	run(origin, "commit", "-q", "-m", "main")
+	run(root, "clone", "-q", "--no-checkout", "file://"+origin, clone)
+
+	sparse := filepath.Join(root, "clone@payments")
+	if err := AddWorktree(context.Background(), clone, sparse, "HEAD", "services/payments"); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	for _, file := range []string{"go.mod", "services/README.md", "services/payments/main.go"} {
+		if _, err := os.Stat(filepath.Join(sparse, file)); err != nil {
+			t.Errorf("expected %s to be checked out: %v", file, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(sparse, "services/ledger")); !os.IsNotExist(err) {
+		t.Error("expected services/ledger to be left out of the sparse worktree")
+	}
+	if status := run(sparse, "status", "--porcelain"); status != "" {
+		t.Errorf("expected a clean sparse worktree, got %q", status)
+	}
+
+	full := filepath.Join(root, "clone@full")
+	if err := AddWorktree(context.Background(), clone, full, "HEAD", ""); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(full, "services/ledger/main.go")); err != nil {
+		t.Errorf("expected a full checkout next to the sparse worktree: %v", err)
+	}
+}
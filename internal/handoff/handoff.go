@@ -0,0 +1,117 @@
+// Package handoff exports a paused run to a bundle that another operator can
+// import to resume the run on their machine, e.g. for campaigns spanning shifts.
+package handoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// version is the bundle format version; bundles of other versions are rejected.
+const version = 1
+
+// Wizard holds the wizard answers the run was started with. The AI tool is
+// stored by name, to be looked up in the importer's own configuration.
+type Wizard struct {
+	Action                  string              `json:"action"`
+	Tool                    string              `json:"tool,omitempty"`
+	IgnoreAgentInstructions bool                `json:"ignore_agent_instructions,omitempty"`
+	TempChanges             []config.TempChange `json:"temp_changes,omitempty"` // with the content to write
+	BranchStrategy          string              `json:"branch_strategy,omitempty"`
+	BranchName              string              `json:"branch_name,omitempty"`
+	UpdatePR                bool                `json:"update_pr,omitempty"`
+	PRTitle                 string              `json:"pr_title,omitempty"`
+	AutoMerge               bool                `json:"auto_merge,omitempty"`
+	PRLabels                []string            `json:"pr_labels,omitempty"`
+	PRMilestone             string              `json:"pr_milestone,omitempty"`
+	Prompt                  string              `json:"prompt"` // as last edited at a checkpoint
+	LicenseHeader           bool                `json:"license_header,omitempty"`
+	Actions                 string              `json:"actions,omitempty"` // action pipeline, by name
+	TriageAlerts            bool                `json:"triage_alerts,omitempty"`
+	VerifyFailure           string              `json:"verify_failure,omitempty"`
+	StructuredAssessment    bool                `json:"structured_assessment,omitempty"`
+	FlakyTests              bool                `json:"flaky_tests,omitempty"`
+	CampaignID              string              `json:"campaign_id,omitempty"`
+}
+
+// Repo is a repo that finished before the handoff.
+type Repo struct {
+	Key    string `json:"key"`
+	Status string `json:"status"`
+	PRURL  string `json:"pr_url,omitempty"`
+}
+
+// Bundle is everything needed to resume a paused run.
+type Bundle struct {
+	Version      int       `json:"version"`
+	ExportedAt   time.Time `json:"exported_at"`
+	ExportedBy   string    `json:"exported_by,omitempty"`
+	Organization string    `json:"organization"`
+	Wizard       Wizard    `json:"wizard"`
+	Completed    []Repo    `json:"completed"`           // across every shift so far
+	Pending      []string  `json:"pending"`             // project keys, in processing order
+	SpentUSD     float64   `json:"spent_usd,omitempty"` // reported AI spend so far
+}
+
+// Save writes the bundle to a new file in dir and returns its path.
+func Save(dir string, b Bundle) (string, error) {
+	b.Version = version
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create handoff directory: %w", err)
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("handoff-%s.json", b.ExportedAt.Format("20060102-150405")))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write handoff bundle %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// Load reads a bundle written by Save.
+func Load(path string) (Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("failed to read handoff bundle: %w", err)
+	}
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return Bundle{}, fmt.Errorf("failed to parse handoff bundle %s: %w", path, err)
+	}
+	if b.Version != version {
+		return Bundle{}, fmt.Errorf("handoff bundle %s has version %d, this copycat reads version %d", path, b.Version, version)
+	}
+	if len(b.Pending) == 0 {
+		return Bundle{}, errors.New("handoff bundle has no pending repos; the run is already complete")
+	}
+	return b, nil
+}
+
+// Select returns the projects still to process, in the bundle's order, and
+// the pending keys that match no project. projects are expanded per service;
+// keys of code-change runs also name their target branch.
+func Select(projects []config.Project, pending []string) ([]config.Project, []string) {
+	byKey := make(map[string]config.Project)
+	for _, p := range slices.Concat(projects, config.ExpandTargetBranches(projects)) {
+		byKey[p.Key()] = p
+	}
+	var selected []config.Project
+	var missing []string
+	for _, key := range pending {
+		if p, ok := byKey[key]; ok {
+			selected = append(selected, p)
+		} else if !slices.Contains(missing, key) {
+			missing = append(missing, key)
+		}
+	}
+	return selected, missing
+}
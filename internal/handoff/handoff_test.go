@@ -0,0 +1,65 @@
+package handoff
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	bundle := Bundle{
+		ExportedAt:   time.Date(2024, 3, 4, 18, 30, 0, 0, time.UTC),
+		ExportedBy:   "alice",
+		Organization: "acme",
+		Wizard:       Wizard{Action: "local", Tool: "claude", BranchName: "copycat-20240304", PRTitle: "Bump Go", Prompt: "Bump Go to 1.22"},
+		Completed:    []Repo{{Key: "payments", Status: "Completed ✅", PRURL: "https://github.com/acme/payments/pull/1"}},
+		Pending:      []string{"ledger", "web@release"},
+		SpentUSD:     4.2,
+	}
+
+	path, err := Save(dir, bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle.Version = version
+	loaded.ExportedAt = loaded.ExportedAt.UTC()
+	if !reflect.DeepEqual(loaded, bundle) {
+		t.Errorf("loaded %+v, want %+v", loaded, bundle)
+	}
+
+	bundle.Pending = nil
+	path, err = Save(t.TempDir(), bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected a bundle without pending repos to be rejected")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	projects := []config.Project{
+		{Repo: "ledger"},
+		{Repo: "web", TargetBranches: []string{"main", "release"}},
+		{Repo: "payments"},
+	}
+
+	selected, missing := Select(projects, []string{"web@release", "ledger", "gone", "web"})
+	var keys []string
+	for _, p := range selected {
+		keys = append(keys, p.Key())
+	}
+	if want := []string{"web@release", "ledger", "web"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("selected %v, want %v", keys, want)
+	}
+	if !reflect.DeepEqual(missing, []string{"gone"}) {
+		t.Errorf("missing %v, want [gone]", missing)
+	}
+}
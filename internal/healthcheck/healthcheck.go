@@ -0,0 +1,112 @@
+// Package healthcheck decides when scheduled health checks are due and turns
+// their latest run, compared with the one before, into a Slack digest.
+package healthcheck
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/saltpay/copycat/v2/internal/ai"
+	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/history"
+)
+
+// slack is how much earlier than its interval a check may run again, so a
+// weekly cron job that starts a few minutes early still runs it.
+const slack = time.Hour
+
+// maxSummaryLen caps the AI summary quoted in the digest.
+const maxSummaryLen = 2500
+
+// Due reports whether check should run at now, given its recorded runs
+// (oldest first).
+func Due(check config.HealthCheck, runs []history.Record, now time.Time) bool {
+	if len(runs) == 0 {
+		return true
+	}
+	last := runs[len(runs)-1].StartedAt
+	return !now.Before(last.Add(check.Interval() - slack))
+}
+
+// Digest renders the Slack message for the latest of runs (oldest first):
+// status totals, then the repos whose status changed since the previous run,
+// regressions first, then the AI summary.
+func Digest(check config.HealthCheck, runs []history.Record, summary string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🩺 *Health check: %s*\n> %s\n\n", check.Name, strings.Join(strings.Fields(check.Question), " "))
+	if len(runs) == 0 {
+		b.WriteString("No run was recorded.")
+		return b.String()
+	}
+
+	latest := runs[len(runs)-1]
+	b.WriteString(totals(latest))
+	b.WriteString("\n\n")
+
+	if len(runs) < 2 {
+		b.WriteString("First run, nothing to compare with yet.")
+	} else {
+		previous := runs[len(runs)-2]
+		since := previous.StartedAt.Local().Format("Jan 2")
+		var regressed, improved, other []string
+		for _, c := range history.CompareAssessments(previous, latest) {
+			if !c.Changed() {
+				continue
+			}
+			line := fmt.Sprintf("%s: %s → %s", c.Repo, history.AssessmentLabel(c.Before), history.AssessmentLabel(c.After))
+			switch c.Trend() {
+			case -1:
+				regressed = append(regressed, "🔻 "+line)
+			case 1:
+				improved = append(improved, "🟢 "+line)
+			default:
+				other = append(other, "• "+line)
+			}
+		}
+		changes := append(append(regressed, improved...), other...)
+		if len(changes) == 0 {
+			fmt.Fprintf(&b, "No status changes since %s.", since)
+		} else {
+			fmt.Fprintf(&b, "*Changes since %s* (%d regressed, %d improved)\n%s", since, len(regressed), len(improved), strings.Join(changes, "\n"))
+		}
+	}
+
+	if summary = strings.TrimSpace(summary); summary != "" {
+		if len(summary) > maxSummaryLen {
+			end := maxSummaryLen
+			for end > 0 && !utf8.RuneStart(summary[end]) {
+				end--
+			}
+			summary = summary[:end] + "…"
+		}
+		b.WriteString("\n\n*Summary*\n")
+		b.WriteString(summary)
+	}
+	return b.String()
+}
+
+// totals counts the latest run's repos by status, e.g.
+// "34/50 compliant · 10 partial · 6 non compliant".
+func totals(run history.Record) string {
+	counts := make(map[string]int)
+	for _, r := range run.Repos {
+		status := r.Status
+		if status == "" {
+			status = r.Outcome
+			if status == "succeeded" {
+				status = "unscored"
+			}
+		}
+		counts[status]++
+	}
+
+	parts := []string{fmt.Sprintf("%d/%d compliant", counts[ai.StatusCompliant], len(run.Repos))}
+	for _, status := range []string{ai.StatusPartial, ai.StatusNonCompliant, ai.StatusUnknown, "unscored", "failed", "cancelled"} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], strings.ReplaceAll(status, "_", " ")))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,81 @@
+package healthcheck
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/history"
+)
+
+func TestDue(t *testing.T) {
+	weekly := config.HealthCheck{Name: "breakers"}
+	daily := config.HealthCheck{Name: "breakers", Every: config.HealthCheckDaily}
+	lastRun := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	runs := []history.Record{{StartedAt: lastRun}}
+
+	tests := []struct {
+		name  string
+		check config.HealthCheck
+		runs  []history.Record
+		now   time.Time
+		want  bool
+	}{
+		{"never run", weekly, nil, lastRun, true},
+		{"ran yesterday", weekly, runs, lastRun.Add(24 * time.Hour), false},
+		{"a week later, a little early", weekly, runs, lastRun.Add(7*24*time.Hour - 10*time.Minute), true},
+		{"daily, next day", daily, runs, lastRun.Add(24 * time.Hour), true},
+	}
+	for _, tt := range tests {
+		if got := Due(tt.check, tt.runs, tt.now); got != tt.want {
+			t.Errorf("%s: Due() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDigest(t *testing.T) {
+	score := func(n int) *int { return &n }
+	check := config.HealthCheck{Name: "breakers", Question: "Do they use\ncircuit breakers?"}
+	previous := history.Record{StartedAt: time.Date(2024, 3, 4, 9, 0, 0, 0, time.Local), Repos: []history.RepoRecord{
+		{Repo: "payments", Outcome: "succeeded", Status: "compliant", Score: score(90)},
+		{Repo: "ledger", Outcome: "succeeded", Status: "non_compliant", Score: score(10)},
+		{Repo: "web", Outcome: "succeeded", Status: "partial", Score: score(50)},
+	}}
+	latest := history.Record{StartedAt: time.Date(2024, 3, 11, 9, 0, 0, 0, time.Local), Repos: []history.RepoRecord{
+		{Repo: "payments", Outcome: "succeeded", Status: "partial", Score: score(60)},
+		{Repo: "ledger", Outcome: "succeeded", Status: "compliant", Score: score(80)},
+		{Repo: "web", Outcome: "succeeded", Status: "partial", Score: score(55)},
+		{Repo: "search", Outcome: "failed"},
+	}}
+
+	digest := Digest(check, []history.Record{previous, latest}, "Most services use resilience4j.")
+	for _, want := range []string{
+		"🩺 *Health check: breakers*\n> Do they use circuit breakers?",
+		"1/4 compliant · 2 partial · 1 failed",
+		"*Changes since Mar 4* (1 regressed, 1 improved)\n🔻 payments: compliant (90) → partial (60)\n🟢 ledger: non_compliant (10) → compliant (80)\n• search: not assessed → failed",
+		"*Summary*\nMost services use resilience4j.",
+	} {
+		if !strings.Contains(digest, want) {
+			t.Errorf("digest does not contain %q:\n%s", want, digest)
+		}
+	}
+	if strings.Contains(digest, "web:") {
+		t.Errorf("digest lists an unchanged repo:\n%s", digest)
+	}
+
+	first := Digest(check, []history.Record{latest}, "")
+	if !strings.Contains(first, "First run, nothing to compare with yet.") || strings.Contains(first, "*Summary*") {
+		t.Errorf("unexpected first digest:\n%s", first)
+	}
+}
+
+func TestDigestCutsSummaryBetweenCharacters(t *testing.T) {
+	// "é" is two bytes, the second of them at the cut
+	summary := strings.Repeat("a", maxSummaryLen-1) + "é" + strings.Repeat("a", 10)
+	digest := Digest(config.HealthCheck{Name: "breakers"}, []history.Record{{}}, summary)
+	if !utf8.ValidString(digest) || !strings.HasSuffix(digest, "\n"+strings.Repeat("a", maxSummaryLen-1)+"…") {
+		t.Errorf("expected the summary cut before the split character, got ...%q", digest[len(digest)-20:])
+	}
+}
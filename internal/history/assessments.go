@@ -0,0 +1,99 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/ai"
+)
+
+// AssessmentRuns returns the recorded assessments that asked question, oldest
+// first. Questions match regardless of whitespace.
+func AssessmentRuns(records []Record, question string) []Record {
+	question = strings.Join(strings.Fields(question), " ")
+	var runs []Record
+	for _, rec := range records {
+		if rec.Action == "assessment" && strings.Join(strings.Fields(rec.Prompt), " ") == question {
+			runs = append(runs, rec)
+		}
+	}
+	return runs
+}
+
+// StatusChange is one repo's result in two assessment runs. Before or After is
+// nil when the repo was not part of that run.
+type StatusChange struct {
+	Repo   string
+	Before *RepoRecord
+	After  *RepoRecord
+}
+
+// Changed reports whether the repo's status differs between the runs; score
+// changes alone do not count.
+func (c StatusChange) Changed() bool {
+	return assessmentStatus(c.Before) != assessmentStatus(c.After)
+}
+
+// Trend is 1 when the repo's structured status improved between the runs, -1
+// when it regressed, and 0 otherwise (including when either run has no status).
+func (c StatusChange) Trend() int {
+	if c.Before == nil || c.After == nil {
+		return 0
+	}
+	before, bok := ai.StatusRank[c.Before.Status]
+	after, aok := ai.StatusRank[c.After.Status]
+	switch {
+	case !bok || !aok || before == after:
+		return 0
+	case after > before:
+		return 1
+	}
+	return -1
+}
+
+// CompareAssessments pairs up each repo of two runs: repos in the latest run
+// first, in its order, then repos only the earlier run assessed.
+func CompareAssessments(previous, latest Record) []StatusChange {
+	before := make(map[string]*RepoRecord, len(previous.Repos))
+	for i := range previous.Repos {
+		before[previous.Repos[i].Repo] = &previous.Repos[i]
+	}
+
+	var changes []StatusChange
+	seen := make(map[string]bool)
+	for i := range latest.Repos {
+		repo := &latest.Repos[i]
+		seen[repo.Repo] = true
+		changes = append(changes, StatusChange{Repo: repo.Repo, Before: before[repo.Repo], After: repo})
+	}
+	for i := range previous.Repos {
+		repo := &previous.Repos[i]
+		if !seen[repo.Repo] {
+			changes = append(changes, StatusChange{Repo: repo.Repo, Before: repo})
+		}
+	}
+	return changes
+}
+
+// AssessmentLabel describes a repo's assessment result: its structured status
+// and score when there is one, otherwise how the run went.
+func AssessmentLabel(r *RepoRecord) string {
+	if r != nil && r.Status != "" && r.Score != nil {
+		return fmt.Sprintf("%s (%d)", r.Status, *r.Score)
+	}
+	return assessmentStatus(r)
+}
+
+// assessmentStatus is the structured status, or the run outcome for repos
+// without one.
+func assessmentStatus(r *RepoRecord) string {
+	switch {
+	case r == nil:
+		return "not assessed"
+	case r.Status != "":
+		return r.Status
+	case r.Outcome == "succeeded":
+		return "assessed"
+	}
+	return r.Outcome
+}
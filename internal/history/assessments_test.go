@@ -0,0 +1,74 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAssessmentRuns(t *testing.T) {
+	records := []Record{
+		{Action: "assessment", Prompt: "Do they use  circuit breakers?", StartedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Action: "local", Prompt: "Do they use circuit breakers?"},
+		{Action: "assessment", Prompt: "Something else?"},
+		{Action: "assessment", Prompt: "Do they use circuit breakers?\n", StartedAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	runs := AssessmentRuns(records, "Do they use circuit breakers?")
+	if len(runs) != 2 || runs[0].StartedAt.Month() != time.January || runs[1].StartedAt.Month() != time.February {
+		t.Errorf("unexpected runs: %+v", runs)
+	}
+}
+
+func TestCompareAssessments(t *testing.T) {
+	score := func(n int) *int { return &n }
+	previous := Record{Repos: []RepoRecord{
+		{Repo: "a", Outcome: "succeeded", Status: "non_compliant", Score: score(20)},
+		{Repo: "b", Outcome: "succeeded", Status: "compliant", Score: score(90)},
+		{Repo: "gone", Outcome: "succeeded"},
+	}}
+	latest := Record{Repos: []RepoRecord{
+		{Repo: "b", Outcome: "succeeded", Status: "compliant", Score: score(95)},
+		{Repo: "a", Outcome: "succeeded", Status: "compliant", Score: score(85)},
+		{Repo: "new", Outcome: "failed"},
+	}}
+
+	changes := CompareAssessments(previous, latest)
+	want := []struct {
+		repo          string
+		before, after string
+		changed       bool
+	}{
+		{"b", "compliant (90)", "compliant (95)", false},
+		{"a", "non_compliant (20)", "compliant (85)", true},
+		{"new", "not assessed", "failed", true},
+		{"gone", "assessed", "not assessed", true},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), changes)
+	}
+	for i, w := range want {
+		c := changes[i]
+		if c.Repo != w.repo || AssessmentLabel(c.Before) != w.before || AssessmentLabel(c.After) != w.after || c.Changed() != w.changed {
+			t.Errorf("change %d = %s %q -> %q (%v), want %+v", i, c.Repo, AssessmentLabel(c.Before), AssessmentLabel(c.After), c.Changed(), w)
+		}
+	}
+}
+
+func TestStatusChangeTrend(t *testing.T) {
+	record := func(status string) *RepoRecord { return &RepoRecord{Outcome: "succeeded", Status: status} }
+	tests := []struct {
+		change StatusChange
+		want   int
+	}{
+		{StatusChange{Before: record("partial"), After: record("compliant")}, 1},
+		{StatusChange{Before: record("compliant"), After: record("non_compliant")}, -1},
+		{StatusChange{Before: record("partial"), After: record("partial")}, 0},
+		{StatusChange{Before: record(""), After: record("compliant")}, 0},
+		{StatusChange{After: record("compliant")}, 0},
+	}
+	for i, tt := range tests {
+		if got := tt.change.Trend(); got != tt.want {
+			t.Errorf("case %d: Trend() = %d, want %d", i, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/ai"
+)
+
+// RepoRecord is the outcome of a single repository in a recorded run.
+type RepoRecord struct {
+	Repo     string `json:"repo"`
+	Outcome  string `json:"outcome"`
+	PRURL    string `json:"pr_url,omitempty"`
+	IssueURL string `json:"issue_url,omitempty"` // issue opened by an issues run
+	Tool     string `json:"tool,omitempty"`      // AI tool that made the changes
+
+	// Usage is the tokens and cost the AI tool reported, if any
+	Usage *ai.Usage `json:"usage,omitempty"`
+
+	// Structured assessment answer, when one was given
+	Status string `json:"status,omitempty"`
+	Score  *int   `json:"score,omitempty"`
+}
+
+// Record is one line of the run history log.
+type Record struct {
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt time.Time    `json:"finished_at,omitzero"`
+	Action     string       `json:"action"`
+	Campaign   string       `json:"campaign"`
+	CampaignID string       `json:"campaign_id,omitempty"` // groups the runs of a campaign for its report
+	Prompt     string       `json:"prompt,omitempty"`
+	Repos      []RepoRecord `json:"repos"`
+	Usage      *ai.Usage    `json:"usage,omitempty"` // total over the repos
+}
+
+// Append adds a record to the history log at path, creating it if needed.
+func Append(path string, rec Record) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode history record: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open history %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history %s: %w", path, err)
+	}
+	return nil
+}
+
+// Load reads every record from the history log. A missing log yields no records.
+func Load(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			// Skip lines from older or partially written runs
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// Issue is an issue opened by a recorded run.
+type Issue struct {
+	URL       string
+	Repo      string
+	Title     string
+	CreatedAt time.Time // when the run that opened it started
+}
+
+// CreatedIssues returns the unique issues opened by past runs, oldest first.
+func CreatedIssues(records []Record) []Issue {
+	seen := make(map[string]bool)
+	var issues []Issue
+	for _, rec := range records {
+		for _, repo := range rec.Repos {
+			if repo.IssueURL == "" || seen[repo.IssueURL] {
+				continue
+			}
+			seen[repo.IssueURL] = true
+			issues = append(issues, Issue{URL: repo.IssueURL, Repo: repo.Repo, Title: rec.Campaign, CreatedAt: rec.StartedAt})
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].CreatedAt.Before(issues[j].CreatedAt) })
+	return issues
+}
+
+// CampaignPRs returns the unique PR URLs opened across all code-change runs
+// that share the campaign name (the PR title), in first-seen order.
+func CampaignPRs(records []Record, campaign string) []string {
+	seen := make(map[string]bool)
+	var urls []string
+	for _, rec := range records {
+		if rec.Action != "local" || rec.Campaign != campaign {
+			continue
+		}
+		for _, repo := range rec.Repos {
+			if repo.PRURL == "" || seen[repo.PRURL] {
+				continue
+			}
+			seen[repo.PRURL] = true
+			urls = append(urls, repo.PRURL)
+		}
+	}
+	return urls
+}
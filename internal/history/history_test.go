@@ -0,0 +1,165 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/ai"
+)
+
+func TestAppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.jsonl")
+
+	records, err := Load(path)
+	if err != nil || len(records) != 0 {
+		t.Fatalf("expected empty history for missing file, got %v, %v", records, err)
+	}
+
+	first := Record{Action: "local", Campaign: "Bump deps", Repos: []RepoRecord{{Repo: "a", Outcome: "succeeded", PRURL: "https://github.com/org/a/pull/1"}}}
+	second := Record{Action: "assessment", Campaign: "Which Go version?", Repos: []RepoRecord{{Repo: "b", Outcome: "succeeded"}}}
+	for _, rec := range []Record{first, second} {
+		if err := Append(path, rec); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	records, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if records[0].Campaign != "Bump deps" || records[0].Repos[0].PRURL != first.Repos[0].PRURL {
+		t.Errorf("unexpected first record: %+v", records[0])
+	}
+}
+
+func TestCampaignPRs(t *testing.T) {
+	records := []Record{
+		{Action: "local", Campaign: "Bump deps", Repos: []RepoRecord{{Repo: "a", PRURL: "u1"}, {Repo: "b"}}},
+		{Action: "local", Campaign: "Other", Repos: []RepoRecord{{Repo: "c", PRURL: "u2"}}},
+		{Action: "local", Campaign: "Bump deps", Repos: []RepoRecord{{Repo: "a", PRURL: "u1"}, {Repo: "d", PRURL: "u3"}}},
+	}
+
+	urls := CampaignPRs(records, "Bump deps")
+	if len(urls) != 2 || urls[0] != "u1" || urls[1] != "u3" {
+		t.Errorf("expected [u1 u3], got %v", urls)
+	}
+}
+
+func TestCreatedIssues(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 9, 0, 0, 0, time.UTC) }
+	records := []Record{
+		{StartedAt: day(2), Action: "issues", Campaign: "Enable Dependabot", Repos: []RepoRecord{{Repo: "a", IssueURL: "i2"}, {Repo: "b", Outcome: "failed"}}},
+		{StartedAt: day(1), Action: "issues", Campaign: "Add CODEOWNERS", Repos: []RepoRecord{{Repo: "a", IssueURL: "i1"}}},
+		{StartedAt: day(3), Action: "issues", Campaign: "Enable Dependabot", Repos: []RepoRecord{{Repo: "b", IssueURL: "i3"}, {Repo: "a", IssueURL: "i2"}}},
+		{StartedAt: day(4), Action: "local", Campaign: "Bump deps", Repos: []RepoRecord{{Repo: "a", PRURL: "u1"}}},
+	}
+
+	issues := CreatedIssues(records)
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 unique issues, got %+v", issues)
+	}
+	for i, want := range []string{"i1", "i2", "i3"} {
+		if issues[i].URL != want {
+			t.Errorf("expected issue %d to be %s, got %+v", i, want, issues[i])
+		}
+	}
+	if issues[1].Repo != "a" || issues[1].Title != "Enable Dependabot" || !issues[1].CreatedAt.Equal(day(2)) {
+		t.Errorf("unexpected issue %+v", issues[1])
+	}
+}
+
+func TestBuildCampaign(t *testing.T) {
+	day := func(d, h int) time.Time { return time.Date(2024, 5, d, h, 0, 0, 0, time.UTC) }
+	prs := []PRState{
+		{URL: "u1", CreatedAt: day(1, 10), MergedAt: day(2, 9)},
+		{URL: "u2", CreatedAt: day(1, 11)},
+		{URL: "u3", CreatedAt: day(3, 8), MergedAt: day(3, 12)},
+	}
+
+	campaign := BuildCampaign("Bump deps", prs, day(4, 18))
+
+	expected := []DayPoint{
+		{Day: day(1, 0), Opened: 2, Merged: 0},
+		{Day: day(2, 0), Opened: 2, Merged: 1},
+		{Day: day(3, 0), Opened: 3, Merged: 2},
+		{Day: day(4, 0), Opened: 3, Merged: 2},
+	}
+	if len(campaign.Points) != len(expected) {
+		t.Fatalf("expected %d points, got %v", len(expected), campaign.Points)
+	}
+	for i, want := range expected {
+		got := campaign.Points[i]
+		if !got.Day.Equal(want.Day) || got.Opened != want.Opened || got.Merged != want.Merged {
+			t.Errorf("point %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+	if len(campaign.Open) != 1 || campaign.Open[0] != "u2" {
+		t.Errorf("expected only u2 to be open, got %v", campaign.Open)
+	}
+}
+
+func TestRecentPrompts(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	records := []Record{
+		{StartedAt: day(1), Action: "local", Campaign: "Bump deps", Prompt: "bump"},
+		{StartedAt: day(2), Action: "assessment", Prompt: "Which Go version?"},
+		{StartedAt: day(3), Action: "local", Campaign: "Bump deps", Prompt: "bump"},
+		{StartedAt: day(4), Action: "local", Campaign: "No prompt"},
+		{StartedAt: day(5), Action: "local", Campaign: "Add CI", Prompt: "add ci"},
+	}
+
+	entries := RecentPrompts(records, 10)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 distinct prompts, got %+v", entries)
+	}
+	if entries[0].Title != "Add CI" || entries[1].Title != "Bump deps" || entries[2].Action != "assessment" {
+		t.Errorf("expected newest first, got %+v", entries)
+	}
+	if !entries[1].UsedAt.Equal(day(3)) {
+		t.Errorf("expected latest use of repeated prompt, got %v", entries[1].UsedAt)
+	}
+
+	if got := RecentPrompts(records, 1); len(got) != 1 || got[0].Title != "Add CI" {
+		t.Errorf("expected limit to apply, got %+v", got)
+	}
+}
+
+func TestBuildCampaignReport(t *testing.T) {
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	records := []Record{
+		{StartedAt: start, FinishedAt: start.Add(20 * time.Minute), Action: "local", CampaignID: "q3-deps", Usage: &ai.Usage{CostUSD: 1.5}, Repos: []RepoRecord{
+			{Repo: "a", Outcome: "succeeded", PRURL: "u1"},
+			{Repo: "b", Outcome: "failed"},
+		}},
+		{StartedAt: start.Add(time.Hour), FinishedAt: start.Add(time.Hour + 5*time.Minute), Action: "local", CampaignID: "other", Repos: []RepoRecord{{Repo: "c", Outcome: "succeeded"}}},
+		{StartedAt: start.Add(24 * time.Hour), FinishedAt: start.Add(24*time.Hour + 10*time.Minute), Action: "local", CampaignID: "q3-deps", Usage: &ai.Usage{CostUSD: 0.5}, Repos: []RepoRecord{
+			{Repo: "a", Outcome: "skipped"},
+			{Repo: "b", Outcome: "succeeded", PRURL: "u2"},
+		}},
+	}
+
+	report, ok := BuildCampaignReport(records, "q3-deps")
+	if !ok {
+		t.Fatal("expected the campaign's runs to be found")
+	}
+	if report.Runs != 2 || report.Duration != 30*time.Minute || report.Usage.CostUSD != 2 {
+		t.Errorf("unexpected totals %+v", report)
+	}
+	if !report.First.Equal(start) || !report.Last.Equal(start.Add(24*time.Hour+10*time.Minute)) {
+		t.Errorf("unexpected period %s to %s", report.First, report.Last)
+	}
+	if outcomes := report.Outcomes(); outcomes["succeeded"] != 2 || len(outcomes) != 1 || report.PRs() != 2 {
+		t.Errorf("expected the retry's success and the first run's PR to count, got %+v", report.Repos)
+	}
+	if report.Repos[0].Attempts != 2 {
+		t.Errorf("expected a to be attempted twice, got %+v", report.Repos[0])
+	}
+
+	if _, ok := BuildCampaignReport(records, "missing"); ok {
+		t.Error("expected no report for an unknown campaign")
+	}
+}
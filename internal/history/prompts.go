@@ -0,0 +1,33 @@
+package history
+
+import "time"
+
+// PromptEntry is a previously used prompt offered for reuse in the wizard.
+type PromptEntry struct {
+	Action string // "local" or "assessment"
+	Title  string // PR title, empty for assessments
+	Prompt string
+	UsedAt time.Time
+}
+
+// RecentPrompts returns up to limit distinct prompts from records, most
+// recently used first. Runs repeating the same title and prompt are listed once.
+func RecentPrompts(records []Record, limit int) []PromptEntry {
+	type key struct{ action, title, prompt string }
+	seen := make(map[key]bool)
+
+	var entries []PromptEntry
+	for i := len(records) - 1; i >= 0 && len(entries) < limit; i-- {
+		rec := records[i]
+		if rec.Prompt == "" {
+			continue
+		}
+		k := key{rec.Action, rec.Campaign, rec.Prompt}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		entries = append(entries, PromptEntry{Action: rec.Action, Title: rec.Campaign, Prompt: rec.Prompt, UsedAt: rec.StartedAt})
+	}
+	return entries
+}
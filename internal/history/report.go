@@ -0,0 +1,102 @@
+package history
+
+import (
+	"sort"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/ai"
+)
+
+// RepoOutcome is where one repo stands after every run of a campaign.
+type RepoOutcome struct {
+	Repo     string
+	Outcome  string // of the run that succeeded, or else of the latest run
+	PRURL    string
+	Attempts int // runs the repo was part of
+}
+
+// CampaignReport rolls up every run tagged with a campaign ID: the initial
+// run, retries and follow-ups.
+type CampaignReport struct {
+	ID       string
+	Runs     int
+	Actions  map[string]int // runs per action, e.g. "local" or "assessment"
+	First    time.Time      // when the first run started
+	Last     time.Time      // when the last run finished
+	Duration time.Duration  // time spent running, summed over the runs
+	Usage    ai.Usage
+	Repos    []RepoOutcome // sorted by repo
+}
+
+// Outcomes counts the repos by outcome.
+func (r CampaignReport) Outcomes() map[string]int {
+	counts := make(map[string]int)
+	for _, repo := range r.Repos {
+		counts[repo.Outcome]++
+	}
+	return counts
+}
+
+// PRs counts the repos with a PR.
+func (r CampaignReport) PRs() int {
+	count := 0
+	for _, repo := range r.Repos {
+		if repo.PRURL != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// BuildCampaignReport rolls up the runs tagged with id. A repo that succeeded
+// in any run keeps that outcome, so a retry that skips it because its branch
+// already exists does not hide the PR. It returns false if no run has the ID.
+func BuildCampaignReport(records []Record, id string) (CampaignReport, bool) {
+	report := CampaignReport{ID: id, Actions: make(map[string]int)}
+	repos := make(map[string]*RepoOutcome)
+	for _, rec := range records {
+		if rec.CampaignID != id {
+			continue
+		}
+		report.Runs++
+		report.Actions[rec.Action]++
+		if report.First.IsZero() || rec.StartedAt.Before(report.First) {
+			report.First = rec.StartedAt
+		}
+		finished := rec.FinishedAt
+		if finished.IsZero() {
+			finished = rec.StartedAt // recorded before runs kept their end
+		}
+		if finished.After(report.Last) {
+			report.Last = finished
+		}
+		report.Duration += finished.Sub(rec.StartedAt)
+		if rec.Usage != nil {
+			report.Usage = report.Usage.Add(*rec.Usage)
+		}
+
+		for _, r := range rec.Repos {
+			repo, ok := repos[r.Repo]
+			if !ok {
+				repo = &RepoOutcome{Repo: r.Repo}
+				repos[r.Repo] = repo
+			}
+			repo.Attempts++
+			if repo.Outcome != "succeeded" {
+				repo.Outcome = r.Outcome
+			}
+			if r.PRURL != "" {
+				repo.PRURL = r.PRURL
+			}
+		}
+	}
+	if report.Runs == 0 {
+		return CampaignReport{}, false
+	}
+
+	for _, repo := range repos {
+		report.Repos = append(report.Repos, *repo)
+	}
+	sort.Slice(report.Repos, func(i, j int) bool { return report.Repos[i].Repo < report.Repos[j].Repo })
+	return report, true
+}
@@ -0,0 +1,71 @@
+package history
+
+import "time"
+
+// PRState holds when a campaign PR was opened and, if it was, merged.
+type PRState struct {
+	URL       string
+	CreatedAt time.Time
+	MergedAt  time.Time // zero if not merged
+}
+
+// DayPoint is the cumulative number of campaign PRs opened and merged by the end of Day.
+type DayPoint struct {
+	Day    time.Time
+	Opened int
+	Merged int
+}
+
+// Campaign is the progress of every PR opened under one PR title.
+type Campaign struct {
+	Title  string
+	Points []DayPoint
+	Open   []string // URLs of PRs not merged yet
+
+	// Skipped says why each PR left out was, when its times couldn't be
+	// fetched.
+	Skipped []string
+}
+
+// BuildCampaign computes daily cumulative opened/merged counts from the first
+// PR's creation day up to now.
+func BuildCampaign(title string, prs []PRState, now time.Time) Campaign {
+	campaign := Campaign{Title: title}
+	if len(prs) == 0 {
+		return campaign
+	}
+
+	first := prs[0].CreatedAt
+	for _, pr := range prs {
+		if pr.CreatedAt.Before(first) {
+			first = pr.CreatedAt
+		}
+		if pr.MergedAt.IsZero() {
+			campaign.Open = append(campaign.Open, pr.URL)
+		}
+	}
+
+	loc := now.Location()
+	day := truncateDay(first.In(loc))
+	last := truncateDay(now)
+	for !day.After(last) {
+		end := day.AddDate(0, 0, 1)
+		point := DayPoint{Day: day}
+		for _, pr := range prs {
+			if pr.CreatedAt.Before(end) {
+				point.Opened++
+			}
+			if !pr.MergedAt.IsZero() && pr.MergedAt.Before(end) {
+				point.Merged++
+			}
+		}
+		campaign.Points = append(campaign.Points, point)
+		day = end
+	}
+
+	return campaign
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,74 @@
+// Package hooks runs the configured shell commands in a repo before and after
+// the AI step, e.g. to generate code the AI builds on or to format and test
+// what it changed.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Run runs command with sh in repoPath, sending each line it prints to
+// logLine as it runs. label names the command in the log and the error (e.g.
+// "pre hook"). The error carries the last lines of the output, so a failed
+// repo shows why without opening its log.
+func Run(ctx context.Context, repoPath, label, command string, logLine func(string)) error {
+	if logLine == nil {
+		logLine = func(string) {}
+	}
+	logLine(fmt.Sprintf("$ %s (%s)", command, label))
+
+	out := &lineWriter{emit: logLine}
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = repoPath
+	cmd.Stdout = out
+	cmd.Stderr = out
+	err := cmd.Run()
+	out.flush()
+	if err != nil {
+		if tail := lastLines(out.all.String(), 5); tail != "" {
+			return fmt.Errorf("%s failed: %v\n%s", label, err, tail)
+		}
+		return fmt.Errorf("%s failed: %v", label, err)
+	}
+	return nil
+}
+
+// lineWriter passes complete lines to emit and keeps everything written.
+type lineWriter struct {
+	emit    func(string)
+	pending bytes.Buffer
+	all     bytes.Buffer
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.all.Write(p)
+	w.pending.Write(p)
+	for {
+		i := bytes.IndexByte(w.pending.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := w.pending.Next(i + 1)
+		w.emit(strings.TrimRight(string(line), "\r\n"))
+	}
+}
+
+// flush emits a last line that did not end with a newline.
+func (w *lineWriter) flush() {
+	if w.pending.Len() > 0 {
+		w.emit(w.pending.String())
+		w.pending.Reset()
+	}
+}
+
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,37 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	var logged []string
+	logLine := func(line string) { logged = append(logged, line) }
+
+	if err := Run(context.Background(), dir, "pre hook", "echo generated > gen.txt && echo done && printf partial >&2", logLine); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "gen.txt")); err != nil || string(data) != "generated\n" {
+		t.Errorf("expected the hook to run in the repo, got %q (%v)", data, err)
+	}
+	want := []string{"$ echo generated > gen.txt && echo done && printf partial >&2 (pre hook)", "done", "partial"}
+	if strings.Join(logged, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected log:\n%s", strings.Join(logged, "\n"))
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	err := Run(context.Background(), t.TempDir(), "post hook", "for i in 1 2 3 4 5 6 7; do echo line $i; done; exit 3", nil)
+	if err == nil {
+		t.Fatal("expected the failing hook to return an error")
+	}
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "post hook failed: exit status 3\n") || !strings.HasSuffix(msg, "line 3\nline 4\nline 5\nline 6\nline 7") {
+		t.Errorf("expected the exit status and the last lines of output, got %q", msg)
+	}
+}
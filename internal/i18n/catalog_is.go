@@ -0,0 +1,146 @@
+package i18n
+
+// icelandic is the Icelandic catalog.
+var icelandic = map[string]string{
+	// Dashboard wizard
+	"Perform Changes Locally": "Gera breytingar staðbundið",
+	"Run Assessment":          "Keyra úttekt",
+	"Add License Headers":     "Bæta við leyfishausum",
+	"Triage Security Alerts":  "Flokka öryggisviðvaranir",
+	"Detect Flaky Tests":      "Finna óstöðug próf",
+	"Run Actions":             "Keyra aðgerðir",
+	"Create GitHub Issues":    "Stofna GitHub-mál",
+
+	"Always create new branches":            "Stofna alltaf nýjar greinar",
+	"Specify branch name (reuse if exists)": "Tilgreina heiti greinar (endurnota ef hún er til)",
+	"Specify branch name (skip if exists)":  "Tilgreina heiti greinar (sleppa ef hún er til)",
+
+	"Block PR creation":           "Stöðva stofnun PR",
+	"Open a draft PR with a note": "Opna PR-drög með athugasemd",
+
+	"  Action":                  "  Aðgerð",
+	"  Actions":                 "  Aðgerðir",
+	"  AI Tool":                 "  Gervigreindartól",
+	"  Assessment Question":     "  Úttektarspurning",
+	"  Branch Name":             "  Heiti greinar",
+	"  Branch Strategy":         "  Greinastefna",
+	"  Confirm Run on %d Repos": "  Staðfesta keyrslu á %d gagnasöfnum",
+	"  If Verification Fails":   "  Ef sannprófun mistekst",
+	"  Issue Description":       "  Lýsing máls",
+	"  Issue Title":             "  Titill máls",
+	"  PR Title":                "  Titill PR",
+	"  Prompt":                  "  Fyrirmæli",
+	"  Temporary File Changes":  "  Tímabundnar skráabreytingar",
+
+	"  Issue Templates": "  Sniðmát mála",
+	"    Fills in the issue title, labels, assignees and description so you can edit them": "    Fyllir út titil, merki, ábyrgð og lýsingu málsins svo þú getir breytt þeim",
+	"    ⚠ Repo issue templates unavailable: %s":                                           "    ⚠ Sniðmát mála í gagnasafninu ekki tiltæk: %s",
+
+	"  ○ AI Tool":                "  ○ Gervigreindartól",
+	"  ○ Assessment Question":    "  ○ Úttektarspurning",
+	"  ○ Branch Name":            "  ○ Heiti greinar",
+	"  ○ Branch Strategy":        "  ○ Greinastefna",
+	"  ○ If Verification Fails":  "  ○ Ef sannprófun mistekst",
+	"  ○ Issue Description":      "  ○ Lýsing máls",
+	"  ○ Issue Title":            "  ○ Titill máls",
+	"  ○ PR Title":               "  ○ Titill PR",
+	"  ○ Prompt":                 "  ○ Fyrirmæli",
+	"  ○ Temporary File Changes": "  ○ Tímabundnar skráabreytingar",
+
+	"  ✓ Action: %s":                                       "  ✓ Aðgerð: %s",
+	"  ✓ Actions: %s (%d steps)":                           "  ✓ Aðgerðir: %s (%d skref)",
+	"  ✓ AI Tool: %s (%s)":                                 "  ✓ Gervigreindartól: %s (%s)",
+	"  ✓ Assignees: %s":                                    "  ✓ Ábyrgð: %s",
+	"  ✓ Auto-merge: squash once branch protection passes": "  ✓ Sjálfvirk sameining: squash þegar greinavörn stenst",
+	"  ✓ Branch: %s":                                       "  ✓ Grein: %s",
+	"  ✓ Branch Name: %s":                                  "  ✓ Heiti greinar: %s",
+	"  ✓ Description: %s":                                  "  ✓ Lýsing: %s",
+	"  ✓ If Verification Fails: %s":                        "  ✓ Ef sannprófun mistekst: %s",
+	"  ✓ Issue Title: %s":                                  "  ✓ Titill máls: %s",
+	"  ✓ Labels: %s":                                       "  ✓ Merki: %s",
+	"  ✓ Milestone: %s":                                    "  ✓ Áfangi: %s",
+	"  ✓ Open PRs: title and description updated":          "  ✓ Opnir PR: titill og lýsing uppfærð",
+	"  ✓ PR Title: %s":                                     "  ✓ Titill PR: %s",
+	"  ✓ Prompt: %s":                                       "  ✓ Fyrirmæli: %s",
+	"  ✓ Prompt: none, actions only":                       "  ✓ Fyrirmæli: engin, aðeins aðgerðir",
+	"  ✓ Question: %s":                                     "  ✓ Spurning: %s",
+	"  ✓ Structured Answer: score, status, evidence":       "  ✓ Skipulagt svar: einkunn, staða, rök",
+	"  ✓ Temporary File Changes: %s":                       "  ✓ Tímabundnar skráabreytingar: %s",
+
+	"    %s Ask for a structured answer (score, status, evidence)":                 "    %s Biðja um skipulagt svar (einkunn, staða, rök)",
+	"    %s Enable auto-merge (squash) once branch protection passes":              "    %s Virkja sjálfvirka sameiningu (squash) þegar greinavörn stenst",
+	"    %s Update the title and description of PRs already open from this branch": "    %s Uppfæra titil og lýsingu PR sem þegar eru opnir úr þessari grein",
+	"    > %s Ignore agent instructions in target repos":                           "    > %s Hunsa leiðbeiningar til fulltrúa í markgagnasöfnum",
+	"    Each repo's test suite is rerun to find tests that pass and fail":         "    Prófanir hvers gagnasafns eru endurkeyrðar til að finna próf sem ýmist standast eða falla",
+	"    Made before the AI step and undone before committing":                     "    Gerðar á undan gervigreindarskrefinu og afturkallaðar fyrir commit",
+	"    Optional: submit it empty to only run the actions":                        "    Valfrjálst: sendu tómt til að keyra aðeins aðgerðirnar",
+	"    PR titles must %s":    "    Titlar PR verða að %s",
+	"    That does not match.": "    Þetta passar ekki.",
+	"    The verify command runs on each repo's changes before its PR is opened":      "    Sannprófunarskipunin keyrir á breytingum hvers gagnasafns áður en PR er opnaður",
+	"    This run is larger than %d repos. Type %s (%s) to start it.":                 "    Þessi keyrsla nær yfir fleiri en %d gagnasöfn. Sláðu inn %s (%s) til að hefja hana.",
+	"    Users or org/team slugs; copilot hands the issues to Copilot's coding agent": "    Notendur eða teymi á forminu org/teymi; copilot felur kóðunarfulltrúa Copilot málin",
+	"    You may include a ticket reference (e.g., PROJ-123 - Description)":           "    Þú mátt tilgreina tilvísun í verkbeiðni (t.d. PROJ-123 - Lýsing)",
+
+	"  a: add change • x: drop last change • enter: confirm • q/ctrl+c: quit":                 "  a: bæta við breytingu • x: fjarlægja síðustu • enter: staðfesta • q/ctrl+c: hætta",
+	"  ctrl+s: submit • enter: new line • ctrl+e: open editor":                                "  ctrl+s: senda • enter: ný lína • ctrl+e: opna ritil",
+	"  enter: add change • esc: cancel • ctrl+c: quit":                                        "  enter: bæta við breytingu • esc: hætta við • ctrl+c: hætta",
+	"  enter: next • esc: back":                                                               "  enter: áfram • esc: til baka",
+	"  enter: start run • esc/ctrl+c: quit":                                                   "  enter: hefja keyrslu • esc/ctrl+c: hætta",
+	"  enter: submit • ctrl+l: labels/milestone":                                              "  enter: senda • ctrl+l: merki/áfangi",
+	"  enter: submit • esc/ctrl+c: quit":                                                      "  enter: senda • esc/ctrl+c: hætta",
+	"  enter: submit • tab: auto-merge • ctrl+l: labels/milestone":                            "  enter: senda • tab: sjálfvirk sameining • ctrl+l: merki/áfangi",
+	"  enter: submit • tab: update open PRs • esc/ctrl+c: quit":                               "  enter: senda • tab: uppfæra opna PR • esc/ctrl+c: hætta",
+	"  space: toggle • a: add change • x: drop last change • enter: confirm • q/ctrl+c: quit": "  bil: víxla • a: bæta við breytingu • x: fjarlægja síðustu • enter: staðfesta • q/ctrl+c: hætta",
+	"  ↑/↓: navigate • enter: reuse • esc: back":                                              "  ↑/↓: fletta • enter: endurnota • esc: til baka",
+	"  ↑/↓: navigate • enter: select • q/ctrl+c: quit":                                        "  ↑/↓: fletta • enter: velja • q/ctrl+c: hætta",
+	"  ↑/↓: navigate • enter: use template • esc: back":                                       "  ↑/↓: fletta • enter: nota sniðmát • esc: til baka",
+	" • ctrl+r: reuse previous prompt":                                                        " • ctrl+r: endurnota fyrri fyrirmæli",
+	" • ctrl+t: templates":                                                                    " • ctrl+t: sniðmát",
+	" • esc/ctrl+c: quit":                                                                     " • esc/ctrl+c: hætta",
+	" • tab: structured answer":                                                               " • tab: skipulagt svar",
+
+	"%d project(s): %s":                                "%d verkefni: %s",
+	"%d projects: %s, +%d more":                        "%d verkefni: %s, +%d til viðbótar",
+	"%s (%d steps)":                                    "%s (%d skref)",
+	"%s calls an API and cannot change code":           "%s kallar á API og getur ekki breytt kóða",
+	"Assignees:":                                       "Ábyrgð:",
+	"Describe the changes to apply to each repository": "Lýstu breytingunum sem á að gera í hverju gagnasafni",
+	"Describe the issue to open in each repository":    "Lýstu málinu sem á að stofna í hverju gagnasafni",
+	"Enter your assessment question (e.g., Are these projects using circuit breakers?)": "Sláðu inn úttektarspurninguna (t.d. Nota þessi verkefni circuit breakers?)",
+	"Labels:":              "Merki:",
+	"Milestone:":           "Áfangi:",
+	"No projects selected": "Engin verkefni valin",
+	"None":                 "Engar",
+	"Optionally describe changes for the AI tool to make after the actions": "Lýstu mögulega breytingum sem gervigreindartólið á að gera á eftir aðgerðunum",
+	"agent instructions ignored":           "leiðbeiningar til fulltrúa hunsaðar",
+	"e.g., PROJ-123 - Enable Dependabot":   "t.d. PROJ-123 - Virkja Dependabot",
+	"e.g., PROJ-123 - Update dependencies": "t.d. PROJ-123 - Uppfæra háð söfn",
+	"none":                                 "enginn",
+	"remove <path> • add <path> <local file> • replace <path> <local file>": "remove <slóð> • add <slóð> <staðbundin skrá> • replace <slóð> <staðbundin skrá>",
+	"the PR title":        "titil PR",
+	"the issue title":     "titil málsins",
+	"the number of repos": "fjölda gagnasafna",
+
+	// Reports
+	"Copycat assessment":    "Copycat-úttekt",
+	"Copycat report — %s":   "Copycat-skýrsla — %s",
+	"Assessment":            "Úttekt",
+	"GitHub issues":         "GitHub-mál",
+	"Code changes":          "Kóðabreytingar",
+	"%d repositories":       "%d gagnasöfn",
+	"generated %s":          "búin til %s",
+	"Prompt":                "Fyrirmæli",
+	"Status":                "Staða",
+	"Campaign progress":     "Framvinda herferðar",
+	"PRs opened vs merged":  "Opnaðir PR á móti sameinuðum",
+	"opened %d":             "opnaðir %d",
+	"merged %d":             "sameinaðir %d",
+	"%d PRs not merged yet": "%d PR enn ósameinaðir",
+	"Time spent":            "Tími",
+	"Summary":               "Samantekt",
+	"Repositories":          "Gagnasöfn",
+	"succeeded":             "tókst",
+	"skipped":               "sleppt",
+	"failed":                "mistókst",
+	"cancelled":             "hætt við",
+}
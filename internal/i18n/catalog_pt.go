@@ -0,0 +1,146 @@
+package i18n
+
+// portuguese is the European Portuguese catalog.
+var portuguese = map[string]string{
+	// Dashboard wizard
+	"Perform Changes Locally": "Aplicar alterações localmente",
+	"Run Assessment":          "Executar avaliação",
+	"Add License Headers":     "Adicionar cabeçalhos de licença",
+	"Triage Security Alerts":  "Triar alertas de segurança",
+	"Detect Flaky Tests":      "Detetar testes instáveis",
+	"Run Actions":             "Executar ações",
+	"Create GitHub Issues":    "Criar issues no GitHub",
+
+	"Always create new branches":            "Criar sempre novos ramos",
+	"Specify branch name (reuse if exists)": "Indicar o nome do ramo (reutilizar se existir)",
+	"Specify branch name (skip if exists)":  "Indicar o nome do ramo (ignorar se existir)",
+
+	"Block PR creation":           "Bloquear a criação do PR",
+	"Open a draft PR with a note": "Abrir um PR em rascunho com uma nota",
+
+	"  Action":                  "  Ação",
+	"  Actions":                 "  Ações",
+	"  AI Tool":                 "  Ferramenta de IA",
+	"  Assessment Question":     "  Pergunta de avaliação",
+	"  Branch Name":             "  Nome do ramo",
+	"  Branch Strategy":         "  Estratégia de ramos",
+	"  Confirm Run on %d Repos": "  Confirmar execução em %d repositórios",
+	"  If Verification Fails":   "  Se a verificação falhar",
+	"  Issue Description":       "  Descrição da issue",
+	"  Issue Title":             "  Título da issue",
+	"  PR Title":                "  Título do PR",
+	"  Prompt":                  "  Instruções",
+	"  Temporary File Changes":  "  Alterações temporárias a ficheiros",
+
+	"  Issue Templates": "  Modelos de issue",
+	"    Fills in the issue title, labels, assignees and description so you can edit them": "    Preenche o título, as etiquetas, os responsáveis e a descrição da issue para os poder editar",
+	"    ⚠ Repo issue templates unavailable: %s":                                           "    ⚠ Modelos de issue do repositório indisponíveis: %s",
+
+	"  ○ AI Tool":                "  ○ Ferramenta de IA",
+	"  ○ Assessment Question":    "  ○ Pergunta de avaliação",
+	"  ○ Branch Name":            "  ○ Nome do ramo",
+	"  ○ Branch Strategy":        "  ○ Estratégia de ramos",
+	"  ○ If Verification Fails":  "  ○ Se a verificação falhar",
+	"  ○ Issue Description":      "  ○ Descrição da issue",
+	"  ○ Issue Title":            "  ○ Título da issue",
+	"  ○ PR Title":               "  ○ Título do PR",
+	"  ○ Prompt":                 "  ○ Instruções",
+	"  ○ Temporary File Changes": "  ○ Alterações temporárias a ficheiros",
+
+	"  ✓ Action: %s":                                       "  ✓ Ação: %s",
+	"  ✓ Actions: %s (%d steps)":                           "  ✓ Ações: %s (%d passos)",
+	"  ✓ AI Tool: %s (%s)":                                 "  ✓ Ferramenta de IA: %s (%s)",
+	"  ✓ Assignees: %s":                                    "  ✓ Responsáveis: %s",
+	"  ✓ Auto-merge: squash once branch protection passes": "  ✓ Fusão automática: squash quando a proteção do ramo passar",
+	"  ✓ Branch: %s":                                       "  ✓ Ramo: %s",
+	"  ✓ Branch Name: %s":                                  "  ✓ Nome do ramo: %s",
+	"  ✓ Description: %s":                                  "  ✓ Descrição: %s",
+	"  ✓ If Verification Fails: %s":                        "  ✓ Se a verificação falhar: %s",
+	"  ✓ Issue Title: %s":                                  "  ✓ Título da issue: %s",
+	"  ✓ Labels: %s":                                       "  ✓ Etiquetas: %s",
+	"  ✓ Milestone: %s":                                    "  ✓ Marco: %s",
+	"  ✓ Open PRs: title and description updated":          "  ✓ PRs abertos: título e descrição atualizados",
+	"  ✓ PR Title: %s":                                     "  ✓ Título do PR: %s",
+	"  ✓ Prompt: %s":                                       "  ✓ Instruções: %s",
+	"  ✓ Prompt: none, actions only":                       "  ✓ Instruções: nenhumas, só ações",
+	"  ✓ Question: %s":                                     "  ✓ Pergunta: %s",
+	"  ✓ Structured Answer: score, status, evidence":       "  ✓ Resposta estruturada: pontuação, estado, evidência",
+	"  ✓ Temporary File Changes: %s":                       "  ✓ Alterações temporárias a ficheiros: %s",
+
+	"    %s Ask for a structured answer (score, status, evidence)":                 "    %s Pedir uma resposta estruturada (pontuação, estado, evidência)",
+	"    %s Enable auto-merge (squash) once branch protection passes":              "    %s Ativar a fusão automática (squash) quando a proteção do ramo passar",
+	"    %s Update the title and description of PRs already open from this branch": "    %s Atualizar o título e a descrição dos PRs já abertos a partir deste ramo",
+	"    > %s Ignore agent instructions in target repos":                           "    > %s Ignorar as instruções para agentes nos repositórios de destino",
+	"    Each repo's test suite is rerun to find tests that pass and fail":         "    Os testes de cada repositório são repetidos para encontrar os que ora passam ora falham",
+	"    Made before the AI step and undone before committing":                     "    Feitas antes do passo de IA e desfeitas antes do commit",
+	"    Optional: submit it empty to only run the actions":                        "    Opcional: submeta em branco para só executar as ações",
+	"    PR titles must %s":    "    Os títulos dos PRs têm de %s",
+	"    That does not match.": "    Não corresponde.",
+	"    The verify command runs on each repo's changes before its PR is opened":      "    O comando de verificação corre sobre as alterações de cada repositório antes de abrir o PR",
+	"    This run is larger than %d repos. Type %s (%s) to start it.":                 "    Esta execução tem mais de %d repositórios. Escreva %s (%s) para a iniciar.",
+	"    Users or org/team slugs; copilot hands the issues to Copilot's coding agent": "    Utilizadores ou equipas org/equipa; copilot entrega as issues ao agente de código do Copilot",
+	"    You may include a ticket reference (e.g., PROJ-123 - Description)":           "    Pode incluir uma referência de ticket (p. ex., PROJ-123 - Descrição)",
+
+	"  a: add change • x: drop last change • enter: confirm • q/ctrl+c: quit":                 "  a: adicionar alteração • x: remover a última • enter: confirmar • q/ctrl+c: sair",
+	"  ctrl+s: submit • enter: new line • ctrl+e: open editor":                                "  ctrl+s: submeter • enter: nova linha • ctrl+e: abrir editor",
+	"  enter: add change • esc: cancel • ctrl+c: quit":                                        "  enter: adicionar alteração • esc: cancelar • ctrl+c: sair",
+	"  enter: next • esc: back":                                                               "  enter: seguinte • esc: voltar",
+	"  enter: start run • esc/ctrl+c: quit":                                                   "  enter: iniciar execução • esc/ctrl+c: sair",
+	"  enter: submit • ctrl+l: labels/milestone":                                              "  enter: submeter • ctrl+l: etiquetas/marco",
+	"  enter: submit • esc/ctrl+c: quit":                                                      "  enter: submeter • esc/ctrl+c: sair",
+	"  enter: submit • tab: auto-merge • ctrl+l: labels/milestone":                            "  enter: submeter • tab: fusão automática • ctrl+l: etiquetas/marco",
+	"  enter: submit • tab: update open PRs • esc/ctrl+c: quit":                               "  enter: submeter • tab: atualizar PRs abertos • esc/ctrl+c: sair",
+	"  space: toggle • a: add change • x: drop last change • enter: confirm • q/ctrl+c: quit": "  espaço: alternar • a: adicionar alteração • x: remover a última • enter: confirmar • q/ctrl+c: sair",
+	"  ↑/↓: navigate • enter: reuse • esc: back":                                              "  ↑/↓: navegar • enter: reutilizar • esc: voltar",
+	"  ↑/↓: navigate • enter: select • q/ctrl+c: quit":                                        "  ↑/↓: navegar • enter: selecionar • q/ctrl+c: sair",
+	"  ↑/↓: navigate • enter: use template • esc: back":                                       "  ↑/↓: navegar • enter: usar modelo • esc: voltar",
+	" • ctrl+r: reuse previous prompt":                                                        " • ctrl+r: reutilizar instruções anteriores",
+	" • ctrl+t: templates":                                                                    " • ctrl+t: modelos",
+	" • esc/ctrl+c: quit":                                                                     " • esc/ctrl+c: sair",
+	" • tab: structured answer":                                                               " • tab: resposta estruturada",
+
+	"%d project(s): %s":                                "%d projeto(s): %s",
+	"%d projects: %s, +%d more":                        "%d projetos: %s, +%d mais",
+	"%s (%d steps)":                                    "%s (%d passos)",
+	"%s calls an API and cannot change code":           "%s chama uma API e não pode alterar código",
+	"Assignees:":                                       "Responsáveis:",
+	"Describe the changes to apply to each repository": "Descreva as alterações a aplicar em cada repositório",
+	"Describe the issue to open in each repository":    "Descreva a issue a abrir em cada repositório",
+	"Enter your assessment question (e.g., Are these projects using circuit breakers?)": "Escreva a sua pergunta de avaliação (p. ex., Estes projetos usam circuit breakers?)",
+	"Labels:":              "Etiquetas:",
+	"Milestone:":           "Marco:",
+	"No projects selected": "Nenhum projeto selecionado",
+	"None":                 "Nenhuma",
+	"Optionally describe changes for the AI tool to make after the actions": "Opcionalmente, descreva alterações para a ferramenta de IA fazer depois das ações",
+	"agent instructions ignored":           "instruções para agentes ignoradas",
+	"e.g., PROJ-123 - Enable Dependabot":   "p. ex., PROJ-123 - Ativar o Dependabot",
+	"e.g., PROJ-123 - Update dependencies": "p. ex., PROJ-123 - Atualizar dependências",
+	"none":                                 "nenhum",
+	"remove <path> • add <path> <local file> • replace <path> <local file>": "remove <caminho> • add <caminho> <ficheiro local> • replace <caminho> <ficheiro local>",
+	"the PR title":        "o título do PR",
+	"the issue title":     "o título da issue",
+	"the number of repos": "o número de repositórios",
+
+	// Reports
+	"Copycat assessment":    "Avaliação Copycat",
+	"Copycat report — %s":   "Relatório Copycat — %s",
+	"Assessment":            "Avaliação",
+	"GitHub issues":         "Issues no GitHub",
+	"Code changes":          "Alterações de código",
+	"%d repositories":       "%d repositórios",
+	"generated %s":          "gerado a %s",
+	"Prompt":                "Instruções",
+	"Status":                "Estado",
+	"Campaign progress":     "Progresso da campanha",
+	"PRs opened vs merged":  "PRs abertos vs fundidos",
+	"opened %d":             "abertos %d",
+	"merged %d":             "fundidos %d",
+	"%d PRs not merged yet": "%d PRs ainda por fundir",
+	"Time spent":            "Tempo gasto",
+	"Summary":               "Resumo",
+	"Repositories":          "Repositórios",
+	"succeeded":             "concluído",
+	"skipped":               "ignorado",
+	"failed":                "falhado",
+	"cancelled":             "cancelado",
+}
@@ -0,0 +1,71 @@
+// Package i18n translates the strings copycat shows operators and writes into
+// reports. Strings are written in English in the code and looked up, as they
+// are, in the catalog of the language picked with SetLanguage; strings a
+// catalog is missing stay in English.
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Supported languages, by BCP 47 tag.
+const (
+	English    = "en"
+	Portuguese = "pt-PT"
+	Icelandic  = "is"
+)
+
+// Languages lists the supported languages, English first.
+var Languages = []string{English, Portuguese, Icelandic}
+
+// catalogs maps each language but English to its translations, keyed by
+// the English string.
+var catalogs = map[string]map[string]string{
+	Portuguese: portuguese,
+	Icelandic:  icelandic,
+}
+
+var (
+	mu       sync.RWMutex
+	language = English
+)
+
+// Supported reports whether lang is one of Languages. An empty lang is
+// English.
+func Supported(lang string) bool {
+	return lang == "" || slices.Contains(Languages, lang)
+}
+
+// SetLanguage sets the language strings are translated into. An empty or
+// unsupported lang is English.
+func SetLanguage(lang string) {
+	if lang == "" || !Supported(lang) {
+		lang = English
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	language = lang
+}
+
+// Language returns the language strings are translated into.
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return language
+}
+
+// T translates s into the current language and, when args are given,
+// formats them into it as fmt.Sprintf does.
+func T(s string, args ...any) string {
+	mu.RLock()
+	if translated, ok := catalogs[language][s]; ok {
+		s = translated
+	}
+	mu.RUnlock()
+	if len(args) == 0 {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
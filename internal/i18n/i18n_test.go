@@ -0,0 +1,86 @@
+package i18n
+
+import (
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var verbs = regexp.MustCompile(`%[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+
+func TestCatalogs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key, translated := range catalog {
+			if !slices.Equal(verbs.FindAllString(key, -1), verbs.FindAllString(translated, -1)) {
+				t.Errorf("%s: %q does not take the same arguments as %q", lang, translated, key)
+			}
+			if len(key)-len(strings.TrimLeft(key, " ")) != len(translated)-len(strings.TrimLeft(translated, " ")) ||
+				len(key)-len(strings.TrimRight(key, " ")) != len(translated)-len(strings.TrimRight(translated, " ")) {
+				t.Errorf("%s: %q is not padded as %q", lang, translated, key)
+			}
+		}
+		for other, otherCatalog := range catalogs {
+			for key := range otherCatalog {
+				if _, ok := catalog[key]; !ok {
+					t.Errorf("%s is missing %q, which %s translates", lang, key, other)
+				}
+			}
+		}
+	}
+}
+
+func TestCatalogsCoverSources(t *testing.T) {
+	literal := regexp.MustCompile(`(?:i18n\.T\(|\{\{t )("(?:[^"\\]|\\.)*")`)
+	for _, file := range []string{"../input/wizard.go", "../input/issuetemplates.go", "../report/report.go", "../../main.go"} {
+		source, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range literal.FindAllStringSubmatch(string(source), -1) {
+			key, err := strconv.Unquote(match[1])
+			if err != nil {
+				t.Fatalf("%s: %v", match[1], err)
+			}
+			for lang, catalog := range catalogs {
+				if _, ok := catalog[key]; !ok {
+					t.Errorf("%s has no translation of %q from %s", lang, key, file)
+				}
+			}
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	t.Cleanup(func() { SetLanguage(English) })
+
+	if got := T("%d repositories", 3); got != "3 repositories" {
+		t.Errorf("expected English by default, got %q", got)
+	}
+
+	SetLanguage(Portuguese)
+	if got := T("%d repositories", 3); got != "3 repositórios" {
+		t.Errorf("expected Portuguese, got %q", got)
+	}
+	if got := T("not in any catalog"); got != "not in any catalog" {
+		t.Errorf("expected a missing string to stay in English, got %q", got)
+	}
+
+	SetLanguage("fr")
+	if Language() != English {
+		t.Errorf("expected an unsupported language to fall back to English, got %s", Language())
+	}
+}
+
+func TestSupported(t *testing.T) {
+	for _, lang := range []string{"", English, Portuguese, Icelandic} {
+		if !Supported(lang) {
+			t.Errorf("expected %q to be supported", lang)
+		}
+	}
+	if Supported("pt-BR") {
+		t.Error("expected pt-BR to be unsupported")
+	}
+}
@@ -0,0 +1,97 @@
+package input
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/saltpay/copycat/v2/internal/ai"
+)
+
+// assessSort is the order of the assessment Projects tab.
+type assessSort int
+
+const (
+	assessSortRun assessSort = iota
+	assessSortScore
+	assessSortStatus
+)
+
+var assessSortLabels = []string{"run order", "score", "status"}
+
+// sortAssessedRepos orders repos by the chosen column. Repos without a
+// structured answer go last; ties keep their run order.
+func (m dashboardModel) sortAssessedRepos(repos []string) []string {
+	if m.assessSort == assessSortRun || len(m.assessments) == 0 {
+		return repos
+	}
+	sorted := append([]string(nil), repos...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, aok := m.assessments[sorted[i]]
+		b, bok := m.assessments[sorted[j]]
+		if aok != bok {
+			return aok
+		}
+		if m.assessSort == assessSortStatus && ai.StatusRank[a.Status] != ai.StatusRank[b.Status] {
+			return ai.StatusRank[a.Status] < ai.StatusRank[b.Status]
+		}
+		return a.Score < b.Score
+	})
+	return sorted
+}
+
+// statusStyle colours a structured assessment status.
+func statusStyle(status string) lipgloss.Style {
+	switch status {
+	case ai.StatusCompliant:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("40"))
+	case ai.StatusPartial:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	case ai.StatusNonCompliant:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+}
+
+// renderAssessmentColumns renders the status and score columns for a repo.
+func (m dashboardModel) renderAssessmentColumns(repo string) string {
+	a, ok := m.assessments[repo]
+	if !ok {
+		return statusStyle("").Render(fmt.Sprintf("%-13s %3s", "—", "—"))
+	}
+	return statusStyle(a.Status).Render(fmt.Sprintf("%-13s %3d", a.Status, a.Score))
+}
+
+// renderAssessmentStats renders aggregate counts such as "34/50 compliant".
+func (m dashboardModel) renderAssessmentStats() string {
+	if len(m.assessments) == 0 {
+		return ""
+	}
+
+	assessed := 0
+	for _, result := range m.doneResults() {
+		if result.Success {
+			assessed++
+		}
+	}
+	counts := make(map[string]int)
+	total := 0
+	for _, a := range m.assessments {
+		counts[a.Status]++
+		total += a.Score
+	}
+
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	parts := []string{statusStyle(ai.StatusCompliant).Render(fmt.Sprintf("%d/%d compliant", counts[ai.StatusCompliant], assessed))}
+	for _, status := range []string{ai.StatusPartial, ai.StatusNonCompliant, ai.StatusUnknown} {
+		if counts[status] > 0 {
+			parts = append(parts, statusStyle(status).Render(fmt.Sprintf("%d %s", counts[status], status)))
+		}
+	}
+	if missing := assessed - len(m.assessments); missing > 0 {
+		parts = append(parts, dimStyle.Render(fmt.Sprintf("%d unscored", missing)))
+	}
+	parts = append(parts, dimStyle.Render(fmt.Sprintf("avg score %d", total/len(m.assessments))))
+	return "  " + strings.Join(parts, dimStyle.Render(" · "))
+}
@@ -0,0 +1,154 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/saltpay/copycat/v2/internal/history"
+)
+
+// assessTabChanges is the assessment done-screen tab comparing runs.
+const assessTabChanges = 2
+
+// assessmentHistoryMsg carries the recorded runs of the current assessment question.
+type assessmentHistoryMsg struct {
+	Runs []history.Record
+	Err  error
+}
+
+// loadAssessmentHistory fetches earlier runs of the same question in the background.
+func (m dashboardModel) loadAssessmentHistory() tea.Cmd {
+	if m.cfg.AssessmentHistory == nil || m.wizardResult == nil || m.wizardResult.Action != "assessment" {
+		return nil
+	}
+	fetch := m.cfg.AssessmentHistory
+	question := m.wizardResult.Prompt
+	return func() tea.Msg {
+		runs, err := fetch(question)
+		return assessmentHistoryMsg{Runs: runs, Err: err}
+	}
+}
+
+// assessmentChanges compares the latest run with the chosen earlier one,
+// listing repos whose status changed first.
+func (m dashboardModel) assessmentChanges() []history.StatusChange {
+	if len(m.assessmentRuns) < 2 {
+		return nil
+	}
+	all := history.CompareAssessments(m.assessmentRuns[m.compareRun], m.assessmentRuns[len(m.assessmentRuns)-1])
+	var changed, unchanged []history.StatusChange
+	for _, c := range all {
+		if c.Changed() {
+			changed = append(changed, c)
+		} else {
+			unchanged = append(unchanged, c)
+		}
+	}
+	return append(changed, unchanged...)
+}
+
+// changesMaxVisible returns how many comparison rows fit on the Changes tab.
+func (m dashboardModel) changesMaxVisible() int {
+	// Same overhead as the Projects tab plus the comparison header (2)
+	available := m.termHeight - 15 + m.compactSaving()
+	if available < 3 {
+		available = 3
+	}
+	return available
+}
+
+// updateDoneChangesTab handles keys on the Changes tab: ←/→ pick the earlier
+// run to compare with, ↑/↓ scroll.
+func (m dashboardModel) updateDoneChangesTab(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if len(m.assessmentRuns) < 2 {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "left", "h":
+		if m.compareRun > 0 {
+			m.compareRun--
+			m.changesScrollOffset = 0
+		}
+	case "right", "l":
+		if m.compareRun < len(m.assessmentRuns)-2 {
+			m.compareRun++
+			m.changesScrollOffset = 0
+		}
+	case "up", "k":
+		if m.changesScrollOffset > 0 {
+			m.changesScrollOffset--
+		}
+	case "down", "j":
+		if m.changesScrollOffset < len(m.assessmentChanges())-m.changesMaxVisible() {
+			m.changesScrollOffset++
+		}
+	}
+	return m, nil
+}
+
+// renderChangesTabContent renders the per-repo comparison with an earlier run.
+func (m dashboardModel) renderChangesTabContent() string {
+	var b strings.Builder
+
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	repoStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	betterStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("40"))
+	worseStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	otherStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+	if len(m.assessmentRuns) < 2 {
+		b.WriteString(dimStyle.Render("  No earlier run of this question to compare with."))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	previous := m.assessmentRuns[m.compareRun]
+	changes := m.assessmentChanges()
+	changed := 0
+	for _, c := range changes {
+		if c.Changed() {
+			changed++
+		}
+	}
+
+	b.WriteString(fmt.Sprintf("  Compared with run of %s  %s\n",
+		previous.StartedAt.Local().Format("2006-01-02 15:04"),
+		dimStyle.Render(fmt.Sprintf("(%d of %d earlier runs)", m.compareRun+1, len(m.assessmentRuns)-1))))
+	b.WriteString(fmt.Sprintf("  %s  %s\n\n",
+		otherStyle.Render(fmt.Sprintf("%d changed", changed)),
+		dimStyle.Render(fmt.Sprintf("%d unchanged", len(changes)-changed))))
+
+	start := m.changesScrollOffset
+	end := start + m.changesMaxVisible()
+	if end > len(changes) {
+		end = len(changes)
+	}
+	if start > 0 {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  ↑ %d more above", start)))
+		b.WriteString("\n")
+	}
+	for _, c := range changes[start:end] {
+		line := fmt.Sprintf("%s → %s", history.AssessmentLabel(c.Before), history.AssessmentLabel(c.After))
+		if !c.Changed() {
+			b.WriteString(fmt.Sprintf("    %s %s\n", dimStyle.Render(fmt.Sprintf("[%s]", c.Repo)), dimStyle.Render(line)))
+			continue
+		}
+
+		marker, style := "●", otherStyle
+		switch c.Trend() {
+		case 1:
+			marker, style = "▲", betterStyle
+		case -1:
+			marker, style = "▼", worseStyle
+		}
+		b.WriteString(fmt.Sprintf("  %s %s %s\n", style.Render(marker), repoStyle.Render(fmt.Sprintf("[%s]", c.Repo)), style.Render(line)))
+	}
+	if end < len(changes) {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  ↓ %d more below", len(changes)-end)))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
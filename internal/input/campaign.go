@@ -0,0 +1,91 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/saltpay/copycat/v2/internal/history"
+)
+
+// maxSparklineDays limits the sparkline to the most recent days of a campaign.
+const maxSparklineDays = 30
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// campaignProgressMsg carries the campaign progress loaded after processing.
+type campaignProgressMsg struct {
+	Campaign history.Campaign
+	Err      error
+}
+
+// loadCampaignProgress fetches campaign progress in the background for code-change runs.
+func (m dashboardModel) loadCampaignProgress() tea.Cmd {
+	if m.cfg.CampaignProgress == nil || m.wizardResult == nil || m.wizardResult.Action != "local" || m.wizardResult.PRTitle == "" {
+		return nil
+	}
+	fetch := m.cfg.CampaignProgress
+	title := m.wizardResult.PRTitle
+	return func() tea.Msg {
+		campaign, err := fetch(title)
+		return campaignProgressMsg{Campaign: campaign, Err: err}
+	}
+}
+
+// renderCampaignSparkline renders cumulative opened vs merged PRs as two sparklines
+// sharing one scale, so the gap between them shows how many PRs are still open,
+// followed by a warning when PRs were left out.
+func renderCampaignSparkline(campaign history.Campaign) string {
+	var lines []string
+	if sparklines := renderCampaignPoints(campaign.Points); sparklines != "" {
+		lines = append(lines, sparklines)
+	}
+	if len(campaign.Skipped) > 0 {
+		warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+		lines = append(lines, warningStyle.Render(fmt.Sprintf("  ⚠️ %d PRs left out of the campaign: %s", len(campaign.Skipped), campaign.Skipped[0])))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderCampaignPoints renders the sparklines for the most recent points.
+func renderCampaignPoints(points []history.DayPoint) string {
+	if len(points) > maxSparklineDays {
+		points = points[len(points)-maxSparklineDays:]
+	}
+	if len(points) == 0 {
+		return ""
+	}
+
+	last := points[len(points)-1]
+	var opened, merged []int
+	for _, p := range points {
+		opened = append(opened, p.Opened)
+		merged = append(merged, p.Merged)
+	}
+
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	openedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("33"))
+	mergedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+
+	return fmt.Sprintf("  %s %s %s  %s %s",
+		labelStyle.Render(fmt.Sprintf("Campaign since %s:", points[0].Day.Format("Jan 2"))),
+		openedStyle.Render("opened "+sparkline(opened, last.Opened)),
+		openedStyle.Render(fmt.Sprintf("%d", last.Opened)),
+		mergedStyle.Render("merged "+sparkline(merged, last.Opened)),
+		mergedStyle.Render(fmt.Sprintf("%d", last.Merged)),
+	)
+}
+
+// sparkline renders values as block characters scaled against scale.
+func sparkline(values []int, scale int) string {
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if scale > 0 {
+			idx = v * (len(sparkBlocks) - 1) / scale
+		}
+		b.WriteRune(sparkBlocks[idx])
+	}
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ciWaiting is the CI status of a PR before its checks were first looked at.
+const ciWaiting = "waiting for CI ⏳"
+
+// ciChecksMsg carries the latest CI status of a PR the run opened.
+type ciChecksMsg struct {
+	Repo   string
+	Status string
+}
+
+// ciChecksDoneMsg is sent once the checks of every watched PR have finished,
+// or the wait for them timed out.
+type ciChecksDoneMsg struct{}
+
+// watchChecks waits in the background for the checks on the PRs a
+// code-change run opened, streaming each PR's CI status as it changes.
+func (m dashboardModel) watchChecks() (dashboardModel, tea.Cmd) {
+	if m.cfg.WatchChecks == nil || m.wizardResult == nil || m.wizardResult.Action != "local" {
+		return m, nil
+	}
+	prURLs := make(map[string]string)
+	for repo, result := range m.doneResults() {
+		if result.Success && result.PRURL != "" {
+			prURLs[repo] = result.PRURL
+		}
+	}
+	if len(prURLs) == 0 {
+		return m, nil
+	}
+
+	if m.ciStatus == nil {
+		m.ciStatus = make(map[string]string)
+	}
+	for repo := range prURLs {
+		m.ciStatus[repo] = ciWaiting
+	}
+	watch, ch := m.cfg.WatchChecks, m.statusCh
+	go func() {
+		watch(prURLs, func(repo, status string) {
+			ch <- ciChecksMsg{Repo: repo, Status: status}
+		})
+		ch <- ciChecksDoneMsg{}
+	}()
+	return m, listenForStatus(ch)
+}
+
+// renderCIStatus colours a PR's CI status by outcome.
+func renderCIStatus(status string) string {
+	color := lipgloss.Color("243")
+	switch {
+	case strings.HasPrefix(status, "CI green"):
+		color = lipgloss.Color("40")
+	case strings.HasPrefix(status, "CI red"):
+		color = lipgloss.Color("196")
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(status)
+}
+
+// renderCITally counts the watched PRs by CI outcome, so the red ones stand
+// out without scrolling through every repo.
+func (m dashboardModel) renderCITally() string {
+	results := m.doneResults()
+	var green, red, other int
+	for repo, status := range m.ciStatus {
+		if result, ok := results[repo]; !ok || !result.Success {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(status, "CI green"):
+			green++
+		case strings.HasPrefix(status, "CI red"):
+			red++
+		default:
+			other++
+		}
+	}
+	if green+red+other == 0 {
+		return ""
+	}
+	parts := []string{renderCIStatus(fmt.Sprintf("CI green: %d", green))}
+	if red > 0 {
+		parts = append(parts, renderCIStatus(fmt.Sprintf("CI red: %d", red)))
+	}
+	if other > 0 {
+		parts = append(parts, renderCIStatus(fmt.Sprintf("Other: %d", other)))
+	}
+	return "  " + strings.Join(parts, "  ")
+}
@@ -6,13 +6,22 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/saltpay/copycat/v2/internal/ai"
 	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/handoff"
+	"github.com/saltpay/copycat/v2/internal/history"
+	"github.com/saltpay/copycat/v2/internal/issuetemplate"
 	"github.com/saltpay/copycat/v2/internal/permission"
+	"github.com/saltpay/copycat/v2/internal/timeline"
+	"github.com/saltpay/copycat/v2/internal/util"
 )
 
 type dashboardPhase int
@@ -50,6 +59,12 @@ type slackSendDoneMsg struct {
 	Results []string
 }
 
+// slackTokenPastedMsg carries the clipboard contents pasted as the Slack token.
+type slackTokenPastedMsg struct {
+	Token string
+	Err   error
+}
+
 // projectsFetchedMsg carries the result of an async project refresh.
 type projectsFetchedMsg struct {
 	Projects []config.Project
@@ -62,6 +77,13 @@ type editorFinishedMsg struct {
 	Err     error
 }
 
+// RemoteApprover asks for permission outside the dashboard.
+type RemoteApprover interface {
+	Ask(id, repo, tool, command string) error
+	Withdraw(id, note string) // the request was answered in the dashboard
+	Close()
+}
+
 // DashboardConfig holds all dependencies injected by main.go.
 type DashboardConfig struct {
 	Projects      []config.Project
@@ -69,13 +91,75 @@ type DashboardConfig struct {
 	GitHubConfig  config.GitHubConfig
 	AppConfig     config.Config
 	Parallelism   int
-	FetchProjects func() ([]config.Project, error)
+	FetchProjects func(discovery config.DiscoveryFilter) ([]config.Project, error)
 	ProcessRepos  func(sender *StatusSender, projects []config.Project, setup *WizardResult)
 	AssessRepos   func(sender *StatusSender, projects []config.Project, setup *WizardResult)
+	CreateIssues  func(sender *StatusSender, projects []config.Project, setup *WizardResult)
+
+	// SlackToken is the bot token from $SLACK_BOT_TOKEN or `copycat slack connect`.
+	// When empty, the notifications tab asks for a token.
+	SlackToken string
 
 	// Slack notification callbacks (invoked from the done screen)
-	SendSlackNotifications      func(projects []config.Project, prTitle string, prURLs map[string]string, token string, onStatus func(string))
+	SendSlackNotifications      func(projects []config.Project, prTitle, prompt string, prURLs, ciStatus map[string]string, token string, onStatus func(string))
 	SendSlackAssessmentFindings func(projects []config.Project, question string, findings map[string]string, token string, onStatus func(string))
+	SendSlackIssueNotifications func(projects []config.Project, issueTitle, prompt string, issueURLs map[string]string, token string, onStatus func(string))
+	// CheckSlackChannels returns a warning for each Slack room of the
+	// projects that notifications wouldn't reach, checked before a run.
+	CheckSlackChannels func(projects []config.Project, token string) ([]string, error)
+
+	// StartRemoteApprovals starts asking for permission somewhere else too,
+	// such as a Slack channel, calling onAnswer with each answer given there
+	// and onError when a request there couldn't be settled.
+	// Optional; requests are only asked in the dashboard when nil.
+	StartRemoteApprovals func(onAnswer func(id string, approved bool, by string), onError func(error)) (RemoteApprover, error)
+
+	// CreateJiraTickets creates follow-up tickets for the assessed projects
+	// picked on the done screen, one per project or one per team. Optional;
+	// offered on the assessment Projects tab when set.
+	CreateJiraTickets func(projects []config.Project, question string, findings map[string]string, perTeam bool, onStatus func(string))
+
+	// WatchChecks waits for the checks on the PRs a run opened, keyed by
+	// project key, calling onUpdate with a PR's CI status each time it
+	// changes. Optional; shown on the done screen and in Slack notifications.
+	WatchChecks func(prURLs map[string]string, onUpdate func(repo, status string))
+
+	// SaveProfile persists a named project selection. Optional.
+	SaveProfile func(name string, repos []string) error
+
+	// IssueTemplates reads a repo's issue templates. Optional; the first
+	// selected repo's are offered when creating issues, before the configured ones.
+	IssueTemplates func(project config.Project) ([]issuetemplate.Template, error)
+
+	// CampaignProgress loads opened/merged counts for every PR created under a
+	// PR title across runs. Optional; shown as a sparkline on the done screen.
+	CampaignProgress func(prTitle string) (history.Campaign, error)
+
+	// PromptHistory returns recently used prompts the wizard offers for reuse. Optional.
+	PromptHistory func() []history.PromptEntry
+
+	// AssessmentHistory returns the recorded runs of an assessment question,
+	// oldest first. Optional; compared on the Changes tab.
+	AssessmentHistory func(question string) ([]history.Record, error)
+
+	// ExportRun saves a paused run's handoff bundle and returns its path.
+	// Optional; offered at checkpoints.
+	ExportRun func(bundle handoff.Bundle) (string, error)
+
+	// Resume is a run imported from a handoff bundle, started straight away.
+	Resume *ResumedRun
+
+	// CampaignID tags the run in the history, so its report rolls it up with
+	// the campaign's other runs. Empty when the run is not part of one.
+	CampaignID string
+
+	// Manifest lists the repos (by key or name) a run may target beyond the
+	// max_repos limit. Nil without a manifest.
+	Manifest []string
+
+	// ContextFiles are the manifest's attachments copied into every repo,
+	// shown as the first temporary file changes in the wizard.
+	ContextFiles []config.TempChange
 }
 
 // DashboardResult holds everything the caller needs after the dashboard exits.
@@ -87,6 +171,19 @@ type DashboardResult struct {
 	Interrupted        bool
 	AssessmentSummary  string
 	AssessmentFindings map[string]string
+	Campaign           *history.Campaign
+	HandoffPath        string // where the run was exported to, if it was
+}
+
+// compactHeight is the terminal height below which views switch to compact
+// layouts, e.g. in a half-height tmux pane: a one-line banner, a one-line
+// context header and fewer rows.
+const compactHeight = 24
+
+// compactAt reports whether a terminal height calls for compact layouts. An
+// unknown (zero) height does not.
+func compactAt(height int) bool {
+	return height > 0 && height < compactHeight
 }
 
 type dashboardModel struct {
@@ -108,22 +205,38 @@ type dashboardModel struct {
 	// Permission server
 	permServer *permission.PermissionServer
 	mcpCleanup func()
+	remote     RemoteApprover
 
 	// Shared state
 	selectedProjects []config.Project
 	wizardResult     *WizardResult
 	processResults   map[string]ProjectDoneMsg
 	interrupted      bool
+	handoffPath      string // set when the run was exported for another operator
 
 	// Assessment results
 	assessmentSummary  string
 	assessmentFindings map[string]string
+	assessments        map[string]ai.Assessment // structured answers, when asked for
+	assessSort         assessSort
+
+	// Earlier runs of the same assessment question (loaded after processing)
+	assessmentRuns      []history.Record
+	compareRun          int // index into assessmentRuns of the run compared with the latest
+	changesScrollOffset int
+
+	// Campaign progress across runs (loaded after processing)
+	campaign *history.Campaign
+
+	// CI status of the PRs the run opened, by repo (watched after processing)
+	ciStatus map[string]string
 
 	// Done screen navigation
 	doneScrollOffset int
 	doneCursorRepo   string
 	expandedLogRepo  string
 	logScrollOffset  int
+	logStream        int // which of logStreams the expanded log shows
 
 	// Assessment done screen navigation
 	expandedFindingRepo string // which repo's finding is expanded (empty = none)
@@ -137,11 +250,19 @@ type dashboardModel struct {
 	notifFocus        notifFocus     // which element has focus
 	slackTokenInput   textinput.Model
 	slackToken        string
+	slackTokenNote    string          // why the last paste failed, if it did
+	slackConnected    bool            // token resolved upfront; no token step
 	slackRepos        []string        // repos eligible for Slack (successful + has slack room)
 	slackSelected     map[string]bool // toggled repos (true = will send)
 	slackCursor       int             // cursor index into slackRepos
 	notifScrollOffset int             // scroll offset for repo list
 	slackResults      []string
+
+	// Follow-up Jira tickets for assessed repos
+	jiraSelected map[string]bool // repos picked for tickets
+	jiraPerTeam  bool            // one ticket per team instead of per repo
+	jiraCreating bool
+	jiraResults  []string
 }
 
 func newDashboardModel(cfg DashboardConfig) dashboardModel {
@@ -149,11 +270,33 @@ func newDashboardModel(cfg DashboardConfig) dashboardModel {
 		phase:    phaseProjects,
 		cfg:      cfg,
 		statusCh: make(chan tea.Msg, 100),
-		projects: initialModel(cfg.Projects),
+		projects: newProjectSelector(cfg, cfg.Projects),
+	}
+}
+
+// newProjectSelector builds the project selector with the saved selection
+// profiles, marking the projects that opted out of copycat runs.
+func newProjectSelector(cfg DashboardConfig, projects []config.Project) projectSelectorModel {
+	m := initialModel(projects)
+	m.profiles = make(map[string][]string, len(cfg.AppConfig.Profiles))
+	for name, repos := range cfg.AppConfig.Profiles {
+		m.profiles[name] = repos
+	}
+	m.saveProfile = cfg.SaveProfile
+	m.githubCfg = cfg.GitHubConfig
+	m.optedOut = make(map[int]string)
+	for i, p := range m.projects {
+		if reason := cfg.GitHubConfig.OptOutReason(p); reason != "" {
+			m.optedOut[i] = reason
+		}
 	}
+	return m
 }
 
 func (m dashboardModel) Init() tea.Cmd {
+	if m.cfg.Resume != nil {
+		return func() tea.Msg { return resumeRunMsg{} }
+	}
 	return m.projects.Init()
 }
 
@@ -182,6 +325,21 @@ func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// CI status arrives in any phase, as a retry may start before it is in
+	switch msg := msg.(type) {
+	case ciChecksMsg:
+		m.ciStatus[msg.Repo] = msg.Status
+		return m, listenForStatus(m.statusCh)
+	case ciChecksDoneMsg:
+		return m, nil
+	}
+
+	if _, ok := msg.(resumeRunMsg); ok {
+		m.selectedProjects = m.cfg.Resume.Projects
+		m.wizardResult = &m.cfg.Resume.Wizard
+		return m.startProcessing()
+	}
+
 	switch m.phase {
 	case phaseProjects:
 		return m.updateProjects(msg)
@@ -202,16 +360,41 @@ func (m dashboardModel) updateProjects(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if len(msg.Selected) == 0 {
 			return m, tea.Quit
 		}
+		if notice := m.runLimitNotice(msg.Selected); notice != "" {
+			m.projects.profileNotice = notice
+			return m, nil
+		}
 		m.selectedProjects = msg.Selected
-		m.wizard = newWizardModel(m.cfg.AIToolsConfig, m.cfg.AppConfig.AgentInstructions, m.selectedProjects)
+		m.wizard = newWizardModel(m.cfg.AIToolsConfig, m.cfg.AppConfig.AgentInstructions, m.selectedProjects, m.cfg.AppConfig.LicenseHeader.Text != "")
+		if m.cfg.PromptHistory != nil {
+			m.wizard.promptHistory = m.cfg.PromptHistory()
+		}
+		m.wizard.templates = m.cfg.AppConfig.Prompts
+		m.wizard = m.wizard.withPipelines(m.cfg.AppConfig.Actions)
+		m.wizard = m.wizard.withVerifyCommand(&m.cfg.AppConfig)
+		m.wizard = m.wizard.withPRMetadata(m.cfg.AppConfig.PRMetadata)
+		m.wizard = m.wizard.withIssues(m.cfg.AppConfig.Issues)
+		if fetch := m.cfg.IssueTemplates; fetch != nil {
+			first := m.selectedProjects[0]
+			m.wizard = m.wizard.withIssueTemplates(func() ([]issuetemplate.Template, error) { return fetch(first) })
+		}
+		m.wizard.tempChanges = slices.Clone(m.cfg.ContextFiles)
+		m.wizard.confirmAbove = m.cfg.AppConfig.RunLimits.ConfirmAbove
+		m.wizard.prPolicy = m.cfg.AppConfig.PRPolicy
 		m.wizard.termWidth = m.termWidth
+		m.wizard.termHeight = m.termHeight
+		m.wizard.promptInput.SetHeight(promptHeight(m.termHeight))
 		m.phase = phaseWizard
 		return m, m.wizard.Init()
 
 	case projectsRefreshMsg:
 		m.projects.refreshing = true
+		if msg.Discovery != nil {
+			m.cfg.GitHubConfig.Discovery = *msg.Discovery
+		}
+		discovery := m.cfg.GitHubConfig.Discovery
 		return m, func() tea.Msg {
-			projects, err := m.cfg.FetchProjects()
+			projects, err := m.cfg.FetchProjects(discovery)
 			return projectsFetchedMsg{Projects: projects, Err: err}
 		}
 
@@ -223,7 +406,9 @@ func (m dashboardModel) updateProjects(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		// Re-create projects model with fresh data, preserving nothing
 		m.cfg.Projects = msg.Projects
-		m.projects = initialModel(msg.Projects)
+		profiles := m.projects.profiles
+		m.projects = newProjectSelector(m.cfg, msg.Projects)
+		m.projects.profiles = profiles
 		// Show warning if any projects are missing slack rooms
 		missing := m.projects.countMissingSlackRooms()
 		if missing > 0 {
@@ -242,6 +427,7 @@ func (m dashboardModel) updateProjects(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m dashboardModel) updateWizard(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case wizardCompletedMsg:
+		msg.Result.CampaignID = m.cfg.CampaignID
 		m.wizardResult = &msg.Result
 		return m.startProcessing()
 
@@ -254,13 +440,13 @@ func (m dashboardModel) updateWizard(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.wizard.prompt = msg.Content
 		m.wizard.promptInput.Blur()
-		if !m.wizard.skipIgnoreInstructions {
-			m.wizard.currentStep = stepIgnoreInstructions
-			return m, nil
+		if m.wizard.action == "issues" {
+			updated, cmd := m.wizard.complete()
+			m.wizard = updated.(wizardModel)
+			return m, cmd
 		}
-		result := m.wizard.buildResult()
-		m.wizardResult = &result
-		return m.startProcessing()
+		m.wizard.currentStep = stepTempFiles
+		return m, nil
 	}
 
 	updated, cmd := m.wizard.Update(msg)
@@ -268,15 +454,44 @@ func (m dashboardModel) updateWizard(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// runLimitNotice explains why selected may not be run, or returns "" if it
+// may: runs on more than max_repos repos must all be listed in a manifest.
+func (m dashboardModel) runLimitNotice(selected []config.Project) string {
+	limit := m.cfg.AppConfig.RunLimits.MaxRepos
+	if limit == 0 || len(selected) <= limit {
+		return ""
+	}
+	if m.cfg.Manifest == nil {
+		return fmt.Sprintf("⚠ %d repos selected. Runs on more than %d need a manifest listing them: copycat -manifest repos.txt", len(selected), limit)
+	}
+	var unlisted []string
+	for _, p := range selected {
+		if !slices.Contains(m.cfg.Manifest, p.Key()) && !slices.Contains(m.cfg.Manifest, p.Repo) {
+			unlisted = append(unlisted, p.Key())
+		}
+	}
+	if len(unlisted) > 0 {
+		return fmt.Sprintf("⚠ %d selected repos are not in the manifest, e.g. %s", len(unlisted), unlisted[0])
+	}
+	return ""
+}
+
 func (m dashboardModel) openEditor() tea.Cmd {
-	tmpFile, err := os.CreateTemp("", "copycat-prompt-*.txt")
+	tmpFile, err := os.CreateTemp("", "copycat-prompt-*.md")
 	if err != nil {
 		return func() tea.Msg {
 			return editorFinishedMsg{Err: err}
 		}
 	}
 	tmpPath := tmpFile.Name()
+	_, err = tmpFile.WriteString(promptScaffold(m.wizard.promptInput.Value()))
 	tmpFile.Close()
+	if err != nil {
+		os.Remove(tmpPath)
+		return func() tea.Msg {
+			return editorFinishedMsg{Err: err}
+		}
+	}
 
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
@@ -293,33 +508,45 @@ func (m dashboardModel) openEditor() tea.Cmd {
 		if readErr != nil {
 			return editorFinishedMsg{Err: readErr}
 		}
-		return editorFinishedMsg{Content: strings.TrimSpace(string(content))}
+		return editorFinishedMsg{Content: stripScaffold(string(content))}
 	})
 }
 
 func (m dashboardModel) startProcessing() (tea.Model, tea.Cmd) {
+	if m.wizardResult.Action == "local" {
+		// One job per target branch, keyed by repo@branch
+		m.selectedProjects = config.ExpandTargetBranches(m.selectedProjects)
+	}
 	var repos []string
 	for _, p := range m.selectedProjects {
-		repos = append(repos, p.Repo)
+		repos = append(repos, p.Key())
 	}
 
 	checkpointInterval := 0
-	if m.cfg.Parallelism > 0 && len(repos) > 0 {
-		// Only checkpoint for non-issues workflows (checked below)
+	if m.cfg.Parallelism > 0 && len(repos) > 0 && m.wizardResult.Action != "issues" {
+		// Only checkpoint for non-issues workflows
 		checkpointInterval = m.cfg.Parallelism
 		if checkpointInterval < 5 {
 			checkpointInterval = 5
 		}
 	}
+	budget := m.cfg.AppConfig.Budget
+	if budget.ReplaceRepoCheckpoints {
+		// The backend pauses with a PauseMsg when the budget is reached
+		checkpointInterval = 0
+	}
 
-	if checkpointInterval > 0 {
+	if checkpointInterval > 0 || (budget.LimitUSD > 0 && len(repos) > 0) {
 		m.resumeCh = make(chan string, 1)
 	}
 
 	m.cancelRegistry = &CancelRegistry{}
 	m.progress = NewProgressModel(repos, checkpointInterval, m.wizardResult.BranchName, m.wizardResult.PRTitle, m.wizardResult.Prompt)
 	m.progress.termWidth = m.termWidth
+	m.progress.termHeight = m.termHeight
+	m.progress.issues = m.wizardResult.Action == "issues"
 	m.progress.cancelRegistry = m.cancelRegistry
+	m.progress.canExport = m.cfg.ExportRun != nil
 	m.phase = phaseProcessing
 
 	// Start background processing
@@ -331,16 +558,68 @@ func (m dashboardModel) startProcessing() (tea.Model, tea.Cmd) {
 		CancelRegistry: m.cancelRegistry,
 	}
 
-	// Set up permission server if the AI tool supports it (skip for assessment — read-only)
-	if m.wizardResult.Action != "assessment" && m.wizardResult.AITool != nil && m.wizardResult.AITool.SupportsPermissionPrompt {
+	// Set up permission server if the AI tool supports it (local changes only;
+	// assessments are read-only and issues run no AI tool)
+	if m.wizardResult.Action == "local" && m.wizardResult.AITool != nil && m.wizardResult.AITool.SupportsPermissionPrompt {
 		permServer, err := permission.NewPermissionServer(m.statusCh)
 		if err != nil {
 			log.Printf("⚠️ Failed to start permission server: %v", err)
 		} else {
 			m.permServer = permServer
-			mcpPath, cleanup, err := permission.GenerateMCPConfig(permServer.Port())
+			// Decisions configured or saved on earlier runs are answered
+			// without asking
+			rules := m.cfg.AppConfig.Permissions.Policy()
+			var saved permission.Policy
+			policyPath, err := config.PermissionPolicyPath()
+			if err == nil {
+				saved, err = permission.LoadPolicy(policyPath)
+			}
 			if err != nil {
-				log.Printf("⚠️ Failed to generate MCP config: %v", err)
+				log.Printf("⚠️ Saved permission decisions unavailable: %v", err)
+				policyPath = ""
+			}
+			m.progress = m.progress.withPermissionPolicy(rules, saved, policyPath)
+			if m.cfg.AppConfig.Permissions.Mode == config.PermissionModePolicy {
+				auditPath, err := config.PermissionAuditPath()
+				if err != nil {
+					log.Printf("⚠️ Permission decisions can't be audited, so all are denied: %v", err)
+				}
+				permServer.AnswerFromPolicy(rules.With(saved), auditPath)
+			} else if m.cfg.StartRemoteApprovals != nil {
+				statusCh := m.statusCh
+				remote, err := m.cfg.StartRemoteApprovals(func(id string, approved bool, by string) {
+					statusCh <- permission.RemoteAnswerMsg{ID: id, Approved: approved, By: by}
+				}, func(err error) {
+					// Also reported as the run ends, when no one may be reading
+					select {
+					case statusCh <- PostStatusMsg{Line: fmt.Sprintf("⚠️ %v", err)}:
+					default:
+						log.Printf("⚠️ %v", err)
+					}
+				})
+				if err != nil {
+					log.Printf("⚠️ Permission requests can only be answered here: %v", err)
+				} else {
+					m.remote = remote
+					m.progress = m.progress.withRemoteApprover(remote)
+				}
+			}
+			// Requests outlive a crash: a restarted copycat answers them
+			queuePath, err := config.PermissionQueuePath()
+			if err == nil {
+				err = permServer.Persist(queuePath)
+			}
+			if err != nil {
+				log.Printf("⚠️ Permission requests won't survive a restart: %v", err)
+				queuePath = ""
+			}
+			generate := permission.GenerateMCPConfig
+			if m.wizardResult.AITool.Adapter() == config.PermissionAdapterHook {
+				generate = permission.GenerateHookSettings
+			}
+			mcpPath, cleanup, err := generate(permServer.Port(), queuePath)
+			if err != nil {
+				log.Printf("⚠️ Failed to generate permission prompt config: %v", err)
 				permServer.Shutdown(context.Background())
 				m.permServer = nil
 			} else {
@@ -356,13 +635,30 @@ func (m dashboardModel) startProcessing() (tea.Model, tea.Cmd) {
 		processFn = func() {
 			m.cfg.AssessRepos(sender, m.selectedProjects, m.wizardResult)
 		}
+	case "issues":
+		processFn = func() {
+			m.cfg.CreateIssues(sender, m.selectedProjects, m.wizardResult)
+		}
 	default:
 		processFn = func() {
 			m.cfg.ProcessRepos(sender, m.selectedProjects, m.wizardResult)
 		}
 	}
 
+	checkChannels := m.cfg.CheckSlackChannels
+	token := m.cfg.SlackToken
+	projects := m.selectedProjects
 	go func() {
+		// Rooms that can't be notified are flagged now, not when sending fails
+		if checkChannels != nil && token != "" {
+			warnings, err := checkChannels(projects, token)
+			if err != nil {
+				sender.PostStatus(fmt.Sprintf("⚠️ Couldn't check the Slack channels: %s", util.RedactSecret(err.Error(), token)))
+			}
+			for _, warning := range warnings {
+				sender.PostStatus(warning)
+			}
+		}
 		processFn()
 		sender.Finish()
 	}()
@@ -380,12 +676,16 @@ func (m dashboardModel) updateProcessing(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m = m.cleanupPermissionServer()
 		m.phase = phaseDone
 		m = m.initDoneScreen()
-		return m, nil
+		var watch tea.Cmd
+		m, watch = m.watchChecks()
+		return m, tea.Batch(m.loadCampaignProgress(), m.loadAssessmentHistory(), watch)
 	case resumeProcessingMsg:
 		if m.resumeCh != nil {
 			m.resumeCh <- msg.NewPrompt
 		}
 		return m, nil
+	case exportRunMsg:
+		return m.exportRun()
 	case cancelProjectMsg:
 		if m.cancelRegistry != nil {
 			m.cancelRegistry.Cancel(msg.Repo)
@@ -399,12 +699,13 @@ func (m dashboardModel) updateProcessing(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if ar, ok := msg.(AssessmentResultMsg); ok {
 		m.assessmentSummary = ar.Summary
 		m.assessmentFindings = ar.Findings
+		m.assessments = ar.Assessments
 	}
 
 	// Pump status channel messages
 	var cmds []tea.Cmd
 	switch msg.(type) {
-	case ProjectStatusMsg, ProjectDoneMsg, permission.PermissionRequestMsg, PostStatusMsg, AssessmentResultMsg:
+	case ProjectStatusMsg, ProjectLogMsg, ProjectDoneMsg, PauseMsg, permission.PermissionRequestMsg, permission.PolicyDecisionMsg, permission.RemoteAnswerMsg, ReviewDecisionMsg, PostStatusMsg, RateLimitMsg, AssessmentResultMsg:
 		cmds = append(cmds, listenForStatus(m.statusCh))
 	}
 
@@ -426,13 +727,17 @@ func (m dashboardModel) cleanupPermissionServer() dashboardModel {
 		m.mcpCleanup()
 		m.mcpCleanup = nil
 	}
+	if m.remote != nil {
+		m.remote.Close()
+		m.remote = nil
+	}
 	return m
 }
 
 // doneTabCount returns the number of tabs for the current workflow.
 func (m dashboardModel) doneTabCount() int {
 	if m.wizardResult != nil && m.wizardResult.Action == "assessment" {
-		return 3 // Summary | Projects | Notifications
+		return 4 // Summary | Projects | Changes | Notifications
 	}
 	return 2 // Results | Notifications
 }
@@ -445,7 +750,9 @@ func (m dashboardModel) doneTabLabel(idx int) string {
 			return "Summary"
 		case 1:
 			return "Projects"
-		case 2:
+		case assessTabChanges:
+			return "Changes"
+		case 3:
 			return "Notifications"
 		}
 	}
@@ -471,6 +778,39 @@ func (m dashboardModel) updateDone(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if pasted, ok := msg.(slackTokenPastedMsg); ok {
+		if pasted.Err != nil {
+			m.slackTokenNote = fmt.Sprintf("Couldn't read the clipboard: %v", pasted.Err)
+			return m, nil
+		}
+		m.slackTokenNote = ""
+		m.slackTokenInput.SetValue(strings.TrimSpace(pasted.Token))
+		m.slackTokenInput.CursorEnd()
+		return m, nil
+	}
+
+	if jiraDone, ok := msg.(jiraTicketsDoneMsg); ok {
+		m.jiraCreating = false
+		m.jiraResults = jiraDone.Results
+		return m, nil
+	}
+
+	if runs, ok := msg.(assessmentHistoryMsg); ok {
+		if runs.Err == nil && len(runs.Runs) > 1 {
+			m.assessmentRuns = runs.Runs
+			m.compareRun = len(runs.Runs) - 2
+			m.changesScrollOffset = 0
+		}
+		return m, nil
+	}
+
+	if progress, ok := msg.(campaignProgressMsg); ok {
+		if progress.Err == nil && (len(progress.Campaign.Points) > 0 || len(progress.Campaign.Skipped) > 0) {
+			m.campaign = &progress.Campaign
+		}
+		return m, nil
+	}
+
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
 		tokenInputFocused := m.isNotifTab() && m.notifFocus == notifFocusToken && m.slackTokenInput.Focused()
 
@@ -520,6 +860,11 @@ func (m dashboardModel) updateDone(msg tea.Msg) (tea.Model, tea.Cmd) {
 					switchTab(2)
 				}
 				return m, nil
+			case "4":
+				if tabCount >= 4 {
+					switchTab(3)
+				}
+				return m, nil
 			}
 		}
 
@@ -529,6 +874,9 @@ func (m dashboardModel) updateDone(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if m.wizardResult != nil && m.wizardResult.Action == "assessment" {
+			if m.activeTab == assessTabChanges {
+				return m.updateDoneChangesTab(keyMsg)
+			}
 			return m.updateDoneAssessmentTab(keyMsg)
 		}
 		return m.updateDoneResultsTab(keyMsg)
@@ -558,10 +906,18 @@ func (m dashboardModel) updateDoneResultsTab(keyMsg tea.KeyMsg) (tea.Model, tea.
 				m.logScrollOffset--
 			}
 			return m, nil
+		case "left", "right":
+			step := 1
+			if keyMsg.String() == "left" {
+				step = len(logStreams) - 1
+			}
+			m.logStream = (m.logStream + step) % len(logStreams)
+			m.logScrollOffset = 0
+			return m, nil
 		case "down", "j":
 			results := m.doneResults()
 			if result, ok := results[m.expandedLogRepo]; ok {
-				lines := aiOutputLines(result.AIOutput)
+				lines := aiOutputLines(logStreamOutput(result, m.logStream))
 				maxScroll := len(lines) - maxLogLines
 				if maxScroll < 0 {
 					maxScroll = 0
@@ -579,16 +935,20 @@ func (m dashboardModel) updateDoneResultsTab(keyMsg tea.KeyMsg) (tea.Model, tea.
 	case "enter", "l":
 		if m.doneCursorRepo != "" {
 			results := m.doneResults()
-			if result, ok := results[m.doneCursorRepo]; ok && result.AIOutput != "" {
+			if result, ok := results[m.doneCursorRepo]; ok && hasLogs(result) {
 				m.expandedLogRepo = m.doneCursorRepo
 				m.logScrollOffset = 0
+				m.logStream = 0
+				for m.logStream < len(logStreams)-1 && logStreamOutput(result, m.logStream) == "" {
+					m.logStream++
+				}
 			}
 		}
 		return m, nil
 	case "r":
 		var retryProjects []config.Project
 		for _, p := range m.selectedProjects {
-			if result, ok := m.processResults[p.Repo]; ok && !result.Success && !result.Skipped {
+			if result, ok := m.processResults[p.Key()]; ok && !result.Success && !result.Skipped {
 				retryProjects = append(retryProjects, p)
 			}
 		}
@@ -600,7 +960,7 @@ func (m dashboardModel) updateDoneResultsTab(keyMsg tea.KeyMsg) (tea.Model, tea.
 	case "a":
 		var retryProjects []config.Project
 		for _, p := range m.selectedProjects {
-			if result, ok := m.processResults[p.Repo]; ok && !result.Success {
+			if result, ok := m.processResults[p.Key()]; ok && !result.Success {
 				retryProjects = append(retryProjects, p)
 			}
 		}
@@ -686,6 +1046,10 @@ func (m dashboardModel) updateDoneAssessmentTab(keyMsg tea.KeyMsg) (tea.Model, t
 		return m, nil
 	}
 
+	if m, cmd, handled := m.updateJiraKeys(keyMsg); handled {
+		return m, cmd
+	}
+
 	switch keyMsg.String() {
 	case "enter", "l":
 		if m.doneCursorRepo != "" {
@@ -701,7 +1065,7 @@ func (m dashboardModel) updateDoneAssessmentTab(keyMsg tea.KeyMsg) (tea.Model, t
 	case "r":
 		var retryProjects []config.Project
 		for _, p := range m.selectedProjects {
-			if result, ok := m.processResults[p.Repo]; ok && !result.Success {
+			if result, ok := m.processResults[p.Key()]; ok && !result.Success {
 				retryProjects = append(retryProjects, p)
 			}
 		}
@@ -710,6 +1074,15 @@ func (m dashboardModel) updateDoneAssessmentTab(keyMsg tea.KeyMsg) (tea.Model, t
 		}
 		m.selectedProjects = retryProjects
 		return m.startProcessing()
+	case "s":
+		if len(m.assessments) > 0 {
+			m.assessSort = (m.assessSort + 1) % assessSort(len(assessSortLabels))
+			m.doneScrollOffset = 0
+			if repos := m.doneVisibleRepos(); len(repos) > 0 {
+				m.doneCursorRepo = repos[0]
+			}
+		}
+		return m, nil
 	case "up", "k":
 		m = m.moveAssessCursor(-1)
 	case "down", "j":
@@ -746,9 +1119,15 @@ func (m dashboardModel) updateDoneNotifTab(keyMsg tea.KeyMsg) (tea.Model, tea.Cm
 					m.notifFocus = notifFocusSend
 				}
 				return m, nil
+			case tea.KeyCtrlV:
+				// Replace rather than insert: the token is masked, so a
+				// paste into the middle of it couldn't be checked
+				return m, pasteSlackToken
 			}
 			var cmd tea.Cmd
 			m.slackTokenInput, cmd = m.slackTokenInput.Update(keyMsg)
+			// Only the end of the token is shown, so keep the cursor there
+			m.slackTokenInput.CursorEnd()
 			return m, cmd
 
 		case notifFocusRepos:
@@ -756,7 +1135,7 @@ func (m dashboardModel) updateDoneNotifTab(keyMsg tea.KeyMsg) (tea.Model, tea.Cm
 			case "up", "k":
 				if m.slackCursor > 0 {
 					m.slackCursor--
-				} else {
+				} else if !m.slackConnected {
 					// Move focus back to token input
 					m.notifFocus = notifFocusToken
 					m.slackTokenInput.Focus()
@@ -793,7 +1172,7 @@ func (m dashboardModel) updateDoneNotifTab(keyMsg tea.KeyMsg) (tea.Model, tea.Cm
 				if len(m.slackRepos) > 0 {
 					m.notifFocus = notifFocusRepos
 					m.slackCursor = len(m.slackRepos) - 1
-				} else {
+				} else if !m.slackConnected {
 					m.notifFocus = notifFocusToken
 					m.slackTokenInput.Focus()
 				}
@@ -836,7 +1215,7 @@ func (m dashboardModel) sendSlackNotifications() (tea.Model, tea.Cmd) {
 
 	var sendProjects []config.Project
 	for _, p := range m.selectedProjects {
-		if m.slackSelected[p.Repo] {
+		if m.slackSelected[p.Key()] {
 			sendProjects = append(sendProjects, p)
 		}
 	}
@@ -858,13 +1237,41 @@ func (m dashboardModel) sendSlackNotifications() (tea.Model, tea.Cmd) {
 			}
 			ch <- slackSendDoneMsg{Results: results}
 		}()
+	} else if m.wizardResult.Action == "issues" {
+		issueTitle := m.wizardResult.PRTitle
+		prompt := m.wizardResult.Prompt
+		issueURLs := make(map[string]string)
+		results := m.doneResults()
+		for _, p := range sendProjects {
+			if result, ok := results[p.Key()]; ok {
+				issueURLs[p.Key()] = result.PRURL
+			}
+		}
+		sendFn := m.cfg.SendSlackIssueNotifications
+
+		go func() {
+			var resultLines []string
+			if sendFn != nil {
+				sendFn(sendProjects, issueTitle, prompt, issueURLs, token, func(line string) {
+					resultLines = append(resultLines, line)
+				})
+			}
+			ch <- slackSendDoneMsg{Results: resultLines}
+		}()
 	} else {
 		prTitle := m.wizardResult.PRTitle
+		prompt := m.wizardResult.Prompt
 		prURLs := make(map[string]string)
 		results := m.doneResults()
 		for _, p := range sendProjects {
-			if result, ok := results[p.Repo]; ok {
-				prURLs[p.Repo] = result.PRURL
+			if result, ok := results[p.Key()]; ok {
+				prURLs[p.Key()] = result.PRURL
+			}
+		}
+		ciStatus := make(map[string]string)
+		for _, p := range sendProjects {
+			if status, ok := m.ciStatus[p.Key()]; ok {
+				ciStatus[p.Key()] = status
 			}
 		}
 		sendFn := m.cfg.SendSlackNotifications
@@ -872,7 +1279,7 @@ func (m dashboardModel) sendSlackNotifications() (tea.Model, tea.Cmd) {
 		go func() {
 			var resultLines []string
 			if sendFn != nil {
-				sendFn(sendProjects, prTitle, prURLs, token, func(line string) {
+				sendFn(sendProjects, prTitle, prompt, prURLs, ciStatus, token, func(line string) {
 					resultLines = append(resultLines, line)
 				})
 			}
@@ -893,6 +1300,7 @@ func (m dashboardModel) initDoneScreen() dashboardModel {
 	m.summaryExpanded = false
 	m.summaryScrollOffset = 0
 	m.slackResults = nil
+	m = m.initJiraSelection()
 
 	repos := m.doneVisibleRepos()
 	if m.wizardResult != nil && m.wizardResult.Action == "assessment" && m.assessmentSummary != "" {
@@ -905,10 +1313,10 @@ func (m dashboardModel) initDoneScreen() dashboardModel {
 	results := m.doneResults()
 	var slackRepos []string
 	for _, p := range m.selectedProjects {
-		if result, ok := results[p.Repo]; ok && result.Success {
+		if result, ok := results[p.Key()]; ok && result.Success {
 			room := strings.TrimSpace(p.SlackRoom)
 			if room != "" {
-				slackRepos = append(slackRepos, p.Repo)
+				slackRepos = append(slackRepos, p.Key())
 			}
 		}
 	}
@@ -925,10 +1333,11 @@ func (m dashboardModel) initDoneScreen() dashboardModel {
 	tokenInput.CharLimit = 512
 	tokenInput.Width = 60
 	m.notifPhase = notifPhaseReady
-	if envToken := os.Getenv("SLACK_BOT_TOKEN"); envToken != "" {
-		tokenInput.SetValue(envToken)
-		m.slackToken = envToken
-		// Token pre-filled: start focused on repos
+	m.slackConnected = m.cfg.SlackToken != ""
+	if m.slackConnected {
+		tokenInput.SetValue(m.cfg.SlackToken)
+		m.slackToken = m.cfg.SlackToken
+		// Token already available: skip the token step
 		m.notifFocus = notifFocusRepos
 	} else {
 		tokenInput.Focus()
@@ -948,6 +1357,15 @@ func (m dashboardModel) doneResults() map[string]ProjectDoneMsg {
 	return results
 }
 
+// runUsage returns the AI usage summed over every finished repo.
+func (m dashboardModel) runUsage() ai.Usage {
+	var total ai.Usage
+	for _, result := range m.doneResults() {
+		total = total.Add(result.Usage)
+	}
+	return total
+}
+
 // doneVisibleRepos returns the list of repos that have results.
 func (m dashboardModel) doneVisibleRepos() []string {
 	results := m.doneResults()
@@ -957,7 +1375,7 @@ func (m dashboardModel) doneVisibleRepos() []string {
 			repos = append(repos, repo)
 		}
 	}
-	return repos
+	return m.sortAssessedRepos(repos)
 }
 
 // moveDoneCursor moves the cursor up or down in the done screen.
@@ -992,15 +1410,31 @@ func (m dashboardModel) moveDoneCursor(delta int) dashboardModel {
 	return m
 }
 
+// compactSaving is how many lines the done screen's compact layout saves:
+// two banner lines, the blank line under the tab bar and the one above the
+// bottom border.
+func (m dashboardModel) compactSaving() int {
+	if compactAt(m.termHeight) {
+		return 4
+	}
+	return 0
+}
+
 // doneMaxVisibleRepos returns how many repo rows fit on screen.
 // Reserves space for: banner(3) + border(2) + header(3) + summary(2) + postLines + help(2) + padding(2).
 func (m dashboardModel) doneMaxVisibleRepos() int {
-	overhead := 14 + len(m.progress.postLines)
+	overhead := 14 - m.compactSaving() + len(m.progress.postLines)
+	if m.campaign != nil {
+		overhead += 2
+		if len(m.campaign.Skipped) > 0 {
+			overhead++
+		}
+	}
 	// Account for expanded log box (content lines + 2 for box border)
 	if m.expandedLogRepo != "" {
 		results := m.doneResults()
 		if result, ok := results[m.expandedLogRepo]; ok {
-			lines := aiOutputLines(result.AIOutput)
+			lines := aiOutputLines(logStreamOutput(result, m.logStream))
 			logHeight := len(lines)
 			if logHeight > maxLogLines {
 				logHeight = maxLogLines
@@ -1015,6 +1449,10 @@ func (m dashboardModel) doneMaxVisibleRepos() int {
 				}
 			}
 			logHeight += 2 // box border top + bottom
+			logHeight++    // stream tabs
+			if len(lines) == 0 {
+				logHeight++ // empty stream notice
+			}
 			overhead += logHeight
 		}
 	}
@@ -1028,7 +1466,7 @@ func (m dashboardModel) doneMaxVisibleRepos() int {
 // assessDoneMaxVisibleRepos returns how many repo rows fit on the assessment done screen (Projects tab).
 func (m dashboardModel) assessDoneMaxVisibleRepos() int {
 	// Base overhead: banner(3) + border(2) + tab bar(2) + stats(2) + blank before help(1) + help(1) + padding(2) = 13
-	overhead := 13
+	overhead := 13 - m.compactSaving()
 
 	// Expanded finding box height
 	if m.expandedFindingRepo != "" {
@@ -1052,6 +1490,11 @@ func (m dashboardModel) assessDoneMaxVisibleRepos() int {
 		}
 	}
 
+	// Follow-up ticket choices and results
+	if m.jiraEnabled() {
+		overhead += 2 + len(m.jiraResults)
+	}
+
 	available := m.termHeight - overhead
 	if available < 3 {
 		available = 3
@@ -1098,9 +1541,13 @@ func (m dashboardModel) moveAssessCursor(delta int) dashboardModel {
 }
 
 func (m dashboardModel) View() string {
-	// Banner always visible above the border
+	// Banner always visible above the border, on one line in compact layouts
 	bannerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
 	banner := bannerStyle.Render(" /\\_/\\\n( o.o ) COPYCAT\n > ^ <")
+	compact := compactAt(m.termHeight)
+	if compact {
+		banner = bannerStyle.Render("=^.^= COPYCAT")
+	}
 
 	// Render phase content
 	var content string
@@ -1114,6 +1561,10 @@ func (m dashboardModel) View() string {
 	case phaseDone:
 		content = m.renderDoneSummary()
 	}
+	if compact {
+		// no blank line above the bottom border
+		content = strings.TrimSuffix(content, "\n")
+	}
 
 	// Border wrapping the content
 	borderWidth := m.termWidth - 2 // account for border chars
@@ -1163,11 +1614,22 @@ func (m dashboardModel) renderDoneSummary() string {
 	} else {
 		b.WriteString(titleStyle.Render("Processing complete!"))
 	}
+	if usage := m.runUsage(); !usage.IsZero() {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render("  💲 " + usage.String()))
+	}
 	b.WriteString("\n")
+	if m.handoffPath != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(
+			fmt.Sprintf("📦 Run exported to %s — resume it with: copycat -resume %s", m.handoffPath, m.handoffPath)))
+		b.WriteString("\n")
+	}
 
 	// Tab bar
 	b.WriteString(m.renderTabBar())
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	if !compactAt(m.termHeight) {
+		b.WriteString("\n")
+	}
 
 	// Dispatch to tab content
 	if m.isNotifTab() {
@@ -1175,6 +1637,8 @@ func (m dashboardModel) renderDoneSummary() string {
 	} else if isAssessment {
 		if m.activeTab == 0 {
 			b.WriteString(m.renderAssessSummaryTabContent())
+		} else if m.activeTab == assessTabChanges {
+			b.WriteString(m.renderChangesTabContent())
 		} else {
 			b.WriteString(m.renderAssessProjectsTabContent())
 		}
@@ -1234,7 +1698,17 @@ func (m dashboardModel) renderLocalResultsTabContent() string {
 	if failed > 0 {
 		b.WriteString(failStyle.Render(fmt.Sprintf("Failed: %d", failed)))
 	}
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	if tally := m.renderCITally(); tally != "" {
+		b.WriteString(tally)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if m.campaign != nil {
+		b.WriteString(renderCampaignSparkline(*m.campaign))
+		b.WriteString("\n\n")
+	}
 
 	visibleRepos := m.doneVisibleRepos()
 	maxVisible := m.doneMaxVisibleRepos()
@@ -1265,7 +1739,7 @@ func (m dashboardModel) renderLocalResultsTabContent() string {
 		}
 
 		logsBtn := ""
-		if result.AIOutput != "" {
+		if hasLogs(result) {
 			if isExpanded {
 				logsBtn = " " + logBtnActiveStyle.Render("[▼ logs]")
 			} else {
@@ -1273,42 +1747,56 @@ func (m dashboardModel) renderLocalResultsTabContent() string {
 			}
 		}
 
-		b.WriteString(fmt.Sprintf("%s%s %s%s\n", prefix, repoStyle.Render(fmt.Sprintf("[%s]", repo)), result.Status, logsBtn))
+		ci := ""
+		if status := m.ciStatus[repo]; status != "" && result.Success {
+			ci = " " + renderCIStatus(status)
+		}
+
+		b.WriteString(fmt.Sprintf("%s%s %s%s%s\n", prefix, repoStyle.Render(fmt.Sprintf("[%s]", repo)), result.Status, ci, logsBtn))
 
 		if isExpanded {
-			lines := aiOutputLines(result.AIOutput)
-			if len(lines) > 0 {
-				logStart := m.logScrollOffset
-				logEnd := logStart + maxLogLines
-				if logEnd > len(lines) {
-					logEnd = len(lines)
-				}
+			lines := aiOutputLines(logStreamOutput(result, m.logStream))
+			logStart := m.logScrollOffset
+			logEnd := logStart + maxLogLines
+			if logEnd > len(lines) {
+				logEnd = len(lines)
+			}
 
-				maxContentWidth := logBoxWidth - 4
-				var contentLines []string
-				if logStart > 0 {
-					contentLines = append(contentLines, dimStyle.Render(fmt.Sprintf("  ↑ %d more", logStart)))
-				}
-				for _, line := range lines[logStart:logEnd] {
-					if len(line) > maxContentWidth {
-						line = line[:maxContentWidth-3] + "..."
-					}
-					contentLines = append(contentLines, logLineStyle.Render(line))
+			maxContentWidth := logBoxWidth - 4
+			var tabs []string
+			for i, name := range logStreams {
+				if i == m.logStream {
+					tabs = append(tabs, logBtnActiveStyle.Render(name))
+				} else {
+					tabs = append(tabs, dimStyle.Render(name))
 				}
-				if len(lines)-logEnd > 0 {
-					contentLines = append(contentLines, dimStyle.Render(fmt.Sprintf("  ↓ %d more", len(lines)-logEnd)))
+			}
+			contentLines := []string{strings.Join(tabs, dimStyle.Render(" │ "))}
+			if len(lines) == 0 {
+				contentLines = append(contentLines, dimStyle.Render(fmt.Sprintf("  no %s output", logStreams[m.logStream])))
+			}
+			if logStart > 0 {
+				contentLines = append(contentLines, dimStyle.Render(fmt.Sprintf("  ↑ %d more", logStart)))
+			}
+			for _, line := range lines[logStart:logEnd] {
+				if len(line) > maxContentWidth {
+					line = line[:maxContentWidth-3] + "..."
 				}
+				contentLines = append(contentLines, logLineStyle.Render(line))
+			}
+			if len(lines)-logEnd > 0 {
+				contentLines = append(contentLines, dimStyle.Render(fmt.Sprintf("  ↓ %d more", len(lines)-logEnd)))
+			}
 
-				logBoxStyle := lipgloss.NewStyle().
-					Border(lipgloss.RoundedBorder()).
-					BorderForeground(lipgloss.Color("238")).
-					Padding(0, 1).
-					Width(logBoxWidth)
+			logBoxStyle := lipgloss.NewStyle().
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(lipgloss.Color("238")).
+				Padding(0, 1).
+				Width(logBoxWidth)
 
-				rendered := logBoxStyle.Render(strings.Join(contentLines, "\n"))
-				for _, boxLine := range strings.Split(rendered, "\n") {
-					b.WriteString("    " + boxLine + "\n")
-				}
+			rendered := logBoxStyle.Render(strings.Join(contentLines, "\n"))
+			for _, boxLine := range strings.Split(rendered, "\n") {
+				b.WriteString("    " + boxLine + "\n")
 			}
 		}
 	}
@@ -1340,6 +1828,11 @@ func (m dashboardModel) renderAssessSummaryTabContent() string {
 	detailBtnActiveStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("33"))
 	repoStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
 
+	if stats := m.renderAssessmentStats(); stats != "" {
+		b.WriteString(stats)
+		b.WriteString("\n\n")
+	}
+
 	if m.assessmentSummary == "" {
 		b.WriteString(dimStyle.Render("  No summary available."))
 		b.WriteString("\n")
@@ -1456,7 +1949,10 @@ func (m dashboardModel) renderAssessProjectsTabContent() string {
 	b.WriteString(fmt.Sprintf("  Total: %d  ", len(results)))
 	b.WriteString(successStyle.Render(fmt.Sprintf("Succeeded: %d  ", succeeded)))
 	if failed > 0 {
-		b.WriteString(failStyle.Render(fmt.Sprintf("Failed: %d", failed)))
+		b.WriteString(failStyle.Render(fmt.Sprintf("Failed: %d  ", failed)))
+	}
+	if len(m.assessments) > 0 {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("Sorted by %s", assessSortLabels[m.assessSort])))
 	}
 	b.WriteString("\n\n")
 
@@ -1504,7 +2000,18 @@ func (m dashboardModel) renderAssessProjectsTabContent() string {
 				}
 			}
 
-			b.WriteString(fmt.Sprintf("%s%s %s%s\n", prefix, repoStyle.Render(fmt.Sprintf("[%s]", repo)), findingPreview, detailsBtn))
+			columns := ""
+			if len(m.assessments) > 0 {
+				columns = m.renderAssessmentColumns(repo) + "  "
+				if a, ok := m.assessments[repo]; ok && a.Evidence != "" {
+					findingPreview = a.Evidence
+					if len(findingPreview) > 120 {
+						findingPreview = findingPreview[:117] + "..."
+					}
+				}
+			}
+
+			b.WriteString(fmt.Sprintf("%s%s%s %s%s%s\n", prefix, m.renderJiraMark(repo), repoStyle.Render(fmt.Sprintf("[%s]", repo)), columns, findingPreview, detailsBtn))
 		} else {
 			b.WriteString(fmt.Sprintf("%s%s Failed ⚠️ %s\n", prefix, repoStyle.Render(fmt.Sprintf("[%s]", repo)), result.Status))
 		}
@@ -1556,6 +2063,8 @@ func (m dashboardModel) renderAssessProjectsTabContent() string {
 		b.WriteString("\n")
 	}
 
+	b.WriteString(m.renderJiraStatus())
+
 	return b.String()
 }
 
@@ -1586,6 +2095,19 @@ func (m *dashboardModel) ensureNotifCursorVisible() {
 	}
 }
 
+// countNotifiedOnGitHub counts the successful repos whose reviewers were
+// asked for a review on GitHub instead of being sent a Slack message.
+func (m dashboardModel) countNotifiedOnGitHub() int {
+	results := m.doneResults()
+	count := 0
+	for _, p := range m.selectedProjects {
+		if result, ok := results[p.Key()]; ok && result.Success && m.cfg.GitHubConfig.NotifiesOnGitHub(p) {
+			count++
+		}
+	}
+	return count
+}
+
 func (m dashboardModel) renderNotifTabContent() string {
 	var b strings.Builder
 
@@ -1600,33 +2122,50 @@ func (m dashboardModel) renderNotifTabContent() string {
 	sendBtnStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15")).Background(lipgloss.Color("206")).Padding(0, 2)
 	sendBtnDimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
 
+	if n := m.countNotifiedOnGitHub(); n > 0 {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  %d repo(s) without a Slack room had reviews requested on GitHub.", n)))
+		b.WriteString("\n\n")
+	}
+
 	if len(m.slackRepos) == 0 {
 		b.WriteString(dimStyle.Render("  No repos with Slack rooms configured."))
 		b.WriteString("\n")
-		b.WriteString(dimStyle.Render("  Set slack_room in your projects config to enable notifications."))
+		b.WriteString(dimStyle.Render("  Set slack_room, or reviewers with github.notify_reviewers, in your projects config to enable notifications."))
 		b.WriteString("\n")
 		return b.String()
 	}
 
 	switch m.notifPhase {
 	case notifPhaseReady:
-		// Token input
-		tokenPrefix := "  "
-		if m.notifFocus == notifFocusToken {
-			tokenPrefix = cursorStyle.Render("▸") + " "
+		if m.slackConnected {
+			connectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("40"))
+			b.WriteString(connectedStyle.Render("  ✓ Slack connected"))
+			b.WriteString(dimStyle.Render(" (" + util.MaskSecret(m.slackToken) + ")"))
+			b.WriteString("\n\n")
+		} else {
+			// Token input (only when no token was resolved upfront)
+			tokenPrefix := "  "
+			if m.notifFocus == notifFocusToken {
+				tokenPrefix = cursorStyle.Render("▸") + " "
+			}
+			b.WriteString(fmt.Sprintf("  %s%s\n", tokenPrefix, labelStyle.Render("Slack Bot Token")))
+			b.WriteString(hintStyle.Render("      Run 'copycat slack connect' once to skip this step"))
+			b.WriteString("\n")
+			b.WriteString(fmt.Sprintf("      %s", m.slackTokenView()))
+			b.WriteString("\n")
+			if m.slackTokenNote != "" {
+				b.WriteString(hintStyle.Render("      " + m.slackTokenNote))
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
 		}
-		b.WriteString(fmt.Sprintf("  %s%s\n", tokenPrefix, labelStyle.Render("Slack Bot Token")))
-		b.WriteString(hintStyle.Render("      Pre-filled from $SLACK_BOT_TOKEN if set"))
-		b.WriteString("\n")
-		b.WriteString(fmt.Sprintf("      %s", m.slackTokenInput.View()))
-		b.WriteString("\n\n")
 
 		// Repo checkboxes
 		repoChannel := make(map[string]string)
 		for _, p := range m.selectedProjects {
 			room := strings.TrimSpace(p.SlackRoom)
 			if room != "" {
-				repoChannel[p.Repo] = room
+				repoChannel[p.Key()] = room
 			}
 		}
 
@@ -1710,6 +2249,26 @@ func (m dashboardModel) renderNotifTabContent() string {
 	return b.String()
 }
 
+// slackTokenView renders the token input with all but the token's last 4
+// characters masked.
+func (m dashboardModel) slackTokenView() string {
+	token := m.slackTokenInput.Value()
+	if token == "" {
+		return m.slackTokenInput.View()
+	}
+	view := util.MaskSecret(token)
+	if m.slackTokenInput.Focused() {
+		view += m.slackTokenInput.Cursor.Style.Render("▏")
+	}
+	return view
+}
+
+// pasteSlackToken reads the Slack token from the clipboard.
+func pasteSlackToken() tea.Msg {
+	token, err := clipboard.ReadAll()
+	return slackTokenPastedMsg{Token: token, Err: err}
+}
+
 func (m dashboardModel) renderDoneHelp() string {
 	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
 	retryStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
@@ -1721,13 +2280,21 @@ func (m dashboardModel) renderDoneHelp() string {
 		switch m.notifPhase {
 		case notifPhaseReady:
 			hints = append(hints, helpStyle.Render("↑↓: navigate"))
+			if m.notifFocus == notifFocusToken {
+				hints = append(hints, helpStyle.Render("ctrl+v: paste token"))
+			}
 			hints = append(hints, helpStyle.Render("space/x: toggle"))
 			hints = append(hints, helpStyle.Render("a: select all"))
 		case notifPhaseSending:
 			hints = append(hints, helpStyle.Render("sending..."))
 		}
 	} else if m.wizardResult != nil && m.wizardResult.Action == "assessment" {
-		if m.activeTab == 0 {
+		if m.activeTab == assessTabChanges {
+			if len(m.assessmentRuns) > 1 {
+				hints = append(hints, helpStyle.Render("←→: earlier/later run"))
+				hints = append(hints, helpStyle.Render("↑↓: scroll"))
+			}
+		} else if m.activeTab == 0 {
 			// Summary tab
 			if m.summaryExpanded {
 				hints = append(hints, helpStyle.Render("↑↓: scroll"))
@@ -1750,9 +2317,24 @@ func (m dashboardModel) renderDoneHelp() string {
 				}
 				hints = append(hints, helpStyle.Render("↑↓: navigate"))
 				hints = append(hints, helpStyle.Render("enter/l: expand"))
+				if len(m.assessments) > 0 {
+					next := (m.assessSort + 1) % assessSort(len(assessSortLabels))
+					hints = append(hints, helpStyle.Render("s: sort by "+assessSortLabels[next]))
+				}
 				if failed > 0 {
 					hints = append(hints, retryStyle.Render(fmt.Sprintf("r: retry %d failed", failed)))
 				}
+				if m.jiraEnabled() && !m.jiraCreating {
+					hints = append(hints, helpStyle.Render("space: pick for Jira"))
+					if m.jiraPerTeam {
+						hints = append(hints, helpStyle.Render("t: per repo"))
+					} else {
+						hints = append(hints, helpStyle.Render("t: per team"))
+					}
+					if picked := len(m.jiraRepos()); picked > 0 {
+						hints = append(hints, retryStyle.Render(fmt.Sprintf("J: create tickets for %d", picked)))
+					}
+				}
 			}
 		}
 	} else {
@@ -1772,6 +2354,7 @@ func (m dashboardModel) renderDoneHelp() string {
 
 		if m.expandedLogRepo != "" {
 			hints = append(hints, helpStyle.Render("↑↓: scroll logs"))
+			hints = append(hints, helpStyle.Render("←→: AI/git/GitHub"))
 			hints = append(hints, helpStyle.Render("enter/esc: close"))
 		} else {
 			hints = append(hints, helpStyle.Render("↑↓: navigate"))
@@ -1791,6 +2374,54 @@ func (m dashboardModel) renderDoneHelp() string {
 }
 
 // aiOutputLines splits AI output into non-empty lines for display.
+// logStreams are the outputs the Results tab's log viewer switches between.
+var logStreams = []string{"AI", "git", "GitHub", "timeline"}
+
+// logStreamOutput returns the output of one of logStreams for a repo.
+func logStreamOutput(result ProjectDoneMsg, stream int) string {
+	switch stream {
+	case 1:
+		return result.GitLog
+	case 2:
+		return result.GitHubLog
+	case 3:
+		return timelineChart(result.Timeline)
+	}
+	return result.AIOutput
+}
+
+// hasLogs reports whether any of a repo's log streams has output.
+func hasLogs(result ProjectDoneMsg) bool {
+	return result.AIOutput != "" || result.GitLog != "" || result.GitHubLog != "" || len(result.Timeline) > 0
+}
+
+// timelineChart shows a repo's phases on one line, then as bars scaled to
+// the longest phase, so the slow step stands out.
+func timelineChart(phases []timeline.Phase) string {
+	if len(phases) == 0 {
+		return ""
+	}
+	const barWidth = 30
+	var longest time.Duration
+	nameWidth := len("total")
+	for _, p := range phases {
+		longest = max(longest, p.Duration)
+		nameWidth = max(nameWidth, len(p.Name))
+	}
+
+	var b strings.Builder
+	b.WriteString(timeline.Format(phases) + "\n")
+	for _, p := range phases {
+		bar := 1
+		if longest > 0 {
+			bar = max(1, int(int64(barWidth)*int64(p.Duration)/int64(longest)))
+		}
+		fmt.Fprintf(&b, "%-*s  %8s  %s\n", nameWidth, p.Name, timeline.FormatDuration(p.Duration), strings.Repeat("█", bar))
+	}
+	fmt.Fprintf(&b, "%-*s  %8s\n", nameWidth, "total", timeline.FormatDuration(timeline.Total(phases)))
+	return b.String()
+}
+
 func aiOutputLines(output string) []string {
 	if output == "" {
 		return nil
@@ -1829,11 +2460,15 @@ func RunDashboard(cfg DashboardConfig) (*DashboardResult, error) {
 		return nil, err
 	}
 
-	m := finalModel.(dashboardModel)
+	return finalModel.(dashboardModel).result(), nil
+}
 
+// result is what the dashboard hands back when it exits, nil if the user quit
+// before starting a run.
+func (m dashboardModel) result() *DashboardResult {
 	// No wizard result means user quit early
 	if m.wizardResult == nil {
-		return nil, nil
+		return nil
 	}
 
 	results := m.processResults
@@ -1849,5 +2484,7 @@ func RunDashboard(cfg DashboardConfig) (*DashboardResult, error) {
 		Interrupted:        m.interrupted,
 		AssessmentSummary:  m.assessmentSummary,
 		AssessmentFindings: m.assessmentFindings,
-	}, nil
+		HandoffPath:        m.handoffPath,
+		Campaign:           m.campaign,
+	}
 }
@@ -0,0 +1,66 @@
+package input
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// updateDiscovery handles keys while editing the discovery filter, which
+// refetches the repos with the new filter for the rest of the session.
+func (m projectSelectorModel) updateDiscovery(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitted = true
+		return m, tea.Quit
+	case "esc":
+		m.discoveryMode = false
+	case "enter":
+		filter, err := config.ParseDiscoveryFilter(m.discoveryQuery)
+		if err != nil {
+			m.discoveryError = err.Error()
+			return m, nil
+		}
+		m.discoveryMode = false
+		return m, func() tea.Msg { return projectsRefreshMsg{Discovery: &filter} }
+	case "backspace":
+		if runes := []rune(m.discoveryQuery); len(runes) > 0 {
+			m.discoveryQuery = string(runes[:len(runes)-1])
+		}
+		m.discoveryError = ""
+	default:
+		if msg.Type == tea.KeyRunes || msg.String() == " " {
+			m.discoveryQuery += msg.String()
+			m.discoveryError = ""
+		}
+	}
+	return m, nil
+}
+
+func (m projectSelectorModel) renderDiscoveryPrompt() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("206"))
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	inputStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("255")).
+		Background(lipgloss.Color("206")).
+		Padding(0, 1)
+
+	b.WriteString(titleStyle.Render("Discovery Filter"))
+	b.WriteString("\n")
+	b.WriteString(inputStyle.Render("> " + m.discoveryQuery + "█"))
+	b.WriteString("\n")
+	if m.discoveryError != "" {
+		b.WriteString(errorStyle.Render(m.discoveryError))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("e.g. language:java,kotlin age:<1y copycat AND NOT deprecated\nlanguage: primary languages • age:<N / age:>N: last push within / before, e.g. 90d, 2w, 1y • topics: AND, OR, NOT, ( )"))
+	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("enter: refetch repos with this filter for this session • esc: cancel"))
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/handoff"
+)
+
+// ResumedRun is a paused run imported from a handoff bundle. The dashboard
+// starts processing it straight away, skipping project selection and the wizard.
+type ResumedRun struct {
+	Projects []config.Project
+	Wizard   WizardResult
+	Bundle   handoff.Bundle
+}
+
+// NewResumedRun resolves a bundle against the importer's projects and AI
+// tools. It returns the pending keys that match no project, which are left out.
+func NewResumedRun(bundle handoff.Bundle, projects []config.Project, tools *config.AIToolsConfig) (*ResumedRun, []string, error) {
+	selected, missing := handoff.Select(projects, bundle.Pending)
+	if len(selected) == 0 {
+		return nil, missing, errors.New("none of the bundle's pending repos are in projects.yaml")
+	}
+
+	w := bundle.Wizard
+	result := WizardResult{
+		Action:                  w.Action,
+		IgnoreAgentInstructions: w.IgnoreAgentInstructions,
+		TempChanges:             w.TempChanges,
+		BranchStrategy:          w.BranchStrategy,
+		BranchName:              w.BranchName,
+		UpdatePR:                w.UpdatePR,
+		PRTitle:                 w.PRTitle,
+		AutoMerge:               w.AutoMerge,
+		PRLabels:                w.PRLabels,
+		PRMilestone:             w.PRMilestone,
+		Prompt:                  w.Prompt,
+		LicenseHeader:           w.LicenseHeader,
+		Actions:                 w.Actions,
+		TriageAlerts:            w.TriageAlerts,
+		VerifyFailure:           w.VerifyFailure,
+		StructuredAssessment:    w.StructuredAssessment,
+		CampaignID:              w.CampaignID,
+		FlakyTests:              w.FlakyTests,
+	}
+	if w.Tool != "" {
+		tool, ok := tools.ToolByName(w.Tool)
+		if !ok {
+			return nil, missing, fmt.Errorf("the run uses AI tool %q, which is not configured here", w.Tool)
+		}
+		result.AITool = tool
+	}
+	return &ResumedRun{Projects: selected, Wizard: result, Bundle: bundle}, missing, nil
+}
+
+// resumeRunMsg starts processing an imported run.
+type resumeRunMsg struct{}
+
+// exportRunMsg asks to export the paused run to a handoff bundle.
+type exportRunMsg struct{}
+
+// handoffBundle captures the paused run: the wizard answers with the prompt as
+// last edited, the repos finished so far (including earlier shifts') and the
+// repos still to process.
+func (m dashboardModel) handoffBundle() handoff.Bundle {
+	r := m.wizardResult
+	bundle := handoff.Bundle{
+		ExportedAt:   time.Now(),
+		ExportedBy:   os.Getenv("USER"),
+		Organization: m.cfg.GitHubConfig.Organization,
+		Wizard: handoff.Wizard{
+			Action:                  r.Action,
+			IgnoreAgentInstructions: r.IgnoreAgentInstructions,
+			TempChanges:             r.TempChanges,
+			BranchStrategy:          r.BranchStrategy,
+			BranchName:              r.BranchName,
+			UpdatePR:                r.UpdatePR,
+			PRTitle:                 r.PRTitle,
+			AutoMerge:               r.AutoMerge,
+			PRLabels:                r.PRLabels,
+			PRMilestone:             r.PRMilestone,
+			Prompt:                  m.progress.prompt,
+			LicenseHeader:           r.LicenseHeader,
+			Actions:                 r.Actions,
+			TriageAlerts:            r.TriageAlerts,
+			VerifyFailure:           r.VerifyFailure,
+			StructuredAssessment:    r.StructuredAssessment,
+			FlakyTests:              r.FlakyTests,
+			CampaignID:              r.CampaignID,
+		},
+	}
+	if r.AITool != nil {
+		bundle.Wizard.Tool = r.AITool.Name
+	}
+	if resumed := m.cfg.Resume; resumed != nil {
+		bundle.Completed = append(bundle.Completed, resumed.Bundle.Completed...)
+		bundle.SpentUSD = resumed.Bundle.SpentUSD
+	}
+	for _, repo := range m.progress.repos {
+		result, ok := m.progress.results[repo]
+		if !ok {
+			bundle.Pending = append(bundle.Pending, repo)
+			continue
+		}
+		bundle.Completed = append(bundle.Completed, handoff.Repo{Key: repo, Status: result.Status, PRURL: result.PRURL})
+		bundle.SpentUSD += result.Usage.CostUSD
+	}
+	return bundle
+}
+
+// exportRun saves the paused run for another operator and ends it here, as
+// if interrupted, so the pending repos are not processed twice.
+func (m dashboardModel) exportRun() (tea.Model, tea.Cmd) {
+	path, err := m.cfg.ExportRun(m.handoffBundle())
+	if err != nil {
+		m.progress.exportErr = err.Error()
+		return m, nil
+	}
+	m.handoffPath = path
+	m.interrupted = true
+	m = m.cleanupPermissionServer()
+	m.phase = phaseDone
+	m = m.initDoneScreen()
+	return m, nil
+}
@@ -0,0 +1,142 @@
+package input
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Headless runs the dashboard without a terminal, for tests that walk through
+// a whole run. Keys go straight to the Update loop; the commands it returns run
+// in the background and their messages are handled while waiting in WaitFor.
+// Commands that need a terminal, such as opening the editor, do nothing.
+type Headless struct {
+	model dashboardModel
+	msgs  chan tea.Msg
+	quit  bool
+}
+
+// NewHeadless starts a dashboard of the given size.
+func NewHeadless(cfg DashboardConfig, width, height int) *Headless {
+	h := &Headless{model: newDashboardModel(cfg), msgs: make(chan tea.Msg, 256)}
+	h.update(tea.WindowSizeMsg{Width: width, Height: height})
+	h.run(h.model.Init())
+	return h
+}
+
+// headlessKeys are the key names Press understands besides single runes.
+var headlessKeys = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"backspace": tea.KeyBackspace,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	" ":         tea.KeySpace,
+	"ctrl+c":    tea.KeyCtrlC,
+	"ctrl+e":    tea.KeyCtrlE,
+	"ctrl+l":    tea.KeyCtrlL,
+	"ctrl+r":    tea.KeyCtrlR,
+	"ctrl+s":    tea.KeyCtrlS,
+	"ctrl+t":    tea.KeyCtrlT,
+}
+
+// Press sends each key, named as bubbletea prints them ("enter", "ctrl+s",
+// "down"), or as the runes typed.
+func (h *Headless) Press(keys ...string) {
+	for _, key := range keys {
+		h.update(keyMsg(key))
+	}
+}
+
+// keyMsg is the message a terminal sends for the named key.
+func keyMsg(key string) tea.KeyMsg {
+	t, ok := headlessKeys[key]
+	if !ok {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	msg := tea.KeyMsg{Type: t}
+	if t == tea.KeySpace {
+		msg.Runes = []rune{' '} // as terminals report it, so text inputs get the space
+	}
+	return msg
+}
+
+// Type sends text one key at a time, as if typed.
+func (h *Headless) Type(text string) {
+	for _, r := range text {
+		h.Press(string(r))
+	}
+}
+
+// Send delivers a message as if a command had returned it.
+func (h *Headless) Send(msg tea.Msg) {
+	h.update(msg)
+}
+
+// WaitFor handles background messages until done reports true for the
+// rendered view, failing once timeout passes or the dashboard quits.
+func (h *Headless) WaitFor(timeout time.Duration, done func(view string) bool) error {
+	deadline := time.After(timeout)
+	for !done(h.View()) {
+		if h.quit {
+			return fmt.Errorf("dashboard quit, last view:\n%s", h.View())
+		}
+		select {
+		case msg := <-h.msgs:
+			h.update(msg)
+		case <-deadline:
+			return fmt.Errorf("timed out after %s, last view:\n%s", timeout, h.View())
+		}
+	}
+	return nil
+}
+
+// View renders the dashboard as the terminal would show it.
+func (h *Headless) View() string {
+	return h.model.View()
+}
+
+// Quit reports whether the dashboard asked to exit.
+func (h *Headless) Quit() bool {
+	return h.quit
+}
+
+// Result is what RunDashboard would return if the dashboard exited now.
+func (h *Headless) Result() *DashboardResult {
+	return h.model.result()
+}
+
+func (h *Headless) update(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case tea.QuitMsg:
+		h.quit = true
+		return
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			h.run(cmd)
+		}
+		return
+	}
+	model, cmd := h.model.Update(msg)
+	h.model = model.(dashboardModel)
+	h.run(cmd)
+}
+
+// run runs cmd in the background, queueing its message.
+func (h *Headless) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		if msg := cmd(); msg != nil {
+			h.msgs <- msg
+		}
+	}()
+}
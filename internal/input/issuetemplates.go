@@ -0,0 +1,192 @@
+package input
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/saltpay/copycat/v2/internal/i18n"
+	"github.com/saltpay/copycat/v2/internal/issuetemplate"
+)
+
+// issueTemplatesMsg carries the issue templates read from the first selected repo.
+type issueTemplatesMsg struct {
+	Templates []issuetemplate.Template
+	Err       error
+}
+
+// withIssueTemplates reads the first selected repo's issue templates with
+// fetch once issues are picked as the action, to offer before the configured ones.
+func (m wizardModel) withIssueTemplates(fetch func() ([]issuetemplate.Template, error)) wizardModel {
+	m.fetchIssueTemplates = fetch
+	return m
+}
+
+// loadIssueTemplates reads the repo's issue templates, if they can be.
+func (m wizardModel) loadIssueTemplates() tea.Cmd {
+	if m.fetchIssueTemplates == nil {
+		return nil
+	}
+	fetch := m.fetchIssueTemplates
+	return func() tea.Msg {
+		templates, err := fetch()
+		return issueTemplatesMsg{Templates: templates, Err: err}
+	}
+}
+
+// openIssueTemplatePicker shows the issue templates, if there are any.
+func (m wizardModel) openIssueTemplatePicker() wizardModel {
+	if len(m.issueTemplates) > 0 {
+		m.pickingIssueTemplate = true
+		m.issueTemplateCursor = 0
+	}
+	return m
+}
+
+// updateIssueTemplate handles keys while picking an issue template and
+// filling in its fields.
+func (m wizardModel) updateIssueTemplate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+
+	if m.issueTemplate != nil {
+		if ok {
+			switch keyMsg.Type {
+			case tea.KeyEsc:
+				m.issueTemplate = nil
+				m.pickingIssueTemplate = false
+				return m, nil
+			case tea.KeyEnter:
+				fields := m.issueTemplate.Asked()
+				value, err := fields[len(m.issueFieldValues)].Check(m.templateVarInput.Value())
+				if err != nil {
+					m.issueFieldError = err.Error()
+					return m, nil
+				}
+				m.issueFieldError = ""
+				m.issueFieldValues = append(m.issueFieldValues, value)
+				if len(m.issueFieldValues) < len(fields) {
+					return m.askIssueField()
+				}
+				return m.applyIssueTemplate()
+			}
+		}
+		var cmd tea.Cmd
+		m.templateVarInput, cmd = m.templateVarInput.Update(msg)
+		return m, cmd
+	}
+
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "esc":
+		m.pickingIssueTemplate = false
+	case "up", "k":
+		if m.issueTemplateCursor > 0 {
+			m.issueTemplateCursor--
+		}
+	case "down", "j":
+		if m.issueTemplateCursor < len(m.issueTemplates)-1 {
+			m.issueTemplateCursor++
+		}
+	case "enter":
+		tmpl := m.issueTemplates[m.issueTemplateCursor]
+		m.issueTemplate = &tmpl
+		m.issueFieldValues = nil
+		m.issueFieldError = ""
+		if len(tmpl.Asked()) == 0 {
+			return m.applyIssueTemplate()
+		}
+		return m.askIssueField()
+	}
+	return m, nil
+}
+
+// askIssueField starts the input for the next field of the issue form.
+func (m wizardModel) askIssueField() (tea.Model, tea.Cmd) {
+	field := m.issueTemplate.Asked()[len(m.issueFieldValues)]
+	m.templateVarInput = textinput.New()
+	m.templateVarInput.Width = 60
+	m.templateVarInput.Placeholder = field.Placeholder
+	m.templateVarInput.SetValue(field.Value)
+	m.templateVarInput.Focus()
+	return m, textinput.Blink
+}
+
+// applyIssueTemplate starts the issue title, labels, assignees and
+// description from the chosen template so they can be reviewed and edited.
+func (m wizardModel) applyIssueTemplate() (tea.Model, tea.Cmd) {
+	tmpl := *m.issueTemplate
+	m.issueTemplate = nil
+	m.pickingIssueTemplate = false
+
+	m.promptInput.SetValue(tmpl.Render(m.issueFieldValues))
+	if tmpl.Title != "" && !strings.HasPrefix(m.prTitleInput.Value(), tmpl.Title) {
+		m.prTitleInput.SetValue(tmpl.Title + m.prTitleInput.Value())
+		m.prTitleInput.CursorEnd()
+	}
+	m.prLabelsInput.SetValue(strings.Join(mergeLists(splitLabels(m.prLabelsInput.Value()), tmpl.Labels), ", "))
+	m.assigneesInput.SetValue(strings.Join(mergeLists(splitLabels(m.assigneesInput.Value()), tmpl.Assignees), ", "))
+	if m.currentStep == stepPrompt {
+		m.promptInput.Focus()
+	}
+	return m, nil
+}
+
+// mergeLists appends the items of extra missing from list.
+func mergeLists(list, extra []string) []string {
+	for _, item := range extra {
+		if !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// renderIssueTemplatePicker renders the issue templates or the field being
+// filled in.
+func (m wizardModel) renderIssueTemplatePicker(b *strings.Builder, label, cursor, hint lipgloss.Style) {
+	if m.issueTemplate != nil {
+		fields := m.issueTemplate.Asked()
+		field := fields[len(m.issueFieldValues)]
+		b.WriteString(label.Render(fmt.Sprintf("  %s: %s (%d/%d)", m.issueTemplate.Name, field.Label, len(m.issueFieldValues)+1, len(fields))))
+		b.WriteString("\n")
+		if field.Description != "" {
+			b.WriteString(hint.Render("    " + field.Description))
+			b.WriteString("\n")
+		}
+		for i, option := range field.Options {
+			b.WriteString(fmt.Sprintf("    %d. %s\n", i+1, option))
+		}
+		b.WriteString(indentLines(m.templateVarInput.View(), "    "))
+		b.WriteString("\n")
+		if m.issueFieldError != "" {
+			b.WriteString(cursor.Render("    " + m.issueFieldError))
+			b.WriteString("\n")
+		}
+		return
+	}
+
+	b.WriteString(label.Render(i18n.T("  Issue Templates")))
+	b.WriteString("\n")
+	for i, tmpl := range m.issueTemplates {
+		line := tmpl.Name
+		if tmpl.About != "" {
+			line += " — " + tmpl.About
+		}
+		if tmpl.Source != "" {
+			line += " (" + tmpl.Source + ")"
+		}
+		if i == m.issueTemplateCursor {
+			b.WriteString(cursor.Render("    > " + line))
+		} else {
+			b.WriteString("      " + line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(hint.Render(i18n.T("    Fills in the issue title, labels, assignees and description so you can edit them")))
+	b.WriteString("\n")
+}
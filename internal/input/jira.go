@@ -0,0 +1,123 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/saltpay/copycat/v2/internal/ai"
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// jiraTicketsDoneMsg carries the outcome of creating follow-up Jira tickets.
+type jiraTicketsDoneMsg struct {
+	Results []string
+}
+
+// initJiraSelection picks the assessed repos found non-compliant or partially
+// compliant for follow-up tickets.
+func (m dashboardModel) initJiraSelection() dashboardModel {
+	m.jiraSelected = make(map[string]bool)
+	m.jiraPerTeam = false
+	m.jiraCreating = false
+	m.jiraResults = nil
+	for repo, a := range m.assessments {
+		if a.Status == ai.StatusNonCompliant || a.Status == ai.StatusPartial {
+			m.jiraSelected[repo] = true
+		}
+	}
+	return m
+}
+
+// jiraEnabled reports whether follow-up tickets can be created from the
+// assessment Projects tab.
+func (m dashboardModel) jiraEnabled() bool {
+	return m.cfg.CreateJiraTickets != nil && m.wizardResult != nil && m.wizardResult.Action == "assessment"
+}
+
+// jiraRepos returns the successfully assessed repos picked for tickets, in
+// run order.
+func (m dashboardModel) jiraRepos() []config.Project {
+	results := m.doneResults()
+	var projects []config.Project
+	for _, p := range m.selectedProjects {
+		if result, ok := results[p.Key()]; ok && result.Success && m.jiraSelected[p.Key()] {
+			projects = append(projects, p)
+		}
+	}
+	return projects
+}
+
+// updateJiraKeys handles the follow-up ticket keys on the assessment
+// Projects tab, reporting whether the key was one of them.
+func (m dashboardModel) updateJiraKeys(keyMsg tea.KeyMsg) (dashboardModel, tea.Cmd, bool) {
+	if !m.jiraEnabled() || m.jiraCreating {
+		return m, nil, false
+	}
+	switch keyMsg.String() {
+	case " ", "x":
+		if result, ok := m.doneResults()[m.doneCursorRepo]; ok && result.Success {
+			m.jiraSelected[m.doneCursorRepo] = !m.jiraSelected[m.doneCursorRepo]
+		}
+		return m, nil, true
+	case "t":
+		m.jiraPerTeam = !m.jiraPerTeam
+		return m, nil, true
+	case "J":
+		projects := m.jiraRepos()
+		if len(projects) == 0 {
+			return m, nil, true
+		}
+		m.jiraCreating = true
+		m.jiraResults = nil
+		create, ch := m.cfg.CreateJiraTickets, m.statusCh
+		question, findings, perTeam := m.wizardResult.Prompt, m.assessmentFindings, m.jiraPerTeam
+		go func() {
+			var results []string
+			create(projects, question, findings, perTeam, func(line string) {
+				results = append(results, line)
+			})
+			ch <- jiraTicketsDoneMsg{Results: results}
+		}()
+		return m, listenForStatus(ch), true
+	}
+	return m, nil, false
+}
+
+// renderJiraMark renders whether a repo is picked for a follow-up ticket.
+func (m dashboardModel) renderJiraMark(repo string) string {
+	if !m.jiraEnabled() {
+		return ""
+	}
+	if m.jiraSelected[repo] {
+		return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("40")).Render("☑") + " "
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("243")).Render("☐") + " "
+}
+
+// renderJiraStatus renders the follow-up ticket choices and results below
+// the assessed repos.
+func (m dashboardModel) renderJiraStatus() string {
+	if !m.jiraEnabled() {
+		return ""
+	}
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	grouping := "one per repo"
+	if m.jiraPerTeam {
+		grouping = "one per team"
+	}
+	var b strings.Builder
+	b.WriteString("\n")
+	switch {
+	case m.jiraCreating:
+		b.WriteString(dimStyle.Render("  Jira: creating tickets..."))
+	default:
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  Jira: %d repos picked, %s", len(m.jiraRepos()), grouping)))
+	}
+	b.WriteString("\n")
+	for _, line := range m.jiraResults {
+		b.WriteString("  " + line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package input
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type profileMode int
+
+const (
+	profileModeNone profileMode = iota
+	profileModeSave
+	profileModeLoad
+)
+
+// profileNames returns the saved profile names in alphabetical order.
+func (m projectSelectorModel) profileNames() []string {
+	names := make([]string, 0, len(m.profiles))
+	for name := range m.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// updateProfile handles keys while naming a profile to save or picking one to load.
+func (m projectSelectorModel) updateProfile(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitted = true
+		return m, tea.Quit
+	case "esc":
+		m.profileMode = profileModeNone
+		return m, nil
+	}
+
+	if m.profileMode == profileModeSave {
+		switch msg.String() {
+		case "enter":
+			name := strings.TrimSpace(m.profileName)
+			if name == "" {
+				return m, nil
+			}
+			m.profileMode = profileModeNone
+			m.profileNotice = m.saveSelectionAs(name)
+		case "backspace":
+			if runes := []rune(m.profileName); len(runes) > 0 {
+				m.profileName = string(runes[:len(runes)-1])
+			}
+		default:
+			if msg.Type == tea.KeyRunes || msg.String() == " " {
+				m.profileName += msg.String()
+			}
+		}
+		return m, nil
+	}
+
+	names := m.profileNames()
+	switch msg.String() {
+	case "up", "k":
+		if m.profileCursor > 0 {
+			m.profileCursor--
+		}
+	case "down", "j":
+		if m.profileCursor < len(names)-1 {
+			m.profileCursor++
+		}
+	case "enter":
+		m.profileMode = profileModeNone
+		if m.profileCursor < len(names) {
+			m.profileNotice = m.loadProfile(names[m.profileCursor])
+		}
+	}
+	return m, nil
+}
+
+// saveSelectionAs stores the current selection under name and returns a notice for the user.
+func (m *projectSelectorModel) saveSelectionAs(name string) string {
+	var repos []string
+	for _, p := range m.extractSelected() {
+		repos = append(repos, p.Key())
+	}
+	if len(repos) == 0 {
+		return "⚠ Nothing selected — profile not saved"
+	}
+
+	if m.saveProfile != nil {
+		if err := m.saveProfile(name, repos); err != nil {
+			return fmt.Sprintf("⚠ Failed to save profile: %v", err)
+		}
+	}
+	if m.profiles == nil {
+		m.profiles = make(map[string][]string)
+	}
+	m.profiles[name] = repos
+	return fmt.Sprintf("✓ Saved profile %q (%d projects)", name, len(repos))
+}
+
+// loadProfile replaces the selection with the profile's repos and returns a notice for the user.
+func (m *projectSelectorModel) loadProfile(name string) string {
+	index := make(map[string]int, len(m.projects))
+	for i, p := range m.projects {
+		index[p.Key()] = i
+	}
+
+	m.selected = make(map[int]struct{})
+	missing, optedOut := 0, 0
+	for _, repo := range m.profiles[name] {
+		i, ok := index[repo]
+		switch {
+		case !ok:
+			missing++
+		case m.optedOut[i] != "":
+			optedOut++
+		default:
+			m.selected[i] = struct{}{}
+		}
+	}
+
+	notice := fmt.Sprintf("✓ Loaded profile %q (%d projects)", name, len(m.selected))
+	if missing > 0 {
+		notice += fmt.Sprintf(" — %d no longer in the project list", missing)
+	}
+	if optedOut > 0 {
+		notice += fmt.Sprintf(" — %d opted out", optedOut)
+	}
+	return notice
+}
+
+func (m projectSelectorModel) renderProfilePrompt() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("206"))
+	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	if m.profileMode == profileModeSave {
+		b.WriteString(titleStyle.Render(fmt.Sprintf("Save %d selected project(s) as profile", len(m.selected))))
+		b.WriteString("\n")
+		inputStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("255")).
+			Background(lipgloss.Color("206")).
+			Padding(0, 1)
+		b.WriteString(inputStyle.Render("> " + m.profileName + "█"))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("enter: save (overwrites a profile with the same name) • esc: cancel"))
+		return b.String()
+	}
+
+	b.WriteString(titleStyle.Render("Load Profile"))
+	b.WriteString("\n\n")
+	cursorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+	for i, name := range m.profileNames() {
+		line := fmt.Sprintf("%s (%d)", name, len(m.profiles[name]))
+		if i == m.profileCursor {
+			b.WriteString(cursorStyle.Render("> " + line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑/↓: navigate • enter: load (replaces current selection) • esc: cancel"))
+	return b.String()
+}
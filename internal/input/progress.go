@@ -11,7 +11,9 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/saltpay/copycat/v2/internal/ai"
 	"github.com/saltpay/copycat/v2/internal/permission"
+	"github.com/saltpay/copycat/v2/internal/timeline"
 )
 
 const maxVisibleProjects = 10
@@ -57,15 +59,28 @@ type ProjectStatusMsg struct {
 	Status string
 }
 
+// ProjectLogMsg carries one line of a project's live AI output.
+type ProjectLogMsg struct {
+	Repo string
+	Line string
+}
+
 // ProjectDoneMsg signals that a project has finished processing.
 type ProjectDoneMsg struct {
 	Repo     string
 	Status   string
 	Success  bool
 	Skipped  bool
-	PRURL    string
+	PRURL    string // or the issue's URL when creating issues
 	Error    error
 	AIOutput string
+	// GitLog and GitHubLog hold the git commands and GitHub API requests
+	// made for the repo, kept apart from the AI output.
+	GitLog    string
+	GitHubLog string
+	Timeline  []timeline.Phase // how long each phase of the pipeline took
+	Diff      string
+	Usage     ai.Usage // tokens and cost the AI tool reported
 }
 
 // PostStatusMsg carries a post-processing status line (e.g. Slack notifications).
@@ -73,36 +88,84 @@ type PostStatusMsg struct {
 	Line string
 }
 
-// AssessmentResultMsg carries the final assessment summary and per-project findings.
+// RateLimitMsg reports that GitHub API requests are waiting for a nearly
+// used up quota to reset. A zero Reset means they have gone on again.
+type RateLimitMsg struct {
+	Resource  string
+	Remaining int
+	Limit     int
+	Reset     time.Time
+}
+
+// AssessmentResultMsg carries the final assessment summary and per-project
+// findings, plus the structured answers of repos that gave one.
 type AssessmentResultMsg struct {
-	Summary  string
-	Findings map[string]string
+	Summary     string
+	Findings    map[string]string
+	Assessments map[string]ai.Assessment
+}
+
+// PauseMsg pauses processing at the end of a batch for a reason other than
+// the regular checkpoint, such as the budget being reached.
+type PauseMsg struct {
+	Reason string
 }
 
 // StatusSender sends status updates to the progress dashboard.
 type StatusSender struct {
 	send           func(tea.Msg)
 	ResumeCh       chan string
-	MCPConfigPath  string
+	MCPConfigPath  string // MCP config or hook settings, per the tool's permission adapter
 	CancelRegistry *CancelRegistry
 }
 
+// NewHeadlessSender returns a StatusSender for runs without a dashboard. Each
+// project's final status and every post-processing line go to onLine, and the
+// assessment result to onResult. Runs never pause at checkpoints.
+func NewHeadlessSender(onLine func(line string), onResult func(msg AssessmentResultMsg)) *StatusSender {
+	return &StatusSender{
+		send: func(msg tea.Msg) {
+			switch msg := msg.(type) {
+			case ProjectDoneMsg:
+				onLine(fmt.Sprintf("[%s] %s", msg.Repo, msg.Status))
+			case PostStatusMsg:
+				onLine(msg.Line)
+			case RateLimitMsg:
+				if !msg.Reset.IsZero() {
+					onLine(rateLimitLine(msg, time.Until(msg.Reset)))
+				}
+			case AssessmentResultMsg:
+				onResult(msg)
+			}
+		},
+	}
+}
+
 // UpdateStatus updates the status line for a project.
 func (s *StatusSender) UpdateStatus(repo, status string) {
 	s.send(ProjectStatusMsg{Repo: repo, Status: status})
 }
 
+// Log adds a line of live AI output for a project.
+func (s *StatusSender) Log(repo, line string) {
+	s.send(ProjectLogMsg{Repo: repo, Line: line})
+}
+
 // Done signals that a project has finished processing.
-func (s *StatusSender) Done(repo, status string, success, skipped bool, prURL string, err error, aiOutput string) {
-	s.send(ProjectDoneMsg{
-		Repo:     repo,
-		Status:   status,
-		Success:  success,
-		Skipped:  skipped,
-		PRURL:    prURL,
-		Error:    err,
-		AIOutput: aiOutput,
-	})
+func (s *StatusSender) Done(msg ProjectDoneMsg) {
+	s.send(msg)
+}
+
+// OnDone returns a sender like s that also passes each project's result to fn.
+func (s *StatusSender) OnDone(fn func(msg ProjectDoneMsg)) *StatusSender {
+	observed := *s
+	observed.send = func(msg tea.Msg) {
+		if done, ok := msg.(ProjectDoneMsg); ok {
+			fn(done)
+		}
+		s.send(msg)
+	}
+	return &observed
 }
 
 // PostStatus sends a post-processing status line to the progress view.
@@ -110,9 +173,29 @@ func (s *StatusSender) PostStatus(line string) {
 	s.send(PostStatusMsg{Line: line})
 }
 
+// RateLimited tells the progress view that API requests are waiting until
+// reset for resource's quota, or that they have gone on again when reset is
+// zero.
+func (s *StatusSender) RateLimited(resource string, remaining, limit int, reset time.Time) {
+	s.send(RateLimitMsg{Resource: resource, Remaining: remaining, Limit: limit, Reset: reset})
+}
+
 // AssessmentResult sends the final assessment summary and per-project findings.
-func (s *StatusSender) AssessmentResult(summary string, findings map[string]string) {
-	s.send(AssessmentResultMsg{Summary: summary, Findings: findings})
+func (s *StatusSender) AssessmentResult(summary string, findings map[string]string, assessments map[string]ai.Assessment) {
+	s.send(AssessmentResultMsg{Summary: summary, Findings: findings, Assessments: assessments})
+}
+
+// RequestPermission asks the user to approve a tool call, as the permission
+// server does for a running AI tool. The answer is sent on req.ResponseCh,
+// which must be buffered.
+func (s *StatusSender) RequestPermission(req permission.PermissionRequest) {
+	s.send(permission.PermissionRequestMsg{Request: req})
+}
+
+// Pause asks the user to confirm before the next batch, showing reason. The
+// caller then waits on ResumeCh.
+func (s *StatusSender) Pause(reason string) {
+	s.send(PauseMsg{Reason: reason})
 }
 
 // Finish signals that all processing (including post-processing) is done.
@@ -121,18 +204,24 @@ func (s *StatusSender) Finish() {
 }
 
 type progressModel struct {
-	repos     []string
-	statuses  map[string]string
-	results   map[string]ProjectDoneMsg
-	completed int
-	total     int
-	startTime time.Time
-	termWidth int
-	quitted   bool
+	repos      []string
+	statuses   map[string]string
+	results    map[string]ProjectDoneMsg
+	completed  int
+	total      int
+	startTime  time.Time
+	termWidth  int
+	termHeight int
+	quitted    bool
 
 	postLines []string
 
+	rateLimit RateLimitMsg // quota GitHub requests are waiting on, if any
+
 	paused             bool
+	pauseReason        string // why the run paused, when not a regular checkpoint
+	canExport          bool   // whether a paused run can be handed off
+	exportErr          string // why the last handoff export failed
 	pauseEditing       bool
 	pausePromptInput   textinput.Model
 	checkpointInterval int
@@ -146,6 +235,14 @@ type progressModel struct {
 	manualScroll bool
 	scrollOffset int
 
+	// Live AI output, last maxLogLines lines per repo
+	logs        map[string][]string
+	liveLogRepo string // repo whose live log pane is open (empty = none)
+
+	// Answered permission requests per repo, oldest first
+	permissionLog   map[string][]resolvedPermission
+	permissionsRepo string // repo whose permission history pane is open (empty = none)
+
 	// Cancel support
 	cancelRegistry *CancelRegistry
 	cancelled      map[string]bool
@@ -153,12 +250,26 @@ type progressModel struct {
 	// Permission prompting
 	permissionQueue     []permission.PermissionRequest
 	currentPermission   *permission.PermissionRequest
-	permissionChoice    int // 0=approve, 1=deny, 2=approve-all
+	permissionChoice    int // 0=approve, 1=deny, 2=approve-all, 3=approve-all in repo, 4=always allow, 5=always deny
 	approvedPatterns    map[string]bool
-	permissionCmdScroll int // scroll offset for the command box
+	repoPatterns        map[repoPattern]bool // approved for one repo only
+	rules               permission.Policy    // configured decisions, see withPermissionPolicy
+	policy              permission.Policy    // saved decisions
+	policyPath          string
+	policyErr           string          // why the last decision couldn't be saved
+	remote              RemoteApprover  // also asks for permission elsewhere, see withRemoteApprover
+	remoteAsked         map[string]bool // requests asked remotely and not yet answered
+	permissionCmdScroll int             // scroll offset for the command box
 
 	// Question prompting (AskUserQuestion)
-	questionOptionIdx int // currently highlighted option index
+	questionOptionIdx int // currently highlighted option index; one past the options is "type an answer"
+	questionTyping    bool
+	questionInput     textinput.Model
+
+	// Review gate decisions (changes rejected by the review tool)
+	reviewQueue   []ReviewDecisionMsg
+	currentReview *ReviewDecisionMsg
+	reviewScroll  int
 
 	// Context from wizard (displayed as header)
 	branchName     string
@@ -167,6 +278,23 @@ type progressModel struct {
 	originalPrompt string
 	promptExpanded bool
 	cursorOnPrompt bool
+	issues         bool // the title and prompt are an issue's
+}
+
+// titleLabel labels the PR title, or the issue title when creating issues.
+func (m progressModel) titleLabel() string {
+	if m.issues {
+		return "Issue: "
+	}
+	return "PR: "
+}
+
+// promptLabel labels the prompt, or the issue description when creating issues.
+func (m progressModel) promptLabel() string {
+	if m.issues {
+		return "Description"
+	}
+	return "Prompt"
 }
 
 // NewProgressModel creates a new progress model for tracking repository processing.
@@ -191,6 +319,9 @@ func NewProgressModel(repos []string, checkpointInterval int, branchName, prTitl
 		cursorRepo:         cursorRepo,
 		cancelled:          make(map[string]bool),
 		approvedPatterns:   make(map[string]bool),
+		repoPatterns:       make(map[repoPattern]bool),
+		logs:               make(map[string][]string),
+		permissionLog:      make(map[string][]resolvedPermission),
 		branchName:         branchName,
 		prTitle:            prTitle,
 		prompt:             prompt,
@@ -214,6 +345,7 @@ func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.termWidth = msg.Width
+		m.termHeight = msg.Height
 	case ProjectStatusMsg:
 		m.statuses[msg.Repo] = msg.Status
 	case ProjectDoneMsg:
@@ -223,10 +355,33 @@ func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.checkpointInterval > 0 && m.completed < m.total && m.completed >= m.nextCheckpoint {
 			m.paused = true
 		}
+	case PauseMsg:
+		m.paused = true
+		m.pauseReason = msg.Reason
+	case ProjectLogMsg:
+		lines := append(m.logs[msg.Repo], msg.Line)
+		if len(lines) > maxLogLines {
+			lines = lines[len(lines)-maxLogLines:]
+		}
+		m.logs[msg.Repo] = lines
 	case PostStatusMsg:
 		m.postLines = append(m.postLines, msg.Line)
+	case RateLimitMsg:
+		m.rateLimit = msg
 	case permission.PermissionRequestMsg:
 		return m.handlePermissionRequest(msg.Request)
+	case permission.RemoteAnswerMsg:
+		return m.handleRemoteAnswer(msg)
+	case permission.PolicyDecisionMsg:
+		d := msg.Decision
+		how := "policy, " + d.Reason
+		if d.Rule != "" {
+			how = fmt.Sprintf("policy rule %q", d.Rule)
+		}
+		m.record(d.Repo, resolvedPermission{At: d.Time, Tool: d.ToolName, Command: d.Command, Approved: d.Approved, How: how})
+		return m.Update(ProjectLogMsg{Repo: d.Repo, Line: policyDecisionLine(d)})
+	case ReviewDecisionMsg:
+		return m.handleReviewDecision(msg)
 	case tickMsg:
 		m.tickCount++
 		return m, m.tickCmd()
@@ -235,6 +390,9 @@ func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.currentPermission != nil {
 			return m.handlePermissionKey(msg)
 		}
+		if m.currentReview != nil {
+			return m.handleReviewKey(msg)
+		}
 		if m.paused {
 			if m.pauseEditing {
 				switch msg.Type {
@@ -264,12 +422,18 @@ func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.pausePromptInput.Width = 60
 				m.pausePromptInput.Focus()
 				return m, textinput.Blink
+			case "s":
+				if m.canExport {
+					return m, func() tea.Msg { return exportRunMsg{} }
+				}
 			case "enter":
 				newPrompt := ""
 				if m.prompt != m.originalPrompt {
 					newPrompt = m.prompt
 				}
 				m.paused = false
+				m.pauseReason = ""
+				m.exportErr = ""
 				m.nextCheckpoint += m.checkpointInterval
 				return m, func() tea.Msg { return resumeProcessingMsg{NewPrompt: newPrompt} }
 			}
@@ -279,9 +443,23 @@ func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c":
 			m.quitted = true
 			return m, tea.Quit
-		case "enter":
+		case "enter", "l":
 			if m.cursorOnPrompt && m.prompt != "" {
 				m.promptExpanded = !m.promptExpanded
+			} else if !m.cursorOnPrompt && m.cursorRepo != "" {
+				if m.liveLogRepo == m.cursorRepo {
+					m.liveLogRepo = ""
+				} else {
+					m.liveLogRepo = m.cursorRepo
+				}
+			}
+		case "p":
+			if !m.cursorOnPrompt && len(m.permissionLog[m.cursorRepo]) > 0 {
+				if m.permissionsRepo == m.cursorRepo {
+					m.permissionsRepo = ""
+				} else {
+					m.permissionsRepo = m.cursorRepo
+				}
 			}
 		case "up", "k":
 			m.moveCursor(-1)
@@ -364,34 +542,191 @@ func (m *progressModel) moveCursor(delta int) {
 	// Adjust scroll offset to keep cursor visible
 	if newIdx < m.scrollOffset {
 		m.scrollOffset = newIdx
-	} else if newIdx >= m.scrollOffset+maxVisibleProjects {
-		m.scrollOffset = newIdx - maxVisibleProjects + 1
+	} else if newIdx >= m.scrollOffset+m.maxVisible() {
+		m.scrollOffset = newIdx - m.maxVisible() + 1
 	}
 }
 
-func (m progressModel) handlePermissionRequest(req permission.PermissionRequest) (tea.Model, tea.Cmd) {
-	// Questions skip auto-approve patterns
-	if !req.IsQuestion {
-		pattern := extractPattern(req.Command)
-		if m.approvedPatterns[pattern] {
-			req.ResponseCh <- permission.PermissionResponse{Approved: true}
-			return m, nil
+// withPermissionPolicy answers the permission requests the configured rules
+// or the saved policy have rules for, and saves the decisions made with A
+// and D to the saved policy at path.
+func (m progressModel) withPermissionPolicy(rules, saved permission.Policy, path string) progressModel {
+	m.rules = rules
+	m.policy = saved
+	m.policyPath = path
+	return m
+}
+
+// withRemoteApprover also asks each permission request with remote, so it
+// can be answered there or here, whichever comes first.
+func (m progressModel) withRemoteApprover(remote RemoteApprover) progressModel {
+	m.remote = remote
+	m.remoteAsked = make(map[string]bool)
+	return m
+}
+
+// askRemotely asks req with the remote approver, if there is one.
+func (m progressModel) askRemotely(req permission.PermissionRequest) tea.Cmd {
+	if m.remote == nil || req.IsQuestion {
+		return nil
+	}
+	m.remoteAsked[req.ID] = true
+	remote := m.remote
+	return func() tea.Msg {
+		if err := remote.Ask(req.ID, req.Repo, req.ToolName, req.Command); err != nil {
+			return ProjectLogMsg{Repo: req.Repo, Line: fmt.Sprintf("⚠ Failed to ask for permission remotely: %v", err)}
+		}
+		return nil
+	}
+}
+
+// settle takes a request answered here off the remote approver.
+func (m progressModel) settle(id string) {
+	if !m.remoteAsked[id] {
+		return
+	}
+	delete(m.remoteAsked, id)
+	go m.remote.Withdraw(id, "☑️ Answered in copycat")
+}
+
+// handleRemoteAnswer answers the request a remote answer is for, whether it
+// is being shown or waiting its turn.
+func (m progressModel) handleRemoteAnswer(msg permission.RemoteAnswerMsg) (tea.Model, tea.Cmd) {
+	if !m.remoteAsked[msg.ID] {
+		return m, nil
+	}
+	delete(m.remoteAsked, msg.ID)
+	resp := permission.PermissionResponse{Approved: msg.Approved}
+	how := "remotely"
+	if msg.By != "" {
+		how = msg.By + ", remotely"
+	}
+	if m.currentPermission != nil && m.currentPermission.ID == msg.ID {
+		m.resolve(*m.currentPermission, resp, how)
+		return m.advancePermissionQueue(), nil
+	}
+	for i, req := range m.permissionQueue {
+		if req.ID == msg.ID {
+			m.resolve(req, resp, how)
+			m.permissionQueue = append(m.permissionQueue[:i:i], m.permissionQueue[i+1:]...)
+			break
+		}
+	}
+	return m, nil
+}
+
+// resolvedPermission is an answered permission request, as listed in its
+// repo's permission history.
+type resolvedPermission struct {
+	At       time.Time
+	Tool     string
+	Command  string
+	Approved bool
+	Answer   string // to a question
+	How      string // who or what answered it, e.g. you or the pattern approved
+}
+
+// maxPermissionLog is how many answered requests are kept per repo.
+const maxPermissionLog = 200
+
+// resolve answers req with resp and adds it to its repo's permission history.
+func (m progressModel) resolve(req permission.PermissionRequest, resp permission.PermissionResponse, how string) {
+	req.ResponseCh <- resp
+	command := req.Command
+	if req.IsQuestion && len(req.Questions) > 0 {
+		command = req.Questions[0].Text
+	}
+	m.record(req.Repo, resolvedPermission{At: time.Now(), Tool: req.ToolName, Command: command, Approved: resp.Approved, Answer: resp.Answer, How: how})
+}
+
+// record adds an answered request to repo's permission history.
+func (m progressModel) record(repo string, r resolvedPermission) {
+	log := append(m.permissionLog[repo], r)
+	if len(log) > maxPermissionLog {
+		log = log[len(log)-maxPermissionLog:]
+	}
+	m.permissionLog[repo] = log
+}
+
+// repoPattern is a command pattern approved in one repo.
+type repoPattern struct {
+	repo, pattern string
+}
+
+// autoAnswer answers req without asking when it matches a pattern approved
+// this run, in every repo or in its own, or a saved decision, and reports
+// whether it did. Questions are always asked.
+func (m progressModel) autoAnswer(req permission.PermissionRequest) bool {
+	if req.IsQuestion {
+		return false
+	}
+	pattern := extractPattern(req.Command)
+	if m.approvedPatterns[pattern] || m.repoPatterns[repoPattern{req.Repo, pattern}] {
+		m.resolve(req, permission.PermissionResponse{Approved: true}, fmt.Sprintf("pattern %q", pattern))
+		m.settle(req.ID)
+		return true
+	}
+	if rule, approved, decided := m.rules.With(m.policy).Match(req.ToolName, req.Repo, req.Command); decided {
+		m.resolve(req, permission.PermissionResponse{Approved: approved}, fmt.Sprintf("rule %q", rule.Command))
+		m.settle(req.ID)
+		return true
+	}
+	return false
+}
+
+// saveDecision answers the current request and saves the decision for every
+// command its suggested rule matches, in any repo. A request without a
+// command is only answered, since no rule would cover just it.
+func (m progressModel) saveDecision(allow bool) progressModel {
+	rule, ok := permission.SuggestRule(m.currentPermission.ToolName, m.currentPermission.Command)
+	if !ok {
+		m.policyErr = "Not saved: the request has no command to save a rule for"
+		m.resolve(*m.currentPermission, permission.PermissionResponse{Approved: allow}, "you")
+		return m.advancePermissionQueue()
+	}
+	m.policy.Add(rule, allow)
+	m.policyErr = ""
+	if m.policyPath != "" {
+		if err := m.policy.Save(m.policyPath); err != nil {
+			m.policyErr = fmt.Sprintf("Failed to save the decision: %v", err)
 		}
 	}
+	m.resolve(*m.currentPermission, permission.PermissionResponse{Approved: allow}, "you, saved")
+	return m.drainAutoApproved().advancePermissionQueue()
+}
+
+// approvePattern approves the current request and the rest of this run's
+// requests matching its pattern, in every repo or only in its own.
+func (m progressModel) approvePattern(thisRepo bool) progressModel {
+	pattern := extractPattern(m.currentPermission.Command)
+	if thisRepo {
+		m.repoPatterns[repoPattern{m.currentPermission.Repo, pattern}] = true
+	} else {
+		m.approvedPatterns[pattern] = true
+	}
+	m.resolve(*m.currentPermission, permission.PermissionResponse{Approved: true}, "you")
+	// Auto-approve any queued requests matching this pattern
+	return m.drainAutoApproved().advancePermissionQueue()
+}
+
+func (m progressModel) handlePermissionRequest(req permission.PermissionRequest) (tea.Model, tea.Cmd) {
+	if m.autoAnswer(req) {
+		return m, nil
+	}
 
 	// Enqueue or show immediately
 	if m.currentPermission == nil {
 		m.currentPermission = &req
 		m.permissionCmdScroll = 0
 		if req.IsQuestion {
-			m.questionOptionIdx = 0
+			m = m.showQuestion()
 		} else {
 			m.permissionChoice = 0
 		}
 	} else {
 		m.permissionQueue = append(m.permissionQueue, req)
 	}
-	return m, nil
+	return m, m.askRemotely(req)
 }
 
 func (m progressModel) handlePermissionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -401,18 +736,19 @@ func (m progressModel) handlePermissionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 
 	switch msg.String() {
 	case "y":
-		m.currentPermission.ResponseCh <- permission.PermissionResponse{Approved: true}
+		m.resolve(*m.currentPermission, permission.PermissionResponse{Approved: true}, "you")
 		return m.advancePermissionQueue(), nil
 	case "n":
-		m.currentPermission.ResponseCh <- permission.PermissionResponse{Approved: false}
+		m.resolve(*m.currentPermission, permission.PermissionResponse{Approved: false}, "you")
 		return m.advancePermissionQueue(), nil
 	case "a":
-		pattern := extractPattern(m.currentPermission.Command)
-		m.approvedPatterns[pattern] = true
-		m.currentPermission.ResponseCh <- permission.PermissionResponse{Approved: true}
-		// Auto-approve any queued requests matching this pattern
-		m = m.drainAutoApproved()
-		return m.advancePermissionQueue(), nil
+		return m.approvePattern(false), nil
+	case "r":
+		return m.approvePattern(true), nil
+	case "A":
+		return m.saveDecision(true), nil
+	case "D":
+		return m.saveDecision(false), nil
 	case "up", "k":
 		if m.permissionCmdScroll > 0 {
 			m.permissionCmdScroll--
@@ -430,23 +766,25 @@ func (m progressModel) handlePermissionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			m.permissionChoice--
 		}
 	case "right", "l":
-		if m.permissionChoice < 2 {
+		if m.permissionChoice < 5 {
 			m.permissionChoice++
 		}
 	case "enter":
 		switch m.permissionChoice {
 		case 0: // Approve
-			m.currentPermission.ResponseCh <- permission.PermissionResponse{Approved: true}
+			m.resolve(*m.currentPermission, permission.PermissionResponse{Approved: true}, "you")
 			return m.advancePermissionQueue(), nil
 		case 1: // Deny
-			m.currentPermission.ResponseCh <- permission.PermissionResponse{Approved: false}
+			m.resolve(*m.currentPermission, permission.PermissionResponse{Approved: false}, "you")
 			return m.advancePermissionQueue(), nil
 		case 2: // Approve all matching
-			pattern := extractPattern(m.currentPermission.Command)
-			m.approvedPatterns[pattern] = true
-			m.currentPermission.ResponseCh <- permission.PermissionResponse{Approved: true}
-			m = m.drainAutoApproved()
-			return m.advancePermissionQueue(), nil
+			return m.approvePattern(false), nil
+		case 3: // Approve all matching in this repo
+			return m.approvePattern(true), nil
+		case 4: // Always allow
+			return m.saveDecision(true), nil
+		case 5: // Always deny
+			return m.saveDecision(false), nil
 		}
 	}
 	return m, nil
@@ -455,8 +793,26 @@ func (m progressModel) handlePermissionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 func (m progressModel) handleQuestionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Collect all options across all questions
 	options := m.collectQuestionOptions()
-	if len(options) == 0 {
-		return m, nil
+
+	if m.questionTyping {
+		switch msg.Type {
+		case tea.KeyEnter:
+			answer := strings.TrimSpace(m.questionInput.Value())
+			if answer == "" {
+				return m, nil
+			}
+			m.resolve(*m.currentPermission, permission.PermissionResponse{Answer: answer}, "you")
+			return m.advancePermissionQueue(), nil
+		case tea.KeyEsc:
+			if len(options) > 0 {
+				m.questionTyping = false
+				m.questionInput.Blur()
+			}
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.questionInput, cmd = m.questionInput.Update(msg)
+		return m, cmd
 	}
 
 	switch msg.String() {
@@ -465,15 +821,17 @@ func (m progressModel) handleQuestionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.questionOptionIdx--
 		}
 	case "down", "j":
-		if m.questionOptionIdx < len(options)-1 {
+		if m.questionOptionIdx < len(options) {
 			m.questionOptionIdx++
 		}
+	case "t":
+		return m.typeAnswer()
 	case "enter":
-		selected := options[m.questionOptionIdx]
-		m.currentPermission.ResponseCh <- permission.PermissionResponse{
-			Approved: false,
-			Answer:   selected.Label,
+		if m.questionOptionIdx == len(options) {
+			return m.typeAnswer()
 		}
+		selected := options[m.questionOptionIdx]
+		m.resolve(*m.currentPermission, permission.PermissionResponse{Answer: selected.Label}, "you")
 		return m.advancePermissionQueue(), nil
 	default:
 		// Number keys for quick selection (1-9)
@@ -482,10 +840,7 @@ func (m progressModel) handleQuestionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			idx := int(key[0] - '1')
 			if idx < len(options) {
 				selected := options[idx]
-				m.currentPermission.ResponseCh <- permission.PermissionResponse{
-					Approved: false,
-					Answer:   selected.Label,
-				}
+				m.resolve(*m.currentPermission, permission.PermissionResponse{Answer: selected.Label}, "you")
 				return m.advancePermissionQueue(), nil
 			}
 		}
@@ -493,6 +848,30 @@ func (m progressModel) handleQuestionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// showQuestion resets the question prompt for the current question, going
+// straight to typing an answer when it offers no options.
+func (m progressModel) showQuestion() progressModel {
+	m.questionOptionIdx = 0
+	m.questionInput = textinput.New()
+	m.questionInput.Placeholder = "Type your answer"
+	m.questionInput.CharLimit = 2048
+	m.questionInput.Width = 60
+	m.questionTyping = len(m.collectQuestionOptions()) == 0
+	if m.questionTyping {
+		m.questionInput.Focus()
+	}
+	return m
+}
+
+// typeAnswer switches the question prompt to a free-text answer, for open
+// questions none of the options fit.
+func (m progressModel) typeAnswer() (tea.Model, tea.Cmd) {
+	m.questionTyping = true
+	m.questionOptionIdx = len(m.collectQuestionOptions())
+	m.questionInput.Focus()
+	return m, textinput.Blink
+}
+
 // collectQuestionOptions returns a flat list of all options across all questions.
 func (m progressModel) collectQuestionOptions() []permission.QuestionOption {
 	if m.currentPermission == nil {
@@ -506,24 +885,22 @@ func (m progressModel) collectQuestionOptions() []permission.QuestionOption {
 }
 
 func (m progressModel) advancePermissionQueue() progressModel {
+	if m.currentPermission != nil {
+		m.settle(m.currentPermission.ID)
+	}
 	if len(m.permissionQueue) > 0 {
 		next := m.permissionQueue[0]
 		m.permissionQueue = m.permissionQueue[1:]
 
-		// Questions skip auto-approve; regular permissions check patterns
-		if !next.IsQuestion {
-			pattern := extractPattern(next.Command)
-			if m.approvedPatterns[pattern] {
-				next.ResponseCh <- permission.PermissionResponse{Approved: true}
-				m.currentPermission = nil
-				return m.advancePermissionQueue()
-			}
+		if m.autoAnswer(next) {
+			m.currentPermission = nil
+			return m.advancePermissionQueue()
 		}
 
 		m.currentPermission = &next
 		m.permissionCmdScroll = 0
 		if next.IsQuestion {
-			m.questionOptionIdx = 0
+			m = m.showQuestion()
 		} else {
 			m.permissionChoice = 0
 		}
@@ -537,10 +914,7 @@ func (m progressModel) advancePermissionQueue() progressModel {
 func (m progressModel) drainAutoApproved() progressModel {
 	var remaining []permission.PermissionRequest
 	for _, req := range m.permissionQueue {
-		pattern := extractPattern(req.Command)
-		if m.approvedPatterns[pattern] {
-			req.ResponseCh <- permission.PermissionResponse{Approved: true}
-		} else {
+		if !m.autoAnswer(req) {
 			remaining = append(remaining, req)
 		}
 	}
@@ -548,6 +922,22 @@ func (m progressModel) drainAutoApproved() progressModel {
 	return m
 }
 
+// policyDecisionLine describes a request answered from the permission
+// policy for the repo's log.
+func policyDecisionLine(d permission.Decision) string {
+	what := d.Command
+	if what == "" {
+		what = d.ToolName
+	}
+	if d.Approved {
+		return fmt.Sprintf("🛡 Allowed by policy (%s): %s", d.Rule, what)
+	}
+	if d.Rule != "" {
+		return fmt.Sprintf("🛡 Denied by policy (%s): %s", d.Rule, what)
+	}
+	return fmt.Sprintf("🛡 Denied by policy, %s: %s", d.Reason, what)
+}
+
 // extractPattern returns a glob-like pattern from a command (first token + *).
 func extractPattern(command string) string {
 	parts := strings.Fields(command)
@@ -617,18 +1007,23 @@ func (m progressModel) View() string {
 	b.WriteString(titleStyle.Render(fmt.Sprintf(
 		"Processing repos %3d%% |%s| (%d/%d) %s",
 		pct, bar, m.completed, m.total, timeInfo)))
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	compact := compactAt(m.termHeight)
+	if !compact {
+		b.WriteString("\n")
+	}
 
-	// Wizard context (branch, PR title, prompt)
+	// Wizard context (branch, PR title, prompt), reduced to the prompt line
+	// in compact layouts
 	dimLabel := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
 	dimValue := lipgloss.NewStyle().Foreground(lipgloss.Color("250"))
-	if m.branchName != "" || m.prTitle != "" {
+	if !compact && (m.branchName != "" || m.prTitle != "") {
 		var parts []string
 		if m.branchName != "" {
 			parts = append(parts, dimLabel.Render("Branch: ")+dimValue.Render(m.branchName))
 		}
 		if m.prTitle != "" {
-			parts = append(parts, dimLabel.Render("PR: ")+dimValue.Render(m.prTitle))
+			parts = append(parts, dimLabel.Render(m.titleLabel())+dimValue.Render(m.prTitle))
 		}
 		b.WriteString("  " + strings.Join(parts, "    "))
 		b.WriteString("\n")
@@ -643,7 +1038,7 @@ func (m progressModel) View() string {
 		if m.promptExpanded {
 			// Expanded: show full prompt in a bordered box
 			btnStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("33"))
-			b.WriteString(promptPrefix + dimLabel.Render("Prompt ") + btnStyle.Render("[▼ collapse]"))
+			b.WriteString(promptPrefix + dimLabel.Render(m.promptLabel()+" ") + btnStyle.Render("[▼ collapse]"))
 			b.WriteString("\n")
 
 			boxWidth := m.termWidth - 10
@@ -673,14 +1068,24 @@ func (m progressModel) View() string {
 			}
 			btnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
 			btn := btnStyle.Render(" [▶ expand]")
-			b.WriteString(promptPrefix + dimLabel.Render("Prompt: ") + dimValue.Render(p) + btn)
+			b.WriteString(promptPrefix + dimLabel.Render(m.promptLabel()+": ") + dimValue.Render(p) + btn)
 			b.WriteString("\n")
 		}
 	}
-	if m.branchName != "" || m.prTitle != "" || m.prompt != "" {
+	if compact && m.prompt == "" && m.prTitle != "" {
+		b.WriteString("  " + dimLabel.Render(m.titleLabel()) + dimValue.Render(m.prTitle) + "\n")
+	}
+	if !compact && (m.branchName != "" || m.prTitle != "" || m.prompt != "") {
 		b.WriteString("\n")
 	}
 
+	// GitHub API quota countdown
+	if !m.rateLimit.Reset.IsZero() {
+		wait := max(time.Until(m.rateLimit.Reset), 0)
+		b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214")).Render(rateLimitLine(m.rateLimit, wait)))
+		b.WriteString("\n\n")
+	}
+
 	// Permission prompt or question prompt (shown between progress bar and project list)
 	if m.currentPermission != nil {
 		if m.currentPermission.IsQuestion {
@@ -689,6 +1094,9 @@ func (m progressModel) View() string {
 			b.WriteString(m.renderPermissionPrompt())
 		}
 		b.WriteString("\n")
+	} else if m.currentReview != nil {
+		b.WriteString(m.renderReviewPrompt())
+		b.WriteString("\n")
 	}
 
 	// Pause confirmation
@@ -698,7 +1106,11 @@ func (m progressModel) View() string {
 			"⏸  Batch complete — %d of %d repos processed.", m.completed, m.total)))
 		b.WriteString("\n")
 		hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
-		b.WriteString(hintStyle.Render("  💰 Please verify you have sufficient AI credits before continuing with the next batch."))
+		if m.pauseReason != "" {
+			b.WriteString(pauseStyle.Render("  💰 " + m.pauseReason))
+		} else {
+			b.WriteString(hintStyle.Render("  💰 Please verify you have sufficient AI credits before continuing with the next batch."))
+		}
 		b.WriteString("\n")
 		if m.pauseEditing {
 			editLabel := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
@@ -714,7 +1126,15 @@ func (m progressModel) View() string {
 				b.WriteString(editedStyle.Render("  ✓ Prompt updated for next batch"))
 				b.WriteString("\n")
 			}
-			b.WriteString(hintStyle.Render("  Press Enter to continue • e: edit prompt • Ctrl+C to stop."))
+			if m.exportErr != "" {
+				b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("  ✗ Export failed: " + m.exportErr))
+				b.WriteString("\n")
+			}
+			exportHint := ""
+			if m.canExport {
+				exportHint = " • s: export for handoff"
+			}
+			b.WriteString(hintStyle.Render("  Press Enter to continue • e: edit prompt" + exportHint + " • Ctrl+C to stop."))
 			b.WriteString("\n")
 		}
 		b.WriteString("\n")
@@ -746,6 +1166,12 @@ func (m progressModel) View() string {
 			prefix = spinnerStyle.Render(frame) + " "
 		}
 		b.WriteString(fmt.Sprintf("%s%s %s\n", prefix, repoStyle.Render(fmt.Sprintf("[%s]", repo)), status))
+		if repo == m.liveLogRepo {
+			b.WriteString(m.renderLiveLog(repo))
+		}
+		if repo == m.permissionsRepo {
+			b.WriteString(m.renderPermissionLog(repo))
+		}
 	}
 
 	remaining := len(sorted) - end
@@ -779,6 +1205,10 @@ func (m progressModel) View() string {
 		if totalWrapped > maxPermissionCmdLines {
 			hints = append(hints, helpStyle.Render("↑↓: scroll command"))
 		}
+	} else if m.currentReview != nil {
+		if len(m.reviewLines()) > maxPermissionCmdLines {
+			hints = append(hints, helpStyle.Render("↑↓: scroll reasons"))
+		}
 	} else {
 		hints = append(hints, helpStyle.Render("↑↓: navigate"))
 	}
@@ -788,9 +1218,23 @@ func (m progressModel) View() string {
 		} else {
 			hints = append(hints, helpStyle.Render("enter: expand"))
 		}
-	} else if m.cursorRepo != "" && m.isCancellable(m.cursorRepo) {
-		cancelHintStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
-		hints = append(hints, cancelHintStyle.Render("x: cancel"))
+	} else if m.cursorRepo != "" {
+		if m.liveLogRepo == m.cursorRepo {
+			hints = append(hints, helpStyle.Render("enter: hide log"))
+		} else {
+			hints = append(hints, helpStyle.Render("enter: live log"))
+		}
+		if n := len(m.permissionLog[m.cursorRepo]); n > 0 {
+			if m.permissionsRepo == m.cursorRepo {
+				hints = append(hints, helpStyle.Render("p: hide permissions"))
+			} else {
+				hints = append(hints, helpStyle.Render(fmt.Sprintf("p: permissions (%d)", n)))
+			}
+		}
+		if m.isCancellable(m.cursorRepo) {
+			cancelHintStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+			hints = append(hints, cancelHintStyle.Render("x: cancel"))
+		}
 	}
 	hints = append(hints, helpStyle.Render("ctrl+c: abort all"))
 	b.WriteString("  " + strings.Join(hints, helpStyle.Render("  •  ")))
@@ -799,6 +1243,94 @@ func (m progressModel) View() string {
 	return b.String()
 }
 
+// renderLiveLog renders the latest AI output lines for repo in a box below its row.
+func (m progressModel) renderLiveLog(repo string) string {
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	lineStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("250"))
+
+	boxWidth := m.termWidth - 14
+	if boxWidth < 40 {
+		boxWidth = 40
+	}
+	maxContentWidth := boxWidth - 4
+
+	var content []string
+	for _, line := range m.logs[repo] {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		if len(line) > maxContentWidth {
+			line = line[:maxContentWidth-3] + "..."
+		}
+		content = append(content, lineStyle.Render(line))
+	}
+	if len(content) == 0 {
+		content = append(content, dimStyle.Render("No output yet"))
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1).
+		Width(boxWidth)
+
+	var b strings.Builder
+	for _, boxLine := range strings.Split(boxStyle.Render(strings.Join(content, "\n")), "\n") {
+		b.WriteString("    " + boxLine + "\n")
+	}
+	return b.String()
+}
+
+// renderPermissionLog renders repo's answered permission requests in a box
+// below its row, latest last.
+func (m progressModel) renderPermissionLog(repo string) string {
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+	approvedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("40"))
+	deniedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	boxWidth := m.termWidth - 14
+	if boxWidth < 40 {
+		boxWidth = 40
+	}
+	maxContentWidth := boxWidth - 4
+
+	log := m.permissionLog[repo]
+	var content []string
+	if len(log) > maxLogLines {
+		content = append(content, dimStyle.Render(fmt.Sprintf("%d earlier not shown", len(log)-maxLogLines)))
+		log = log[len(log)-maxLogLines:]
+	}
+	for _, r := range log {
+		mark, style := "✗", deniedStyle
+		if r.Approved || r.Answer != "" {
+			mark, style = "✓", approvedStyle
+		}
+		what := r.Command
+		if what == "" {
+			what = r.Tool
+		}
+		if r.Answer != "" {
+			what += " → " + r.Answer
+		}
+		line := fmt.Sprintf("%s %s %s", r.At.Format("15:04:05"), mark, strings.ReplaceAll(what, "\n", " "))
+		suffix := " — " + r.How
+		if width := len(line) + len(suffix); width > maxContentWidth {
+			line = line[:max(maxContentWidth-len(suffix)-3, 12)] + "..."
+		}
+		content = append(content, style.Render(line)+dimStyle.Render(suffix))
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1).
+		Width(boxWidth)
+
+	var b strings.Builder
+	for _, boxLine := range strings.Split(boxStyle.Render(strings.Join(content, "\n")), "\n") {
+		b.WriteString("    " + boxLine + "\n")
+	}
+	return b.String()
+}
+
 func (m progressModel) renderPermissionPrompt() string {
 	var b strings.Builder
 
@@ -816,6 +1348,9 @@ func (m progressModel) renderPermissionPrompt() string {
 		toolLabel = "command"
 	}
 	b.WriteString(lockStyle.Render(fmt.Sprintf("🔐 [%s] wants to run %s:", repoName, toolLabel)))
+	if m.currentPermission.Restored {
+		b.WriteString(dimStyle.Render("  (asked before copycat restarted)"))
+	}
 	b.WriteString("\n")
 
 	cmdLines := strings.Split(m.currentPermission.Command, "\n")
@@ -869,10 +1404,17 @@ func (m progressModel) renderPermissionPrompt() string {
 	b.WriteString("\n")
 
 	pattern := extractPattern(m.currentPermission.Command)
+	alwaysAllow := "Always allow (A)"
+	if rule, ok := permission.SuggestRule(m.currentPermission.ToolName, m.currentPermission.Command); ok {
+		alwaysAllow = fmt.Sprintf("Always allow \"%s\" (A)", rule.Command)
+	}
 	options := []string{
 		"Approve (y)",
 		"Deny (n)",
 		fmt.Sprintf("Approve all \"%s\" (a)", pattern),
+		fmt.Sprintf("Only in %s (r)", repoName),
+		alwaysAllow,
+		"Always deny (D)",
 	}
 
 	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
@@ -885,11 +1427,19 @@ func (m progressModel) renderPermissionPrompt() string {
 		} else {
 			b.WriteString(normalStyle.Render("  " + opt))
 		}
-		if i < len(options)-1 {
+		switch {
+		case i == 3:
+			// Saved decisions go on their own line
+			b.WriteString("\n  ")
+		case i < len(options)-1:
 			b.WriteString("    ")
 		}
 	}
 	b.WriteString("\n")
+	if m.policyErr != "" {
+		b.WriteString(dimStyle.Render("  ⚠ " + m.policyErr))
+		b.WriteString("\n")
+	}
 
 	if len(m.permissionQueue) > 0 {
 		b.WriteString(dimStyle.Render(fmt.Sprintf("\n  [%d more pending]", len(m.permissionQueue))))
@@ -913,6 +1463,11 @@ func (m progressModel) renderQuestionPrompt() string {
 		repoName = "repo"
 	}
 
+	if m.currentPermission.Restored {
+		b.WriteString(dimStyle.Render("(asked before copycat restarted)"))
+		b.WriteString("\n")
+	}
+
 	optionIdx := 0
 	for _, q := range m.currentPermission.Questions {
 		b.WriteString(questionStyle.Render(fmt.Sprintf("❓ [%s]", repoName)))
@@ -942,9 +1497,27 @@ func (m progressModel) renderQuestionPrompt() string {
 		}
 	}
 
-	b.WriteString("\n")
-	b.WriteString(dimStyle.Render("  ↑↓: navigate  enter: select  1-9: quick select"))
-	b.WriteString("\n")
+	if m.questionTyping {
+		b.WriteString("  ")
+		b.WriteString(m.questionInput.View())
+		b.WriteString("\n\n")
+		if optionIdx > 0 {
+			b.WriteString(dimStyle.Render("  enter: send answer  esc: back to options"))
+		} else {
+			b.WriteString(dimStyle.Render("  enter: send answer"))
+		}
+		b.WriteString("\n")
+	} else {
+		label := "    Type an answer (t)"
+		if optionIdx == m.questionOptionIdx {
+			b.WriteString(selectedStyle.Render("▸ " + label))
+		} else {
+			b.WriteString(normalStyle.Render("  " + label))
+		}
+		b.WriteString("\n\n")
+		b.WriteString(dimStyle.Render("  ↑↓: navigate  enter: select  1-9: quick select  t: type an answer"))
+		b.WriteString("\n")
+	}
 
 	if len(m.permissionQueue) > 0 {
 		b.WriteString(dimStyle.Render(fmt.Sprintf("  [%d more pending]", len(m.permissionQueue))))
@@ -975,8 +1548,19 @@ func (m progressModel) sortedRepos() []string {
 	return sorted
 }
 
+// maxVisible returns how many project rows are shown at once. Compact
+// layouts show what fits below the banner, header and help: 1 + border(2) +
+// title(1) + context(1) + scroll indicators(2) + help(2) + postLines.
+func (m progressModel) maxVisible() int {
+	if !compactAt(m.termHeight) {
+		return maxVisibleProjects
+	}
+	return min(max(m.termHeight-9-len(m.postLines), 3), maxVisibleProjects)
+}
+
 // visibleWindow returns the start and end indices for the visible window of projects.
 func (m progressModel) visibleWindow(sorted []string) (int, int) {
+	maxVisibleProjects := m.maxVisible()
 	if len(sorted) <= maxVisibleProjects {
 		return 0, len(sorted)
 	}
@@ -1024,6 +1608,15 @@ func (m progressModel) visibleWindow(sorted []string) (int, int) {
 	return start, start + maxVisibleProjects
 }
 
+// rateLimitLine describes a wait for a GitHub API quota to reset in wait.
+func rateLimitLine(msg RateLimitMsg, wait time.Duration) string {
+	left := fmt.Sprintf("%d left", msg.Remaining)
+	if msg.Limit > 0 {
+		left = fmt.Sprintf("%d of %d left", msg.Remaining, msg.Limit)
+	}
+	return fmt.Sprintf("⏳ GitHub API %s quota low (%s), GitHub requests resume in %s", msg.Resource, left, formatDuration(wait))
+}
+
 func formatDuration(d time.Duration) string {
 	d = d.Round(time.Second)
 	m := int(d.Minutes())
@@ -8,6 +8,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/saltpay/copycat/v2/internal/util"
 )
 
 const (
@@ -20,8 +21,11 @@ type projectsConfirmedMsg struct {
 	Selected []config.Project
 }
 
-// projectsRefreshMsg is emitted when the user requests a project list refresh.
-type projectsRefreshMsg struct{}
+// projectsRefreshMsg is emitted when the user requests a project list refresh,
+// with a new discovery filter when they changed it.
+type projectsRefreshMsg struct {
+	Discovery *config.DiscoveryFilter
+}
 
 type projectSelectorModel struct {
 	projects     []config.Project
@@ -40,9 +44,28 @@ type projectSelectorModel struct {
 	filteredProjects []config.Project
 	// Track if user has manually modified selection in filter mode
 	manualSelection bool
+	// Search fields: incremental fuzzy narrowing that never changes the selection
+	searchMode bool
+	searchText string
 	// Slack room warning after refresh
 	showSlackWarning  bool
 	missingSlackCount int
+	// Saved selection profiles
+	profiles      map[string][]string
+	saveProfile   func(name string, repos []string) error
+	profileMode   profileMode
+	profileName   string
+	profileCursor int
+	profileNotice string
+	// Discovery filter prompt
+	discoveryMode  bool
+	discoveryQuery string
+	discoveryError string
+	// GitHub settings deciding which projects opted out and which are
+	// notified through review requests rather than Slack
+	githubCfg config.GitHubConfig
+	// Reasons projects opted out of copycat runs, keyed by index; these are never selected
+	optedOut map[int]string
 }
 
 func initialModel(projects []config.Project) projectSelectorModel {
@@ -50,7 +73,7 @@ func initialModel(projects []config.Project) projectSelectorModel {
 	sortedProjects := make([]config.Project, len(projects))
 	copy(sortedProjects, projects)
 	sort.Slice(sortedProjects, func(i, j int) bool {
-		return sortedProjects[i].Repo < sortedProjects[j].Repo
+		return sortedProjects[i].Key() < sortedProjects[j].Key()
 	})
 
 	return projectSelectorModel{
@@ -84,6 +107,20 @@ func (m projectSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		// Handle profile save/load prompts
+		if m.profileMode != profileModeNone {
+			return m.updateProfile(msg)
+		}
+		if m.discoveryMode {
+			return m.updateDiscovery(msg)
+		}
+		m.profileNotice = ""
+
+		// Handle search mode
+		if m.searchMode {
+			return m.updateSearch(msg)
+		}
+
 		// Handle filter mode
 		if m.filterMode {
 			switch msg.String() {
@@ -167,8 +204,21 @@ func (m projectSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.quitted = true
 				return m, tea.Quit
 
+			case "/":
+				// Enter search mode, refining any current search
+				m.searchMode = true
+				return m, nil
+
+			case "esc":
+				// Clear an applied search
+				if m.searchText != "" {
+					m.searchText = ""
+					m.refreshSearch()
+				}
+
 			case "f":
-				// Enter filter mode
+				// Enter filter mode (replaces any search)
+				m.searchText = ""
 				m.filterMode = true
 				m.filterText = ""
 				m.filterTerms = nil
@@ -216,7 +266,7 @@ func (m projectSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						if _, ok := m.selected[currentProjectIdx]; ok {
 							delete(m.selected, currentProjectIdx)
 						} else {
-							m.selected[currentProjectIdx] = struct{}{}
+							m.selectProject(currentProjectIdx)
 						}
 						// Mark that user has manually modified selection
 						m.manualSelection = true
@@ -228,7 +278,7 @@ func (m projectSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						if _, ok := m.selected[currentProjectIdx]; ok {
 							delete(m.selected, currentProjectIdx)
 						} else {
-							m.selected[currentProjectIdx] = struct{}{}
+							m.selectProject(currentProjectIdx)
 						}
 					}
 				}
@@ -246,20 +296,31 @@ func (m projectSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						// Select all filtered projects
 						for _, project := range m.filteredProjects {
 							currentProjectIdx := m.findOriginalProjectIndex(project)
-							m.selected[currentProjectIdx] = struct{}{}
+							m.selectProject(currentProjectIdx)
 						}
 					}
 					// Mark that user has manually modified selection
 					m.manualSelection = true
+				} else if m.searchText != "" {
+					// Search applied: toggle only the matching projects
+					allSelected := m.allFilteredProjectsSelected()
+					for _, project := range m.filteredProjects {
+						currentProjectIdx := m.findOriginalProjectIndex(project)
+						if allSelected {
+							delete(m.selected, currentProjectIdx)
+						} else {
+							m.selectProject(currentProjectIdx)
+						}
+					}
 				} else {
 					// Normal mode: select/deselect all projects
-					if len(m.selected) == len(m.projects) {
+					if len(m.selected) == len(m.projects)-len(m.optedOut) {
 						// Deselect all projects
 						m.selected = make(map[int]struct{})
 					} else {
 						// Select all projects
 						for i := range m.projects {
-							m.selected[i] = struct{}{}
+							m.selectProject(i)
 						}
 					}
 				}
@@ -267,6 +328,26 @@ func (m projectSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "r":
 				return m, func() tea.Msg { return projectsRefreshMsg{} }
 
+			case "d":
+				m.discoveryMode = true
+				m.discoveryQuery = m.githubCfg.Discovery.String()
+				m.discoveryError = ""
+				return m, nil
+
+			case "s":
+				m.profileMode = profileModeSave
+				m.profileName = ""
+				return m, nil
+
+			case "p":
+				if len(m.profiles) > 0 {
+					m.profileMode = profileModeLoad
+					m.profileCursor = 0
+				} else {
+					m.profileNotice = "No saved profiles yet — press s to save the current selection"
+				}
+				return m, nil
+
 			case "enter":
 				return m, func() tea.Msg { return projectsConfirmedMsg{Selected: m.extractSelected()} }
 			}
@@ -281,6 +362,97 @@ func (m projectSelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateSearch handles keys while typing a search. Runes extend the query,
+// arrows move the cursor and space toggles selection, so selections made
+// before or during the search are kept.
+func (m projectSelectorModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitted = true
+		return m, tea.Quit
+	case "esc":
+		m.searchMode = false
+		m.searchText = ""
+		m.refreshSearch()
+	case "enter":
+		// Keep the narrowed grid and return to normal navigation
+		m.searchMode = false
+	case "backspace":
+		if len(m.searchText) > 0 {
+			runes := []rune(m.searchText)
+			m.searchText = string(runes[:len(runes)-1])
+			m.refreshSearch()
+		}
+	case "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down":
+		if m.cursor < len(m.filteredProjects)-1 {
+			m.cursor++
+		}
+	case "left":
+		numCols := m.calculateColumns()
+		numRows := (len(m.filteredProjects) + numCols - 1) / numCols
+		if m.cursor >= numRows {
+			m.cursor -= numRows
+		}
+	case "right":
+		numCols := m.calculateColumns()
+		numRows := (len(m.filteredProjects) + numCols - 1) / numCols
+		if m.cursor+numRows < len(m.filteredProjects) {
+			m.cursor += numRows
+		}
+	case " ":
+		if m.cursor < len(m.filteredProjects) {
+			currentProjectIdx := m.findOriginalProjectIndex(m.filteredProjects[m.cursor])
+			if _, ok := m.selected[currentProjectIdx]; ok {
+				delete(m.selected, currentProjectIdx)
+			} else {
+				m.selectProject(currentProjectIdx)
+			}
+		}
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.searchText += msg.String()
+			m.refreshSearch()
+		}
+	}
+
+	m.ensureCursorVisible()
+	return m, nil
+}
+
+// refreshSearch narrows the topic-filtered projects by the search text.
+func (m *projectSelectorModel) refreshSearch() {
+	base := m.projects
+	if len(m.appliedTerms) > 0 {
+		base = m.filterByTerms(m.appliedTerms)
+	}
+	m.filteredProjects = searchProjects(base, m.searchText)
+	m.cursor = 0
+}
+
+// searchProjects returns the projects whose repo name, topics, or Slack room fuzzy-match query.
+func searchProjects(projects []config.Project, query string) []config.Project {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return projects
+	}
+
+	var matched []config.Project
+	for _, project := range projects {
+		fields := append([]string{project.Key(), project.SlackRoom}, project.Topics...)
+		for _, field := range fields {
+			if util.FuzzyMatch(query, field) {
+				matched = append(matched, project)
+				break
+			}
+		}
+	}
+	return matched
+}
+
 // Helper methods for filtering
 func (m projectSelectorModel) filterProjectsByTopic(filterText string) []config.Project {
 	if filterText == "" {
@@ -326,6 +498,11 @@ func (m projectSelectorModel) applyAllFilters() []config.Project {
 		allTerms = append(allTerms, t)
 	}
 
+	return m.filterByTerms(allTerms)
+}
+
+// filterByTerms returns the projects whose topics match every term.
+func (m projectSelectorModel) filterByTerms(allTerms []string) []config.Project {
 	if len(allTerms) == 0 {
 		return m.projects
 	}
@@ -367,7 +544,7 @@ func (m *projectSelectorModel) autoSelectFiltered() {
 		filteredSet[m.findOriginalProjectIndex(project)] = struct{}{}
 	}
 	for _, project := range m.filteredProjects {
-		m.selected[m.findOriginalProjectIndex(project)] = struct{}{}
+		m.selectProject(m.findOriginalProjectIndex(project))
 	}
 	for i := range m.projects {
 		if _, found := filteredSet[i]; !found {
@@ -376,9 +553,17 @@ func (m *projectSelectorModel) autoSelectFiltered() {
 	}
 }
 
+// selectProject adds the project at idx to the selection unless it opted out.
+func (m *projectSelectorModel) selectProject(idx int) {
+	if _, ok := m.optedOut[idx]; ok {
+		return
+	}
+	m.selected[idx] = struct{}{}
+}
+
 func (m projectSelectorModel) findOriginalProjectIndex(project config.Project) int {
 	for i, p := range m.projects {
-		if p.Repo == project.Repo {
+		if p.Key() == project.Key() {
 			return i
 		}
 	}
@@ -388,6 +573,9 @@ func (m projectSelectorModel) findOriginalProjectIndex(project config.Project) i
 func (m projectSelectorModel) allFilteredProjectsSelected() bool {
 	for _, project := range m.filteredProjects {
 		currentProjectIdx := m.findOriginalProjectIndex(project)
+		if _, ok := m.optedOut[currentProjectIdx]; ok {
+			continue
+		}
 		if _, ok := m.selected[currentProjectIdx]; !ok {
 			return false
 		}
@@ -395,10 +583,16 @@ func (m projectSelectorModel) allFilteredProjectsSelected() bool {
 	return true
 }
 
+// missingSlackRoom reports whether nobody will be told about the project's
+// PR: it has no Slack room and its reviewers are not asked on GitHub.
+func (m projectSelectorModel) missingSlackRoom(p config.Project) bool {
+	return strings.TrimSpace(p.SlackRoom) == "" && !m.githubCfg.NotifiesOnGitHub(p)
+}
+
 func (m projectSelectorModel) countMissingSlackRooms() int {
 	count := 0
 	for _, p := range m.projects {
-		if strings.TrimSpace(p.SlackRoom) == "" {
+		if m.missingSlackRoom(p) {
 			count++
 		}
 	}
@@ -417,8 +611,8 @@ func (m projectSelectorModel) calculateColumns() int {
 	maxLen := 0
 	for i, p := range projectsToUse {
 		// Format: "[ ] 123. repo-name" or "[ ] 123. repo-name ⚠"
-		itemLen := len(fmt.Sprintf("[ ] %d. %s", i+1, p.Repo))
-		if strings.TrimSpace(p.SlackRoom) == "" {
+		itemLen := len(fmt.Sprintf("[ ] %d. %s", i+1, p.Key()))
+		if m.missingSlackRoom(p) {
 			itemLen += 2 // " ⚠"
 		}
 		if itemLen > maxLen {
@@ -466,12 +660,12 @@ func (m *projectSelectorModel) ensureCursorVisible() {
 	if cursorRow < m.scrollOffset {
 		m.scrollOffset = cursorRow
 	}
-	if cursorRow >= m.scrollOffset+maxVisibleRows {
-		m.scrollOffset = cursorRow - maxVisibleRows + 1
+	if cursorRow >= m.scrollOffset+m.visibleRows() {
+		m.scrollOffset = cursorRow - m.visibleRows() + 1
 	}
 
 	// Clamp
-	maxOffset := numRows - maxVisibleRows
+	maxOffset := numRows - m.visibleRows()
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
@@ -483,6 +677,28 @@ func (m *projectSelectorModel) ensureCursorVisible() {
 	}
 }
 
+// visibleRows returns how many grid rows are shown at once. Compact layouts
+// show what fits below the banner and title and above the help and counts:
+// banner(1) + border(2) + title(2) + scroll indicators(2) + help(2) +
+// selected(1), plus a line for each notice.
+func (m projectSelectorModel) visibleRows() int {
+	if !compactAt(m.termHeight) {
+		return maxVisibleRows
+	}
+	overhead := 10
+	for _, notice := range []bool{
+		len(m.filteredProjects) < len(m.projects),
+		len(m.optedOut) > 0,
+		m.profileNotice != "",
+		m.countMissingSlackRooms() > 0,
+	} {
+		if notice {
+			overhead++
+		}
+	}
+	return min(max(m.termHeight-overhead, 3), maxVisibleRows)
+}
+
 func (m projectSelectorModel) View() string {
 	if m.quitted {
 		return ""
@@ -499,11 +715,19 @@ func (m projectSelectorModel) View() string {
 		return fmt.Sprintf(
 			"%s\n\n%s\n\n%s",
 			warnStyle.Render(fmt.Sprintf("⚠ %d project(s) have no slack_room configured", m.missingSlackCount)),
-			dimStyle.Render("Slack notifications will be skipped for these projects.\nRun 'copycat edit projects' to configure slack_room."),
+			dimStyle.Render("Slack notifications will be skipped for these projects.\nRun 'copycat edit projects' to configure slack_room, or reviewers with github.notify_reviewers."),
 			dimStyle.Render("Press enter to continue"),
 		)
 	}
 
+	if m.profileMode != profileModeNone {
+		return m.renderProfilePrompt()
+	}
+
+	if m.discoveryMode {
+		return m.renderDiscoveryPrompt()
+	}
+
 	var b strings.Builder
 
 	// Title
@@ -530,6 +754,19 @@ func (m projectSelectorModel) View() string {
 			Padding(0, 1)
 		b.WriteString(filterStyle.Render("> " + m.filterText))
 		b.WriteString("\n\n")
+	} else if m.searchMode || m.searchText != "" {
+		b.WriteString(titleStyle.Render("Search Projects"))
+		b.WriteString("\n")
+		searchStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("255")).
+			Background(lipgloss.Color("206")).
+			Padding(0, 1)
+		cursor := ""
+		if m.searchMode {
+			cursor = "█"
+		}
+		b.WriteString(searchStyle.Render("/ " + m.searchText + cursor))
+		b.WriteString("\n\n")
 	} else {
 		b.WriteString(titleStyle.Render("Select Projects"))
 		if len(m.appliedTerms) > 0 {
@@ -556,17 +793,17 @@ func (m projectSelectorModel) View() string {
 	// Find max width for alignment
 	maxLen := 0
 	for i, p := range projectsToDisplay {
-		itemLen := len(fmt.Sprintf("[ ] %d. %s", i+1, p.Repo))
+		itemLen := len(fmt.Sprintf("[ ] %d. %s", i+1, p.Key()))
 		if itemLen > maxLen {
 			maxLen = itemLen
 		}
 	}
 	colWidth := maxLen + 2
 
-	// Scrolling viewport: only show maxVisibleRows rows
+	// Scrolling viewport: only show visibleRows rows
 	visibleRows := numRows
-	if visibleRows > maxVisibleRows {
-		visibleRows = maxVisibleRows
+	if visibleRows > m.visibleRows() {
+		visibleRows = m.visibleRows()
 	}
 	scrollEnd := m.scrollOffset + visibleRows
 
@@ -596,19 +833,27 @@ func (m projectSelectorModel) View() string {
 			if _, ok := m.selected[originalIdx]; ok {
 				checkbox = "[✓]"
 			}
+			_, optedOut := m.optedOut[originalIdx]
+			if optedOut {
+				checkbox = "[-]"
+			}
 
 			// Item text
-			itemText := fmt.Sprintf("%s %d. %s", checkbox, idx+1, project.Repo)
-			if strings.TrimSpace(project.SlackRoom) == "" {
+			itemText := fmt.Sprintf("%s %d. %s", checkbox, idx+1, project.Key())
+			if m.missingSlackRoom(project) {
 				itemText += " ⚠"
 			}
 
 			// Style based on cursor position
 			itemStyle := lipgloss.NewStyle().Width(colWidth)
+			if optedOut {
+				itemStyle = itemStyle.Foreground(lipgloss.Color("241"))
+			}
 			if idx == m.cursor {
-				itemStyle = itemStyle.
-					Foreground(lipgloss.Color("205")).
-					Bold(true)
+				itemStyle = itemStyle.Bold(true)
+				if !optedOut {
+					itemStyle = itemStyle.Foreground(lipgloss.Color("205"))
+				}
 			}
 
 			rowItems = append(rowItems, itemStyle.Render(itemText))
@@ -626,16 +871,35 @@ func (m projectSelectorModel) View() string {
 		b.WriteString("\n")
 	}
 
-	// Help text
+	// Explain why the project under the cursor cannot be selected
+	if m.cursor < len(projectsToDisplay) {
+		project := projectsToDisplay[m.cursor]
+		if reason, ok := m.optedOut[m.findOriginalProjectIndex(project)]; ok {
+			dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+			b.WriteString(dimStyle.Render(fmt.Sprintf("  ⊘ %s %s", project.Key(), reason)))
+			b.WriteString("\n")
+		}
+	}
+
+	// Help text, without the blank lines around it in compact layouts
 	helpStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("241")).
 		Padding(1, 0)
+	if compactAt(m.termHeight) {
+		helpStyle = helpStyle.Padding(0)
+	}
 
 	var help string
-	if m.filterMode {
+	if m.searchMode {
+		help = "Type to search repo, topic or Slack room • ↑/↓/←/→: navigate • space: toggle • enter: keep results • esc: clear search • ctrl+c: quit"
+	} else if m.searchText != "" {
+		help = "/: refine search • esc: clear search • ↑/↓/←/→: navigate • space: toggle • a: toggle matches • enter: confirm • q: quit"
+	} else if m.filterMode {
 		help = "Type to filter • enter: lock term • enter (empty): apply • esc: clear • backspace: remove last term • ↑/↓/←/→: navigate • space: toggle • a: toggle all • ctrl+c: quit"
+	} else if compactAt(m.termHeight) {
+		help = "/: search • f: filter • space: toggle • a: all • enter: confirm • q: quit"
 	} else {
-		help = "f: filter by topic • ↑/↓/←/→: navigate • space: toggle • a: toggle all • r: refresh • enter: confirm • q: quit"
+		help = "/: search • f: filter by topic • ↑/↓/←/→: navigate • space: toggle • a: toggle all • s: save profile • p: load profile • r: refresh • d: discovery filter • enter: confirm • q: quit"
 	}
 	b.WriteString("\n")
 	b.WriteString(helpStyle.Render(help))
@@ -654,14 +918,31 @@ func (m projectSelectorModel) View() string {
 		b.WriteString(countStyle.Render(filterCountText))
 	}
 
+	if len(m.optedOut) > 0 {
+		dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+		b.WriteString("\n")
+		b.WriteString(dimStyle.Render(fmt.Sprintf("⊘ %d project(s) opted out and cannot be selected", len(m.optedOut))))
+	}
+
+	if m.profileNotice != "" {
+		noticeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+		if strings.HasPrefix(m.profileNotice, "✓") {
+			noticeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("40"))
+		}
+		b.WriteString("\n")
+		b.WriteString(noticeStyle.Render(m.profileNotice))
+	}
+
 	// Warn about projects without slack rooms
 	missingSlack := m.countMissingSlackRooms()
 	if missingSlack > 0 {
 		warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
 		b.WriteString("\n")
-		b.WriteString(warnStyle.Render(fmt.Sprintf(
-			"⚠ %d project(s) have no slack_room — run 'copycat edit projects' to configure",
-			missingSlack)))
+		warning := fmt.Sprintf("⚠ %d project(s) have no slack_room — run 'copycat edit projects' to configure", missingSlack)
+		if compactAt(m.termHeight) {
+			warning = fmt.Sprintf("⚠ %d project(s) have no slack_room", missingSlack)
+		}
+		b.WriteString(warnStyle.Render(warning))
 	}
 
 	return b.String()
@@ -0,0 +1,95 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/saltpay/copycat/v2/internal/history"
+)
+
+// reusablePrompts returns the previously used prompts for the chosen action.
+func (m wizardModel) reusablePrompts() []history.PromptEntry {
+	if m.licenseHeader {
+		return nil
+	}
+	var entries []history.PromptEntry
+	for _, entry := range m.promptHistory {
+		if entry.Action == m.action {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// openPromptPicker shows the previous prompts, if there are any.
+func (m wizardModel) openPromptPicker() wizardModel {
+	if len(m.reusablePrompts()) > 0 {
+		m.pickingPrompt = true
+		m.promptCursor = 0
+	}
+	return m
+}
+
+// updatePromptPicker handles keys while choosing a previous prompt. Picking one
+// from the PR title step fills in both the title and the prompt for editing.
+func (m wizardModel) updatePromptPicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	entries := m.reusablePrompts()
+	switch keyMsg.String() {
+	case "esc":
+		m.pickingPrompt = false
+	case "up", "k":
+		if m.promptCursor > 0 {
+			m.promptCursor--
+		}
+	case "down", "j":
+		if m.promptCursor < len(entries)-1 {
+			m.promptCursor++
+		}
+	case "enter":
+		entry := entries[m.promptCursor]
+		m.pickingPrompt = false
+		m.promptInput.SetValue(entry.Prompt)
+		if m.currentStep == stepPRTitle {
+			m.prTitleInput.SetValue(entry.Title)
+			m.prTitleInput.CursorEnd()
+			return m, nil
+		}
+		m.promptInput.Focus()
+		return m, textarea.Blink
+	}
+	return m, nil
+}
+
+// renderPromptPicker renders the list of previous prompts.
+func (m wizardModel) renderPromptPicker(b *strings.Builder, label, cursor, hint lipgloss.Style) {
+	b.WriteString(label.Render("  Reuse Previous Prompt"))
+	b.WriteString("\n")
+	for i, entry := range m.reusablePrompts() {
+		summary := strings.Join(strings.Fields(entry.Prompt), " ")
+		if entry.Title != "" {
+			summary = entry.Title + " — " + summary
+		}
+		if len(summary) > 70 {
+			summary = summary[:67] + "..."
+		}
+		line := fmt.Sprintf("%s  %s", entry.UsedAt.Format("2006-01-02"), summary)
+		if i == m.promptCursor {
+			b.WriteString(cursor.Render("    > " + line))
+		} else {
+			b.WriteString("      " + line)
+		}
+		b.WriteString("\n")
+	}
+	if m.currentStep == stepPRTitle {
+		b.WriteString(hint.Render("    Fills in the PR title and prompt so you can edit them"))
+		b.WriteString("\n")
+	}
+}
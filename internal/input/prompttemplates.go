@@ -0,0 +1,129 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// openTemplatePicker shows the prompt library, if there is one.
+func (m wizardModel) openTemplatePicker() wizardModel {
+	if len(m.templates) > 0 && !m.licenseHeader {
+		m.pickingTemplate = true
+		m.templateCursor = 0
+	}
+	return m
+}
+
+// updateTemplate handles keys while picking a template and filling in its variables.
+func (m wizardModel) updateTemplate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+
+	if m.template != nil {
+		if ok {
+			switch keyMsg.Type {
+			case tea.KeyEsc:
+				m.template = nil
+				m.pickingTemplate = false
+				return m, nil
+			case tea.KeyEnter:
+				value := strings.TrimSpace(m.templateVarInput.Value())
+				if value == "" {
+					return m, nil
+				}
+				m.templateValues[m.templateVars[len(m.templateValues)]] = value
+				if len(m.templateValues) < len(m.templateVars) {
+					m.templateVarInput.SetValue("")
+					return m, nil
+				}
+				return m.applyTemplate()
+			}
+		}
+		var cmd tea.Cmd
+		m.templateVarInput, cmd = m.templateVarInput.Update(msg)
+		return m, cmd
+	}
+
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "esc":
+		m.pickingTemplate = false
+	case "up", "k":
+		if m.templateCursor > 0 {
+			m.templateCursor--
+		}
+	case "down", "j":
+		if m.templateCursor < len(m.templates)-1 {
+			m.templateCursor++
+		}
+	case "enter":
+		tmpl := m.templates[m.templateCursor]
+		m.template = &tmpl
+		m.templateVars = tmpl.Variables()
+		m.templateValues = make(map[string]string, len(m.templateVars))
+		if len(m.templateVars) == 0 {
+			return m.applyTemplate()
+		}
+		m.templateVarInput = textinput.New()
+		m.templateVarInput.Width = 60
+		m.templateVarInput.Focus()
+		return m, textinput.Blink
+	}
+	return m, nil
+}
+
+// applyTemplate fills the prompt (and the PR title, when picked on that step)
+// from the chosen template so it can be reviewed and edited.
+func (m wizardModel) applyTemplate() (tea.Model, tea.Cmd) {
+	title, prompt := m.template.Render(m.templateValues)
+	m.template = nil
+	m.pickingTemplate = false
+
+	m.promptInput.SetValue(prompt)
+	if m.currentStep == stepPRTitle {
+		if title != "" {
+			m.prTitleInput.SetValue(title)
+			m.prTitleInput.CursorEnd()
+		}
+		return m, nil
+	}
+	m.promptInput.Focus()
+	return m, textarea.Blink
+}
+
+// renderTemplatePicker renders the prompt library or the variable being filled in.
+func (m wizardModel) renderTemplatePicker(b *strings.Builder, label, cursor, hint lipgloss.Style) {
+	if m.template != nil {
+		name := m.templateVars[len(m.templateValues)]
+		b.WriteString(label.Render(fmt.Sprintf("  %s: %s (%d/%d)", m.template.Name, name, len(m.templateValues)+1, len(m.templateVars))))
+		b.WriteString("\n")
+		b.WriteString(indentLines(m.templateVarInput.View(), "    "))
+		b.WriteString("\n")
+		return
+	}
+
+	b.WriteString(label.Render("  Prompt Templates"))
+	b.WriteString("\n")
+	for i, tmpl := range m.templates {
+		line := tmpl.Name
+		if tmpl.Description != "" {
+			line += " — " + tmpl.Description
+		}
+		if i == m.templateCursor {
+			b.WriteString(cursor.Render("    > " + line))
+		} else {
+			b.WriteString("      " + line)
+		}
+		b.WriteString("\n")
+	}
+	if m.currentStep == stepPRTitle {
+		b.WriteString(hint.Render("    Fills in the PR title and prompt so you can edit them"))
+		b.WriteString("\n")
+	}
+}
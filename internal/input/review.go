@@ -0,0 +1,141 @@
+package input
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ReviewDecisionMsg asks the user whether to keep changes the review tool rejected.
+type ReviewDecisionMsg struct {
+	Repo       string
+	Reviewer   string
+	Reasons    string
+	ResponseCh chan bool
+}
+
+// RequestReviewDecision pauses the repo until the user decides whether to push
+// changes the reviewer rejected. Returns false if ctx is cancelled first.
+func (s *StatusSender) RequestReviewDecision(ctx context.Context, repo, reviewer, reasons string) bool {
+	responseCh := make(chan bool, 1)
+	s.send(ReviewDecisionMsg{Repo: repo, Reviewer: reviewer, Reasons: reasons, ResponseCh: responseCh})
+
+	select {
+	case keep := <-responseCh:
+		return keep
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (m progressModel) handleReviewDecision(msg ReviewDecisionMsg) (tea.Model, tea.Cmd) {
+	if m.currentReview == nil {
+		m.currentReview = &msg
+		m.reviewScroll = 0
+	} else {
+		m.reviewQueue = append(m.reviewQueue, msg)
+	}
+	return m, nil
+}
+
+func (m progressModel) handleReviewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		m.currentReview.ResponseCh <- true
+		return m.advanceReviewQueue(), nil
+	case "n":
+		m.currentReview.ResponseCh <- false
+		return m.advanceReviewQueue(), nil
+	case "up", "k":
+		if m.reviewScroll > 0 {
+			m.reviewScroll--
+		}
+	case "down", "j":
+		if m.reviewScroll < len(m.reviewLines())-maxPermissionCmdLines {
+			m.reviewScroll++
+		}
+	}
+	return m, nil
+}
+
+func (m progressModel) advanceReviewQueue() progressModel {
+	m.currentReview = nil
+	m.reviewScroll = 0
+	if len(m.reviewQueue) > 0 {
+		next := m.reviewQueue[0]
+		m.reviewQueue = m.reviewQueue[1:]
+		m.currentReview = &next
+	}
+	return m
+}
+
+// reviewLines returns the reviewer's reasons wrapped to the prompt box width.
+func (m progressModel) reviewLines() []string {
+	if m.currentReview == nil {
+		return nil
+	}
+	maxContentWidth := m.termWidth - 10 - 4
+	if maxContentWidth < 36 {
+		maxContentWidth = 36
+	}
+	// Wrapped by display width, so wide and multi-byte characters fit whole
+	return strings.Split(ansi.Wrap(m.currentReview.Reasons, maxContentWidth, ""), "\n")
+}
+
+func (m progressModel) renderReviewPrompt() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214"))
+	reasonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("🔍 [%s] changes were rejected by %s:", m.currentReview.Repo, m.currentReview.Reviewer)))
+	b.WriteString("\n")
+
+	lines := m.reviewLines()
+	start := m.reviewScroll
+	end := start + maxPermissionCmdLines
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	var rendered []string
+	if start > 0 {
+		rendered = append(rendered, dimStyle.Render(fmt.Sprintf("  ↑ %d more above", start)))
+	}
+	for _, line := range lines[start:end] {
+		rendered = append(rendered, reasonStyle.Render(line))
+	}
+	if remaining := len(lines) - end; remaining > 0 {
+		rendered = append(rendered, dimStyle.Render(fmt.Sprintf("  ↓ %d more below", remaining)))
+	}
+
+	boxWidth := m.termWidth - 10
+	if boxWidth < 40 {
+		boxWidth = 40
+	}
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("238")).
+		Padding(0, 1).
+		Width(boxWidth)
+	for _, line := range strings.Split(box.Render(strings.Join(rendered, "\n")), "\n") {
+		b.WriteString("  " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	optionStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	b.WriteString("  " + optionStyle.Render("Push anyway (y)") + "    " + optionStyle.Render("Discard changes (n)"))
+	b.WriteString("\n")
+
+	if len(m.reviewQueue) > 0 {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("\n  [%d more pending]", len(m.reviewQueue))))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package input
+
+import (
+	"regexp"
+	"strings"
+)
+
+// scaffoldSections are the headings the editor scaffold asks for, in order.
+var scaffoldSections = []struct {
+	heading string
+	hint    string
+}{
+	{"Goal:", "What should change in each repo, and why?"},
+	{"Constraints:", "What must stay as it is: public APIs, versions, style, files to leave alone."},
+	{"Files to touch:", "Paths or patterns, if you know them, e.g. pom.xml or .github/workflows/*.yml."},
+	{"Definition of done:", "How to tell it worked, e.g. the build and tests pass, no deprecated calls remain."},
+}
+
+var (
+	scaffoldCommentLines = regexp.MustCompile(`(?m)^[ \t]*<!--(?s:.*?)-->[ \t]*(\n|$)`)
+	scaffoldComment      = regexp.MustCompile(`(?s)<!--.*?-->`)
+	blankLines           = regexp.MustCompile(`\n{3,}`)
+)
+
+// promptScaffold is what the editor opens with: a section per heading with
+// a hint in a comment, and the prompt typed so far as the goal.
+func promptScaffold(prompt string) string {
+	var b strings.Builder
+	b.WriteString("<!-- Lines in comments like this one are removed. Sections left empty are dropped; save an empty file to cancel. -->\n\n")
+	for i, section := range scaffoldSections {
+		b.WriteString(section.heading + "\n")
+		b.WriteString("<!-- " + section.hint + " -->\n")
+		if i == 0 && strings.TrimSpace(prompt) != "" {
+			b.WriteString(strings.TrimSpace(prompt) + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// stripScaffold removes the scaffold's comments and the headings of sections
+// that were left empty, so an untouched scaffold gives an empty prompt.
+func stripScaffold(content string) string {
+	content = scaffoldCommentLines.ReplaceAllString(content, "")
+	lines := strings.Split(scaffoldComment.ReplaceAllString(content, ""), "\n")
+
+	var kept []string
+	for i := 0; i < len(lines); i++ {
+		if !isScaffoldHeading(lines[i]) {
+			kept = append(kept, strings.TrimRight(lines[i], " \t"))
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && !isScaffoldHeading(lines[end]) {
+			end++
+		}
+		if strings.TrimSpace(strings.Join(lines[i+1:end], "")) == "" {
+			i = end - 1
+			continue
+		}
+		kept = append(kept, strings.TrimSpace(lines[i]))
+	}
+
+	// Collapse the blank lines left behind by the dropped sections
+	prompt := blankLines.ReplaceAllString(strings.Join(kept, "\n"), "\n\n")
+	return strings.TrimSpace(prompt)
+}
+
+func isScaffoldHeading(line string) bool {
+	line = strings.TrimSpace(line)
+	for _, section := range scaffoldSections {
+		if line == section.heading {
+			return true
+		}
+	}
+	return false
+}
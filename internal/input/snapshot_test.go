@@ -0,0 +1,144 @@
+package input
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/saltpay/copycat/v2/internal/ai"
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// Run `go test ./internal/input -update` after an intended layout change and
+// review the golden files' diff.
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestMain(m *testing.M) {
+	// Snapshots hold the layout only, whatever terminal runs the tests
+	lipgloss.SetColorProfile(termenv.Ascii)
+	os.Exit(m.Run())
+}
+
+// snapshotSizes are the terminal sizes every view is checked at: a
+// half-height tmux pane, which gets compact layouts, the smallest full-size
+// terminal and a common large one.
+var snapshotSizes = []struct{ width, height int }{{80, 14}, {80, 24}, {120, 40}}
+
+// assertSnapshot compares view with testdata/<name>.golden.
+func assertSnapshot(t *testing.T, name, view string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(view), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if view != string(want) {
+		t.Errorf("%s changed (run with -update if intended)\n--- got\n%s\n--- want\n%s", path, view, want)
+	}
+}
+
+// send delivers msgs to the dashboard in turn. Keys are given by name, as
+// Headless.Press takes them. The commands returned are not run.
+func send(m dashboardModel, msgs ...any) dashboardModel {
+	for _, msg := range msgs {
+		if key, ok := msg.(string); ok {
+			msg = keyMsg(key)
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(dashboardModel)
+	}
+	return m
+}
+
+var snapshotProjects = []config.Project{
+	{Repo: "ledger-service", SlackRoom: "#team-ledger"},
+	{Repo: "payments-api", SlackRoom: "#team-payments"},
+	{Repo: "checkout-web"},
+}
+
+func snapshotConfig() DashboardConfig {
+	cfg := config.DefaultConfig("acme")
+	cfg.Tools = []config.AITool{{Name: "claude", Command: "claude"}}
+	cfg.Default = "claude"
+	return DashboardConfig{
+		Projects:      snapshotProjects,
+		AIToolsConfig: &cfg.AIToolsConfig,
+		GitHubConfig:  cfg.GitHub,
+		AppConfig:     *cfg,
+		Parallelism:   2,
+	}
+}
+
+// wizardAt opens the wizard on the snapshot projects at the given size.
+func wizardAt(width, height int) dashboardModel {
+	return send(newDashboardModel(snapshotConfig()),
+		tea.WindowSizeMsg{Width: width, Height: height},
+		projectsConfirmedMsg{Selected: snapshotProjects})
+}
+
+// processingAt shows a code change run part way through: one repo done, one
+// failed, one still running with live output.
+func processingAt(width, height int) dashboardModel {
+	m := wizardAt(width, height)
+	m.wizardResult = &WizardResult{Action: "local", AITool: &m.cfg.AIToolsConfig.Tools[0], BranchName: "copycat-bump-timeout", PRTitle: "Bump the HTTP timeout", Prompt: "Raise the HTTP timeout to 30s"}
+	m.selectedProjects = snapshotProjects
+	m.progress = NewProgressModel([]string{"ledger-service", "payments-api", "checkout-web"}, 0, m.wizardResult.BranchName, m.wizardResult.PRTitle, m.wizardResult.Prompt)
+	m.progress.termWidth = width
+	m.progress.termHeight = height
+	m.phase = phaseProcessing
+
+	usage := ai.Usage{InputTokens: 12000, OutputTokens: 900, CostUSD: 0.31}
+	m = send(m,
+		ProjectDoneMsg{Repo: "ledger-service", Status: "Completed ✅ PR: https://github.com/acme/ledger-service/pull/7 💲 " + usage.String(), Success: true, PRURL: "https://github.com/acme/ledger-service/pull/7", AIOutput: "Raised the timeout.", Usage: usage},
+		ProjectDoneMsg{Repo: "checkout-web", Status: "Failed ⚠️ AI tool failed: exit status 1", Error: errors.New("AI tool failed: exit status 1")},
+		ProjectStatusMsg{Repo: "payments-api", Status: "Running AI agent..."},
+		ProjectLogMsg{Repo: "payments-api", Line: "Reading internal/http/client.go"},
+		ProjectLogMsg{Repo: "payments-api", Line: "Editing internal/http/client.go"},
+	)
+	// Elapsed time renders as 0s
+	m.progress.startTime = time.Now()
+	return m
+}
+
+func TestWizardSnapshots(t *testing.T) {
+	for _, size := range snapshotSizes {
+		m := wizardAt(size.width, size.height)
+		assertSnapshot(t, fmt.Sprintf("wizard-action-%dx%d", size.width, size.height), m.View())
+
+		m = send(m, "enter", "enter")
+		for _, r := range "Bump the HTTP timeout" {
+			m = send(m, string(r))
+		}
+		m = send(m, "enter")
+		assertSnapshot(t, fmt.Sprintf("wizard-prompt-%dx%d", size.width, size.height), m.View())
+	}
+}
+
+func TestProgressSnapshots(t *testing.T) {
+	for _, size := range snapshotSizes {
+		m := processingAt(size.width, size.height)
+		assertSnapshot(t, fmt.Sprintf("progress-%dx%d", size.width, size.height), m.View())
+	}
+}
+
+func TestDoneSnapshots(t *testing.T) {
+	for _, size := range snapshotSizes {
+		m := processingAt(size.width, size.height)
+		m = send(m, ProjectDoneMsg{Repo: "payments-api", Status: "Skipped ⊘ no changes detected", Skipped: true, Error: errors.New("no changes detected")})
+		m = send(m, processingDoneMsg{})
+		assertSnapshot(t, fmt.Sprintf("done-%dx%d", size.width, size.height), m.View())
+	}
+}
@@ -1,13 +1,22 @@
 package input
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/history"
+	"github.com/saltpay/copycat/v2/internal/i18n"
+	"github.com/saltpay/copycat/v2/internal/issuetemplate"
 )
 
 // wizardCompletedMsg is emitted when the wizard finishes collecting all inputs.
@@ -29,18 +38,34 @@ const (
 	stepPRTitle
 	// Shared
 	stepPrompt
-	stepIgnoreInstructions
+	stepTempFiles
+	stepConfirm       // large runs only
+	stepPipeline      // "Run Actions" only
+	stepVerifyFailure // local changes with a verify command only
 )
 
 // WizardResult holds all values collected by the setup wizard.
 type WizardResult struct {
-	Action                  string // "local" or "assessment"
+	Action                  string // "local", "assessment" or "issues"
 	AITool                  *config.AITool
 	IgnoreAgentInstructions bool
+	TempChanges             []config.TempChange // undone before committing
 	BranchStrategy          string
 	BranchName              string
+	UpdatePR                bool // replace the title and description of a PR already open from the branch
 	PRTitle                 string
+	AutoMerge               bool // enable GitHub auto-merge on the PRs opened
+	PRLabels                []string
+	PRMilestone             string
+	Assignees               []string // users and org/team slugs the issues are assigned to
 	Prompt                  string
+	LicenseHeader           bool   // add license headers instead of running an AI tool
+	Actions                 string // action pipeline run before the AI tool, or instead of it without a prompt
+	TriageAlerts            bool   // give each repo its open security alerts to fix or dismiss
+	VerifyFailure           string // config.VerifyFailure*, when a verify command is configured
+	StructuredAssessment    bool   // ask each repo for a score, status and evidence
+	FlakyTests              bool   // assessment that reruns each repo's tests to find flaky ones
+	CampaignID              string // from -campaign, recorded with the run
 }
 
 type wizardModel struct {
@@ -50,19 +75,43 @@ type wizardModel struct {
 	// Action
 	actionOptions []string
 	actionCursor  int
-	action        string // "local" or "assessment"
+	action        string // "local", "assessment" or "issues"
+	licenseHeader bool   // "Add License Headers": a local change without AI
+	triageAlerts  bool   // "Triage Security Alerts": a local change fed the repo's alerts
+	structured    bool   // assessment asks for a structured answer
+	flakyTests    bool   // "Detect Flaky Tests": an assessment preset
+
+	// Action pipelines from config ("Run Actions")
+	pipelines      []config.ActionPipeline
+	pipelineCursor int
+	pipeline       *config.ActionPipeline
+	promptSkipped  bool // no prompt: the actions are the whole change
 
 	// AI Tool
 	aiTools      []config.AITool
 	aiToolCursor int
 	aiTool       *config.AITool
 	skipAITool   bool
+	aiToolNotice string // why the highlighted tool cannot be picked
+
+	// What to do when the verify command fails, asked when one is configured
+	verifyCommand bool
+	verifyCursor  int
+	verifyFailure string
+
+	// Confirmation of runs on more repos than confirmAbove (0: never)
+	confirmAbove    int
+	confirmInput    textinput.Model
+	confirmMismatch bool
 
-	// Ignore agent instructions
-	agentInstructions      []string
-	ignoreInstructions     bool
-	ignoreInstructionsSet  bool
-	skipIgnoreInstructions bool
+	// Temporary file changes, made for the AI step only
+	agentInstructions  []string
+	ignoreInstructions bool
+	tempChanges        []config.TempChange
+	tempFilesSet       bool
+	addingTempChange   bool
+	tempChangeInput    textinput.Model
+	tempChangeError    string
 
 	// Branch strategy
 	branchOptions  []string
@@ -73,41 +122,127 @@ type wizardModel struct {
 	branchNameInput textinput.Model
 	branchName      string
 	needsBranchName bool
+	updatePR        bool // toggled with tab on the branch name step when the branch is reused
 
 	// PR Title
 	prTitleInput textinput.Model
 	prTitle      string
+	autoMerge    bool // toggled with tab on the PR title step
+	prPolicy     config.PRPolicy
+	titleError   string // why the PR title breaks the policy
+
+	// PR labels and milestone, edited on the PR title step (ctrl+l moves focus)
+	prLabelsInput    textinput.Model
+	prMilestoneInput textinput.Model
+	prFocus          int // 0: title, 1: labels, 2: milestone, 3: assignees (issues only)
+	prLabels         []string
+	prMilestone      string
+
+	// Issue assignees, edited on the issue title step
+	assigneesInput textinput.Model
+	assignees      []string
 
 	// Prompt
-	promptInput textinput.Model
+	promptInput textarea.Model
 	prompt      string
 	useEditor   bool
 
+	// Previously used prompts offered for reuse (ctrl+r)
+	promptHistory []history.PromptEntry
+	pickingPrompt bool
+	promptCursor  int
+
+	// Prompt library from config (ctrl+t)
+	templates        []config.PromptTemplate
+	pickingTemplate  bool
+	templateCursor   int
+	template         *config.PromptTemplate // template whose variables are being filled in
+	templateVars     []string
+	templateValues   map[string]string
+	templateVarInput textinput.Model
+
+	// Issue templates, the first repo's and the configured ones (ctrl+t when
+	// creating issues). Their fields are filled in with templateVarInput.
+	issueTemplates       []issuetemplate.Template
+	issueTemplateNotice  string // why the repo's templates could not be read
+	fetchIssueTemplates  func() ([]issuetemplate.Template, error)
+	pickingIssueTemplate bool
+	issueTemplateCursor  int
+	issueTemplate        *issuetemplate.Template // template whose fields are being filled in
+	issueFieldValues     []string
+	issueFieldError      string
+
 	// State
-	termWidth int
+	termWidth  int
+	termHeight int
 }
 
-func newWizardModel(aiToolsConfig *config.AIToolsConfig, agentInstructions []string, selectedProjects []config.Project) wizardModel {
+// Action step options.
+const (
+	optionLocal         = "Perform Changes Locally"
+	optionAssessment    = "Run Assessment"
+	optionLicenseHeader = "Add License Headers"
+	optionTriageAlerts  = "Triage Security Alerts"
+	optionFlakyTests    = "Detect Flaky Tests"
+	optionActions       = "Run Actions"
+	optionIssues        = "Create GitHub Issues"
+)
+
+// triageAlertsPrompt is the starting prompt for alert triage; each repo's
+// alerts and reporting instructions are appended to it.
+const triageAlertsPrompt = "Fix the open security alerts listed below with minimal, behavior-preserving changes. Dismiss an alert only when you are confident it is a false positive or does not apply."
+
+// flakyTestsPrompt is the starting question for flaky test detection; the
+// rerun and reporting instructions are appended to it for each repo.
+const flakyTestsPrompt = "Which tests in this repository are flaky, passing on some runs and failing on others?"
+
+func newWizardModel(aiToolsConfig *config.AIToolsConfig, agentInstructions []string, selectedProjects []config.Project, licenseHeader bool) wizardModel {
 	branchInput := textinput.New()
 	branchInput.Placeholder = "my-branch-name"
 	branchInput.CharLimit = 256
 	branchInput.Width = 60
 
 	prTitleInput := textinput.New()
-	prTitleInput.Placeholder = "e.g., PROJ-123 - Update dependencies"
+	prTitleInput.Placeholder = i18n.T("e.g., PROJ-123 - Update dependencies")
 	prTitleInput.CharLimit = 256
 	prTitleInput.Width = 60
 
-	promptInput := textinput.New()
-	promptInput.Placeholder = "Describe the changes to apply to each repository"
-	promptInput.CharLimit = 2048
-	promptInput.Width = 60
+	promptInput := textarea.New()
+	promptInput.Placeholder = i18n.T("Describe the changes to apply to each repository")
+	promptInput.CharLimit = 0
+	promptInput.ShowLineNumbers = false
+	promptInput.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	promptInput.SetWidth(promptWidth(0))
+	promptInput.SetHeight(promptHeight(0))
+
+	prLabelsInput := textinput.New()
+	prLabelsInput.Placeholder = "automated, dependencies"
+	prLabelsInput.CharLimit = 256
+	prLabelsInput.Width = 50
+
+	prMilestoneInput := textinput.New()
+	prMilestoneInput.Placeholder = i18n.T("none")
+	prMilestoneInput.CharLimit = 256
+	prMilestoneInput.Width = 50
+
+	assigneesInput := textinput.New()
+	assigneesInput.Placeholder = i18n.T("none")
+	assigneesInput.CharLimit = 256
+	assigneesInput.Width = 50
+
+	tempChangeInput := textinput.New()
+	tempChangeInput.Placeholder = "remove docs/ARCHITECTURE.md"
+	tempChangeInput.CharLimit = 512
+	tempChangeInput.Width = 60
 
 	m := wizardModel{
 		selectedProjects: selectedProjects,
 		actionOptions: []string{
-			"Perform Changes Locally",
-			"Run Assessment",
+			optionLocal,
+			optionAssessment,
+			optionTriageAlerts,
+			optionFlakyTests,
+			optionIssues,
 		},
 		currentStep: stepAction,
 		aiTools:     aiToolsConfig.Tools,
@@ -116,9 +251,18 @@ func newWizardModel(aiToolsConfig *config.AIToolsConfig, agentInstructions []str
 			"Specify branch name (reuse if exists)",
 			"Specify branch name (skip if exists)",
 		},
-		branchNameInput: branchInput,
-		prTitleInput:    prTitleInput,
-		promptInput:     promptInput,
+		branchNameInput:   branchInput,
+		prTitleInput:      prTitleInput,
+		prLabelsInput:     prLabelsInput,
+		prMilestoneInput:  prMilestoneInput,
+		assigneesInput:    assigneesInput,
+		promptInput:       promptInput,
+		tempChangeInput:   tempChangeInput,
+		agentInstructions: agentInstructions,
+	}
+
+	if licenseHeader {
+		m.actionOptions = append(m.actionOptions, optionLicenseHeader)
 	}
 
 	if len(aiToolsConfig.Tools) <= 1 {
@@ -135,15 +279,51 @@ func newWizardModel(aiToolsConfig *config.AIToolsConfig, agentInstructions []str
 		}
 	}
 
-	if len(agentInstructions) == 0 {
-		m.skipIgnoreInstructions = true
-	} else {
-		m.agentInstructions = agentInstructions
+	return m
+}
+
+// withPipelines offers the configured action pipelines as an action.
+func (m wizardModel) withPipelines(pipelines []config.ActionPipeline) wizardModel {
+	if len(pipelines) > 0 {
+		m.pipelines = pipelines
+		m.actionOptions = append(m.actionOptions, optionActions)
 	}
+	return m
+}
 
+// withPRMetadata starts the PR labels and milestone from the configured ones.
+func (m wizardModel) withPRMetadata(meta config.PRMetadata) wizardModel {
+	m.prLabelsInput.SetValue(strings.Join(meta.Labels, ", "))
+	m.prMilestoneInput.SetValue(meta.Milestone)
 	return m
 }
 
+// withIssues starts the issue assignees from the configured ones and
+// offers the configured issue templates.
+func (m wizardModel) withIssues(issues config.Issues) wizardModel {
+	m.assigneesInput.SetValue(strings.Join(issues.DefaultAssignees(), ", "))
+	m.issueTemplates = issues.Templates
+	return m
+}
+
+// withVerifyCommand asks what to do when the verify command fails, if any
+// selected repo has one.
+func (m wizardModel) withVerifyCommand(cfg *config.Config) wizardModel {
+	for _, p := range m.selectedProjects {
+		if cfg.VerifyCommandFor(p) != "" {
+			m.verifyCommand = true
+			break
+		}
+	}
+	return m
+}
+
+// verifyFailureOptions are the choices for a failed verify command.
+var verifyFailureOptions = []struct{ value, label string }{
+	{config.VerifyFailureBlock, "Block PR creation"},
+	{config.VerifyFailureDraft, "Open a draft PR with a note"},
+}
+
 func (m wizardModel) Init() tea.Cmd {
 	return tea.ClearScreen
 }
@@ -152,6 +332,19 @@ func (m wizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.termWidth = msg.Width
+		m.termHeight = msg.Height
+		m.promptInput.SetWidth(promptWidth(msg.Width))
+		m.promptInput.SetHeight(promptHeight(msg.Height))
+		return m, nil
+	case issueTemplatesMsg:
+		if msg.Err != nil {
+			m.issueTemplateNotice = msg.Err.Error()
+		}
+		m.issueTemplates = append(msg.Templates, m.issueTemplates...)
+		if m.pickingIssueTemplate {
+			// stay on the template picked before they came
+			m.issueTemplateCursor += len(msg.Templates)
+		}
 		return m, nil
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
@@ -159,13 +352,25 @@ func (m wizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if m.pickingPrompt {
+		return m.updatePromptPicker(msg)
+	}
+	if m.pickingTemplate {
+		return m.updateTemplate(msg)
+	}
+	if m.pickingIssueTemplate {
+		return m.updateIssueTemplate(msg)
+	}
+
 	switch m.currentStep {
 	case stepAction:
 		return m.updateActionStep(msg)
+	case stepPipeline:
+		return m.updatePipelineStep(msg)
 	case stepAITool:
 		return m.updateAIToolStep(msg)
-	case stepIgnoreInstructions:
-		return m.updateIgnoreInstructionsStep(msg)
+	case stepTempFiles:
+		return m.updateTempFilesStep(msg)
 	case stepBranchStrategy:
 		return m.updateBranchStrategyStep(msg)
 	case stepBranchName:
@@ -174,11 +379,101 @@ func (m wizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updatePRTitleStep(msg)
 	case stepPrompt:
 		return m.updatePromptStep(msg)
+	case stepVerifyFailure:
+		return m.updateVerifyFailureStep(msg)
+	case stepConfirm:
+		return m.updateConfirmStep(msg)
 	}
 
 	return m, nil
 }
 
+// hasTemplates reports whether ctrl+t offers templates: issue templates
+// when creating issues, the prompt library otherwise.
+func (m wizardModel) hasTemplates() bool {
+	if m.action == "issues" {
+		return len(m.issueTemplates) > 0
+	}
+	return len(m.templates) > 0 && !m.licenseHeader
+}
+
+// needsConfirmation reports whether the run is large enough that the user
+// must type the confirmation phrase before it starts.
+func (m wizardModel) needsConfirmation() bool {
+	return m.confirmAbove > 0 && len(m.selectedProjects) > m.confirmAbove
+}
+
+// confirmPhrase is what the user types to confirm a large run: the campaign's
+// PR or issue title, or for assessments (which have none) the number of repos.
+func (m wizardModel) confirmPhrase() string {
+	if m.action == "local" || m.action == "issues" {
+		return m.prTitle
+	}
+	return strconv.Itoa(len(m.selectedProjects))
+}
+
+// complete finishes the wizard, first asking what to do when the verify
+// command fails and for confirmation if the run is large.
+func (m wizardModel) complete() (tea.Model, tea.Cmd) {
+	if m.action == "local" && m.verifyCommand && m.verifyFailure == "" {
+		m.currentStep = stepVerifyFailure
+		return m, nil
+	}
+	if m.needsConfirmation() && m.currentStep != stepConfirm {
+		m.confirmInput = textinput.New()
+		m.confirmInput.CharLimit = 256
+		m.confirmInput.Width = 60
+		m.confirmInput.Focus()
+		m.currentStep = stepConfirm
+		return m, textinput.Blink
+	}
+	return m, func() tea.Msg { return wizardCompletedMsg{Result: m.buildResult()} }
+}
+
+func (m wizardModel) updateVerifyFailureStep(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		if m.verifyCursor > 0 {
+			m.verifyCursor--
+		}
+	case "down", "j":
+		if m.verifyCursor < len(verifyFailureOptions)-1 {
+			m.verifyCursor++
+		}
+	case "enter":
+		m.verifyFailure = verifyFailureOptions[m.verifyCursor].value
+		return m.complete()
+	}
+	return m, nil
+}
+
+func (m wizardModel) updateConfirmStep(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if ok {
+		switch keyMsg.Type {
+		case tea.KeyEnter:
+			if strings.TrimSpace(m.confirmInput.Value()) != m.confirmPhrase() {
+				m.confirmMismatch = true
+				return m, nil
+			}
+			m.confirmInput.Blur()
+			return m.complete()
+		case tea.KeyEsc:
+			return m, tea.Quit
+		}
+	}
+	m.confirmMismatch = false
+	var cmd tea.Cmd
+	m.confirmInput, cmd = m.confirmInput.Update(msg)
+	return m, cmd
+}
+
 func (m wizardModel) updateActionStep(msg tea.Msg) (tea.Model, tea.Cmd) {
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if !ok {
@@ -196,28 +491,88 @@ func (m wizardModel) updateActionStep(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.actionCursor++
 		}
 	case "enter", " ":
-		switch m.actionCursor {
-		case 0:
+		switch m.actionOptions[m.actionCursor] {
+		case optionLocal, optionTriageAlerts:
 			m.action = "local"
+			if m.actionOptions[m.actionCursor] == optionTriageAlerts {
+				m.triageAlerts = true
+				m.promptInput.SetValue(triageAlertsPrompt)
+			}
 			if m.skipAITool {
 				m.currentStep = stepBranchStrategy
 			} else {
 				m.currentStep = stepAITool
 			}
-		case 1:
+		case optionAssessment, optionFlakyTests:
 			m.action = "assessment"
+			if m.actionOptions[m.actionCursor] == optionFlakyTests {
+				m.flakyTests = true
+				m.promptInput.SetValue(flakyTestsPrompt)
+			}
 			if m.skipAITool {
-				m.promptInput.Placeholder = "Enter your assessment question (e.g., Are these projects using circuit breakers?)"
+				m.promptInput.Placeholder = i18n.T("Enter your assessment question (e.g., Are these projects using circuit breakers?)")
 				m.promptInput.Focus()
 				m.currentStep = stepPrompt
-				return m, textinput.Blink
+				return m, textarea.Blink
 			}
 			m.currentStep = stepAITool
+		case optionLicenseHeader:
+			m.action = "local"
+			m.licenseHeader = true
+			m.currentStep = stepBranchStrategy
+		case optionIssues:
+			m.action = "issues"
+			m.prTitleInput.Placeholder = i18n.T("e.g., PROJ-123 - Enable Dependabot")
+			m.promptInput.Placeholder = i18n.T("Describe the issue to open in each repository")
+			m.prTitleInput.Focus()
+			m.currentStep = stepPRTitle
+			return m, tea.Batch(textinput.Blink, m.loadIssueTemplates())
+		case optionActions:
+			m.action = "local"
+			m.promptInput.Placeholder = i18n.T("Optionally describe changes for the AI tool to make after the actions")
+			if len(m.pipelines) == 1 {
+				return m.selectPipeline(0), nil
+			}
+			m.currentStep = stepPipeline
 		}
 	}
 	return m, nil
 }
 
+func (m wizardModel) updatePipelineStep(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "q":
+		return m, tea.Quit
+	case "up", "k":
+		if m.pipelineCursor > 0 {
+			m.pipelineCursor--
+		}
+	case "down", "j":
+		if m.pipelineCursor < len(m.pipelines)-1 {
+			m.pipelineCursor++
+		}
+	case "enter", " ":
+		return m.selectPipeline(m.pipelineCursor), nil
+	}
+	return m, nil
+}
+
+// selectPipeline picks the pipeline at i and moves on to the AI tool that
+// may follow it.
+func (m wizardModel) selectPipeline(i int) wizardModel {
+	m.pipeline = &m.pipelines[i]
+	if m.skipAITool {
+		m.currentStep = stepBranchStrategy
+	} else {
+		m.currentStep = stepAITool
+	}
+	return m
+}
+
 func (m wizardModel) updateAIToolStep(msg tea.Msg) (tea.Model, tea.Cmd) {
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if !ok {
@@ -235,19 +590,27 @@ func (m wizardModel) updateAIToolStep(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.aiToolCursor++
 		}
 	case "enter", " ":
+		if m.action == "local" && m.aiTools[m.aiToolCursor].IsAPI() {
+			m.aiToolNotice = i18n.T("%s calls an API and cannot change code", m.aiTools[m.aiToolCursor].Name)
+			return m, nil
+		}
+		m.aiToolNotice = ""
 		m.aiTool = &m.aiTools[m.aiToolCursor]
 		if m.action == "assessment" {
-			m.promptInput.Placeholder = "Enter your assessment question (e.g., Are these projects using circuit breakers?)"
+			m.promptInput.Placeholder = i18n.T("Enter your assessment question (e.g., Are these projects using circuit breakers?)")
 			m.promptInput.Focus()
 			m.currentStep = stepPrompt
-			return m, textinput.Blink
+			return m, textarea.Blink
 		}
 		m.currentStep = stepBranchStrategy
 	}
 	return m, nil
 }
 
-func (m wizardModel) updateIgnoreInstructionsStep(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m wizardModel) updateTempFilesStep(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.addingTempChange {
+		return m.updateTempChangeInput(msg)
+	}
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if !ok {
 		return m, nil
@@ -256,14 +619,83 @@ func (m wizardModel) updateIgnoreInstructionsStep(msg tea.Msg) (tea.Model, tea.C
 	case "q":
 		return m, tea.Quit
 	case " ":
-		m.ignoreInstructions = !m.ignoreInstructions
+		if len(m.agentInstructions) > 0 {
+			m.ignoreInstructions = !m.ignoreInstructions
+		}
+	case "a":
+		m.addingTempChange = true
+		m.tempChangeInput.SetValue("")
+		m.tempChangeInput.Focus()
+		return m, textinput.Blink
+	case "x":
+		if n := len(m.tempChanges); n > 0 {
+			m.tempChanges = m.tempChanges[:n-1]
+		}
 	case "enter":
-		m.ignoreInstructionsSet = true
-		return m, func() tea.Msg { return wizardCompletedMsg{Result: m.buildResult()} }
+		m.tempFilesSet = true
+		return m.complete()
 	}
 	return m, nil
 }
 
+func (m wizardModel) updateTempChangeInput(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEnter:
+			change, err := parseTempChange(m.tempChangeInput.Value())
+			if err != nil {
+				m.tempChangeError = err.Error()
+				return m, nil
+			}
+			m.tempChanges = append(m.tempChanges, change)
+			fallthrough
+		case tea.KeyEsc:
+			m.addingTempChange = false
+			m.tempChangeError = ""
+			m.tempChangeInput.Blur()
+			return m, nil
+		}
+	}
+	m.tempChangeError = ""
+	var cmd tea.Cmd
+	m.tempChangeInput, cmd = m.tempChangeInput.Update(msg)
+	return m, cmd
+}
+
+// parseTempChange parses "remove <path>" or "add <path> <local file>". The
+// local file is read now, so every repo gets the same content.
+func parseTempChange(line string) (config.TempChange, error) {
+	usage := errors.New("enter remove <path>, or add <path> <local file>")
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return config.TempChange{}, usage
+	}
+	path := filepath.ToSlash(filepath.Clean(fields[1]))
+	if !filepath.IsLocal(path) {
+		return config.TempChange{}, fmt.Errorf("%s is not a path inside the repo", fields[1])
+	}
+	switch {
+	case fields[0] == "remove" && len(fields) == 2:
+		return config.TempChange{Path: path, Remove: true}, nil
+	case (fields[0] == "add" || fields[0] == "replace") && len(fields) == 3:
+		source := fields[2]
+		local := source
+		if rest, ok := strings.CutPrefix(source, "~/"); ok {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return config.TempChange{}, err
+			}
+			local = filepath.Join(home, rest)
+		}
+		data, err := os.ReadFile(local)
+		if err != nil {
+			return config.TempChange{}, fmt.Errorf("cannot read %s: %v", source, err)
+		}
+		return config.TempChange{Path: path, Content: string(data), Source: source}, nil
+	}
+	return config.TempChange{}, usage
+}
+
 func (m wizardModel) updateBranchStrategyStep(msg tea.Msg) (tea.Model, tea.Cmd) {
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if !ok {
@@ -295,6 +727,12 @@ func (m wizardModel) updateBranchStrategyStep(msg tea.Msg) (tea.Model, tea.Cmd)
 	return m, nil
 }
 
+// reusesBranch reports whether the chosen branch strategy pushes onto an
+// existing branch, which may already have an open PR.
+func (m wizardModel) reusesBranch() bool {
+	return strings.Contains(m.branchStrategy, "reuse if exists")
+}
+
 func (m wizardModel) updateBranchNameStep(msg tea.Msg) (tea.Model, tea.Cmd) {
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if ok {
@@ -311,6 +749,11 @@ func (m wizardModel) updateBranchNameStep(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, textinput.Blink
 		case tea.KeyEsc:
 			return m, tea.Quit
+		case tea.KeyTab:
+			if m.reusesBranch() {
+				m.updatePR = !m.updatePR
+			}
+			return m, nil
 		}
 	}
 	var cmd tea.Cmd
@@ -327,42 +770,134 @@ func (m wizardModel) updatePRTitleStep(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if value == "" {
 				return m, nil
 			}
+			if m.action != "issues" {
+				if err := m.prPolicy.CheckTitle(value); err != nil {
+					m.titleError = err.Error()
+					return m, nil
+				}
+				m.titleError = ""
+			}
 			m.prTitle = value
+			m.prLabels = splitLabels(m.prLabelsInput.Value())
+			m.prMilestone = strings.TrimSpace(m.prMilestoneInput.Value())
 			m.prTitleInput.Blur()
+			m.prLabelsInput.Blur()
+			m.prMilestoneInput.Blur()
+			if m.action == "issues" {
+				m.assignees = splitLabels(m.assigneesInput.Value())
+				m.assigneesInput.Blur()
+			}
+			if m.licenseHeader {
+				return m.complete()
+			}
 			m.promptInput.Focus()
 			m.currentStep = stepPrompt
-			return m, textinput.Blink
+			return m, textarea.Blink
 		case tea.KeyEsc:
 			return m, tea.Quit
+		case tea.KeyCtrlR:
+			return m.openPromptPicker(), nil
+		case tea.KeyCtrlT:
+			if m.action == "issues" {
+				return m.openIssueTemplatePicker(), nil
+			}
+			return m.openTemplatePicker(), nil
+		case tea.KeyTab:
+			if m.action != "issues" {
+				m.autoMerge = !m.autoMerge
+			}
+			return m, nil
+		case tea.KeyCtrlL:
+			fields := 3
+			if m.action == "issues" {
+				fields = 4
+			}
+			m.prFocus = (m.prFocus + 1) % fields
+			m.prTitleInput.Blur()
+			m.prLabelsInput.Blur()
+			m.prMilestoneInput.Blur()
+			m.assigneesInput.Blur()
+			switch m.prFocus {
+			case 0:
+				m.prTitleInput.Focus()
+			case 1:
+				m.prLabelsInput.Focus()
+			case 2:
+				m.prMilestoneInput.Focus()
+			case 3:
+				m.assigneesInput.Focus()
+			}
+			return m, textinput.Blink
 		}
 	}
 	var cmd tea.Cmd
-	m.prTitleInput, cmd = m.prTitleInput.Update(msg)
+	switch m.prFocus {
+	case 1:
+		m.prLabelsInput, cmd = m.prLabelsInput.Update(msg)
+	case 2:
+		m.prMilestoneInput, cmd = m.prMilestoneInput.Update(msg)
+	case 3:
+		m.assigneesInput, cmd = m.assigneesInput.Update(msg)
+	default:
+		m.prTitleInput, cmd = m.prTitleInput.Update(msg)
+	}
 	return m, cmd
 }
 
+// splitLabels reads a comma-separated list of labels, dropping duplicates.
+func splitLabels(value string) []string {
+	var labels []string
+	for _, label := range strings.Split(value, ",") {
+		label = strings.TrimSpace(label)
+		if label != "" && !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
 func (m wizardModel) updatePromptStep(msg tea.Msg) (tea.Model, tea.Cmd) {
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if ok {
 		switch keyMsg.Type {
-		case tea.KeyEnter:
+		case tea.KeyCtrlS:
 			value := strings.TrimSpace(m.promptInput.Value())
+			if value == "" && m.pipeline != nil {
+				// Actions alone: no AI tool, so no instructions to ignore
+				m.promptSkipped = true
+				m.promptInput.Blur()
+				return m.complete()
+			}
 			if value == "" {
 				return m, nil
 			}
 			m.prompt = value
 			m.promptInput.Blur()
-			if !m.skipIgnoreInstructions {
-				m.currentStep = stepIgnoreInstructions
-				return m, nil
+			if m.action == "issues" {
+				// Nothing is cloned, so there are no files to change
+				return m.complete()
 			}
-			return m, func() tea.Msg { return wizardCompletedMsg{Result: m.buildResult()} }
+			m.currentStep = stepTempFiles
+			return m, nil
 		case tea.KeyEsc:
 			return m, tea.Quit
 		}
 		if keyMsg.String() == "ctrl+e" {
 			return m, func() tea.Msg { return editorRequestedMsg{} }
 		}
+		if keyMsg.String() == "ctrl+r" {
+			return m.openPromptPicker(), nil
+		}
+		if keyMsg.String() == "ctrl+t" {
+			if m.action == "issues" {
+				return m.openIssueTemplatePicker(), nil
+			}
+			return m.openTemplatePicker(), nil
+		}
+		if keyMsg.Type == tea.KeyTab && m.action == "assessment" && !m.flakyTests {
+			m.structured = !m.structured
+			return m, nil
+		}
 	}
 	var cmd tea.Cmd
 	m.promptInput, cmd = m.promptInput.Update(msg)
@@ -383,32 +918,47 @@ func (m wizardModel) View() string {
 	// Projects header
 	projectsHeader := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("206"))
 	b.WriteString(projectsHeader.Render(formatProjectsSummary(m.selectedProjects)))
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	if !compactAt(m.termHeight) {
+		b.WriteString("\n")
+	}
 
 	// Action
 	if m.action != "" {
 		var label string
 		switch m.action {
 		case "local":
-			label = "Perform Changes Locally"
+			label = optionLocal
+			if m.licenseHeader {
+				label = optionLicenseHeader
+			} else if m.pipeline != nil || m.currentStep == stepPipeline {
+				label = optionActions
+			} else if m.triageAlerts {
+				label = optionTriageAlerts
+			}
 		case "assessment":
-			label = "Run Assessment"
+			label = optionAssessment
+			if m.flakyTests {
+				label = optionFlakyTests
+			}
+		case "issues":
+			label = optionIssues
 		}
-		b.WriteString(completedStyle.Render(fmt.Sprintf("  ✓ Action: %s", label)))
+		b.WriteString(completedStyle.Render(i18n.T("  ✓ Action: %s", i18n.T(label))))
 		b.WriteString("\n")
 	} else {
-		b.WriteString(labelStyle.Render("  Action"))
+		b.WriteString(labelStyle.Render(i18n.T("  Action")))
 		b.WriteString("\n")
 		for i, option := range m.actionOptions {
 			if i == m.actionCursor {
-				b.WriteString(cursorStyle.Render(fmt.Sprintf("    > %s", option)))
+				b.WriteString(cursorStyle.Render(fmt.Sprintf("    > %s", i18n.T(option))))
 			} else {
-				b.WriteString(fmt.Sprintf("      %s", option))
+				b.WriteString(fmt.Sprintf("      %s", i18n.T(option)))
 			}
 			b.WriteString("\n")
 		}
 		b.WriteString("\n")
-		b.WriteString(helpStyle.Render("  ↑/↓: navigate • enter: select • q/ctrl+c: quit"))
+		b.WriteString(helpStyle.Render(i18n.T("  ↑/↓: navigate • enter: select • q/ctrl+c: quit")))
 		b.WriteString("\n")
 		return b.String()
 	}
@@ -419,19 +969,129 @@ func (m wizardModel) View() string {
 		m.viewLocalFields(&b, completedStyle, labelStyle, pendingStyle, cursorStyle, hintStyle)
 	case "assessment":
 		m.viewAssessmentFields(&b, completedStyle, labelStyle, pendingStyle, cursorStyle, hintStyle)
+	case "issues":
+		m.viewIssueFields(&b, completedStyle, labelStyle, pendingStyle, hintStyle)
+	}
+
+	// Failed verification (after every local path's last step)
+	if m.action == "local" && m.verifyCommand {
+		if m.verifyFailure != "" {
+			b.WriteString(completedStyle.Render(i18n.T("  ✓ If Verification Fails: %s", i18n.T(verifyFailureOptions[m.verifyCursor].label))))
+			b.WriteString("\n")
+		} else if m.currentStep == stepVerifyFailure {
+			b.WriteString(labelStyle.Render(i18n.T("  If Verification Fails")))
+			b.WriteString("\n")
+			b.WriteString(hintStyle.Render(i18n.T("    The verify command runs on each repo's changes before its PR is opened")))
+			b.WriteString("\n")
+			for i, option := range verifyFailureOptions {
+				if i == m.verifyCursor {
+					b.WriteString(cursorStyle.Render(fmt.Sprintf("    > %s", i18n.T(option.label))))
+				} else {
+					b.WriteString(fmt.Sprintf("      %s", i18n.T(option.label)))
+				}
+				b.WriteString("\n")
+			}
+		} else {
+			b.WriteString(pendingStyle.Render(i18n.T("  ○ If Verification Fails")))
+			b.WriteString("\n")
+		}
+	}
+
+	if m.currentStep == stepConfirm {
+		b.WriteString(labelStyle.Render(i18n.T("  Confirm Run on %d Repos", len(m.selectedProjects))))
+		b.WriteString("\n")
+		what := i18n.T("the PR title")
+		switch m.action {
+		case "issues":
+			what = i18n.T("the issue title")
+		case "assessment":
+			what = i18n.T("the number of repos")
+		}
+		b.WriteString(hintStyle.Render(i18n.T("    This run is larger than %d repos. Type %s (%s) to start it.", m.confirmAbove, what, m.confirmPhrase())))
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("    %s", m.confirmInput.View()))
+		b.WriteString("\n")
+		if m.confirmMismatch {
+			b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(i18n.T("    That does not match.")))
+			b.WriteString("\n")
+		}
+	}
+
+	if m.pickingPrompt {
+		b.WriteString("\n")
+		m.renderPromptPicker(&b, labelStyle, cursorStyle, hintStyle)
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(i18n.T("  ↑/↓: navigate • enter: reuse • esc: back")))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	if m.pickingTemplate {
+		b.WriteString("\n")
+		m.renderTemplatePicker(&b, labelStyle, cursorStyle, hintStyle)
+		b.WriteString("\n")
+		if m.template != nil {
+			b.WriteString(helpStyle.Render(i18n.T("  enter: next • esc: back")))
+		} else {
+			b.WriteString(helpStyle.Render(i18n.T("  ↑/↓: navigate • enter: use template • esc: back")))
+		}
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	if m.pickingIssueTemplate {
+		b.WriteString("\n")
+		m.renderIssueTemplatePicker(&b, labelStyle, cursorStyle, hintStyle)
+		b.WriteString("\n")
+		if m.issueTemplate != nil {
+			b.WriteString(helpStyle.Render(i18n.T("  enter: next • esc: back")))
+		} else {
+			b.WriteString(helpStyle.Render(i18n.T("  ↑/↓: navigate • enter: use template • esc: back")))
+		}
+		b.WriteString("\n")
+		return b.String()
 	}
 
 	// Help text
 	b.WriteString("\n")
+	reuse := ""
+	if len(m.reusablePrompts()) > 0 {
+		reuse = i18n.T(" • ctrl+r: reuse previous prompt")
+	}
+	if m.hasTemplates() {
+		reuse += i18n.T(" • ctrl+t: templates")
+	}
 	switch m.currentStep {
-	case stepAITool, stepBranchStrategy:
-		b.WriteString(helpStyle.Render("  ↑/↓: navigate • enter: select • q/ctrl+c: quit"))
-	case stepBranchName, stepPRTitle:
-		b.WriteString(helpStyle.Render("  enter: submit • esc/ctrl+c: quit"))
+	case stepPipeline, stepAITool, stepBranchStrategy, stepVerifyFailure:
+		b.WriteString(helpStyle.Render(i18n.T("  ↑/↓: navigate • enter: select • q/ctrl+c: quit")))
+	case stepBranchName:
+		if m.reusesBranch() {
+			b.WriteString(helpStyle.Render(i18n.T("  enter: submit • tab: update open PRs • esc/ctrl+c: quit")))
+		} else {
+			b.WriteString(helpStyle.Render(i18n.T("  enter: submit • esc/ctrl+c: quit")))
+		}
+	case stepPRTitle:
+		if m.action == "issues" {
+			b.WriteString(helpStyle.Render(i18n.T("  enter: submit • ctrl+l: labels/milestone") + reuse + i18n.T(" • esc/ctrl+c: quit")))
+		} else {
+			b.WriteString(helpStyle.Render(i18n.T("  enter: submit • tab: auto-merge • ctrl+l: labels/milestone") + reuse + i18n.T(" • esc/ctrl+c: quit")))
+		}
 	case stepPrompt:
-		b.WriteString(helpStyle.Render("  enter: submit • ctrl+e: open editor • esc/ctrl+c: quit"))
-	case stepIgnoreInstructions:
-		b.WriteString(helpStyle.Render("  space: toggle • enter: confirm • q/ctrl+c: quit"))
+		if m.action == "assessment" && !m.flakyTests {
+			reuse += i18n.T(" • tab: structured answer")
+		}
+		b.WriteString(helpStyle.Render(i18n.T("  ctrl+s: submit • enter: new line • ctrl+e: open editor") + reuse + i18n.T(" • esc/ctrl+c: quit")))
+	case stepTempFiles:
+		switch {
+		case m.addingTempChange:
+			b.WriteString(helpStyle.Render(i18n.T("  enter: add change • esc: cancel • ctrl+c: quit")))
+		case len(m.agentInstructions) > 0:
+			b.WriteString(helpStyle.Render(i18n.T("  space: toggle • a: add change • x: drop last change • enter: confirm • q/ctrl+c: quit")))
+		default:
+			b.WriteString(helpStyle.Render(i18n.T("  a: add change • x: drop last change • enter: confirm • q/ctrl+c: quit")))
+		}
+	case stepConfirm:
+		b.WriteString(helpStyle.Render(i18n.T("  enter: start run • esc/ctrl+c: quit")))
 	}
 	b.WriteString("\n")
 
@@ -439,16 +1099,37 @@ func (m wizardModel) View() string {
 }
 
 func (m wizardModel) viewLocalFields(b *strings.Builder, completed, label, pending, cursor, hint lipgloss.Style) {
+	// Action pipeline
+	if m.pipeline != nil {
+		b.WriteString(completed.Render(i18n.T("  ✓ Actions: %s (%d steps)", m.pipeline.Name, len(m.pipeline.Steps))))
+		b.WriteString("\n")
+	} else if m.currentStep == stepPipeline {
+		b.WriteString(label.Render(i18n.T("  Actions")))
+		b.WriteString("\n")
+		for i, p := range m.pipelines {
+			text := i18n.T("%s (%d steps)", p.Name, len(p.Steps))
+			if p.Description != "" {
+				text += " - " + p.Description
+			}
+			if i == m.pipelineCursor {
+				b.WriteString(cursor.Render(fmt.Sprintf("    > %s", text)))
+			} else {
+				b.WriteString(fmt.Sprintf("      %s", text))
+			}
+			b.WriteString("\n")
+		}
+	}
+
 	// AI Tool
-	if !m.skipAITool {
+	if !m.skipAITool && !m.licenseHeader {
 		if m.aiTool != nil {
-			b.WriteString(completed.Render(fmt.Sprintf("  ✓ AI Tool: %s (%s)", m.aiTool.Name, m.aiTool.Command)))
+			b.WriteString(completed.Render(i18n.T("  ✓ AI Tool: %s (%s)", m.aiTool.Name, m.aiTool.Describe())))
 			b.WriteString("\n")
 		} else if m.currentStep == stepAITool {
-			b.WriteString(label.Render("  AI Tool"))
+			b.WriteString(label.Render(i18n.T("  AI Tool")))
 			b.WriteString("\n")
 			for i, tool := range m.aiTools {
-				text := fmt.Sprintf("%s (%s)", tool.Name, tool.Command)
+				text := fmt.Sprintf("%s (%s)", tool.Name, tool.Describe())
 				if i == m.aiToolCursor {
 					b.WriteString(cursor.Render(fmt.Sprintf("    > %s", text)))
 				} else {
@@ -456,99 +1137,155 @@ func (m wizardModel) viewLocalFields(b *strings.Builder, completed, label, pendi
 				}
 				b.WriteString("\n")
 			}
+			if m.aiToolNotice != "" {
+				b.WriteString(hint.Render("    " + m.aiToolNotice))
+				b.WriteString("\n")
+			}
 		} else {
-			b.WriteString(pending.Render("  ○ AI Tool"))
+			b.WriteString(pending.Render(i18n.T("  ○ AI Tool")))
 			b.WriteString("\n")
 		}
 	}
 
 	// Branch Strategy
 	if m.branchStrategy != "" {
-		b.WriteString(completed.Render(fmt.Sprintf("  ✓ Branch: %s", m.branchStrategy)))
+		b.WriteString(completed.Render(i18n.T("  ✓ Branch: %s", i18n.T(m.branchStrategy))))
 		b.WriteString("\n")
 	} else if m.currentStep == stepBranchStrategy {
-		b.WriteString(label.Render("  Branch Strategy"))
+		b.WriteString(label.Render(i18n.T("  Branch Strategy")))
 		b.WriteString("\n")
 		for i, option := range m.branchOptions {
 			if i == m.branchCursor {
-				b.WriteString(cursor.Render(fmt.Sprintf("    > %s", option)))
+				b.WriteString(cursor.Render(fmt.Sprintf("    > %s", i18n.T(option))))
 			} else {
-				b.WriteString(fmt.Sprintf("      %s", option))
+				b.WriteString(fmt.Sprintf("      %s", i18n.T(option)))
 			}
 			b.WriteString("\n")
 		}
 	} else {
-		b.WriteString(pending.Render("  ○ Branch Strategy"))
+		b.WriteString(pending.Render(i18n.T("  ○ Branch Strategy")))
 		b.WriteString("\n")
 	}
 
 	// Branch Name (conditional)
 	if m.needsBranchName {
 		if m.branchName != "" {
-			b.WriteString(completed.Render(fmt.Sprintf("  ✓ Branch Name: %s", m.branchName)))
+			b.WriteString(completed.Render(i18n.T("  ✓ Branch Name: %s", m.branchName)))
 			b.WriteString("\n")
+			if m.updatePR {
+				b.WriteString(completed.Render(i18n.T("  ✓ Open PRs: title and description updated")))
+				b.WriteString("\n")
+			}
 		} else if m.currentStep == stepBranchName {
-			b.WriteString(label.Render("  Branch Name"))
+			b.WriteString(label.Render(i18n.T("  Branch Name")))
 			b.WriteString("\n")
 			b.WriteString(fmt.Sprintf("    %s", m.branchNameInput.View()))
 			b.WriteString("\n")
+			if m.reusesBranch() {
+				check := "[ ]"
+				if m.updatePR {
+					check = "[x]"
+				}
+				b.WriteString(hint.Render(i18n.T("    %s Update the title and description of PRs already open from this branch", check)))
+				b.WriteString("\n")
+			}
 		} else {
-			b.WriteString(pending.Render("  ○ Branch Name"))
+			b.WriteString(pending.Render(i18n.T("  ○ Branch Name")))
 			b.WriteString("\n")
 		}
 	}
 
 	// PR Title
 	if m.prTitle != "" {
-		b.WriteString(completed.Render(fmt.Sprintf("  ✓ PR Title: %s", m.prTitle)))
+		b.WriteString(completed.Render(i18n.T("  ✓ PR Title: %s", m.prTitle)))
 		b.WriteString("\n")
+		if m.autoMerge {
+			b.WriteString(completed.Render(i18n.T("  ✓ Auto-merge: squash once branch protection passes")))
+			b.WriteString("\n")
+		}
+		if len(m.prLabels) > 0 {
+			b.WriteString(completed.Render(i18n.T("  ✓ Labels: %s", strings.Join(m.prLabels, ", "))))
+			b.WriteString("\n")
+		}
+		if m.prMilestone != "" {
+			b.WriteString(completed.Render(i18n.T("  ✓ Milestone: %s", m.prMilestone)))
+			b.WriteString("\n")
+		}
 	} else if m.currentStep == stepPRTitle {
-		b.WriteString(label.Render("  PR Title"))
+		b.WriteString(label.Render(i18n.T("  PR Title")))
 		b.WriteString("\n")
-		b.WriteString(hint.Render("    You may include a ticket reference (e.g., PROJ-123 - Description)"))
+		if m.prPolicy.TitleHint != "" {
+			b.WriteString(hint.Render(i18n.T("    PR titles must %s", m.prPolicy.TitleHint)))
+		} else {
+			b.WriteString(hint.Render(i18n.T("    You may include a ticket reference (e.g., PROJ-123 - Description)")))
+		}
 		b.WriteString("\n")
 		b.WriteString(fmt.Sprintf("    %s", m.prTitleInput.View()))
 		b.WriteString("\n")
+		if m.titleError != "" {
+			b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("    " + m.titleError))
+			b.WriteString("\n")
+		}
+		check := "[ ]"
+		if m.autoMerge {
+			check = "[x]"
+		}
+		b.WriteString(hint.Render(i18n.T("    %s Enable auto-merge (squash) once branch protection passes", check)))
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("    %s %s\n", hint.Render(fmt.Sprintf("%-10s", i18n.T("Labels:"))), m.prLabelsInput.View()))
+		b.WriteString(fmt.Sprintf("    %s %s\n", hint.Render(fmt.Sprintf("%-10s", i18n.T("Milestone:"))), m.prMilestoneInput.View()))
 	} else {
-		b.WriteString(pending.Render("  ○ PR Title"))
+		b.WriteString(pending.Render(i18n.T("  ○ PR Title")))
 		b.WriteString("\n")
 	}
 
+	// License headers need no prompt
+	if m.licenseHeader {
+		return
+	}
+
 	// Prompt
+	if m.promptSkipped {
+		b.WriteString(completed.Render(i18n.T("  ✓ Prompt: none, actions only")))
+		b.WriteString("\n")
+		return
+	}
 	if m.prompt != "" && m.currentStep != stepPrompt {
-		display := m.prompt
+		display := strings.Join(strings.Fields(m.prompt), " ")
 		if len(display) > 60 {
 			display = display[:57] + "..."
 		}
-		b.WriteString(completed.Render(fmt.Sprintf("  ✓ Prompt: %s", display)))
+		b.WriteString(completed.Render(i18n.T("  ✓ Prompt: %s", display)))
 		b.WriteString("\n")
 	} else if m.currentStep == stepPrompt {
-		b.WriteString(label.Render("  Prompt"))
+		b.WriteString(label.Render(i18n.T("  Prompt")))
 		b.WriteString("\n")
-		b.WriteString(fmt.Sprintf("    %s", m.promptInput.View()))
+		b.WriteString(indentLines(m.promptInput.View(), "    "))
 		b.WriteString("\n")
+		if m.pipeline != nil {
+			b.WriteString(hint.Render(i18n.T("    Optional: submit it empty to only run the actions")))
+			b.WriteString("\n")
+		}
 	} else {
-		b.WriteString(pending.Render("  ○ Prompt"))
+		b.WriteString(pending.Render(i18n.T("  ○ Prompt")))
 		b.WriteString("\n")
 	}
 
-	// Ignore Agent Instructions (after prompt)
-	if !m.skipIgnoreInstructions {
-		m.viewIgnoreInstructions(b, completed, label, pending, cursor, hint)
-	}
+	// Temporary File Changes (after prompt)
+	m.viewTempFiles(b, completed, label, pending, cursor, hint)
 }
 
 func (m wizardModel) viewAssessmentFields(b *strings.Builder, completed, label, pending, cursor, hint lipgloss.Style) {
 	// AI Tool
 	if !m.skipAITool {
 		if m.aiTool != nil {
-			b.WriteString(completed.Render(fmt.Sprintf("  ✓ AI Tool: %s (%s)", m.aiTool.Name, m.aiTool.Command)))
+			b.WriteString(completed.Render(i18n.T("  ✓ AI Tool: %s (%s)", m.aiTool.Name, m.aiTool.Describe())))
 			b.WriteString("\n")
 		} else if m.currentStep == stepAITool {
-			b.WriteString(label.Render("  AI Tool"))
+			b.WriteString(label.Render(i18n.T("  AI Tool")))
 			b.WriteString("\n")
 			for i, tool := range m.aiTools {
-				text := fmt.Sprintf("%s (%s)", tool.Name, tool.Command)
+				text := fmt.Sprintf("%s (%s)", tool.Name, tool.Describe())
 				if i == m.aiToolCursor {
 					b.WriteString(cursor.Render(fmt.Sprintf("    > %s", text)))
 				} else {
@@ -557,72 +1294,180 @@ func (m wizardModel) viewAssessmentFields(b *strings.Builder, completed, label,
 				b.WriteString("\n")
 			}
 		} else {
-			b.WriteString(pending.Render("  ○ AI Tool"))
+			b.WriteString(pending.Render(i18n.T("  ○ AI Tool")))
 			b.WriteString("\n")
 		}
 	}
 
 	// Prompt
 	if m.prompt != "" && m.currentStep != stepPrompt {
-		display := m.prompt
+		display := strings.Join(strings.Fields(m.prompt), " ")
 		if len(display) > 60 {
 			display = display[:57] + "..."
 		}
-		b.WriteString(completed.Render(fmt.Sprintf("  ✓ Question: %s", display)))
+		b.WriteString(completed.Render(i18n.T("  ✓ Question: %s", display)))
 		b.WriteString("\n")
+		if m.structured {
+			b.WriteString(completed.Render(i18n.T("  ✓ Structured Answer: score, status, evidence")))
+			b.WriteString("\n")
+		}
 	} else if m.currentStep == stepPrompt {
-		b.WriteString(label.Render("  Assessment Question"))
+		b.WriteString(label.Render(i18n.T("  Assessment Question")))
+		b.WriteString("\n")
+		b.WriteString(indentLines(m.promptInput.View(), "    "))
 		b.WriteString("\n")
-		b.WriteString(fmt.Sprintf("    %s", m.promptInput.View()))
+		if m.flakyTests {
+			b.WriteString(hint.Render(i18n.T("    Each repo's test suite is rerun to find tests that pass and fail")))
+		} else {
+			check := "[ ]"
+			if m.structured {
+				check = "[x]"
+			}
+			b.WriteString(hint.Render(i18n.T("    %s Ask for a structured answer (score, status, evidence)", check)))
+		}
 		b.WriteString("\n")
 	} else {
-		b.WriteString(pending.Render("  ○ Assessment Question"))
+		b.WriteString(pending.Render(i18n.T("  ○ Assessment Question")))
 		b.WriteString("\n")
 	}
 
-	// Ignore Agent Instructions (after prompt)
-	if !m.skipIgnoreInstructions {
-		m.viewIgnoreInstructions(b, completed, label, pending, cursor, hint)
-	}
+	// Temporary File Changes (after prompt)
+	m.viewTempFiles(b, completed, label, pending, cursor, hint)
 }
 
-func (m wizardModel) viewIgnoreInstructions(b *strings.Builder, completed, label, pending, cursor, hint lipgloss.Style) {
-	if m.ignoreInstructionsSet {
-		val := "No"
-		if m.ignoreInstructions {
-			val = "Yes"
+func (m wizardModel) viewIssueFields(b *strings.Builder, completed, label, pending, hint lipgloss.Style) {
+	// Issue Title
+	if m.prTitle != "" {
+		b.WriteString(completed.Render(i18n.T("  ✓ Issue Title: %s", m.prTitle)))
+		b.WriteString("\n")
+		if len(m.prLabels) > 0 {
+			b.WriteString(completed.Render(i18n.T("  ✓ Labels: %s", strings.Join(m.prLabels, ", "))))
+			b.WriteString("\n")
+		}
+		if m.prMilestone != "" {
+			b.WriteString(completed.Render(i18n.T("  ✓ Milestone: %s", m.prMilestone)))
+			b.WriteString("\n")
 		}
-		b.WriteString(completed.Render(fmt.Sprintf("  ✓ Ignore Agent Instructions: %s", val)))
+		b.WriteString(completed.Render(i18n.T("  ✓ Assignees: %s", assigneeList(m.assignees))))
 		b.WriteString("\n")
-	} else if m.currentStep == stepIgnoreInstructions {
-		b.WriteString(label.Render("  Ignore Agent Instructions"))
+	} else if m.currentStep == stepPRTitle {
+		b.WriteString(label.Render(i18n.T("  Issue Title")))
 		b.WriteString("\n")
-		check := "[ ]"
-		if m.ignoreInstructions {
-			check = "[x]"
+		b.WriteString(hint.Render(i18n.T("    You may include a ticket reference (e.g., PROJ-123 - Description)")))
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("    %s", m.prTitleInput.View()))
+		b.WriteString("\n")
+		b.WriteString(fmt.Sprintf("    %s %s\n", hint.Render(fmt.Sprintf("%-10s", i18n.T("Labels:"))), m.prLabelsInput.View()))
+		b.WriteString(fmt.Sprintf("    %s %s\n", hint.Render(fmt.Sprintf("%-10s", i18n.T("Milestone:"))), m.prMilestoneInput.View()))
+		b.WriteString(fmt.Sprintf("    %s %s\n", hint.Render(fmt.Sprintf("%-10s", i18n.T("Assignees:"))), m.assigneesInput.View()))
+		b.WriteString(hint.Render(i18n.T("    Users or org/team slugs; copilot hands the issues to Copilot's coding agent")))
+		b.WriteString("\n")
+		if m.issueTemplateNotice != "" {
+			b.WriteString(hint.Render(i18n.T("    ⚠ Repo issue templates unavailable: %s", m.issueTemplateNotice)))
+			b.WriteString("\n")
+		}
+	} else {
+		b.WriteString(pending.Render(i18n.T("  ○ Issue Title")))
+		b.WriteString("\n")
+	}
+
+	// Description
+	if m.currentStep == stepPrompt {
+		b.WriteString(label.Render(i18n.T("  Issue Description")))
+		b.WriteString("\n")
+		b.WriteString(indentLines(m.promptInput.View(), "    "))
+		b.WriteString("\n")
+	} else if m.prompt != "" {
+		display := strings.Join(strings.Fields(m.prompt), " ")
+		if len(display) > 60 {
+			display = display[:57] + "..."
 		}
-		b.WriteString(cursor.Render(fmt.Sprintf("    > %s Ignore agent instructions in target repos", check)))
+		b.WriteString(completed.Render(i18n.T("  ✓ Description: %s", display)))
 		b.WriteString("\n")
-		b.WriteString(hint.Render(fmt.Sprintf("      %s", strings.Join(m.agentInstructions, ", "))))
+	} else {
+		b.WriteString(pending.Render(i18n.T("  ○ Issue Description")))
 		b.WriteString("\n")
+	}
+}
+
+// assigneeList shows assignees as mentions, or "none".
+func assigneeList(assignees []string) string {
+	if len(assignees) == 0 {
+		return i18n.T("none")
+	}
+	mentions := make([]string, len(assignees))
+	for i, a := range assignees {
+		mentions[i] = "@" + strings.TrimPrefix(a, "@")
+	}
+	return strings.Join(mentions, ", ")
+}
+
+func (m wizardModel) viewTempFiles(b *strings.Builder, completed, label, pending, cursor, hint lipgloss.Style) {
+	if m.tempFilesSet {
+		b.WriteString(completed.Render(i18n.T("  ✓ Temporary File Changes: %s", m.tempFilesSummary())))
+		b.WriteString("\n")
+	} else if m.currentStep == stepTempFiles {
+		b.WriteString(label.Render(i18n.T("  Temporary File Changes")))
+		b.WriteString("\n")
+		b.WriteString(hint.Render(i18n.T("    Made before the AI step and undone before committing")))
+		b.WriteString("\n")
+		if len(m.agentInstructions) > 0 {
+			check := "[ ]"
+			if m.ignoreInstructions {
+				check = "[x]"
+			}
+			b.WriteString(cursor.Render(i18n.T("    > %s Ignore agent instructions in target repos", check)))
+			b.WriteString("\n")
+			b.WriteString(hint.Render(fmt.Sprintf("      %s", strings.Join(m.agentInstructions, ", "))))
+			b.WriteString("\n")
+		}
+		for _, c := range m.tempChanges {
+			b.WriteString(fmt.Sprintf("      %s", c))
+			b.WriteString("\n")
+		}
+		if m.addingTempChange {
+			b.WriteString("    " + m.tempChangeInput.View())
+			b.WriteString("\n")
+			text := i18n.T("remove <path> • add <path> <local file> • replace <path> <local file>")
+			if m.tempChangeError != "" {
+				text = m.tempChangeError
+			}
+			b.WriteString(hint.Render("    " + text))
+			b.WriteString("\n")
+		}
 	} else {
-		b.WriteString(pending.Render("  ○ Ignore Agent Instructions"))
+		b.WriteString(pending.Render(i18n.T("  ○ Temporary File Changes")))
 		b.WriteString("\n")
 	}
 }
 
+// tempFilesSummary lists the confirmed temporary file changes.
+func (m wizardModel) tempFilesSummary() string {
+	var parts []string
+	if m.ignoreInstructions {
+		parts = append(parts, i18n.T("agent instructions ignored"))
+	}
+	for _, c := range m.tempChanges {
+		parts = append(parts, c.String())
+	}
+	if len(parts) == 0 {
+		return i18n.T("None")
+	}
+	return strings.Join(parts, ", ")
+}
+
 func formatProjectsSummary(projects []config.Project) string {
 	if len(projects) == 0 {
-		return "No projects selected"
+		return i18n.T("No projects selected")
 	}
 	names := make([]string, 0, len(projects))
 	for _, p := range projects {
-		names = append(names, p.Repo)
+		names = append(names, p.Key())
 	}
 	if len(names) <= 3 {
-		return fmt.Sprintf("%d project(s): %s", len(names), strings.Join(names, ", "))
+		return i18n.T("%d project(s): %s", len(names), strings.Join(names, ", "))
 	}
-	return fmt.Sprintf("%d projects: %s, +%d more", len(names), strings.Join(names[:3], ", "), len(names)-3)
+	return i18n.T("%d projects: %s, +%d more", len(names), strings.Join(names[:3], ", "), len(names)-3)
 }
 
 func (m wizardModel) buildResult() WizardResult {
@@ -630,9 +1475,54 @@ func (m wizardModel) buildResult() WizardResult {
 		Action:                  m.action,
 		AITool:                  m.aiTool,
 		IgnoreAgentInstructions: m.ignoreInstructions,
+		TempChanges:             m.tempChanges,
 		BranchStrategy:          m.branchStrategy,
 		BranchName:              m.branchName,
 		PRTitle:                 m.prTitle,
+		AutoMerge:               m.autoMerge,
+		PRLabels:                m.prLabels,
+		PRMilestone:             m.prMilestone,
+		Assignees:               m.assignees,
+		UpdatePR:                m.updatePR,
 		Prompt:                  m.prompt,
+		LicenseHeader:           m.licenseHeader,
+		Actions:                 m.pipelineName(),
+		StructuredAssessment:    m.structured,
+		TriageAlerts:            m.triageAlerts,
+		VerifyFailure:           m.verifyFailure,
+		FlakyTests:              m.flakyTests,
+	}
+}
+
+func (m wizardModel) pipelineName() string {
+	if m.pipeline == nil {
+		return ""
+	}
+	return m.pipeline.Name
+}
+
+// promptWidth sizes the prompt textarea to the terminal, within sensible bounds.
+func promptWidth(termWidth int) int {
+	width := termWidth - 8
+	if width < 40 {
+		width = 60
+	}
+	if width > 100 {
+		width = 100
 	}
+	return width
+}
+
+// promptHeight is the number of lines the prompt textarea shows, fewer in
+// compact layouts.
+func promptHeight(termHeight int) int {
+	if compactAt(termHeight) {
+		return 3
+	}
+	return 6
+}
+
+// indentLines prefixes every line of s with indent.
+func indentLines(s, indent string) string {
+	return indent + strings.ReplaceAll(s, "\n", "\n"+indent)
 }
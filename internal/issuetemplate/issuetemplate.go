@@ -0,0 +1,343 @@
+// Package issuetemplate reads GitHub issue templates: Markdown templates with
+// YAML frontmatter and YAML issue forms. Copycat-configured templates take the
+// same shape as an issue form, or a Markdown template's frontmatter with its
+// body inline.
+package issuetemplate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Field types of an issue form that are filled in. Markdown elements only
+// guide whoever files the issue and are dropped.
+const (
+	FieldInput      = "input"
+	FieldTextarea   = "textarea"
+	FieldDropdown   = "dropdown"
+	FieldCheckboxes = "checkboxes"
+)
+
+// noResponse is what GitHub writes under a form field left empty.
+const noResponse = "_No response_"
+
+// Template is an issue template.
+type Template struct {
+	Name      string
+	About     string
+	Title     string // starts the issue title, e.g. "[Bug]: "
+	Labels    []string
+	Assignees []string
+	Body      string  // Markdown templates: the description to start from
+	Fields    []Field // issue forms: the fields, in order
+	Source    string  // the repo it was read from; empty when configured
+}
+
+// Field is a field of an issue form.
+type Field struct {
+	Type        string
+	ID          string
+	Label       string
+	Description string
+	Placeholder string
+	Value       string // filled in to start with
+	Options     []string
+	Required    bool
+}
+
+// Asked reports whether the field is asked for one value. Checkboxes are
+// written into the description unticked, to be ticked there.
+func (f Field) Asked() bool {
+	return f.Type != FieldCheckboxes
+}
+
+// Check returns the value to use for value, or an error when it is missing
+// for a required field or is not one of a dropdown's options. A dropdown
+// option may also be picked by its number.
+func (f Field) Check(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		if f.Required {
+			return "", fmt.Errorf("%s is required", f.Label)
+		}
+		return "", nil
+	}
+	if f.Type != FieldDropdown {
+		return value, nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 1 && n <= len(f.Options) {
+		return f.Options[n-1], nil
+	}
+	for _, option := range f.Options {
+		if strings.EqualFold(option, value) {
+			return option, nil
+		}
+	}
+	return "", fmt.Errorf("%s must be one of %s", f.Label, strings.Join(f.Options, ", "))
+}
+
+// Asked returns the fields asked for a value, in order.
+func (t Template) Asked() []Field {
+	var asked []Field
+	for _, f := range t.Fields {
+		if f.Asked() {
+			asked = append(asked, f)
+		}
+	}
+	return asked
+}
+
+// Render returns the issue description: a Markdown template's body, or an
+// issue form's fields under their labels as GitHub writes them, with values
+// those of the asked fields in order.
+func (t Template) Render(values []string) string {
+	if len(t.Fields) == 0 {
+		return t.Body
+	}
+	var b strings.Builder
+	asked := 0
+	for _, f := range t.Fields {
+		fmt.Fprintf(&b, "### %s\n\n", f.Label)
+		if !f.Asked() {
+			for _, option := range f.Options {
+				fmt.Fprintf(&b, "- [ ] %s\n", option)
+			}
+			b.WriteString("\n")
+			continue
+		}
+		value := ""
+		if asked < len(values) {
+			value = values[asked]
+		}
+		asked++
+		if value == "" {
+			value = noResponse
+		}
+		b.WriteString(value + "\n\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Parse reads the issue template in the file called name: Markdown with
+// frontmatter, or a YAML issue form.
+func Parse(name string, data []byte) (Template, error) {
+	ext := path.Ext(name)
+	var t Template
+	switch ext {
+	case ".md":
+		frontmatter, body := splitFrontmatter(data)
+		if err := yaml.Unmarshal(frontmatter, &t); err != nil {
+			return Template{}, fmt.Errorf("invalid frontmatter in %s: %w", name, err)
+		}
+		t.Body = strings.TrimSpace(string(body))
+	case ".yml", ".yaml":
+		if err := yaml.Unmarshal(data, &t); err != nil {
+			return Template{}, fmt.Errorf("invalid issue form %s: %w", name, err)
+		}
+		if len(t.Fields) == 0 {
+			return Template{}, fmt.Errorf("issue form %s has no fields", name)
+		}
+	default:
+		return Template{}, fmt.Errorf("%s is not an issue template", name)
+	}
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(path.Base(name), ext)
+	}
+	return t, nil
+}
+
+// IsTemplateFile reports whether a file in .github/ISSUE_TEMPLATE is a
+// template, rather than the template chooser's config.yml.
+func IsTemplateFile(name string) bool {
+	base := path.Base(name)
+	if base == "config.yml" || base == "config.yaml" {
+		return false
+	}
+	return slices.Contains([]string{".md", ".yml", ".yaml"}, path.Ext(base))
+}
+
+// splitFrontmatter splits a Markdown file into its YAML frontmatter, if it
+// has any, and the rest.
+func splitFrontmatter(data []byte) (frontmatter, body []byte) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(data, []byte("---\n")) {
+		return nil, data
+	}
+	rest := data[len("---\n"):]
+	end := bytes.Index(rest, []byte("\n---"))
+	if end < 0 {
+		return nil, data
+	}
+	body = rest[end+len("\n---"):]
+	if i := bytes.IndexByte(body, '\n'); i >= 0 {
+		body = body[i+1:]
+	} else {
+		body = nil
+	}
+	return rest[:end], body
+}
+
+// list is a YAML list that may also be written as a comma-separated string,
+// as labels and assignees are in Markdown templates.
+type list []string
+
+func (l *list) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*l = nil
+		for _, item := range strings.Split(value.Value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*l = append(*l, item)
+			}
+		}
+		return nil
+	}
+	var items []string
+	if err := value.Decode(&items); err != nil {
+		return err
+	}
+	*l = items
+	return nil
+}
+
+// document is a template as written: a Markdown template's frontmatter, or
+// an issue form. Body is the form's elements, or a configured Markdown body.
+type document struct {
+	Name        string    `yaml:"name"`
+	About       string    `yaml:"about,omitempty"`
+	Description string    `yaml:"description,omitempty"` // issue forms' about
+	Title       string    `yaml:"title,omitempty"`
+	Labels      list      `yaml:"labels,omitempty"`
+	Assignees   list      `yaml:"assignees,omitempty"`
+	Body        yaml.Node `yaml:"body,omitempty"`
+}
+
+type element struct {
+	Type       string `yaml:"type"`
+	ID         string `yaml:"id,omitempty"`
+	Attributes struct {
+		Label       string   `yaml:"label,omitempty"`
+		Description string   `yaml:"description,omitempty"`
+		Placeholder string   `yaml:"placeholder,omitempty"`
+		Value       string   `yaml:"value,omitempty"`
+		Options     []option `yaml:"options,omitempty"`
+	} `yaml:"attributes"`
+	Validations struct {
+		Required bool `yaml:"required,omitempty"`
+	} `yaml:"validations,omitempty"`
+}
+
+// option is a dropdown option, or a checkbox written as {label: ...}.
+type option struct {
+	Label string `yaml:"label"`
+}
+
+func (o *option) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		o.Label = value.Value
+		return nil
+	}
+	type plain option
+	return value.Decode((*plain)(o))
+}
+
+func (t *Template) UnmarshalYAML(value *yaml.Node) error {
+	var doc document
+	if err := value.Decode(&doc); err != nil {
+		return err
+	}
+	*t = Template{
+		Name:      doc.Name,
+		About:     doc.About,
+		Title:     doc.Title,
+		Labels:    doc.Labels,
+		Assignees: doc.Assignees,
+	}
+	if t.About == "" {
+		t.About = doc.Description
+	}
+
+	switch doc.Body.Kind {
+	case 0:
+	case yaml.ScalarNode:
+		t.Body = strings.TrimSpace(doc.Body.Value)
+	case yaml.SequenceNode:
+		var elements []element
+		if err := doc.Body.Decode(&elements); err != nil {
+			return err
+		}
+		for i, e := range elements {
+			if e.Type == "markdown" {
+				continue
+			}
+			if !slices.Contains([]string{FieldInput, FieldTextarea, FieldDropdown, FieldCheckboxes}, e.Type) {
+				return fmt.Errorf("field %d of %q has unknown type %q", i+1, doc.Name, e.Type)
+			}
+			if e.Attributes.Label == "" {
+				return fmt.Errorf("field %d of %q has no label", i+1, doc.Name)
+			}
+			f := Field{
+				Type:        e.Type,
+				ID:          e.ID,
+				Label:       e.Attributes.Label,
+				Description: e.Attributes.Description,
+				Placeholder: e.Attributes.Placeholder,
+				Value:       e.Attributes.Value,
+				Required:    e.Validations.Required,
+			}
+			for _, o := range e.Attributes.Options {
+				f.Options = append(f.Options, o.Label)
+			}
+			if (f.Type == FieldDropdown || f.Type == FieldCheckboxes) && len(f.Options) == 0 {
+				return fmt.Errorf("field %q of %q has no options", f.Label, doc.Name)
+			}
+			t.Fields = append(t.Fields, f)
+		}
+	default:
+		return errors.New("body must be Markdown or a list of form fields")
+	}
+	return nil
+}
+
+func (t Template) MarshalYAML() (any, error) {
+	doc := document{
+		Name:      t.Name,
+		About:     t.About,
+		Title:     t.Title,
+		Labels:    t.Labels,
+		Assignees: t.Assignees,
+	}
+	var body any = t.Body
+	if len(t.Fields) > 0 {
+		elements := make([]element, len(t.Fields))
+		for i, f := range t.Fields {
+			e := element{Type: f.Type, ID: f.ID}
+			e.Attributes.Label = f.Label
+			e.Attributes.Description = f.Description
+			e.Attributes.Placeholder = f.Placeholder
+			e.Attributes.Value = f.Value
+			for _, o := range f.Options {
+				e.Attributes.Options = append(e.Attributes.Options, option{Label: o})
+			}
+			e.Validations.Required = f.Required
+			elements[i] = e
+		}
+		body = elements
+	}
+	if t.Body != "" || len(t.Fields) > 0 {
+		if err := doc.Body.Encode(body); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+func (o option) MarshalYAML() (any, error) {
+	return o.Label, nil
+}
@@ -0,0 +1,122 @@
+package issuetemplate
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	data := "---\nname: Bug report\nabout: Something is broken\ntitle: '[Bug] '\nlabels: bug, triage\nassignees: ''\n---\n\n**Describe the bug**\n\n**To reproduce**\n"
+	tmpl, err := Parse("bug_report.md", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Name != "Bug report" || tmpl.About != "Something is broken" || tmpl.Title != "[Bug] " {
+		t.Errorf("unexpected frontmatter %+v", tmpl)
+	}
+	if !slices.Equal(tmpl.Labels, []string{"bug", "triage"}) || len(tmpl.Assignees) != 0 {
+		t.Errorf("expected comma-separated labels and no assignees, got %v and %v", tmpl.Labels, tmpl.Assignees)
+	}
+	if got := tmpl.Render(nil); got != "**Describe the bug**\n\n**To reproduce**" {
+		t.Errorf("unexpected body %q", got)
+	}
+
+	plain, err := Parse("chore.md", []byte("Just a body\n"))
+	if err != nil || plain.Name != "chore" || plain.Body != "Just a body" {
+		t.Errorf("expected a template without frontmatter to be named after its file, got %+v (%v)", plain, err)
+	}
+}
+
+func TestParseForm(t *testing.T) {
+	data := `name: Dependency bump
+description: Bump a vulnerable dependency
+title: "[deps]: "
+labels: [dependencies]
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for reporting!
+  - type: input
+    id: package
+    attributes:
+      label: Package
+    validations:
+      required: true
+  - type: dropdown
+    id: severity
+    attributes:
+      label: Severity
+      options: [low, high]
+  - type: checkboxes
+    attributes:
+      label: Checks
+      options:
+        - label: Tests pass
+`
+	tmpl, err := Parse("deps.yml", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.About != "Bump a vulnerable dependency" || len(tmpl.Fields) != 3 || len(tmpl.Asked()) != 2 {
+		t.Fatalf("unexpected form %+v", tmpl)
+	}
+
+	pkg, severity := tmpl.Asked()[0], tmpl.Asked()[1]
+	if _, err := pkg.Check(" "); err == nil {
+		t.Error("expected a required field to need a value")
+	}
+	if got, err := severity.Check("2"); err != nil || got != "high" {
+		t.Errorf("expected option 2 to be high, got %q (%v)", got, err)
+	}
+	if _, err := severity.Check("urgent"); err == nil {
+		t.Error("expected a value that is not an option to be rejected")
+	}
+
+	want := "### Package\n\nlodash\n\n### Severity\n\n_No response_\n\n### Checks\n\n- [ ] Tests pass\n"
+	if got := tmpl.Render([]string{"lodash", ""}); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := Parse("empty.yml", []byte("name: Empty\n")); err == nil {
+		t.Error("expected a form without fields to be rejected")
+	}
+	if _, err := Parse("odd.yml", []byte("name: Odd\nbody:\n  - type: slider\n    attributes:\n      label: X\n")); err == nil || !strings.Contains(err.Error(), "slider") {
+		t.Errorf("expected an unknown field type to be rejected, got %v", err)
+	}
+}
+
+func TestTemplateRoundTrips(t *testing.T) {
+	tmpl := Template{
+		Name:   "Dependency bump",
+		Labels: []string{"dependencies"},
+		Fields: []Field{{Type: FieldDropdown, ID: "severity", Label: "Severity", Options: []string{"low", "high"}, Required: true}},
+	}
+	data, err := yaml.Marshal(tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Template
+	if err := yaml.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != tmpl.Name || !slices.Equal(got.Labels, tmpl.Labels) || len(got.Fields) != 1 ||
+		!slices.Equal(got.Fields[0].Options, tmpl.Fields[0].Options) || !got.Fields[0].Required {
+		t.Errorf("got %+v from\n%s", got, data)
+	}
+}
+
+func TestIsTemplateFile(t *testing.T) {
+	for name, want := range map[string]bool{
+		"bug_report.md": true,
+		"feature.yml":   true,
+		"config.yml":    false,
+		"README.txt":    false,
+	} {
+		if got := IsTemplateFile(name); got != want {
+			t.Errorf("IsTemplateFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Package jira creates follow-up tickets from assessment findings.
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// TokenEnv is the environment variable holding the Jira API token.
+const TokenEnv = "JIRA_API_TOKEN"
+
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// Ticket is a follow-up ticket for one repo, or for one team's repos.
+type Ticket struct {
+	Summary     string
+	Description string
+	Repos       []string
+}
+
+// ResolveToken returns the Jira API token from the environment.
+func ResolveToken() string {
+	return strings.TrimSpace(os.Getenv(TokenEnv))
+}
+
+// RemediationPrompt is the copycat prompt suggested in tickets to fix the
+// repos an assessment question found lacking.
+func RemediationPrompt(question string) string {
+	return "Change this repository so it fully satisfies the following, keeping the change as small as possible:\n" + question
+}
+
+// Tickets builds one ticket per project with a finding, or one per team when
+// perTeam is set. Projects without a team get a ticket of their own.
+func Tickets(projects []config.Project, question string, findings map[string]string, perTeam bool) []Ticket {
+	groups := make(map[string][]string)
+	var order []string
+	for _, project := range projects {
+		repo := project.Key()
+		if strings.TrimSpace(findings[repo]) == "" {
+			continue
+		}
+		group := repo
+		if perTeam && project.Team != "" {
+			group = project.Team
+		}
+		if _, ok := groups[group]; !ok {
+			order = append(order, group)
+		}
+		groups[group] = append(groups[group], repo)
+	}
+
+	tickets := make([]Ticket, 0, len(order))
+	for _, group := range order {
+		repos := groups[group]
+		sort.Strings(repos)
+		summary := fmt.Sprintf("%s: %s", group, firstLine(question))
+		if len(repos) > 1 {
+			summary = fmt.Sprintf("%s (%d repos)", summary, len(repos))
+		}
+		tickets = append(tickets, Ticket{
+			Summary:     truncate(summary, 250),
+			Description: description(question, repos, findings),
+			Repos:       repos,
+		})
+	}
+	return tickets
+}
+
+// description writes a ticket's body in Jira's wiki markup.
+func description(question string, repos []string, findings map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Copycat assessment: %s\n", question)
+	for _, repo := range repos {
+		fmt.Fprintf(&b, "\nh3. %s\n%s\n", repo, strings.TrimSpace(findings[repo]))
+	}
+	b.WriteString("\nh3. Remediation\nRun copycat on the repos above with this prompt:\n{noformat}\n")
+	b.WriteString(RemediationPrompt(question))
+	b.WriteString("\n{noformat}\n")
+	return b.String()
+}
+
+// CreateTickets creates the tickets in Jira, reporting each one's outcome.
+func CreateTickets(ctx context.Context, cfg config.JiraConfig, token string, tickets []Ticket, onStatus func(string)) {
+	if len(tickets) == 0 {
+		onStatus("⚠️  No findings for the chosen repos, no Jira tickets created")
+		return
+	}
+	onStatus(fmt.Sprintf("Creating %d Jira tickets in %s...", len(tickets), cfg.Project))
+	for _, ticket := range tickets {
+		repos := strings.Join(ticket.Repos, ", ")
+		key, err := createIssue(ctx, cfg, token, ticket)
+		if err != nil {
+			onStatus(fmt.Sprintf("⚠️  Failed to create a ticket for %s: %v", repos, err))
+			continue
+		}
+		onStatus(fmt.Sprintf("✓ %s/browse/%s for: %s", strings.TrimRight(cfg.BaseURL, "/"), key, repos))
+	}
+}
+
+type issueRequest struct {
+	Fields issueFields `json:"fields"`
+}
+
+type issueFields struct {
+	Project     keyField  `json:"project"`
+	Summary     string    `json:"summary"`
+	Description string    `json:"description"`
+	IssueType   nameField `json:"issuetype"`
+	Labels      []string  `json:"labels,omitempty"`
+}
+
+type keyField struct {
+	Key string `json:"key"`
+}
+
+type nameField struct {
+	Name string `json:"name"`
+}
+
+// createIssue creates ticket with the v2 REST API, which takes wiki markup
+// descriptions, and returns its key.
+func createIssue(ctx context.Context, cfg config.JiraConfig, token string, ticket Ticket) (string, error) {
+	issueType := cfg.IssueType
+	if issueType == "" {
+		issueType = "Task"
+	}
+	body, err := json.Marshal(issueRequest{Fields: issueFields{
+		Project:     keyField{Key: cfg.Project},
+		Summary:     ticket.Summary,
+		Description: ticket.Description,
+		IssueType:   nameField{Name: issueType},
+		Labels:      cfg.Labels,
+	}})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal issue: %w", err)
+	}
+
+	url := strings.TrimRight(cfg.BaseURL, "/") + "/rest/api/2/issue"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(cfg.Email, token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Key           string            `json:"key"`
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil && resp.StatusCode < 300 {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.StatusCode >= 300 || result.Key == "" {
+		messages := append([]string(nil), result.ErrorMessages...)
+		for field, message := range result.Errors {
+			messages = append(messages, field+": "+message)
+		}
+		sort.Strings(messages)
+		return "", fmt.Errorf("jira API error: %s %s", resp.Status, strings.Join(messages, "; "))
+	}
+	return result.Key, nil
+}
+
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return s
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
@@ -0,0 +1,84 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestTickets(t *testing.T) {
+	projects := []config.Project{
+		{Repo: "ledger-service", Team: "payments"},
+		{Repo: "payments-api", Team: "payments"},
+		{Repo: "card-service"},
+		{Repo: "fraud-service", Team: "risk"},
+	}
+	findings := map[string]string{
+		"ledger-service": "No HTTP timeouts.",
+		"payments-api":   "Timeouts only on the server.",
+		"card-service":   "No HTTP timeouts.",
+	}
+
+	perRepo := Tickets(projects, "Do we set HTTP timeouts?", findings, false)
+	if len(perRepo) != 3 {
+		t.Fatalf("expected a ticket per repo with a finding, got %+v", perRepo)
+	}
+	if perRepo[0].Summary != "ledger-service: Do we set HTTP timeouts?" {
+		t.Errorf("unexpected summary %q", perRepo[0].Summary)
+	}
+
+	perTeam := Tickets(projects, "Do we set HTTP timeouts?", findings, true)
+	if len(perTeam) != 2 {
+		t.Fatalf("expected a ticket for the team and one for the repo without a team, got %+v", perTeam)
+	}
+	if perTeam[0].Summary != "payments: Do we set HTTP timeouts? (2 repos)" {
+		t.Errorf("unexpected summary %q", perTeam[0].Summary)
+	}
+	for _, want := range []string{"h3. ledger-service\nNo HTTP timeouts.", "h3. payments-api\nTimeouts only on the server.", "{noformat}"} {
+		if !strings.Contains(perTeam[0].Description, want) {
+			t.Errorf("expected the description to contain %q, got:\n%s", want, perTeam[0].Description)
+		}
+	}
+}
+
+func TestCreateTickets(t *testing.T) {
+	var got issueRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "jane@example.com" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errorMessages":["not allowed"]}`))
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		if got.Fields.Summary == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":{"summary":"too dull"}}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"key":"OPS-7"}`))
+	}))
+	defer server.Close()
+
+	cfg := config.JiraConfig{BaseURL: server.URL, Email: "jane@example.com", Project: "OPS", Labels: []string{"copycat"}}
+	var lines []string
+	CreateTickets(context.Background(), cfg, "secret", []Ticket{
+		{Summary: "ledger-service: timeouts", Repos: []string{"ledger-service"}},
+		{Summary: "fail", Repos: []string{"payments-api"}},
+	}, func(line string) { lines = append(lines, line) })
+
+	if len(lines) != 3 || lines[1] != "✓ "+server.URL+"/browse/OPS-7 for: ledger-service" {
+		t.Fatalf("unexpected status lines %q", lines)
+	}
+	if !strings.Contains(lines[2], "payments-api") || !strings.Contains(lines[2], "summary: too dull") {
+		t.Errorf("expected the failure to name the repo and Jira's error, got %q", lines[2])
+	}
+	if got.Fields.Project.Key != "OPS" || got.Fields.IssueType.Name != "Task" || len(got.Fields.Labels) != 1 {
+		t.Errorf("unexpected request %+v", got)
+	}
+}
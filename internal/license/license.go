@@ -0,0 +1,204 @@
+package license
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/util"
+)
+
+// yearPlaceholder in the header text is replaced with the current year.
+const yearPlaceholder = "{{year}}"
+
+// headerSearchLines is how far into a file an existing header is looked for.
+const headerSearchLines = 20
+
+var (
+	slashes = config.CommentStyle{Prefix: "// "}
+	hashes  = config.CommentStyle{Prefix: "# "}
+	dashes  = config.CommentStyle{Prefix: "-- "}
+	cBlock  = config.CommentStyle{Start: "/*", Prefix: " * ", End: " */"}
+	xml     = config.CommentStyle{Start: "<!--", Prefix: "  ", End: "-->"}
+)
+
+// defaultStyles maps file extensions (or base names for files without one) to
+// the comment style used for their header.
+var defaultStyles = map[string]config.CommentStyle{
+	".go": slashes, ".java": slashes, ".kt": slashes, ".kts": slashes, ".scala": slashes,
+	".groovy": slashes, ".gradle": slashes, ".js": slashes, ".jsx": slashes, ".mjs": slashes,
+	".ts": slashes, ".tsx": slashes, ".c": slashes, ".h": slashes, ".cc": slashes,
+	".cpp": slashes, ".hpp": slashes, ".cs": slashes, ".swift": slashes, ".rs": slashes,
+	".dart": slashes, ".proto": slashes, ".php": slashes,
+	".py": hashes, ".rb": hashes, ".sh": hashes, ".bash": hashes, ".zsh": hashes,
+	".pl": hashes, ".r": hashes, ".tf": hashes, ".yaml": hashes, ".yml": hashes,
+	".toml": hashes, ".ex": hashes, ".exs": hashes, "Dockerfile": hashes, "Makefile": hashes,
+	".sql": dashes, ".lua": dashes, ".hs": dashes,
+	".css": cBlock, ".scss": cBlock, ".less": cBlock,
+	".html": xml, ".xml": xml, ".vue": xml, ".svelte": xml,
+}
+
+// defaultExclude lists paths that are never given a header.
+var defaultExclude = []string{"vendor/**", "**/vendor/**", "**/node_modules/**", "third_party/**"}
+
+// StyleFor returns the comment style for the file at name, preferring the
+// configured overrides. It reports false for files with no known style.
+func StyleFor(name string, overrides map[string]config.CommentStyle) (config.CommentStyle, bool) {
+	key := strings.ToLower(path.Ext(name))
+	if key == "" {
+		key = path.Base(name)
+	}
+	if style, ok := overrides[key]; ok {
+		return style, true
+	}
+	style, ok := defaultStyles[key]
+	return style, ok
+}
+
+// Render formats text as a comment block in style, followed by a blank line.
+func Render(text string, style config.CommentStyle) string {
+	var b strings.Builder
+	if style.Start != "" {
+		b.WriteString(style.Start + "\n")
+	}
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		b.WriteString(strings.TrimRight(style.Prefix+line, " ") + "\n")
+	}
+	if style.End != "" {
+		b.WriteString(style.End + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// Apply adds the configured header to every tracked source file in repoPath
+// that does not already have one. Files are skipped when excluded, generated,
+// binary, empty, or of a language with no known comment style. Returns the
+// updated paths.
+func Apply(ctx context.Context, repoPath string, cfg config.LicenseHeaderConfig) ([]string, error) {
+	text := strings.TrimSpace(cfg.Text)
+	if text == "" {
+		return nil, fmt.Errorf("license_header.text is not configured")
+	}
+	text = strings.ReplaceAll(text, yearPlaceholder, strconv.Itoa(time.Now().Year()))
+	marker := headerMarker(cfg.Text)
+
+	cmd := exec.CommandContext(ctx, "git", "ls-files", "-z")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	var updated []string
+	for _, name := range strings.Split(strings.TrimRight(string(output), "\x00"), "\x00") {
+		if name == "" || util.MatchAnyGlob(defaultExclude, name) || util.MatchAnyGlob(cfg.Exclude, name) {
+			continue
+		}
+		style, ok := StyleFor(name, cfg.Styles)
+		if !ok {
+			continue
+		}
+
+		full := filepath.Join(repoPath, name)
+		info, err := os.Lstat(full)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		data, err := os.ReadFile(full)
+		if err != nil {
+			return updated, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if len(bytes.TrimSpace(data)) == 0 || bytes.IndexByte(data, 0) >= 0 || isGenerated(data) || hasHeader(data, marker) {
+			continue
+		}
+
+		if err := os.WriteFile(full, insertHeader(data, Render(text, style)), info.Mode().Perm()); err != nil {
+			return updated, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		updated = append(updated, name)
+	}
+	return updated, nil
+}
+
+// headerMarker is the text identifying an existing header: the first line of
+// the configured header, up to the year placeholder.
+func headerMarker(text string) string {
+	first := strings.TrimSpace(strings.SplitN(strings.TrimSpace(text), "\n", 2)[0])
+	if idx := strings.Index(first, yearPlaceholder); idx >= 0 {
+		first = strings.TrimSpace(first[:idx])
+	}
+	return strings.ToLower(first)
+}
+
+// hasHeader reports whether marker appears near the top of the file.
+func hasHeader(data []byte, marker string) bool {
+	lines := bytes.SplitN(data, []byte("\n"), headerSearchLines+1)
+	if len(lines) > headerSearchLines {
+		lines = lines[:headerSearchLines]
+	}
+	return bytes.Contains(bytes.ToLower(bytes.Join(lines, []byte("\n"))), []byte(marker))
+}
+
+// isGenerated reports whether the file carries a generated-code marker.
+func isGenerated(data []byte) bool {
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	return (bytes.Contains(head, []byte("Code generated")) && bytes.Contains(head, []byte("DO NOT EDIT"))) ||
+		bytes.Contains(head, []byte("@generated"))
+}
+
+// insertHeader places header at the top of data, after any lines that must
+// stay first: shebangs, XML/PHP openers, encoding declarations and Dockerfile
+// parser directives.
+func insertHeader(data []byte, header string) []byte {
+	crlf := bytes.Contains(data, []byte("\r\n"))
+	if crlf {
+		header = strings.ReplaceAll(header, "\n", "\r\n")
+	}
+
+	offset := 0
+	for i := 0; i < 2 && offset < len(data); i++ {
+		end := bytes.IndexByte(data[offset:], '\n')
+		if end < 0 {
+			end = len(data) - offset
+		} else {
+			end++
+		}
+		line := strings.TrimSpace(string(data[offset : offset+end]))
+		if !mustStayFirst(line) {
+			break
+		}
+		offset += end
+	}
+
+	var b bytes.Buffer
+	b.Write(data[:offset])
+	if offset > 0 && !bytes.HasSuffix(data[:offset], []byte("\n")) {
+		b.WriteString("\n")
+	}
+	b.WriteString(header)
+	b.Write(data[offset:])
+	return b.Bytes()
+}
+
+func mustStayFirst(line string) bool {
+	switch {
+	case strings.HasPrefix(line, "#!"), strings.HasPrefix(line, "<?xml"), strings.HasPrefix(line, "<?php"),
+		strings.HasPrefix(line, "# syntax="), strings.HasPrefix(line, "# escape="):
+		return true
+	case strings.HasPrefix(line, "#") && (strings.Contains(line, "coding:") || strings.Contains(line, "coding=")):
+		return true
+	}
+	return false
+}
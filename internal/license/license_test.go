@@ -0,0 +1,123 @@
+package license
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestRender(t *testing.T) {
+	got := Render("Copyright Acme\nAll rights reserved.", config.CommentStyle{Prefix: "// "})
+	want := "// Copyright Acme\n// All rights reserved.\n\n"
+	if got != want {
+		t.Errorf("line style: got %q, want %q", got, want)
+	}
+
+	got = Render("Copyright Acme", config.CommentStyle{Start: "/*", Prefix: " * ", End: " */"})
+	want = "/*\n * Copyright Acme\n */\n\n"
+	if got != want {
+		t.Errorf("block style: got %q, want %q", got, want)
+	}
+}
+
+func TestStyleFor(t *testing.T) {
+	if style, ok := StyleFor("cmd/main.go", nil); !ok || style.Prefix != "// " {
+		t.Errorf("expected Go line comments, got %+v, %v", style, ok)
+	}
+	if style, ok := StyleFor("build/Dockerfile", nil); !ok || style.Prefix != "# " {
+		t.Errorf("expected Dockerfile hash comments, got %+v, %v", style, ok)
+	}
+	if _, ok := StyleFor("logo.png", nil); ok {
+		t.Error("expected no style for images")
+	}
+	overrides := map[string]config.CommentStyle{".py": {Start: `"""`, End: `"""`}}
+	if style, _ := StyleFor("app.py", overrides); style.Start != `"""` {
+		t.Errorf("expected override to win, got %+v", style)
+	}
+}
+
+func TestInsertHeader(t *testing.T) {
+	header := "# Copyright Acme\n\n"
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"plain", "print(1)\n", header + "print(1)\n"},
+		{"shebang", "#!/usr/bin/env python\nprint(1)\n", "#!/usr/bin/env python\n" + header + "print(1)\n"},
+		{"shebang and coding", "#!/usr/bin/env python\n# -*- coding: utf-8 -*-\nx\n", "#!/usr/bin/env python\n# -*- coding: utf-8 -*-\n" + header + "x\n"},
+		{"crlf", "x\r\n", "# Copyright Acme\r\n\r\nx\r\n"},
+	}
+	for _, tt := range tests {
+		if got := string(insertHeader([]byte(tt.data), header)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":         "package main\n",
+		"has_header.go":   "// Copyright 2020 Acme Corp\n\npackage main\n",
+		"gen.pb.go":       "// Code generated by protoc-gen-go. DO NOT EDIT.\npackage main\n",
+		"script.sh":       "#!/bin/sh\necho hi\n",
+		"vendor/lib/x.go": "package lib\n",
+		"skip/me.go":      "package skip\n",
+		"README.md":       "# Title\n",
+		"web/styles.css":  "body {}\n",
+		"empty.go":        "",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"add", "-A"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+
+	updated, err := Apply(context.Background(), dir, config.LicenseHeaderConfig{
+		Text:    "Copyright {{year}} Acme Corp",
+		Exclude: []string{"skip/**"},
+	})
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if strings.Join(updated, ",") != "main.go,script.sh,web/styles.css" {
+		t.Errorf("unexpected updated files: %v", updated)
+	}
+
+	year := strconv.Itoa(time.Now().Year())
+	data, _ := os.ReadFile(filepath.Join(dir, "main.go"))
+	if want := "// Copyright " + year + " Acme Corp\n\npackage main\n"; string(data) != want {
+		t.Errorf("main.go: got %q, want %q", data, want)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "script.sh"))
+	if !strings.HasPrefix(string(data), "#!/bin/sh\n# Copyright ") {
+		t.Errorf("expected header after shebang, got %q", data)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "web/styles.css"))
+	if !strings.HasPrefix(string(data), "/*\n * Copyright ") {
+		t.Errorf("expected block comment header, got %q", data)
+	}
+}
@@ -0,0 +1,79 @@
+package permission
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Decision is a request the permission server answered from its policy
+// alone, as written to the audit log.
+type Decision struct {
+	Time     time.Time `json:"time"`
+	Repo     string    `json:"repo"`
+	ToolName string    `json:"tool_name"`
+	Command  string    `json:"command,omitempty"`
+	Approved bool      `json:"approved"`
+	Rule     string    `json:"rule,omitempty"`   // the rule that decided it; none when denied by default
+	Reason   string    `json:"reason,omitempty"` // why a request no rule matched was denied
+}
+
+// PolicyDecisionMsg tells the TUI about a request answered from the policy.
+type PolicyDecisionMsg struct {
+	Decision Decision
+}
+
+var _ tea.Msg = PolicyDecisionMsg{}
+
+// decide answers req from the policy, denying what no rule allows for its
+// tool. Commands chaining others with shell syntax need a rule for exactly
+// them.
+// Questions can't be answered from rules and are always denied.
+func decide(policy Policy, req permissionHTTPRequest) Decision {
+	d := Decision{Time: time.Now(), Repo: req.Repo, ToolName: req.ToolName, Command: req.Command}
+	if len(req.Questions) > 0 {
+		d.Reason = "questions need someone to answer them"
+		return d
+	}
+	// Rules with a * never match chained commands, so those are only
+	// approved by a rule for that exact command
+	rule, approved, decided := policy.Match(req.ToolName, req.Repo, req.Command)
+	if !decided && hasShellSyntax(req.Command) {
+		d.Reason = "it uses shell control syntax and no rule allows that exact command"
+		return d
+	}
+	if !decided {
+		d.Reason = "no rule allows it"
+		return d
+	}
+	d.Approved = approved
+	d.Rule = rule.Command
+	if rule.Tool != "" {
+		d.Rule += " for " + rule.Tool
+	}
+	if rule.Repo != "" {
+		d.Rule += " in " + rule.Repo
+	}
+	return d
+}
+
+// appendDecision adds d to the audit log at path, one JSON object per line.
+func appendDecision(path string, d Decision) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,17 @@
+package permission
+
+import "testing"
+
+func TestDecideMatchesTool(t *testing.T) {
+	policy := Policy{Allow: []Rule{{Tool: "Bash", Command: "echo hello > notes.txt", Repo: "ledger-service"}}}
+
+	d := decide(policy, permissionHTTPRequest{ToolName: "Bash", Command: "echo hello > notes.txt", Repo: "ledger-service"})
+	if !d.Approved || d.Rule != "echo hello > notes.txt for Bash in ledger-service" {
+		t.Errorf("expected the Bash rule to approve its command, got %+v", d)
+	}
+
+	d = decide(policy, permissionHTTPRequest{ToolName: "Write", Command: "echo hello > notes.txt", Repo: "ledger-service"})
+	if d.Approved || d.Rule != "" {
+		t.Errorf("expected a rule for Bash not to approve a Write with the same text, got %+v", d)
+	}
+}
@@ -0,0 +1,112 @@
+package permission
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// hookInput is what a tool passes a BeforeTool hook on stdin, as Gemini does
+// (Claude's PreToolUse hooks get the same fields).
+type hookInput struct {
+	ToolName  string          `json:"tool_name"`
+	ToolInput json.RawMessage `json:"tool_input"`
+}
+
+// GenerateHookSettings creates a temporary settings file that runs the
+// copycat permission-hook subcommand before each of the tool's tool calls,
+// for tools whose permission_adapter is hook. The tool is pointed at it with
+// its permission_settings_env. With a queuePath (see PermissionServer.Persist),
+// the hook asks a restarted copycat when this one goes away.
+// Returns the file path and a cleanup function that removes it.
+func GenerateHookSettings(port int, queuePath string) (string, func(), error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get executable path: %w", err)
+	}
+
+	// Hooks run through the shell with the tool's environment, so the port
+	// and queue are set on the command itself
+	command := fmt.Sprintf("COPYCAT_PERMISSION_PORT=%d", port)
+	if queuePath != "" {
+		command += " COPYCAT_PERMISSION_QUEUE=" + shellQuote(queuePath)
+	}
+	command += " " + shellQuote(exe) + " permission-hook"
+
+	type hook struct {
+		Name    string `json:"name"`
+		Type    string `json:"type"`
+		Command string `json:"command"`
+		Timeout int64  `json:"timeout"` // in milliseconds
+	}
+	type matcher struct {
+		Matcher string `json:"matcher"`
+		Hooks   []hook `json:"hooks"`
+	}
+	settings := map[string]any{
+		"hooksConfig": map[string]bool{"enabled": true},
+		"hooks": map[string][]matcher{
+			"BeforeTool": {{
+				Matcher: ".*",
+				Hooks: []hook{{
+					Name:    "copycat-auth",
+					Type:    "command",
+					Command: command,
+					// Waits as long as a request can be answered
+					Timeout: (permissionTimeout + time.Minute).Milliseconds(),
+				}},
+			}},
+		},
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal hook settings: %w", err)
+	}
+	return writeTempConfig("copycat-hooks-*.json", data)
+}
+
+// RunPermissionHook is the entry point for the "permission-hook" subcommand,
+// run by a tool before each of its tool calls. It asks the main copycat
+// process about the call read from stdin and returns the exit code telling
+// the tool the answer: 0 to go ahead, or 2 to block it, with the reason on
+// stderr.
+func RunPermissionHook(stdin io.Reader, stdout, stderr io.Writer) int {
+	deny := func(reason string) int {
+		fmt.Fprintln(stderr, reason)
+		return 2
+	}
+
+	port := os.Getenv("COPYCAT_PERMISSION_PORT")
+	if port == "" {
+		return deny("COPYCAT_PERMISSION_PORT not set")
+	}
+	var input hookInput
+	if err := json.NewDecoder(stdin).Decode(&input); err != nil {
+		return deny("invalid hook input")
+	}
+
+	httpReq := permissionHTTPRequest{
+		ToolName: input.ToolName,
+		Repo:     os.Getenv("COPYCAT_REPO_NAME"),
+		Command:  extractCommand(input.ToolInput),
+	}
+	baseURL := fmt.Sprintf("http://127.0.0.1:%s", port)
+	httpResp, err := askPermission(httpReq, baseURL, os.Getenv("COPYCAT_PERMISSION_QUEUE"), time.Now().Add(permissionTimeout))
+	if err != nil {
+		return deny(err.Error())
+	}
+	if !httpResp.Approved {
+		return deny("User denied permission")
+	}
+	fmt.Fprintln(stdout, `{"decision":"allow"}`)
+	return 0
+}
+
+// shellQuote quotes s as a single word for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,106 @@
+package permission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestGenerateHookSettings(t *testing.T) {
+	path, cleanup, err := GenerateHookSettings(12345, "/tmp/it's queue.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings struct {
+		Hooks struct {
+			BeforeTool []struct {
+				Matcher string `json:"matcher"`
+				Hooks   []struct {
+					Type    string `json:"type"`
+					Command string `json:"command"`
+				} `json:"hooks"`
+			} `json:"BeforeTool"`
+		} `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal("failed to parse hook settings:", err)
+	}
+	if len(settings.Hooks.BeforeTool) != 1 || len(settings.Hooks.BeforeTool[0].Hooks) != 1 {
+		t.Fatalf("expected one BeforeTool hook, got %s", data)
+	}
+	command := settings.Hooks.BeforeTool[0].Hooks[0].Command
+	for _, want := range []string{"COPYCAT_PERMISSION_PORT=12345", `COPYCAT_PERMISSION_QUEUE='/tmp/it'\''s queue.json'`, "' permission-hook"} {
+		if !strings.Contains(command, want) {
+			t.Errorf("expected %q in hook command %q", want, command)
+		}
+	}
+
+	cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("cleanup should have removed the file")
+	}
+}
+
+func TestRunPermissionHook(t *testing.T) {
+	statusCh := make(chan tea.Msg, 10)
+	server, err := NewPermissionServer(statusCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Shutdown(context.Background())
+	t.Setenv("COPYCAT_PERMISSION_PORT", fmt.Sprint(server.Port()))
+	t.Setenv("COPYCAT_PERMISSION_QUEUE", "")
+	t.Setenv("COPYCAT_REPO_NAME", "payments-api")
+
+	for _, approve := range []bool{true, false} {
+		go func() {
+			select {
+			case msg := <-statusCh:
+				req := msg.(PermissionRequestMsg).Request
+				if req.Command != "npm install" || req.Repo != "payments-api" || req.ToolName != "run_shell_command" {
+					t.Errorf("unexpected request %+v", req)
+				}
+				req.ResponseCh <- PermissionResponse{Approved: approve}
+			case <-time.After(5 * time.Second):
+				t.Error("timeout waiting for permission request")
+			}
+		}()
+
+		var stdout, stderr bytes.Buffer
+		stdin := strings.NewReader(`{"tool_name":"run_shell_command","tool_input":{"command":"npm install"}}`)
+		code := RunPermissionHook(stdin, &stdout, &stderr)
+		if approve && (code != 0 || !strings.Contains(stdout.String(), `"allow"`)) {
+			t.Errorf("expected an approved call to go ahead, got %d %q", code, stdout.String())
+		}
+		if !approve && (code != 2 || !strings.Contains(stderr.String(), "denied")) {
+			t.Errorf("expected a denied call to be blocked, got %d %q", code, stderr.String())
+		}
+	}
+}
+
+func TestExtractCommand(t *testing.T) {
+	if got := extractCommand(json.RawMessage(`{"command":"npm install","description":"Install"}`)); got != "npm install" {
+		t.Errorf("expected the command itself, got %q", got)
+	}
+	// Other tools' inputs are spelled out in one order, so a saved rule
+	// matches the same input again
+	input := json.RawMessage(`{"file_path":"go.mod","content":"module x","mode":"overwrite"}`)
+	for range 10 {
+		if got := extractCommand(input); got != "content=module x file_path=go.mod mode=overwrite" {
+			t.Fatalf("expected the input's fields sorted by name, got %q", got)
+		}
+	}
+}
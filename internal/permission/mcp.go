@@ -4,10 +4,16 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 // JSON-RPC types for MCP protocol
@@ -151,17 +157,9 @@ func handleToolCall(req jsonRPCRequest, baseURL string) jsonRPCResponse {
 		httpReq.Command = extractCommand(args.Input)
 	}
 
-	body, _ := json.Marshal(httpReq)
-
-	resp, err := http.Post(baseURL+"/permission", "application/json", bytes.NewReader(body))
+	httpResp, err := askPermission(httpReq, baseURL, os.Getenv("COPYCAT_PERMISSION_QUEUE"), time.Now().Add(permissionTimeout))
 	if err != nil {
-		return respondDeny(req.ID, "failed to contact permission server")
-	}
-	defer resp.Body.Close()
-
-	var httpResp permissionHTTPResponse
-	if err := json.NewDecoder(resp.Body).Decode(&httpResp); err != nil {
-		return respondDeny(req.ID, "failed to decode permission response")
+		return respondDeny(req.ID, err.Error())
 	}
 
 	// For AskUserQuestion, always deny the tool but include the user's answer
@@ -176,6 +174,38 @@ func handleToolCall(req jsonRPCRequest, baseURL string) jsonRPCResponse {
 	return respondDeny(req.ID, "User denied permission")
 }
 
+// askPermission posts the request to the permission server and waits for the
+// answer. When the server goes away and queuePath is set, the request is
+// asked again, under the same ID, of whichever copycat the queue file names
+// next, until deadline.
+func askPermission(httpReq permissionHTTPRequest, baseURL, queuePath string, deadline time.Time) (permissionHTTPResponse, error) {
+	httpReq.ID = uuid.New().String()
+	body, _ := json.Marshal(httpReq)
+
+	for {
+		var httpResp permissionHTTPResponse
+		resp, err := http.Post(baseURL+"/permission", "application/json", bytes.NewReader(body))
+		if err == nil {
+			err = json.NewDecoder(resp.Body).Decode(&httpResp)
+			resp.Body.Close()
+			if err == nil {
+				return httpResp, nil
+			}
+			err = errors.New("failed to decode permission response")
+		} else {
+			err = errors.New("failed to contact permission server")
+		}
+
+		if queuePath == "" || time.Now().Add(reattachInterval).After(deadline) {
+			return httpResp, err
+		}
+		time.Sleep(reattachInterval)
+		if state, err := readQueue(queuePath); err == nil && state.Port != 0 {
+			baseURL = fmt.Sprintf("http://127.0.0.1:%d", state.Port)
+		}
+	}
+}
+
 // extractQuestions parses the AskUserQuestion input into structured question data.
 func extractQuestions(input json.RawMessage) []httpQuestion {
 	var obj struct {
@@ -231,10 +261,11 @@ func extractCommand(input json.RawMessage) string {
 	if cmd, ok := obj["command"].(string); ok {
 		return cmd
 	}
-	// Fallback: stringify the whole input
-	parts := make([]string, 0)
-	for k, v := range obj {
-		parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+	// Fallback: stringify the whole input, in the same order every time so
+	// rules saved for it match again
+	parts := make([]string, 0, len(obj))
+	for _, k := range slices.Sorted(maps.Keys(obj)) {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, obj[k]))
 	}
 	return strings.Join(parts, " ")
 }
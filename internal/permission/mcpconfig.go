@@ -37,8 +37,10 @@ func readUserMCPServers() map[string]json.RawMessage {
 // GenerateMCPConfig creates a temporary MCP configuration file that points
 // Claude Code's permission-prompt-tool at the copycat permission-handler subcommand.
 // It also merges the user's MCP servers from ~/.claude.json so they remain available.
+// With a queuePath (see PermissionServer.Persist), the handler asks a
+// restarted copycat when this one goes away.
 // Returns the file path and a cleanup function that removes it.
-func GenerateMCPConfig(port int) (string, func(), error) {
+func GenerateMCPConfig(port int, queuePath string) (string, func(), error) {
 	exe, err := os.Executable()
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to get executable path: %w", err)
@@ -55,6 +57,9 @@ func GenerateMCPConfig(port int) (string, func(), error) {
 			"COPYCAT_PERMISSION_PORT": fmt.Sprintf("%d", port),
 		},
 	}
+	if queuePath != "" {
+		copycatAuth.Env["COPYCAT_PERMISSION_QUEUE"] = queuePath
+	}
 
 	copycatAuthRaw, err := json.Marshal(copycatAuth)
 	if err != nil {
@@ -75,21 +80,27 @@ func GenerateMCPConfig(port int) (string, func(), error) {
 		return "", nil, fmt.Errorf("failed to marshal MCP config: %w", err)
 	}
 
-	f, err := os.CreateTemp("", "copycat-mcp-*.json")
+	return writeTempConfig("copycat-mcp-*.json", data)
+}
+
+// writeTempConfig writes data to a new temp file only the user can read, named
+// after pattern, and returns its path and a cleanup function that removes it.
+func writeTempConfig(pattern string, data []byte) (string, func(), error) {
+	f, err := os.CreateTemp("", pattern)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to create temp MCP config: %w", err)
+		return "", nil, fmt.Errorf("failed to create temp config: %w", err)
 	}
 
 	if _, err := f.Write(data); err != nil {
 		f.Close()
 		os.Remove(f.Name())
-		return "", nil, fmt.Errorf("failed to write MCP config: %w", err)
+		return "", nil, fmt.Errorf("failed to write config: %w", err)
 	}
 	f.Close()
 
 	if err := os.Chmod(f.Name(), 0o600); err != nil {
 		os.Remove(f.Name())
-		return "", nil, fmt.Errorf("failed to set MCP config permissions: %w", err)
+		return "", nil, fmt.Errorf("failed to set config permissions: %w", err)
 	}
 
 	path := f.Name()
@@ -8,7 +8,7 @@ import (
 )
 
 func TestGenerateMCPConfig(t *testing.T) {
-	path, cleanup, err := GenerateMCPConfig(12345)
+	path, cleanup, err := GenerateMCPConfig(12345, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -90,7 +90,7 @@ func TestGenerateMCPConfig_MergesUserServers(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	path, cleanup, err := GenerateMCPConfig(9999)
+	path, cleanup, err := GenerateMCPConfig(9999, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -146,7 +146,7 @@ func TestGenerateMCPConfig_CopycatAuthTakesPrecedence(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	path, cleanup, err := GenerateMCPConfig(7777)
+	path, cleanup, err := GenerateMCPConfig(7777, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,151 @@
+package permission
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy is the permission decisions saved across runs, so AI tools aren't
+// asked the same thing again on every run. Deny rules win over allow rules.
+type Policy struct {
+	Allow []Rule `yaml:"allow,omitempty"`
+	Deny  []Rule `yaml:"deny,omitempty"`
+}
+
+// Rule matches the commands a decision applies to.
+type Rule struct {
+	Tool    string `yaml:"tool,omitempty"` // the tool it applies to, e.g. "Bash"; any tool when empty
+	Command string `yaml:"command"`        // e.g. "./mvnw test *"; * matches anything
+	Repo    string `yaml:"repo,omitempty"` // the repo it applies in; any repo when empty
+}
+
+// shellSyntax is what chains, pipes, backgrounds, substitutes or redirects
+// commands in a shell, so a command containing any of it can run more than
+// its leading program.
+var shellSyntax = []string{";", "&", "|", "`", "$(", ">", "<", "\n"}
+
+// hasShellSyntax reports whether command contains shell control syntax.
+func hasShellSyntax(command string) bool {
+	return slices.ContainsFunc(shellSyntax, func(s string) bool { return strings.Contains(command, s) })
+}
+
+// Matches reports whether the rule covers command run by tool in repo. A trailing
+// " *" also matches the command without arguments. A rule with a * never
+// matches a command with shell control syntax, so "./mvnw test *" doesn't
+// cover "./mvnw test && curl ... | sh"; only a rule for that exact command
+// does.
+func (r Rule) Matches(tool, repo, command string) bool {
+	if r.Tool != "" && r.Tool != tool {
+		return false
+	}
+	if r.Repo != "" && r.Repo != repo {
+		return false
+	}
+	if strings.Contains(r.Command, "*") && hasShellSyntax(command) {
+		return false
+	}
+	pattern := strings.ReplaceAll(regexp.QuoteMeta(strings.TrimSpace(r.Command)), `\*`, ".*")
+	if prefix, ok := strings.CutSuffix(pattern, " .*"); ok {
+		pattern = prefix + "( .*)?"
+	}
+	return regexp.MustCompile("^" + pattern + "$").MatchString(strings.TrimSpace(command))
+}
+
+// Decide returns whether the policy approves command run by tool in repo,
+// and whether it has a rule for it at all.
+func (p Policy) Decide(tool, repo, command string) (approved, decided bool) {
+	_, approved, decided = p.Match(tool, repo, command)
+	return approved, decided
+}
+
+// Match returns the rule that decides command run by tool in repo and
+// whether it allows it, or false for decided when no rule matches.
+func (p Policy) Match(tool, repo, command string) (rule Rule, approved, decided bool) {
+	matches := func(r Rule) bool { return r.Matches(tool, repo, command) }
+	if i := slices.IndexFunc(p.Deny, matches); i >= 0 {
+		return p.Deny[i], false, true
+	}
+	if i := slices.IndexFunc(p.Allow, matches); i >= 0 {
+		return p.Allow[i], true, true
+	}
+	return Rule{}, false, false
+}
+
+// With returns the policy with other's rules added.
+func (p Policy) With(other Policy) Policy {
+	return Policy{
+		Allow: append(slices.Clone(p.Allow), other.Allow...),
+		Deny:  append(slices.Clone(p.Deny), other.Deny...),
+	}
+}
+
+// Add saves a decision for the commands rule matches, replacing an opposite
+// one for the same rule.
+func (p *Policy) Add(rule Rule, allow bool) {
+	p.Allow = slices.DeleteFunc(p.Allow, func(r Rule) bool { return r == rule })
+	p.Deny = slices.DeleteFunc(p.Deny, func(r Rule) bool { return r == rule })
+	if allow {
+		p.Allow = append(p.Allow, rule)
+	} else {
+		p.Deny = append(p.Deny, rule)
+	}
+}
+
+// SuggestRule returns the rule to save a decision on command run by tool
+// with: its program and subcommand with any arguments, e.g. "./mvnw test *"
+// for "./mvnw test -pl core". A command with shell control syntax is saved
+// as it is, since no rule with a * would cover it. There is no rule for an
+// empty command, as "*" would decide everything the tool asks.
+func SuggestRule(tool, command string) (Rule, bool) {
+	parts := strings.Fields(command)
+	switch {
+	case len(parts) == 0:
+		return Rule{}, false
+	case hasShellSyntax(command):
+		return Rule{Tool: tool, Command: strings.TrimSpace(command)}, true
+	case len(parts) == 1, strings.HasPrefix(parts[1], "-"):
+		return Rule{Tool: tool, Command: parts[0] + " *"}, true
+	default:
+		return Rule{Tool: tool, Command: parts[0] + " " + parts[1] + " *"}, true
+	}
+}
+
+// LoadPolicy reads the policy file at path. A missing file is an empty policy.
+func LoadPolicy(path string) (Policy, error) {
+	var p Policy
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return p, err
+	}
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, r := range append(slices.Clone(p.Allow), p.Deny...) {
+		if strings.TrimSpace(r.Command) == "" {
+			return p, fmt.Errorf("every rule in %s needs a command", path)
+		}
+	}
+	return p, nil
+}
+
+// Save writes the policy to path.
+func (p Policy) Save(path string) error {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
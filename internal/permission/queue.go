@@ -0,0 +1,66 @@
+package permission
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// reattachInterval is how often the MCP handler looks for a restarted
+// permission server after losing the one it asked.
+const reattachInterval = 2 * time.Second
+
+// queueState is what the permission server keeps on disk, so a copycat
+// restarted after a crash can take over the requests its AI tools are still
+// waiting on.
+type queueState struct {
+	Port    int             `json:"port"`
+	Pending []queuedRequest `json:"pending,omitempty"`
+}
+
+type queuedRequest struct {
+	permissionHTTPRequest
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+func readQueue(path string) (queueState, error) {
+	var state queueState
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// writeQueue replaces the queue file in one step, so the handler never reads
+// half of it.
+func writeQueue(path string, state queueState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// serverAlive reports whether a permission server still listens on port.
+func serverAlive(port int) bool {
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
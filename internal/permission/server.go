@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -22,11 +24,16 @@ type PermissionServer struct {
 	server   *http.Server
 	statusCh chan<- tea.Msg
 
-	mu      sync.Mutex
-	pending map[string]chan PermissionResponse
+	mu        sync.Mutex
+	pending   map[string]chan PermissionResponse
+	queue     map[string]queuedRequest // pending requests as saved to queuePath
+	queuePath string                   // set by Persist
+	policy    *Policy                  // set by AnswerFromPolicy
+	auditPath string
 }
 
 type permissionHTTPRequest struct {
+	ID        string         `json:"id,omitempty"` // kept by the handler when it asks again after a restart
 	ToolName  string         `json:"tool_name"`
 	Command   string         `json:"command"`
 	Repo      string         `json:"repo"`
@@ -60,6 +67,7 @@ func NewPermissionServer(statusCh chan<- tea.Msg) (*PermissionServer, error) {
 		listener: listener,
 		statusCh: statusCh,
 		pending:  make(map[string]chan PermissionResponse),
+		queue:    make(map[string]queuedRequest),
 	}
 
 	mux := http.NewServeMux()
@@ -76,6 +84,62 @@ func (ps *PermissionServer) Port() int {
 	return ps.listener.Addr().(*net.TCPAddr).Port
 }
 
+// Persist keeps the server's port and pending requests in the queue file at
+// path. Requests a crashed copycat left there are queued again for the
+// user to answer, and their handlers, which keep asking until they time
+// out, are answered once they reach this server. Persist fails when another
+// running copycat owns the queue file.
+func (ps *PermissionServer) Persist(path string) error {
+	previous, err := readQueue(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if previous.Port != 0 && previous.Port != ps.Port() && serverAlive(previous.Port) {
+		return fmt.Errorf("another copycat is answering permission requests from %s", path)
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.queuePath = path
+	for _, queued := range previous.Pending {
+		if time.Since(queued.RequestedAt) > permissionTimeout || ps.pending[queued.ID] != nil {
+			continue
+		}
+		responseCh := make(chan PermissionResponse, 1)
+		ps.pending[queued.ID] = responseCh
+		ps.queue[queued.ID] = queued
+		req := newPermissionRequest(queued.permissionHTTPRequest, responseCh)
+		req.Restored = true
+		go func() { ps.statusCh <- PermissionRequestMsg{Request: req} }()
+	}
+	return ps.saveQueue()
+}
+
+// AnswerFromPolicy makes the server answer every request from policy alone
+// instead of asking in the TUI, denying what no rule allows, so runs can go
+// unattended. Each decision is appended to the audit log at auditPath and
+// reported to the TUI as a PolicyDecisionMsg.
+func (ps *PermissionServer) AnswerFromPolicy(policy Policy, auditPath string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.policy = &policy
+	ps.auditPath = auditPath
+}
+
+// saveQueue writes the pending requests to the queue file, if persisted.
+// The caller holds ps.mu.
+func (ps *PermissionServer) saveQueue() error {
+	if ps.queuePath == "" {
+		return nil
+	}
+	state := queueState{Port: ps.Port()}
+	for _, queued := range ps.queue {
+		state.Pending = append(state.Pending, queued)
+	}
+	sort.Slice(state.Pending, func(i, j int) bool { return state.Pending[i].RequestedAt.Before(state.Pending[j].RequestedAt) })
+	return writeQueue(ps.queuePath, state)
+}
+
 func (ps *PermissionServer) handlePermission(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -88,22 +152,86 @@ func (ps *PermissionServer) handlePermission(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	id := uuid.New().String()
-	responseCh := make(chan PermissionResponse, 1)
+	if req.ID == "" {
+		req.ID = uuid.New().String()
+	}
 
 	ps.mu.Lock()
-	ps.pending[id] = responseCh
+	policy, auditPath := ps.policy, ps.auditPath
+	ps.mu.Unlock()
+	if policy != nil {
+		d := decide(*policy, req)
+		if err := appendDecision(auditPath, d); err != nil {
+			// An approval that can't be audited is not given
+			d.Approved = false
+			d.Reason = fmt.Sprintf("failed to write the audit log: %v", err)
+		}
+		go func() { ps.statusCh <- PolicyDecisionMsg{Decision: d} }()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(permissionHTTPResponse{Approved: d.Approved})
+		return
+	}
+
+	// A handler asking again after a restart waits for the queued request
+	ps.mu.Lock()
+	responseCh, reattached := ps.pending[req.ID]
+	if !reattached {
+		responseCh = make(chan PermissionResponse, 1)
+		ps.pending[req.ID] = responseCh
+		ps.queue[req.ID] = queuedRequest{permissionHTTPRequest: req, RequestedAt: time.Now()}
+		ps.saveQueue()
+	}
 	ps.mu.Unlock()
 
 	defer func() {
 		ps.mu.Lock()
-		delete(ps.pending, id)
+		delete(ps.pending, req.ID)
+		delete(ps.queue, req.ID)
+		ps.saveQueue()
 		ps.mu.Unlock()
 	}()
 
-	// Build the permission request
+	if !reattached {
+		ps.statusCh <- PermissionRequestMsg{Request: newPermissionRequest(req, responseCh)}
+	}
+
+	// Wait for user response or timeout
+	select {
+	case resp := <-responseCh:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(permissionHTTPResponse{Approved: resp.Approved, Answer: resp.Answer})
+	case <-time.After(permissionTimeout):
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(permissionHTTPResponse{Approved: false})
+	}
+}
+
+// Shutdown gracefully shuts down the server and denies any pending requests.
+func (ps *PermissionServer) Shutdown(ctx context.Context) error {
+	ps.mu.Lock()
+	for id, ch := range ps.pending {
+		// Restored requests may hold an answer their handler never collected
+		select {
+		case ch <- PermissionResponse{Approved: false}:
+		default:
+		}
+		delete(ps.pending, id)
+	}
+	ps.queue = make(map[string]queuedRequest)
+	if ps.queuePath != "" {
+		os.Remove(ps.queuePath)
+		ps.queuePath = ""
+	}
+	ps.mu.Unlock()
+
+	return ps.server.Shutdown(ctx)
+}
+
+// newPermissionRequest builds the request shown in the TUI from the one the
+// handler sent.
+func newPermissionRequest(req permissionHTTPRequest, responseCh chan PermissionResponse) PermissionRequest {
 	permReq := PermissionRequest{
-		ID:         id,
+		ID:         req.ID,
 		Repo:       req.Repo,
 		ToolName:   req.ToolName,
 		Command:    req.Command,
@@ -127,29 +255,5 @@ func (ps *PermissionServer) handlePermission(w http.ResponseWriter, r *http.Requ
 			permReq.Questions = append(permReq.Questions, question)
 		}
 	}
-
-	// Send to TUI
-	ps.statusCh <- PermissionRequestMsg{Request: permReq}
-
-	// Wait for user response or timeout
-	select {
-	case resp := <-responseCh:
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(permissionHTTPResponse{Approved: resp.Approved, Answer: resp.Answer})
-	case <-time.After(permissionTimeout):
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(permissionHTTPResponse{Approved: false})
-	}
-}
-
-// Shutdown gracefully shuts down the server and denies any pending requests.
-func (ps *PermissionServer) Shutdown(ctx context.Context) error {
-	ps.mu.Lock()
-	for id, ch := range ps.pending {
-		ch <- PermissionResponse{Approved: false}
-		delete(ps.pending, id)
-	}
-	ps.mu.Unlock()
-
-	return ps.server.Shutdown(ctx)
+	return permReq
 }
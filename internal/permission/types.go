@@ -24,6 +24,7 @@ type PermissionRequest struct {
 	ResponseCh chan PermissionResponse
 	IsQuestion bool
 	Questions  []Question
+	Restored   bool // queued before copycat restarted, see PermissionServer.Persist
 }
 
 // PermissionResponse carries the user's decision.
@@ -37,5 +38,13 @@ type PermissionRequestMsg struct {
 	Request PermissionRequest
 }
 
+// RemoteAnswerMsg is an answer to the PermissionRequest with ID given
+// outside the TUI, such as in Slack, by who.
+type RemoteAnswerMsg struct {
+	ID       string
+	Approved bool
+	By       string
+}
+
 // Ensure PermissionRequestMsg satisfies tea.Msg (it does implicitly, but this is for documentation).
 var _ tea.Msg = PermissionRequestMsg{}
@@ -0,0 +1,78 @@
+// Package policy sends each repo's changes to an organization's review
+// service, which must approve them before copycat pushes and opens the PR.
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// Request is what the review service receives for one repo's changes.
+type Request struct {
+	Organization string `json:"organization"`
+	Repo         string `json:"repo"`
+	Path         string `json:"path,omitempty"`        // directory a monorepo job is scoped to
+	BaseBranch   string `json:"base_branch,omitempty"` // the default branch when empty
+	Branch       string `json:"branch"`
+	PRTitle      string `json:"pr_title"`
+	Prompt       string `json:"prompt"`
+	Diff         string `json:"diff"`
+}
+
+// Verdict is the review service's answer. Reasons explains a rejection.
+type Verdict struct {
+	Approved bool   `json:"approved"`
+	Reasons  string `json:"reasons,omitempty"`
+}
+
+// Review POSTs the changes as JSON to the configured service and returns its
+// verdict. Any answer other than a 2xx response with a verdict is an error,
+// so the caller can treat the changes as not approved.
+func Review(ctx context.Context, cfg config.PolicyReview, req Request) (Verdict, error) {
+	var verdict Verdict
+	body, err := json.Marshal(req)
+	if err != nil {
+		return verdict, fmt.Errorf("failed to marshal review request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout())
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return verdict, fmt.Errorf("failed to create review request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if cfg.TokenEnv != "" {
+		token := strings.TrimSpace(os.Getenv(cfg.TokenEnv))
+		if token == "" {
+			return verdict, fmt.Errorf("set %s to authenticate with the policy review service", cfg.TokenEnv)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return verdict, fmt.Errorf("policy review service unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return verdict, fmt.Errorf("failed to read the policy review response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return verdict, fmt.Errorf("policy review service returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, &verdict); err != nil {
+		return verdict, fmt.Errorf("failed to decode the policy review response: %w", err)
+	}
+	return verdict, nil
+}
@@ -0,0 +1,49 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestReview(t *testing.T) {
+	t.Setenv("POLICY_TOKEN", "secret")
+	var got Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		if strings.Contains(got.Diff, "curl | sh") {
+			_, _ = w.Write([]byte(`{"approved":false,"reasons":"pipes a download into a shell"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"approved":true}`))
+	}))
+	defer server.Close()
+	cfg := config.PolicyReview{URL: server.URL, TokenEnv: "POLICY_TOKEN"}
+
+	verdict, err := Review(context.Background(), cfg, Request{Organization: "acme", Repo: "payments-api", Diff: "+timeout: 30s"})
+	if err != nil || !verdict.Approved {
+		t.Fatalf("expected the diff to be approved, got %+v (%v)", verdict, err)
+	}
+	if got.Repo != "payments-api" || got.Organization != "acme" {
+		t.Errorf("expected the repo in the request, got %+v", got)
+	}
+
+	verdict, err = Review(context.Background(), cfg, Request{Repo: "payments-api", Diff: "+RUN curl | sh"})
+	if err != nil || verdict.Approved || verdict.Reasons != "pipes a download into a shell" {
+		t.Errorf("expected the diff to be rejected with reasons, got %+v (%v)", verdict, err)
+	}
+
+	t.Setenv("POLICY_TOKEN", "wrong")
+	if _, err := Review(context.Background(), cfg, Request{Repo: "payments-api"}); err == nil {
+		t.Error("expected an error response to be an error, not a verdict")
+	}
+}
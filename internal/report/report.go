@@ -0,0 +1,299 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/saltpay/copycat/v2/internal/history"
+	"github.com/saltpay/copycat/v2/internal/i18n"
+	"github.com/saltpay/copycat/v2/internal/timeline"
+)
+
+// maxDiffBytes caps the diff embedded per repo so reports stay shareable.
+const maxDiffBytes = 100 * 1024
+
+// Outcome classifies a repo's final state for charts and styling.
+type Outcome string
+
+const (
+	OutcomeSucceeded Outcome = "succeeded"
+	OutcomeSkipped   Outcome = "skipped"
+	OutcomeFailed    Outcome = "failed"
+	OutcomeCancelled Outcome = "cancelled"
+)
+
+// RepoResult is a single repository's section in the report.
+type RepoResult struct {
+	Repo     string
+	Outcome  Outcome
+	Status   string
+	PRURL    string
+	Diff     string
+	Finding  string
+	Timeline []timeline.Phase // how long each phase of the repo's pipeline took
+}
+
+// Run holds everything rendered into a run report.
+type Run struct {
+	Action      string // "local", "assessment" or "issues"
+	Title       string // PR title or assessment question
+	Prompt      string
+	GeneratedAt time.Time
+	Summary     string // assessment summary, if any
+	Repos       []RepoResult
+	Campaign    *history.Campaign // progress across runs sharing the PR title, if any
+}
+
+// statusCount is one bar in the status chart.
+type statusCount struct {
+	Outcome Outcome
+	Count   int
+	Percent int
+}
+
+// Counts returns how many repos ended in each outcome, in a stable order.
+func (r Run) Counts() []statusCount {
+	counts := make(map[Outcome]int)
+	for _, repo := range r.Repos {
+		counts[repo.Outcome]++
+	}
+
+	var result []statusCount
+	for _, o := range []Outcome{OutcomeSucceeded, OutcomeSkipped, OutcomeFailed, OutcomeCancelled} {
+		if counts[o] == 0 {
+			continue
+		}
+		pct := 0
+		if len(r.Repos) > 0 {
+			pct = counts[o] * 100 / len(r.Repos)
+		}
+		result = append(result, statusCount{Outcome: o, Count: counts[o], Percent: pct})
+	}
+	return result
+}
+
+// phaseBar is one bar in the time-spent chart.
+type phaseBar struct {
+	Name     string
+	Duration string
+	Percent  int
+}
+
+// TimeSpent returns the time all repos spent in each phase, scaled to the
+// phase that took longest, or nil when no repo recorded a timeline.
+func (r Run) TimeSpent() []phaseBar {
+	var timelines [][]timeline.Phase
+	for _, repo := range r.Repos {
+		timelines = append(timelines, repo.Timeline)
+	}
+	totals := timeline.Totals(timelines)
+	var longest time.Duration
+	for _, p := range totals {
+		longest = max(longest, p.Duration)
+	}
+
+	var bars []phaseBar
+	for _, p := range totals {
+		pct := 0
+		if longest > 0 {
+			pct = int(p.Duration * 100 / longest)
+		}
+		bars = append(bars, phaseBar{Name: p.Name, Duration: timeline.FormatDuration(p.Duration), Percent: pct})
+	}
+	return bars
+}
+
+// campaignChart holds the SVG polyline coordinates for the campaign chart.
+type campaignChart struct {
+	Width, Height  int
+	Opened, Merged string
+	OpenedCount    int
+	MergedCount    int
+	FirstDay       string
+	LastDay        string
+}
+
+const (
+	chartWidth  = 600
+	chartHeight = 160
+)
+
+// CampaignChart returns the opened vs merged lines for the campaign, or nil if there is no history.
+func (r Run) CampaignChart() *campaignChart {
+	if r.Campaign == nil || len(r.Campaign.Points) == 0 {
+		return nil
+	}
+	points := r.Campaign.Points
+	last := points[len(points)-1]
+
+	scale := last.Opened
+	if scale == 0 {
+		scale = 1
+	}
+	coords := func(value func(history.DayPoint) int) string {
+		var parts []string
+		for i, p := range points {
+			x := 0
+			if len(points) > 1 {
+				x = i * chartWidth / (len(points) - 1)
+			}
+			y := chartHeight - value(p)*chartHeight/scale
+			parts = append(parts, fmt.Sprintf("%d,%d", x, y))
+			if len(points) == 1 {
+				parts = append(parts, fmt.Sprintf("%d,%d", chartWidth, y))
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+
+	return &campaignChart{
+		Width:       chartWidth,
+		Height:      chartHeight,
+		Opened:      coords(func(p history.DayPoint) int { return p.Opened }),
+		Merged:      coords(func(p history.DayPoint) int { return p.Merged }),
+		OpenedCount: last.Opened,
+		MergedCount: last.Merged,
+		FirstDay:    points[0].Day.Format("2006-01-02"),
+		LastDay:     last.Day.Format("2006-01-02"),
+	}
+}
+
+// Write renders the run as a self-contained HTML file in dir and returns its
+// path. The report is written in the language set with i18n.SetLanguage.
+func Write(dir string, run Run) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	if run.GeneratedAt.IsZero() {
+		run.GeneratedAt = time.Now()
+	}
+	sort.Slice(run.Repos, func(i, j int) bool {
+		return run.Repos[i].Repo < run.Repos[j].Repo
+	})
+	for i := range run.Repos {
+		run.Repos[i].Diff = truncateDiff(run.Repos[i].Diff)
+	}
+
+	// The random suffix keeps runs finishing in the same second apart
+	f, err := os.CreateTemp(dir, fmt.Sprintf("copycat-report-%s-*.html", run.GeneratedAt.Format("20060102-150405")))
+	if err != nil {
+		return "", fmt.Errorf("failed to create report: %w", err)
+	}
+	defer f.Close()
+	path := f.Name()
+	if err := f.Chmod(0o644); err != nil {
+		return "", fmt.Errorf("failed to create report: %w", err)
+	}
+
+	if err := reportTemplate.Execute(f, run); err != nil {
+		return "", fmt.Errorf("failed to render report: %w", err)
+	}
+	return path, nil
+}
+
+// truncateDiff cuts diff down to maxDiffBytes, at the start of a character
+// so none is split.
+func truncateDiff(diff string) string {
+	if len(diff) <= maxDiffBytes {
+		return diff
+	}
+	end := maxDiffBytes
+	for end > 0 && !utf8.RuneStart(diff[end]) {
+		end--
+	}
+	return diff[:end] + "\n...(truncated)"
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"lines":    func(s string) []string { return strings.Split(strings.TrimRight(s, "\n"), "\n") },
+	"timeline": timeline.Format,
+	"t":        i18n.T,
+	"lang":     i18n.Language,
+	"diffClass": func(line string) string {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			return "meta"
+		case strings.HasPrefix(line, "+"):
+			return "add"
+		case strings.HasPrefix(line, "-"):
+			return "del"
+		case strings.HasPrefix(line, "@@"):
+			return "hunk"
+		}
+		return ""
+	},
+}).Parse(reportHTML))
+
+const reportHTML = `<!DOCTYPE html>
+<html lang="{{lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{t "Copycat report — %s" .Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 2rem auto; max-width: 1100px; color: #222; padding: 0 1rem; }
+h1 { color: #d63384; margin-bottom: 0.2rem; }
+.meta { color: #777; font-size: 0.9rem; }
+.prompt, .summary { background: #f6f6f8; border-left: 4px solid #d63384; padding: 0.8rem 1rem; white-space: pre-wrap; }
+.chart { margin: 1rem 0 2rem; }
+.bar-row { display: flex; align-items: center; margin: 0.3rem 0; }
+.bar-label { width: 110px; text-transform: capitalize; }
+.bar { height: 20px; border-radius: 3px; min-width: 2px; }
+.bar-count { margin-left: 0.5rem; color: #555; }
+.succeeded { background: #2da44e; } .skipped { background: #8c959f; } .failed { background: #cf222e; } .cancelled { background: #d4a72c; } .phase { background: #0969da; }
+details { border: 1px solid #ddd; border-radius: 6px; margin: 0.5rem 0; padding: 0.5rem 0.8rem; }
+summary { cursor: pointer; font-weight: 600; }
+.badge { display: inline-block; color: #fff; border-radius: 10px; padding: 0 0.5rem; font-size: 0.8rem; margin-left: 0.5rem; text-transform: capitalize; }
+svg.campaign { border-bottom: 1px solid #ddd; overflow: visible; }
+pre { background: #0d1117; color: #c9d1d9; padding: 0.8rem; overflow-x: auto; font-size: 0.8rem; border-radius: 6px; }
+pre .add { color: #3fb950; } pre .del { color: #f85149; } pre .hunk { color: #a371f7; } pre .meta { color: #8b949e; }
+</style>
+</head>
+<body>
+<h1>🐱 {{.Title}}</h1>
+<div class="meta">{{if eq .Action "assessment"}}{{t "Assessment"}}{{else if eq .Action "issues"}}{{t "GitHub issues"}}{{else}}{{t "Code changes"}}{{end}} · {{t "%d repositories" (len .Repos)}} · {{t "generated %s" (.GeneratedAt.Format "2006-01-02 15:04 MST")}}</div>
+
+{{if .Prompt}}<h2>{{t "Prompt"}}</h2>
+<div class="prompt">{{.Prompt}}</div>{{end}}
+
+<h2>{{t "Status"}}</h2>
+<div class="chart">
+{{range .Counts}}<div class="bar-row"><span class="bar-label">{{t (print .Outcome)}}</span><span class="bar {{.Outcome}}" style="width: {{.Percent}}%"></span><span class="bar-count">{{.Count}}</span></div>
+{{end}}</div>
+
+{{with .CampaignChart}}<h2>{{t "Campaign progress"}}</h2>
+<svg class="campaign" viewBox="-5 -5 {{.Width}} {{.Height}}" width="100%" height="{{.Height}}" preserveAspectRatio="none" role="img" aria-label="{{t "PRs opened vs merged"}}">
+<polyline fill="none" stroke="#0969da" stroke-width="2" points="{{.Opened}}"/>
+<polyline fill="none" stroke="#8250df" stroke-width="2" points="{{.Merged}}"/>
+</svg>
+<div class="meta">{{.FirstDay}} → {{.LastDay}} · <span style="color: #0969da">{{t "opened %d" .OpenedCount}}</span> · <span style="color: #8250df">{{t "merged %d" .MergedCount}}</span></div>
+{{end}}{{if and .Campaign .Campaign.Open}}<details><summary>{{t "%d PRs not merged yet" (len .Campaign.Open)}}</summary>
+<ul>{{range .Campaign.Open}}<li><a href="{{.}}">{{.}}</a></li>{{end}}</ul>
+</details>{{end}}
+
+{{with .TimeSpent}}<h2>{{t "Time spent"}}</h2>
+<div class="chart">
+{{range .}}<div class="bar-row"><span class="bar-label">{{.Name}}</span><span class="bar phase" style="width: {{.Percent}}%"></span><span class="bar-count">{{.Duration}}</span></div>
+{{end}}</div>
+{{end}}
+{{if .Summary}}<h2>{{t "Summary"}}</h2>
+<div class="summary">{{.Summary}}</div>{{end}}
+
+<h2>{{t "Repositories"}}</h2>
+{{range .Repos}}<details{{if eq .Outcome "failed"}} open{{end}}>
+<summary>{{.Repo}}<span class="badge {{.Outcome}}">{{t (print .Outcome)}}</span></summary>
+<p>{{if .PRURL}}<a href="{{.PRURL}}">{{.PRURL}}</a>{{else}}{{.Status}}{{end}}</p>
+{{if .Finding}}<div class="summary">{{.Finding}}</div>{{end}}
+{{if .Timeline}}<p class="meta">⏱ {{timeline .Timeline}}</p>{{end}}
+{{if .Diff}}<pre>{{range lines .Diff}}<span class="{{diffClass .}}">{{.}}</span>
+{{end}}</pre>{{end}}
+</details>
+{{end}}
+</body>
+</html>
+`
@@ -0,0 +1,181 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/saltpay/copycat/v2/internal/history"
+	"github.com/saltpay/copycat/v2/internal/i18n"
+	"github.com/saltpay/copycat/v2/internal/timeline"
+)
+
+func TestCounts(t *testing.T) {
+	run := Run{Repos: []RepoResult{
+		{Repo: "a", Outcome: OutcomeSucceeded},
+		{Repo: "b", Outcome: OutcomeSucceeded},
+		{Repo: "c", Outcome: OutcomeFailed},
+		{Repo: "d", Outcome: OutcomeSkipped},
+	}}
+
+	counts := run.Counts()
+	expected := []statusCount{
+		{Outcome: OutcomeSucceeded, Count: 2, Percent: 50},
+		{Outcome: OutcomeSkipped, Count: 1, Percent: 25},
+		{Outcome: OutcomeFailed, Count: 1, Percent: 25},
+	}
+	if len(counts) != len(expected) {
+		t.Fatalf("expected %d counts, got %v", len(expected), counts)
+	}
+	for i, want := range expected {
+		if counts[i] != want {
+			t.Errorf("count %d: expected %+v, got %+v", i, want, counts[i])
+		}
+	}
+}
+
+func TestTimeSpent(t *testing.T) {
+	run := Run{Repos: []RepoResult{
+		{Repo: "a", Timeline: []timeline.Phase{{Name: "clone", Duration: 10 * time.Second}, {Name: "AI", Duration: 3 * time.Minute}}},
+		{Repo: "b", Timeline: []timeline.Phase{{Name: "clone", Duration: 20 * time.Second}, {Name: "AI", Duration: time.Minute}}},
+		{Repo: "c"},
+	}}
+
+	bars := run.TimeSpent()
+	expected := []phaseBar{
+		{Name: "clone", Duration: "30s", Percent: 12},
+		{Name: "AI", Duration: "4m00s", Percent: 100},
+	}
+	if len(bars) != len(expected) {
+		t.Fatalf("expected %d bars, got %v", len(expected), bars)
+	}
+	for i, want := range expected {
+		if bars[i] != want {
+			t.Errorf("bar %d: expected %+v, got %+v", i, want, bars[i])
+		}
+	}
+	if (Run{Repos: []RepoResult{{Repo: "c"}}}).TimeSpent() != nil {
+		t.Error("expected no chart without timelines")
+	}
+}
+
+func TestWriteEscapesContent(t *testing.T) {
+	dir := t.TempDir()
+	path, err := Write(dir, Run{
+		Action: "local",
+		Title:  "Bump deps",
+		Repos: []RepoResult{{
+			Repo:    "svc",
+			Outcome: OutcomeSucceeded,
+			PRURL:   "https://github.com/org/svc/pull/1",
+			Diff:    "+<script>alert(1)</script>\n",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	if strings.Contains(html, "<script>alert") {
+		t.Error("expected diff content to be escaped")
+	}
+	if !strings.Contains(html, `<span class="add">&#43;&lt;script&gt;`) {
+		t.Error("expected added diff line to be highlighted")
+	}
+	if !strings.Contains(html, "https://github.com/org/svc/pull/1") {
+		t.Error("expected PR link in report")
+	}
+}
+
+func TestWriteKeepsReportsOfTheSameSecond(t *testing.T) {
+	dir := t.TempDir()
+	generated := time.Date(2026, 10, 17, 15, 4, 5, 0, time.UTC)
+	first, err := Write(dir, Run{Action: "local", Title: "First", GeneratedAt: generated})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Write(dir, Run{Action: "local", Title: "Second", GeneratedAt: generated})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatalf("expected reports of runs ending in the same second to get their own files, both got %s", first)
+	}
+	if !strings.HasPrefix(filepath.Base(first), "copycat-report-20261017-150405-") {
+		t.Errorf("expected the report named after when it was generated, got %s", first)
+	}
+	if data, _ := os.ReadFile(first); !strings.Contains(string(data), "First") {
+		t.Error("expected the first report to be kept")
+	}
+}
+
+func TestTruncateDiff(t *testing.T) {
+	// A 2-byte character straddles the limit
+	diff := strings.Repeat("a", maxDiffBytes-1) + "é" + strings.Repeat("b", 10)
+	got := truncateDiff(diff)
+	if !utf8.ValidString(got) || got != strings.Repeat("a", maxDiffBytes-1)+"\n...(truncated)" {
+		t.Errorf("expected the diff cut before the split character, got ...%q", got[len(got)-20:])
+	}
+	if short := "+é\n"; truncateDiff(short) != short {
+		t.Error("expected a short diff to be kept whole")
+	}
+}
+
+func TestWriteTranslates(t *testing.T) {
+	i18n.SetLanguage(i18n.Portuguese)
+	t.Cleanup(func() { i18n.SetLanguage(i18n.English) })
+
+	path, err := Write(t.TempDir(), Run{
+		Action: "local",
+		Title:  "Bump deps",
+		Repos:  []RepoResult{{Repo: "svc", Outcome: OutcomeFailed}},
+	})
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{`<html lang="pt-PT">`, "Alterações de código", "Repositórios", ">falhado<"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in the report", want)
+		}
+	}
+}
+
+func TestCampaignChart(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+	run := Run{Campaign: &history.Campaign{Points: []history.DayPoint{
+		{Day: day(1), Opened: 2, Merged: 0},
+		{Day: day(2), Opened: 4, Merged: 1},
+		{Day: day(3), Opened: 4, Merged: 4},
+	}}}
+
+	chart := run.CampaignChart()
+	if chart == nil {
+		t.Fatal("expected a chart")
+	}
+	if chart.Opened != "0,80 300,0 600,0" {
+		t.Errorf("unexpected opened line %q", chart.Opened)
+	}
+	if chart.Merged != "0,160 300,120 600,0" {
+		t.Errorf("unexpected merged line %q", chart.Merged)
+	}
+	if chart.OpenedCount != 4 || chart.MergedCount != 4 {
+		t.Errorf("unexpected totals: %+v", chart)
+	}
+
+	if (Run{}).CampaignChart() != nil {
+		t.Error("expected no chart without campaign history")
+	}
+}
@@ -0,0 +1,179 @@
+// Package sarif reads scanner reports in SARIF format (CodeQL, Snyk, ...) and
+// turns their results into per-repo prompt context.
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// maxFindingsInPrompt caps how many findings are listed in one repo's prompt.
+const maxFindingsInPrompt = 100
+
+// Finding is a single scanner result.
+type Finding struct {
+	Tool    string
+	RuleID  string
+	Level   string // error, warning or note
+	Message string
+	File    string
+	Line    int
+}
+
+type sarifLog struct {
+	Runs []run `json:"runs"`
+}
+
+type run struct {
+	Tool struct {
+		Driver struct {
+			Name  string `json:"name"`
+			Rules []struct {
+				ID                   string `json:"id"`
+				DefaultConfiguration struct {
+					Level string `json:"level"`
+				} `json:"defaultConfiguration"`
+			} `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	VersionControlProvenance []struct {
+		RepositoryURI string `json:"repositoryUri"`
+	} `json:"versionControlProvenance"`
+	Results []result `json:"results"`
+}
+
+type result struct {
+	RuleID  string `json:"ruleId"`
+	Level   string `json:"level"`
+	Message struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Locations []struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region struct {
+				StartLine int `json:"startLine"`
+			} `json:"region"`
+		} `json:"physicalLocation"`
+	} `json:"locations"`
+}
+
+// Load reads a SARIF file and groups its findings by repo. Runs are matched
+// to a repo through their version control provenance; otherwise each result
+// is matched by a path segment naming one of repos. It also returns how many
+// findings matched no repo.
+func Load(filePath string, repos []string) (map[string][]Finding, int, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read SARIF file: %w", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse SARIF file %s: %w", filePath, err)
+	}
+	grouped, unmatched := group(log, repos)
+	return grouped, unmatched, nil
+}
+
+func group(log sarifLog, repos []string) (map[string][]Finding, int) {
+	known := make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		known[repo] = true
+	}
+
+	grouped := make(map[string][]Finding)
+	unmatched := 0
+	for _, r := range log.Runs {
+		runRepo := ""
+		for _, vcp := range r.VersionControlProvenance {
+			if name := repoName(vcp.RepositoryURI); known[name] {
+				runRepo = name
+				break
+			}
+		}
+
+		levels := make(map[string]string)
+		for _, rule := range r.Tool.Driver.Rules {
+			levels[rule.ID] = rule.DefaultConfiguration.Level
+		}
+
+		for _, res := range r.Results {
+			f := Finding{
+				Tool:    r.Tool.Driver.Name,
+				RuleID:  res.RuleID,
+				Level:   res.Level,
+				Message: strings.Join(strings.Fields(res.Message.Text), " "),
+			}
+			if f.Level == "" {
+				f.Level = levels[res.RuleID]
+			}
+			if f.Level == "" {
+				f.Level = "warning" // the SARIF default
+			}
+			if len(res.Locations) > 0 {
+				loc := res.Locations[0].PhysicalLocation
+				f.File = strings.TrimPrefix(loc.ArtifactLocation.URI, "file://")
+				f.Line = loc.Region.StartLine
+			}
+
+			repo := runRepo
+			if repo == "" {
+				repo, f.File = splitRepoPath(f.File, known)
+			}
+			if repo == "" {
+				unmatched++
+				continue
+			}
+			grouped[repo] = append(grouped[repo], f)
+		}
+	}
+	return grouped, unmatched
+}
+
+// repoName returns the repository name from a clone or web URL.
+func repoName(uri string) string {
+	return strings.TrimSuffix(path.Base(strings.TrimRight(uri, "/")), ".git")
+}
+
+// splitRepoPath finds the first path segment naming a known repo and returns
+// it with the rest of the path.
+func splitRepoPath(file string, known map[string]bool) (string, string) {
+	segments := strings.Split(strings.TrimPrefix(file, "/"), "/")
+	for i, segment := range segments[:len(segments)-1] {
+		if known[segment] {
+			return segment, strings.Join(segments[i+1:], "/")
+		}
+	}
+	return "", file
+}
+
+// PromptContext renders a repo's findings as context appended to the prompt.
+func PromptContext(findings []Finding) string {
+	if len(findings) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\nScanner findings to fix in this repository:\n")
+	for i, f := range findings {
+		if i == maxFindingsInPrompt {
+			fmt.Fprintf(&b, "- ...and %d more\n", len(findings)-i)
+			break
+		}
+		location := f.File
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		rule := f.RuleID
+		if f.Tool != "" {
+			rule = f.Tool + " " + rule
+		}
+		fmt.Fprintf(&b, "- [%s] %s at %s: %s\n", f.Level, rule, location, f.Message)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,96 @@
+package sarif
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const codeQLReport = `{
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {"driver": {"name": "CodeQL", "rules": [{"id": "go/sql-injection", "defaultConfiguration": {"level": "error"}}]}},
+      "versionControlProvenance": [{"repositoryUri": "https://github.com/acme/payments-api.git"}],
+      "results": [
+        {
+          "ruleId": "go/sql-injection",
+          "message": {"text": "This query depends on\n a user-provided value."},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "internal/db.go"}, "region": {"startLine": 42}}}]
+        }
+      ]
+    },
+    {
+      "tool": {"driver": {"name": "Snyk Open Source"}},
+      "results": [
+        {
+          "ruleId": "SNYK-JS-LODASH-1",
+          "level": "note",
+          "message": {"text": "Prototype pollution"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file:///scan/web-app/package.json"}}}]
+        },
+        {
+          "ruleId": "SNYK-JS-OTHER",
+          "message": {"text": "Unknown repo"},
+          "locations": [{"physicalLocation": {"artifactLocation": {"uri": "elsewhere/package.json"}}}]
+        }
+      ]
+    }
+  ]
+}`
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.sarif")
+	if err := os.WriteFile(path, []byte(codeQLReport), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	grouped, unmatched, err := Load(path, []string{"payments-api", "web-app"})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if unmatched != 1 {
+		t.Errorf("expected 1 unmatched finding, got %d", unmatched)
+	}
+
+	want := Finding{Tool: "CodeQL", RuleID: "go/sql-injection", Level: "error", Message: "This query depends on a user-provided value.", File: "internal/db.go", Line: 42}
+	if got := grouped["payments-api"]; len(got) != 1 || got[0] != want {
+		t.Errorf("payments-api findings = %+v, want %+v", got, want)
+	}
+
+	want = Finding{Tool: "Snyk Open Source", RuleID: "SNYK-JS-LODASH-1", Level: "note", Message: "Prototype pollution", File: "package.json"}
+	if got := grouped["web-app"]; len(got) != 1 || got[0] != want {
+		t.Errorf("web-app findings = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.sarif")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Load(path, nil); err == nil {
+		t.Error("expected an error for invalid SARIF")
+	}
+}
+
+func TestPromptContext(t *testing.T) {
+	if got := PromptContext(nil); got != "" {
+		t.Errorf("expected no context without findings, got %q", got)
+	}
+
+	got := PromptContext([]Finding{
+		{Tool: "CodeQL", RuleID: "go/sql-injection", Level: "error", Message: "Injected", File: "db.go", Line: 42},
+		{RuleID: "R2", Level: "note", Message: "Whole file", File: "go.mod"},
+	})
+	want := "\n\nScanner findings to fix in this repository:\n- [error] CodeQL go/sql-injection at db.go:42: Injected\n- [note] R2 at go.mod: Whole file"
+	if got != want {
+		t.Errorf("PromptContext() = %q, want %q", got, want)
+	}
+
+	many := make([]Finding, maxFindingsInPrompt+5)
+	if got := PromptContext(many); !strings.HasSuffix(got, "- ...and 5 more") {
+		t.Errorf("expected long lists to be capped, got suffix %q", got[len(got)-30:])
+	}
+}
@@ -0,0 +1,321 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// slackAPIBase is where the Web API methods used for approvals live.
+var slackAPIBase = "https://slack.com/api/"
+
+// maxCommandLength keeps a command within the 3000 characters a Block Kit
+// section allows.
+const maxCommandLength = 2500
+
+// maxRequestAge is how old a signed request from Slack may be, against replays.
+const maxRequestAge = 5 * time.Minute
+
+// Approver posts permission requests to a Slack channel with Approve and
+// Deny buttons, and passes on the answers Slack sends when one is clicked.
+type Approver struct {
+	token    string
+	channel  string
+	secret   string
+	onAnswer func(id string, approved bool, by string)
+	onError  func(error)
+
+	listener net.Listener
+	server   *http.Server
+
+	mu       sync.Mutex
+	messages map[string]postedRequest // by request ID, from being asked until answered
+}
+
+// postedRequest is a permission request's message in the channel.
+type postedRequest struct {
+	ts   string // empty while the message is being posted
+	text string
+	note string // how it was settled while being posted, for when that returns
+}
+
+// closedNote replaces the buttons of requests still waiting when the run ends.
+const closedNote = "⏹ Not answered before the run ended"
+
+// StartApprover listens for Slack's interactivity requests at the configured
+// address and returns an Approver posting to the configured channel.
+// onAnswer is called with each request answered in Slack and who answered it,
+// and onError with messages whose buttons couldn't be taken off.
+func StartApprover(token string, cfg config.SlackApprovals, onAnswer func(id string, approved bool, by string), onError func(error)) (*Approver, error) {
+	secret := strings.TrimSpace(os.Getenv(cfg.SecretEnv()))
+	if secret == "" {
+		return nil, fmt.Errorf("set %s to the Slack app's signing secret to answer permission requests in Slack", cfg.SecretEnv())
+	}
+	listener, err := net.Listen("tcp", cfg.Addr())
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for Slack approvals: %w", err)
+	}
+
+	a := &Approver{
+		token:    token,
+		channel:  cfg.Channel,
+		secret:   secret,
+		onAnswer: onAnswer,
+		onError:  onError,
+		listener: listener,
+		messages: make(map[string]postedRequest),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slack/actions", a.handleAction)
+	a.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go a.server.Serve(listener)
+	return a, nil
+}
+
+// Addr returns the address Slack's interactivity requests are received at.
+func (a *Approver) Addr() string {
+	return a.listener.Addr().String()
+}
+
+// Ask posts the permission request with id to the channel. A request
+// withdrawn while it is being posted is settled as soon as it has been.
+func (a *Approver) Ask(id, repo, tool, command string) error {
+	text := fmt.Sprintf("🔐 *%s* wants to run %s", escape(repo), escape(tool))
+	if command != "" {
+		if len(command) > maxCommandLength {
+			end := maxCommandLength
+			for end > 0 && !utf8.RuneStart(command[end]) {
+				end--
+			}
+			command = command[:end] + "…"
+		}
+		text += ":\n```" + escape(command) + "```"
+	}
+	button := func(action, label, style string) map[string]any {
+		return map[string]any{
+			"type":      "button",
+			"action_id": action,
+			"text":      map[string]string{"type": "plain_text", "text": label},
+			"style":     style,
+			"value":     id,
+		}
+	}
+	msg := map[string]any{
+		"channel": a.channel,
+		"text":    text,
+		"blocks": []any{
+			section(text),
+			map[string]any{"type": "actions", "elements": []any{button("approve", "Approve", "primary"), button("deny", "Deny", "danger")}},
+		},
+	}
+
+	a.mu.Lock()
+	a.messages[id] = postedRequest{text: text}
+	a.mu.Unlock()
+
+	var resp struct {
+		TS string `json:"ts"`
+	}
+	err := a.call("chat.postMessage", msg, &resp)
+	a.mu.Lock()
+	posted := a.messages[id]
+	posted.ts = resp.TS
+	if err != nil || posted.note != "" {
+		delete(a.messages, id)
+	} else {
+		a.messages[id] = posted
+	}
+	a.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if posted.note != "" {
+		a.settle(posted, posted.note)
+	}
+	return nil
+}
+
+// Withdraw replaces the buttons of the request with id, answered somewhere
+// else, with note.
+func (a *Approver) Withdraw(id, note string) {
+	a.mu.Lock()
+	posted, ok := a.messages[id]
+	if ok && posted.ts == "" {
+		// Ask settles it once the message is posted
+		posted.note = note
+		a.messages[id] = posted
+		a.mu.Unlock()
+		return
+	}
+	delete(a.messages, id)
+	a.mu.Unlock()
+	if ok {
+		a.settle(posted, note)
+	}
+}
+
+// Close stops receiving answers, taking the buttons off the requests still
+// waiting.
+func (a *Approver) Close() {
+	a.server.Shutdown(context.Background())
+	a.mu.Lock()
+	var waiting []postedRequest
+	for id, posted := range a.messages {
+		if posted.ts == "" {
+			posted.note = closedNote
+			a.messages[id] = posted
+			continue
+		}
+		waiting = append(waiting, posted)
+		delete(a.messages, id)
+	}
+	a.mu.Unlock()
+	for _, posted := range waiting {
+		a.settle(posted, closedNote)
+	}
+}
+
+// settle replaces the buttons of a posted request with note, reporting when
+// they couldn't be, since clicking them then does nothing.
+func (a *Approver) settle(posted postedRequest, note string) {
+	err := a.call("chat.update", map[string]any{
+		"channel": a.channel,
+		"ts":      posted.ts,
+		"text":    posted.text,
+		"blocks":  []any{section(posted.text), section(note)},
+	}, nil)
+	if err != nil {
+		a.onError(fmt.Errorf("failed to take the buttons off a permission request in Slack (%s): %w", note, err))
+	}
+}
+
+// handleAction receives a button click, checked to come from Slack.
+func (a *Approver) handleAction(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if err := a.verify(r.Header, body, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	var payload struct {
+		User struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+		} `json:"user"`
+		Actions []struct {
+			ActionID string `json:"action_id"`
+			Value    string `json:"value"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(r.PostFormValue("payload")), &payload); err != nil || len(payload.Actions) == 0 {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	action := payload.Actions[0]
+	w.WriteHeader(http.StatusOK)
+
+	a.mu.Lock()
+	posted, ok := a.messages[action.Value]
+	delete(a.messages, action.Value)
+	a.mu.Unlock()
+	if !ok {
+		return
+	}
+	approved := action.ActionID == "approve"
+	verdict := "❌ Denied"
+	if approved {
+		verdict = "✅ Approved"
+	}
+	a.settle(posted, fmt.Sprintf("%s by <@%s>", verdict, payload.User.ID))
+	by := payload.User.Username
+	if by == "" {
+		by = payload.User.ID
+	}
+	a.onAnswer(action.Value, approved, by)
+}
+
+// verify checks a request's Slack signature, an HMAC of its timestamp and
+// body with the app's signing secret.
+func (a *Approver) verify(header http.Header, body []byte, now time.Time) error {
+	ts := header.Get("X-Slack-Request-Timestamp")
+	sent, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return errors.New("missing Slack request timestamp")
+	}
+	if math.Abs(now.Sub(time.Unix(sent, 0)).Seconds()) > maxRequestAge.Seconds() {
+		return errors.New("stale Slack request")
+	}
+	mac := hmac.New(sha256.New, []byte(a.secret))
+	fmt.Fprintf(mac, "v0:%s:%s", ts, body)
+	want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(want), []byte(header.Get("X-Slack-Signature"))) {
+		return errors.New("invalid Slack signature")
+	}
+	return nil
+}
+
+// call invokes a Slack Web API method with a JSON body, decoding the
+// response into out when it isn't nil.
+func (a *Approver) call(method string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, slackAPIBase+method, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Slack: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var result slackResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("slack API error: %s", result.Error)
+	}
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+// section is a Block Kit section of Markdown text.
+func section(text string) map[string]any {
+	return map[string]any{"type": "section", "text": map[string]string{"type": "mrkdwn", "text": text}}
+}
+
+// escape escapes the characters Slack's Markdown gives meaning to.
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
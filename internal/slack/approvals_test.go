@@ -0,0 +1,207 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestApprover(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		calls = append(calls, strings.TrimPrefix(r.URL.Path, "/")+" "+fmt.Sprint(body["text"]))
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"ok":true,"ts":"1700000000.000100"}`))
+	}))
+	defer api.Close()
+	previous := slackAPIBase
+	slackAPIBase = api.URL + "/"
+	t.Cleanup(func() { slackAPIBase = previous })
+
+	t.Setenv("TEST_SIGNING_SECRET", "s3cret")
+	answers := make(chan string, 1)
+	approver, err := StartApprover("xoxb-test", config.SlackApprovals{Channel: "#copycat-approvals", ListenAddr: "127.0.0.1:0", SigningSecretEnv: "TEST_SIGNING_SECRET"}, func(id string, approved bool, by string) {
+		answers <- fmt.Sprintf("%s %v %s", id, approved, by)
+	}, func(err error) {
+		t.Errorf("unexpected error %v", err)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer approver.Close()
+
+	if err := approver.Ask("req-1", "ledger-service", "Bash", "./mvnw test <all>"); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 || !strings.HasPrefix(calls[0], "chat.postMessage") || !strings.Contains(calls[0], "./mvnw test &lt;all&gt;") {
+		t.Fatalf("expected the request posted with its command escaped, got %v", calls)
+	}
+
+	click := func(secret string, sent time.Time) int {
+		payload := `{"user":{"id":"U1","username":"jane"},"actions":[{"action_id":"approve","value":"req-1"}]}`
+		body := url.Values{"payload": {payload}}.Encode()
+		ts := strconv.FormatInt(sent.Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(secret))
+		fmt.Fprintf(mac, "v0:%s:%s", ts, body)
+		req, _ := http.NewRequest(http.MethodPost, "http://"+approver.Addr()+"/slack/actions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Slack-Request-Timestamp", ts)
+		req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := click("wrong", time.Now()); code != http.StatusUnauthorized {
+		t.Errorf("expected a request signed with another secret to be refused, got %d", code)
+	}
+	if code := click("s3cret", time.Now().Add(-10*time.Minute)); code != http.StatusUnauthorized {
+		t.Errorf("expected a stale request to be refused, got %d", code)
+	}
+	if code := click("s3cret", time.Now()); code != http.StatusOK {
+		t.Fatalf("expected a signed click to be accepted, got %d", code)
+	}
+	select {
+	case got := <-answers:
+		if got != "req-1 true jane" {
+			t.Errorf("unexpected answer %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the click to answer the request")
+	}
+	mu.Lock()
+	last := calls[len(calls)-1]
+	mu.Unlock()
+	if !strings.HasPrefix(last, "chat.update") {
+		t.Errorf("expected the buttons to be replaced with the answer, got %v", calls)
+	}
+
+	// Answered requests ignore further clicks
+	click("s3cret", time.Now())
+	select {
+	case got := <-answers:
+		t.Errorf("expected a second click to be ignored, got %q", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestApproverCutsLongCommands(t *testing.T) {
+	var text string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		text = fmt.Sprint(body["text"])
+		_, _ = w.Write([]byte(`{"ok":true,"ts":"1700000000.000100"}`))
+	}))
+	defer api.Close()
+	previous := slackAPIBase
+	slackAPIBase = api.URL + "/"
+	t.Cleanup(func() { slackAPIBase = previous })
+
+	t.Setenv("TEST_SIGNING_SECRET", "s3cret")
+	approver, err := StartApprover("xoxb-test", config.SlackApprovals{Channel: "#copycat-approvals", ListenAddr: "127.0.0.1:0", SigningSecretEnv: "TEST_SIGNING_SECRET"}, func(string, bool, string) {}, func(err error) {
+		t.Errorf("unexpected error %v", err)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer approver.Close()
+
+	// "é" is two bytes, the second of them at the cut
+	command := strings.Repeat("a", maxCommandLength-1) + "é" + strings.Repeat("a", 10)
+	if err := approver.Ask("req-1", "ledger-service", "Bash", command); err != nil {
+		t.Fatal(err)
+	}
+	if !utf8.ValidString(text) || !strings.Contains(text, "```"+strings.Repeat("a", maxCommandLength-1)+"…```") {
+		t.Errorf("expected the command cut before the split character, got ...%q", text[max(len(text)-20, 0):])
+	}
+}
+
+func TestApproverWithdrawnWhilePosting(t *testing.T) {
+	posting := make(chan struct{})
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var updates []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			TS     string `json:"ts"`
+			Blocks []struct {
+				Text struct {
+					Text string `json:"text"`
+				} `json:"text"`
+			} `json:"blocks"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/chat.postMessage":
+			close(posting)
+			<-release
+			_, _ = w.Write([]byte(`{"ok":true,"ts":"1700000000.000100"}`))
+		case "/chat.update":
+			mu.Lock()
+			updates = append(updates, body.TS+" "+body.Blocks[len(body.Blocks)-1].Text.Text)
+			mu.Unlock()
+			// The message was deleted from the channel in the meantime
+			_, _ = w.Write([]byte(`{"ok":false,"error":"message_not_found"}`))
+		}
+	}))
+	defer api.Close()
+	previous := slackAPIBase
+	slackAPIBase = api.URL + "/"
+	t.Cleanup(func() { slackAPIBase = previous })
+
+	t.Setenv("TEST_SIGNING_SECRET", "s3cret")
+	errs := make(chan error, 1)
+	approver, err := StartApprover("xoxb-test", config.SlackApprovals{Channel: "#copycat-approvals", ListenAddr: "127.0.0.1:0", SigningSecretEnv: "TEST_SIGNING_SECRET"}, func(string, bool, string) {}, func(err error) {
+		errs <- err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer approver.Close()
+
+	asked := make(chan error)
+	go func() { asked <- approver.Ask("req-1", "ledger-service", "Bash", "./mvnw test") }()
+	<-posting
+	// Answered in the dashboard before Slack said where the message is
+	approver.Withdraw("req-1", "☑️ Answered in copycat")
+	close(release)
+	if err := <-asked; err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	got := strings.Join(updates, "; ")
+	mu.Unlock()
+	if got != "1700000000.000100 ☑️ Answered in copycat" {
+		t.Errorf("expected the request to be settled once posted, got %q", got)
+	}
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "message_not_found") {
+			t.Errorf("unexpected error %v", err)
+		}
+	default:
+		t.Error("expected the failed update to be reported")
+	}
+}
@@ -0,0 +1,189 @@
+package slack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// checkClient gives up on Slack quickly, since runs wait for the check.
+var checkClient = &http.Client{Timeout: 10 * time.Second}
+
+// maxChannelPages bounds how many pages of channels are read, 1000 each.
+const maxChannelPages = 50
+
+// channelIDPattern matches rooms given as a channel's ID rather than its
+// name, e.g. C0123ABCD.
+var channelIDPattern = regexp.MustCompile(`^[CG][A-Z0-9]{8,}$`)
+
+// channel is a conversation as listed by conversations.list or looked up by
+// conversations.info.
+type channel struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	IsMember   bool   `json:"is_member"`
+	IsArchived bool   `json:"is_archived"`
+}
+
+// CheckChannels returns a warning for each Slack room of projects that
+// notifications wouldn't reach: rooms that don't exist, are archived or are
+// private without the bot, and rooms the bot isn't in and couldn't join.
+// Public channels the bot isn't in yet are joined. Rooms given as IDs are
+// looked up one by one, and rooms given by name are searched for among the
+// channels the bot can see. An error means the channels couldn't be
+// checked at all.
+func CheckChannels(projects []config.Project, token string) ([]string, error) {
+	reposByRoom := make(map[string][]string)
+	for _, project := range projects {
+		if room := strings.TrimSpace(project.SlackRoom); room != "" {
+			reposByRoom[room] = append(reposByRoom[room], project.Key())
+		}
+	}
+	if len(reposByRoom) == 0 {
+		return nil, nil
+	}
+
+	rooms := slices.Sorted(maps.Keys(reposByRoom))
+	var names []string
+	for _, room := range rooms {
+		if name := strings.TrimPrefix(room, "#"); !channelIDPattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	byName, err := findChannels(token, names)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	for _, room := range rooms {
+		repos := strings.Join(reposByRoom[room], ", ")
+		name := strings.TrimPrefix(room, "#")
+		c, found := byName[name]
+		if channelIDPattern.MatchString(name) {
+			if c, err = channelInfo(token, name); err != nil {
+				warnings = append(warnings, fmt.Sprintf("⚠️ Couldn't look up Slack channel %s (%v), so it may not be notified for: %s", room, err, repos))
+				continue
+			}
+			found = c.ID != "" && !c.IsArchived
+		}
+		if !found {
+			warnings = append(warnings, fmt.Sprintf("⚠️ Slack channel %s not found (or archived, or private without the bot), so it won't be notified for: %s", room, repos))
+			continue
+		}
+		if !c.IsMember {
+			if err := formCall(token, "conversations.join", url.Values{"channel": {c.ID}}, nil); err != nil {
+				warnings = append(warnings, fmt.Sprintf("⚠️ The Slack bot isn't in %s and couldn't join it (%v); invite it or it won't be notified for: %s", room, err, repos))
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// channelInfo looks up the channel with id. A channel that doesn't exist, or
+// is private without the bot, has no ID.
+func channelInfo(token, id string) (channel, error) {
+	var info struct {
+		Channel channel `json:"channel"`
+	}
+	err := formCall(token, "conversations.info", url.Values{"channel": {id}}, &info)
+	if errors.Is(err, apiError("channel_not_found")) {
+		return channel{}, nil
+	}
+	return info.Channel, err
+}
+
+// findChannels returns the unarchived channels the bot can see with one of
+// names, reading pages of them until all are found. Private channels are
+// only included when the token has the groups:read scope.
+func findChannels(token string, names []string) (map[string]channel, error) {
+	found := make(map[string]channel)
+	if len(names) == 0 {
+		return found, nil
+	}
+	types := "public_channel,private_channel"
+	cursor := ""
+	for range maxChannelPages {
+		var page struct {
+			Channels []channel `json:"channels"`
+			Metadata struct {
+				NextCursor string `json:"next_cursor"`
+			} `json:"response_metadata"`
+		}
+		args := url.Values{
+			"types":            {types},
+			"exclude_archived": {"true"},
+			"limit":            {"1000"},
+		}
+		if cursor != "" {
+			args.Set("cursor", cursor)
+		}
+		err := formCall(token, "conversations.list", args, &page)
+		if err != nil && cursor == "" && types != "public_channel" && errors.Is(err, apiError("missing_scope")) {
+			// Tokens without groups:read can still list public channels
+			types = "public_channel"
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range page.Channels {
+			if slices.Contains(names, c.Name) {
+				found[c.Name] = c
+			}
+		}
+		if cursor = page.Metadata.NextCursor; cursor == "" || len(found) == len(names) {
+			break
+		}
+	}
+	return found, nil
+}
+
+// apiError is the error code Slack answered a call with, e.g. missing_scope.
+type apiError string
+
+func (e apiError) Error() string {
+	return "slack API error: " + string(e)
+}
+
+// formCall invokes a Slack Web API method with form-encoded arguments, as
+// read methods like conversations.list require, decoding the response into
+// out when it isn't nil.
+func formCall(token, method string, args url.Values, out any) error {
+	req, err := http.NewRequest(http.MethodPost, slackAPIBase+method, strings.NewReader(args.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := checkClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Slack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	var result slackResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !result.OK {
+		return apiError(result.Error)
+	}
+	if out != nil {
+		return json.Unmarshal(raw, out)
+	}
+	return nil
+}
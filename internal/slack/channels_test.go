@@ -0,0 +1,113 @@
+package slack
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestCheckChannels(t *testing.T) {
+	var joined, listed []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			fmt.Fprint(w, `{"ok":false,"error":"invalid_auth"}`)
+			return
+		}
+		switch r.URL.Path {
+		case "/conversations.list":
+			listed = append(listed, r.FormValue("types")+"@"+r.FormValue("cursor"))
+			switch r.FormValue("cursor") {
+			case "":
+				fmt.Fprint(w, `{"ok":true,"channels":[{"id":"C1","name":"team-payments","is_member":true}],"response_metadata":{"next_cursor":"page2"}}`)
+			case "page2":
+				fmt.Fprint(w, `{"ok":true,"channels":[{"id":"C2","name":"team-ledger","is_member":false},{"id":"C3","name":"team-web","is_member":false}],"response_metadata":{"next_cursor":"page3"}}`)
+			default:
+				fmt.Fprint(w, `{"ok":true,"channels":[],"response_metadata":{"next_cursor":"page4"}}`)
+			}
+		case "/conversations.info":
+			switch r.FormValue("channel") {
+			case "C0PLATFORM":
+				fmt.Fprint(w, `{"ok":true,"channel":{"id":"C0PLATFORM","name":"platform","is_member":false}}`)
+			case "C0ARCHIVED":
+				fmt.Fprint(w, `{"ok":true,"channel":{"id":"C0ARCHIVED","name":"old-team","is_member":true,"is_archived":true}}`)
+			default:
+				fmt.Fprint(w, `{"ok":false,"error":"channel_not_found"}`)
+			}
+		case "/conversations.join":
+			joined = append(joined, r.FormValue("channel"))
+			if r.FormValue("channel") == "C3" {
+				fmt.Fprint(w, `{"ok":false,"error":"missing_scope"}`)
+				return
+			}
+			fmt.Fprint(w, `{"ok":true}`)
+		}
+	}))
+	defer api.Close()
+	previous := slackAPIBase
+	slackAPIBase = api.URL + "/"
+	t.Cleanup(func() { slackAPIBase = previous })
+
+	projects := []config.Project{
+		{Repo: "payments-api", SlackRoom: "#team-payments"},
+		{Repo: "ledger-service", SlackRoom: "#team-ledger"},
+		{Repo: "web", SlackRoom: "#team-web"},
+		{Repo: "legacy-batch", SlackRoom: "#team-gone"},
+		{Repo: "infra", SlackRoom: "C0PLATFORM"},
+		{Repo: "old-batch", SlackRoom: "C0ARCHIVED"},
+		{Repo: "docs"},
+	}
+	warnings, err := CheckChannels(projects, "xoxb-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(joined, " ") != "C2 C3 C0PLATFORM" {
+		t.Errorf("expected the public channels the bot isn't in to be joined, got %v", joined)
+	}
+	if len(listed) != maxChannelPages {
+		t.Errorf("expected every page to be read while #team-gone isn't found, got %d", len(listed))
+	}
+	if len(warnings) != 3 ||
+		!strings.Contains(warnings[0], "#team-gone not found") || !strings.Contains(warnings[0], "legacy-batch") ||
+		!strings.Contains(warnings[1], "isn't in #team-web and couldn't join it (slack API error: missing_scope)") ||
+		!strings.Contains(warnings[2], "C0ARCHIVED not found") || !strings.Contains(warnings[2], "old-batch") {
+		t.Errorf("unexpected warnings %q", warnings)
+	}
+
+	listed = nil
+	if _, err := CheckChannels(projects[:2], "xoxb-test"); err != nil || len(listed) != 2 {
+		t.Errorf("expected listing to stop once every room is found, got %v (%v)", listed, err)
+	}
+
+	if _, err := CheckChannels(projects, "xoxb-revoked"); err == nil || !strings.Contains(err.Error(), "invalid_auth") {
+		t.Errorf("expected the check itself to fail, got %v", err)
+	}
+}
+
+func TestCheckChannelsWithoutGroupsRead(t *testing.T) {
+	var listed []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		listed = append(listed, r.FormValue("types"))
+		// A token from before groups:read was asked for
+		if strings.Contains(r.FormValue("types"), "private_channel") {
+			fmt.Fprint(w, `{"ok":false,"error":"missing_scope","needed":"groups:read","provided":"chat:write,channels:read,channels:join"}`)
+			return
+		}
+		fmt.Fprint(w, `{"ok":true,"channels":[{"id":"C1","name":"team-payments","is_member":true}]}`)
+	}))
+	defer api.Close()
+	previous := slackAPIBase
+	slackAPIBase = api.URL + "/"
+	t.Cleanup(func() { slackAPIBase = previous })
+
+	warnings, err := CheckChannels([]config.Project{{Repo: "payments-api", SlackRoom: "#team-payments"}}, "xoxb-test")
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("expected public channels to be checked without groups:read, got %q (%v)", warnings, err)
+	}
+	if strings.Join(listed, " ") != "public_channel,private_channel public_channel" {
+		t.Errorf("expected to fall back to public channels, got %v", listed)
+	}
+}
@@ -0,0 +1,188 @@
+package slack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+const (
+	keyringService = "copycat"
+	keyringAccount = "slack-bot-token"
+	tokenFileName  = "slack_token"
+)
+
+// ErrNoToken is returned when no Slack token has been stored.
+var ErrNoToken = errors.New("no Slack token stored")
+
+// ResolveToken returns the Slack bot token to use, preferring $SLACK_BOT_TOKEN
+// over the token stored by `copycat slack connect`. Returns "" if neither is set.
+func ResolveToken() string {
+	if token := strings.TrimSpace(os.Getenv("SLACK_BOT_TOKEN")); token != "" {
+		return token
+	}
+	token, err := LoadToken()
+	if err != nil {
+		return ""
+	}
+	return token
+}
+
+// goos picks the OS keyring used, a variable so tests can pick another.
+var goos = runtime.GOOS
+
+// keyringTool returns the OS keyring tool available, or "" when there is none.
+func keyringTool() string {
+	switch {
+	case goos == "darwin" && hasCommand("security"):
+		return "security"
+	case goos == "linux" && hasCommand("secret-tool"):
+		return "secret-tool"
+	}
+	return ""
+}
+
+// StoreToken saves the token in the OS keyring (macOS Keychain or the Secret
+// Service on Linux). When no keyring is available, e.g. secret-tool is
+// installed but no Secret Service runs on a headless machine, it falls back
+// to a 0600 file in the config directory.
+func StoreToken(token string) error {
+	var err error
+	switch keyringTool() {
+	case "security":
+		err = storeInKeychain(token)
+	case "secret-tool":
+		err = storeInSecretService(token)
+	default:
+		return storeInFile(token)
+	}
+	if err != nil {
+		if fileErr := storeInFile(token); fileErr != nil {
+			return fmt.Errorf("%w; %w", err, fileErr)
+		}
+		return nil
+	}
+	// A token from an earlier fallback would otherwise outlive this one
+	if path, pathErr := tokenFilePath(); pathErr == nil {
+		os.Remove(path)
+	}
+	return nil
+}
+
+// storeInKeychain saves token with security's interactive mode, which reads
+// the command from stdin so the token never shows up in the process list.
+func storeInKeychain(token string) error {
+	if strings.ContainsAny(token, "\"\\\n") {
+		return fmt.Errorf("failed to store token in keychain: it contains quotes or line breaks")
+	}
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w \"%s\"\n", keyringService, keyringAccount, token))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to store token in keychain: %v (%s)", err, strings.TrimSpace(string(output)))
+	}
+	// security -i carries on past failed commands, so check it was stored
+	if stored, err := loadFromKeyring("security"); err != nil || stored != token {
+		return fmt.Errorf("failed to store token in keychain")
+	}
+	return nil
+}
+
+func storeInSecretService(token string) error {
+	cmd := exec.Command("secret-tool", "store", "--label", "Copycat Slack token",
+		"service", keyringService, "account", keyringAccount)
+	cmd.Stdin = strings.NewReader(token)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to store token in secret service: %v (%s)", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func storeInFile(token string) error {
+	path, err := tokenFilePath()
+	if err != nil {
+		return err
+	}
+	if err := config.EnsureConfigDir(); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(token), 0o600); err != nil {
+		return fmt.Errorf("failed to write token to %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadToken reads the token saved by StoreToken, from the OS keyring or the
+// file it fell back to.
+func LoadToken() (string, error) {
+	if tool := keyringTool(); tool != "" {
+		if token, err := loadFromKeyring(tool); err == nil && token != "" {
+			return token, nil
+		}
+	}
+	path, err := tokenFilePath()
+	if err != nil {
+		return "", err
+	}
+	output, err := os.ReadFile(path)
+	token := string(bytes.TrimSpace(output))
+	if err != nil || token == "" {
+		return "", ErrNoToken
+	}
+	return token, nil
+}
+
+func loadFromKeyring(tool string) (string, error) {
+	var output []byte
+	var err error
+	switch tool {
+	case "security":
+		output, err = exec.Command("security", "find-generic-password",
+			"-s", keyringService, "-a", keyringAccount, "-w").Output()
+	case "secret-tool":
+		output, err = exec.Command("secret-tool", "lookup",
+			"service", keyringService, "account", keyringAccount).Output()
+	}
+	return string(bytes.TrimSpace(output)), err
+}
+
+// DeleteToken removes the stored token from the OS keyring and the fallback
+// file. Deleting a missing token is not an error.
+func DeleteToken() error {
+	switch keyringTool() {
+	case "security":
+		exec.Command("security", "delete-generic-password",
+			"-s", keyringService, "-a", keyringAccount).Run()
+	case "secret-tool":
+		exec.Command("secret-tool", "clear",
+			"service", keyringService, "account", keyringAccount).Run()
+	}
+
+	path, err := tokenFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete %s: %w", path, err)
+	}
+	return nil
+}
+
+func tokenFilePath() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, tokenFileName), nil
+}
+
+func hasCommand(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
@@ -0,0 +1,95 @@
+package slack
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeKeyringTool puts a script named name first on PATH, recording its
+// arguments and stdin in dir, and makes system the OS whose keyring is used.
+func fakeKeyringTool(t *testing.T, system, name, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\nFAKE_DIR="+dir+"\necho \"$@\" >> \"$FAKE_DIR/args\"\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	previous := goos
+	goos = system
+	t.Cleanup(func() { goos = previous })
+	return dir
+}
+
+func TestStoreTokenInKeychain(t *testing.T) {
+	dir := fakeKeyringTool(t, "darwin", "security", `
+case "$1" in
+-i) cat > "$FAKE_DIR/stdin" ;;
+find-generic-password) sed -n 's/.*-w "\(.*\)"$/\1/p' "$FAKE_DIR/stdin" 2>/dev/null ;;
+delete-generic-password) rm -f "$FAKE_DIR/stdin" ;;
+esac
+`)
+
+	if err := StoreToken("xoxb-secret"); err != nil {
+		t.Fatal(err)
+	}
+	args, _ := os.ReadFile(filepath.Join(dir, "args"))
+	if strings.Contains(string(args), "xoxb-secret") {
+		t.Errorf("expected the token to stay out of the command line, got %q", args)
+	}
+	if token, err := LoadToken(); err != nil || token != "xoxb-secret" {
+		t.Errorf("LoadToken() = %q, %v", token, err)
+	}
+	if path, _ := tokenFilePath(); fileExists(path) {
+		t.Error("expected no token file while the keychain works")
+	}
+
+	if err := DeleteToken(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadToken(); err != ErrNoToken {
+		t.Errorf("expected no token after deleting it, got %v", err)
+	}
+}
+
+func TestStoreTokenWithoutSecretService(t *testing.T) {
+	// secret-tool is installed, but no Secret Service runs, as on CI
+	fakeKeyringTool(t, "linux", "secret-tool", `
+echo "Cannot autolaunch D-Bus without X11 \$DISPLAY" >&2
+exit 1
+`)
+
+	if err := StoreToken("xoxb-secret"); err != nil {
+		t.Fatalf("expected to fall back to the token file, got %v", err)
+	}
+	path, _ := tokenFilePath()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected the token file to be 0600, got %v", info.Mode().Perm())
+	}
+	if token, err := LoadToken(); err != nil || token != "xoxb-secret" {
+		t.Errorf("LoadToken() = %q, %v", token, err)
+	}
+
+	if err := DeleteToken(); err != nil {
+		t.Fatal(err)
+	}
+	if fileExists(path) {
+		t.Error("expected the token file to be deleted")
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
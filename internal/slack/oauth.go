@@ -0,0 +1,206 @@
+package slack
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+const (
+	slackAuthorizeURL = "https://slack.com/oauth/v2/authorize"
+
+	defaultRedirectPort = 8976
+	oauthTimeout        = 5 * time.Minute
+)
+
+// slackOAuthAccessURL is where codes are exchanged for tokens, a variable so
+// tests can point it at a fake.
+var slackOAuthAccessURL = "https://slack.com/api/oauth.v2.access"
+
+// botScopes are the bot token scopes copycat needs to post notifications.
+var botScopes = []string{"chat:write", "chat:write.public", "channels:read", "channels:join", "groups:read"}
+
+type oauthAccessResponse struct {
+	OK          bool   `json:"ok"`
+	Error       string `json:"error,omitempty"`
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	Team        struct {
+		Name string `json:"name"`
+	} `json:"team"`
+}
+
+// OAuthResult holds the outcome of a successful OAuth flow.
+type OAuthResult struct {
+	Token    string
+	TeamName string
+}
+
+// Connect runs the Slack OAuth v2 flow: it starts a local callback server,
+// opens the authorize URL in the browser, and exchanges the returned code for
+// a bot token. The redirect URL (http://localhost:<port>/callback) must be
+// registered in the Slack app's OAuth settings.
+func Connect(ctx context.Context, cfg config.SlackConfig, onStatus func(string)) (*OAuthResult, error) {
+	if cfg.ClientID == "" {
+		return nil, fmt.Errorf("slack.client_id is not set in config.yaml")
+	}
+	clientSecret := cfg.ClientSecret
+	if clientSecret == "" {
+		clientSecret = os.Getenv("SLACK_CLIENT_SECRET")
+	}
+	if clientSecret == "" {
+		return nil, fmt.Errorf("slack.client_secret is not set in config.yaml and $SLACK_CLIENT_SECRET is empty")
+	}
+
+	port := cfg.RedirectPort
+	if port == 0 {
+		port = defaultRedirectPort
+	}
+	redirectURI := fmt.Sprintf("http://localhost:%d/callback", port)
+
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on port %d: %w", port, err)
+	}
+	// Browsers may resolve localhost to ::1 first, so the callback is served
+	// there too wherever the machine has IPv6
+	listeners := []net.Listener{listener}
+	if ipv6, err := net.Listen("tcp", fmt.Sprintf("[::1]:%d", port)); err == nil {
+		listeners = append(listeners, ipv6)
+	}
+
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	// Only the first callback with the right state counts; others, e.g. a
+	// page reload or a request forged by another site, are answered but
+	// never block or end the flow
+	resultCh := make(chan callbackResult, 1)
+	deliver := func(result callbackResult) {
+		select {
+		case resultCh <- result:
+		default:
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case q.Get("state") != state:
+			http.Error(w, "invalid state", http.StatusBadRequest)
+		case q.Get("error") != "":
+			fmt.Fprintln(w, "Slack authorization was denied. You can close this window.")
+			deliver(callbackResult{err: fmt.Errorf("authorization denied: %s", q.Get("error"))})
+		default:
+			fmt.Fprintln(w, "Copycat is connected to Slack. You can close this window.")
+			deliver(callbackResult{code: q.Get("code")})
+		}
+	})
+	server := &http.Server{Handler: mux}
+	for _, l := range listeners {
+		go server.Serve(l)
+	}
+	defer server.Shutdown(context.Background())
+
+	authURL := buildAuthorizeURL(cfg.ClientID, redirectURI, state)
+	onStatus("Opening Slack in your browser. If it does not open, visit:")
+	onStatus(authURL)
+	openBrowser(authURL)
+
+	var result callbackResult
+	select {
+	case result = <-resultCh:
+	case <-time.After(oauthTimeout):
+		return nil, fmt.Errorf("timed out waiting for Slack authorization")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	return exchangeCode(ctx, cfg.ClientID, clientSecret, result.code, redirectURI)
+}
+
+func buildAuthorizeURL(clientID, redirectURI, state string) string {
+	params := url.Values{}
+	params.Set("client_id", clientID)
+	params.Set("scope", strings.Join(botScopes, ","))
+	params.Set("redirect_uri", redirectURI)
+	params.Set("state", state)
+	return slackAuthorizeURL + "?" + params.Encode()
+}
+
+func exchangeCode(ctx context.Context, clientID, clientSecret, code, redirectURI string) (*OAuthResult, error) {
+	form := url.Values{}
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", slackOAuthAccessURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, clientSecret)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange OAuth code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var accessResp oauthAccessResponse
+	if err := json.NewDecoder(resp.Body).Decode(&accessResp); err != nil {
+		return nil, fmt.Errorf("failed to decode OAuth response: %w", err)
+	}
+	if !accessResp.OK {
+		return nil, fmt.Errorf("slack API error: %s", accessResp.Error)
+	}
+	if accessResp.TokenType != "bot" || accessResp.AccessToken == "" {
+		return nil, fmt.Errorf("slack did not return a bot token")
+	}
+
+	return &OAuthResult{Token: accessResp.AccessToken, TeamName: accessResp.Team.Name}, nil
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate OAuth state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// openBrowser opens target in the default browser, a variable so tests can
+// follow the flow themselves.
+var openBrowser = func(target string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", target)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", target)
+	default:
+		cmd = exec.Command("xdg-open", target)
+	}
+	_ = cmd.Start()
+}
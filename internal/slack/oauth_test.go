@@ -0,0 +1,122 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestConnect(t *testing.T) {
+	access := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, _ := r.BasicAuth(); id != "client-id" || secret != "client-secret" || r.FormValue("code") != "good-code" {
+			fmt.Fprint(w, `{"ok":false,"error":"invalid_code"}`)
+			return
+		}
+		fmt.Fprint(w, `{"ok":true,"access_token":"xoxb-new","token_type":"bot","team":{"name":"Acme"}}`)
+	}))
+	defer access.Close()
+	previousURL, previousOpen := slackOAuthAccessURL, openBrowser
+	t.Cleanup(func() { slackOAuthAccessURL, openBrowser = previousURL, previousOpen })
+	slackOAuthAccessURL = access.URL
+
+	// Follow the flow as a browser would, with a forged callback first and a
+	// reload after the real one
+	statuses := make(chan []int, 1)
+	openBrowser = func(target string) {
+		authURL, err := url.Parse(target)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		q := authURL.Query()
+		go func() {
+			var got []int
+			for _, state := range []string{"forged", q.Get("state"), q.Get("state")} {
+				resp, err := http.Get(q.Get("redirect_uri") + "?" + url.Values{"state": {state}, "code": {"good-code"}}.Encode())
+				if err != nil {
+					t.Error(err)
+					break
+				}
+				resp.Body.Close()
+				got = append(got, resp.StatusCode)
+			}
+			statuses <- got
+		}()
+	}
+
+	port := freePort(t)
+	result, err := Connect(context.Background(), config.SlackConfig{ClientID: "client-id", ClientSecret: "client-secret", RedirectPort: port}, func(string) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Token != "xoxb-new" || result.TeamName != "Acme" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if got := fmt.Sprint(<-statuses); got != "[400 200 200]" {
+		t.Errorf("expected a forged callback to be refused without ending the flow and a reload not to block, got %s", got)
+	}
+}
+
+func TestConnectDenied(t *testing.T) {
+	previous := openBrowser
+	t.Cleanup(func() { openBrowser = previous })
+	openBrowser = func(target string) {
+		authURL, _ := url.Parse(target)
+		q := authURL.Query()
+		go func() {
+			resp, err := http.Get(q.Get("redirect_uri") + "?" + url.Values{"state": {q.Get("state")}, "error": {"access_denied"}}.Encode())
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+
+	_, err := Connect(context.Background(), config.SlackConfig{ClientID: "client-id", ClientSecret: "client-secret", RedirectPort: freePort(t)}, func(string) {})
+	if err == nil || err.Error() != "authorization denied: access_denied" {
+		t.Errorf("expected the denial to end the flow, got %v", err)
+	}
+}
+
+func TestConnectServesLocalhostOverIPv6(t *testing.T) {
+	probe, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skip("no IPv6 loopback")
+	}
+	probe.Close()
+
+	previous := openBrowser
+	t.Cleanup(func() { openBrowser = previous })
+	port := freePort(t)
+	openBrowser = func(target string) {
+		authURL, _ := url.Parse(target)
+		q := authURL.Query()
+		go func() {
+			callback := fmt.Sprintf("http://[::1]:%d/callback?", port) + url.Values{"state": {q.Get("state")}, "error": {"access_denied"}}.Encode()
+			resp, err := http.Get(callback)
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+
+	_, err = Connect(context.Background(), config.SlackConfig{ClientID: "client-id", ClientSecret: "client-secret", RedirectPort: port}, func(string) {})
+	if err == nil || err.Error() != "authorization denied: access_denied" {
+		t.Errorf("expected a callback to localhost over IPv6 to reach the flow, got %v", err)
+	}
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
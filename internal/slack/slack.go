@@ -5,35 +5,92 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/util"
 )
 
-const slackAPIURL = "https://slack.com/api/chat.postMessage"
-
 type slackMessage struct {
-	Channel string `json:"channel"`
-	Text    string `json:"text"`
+	Channel  string `json:"channel"`
+	Text     string `json:"text"`
+	PostAt   int64  `json:"post_at,omitempty"`   // scheduled messages only
+	ThreadTS string `json:"thread_ts,omitempty"` // replies only
 }
 
 type slackResponse struct {
 	OK    bool   `json:"ok"`
 	Error string `json:"error,omitempty"`
+	TS    string `json:"ts,omitempty"` // of the message posted
 }
 
-// repoWithURL holds a repository name and its PR URL
+// repoWithURL holds a repository name, its PR (or issue) URL and the PR's CI status
 type repoWithURL struct {
 	Repo  string
 	PRURL string
+	CI    string
 }
 
+// maxPromptExcerpt is how much of the run's prompt message templates get.
+const maxPromptExcerpt = 200
+
 // SendNotifications sends notifications for successful projects, grouped by Slack room.
+// Rooms in their quiet hours get the notification when the hours end.
+// ciStatus, when the run waited for checks, gives each PR's CI status.
+// The message is written by the slack messages prs template when set.
 // The onStatus callback receives progress lines instead of printing to stdout.
-func SendNotifications(successfulProjects []config.Project, prTitle string, prURLs map[string]string, token string, onStatus func(string)) {
+func SendNotifications(successfulProjects []config.Project, prTitle, prompt string, prURLs, ciStatus map[string]string, token string, cfg config.SlackConfig, onStatus func(string)) {
+	notifyRunChannel(successfulProjects, prTitle, "PRs", prURLs, ciStatus, token, cfg, onStatus)
+	notifyRooms(successfulProjects, prURLs, ciStatus, token, cfg, onStatus, func(repos []repoWithURL) string {
+		return templatedMessage("prs", cfg.Messages.PRs, prTitle, prompt, repos, onStatus, formatMessage)
+	})
+}
+
+// SendIssueNotifications tells each project's Slack room about the issues
+// opened in its repos, like SendNotifications does for PRs.
+func SendIssueNotifications(projects []config.Project, issueTitle, prompt string, issueURLs map[string]string, token string, cfg config.SlackConfig, onStatus func(string)) {
+	notifyRunChannel(projects, issueTitle, "issues", issueURLs, nil, token, cfg, onStatus)
+	notifyRooms(projects, issueURLs, nil, token, cfg, onStatus, func(repos []repoWithURL) string {
+		return templatedMessage("issues", cfg.Messages.Issues, issueTitle, prompt, repos, onStatus, formatIssueMessage)
+	})
+}
+
+// templatedMessage writes the message about repos with the template text,
+// or with builtin when there is none or it fails.
+func templatedMessage(name, text, title, prompt string, repos []repoWithURL, onStatus func(string), builtin func(string, []repoWithURL) string) string {
+	if text == "" {
+		return builtin(title, repos)
+	}
+	data := config.SlackMessageData{Title: title, Prompt: promptExcerpt(prompt), Author: os.Getenv("USER")}
+	for _, r := range repos {
+		data.Repos = append(data.Repos, config.SlackMessageRepo{Repo: r.Repo, URL: r.PRURL, CI: r.CI})
+	}
+	message, err := config.RenderSlackMessage(name, text, data)
+	if err != nil || strings.TrimSpace(message) == "" {
+		onStatus(fmt.Sprintf("⚠️  Slack messages %s template didn't write a message, sending the default one: %v", name, err))
+		return builtin(title, repos)
+	}
+	return message
+}
+
+// promptExcerpt returns the start of prompt on one line.
+func promptExcerpt(prompt string) string {
+	excerpt := strings.Join(strings.Fields(prompt), " ")
+	if runes := []rune(excerpt); len(runes) > maxPromptExcerpt {
+		excerpt = string(runes[:maxPromptExcerpt]) + "…"
+	}
+	return excerpt
+}
+
+// notifyRooms posts a message listing the projects of each Slack room, as
+// written by format.
+func notifyRooms(successfulProjects []config.Project, urls, ciStatus map[string]string, token string, cfg config.SlackConfig, onStatus func(string), format func([]repoWithURL) string) {
 	if len(successfulProjects) == 0 {
 		return
 	}
+	onStatus = redactToken(onStatus, token)
 
 	// Group projects by Slack room
 	projectsByRoom := make(map[string][]repoWithURL)
@@ -43,38 +100,92 @@ func SendNotifications(successfulProjects []config.Project, prTitle string, prUR
 			continue // Skip projects without a Slack room
 		}
 		projectsByRoom[slackRoom] = append(projectsByRoom[slackRoom], repoWithURL{
-			Repo:  project.Repo,
-			PRURL: prURLs[project.Repo],
+			Repo:  project.Key(),
+			PRURL: urls[project.Key()],
+			CI:    ciStatus[project.Key()],
 		})
 	}
 
 	if len(projectsByRoom) == 0 {
-		onStatus("⚠️  No Slack rooms configured for successful projects, skipping notifications")
+		if cfg.RunChannel == "" {
+			onStatus("⚠️  No Slack rooms configured for successful projects, skipping notifications")
+		}
 		return
 	}
 
 	onStatus("Sending Slack notifications...")
 
 	for channel, repos := range projectsByRoom {
-		message := formatMessage(prTitle, repos)
-		err := sendMessage(token, channel, message)
+		message := format(repos)
+		postAt, err := Deliver(token, channel, message, cfg)
 		repoNames := make([]string, len(repos))
 		for i, r := range repos {
 			repoNames[i] = r.Repo
 		}
 		if err != nil {
 			onStatus(fmt.Sprintf("⚠️  Failed to send notification to %s for: %s: %v", channel, strings.Join(repoNames, ", "), err))
+		} else if !postAt.IsZero() {
+			onStatus(fmt.Sprintf("✓ Notification scheduled for %s %s (quiet hours) for: %s", channel, formatPostAt(postAt), strings.Join(repoNames, ", ")))
 		} else {
 			onStatus(fmt.Sprintf("✓ Notification sent to %s for: %s", channel, strings.Join(repoNames, ", ")))
 		}
 	}
 }
 
+// notifyRunChannel starts a thread about the run in the configured run
+// channel, with a reply per project, so a campaign channel gets one thread
+// per run however many repos it changed. what names the run's results, e.g.
+// "PRs". During the channel's quiet hours, when replies can't be threaded
+// under a message not yet posted, one message listing them all is scheduled
+// instead.
+func notifyRunChannel(projects []config.Project, title, what string, urls, ciStatus map[string]string, token string, cfg config.SlackConfig, onStatus func(string)) {
+	channel := strings.TrimSpace(cfg.RunChannel)
+	if channel == "" || len(projects) == 0 {
+		return
+	}
+	onStatus = redactToken(onStatus, token)
+
+	parent := fmt.Sprintf("🐱 *%s*\n\nCopycat opened %d %s in this run, one per reply 🧵", title, len(projects), what)
+	replies := make([]string, len(projects))
+	for i, project := range projects {
+		replies[i] = formatRepoLine(repoWithURL{Repo: project.Key(), PRURL: urls[project.Key()], CI: ciStatus[project.Key()]})
+	}
+
+	if postAt := cfg.QuietUntil(channel, time.Now()); !postAt.IsZero() {
+		_, err := callAPI(slackAPIBase+"chat.scheduleMessage", token, slackMessage{Channel: channel, Text: parent + "\n\n" + strings.Join(replies, "\n"), PostAt: postAt.Unix()})
+		if err != nil {
+			onStatus(fmt.Sprintf("⚠️  Failed to schedule the run's summary in %s: %v", channel, err))
+		} else {
+			onStatus(fmt.Sprintf("✓ Run summary scheduled for %s %s (quiet hours), listing %d %s", channel, formatPostAt(postAt), len(replies), what))
+		}
+		return
+	}
+
+	ts, err := callAPI(slackAPIBase+"chat.postMessage", token, slackMessage{Channel: channel, Text: parent})
+	if err != nil {
+		onStatus(fmt.Sprintf("⚠️  Failed to start the run's thread in %s: %v", channel, err))
+		return
+	}
+	var failed []string
+	for i, reply := range replies {
+		if _, err := callAPI(slackAPIBase+"chat.postMessage", token, slackMessage{Channel: channel, Text: reply, ThreadTS: ts}); err != nil {
+			failed = append(failed, projects[i].Key())
+		}
+	}
+	if len(failed) > 0 {
+		onStatus(fmt.Sprintf("⚠️  Run thread started in %s, but replies failed for: %s", channel, strings.Join(failed, ", ")))
+	} else {
+		onStatus(fmt.Sprintf("✓ Run thread started in %s with %d replies", channel, len(replies)))
+	}
+}
+
 // SendAssessmentFindings sends per-project assessment findings to Slack, grouped by channel.
-func SendAssessmentFindings(projects []config.Project, question string, findings map[string]string, token string, onStatus func(string)) {
+// Like notifications, they wait for the end of a channel's quiet hours.
+func SendAssessmentFindings(projects []config.Project, question string, findings map[string]string, token string, cfg config.SlackConfig, onStatus func(string)) {
 	if len(projects) == 0 {
 		return
 	}
+	onStatus = redactToken(onStatus, token)
 
 	// Group projects by Slack room
 	projectsByRoom := make(map[string][]string)
@@ -83,7 +194,7 @@ func SendAssessmentFindings(projects []config.Project, question string, findings
 		if slackRoom == "" {
 			continue
 		}
-		projectsByRoom[slackRoom] = append(projectsByRoom[slackRoom], project.Repo)
+		projectsByRoom[slackRoom] = append(projectsByRoom[slackRoom], project.Key())
 	}
 
 	if len(projectsByRoom) == 0 {
@@ -106,10 +217,12 @@ func SendAssessmentFindings(projects []config.Project, question string, findings
 		}
 
 		message := formatAssessmentMessage(question, repoFindings)
-		err := sendMessage(token, channel, message)
+		postAt, err := Deliver(token, channel, message, cfg)
 		repoNames := strings.Join(repos, ", ")
 		if err != nil {
 			onStatus(fmt.Sprintf("⚠️  Failed to send findings to %s for: %s: %v", channel, repoNames, err))
+		} else if !postAt.IsZero() {
+			onStatus(fmt.Sprintf("✓ Findings scheduled for %s %s (quiet hours) for: %s", channel, formatPostAt(postAt), repoNames))
 		} else {
 			onStatus(fmt.Sprintf("✓ Findings sent to %s for: %s", channel, repoNames))
 		}
@@ -130,6 +243,29 @@ func formatMessage(prTitle string, repos []repoWithURL) string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("🐱 *%s*\n\n", prTitle))
 	sb.WriteString("Copycat dropped some PRs for you - don't leave them hanging! 👀\n\n")
+	for _, r := range repos {
+		sb.WriteString("• " + formatRepoLine(r) + "\n")
+	}
+	sb.WriteString("\nReview, approve, merge - you know the drill 🚀")
+	return sb.String()
+}
+
+// formatRepoLine links a repo to its PR or issue, with the PR's CI status.
+func formatRepoLine(r repoWithURL) string {
+	line := r.Repo
+	if r.PRURL != "" {
+		line = fmt.Sprintf("<%s|%s>", r.PRURL, r.Repo)
+	}
+	if r.CI != "" {
+		line += " — " + r.CI
+	}
+	return line
+}
+
+func formatIssueMessage(issueTitle string, repos []repoWithURL) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🐱 *%s*\n\n", issueTitle))
+	sb.WriteString("Copycat opened some issues for you 📋\n\n")
 	for _, r := range repos {
 		if r.PRURL != "" {
 			sb.WriteString(fmt.Sprintf("• <%s|%s>\n", r.PRURL, r.Repo))
@@ -137,24 +273,56 @@ func formatMessage(prTitle string, repos []repoWithURL) string {
 			sb.WriteString(fmt.Sprintf("• %s\n", r.Repo))
 		}
 	}
-	sb.WriteString("\nReview, approve, merge - you know the drill 🚀")
+	sb.WriteString("\nTake a look and pick them up 🚀")
 	return sb.String()
 }
 
+// Deliver posts text to channel, or during the channel's quiet hours has
+// Slack schedule it for when they end. It returns the scheduled time, or the
+// zero time if the message was posted straight away.
+func Deliver(token, channel, text string, cfg config.SlackConfig) (time.Time, error) {
+	postAt := cfg.QuietUntil(channel, time.Now())
+	if postAt.IsZero() {
+		return postAt, sendMessage(token, channel, text)
+	}
+	_, err := callAPI(slackAPIBase+"chat.scheduleMessage", token, slackMessage{Channel: channel, Text: text, PostAt: postAt.Unix()})
+	return postAt, err
+}
+
+// formatPostAt shows a scheduled time in local time, with the day if it is
+// not today.
+func formatPostAt(t time.Time) string {
+	t = t.Local()
+	if now := time.Now(); t.YearDay() == now.YearDay() && t.Year() == now.Year() {
+		return "at " + t.Format("15:04")
+	}
+	return "on " + t.Format("Mon 15:04")
+}
+
 func sendMessage(token, channel, text string) error {
-	msg := slackMessage{
-		Channel: channel,
-		Text:    text,
+	_, err := callAPI(slackAPIBase+"chat.postMessage", token, slackMessage{Channel: channel, Text: text})
+	return err
+}
+
+// redactToken masks the token in status lines, which end up on screen and in
+// the run's results, in case an error ever quotes it.
+func redactToken(onStatus func(string), token string) func(string) {
+	return func(line string) {
+		onStatus(util.RedactSecret(line, token))
 	}
+}
 
+// callAPI posts msg to the Slack API method at url and returns the posted
+// message's timestamp, which threads replies under it.
+func callAPI(url, token string, msg slackMessage) (string, error) {
 	body, err := json.Marshal(msg)
 	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
+		return "", fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", slackAPIURL, bytes.NewReader(body))
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -162,18 +330,18 @@ func sendMessage(token, channel, text string) error {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+		return "", fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	var slackResp slackResponse
 	if err := json.NewDecoder(resp.Body).Decode(&slackResp); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+		return "", fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	if !slackResp.OK {
-		return fmt.Errorf("slack API error: %s", slackResp.Error)
+		return "", fmt.Errorf("slack API error: %s", slackResp.Error)
 	}
 
-	return nil
+	return slackResp.TS, nil
 }
@@ -0,0 +1,77 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestTemplatedMessage(t *testing.T) {
+	t.Setenv("USER", "jane")
+	repos := []repoWithURL{{Repo: "payments-api", PRURL: "https://github.com/acme/payments-api/pull/7", CI: "CI green ✅"}}
+	var status []string
+	onStatus := func(line string) { status = append(status, line) }
+
+	prompt := "Upgrade the Go toolchain\n\nto 1.23 " + strings.Repeat("and fix what breaks ", 20)
+	text := "{{.Author}}: {{.Prompt}}\n{{range .Repos}}{{.Repo}} {{.URL}} {{.CI}}{{end}}"
+	message := templatedMessage("prs", text, "Bump Go", prompt, repos, onStatus, formatMessage)
+	if !strings.HasPrefix(message, "jane: Upgrade the Go toolchain to 1.23 and fix") || !strings.Contains(message, "…\n") {
+		t.Errorf("expected the author and the start of the prompt on one line, got %q", message)
+	}
+	if !strings.HasSuffix(message, "payments-api https://github.com/acme/payments-api/pull/7 CI green ✅") {
+		t.Errorf("expected the repo's PR, got %q", message)
+	}
+
+	if got := templatedMessage("prs", "", "Bump Go", prompt, repos, onStatus, formatMessage); got != formatMessage("Bump Go", repos) {
+		t.Errorf("expected the built-in message without a template, got %q", got)
+	}
+	if got := templatedMessage("prs", "{{/* nothing */}}", "Bump Go", prompt, repos, onStatus, formatMessage); got != formatMessage("Bump Go", repos) || len(status) != 1 {
+		t.Errorf("expected an empty message to fall back to the built-in one with a warning, got %q and %v", got, status)
+	}
+}
+
+func TestSendNotificationsThreadsRunChannel(t *testing.T) {
+	var posted []slackMessage
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slackMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		posted = append(posted, msg)
+		_, _ = fmt.Fprintf(w, `{"ok":true,"ts":"1700000000.%06d"}`, len(posted))
+	}))
+	defer api.Close()
+	previous := slackAPIBase
+	slackAPIBase = api.URL + "/"
+	t.Cleanup(func() { slackAPIBase = previous })
+
+	projects := []config.Project{{Repo: "payments-api", SlackRoom: "#team-payments"}, {Repo: "ledger-service"}}
+	prURLs := map[string]string{"payments-api": "https://github.com/acme/payments-api/pull/7", "ledger-service": "https://github.com/acme/ledger-service/pull/3"}
+	var status []string
+	SendNotifications(projects, "Bump Go", "", prURLs, nil, "xoxb-test", config.SlackConfig{RunChannel: "#go-upgrade"}, func(line string) {
+		status = append(status, line)
+	})
+
+	if len(posted) != 4 {
+		t.Fatalf("expected a parent, two replies and the team's message, got %+v", posted)
+	}
+	parent := posted[0]
+	if parent.Channel != "#go-upgrade" || parent.ThreadTS != "" || !strings.Contains(parent.Text, "2 PRs") {
+		t.Errorf("unexpected parent message %+v", parent)
+	}
+	for i, repo := range []string{"payments-api", "ledger-service"} {
+		reply := posted[i+1]
+		if reply.Channel != "#go-upgrade" || reply.ThreadTS != "1700000000.000001" || !strings.Contains(reply.Text, "|"+repo+">") {
+			t.Errorf("expected %s's PR threaded under the parent, got %+v", repo, reply)
+		}
+	}
+	if posted[3].Channel != "#team-payments" {
+		t.Errorf("expected the team's room to be notified too, got %+v", posted[3])
+	}
+	if !strings.Contains(strings.Join(status, "\n"), "✓ Run thread started in #go-upgrade with 2 replies") {
+		t.Errorf("unexpected status %v", status)
+	}
+}
@@ -0,0 +1,40 @@
+package throttle
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Sample is how busy the machine is. Zero fields are unknown and never
+// count as pressure, so platforms without /proc only adapt to errors.
+type Sample struct {
+	Load            float64 // 1-minute load average
+	CPUs            int
+	AvailableMemory uint64 // bytes
+}
+
+// ReadSample reads the load average and available memory from /proc.
+func ReadSample() Sample {
+	sample := Sample{CPUs: runtime.NumCPU()}
+	if data, err := os.ReadFile("/proc/loadavg"); err == nil {
+		if fields := strings.Fields(string(data)); len(fields) > 0 {
+			sample.Load, _ = strconv.ParseFloat(fields[0], 64)
+		}
+	}
+	if f, err := os.Open("/proc/meminfo"); err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) >= 2 && fields[0] == "MemAvailable:" {
+				kb, _ := strconv.ParseUint(fields[1], 10, 64)
+				sample.AvailableMemory = kb << 10
+				break
+			}
+		}
+	}
+	return sample
+}
@@ -0,0 +1,185 @@
+// Package throttle adapts how many repos a run works on at once: it sheds
+// workers while the machine is short of CPU or memory or while jobs and
+// GitHub requests keep failing, and adds them back once things recover.
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// cooldown is how long the limit stays put after it is lowered, so the
+// workers still running can finish and the load average can catch up.
+const cooldown = 30 * time.Second
+
+// rampUp is the least time between two raises of the limit.
+const rampUp = 10 * time.Second
+
+// minOutcomes is how many recent outcomes it takes to judge the error rate.
+const minOutcomes = 4
+
+// Limiter hands out up to max slots, fewer while under pressure. A nil
+// Limiter never limits.
+type Limiter struct {
+	cfg      config.AdaptiveParallelism
+	max      int
+	probe    func() Sample
+	failures func() int64 // running count of transient failures
+	now      func() time.Time
+	onChange func(limit int, reason string)
+
+	mu           sync.Mutex
+	limit        int
+	active       int
+	outcomes     []bool // recent outcomes, true for a failure
+	seenFailures int64
+	lowered      time.Time
+	raised       time.Time
+	wake         chan struct{} // closed when a slot may have come free
+}
+
+// New returns a Limiter for up to max workers, starting at max. failures
+// reports the running count of transient failures (such as
+// git.TransientFailures) and onChange, if set, is told of every new limit.
+func New(cfg config.AdaptiveParallelism, max int, failures func() int64, onChange func(limit int, reason string)) *Limiter {
+	if max < 1 {
+		max = 1
+	}
+	l := &Limiter{
+		cfg:      cfg,
+		max:      max,
+		probe:    ReadSample,
+		failures: failures,
+		now:      time.Now,
+		onChange: onChange,
+		limit:    max,
+		wake:     make(chan struct{}),
+	}
+	if failures != nil {
+		l.seenFailures = failures()
+	}
+	return l
+}
+
+// Acquire blocks until a slot is free or ctx is cancelled. status, if set,
+// is told when the job has to wait.
+func (l *Limiter) Acquire(ctx context.Context, status func(string)) error {
+	if l == nil {
+		return nil
+	}
+	for waited := false; ; waited = true {
+		l.mu.Lock()
+		l.adjust()
+		if l.active < l.limit {
+			l.active++
+			l.mu.Unlock()
+			return nil
+		}
+		limit, wake := l.limit, l.wake
+		l.mu.Unlock()
+
+		if !waited && status != nil {
+			status(fmt.Sprintf("Waiting for a worker (parallelism lowered to %d of %d)...", limit, l.max))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wake:
+		}
+	}
+}
+
+// Release frees a slot taken by Acquire, recording whether its job failed.
+func (l *Limiter) Release(failed bool) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.record(failed)
+	l.adjust()
+	close(l.wake)
+	l.wake = make(chan struct{})
+}
+
+// Limit is how many slots may be in use at the moment.
+func (l *Limiter) Limit() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// record adds an outcome, keeping only the last few per worker.
+func (l *Limiter) record(failed bool) {
+	l.outcomes = append(l.outcomes, failed)
+	if window := max(2*l.max, minOutcomes); len(l.outcomes) > window {
+		l.outcomes = l.outcomes[len(l.outcomes)-window:]
+	}
+}
+
+// adjust halves the limit under pressure and raises it by one when healthy,
+// no more often than rampUp. It must be called with l.mu held.
+func (l *Limiter) adjust() {
+	if l.failures != nil {
+		seen := l.failures()
+		for ; l.seenFailures < seen; l.seenFailures++ {
+			l.record(true)
+		}
+	}
+	if !l.lowered.IsZero() && l.now().Sub(l.lowered) < cooldown {
+		return
+	}
+
+	reason := l.pressure()
+	switch {
+	case reason != "" && l.limit > 1:
+		l.limit = max(l.limit/2, 1)
+		l.lowered = l.now()
+		// judge the new limit on its own outcomes
+		l.outcomes = nil
+		l.notify(reason)
+	case reason == "" && l.limit < l.max && l.now().Sub(l.raised) >= rampUp:
+		l.limit++
+		l.raised = l.now()
+		l.notify("recovered")
+	}
+}
+
+// pressure says why the run should slow down, or "" when it needn't.
+func (l *Limiter) pressure() string {
+	sample := l.probe()
+	if sample.CPUs > 0 && sample.Load > 0 {
+		if perCPU := sample.Load / float64(sample.CPUs); perCPU > l.cfg.LoadLimit() {
+			return fmt.Sprintf("load %.1f per CPU", perCPU)
+		}
+	}
+	if sample.AvailableMemory > 0 && sample.AvailableMemory < l.cfg.MemoryLimit() {
+		return fmt.Sprintf("%d MB of memory free", sample.AvailableMemory>>20)
+	}
+	if len(l.outcomes) >= minOutcomes {
+		failed := 0
+		for _, f := range l.outcomes {
+			if f {
+				failed++
+			}
+		}
+		if rate := float64(failed) / float64(len(l.outcomes)); rate > l.cfg.ErrorRateLimit() {
+			return fmt.Sprintf("%.0f%% of recent jobs and requests failing", rate*100)
+		}
+	}
+	return ""
+}
+
+func (l *Limiter) notify(reason string) {
+	if l.onChange != nil {
+		l.onChange(l.limit, reason)
+	}
+}
@@ -0,0 +1,142 @@
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func healthy() Sample {
+	return Sample{Load: 1, CPUs: 4, AvailableMemory: 8 << 30}
+}
+
+func newTestLimiter(max int, clock *fakeClock) *Limiter {
+	l := New(config.AdaptiveParallelism{Enabled: true}, max, nil, nil)
+	l.probe = healthy
+	l.now = clock.now
+	return l
+}
+
+func TestLimiterShedsWorkersUnderLoad(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := newTestLimiter(8, clock)
+	l.probe = func() Sample { return Sample{Load: 12, CPUs: 4, AvailableMemory: 8 << 30} }
+
+	var changes []int
+	l.onChange = func(limit int, reason string) { changes = append(changes, limit) }
+
+	if err := l.Acquire(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Limit(); got != 4 {
+		t.Fatalf("limit under load = %d, want 4", got)
+	}
+
+	// still loaded, but within the cooldown
+	l.Release(false)
+	if got := l.Limit(); got != 4 {
+		t.Fatalf("limit during cooldown = %d, want 4", got)
+	}
+
+	clock.advance(cooldown)
+	l.probe = func() Sample { return Sample{Load: 1, CPUs: 4, AvailableMemory: 100 << 20} }
+	l.Acquire(context.Background(), nil)
+	if got := l.Limit(); got != 2 {
+		t.Fatalf("limit when short of memory = %d, want 2", got)
+	}
+	l.Release(false)
+
+	// recovers one worker at a time
+	clock.advance(cooldown)
+	l.probe = healthy
+	l.Acquire(context.Background(), nil)
+	l.Release(false)
+	if got := l.Limit(); got != 3 {
+		t.Fatalf("limit after recovering = %d, want 3", got)
+	}
+	clock.advance(rampUp)
+	l.Acquire(context.Background(), nil)
+	l.Release(false)
+	if got := l.Limit(); got != 4 {
+		t.Fatalf("limit after recovering again = %d, want 4", got)
+	}
+
+	want := []int{4, 2, 3, 4}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("changes = %v, want %v", changes, want)
+		}
+	}
+}
+
+func TestLimiterShedsWorkersWhenErrorsSpike(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := newTestLimiter(4, clock)
+
+	for _, failed := range []bool{false, true, false} {
+		l.Acquire(context.Background(), nil)
+		l.Release(failed)
+	}
+	if got := l.Limit(); got != 4 {
+		t.Fatalf("limit after one failure = %d, want 4", got)
+	}
+
+	// transient failures reported by git count too
+	var transient int64
+	l.failures = func() int64 { return transient }
+	transient = 3
+	l.Acquire(context.Background(), nil)
+	if got := l.Limit(); got != 2 {
+		t.Fatalf("limit after failures spiked = %d, want 2", got)
+	}
+	l.Release(false)
+}
+
+func TestLimiterAcquireWaitsForAFreeSlot(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := newTestLimiter(1, clock)
+	l.Acquire(context.Background(), nil)
+
+	var waiting string
+	acquired := make(chan error)
+	go func() {
+		acquired <- l.Acquire(context.Background(), func(status string) { waiting = status })
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("acquired a second slot with a limit of 1")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	l.Release(false)
+	if err := <-acquired; err != nil {
+		t.Fatal(err)
+	}
+	if waiting == "" {
+		t.Error("waiting job got no status")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Acquire(ctx, nil); err == nil {
+		t.Error("Acquire on a cancelled context succeeded with every slot taken")
+	}
+}
+
+func TestNilLimiterNeverLimits(t *testing.T) {
+	var l *Limiter
+	if err := l.Acquire(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	l.Release(true)
+}
@@ -0,0 +1,112 @@
+// Package timeline times the phases of a repo's pipeline (clone, AI, verify,
+// push, PR), so the results screen and reports show where a run spent its
+// time.
+package timeline
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Phase is one step of a repo's pipeline and how long it took.
+type Phase struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Recorder times consecutive phases: starting one ends the one before.
+type Recorder struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	phases  []Phase
+	current string
+	started time.Time
+}
+
+// NewRecorder returns a Recorder with no phase started.
+func NewRecorder() *Recorder {
+	return &Recorder{now: time.Now}
+}
+
+// Start ends the current phase and starts name. Starting the phase that is
+// already running does nothing.
+func (r *Recorder) Start(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if name == r.current {
+		return
+	}
+	now := r.now()
+	r.end(now)
+	r.current, r.started = name, now
+}
+
+// Stop ends the current phase and returns every phase in the order they ran.
+func (r *Recorder) Stop() []Phase {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.end(r.now())
+	r.current = ""
+	return append([]Phase(nil), r.phases...)
+}
+
+func (r *Recorder) end(now time.Time) {
+	if r.current == "" {
+		return
+	}
+	r.phases = append(r.phases, Phase{Name: r.current, Duration: now.Sub(r.started)})
+}
+
+// Total returns how long the phases took together.
+func Total(phases []Phase) time.Duration {
+	var total time.Duration
+	for _, p := range phases {
+		total += p.Duration
+	}
+	return total
+}
+
+// Format writes the phases on one line, e.g. "clone 12s → AI 4m10s → push 3s".
+func Format(phases []Phase) string {
+	parts := make([]string, len(phases))
+	for i, p := range phases {
+		parts[i] = p.Name + " " + FormatDuration(p.Duration)
+	}
+	return strings.Join(parts, " → ")
+}
+
+// FormatDuration rounds d for display: 850ms, 12s, 4m10s or 1h05m.
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Round(time.Second).Seconds()))
+	case d < time.Hour:
+		d = d.Round(time.Second)
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// Totals adds up the time spent in each phase across repos, in the order
+// the phases first ran.
+func Totals(timelines [][]Phase) []Phase {
+	index := make(map[string]int)
+	var totals []Phase
+	for _, phases := range timelines {
+		for _, p := range phases {
+			i, ok := index[p.Name]
+			if !ok {
+				i = len(totals)
+				index[p.Name] = i
+				totals = append(totals, Phase{Name: p.Name})
+			}
+			totals[i].Duration += p.Duration
+		}
+	}
+	return totals
+}
@@ -0,0 +1,47 @@
+package timeline
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	clock := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	r := &Recorder{now: func() time.Time { return clock }}
+	advance := func(d time.Duration) { clock = clock.Add(d) }
+
+	r.Start("clone")
+	advance(12 * time.Second)
+	r.Start("AI")
+	advance(4*time.Minute + 10*time.Second)
+	r.Start("AI") // still the same phase
+	advance(0)
+	r.Start("verify")
+	advance(time.Minute)
+	r.Start("push")
+	advance(3 * time.Second)
+	phases := r.Stop()
+
+	want := []Phase{{"clone", 12 * time.Second}, {"AI", 4*time.Minute + 10*time.Second}, {"verify", time.Minute}, {"push", 3 * time.Second}}
+	if !reflect.DeepEqual(phases, want) {
+		t.Fatalf("unexpected phases %+v", phases)
+	}
+	if got := Format(phases); got != "clone 12s → AI 4m10s → verify 1m00s → push 3s" {
+		t.Errorf("unexpected timeline %q", got)
+	}
+	if got := Total(phases); got != 5*time.Minute+25*time.Second {
+		t.Errorf("unexpected total %s", got)
+	}
+}
+
+func TestTotals(t *testing.T) {
+	totals := Totals([][]Phase{
+		{{"clone", 10 * time.Second}, {"AI", time.Minute}},
+		{{"clone", 5 * time.Second}, {"AI", 2 * time.Minute}, {"PR", time.Second}},
+	})
+	want := []Phase{{"clone", 15 * time.Second}, {"AI", 3 * time.Minute}, {"PR", time.Second}}
+	if !reflect.DeepEqual(totals, want) {
+		t.Errorf("unexpected totals %+v", totals)
+	}
+}
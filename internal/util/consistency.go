@@ -0,0 +1,78 @@
+package util
+
+import (
+	"sort"
+	"strings"
+)
+
+// Outlier is a repo whose change to a file differs from the change most of
+// the other repos made to it.
+type Outlier struct {
+	Repo   string
+	Path   string
+	Agreed int // repos that made the common change
+}
+
+// Inconsistencies compares the changes each repo's diff makes to the files
+// changed in at least three repos, and returns the repos whose change to such
+// a file differs from the one most of them made. Only the added lines are
+// compared, ignoring whitespace, so repos that started from different
+// versions of a file still agree when they end up the same. Files where no
+// change is shared by a majority are left out, as there is no common change
+// to compare with. patterns limits the files compared; all are compared when
+// it is empty. Outliers are sorted by path, then repo.
+func Inconsistencies(diffs map[string]string, patterns []string) []Outlier {
+	// changes[path][repo] is the repo's added content for the file
+	changes := make(map[string]map[string]string)
+	for repo, diff := range diffs {
+		for _, file := range splitDiffFiles(diff) {
+			if file.name == "" || (len(patterns) > 0 && !MatchAnyGlob(patterns, file.name)) {
+				continue
+			}
+			var added strings.Builder
+			for _, line := range file.lines {
+				if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
+					added.WriteString(stripSpace(line[1:]))
+					added.WriteByte('\n')
+				}
+			}
+			if changes[file.name] == nil {
+				changes[file.name] = make(map[string]string)
+			}
+			changes[file.name][repo] = added.String()
+		}
+	}
+
+	var outliers []Outlier
+	for path, byRepo := range changes {
+		if len(byRepo) < 3 {
+			continue
+		}
+		counts := make(map[string]int)
+		for _, added := range byRepo {
+			counts[added]++
+		}
+		common, agreed := "", 0
+		for added, count := range counts {
+			if count > agreed {
+				common, agreed = added, count
+			}
+		}
+		if agreed*2 <= len(byRepo) {
+			continue
+		}
+		for repo, added := range byRepo {
+			if added != common {
+				outliers = append(outliers, Outlier{Repo: repo, Path: path, Agreed: agreed})
+			}
+		}
+	}
+
+	sort.Slice(outliers, func(i, j int) bool {
+		if outliers[i].Path != outliers[j].Path {
+			return outliers[i].Path < outliers[j].Path
+		}
+		return outliers[i].Repo < outliers[j].Repo
+	})
+	return outliers
+}
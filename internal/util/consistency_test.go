@@ -0,0 +1,37 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func workflowDiff(from, to string) string {
+	return "diff --git a/.github/workflows/ci.yml b/.github/workflows/ci.yml\n" +
+		"--- a/.github/workflows/ci.yml\n+++ b/.github/workflows/ci.yml\n@@ -1 +1 @@\n" +
+		"-      - uses: actions/checkout@" + from + "\n+      - uses: actions/checkout@" + to + "\n"
+}
+
+func TestInconsistencies(t *testing.T) {
+	readme := "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1 +1 @@\n-old\n+new\n"
+	diffs := map[string]string{
+		"ledger-service": workflowDiff("v2", "v4"),
+		"payments-api":   workflowDiff("v3", "v4") + readme,
+		"card-service":   workflowDiff("v3", "v4"),
+		"fraud-service":  workflowDiff("v3", "v5"),
+	}
+
+	want := []Outlier{{Repo: "fraud-service", Path: ".github/workflows/ci.yml", Agreed: 3}}
+	if got := Inconsistencies(diffs, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if got := Inconsistencies(diffs, []string{"*.md"}); len(got) != 0 {
+		t.Errorf("expected files outside the patterns to be left out, got %+v", got)
+	}
+
+	// Without a majority there is no common change to compare with
+	diffs["card-service"] = workflowDiff("v3", "v5")
+	if got := Inconsistencies(diffs, nil); len(got) != 0 {
+		t.Errorf("expected no outliers without a majority, got %+v", got)
+	}
+}
@@ -0,0 +1,21 @@
+package util
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// FuzzyMatch reports whether every character of pattern appears in s in order,
+// ignoring case (e.g. "pmsvc" matches "payments-service"). An empty pattern matches everything.
+func FuzzyMatch(pattern, s string) bool {
+	pattern = strings.ToLower(pattern)
+	s = strings.ToLower(s)
+	for _, r := range pattern {
+		idx := strings.IndexRune(s, r)
+		if idx < 0 {
+			return false
+		}
+		s = s[idx+utf8.RuneLen(r):]
+	}
+	return true
+}
@@ -0,0 +1,24 @@
+package util
+
+import "testing"
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"", "anything", true},
+		{"pmsvc", "payments-service", true},
+		{"PAY", "payments-service", true},
+		{"svcpay", "payments-service", false},
+		{"#team", "#team-payments", true},
+		{"xyz", "payments-service", false},
+	}
+
+	for _, tt := range tests {
+		if got := FuzzyMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("FuzzyMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
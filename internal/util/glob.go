@@ -0,0 +1,67 @@
+package util
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether a slash-separated relative path matches pattern.
+// Patterns follow path.Match syntax per segment, plus "**" which matches
+// zero or more whole path segments (e.g. "**/secrets/**").
+// A pattern without a slash matches against the base name at any depth.
+func MatchGlob(pattern, name string) bool {
+	pattern = strings.Trim(strings.TrimSpace(pattern), "/")
+	name = strings.Trim(name, "/")
+	if pattern == "" || name == "" {
+		return false
+	}
+
+	if !strings.Contains(pattern, "/") && pattern != "**" {
+		matched, _ := path.Match(pattern, path.Base(name))
+		return matched
+	}
+
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// MatchAnyGlob reports whether name matches any of the given patterns.
+func MatchAnyGlob(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if MatchGlob(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse consecutive ** segments
+			rest := pattern[1:]
+			for len(rest) > 0 && rest[0] == "**" {
+				rest = rest[1:]
+			}
+			if len(rest) == 0 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		matched, err := path.Match(pattern[0], name[0])
+		if err != nil || !matched {
+			return false
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,90 @@
+package util
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{
+			name:     "Double star directory anywhere",
+			pattern:  "**/secrets/**",
+			path:     "config/secrets/prod.env",
+			expected: true,
+		},
+		{
+			name:     "Double star matches top level directory",
+			pattern:  "**/secrets/**",
+			path:     "secrets/key.pem",
+			expected: true,
+		},
+		{
+			name:     "Single star stays within directory",
+			pattern:  ".github/workflows/*",
+			path:     ".github/workflows/ci.yml",
+			expected: true,
+		},
+		{
+			name:     "Single star does not cross directories",
+			pattern:  ".github/workflows/*",
+			path:     ".github/workflows/nested/ci.yml",
+			expected: false,
+		},
+		{
+			name:     "Base name pattern matches at any depth",
+			pattern:  "*.pem",
+			path:     "deploy/certs/server.pem",
+			expected: true,
+		},
+		{
+			name:     "Exact path",
+			pattern:  "go.mod",
+			path:     "go.mod",
+			expected: true,
+		},
+		{
+			name:     "Non-matching path",
+			pattern:  "**/secrets/**",
+			path:     "src/main.go",
+			expected: false,
+		},
+		{
+			name:     "Empty pattern never matches",
+			pattern:  "",
+			path:     "src/main.go",
+			expected: false,
+		},
+		{
+			name:     "Leading slash is ignored",
+			pattern:  "/.github/workflows/*",
+			path:     ".github/workflows/release.yml",
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MatchGlob(tt.pattern, tt.path)
+			if result != tt.expected {
+				t.Errorf("MatchGlob(%q, %q) = %v, expected %v", tt.pattern, tt.path, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchAnyGlob(t *testing.T) {
+	patterns := []string{".github/workflows/*", "**/secrets/**"}
+
+	if !MatchAnyGlob(patterns, "app/secrets/token") {
+		t.Error("expected app/secrets/token to match")
+	}
+	if MatchAnyGlob(patterns, "README.md") {
+		t.Error("expected README.md not to match")
+	}
+	if MatchAnyGlob(nil, "README.md") {
+		t.Error("expected no match for empty pattern list")
+	}
+}
@@ -0,0 +1,22 @@
+package util
+
+import "strings"
+
+// MaskSecret hides all but the last 4 characters of a secret such as a
+// token, so it can be shown without being readable.
+func MaskSecret(secret string) string {
+	runes := []rune(secret)
+	if len(runes) <= 4 {
+		return strings.Repeat("•", len(runes))
+	}
+	return strings.Repeat("•", 8) + string(runes[len(runes)-4:])
+}
+
+// RedactSecret replaces every occurrence of secret in text with its masked
+// form. An empty secret leaves text unchanged.
+func RedactSecret(text, secret string) string {
+	if secret == "" {
+		return text
+	}
+	return strings.ReplaceAll(text, secret, MaskSecret(secret))
+}
@@ -0,0 +1,126 @@
+package util
+
+import (
+	"path"
+	"strings"
+	"unicode"
+)
+
+// Kinds of low-value diffs reported by TrivialDiff.
+const (
+	TrivialWhitespace = "whitespace"
+	TrivialComments   = "comments"
+)
+
+// DefaultCommentPrefixes are the line comment markers TrivialDiff uses when
+// none are configured.
+var DefaultCommentPrefixes = []string{"//", "#", "/*", "*", "*/", "--", ";", "<!--"}
+
+// proseExtensions are files where every line is content, so changes there are
+// never treated as comment-only.
+var proseExtensions = map[string]bool{".md": true, ".txt": true, ".rst": true, ".adoc": true}
+
+// TrivialDiff reports whether a unified diff changes only whitespace
+// (TrivialWhitespace) or only comments and whitespace (TrivialComments).
+// It returns "" when the diff contains real changes. Binary changes, renames,
+// and mode changes always count as real changes.
+func TrivialDiff(diff string, commentPrefixes []string) string {
+	if strings.TrimSpace(diff) == "" {
+		return ""
+	}
+	if len(commentPrefixes) == 0 {
+		commentPrefixes = DefaultCommentPrefixes
+	}
+
+	kind := TrivialWhitespace
+	for _, file := range splitDiffFiles(diff) {
+		var removed, added, removedCode, addedCode strings.Builder
+		prose := proseExtensions[strings.ToLower(path.Ext(file.name))]
+		changed := false
+		for _, line := range file.lines {
+			if strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---") {
+				continue
+			}
+			if strings.HasPrefix(line, "Binary files ") {
+				return ""
+			}
+			var all, code *strings.Builder
+			switch {
+			case strings.HasPrefix(line, "-"):
+				all, code = &removed, &removedCode
+			case strings.HasPrefix(line, "+"):
+				all, code = &added, &addedCode
+			default:
+				continue
+			}
+			changed = true
+			content := stripSpace(line[1:])
+			all.WriteString(content)
+			if prose || !isComment(strings.TrimSpace(line[1:]), commentPrefixes) {
+				code.WriteString(content)
+			}
+		}
+
+		// Renames, mode changes and empty new files have no content lines
+		if !changed {
+			return ""
+		}
+		if removed.String() == added.String() {
+			continue
+		}
+		if removedCode.String() != addedCode.String() {
+			return ""
+		}
+		kind = TrivialComments
+	}
+	return kind
+}
+
+// diffFile is one file's section of a unified diff.
+type diffFile struct {
+	name  string
+	lines []string
+}
+
+// splitDiffFiles splits a unified diff into per-file sections.
+func splitDiffFiles(diff string) []diffFile {
+	var files []diffFile
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			name := line
+			if idx := strings.LastIndex(line, " b/"); idx >= 0 {
+				name = line[idx+3:]
+			}
+			files = append(files, diffFile{name: name})
+			continue
+		}
+		if len(files) == 0 {
+			files = append(files, diffFile{})
+		}
+		files[len(files)-1].lines = append(files[len(files)-1].lines, line)
+	}
+	return files
+}
+
+// isComment reports whether a trimmed line is blank or starts with a comment marker.
+func isComment(line string, prefixes []string) bool {
+	if line == "" {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripSpace removes every whitespace character from s.
+func stripSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,39 @@
+package util
+
+import "testing"
+
+func TestTrivialDiff(t *testing.T) {
+	header := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1,3 +1,3 @@\n"
+	tests := []struct {
+		name string
+		diff string
+		want string
+	}{
+		{"empty", "", ""},
+		{"reindent", header + "-func a() {\n+func a()  {\n-\treturn 1\n+    return 1\n", TrivialWhitespace},
+		{"comment added", header + "+// explains a\n func a() {\n", TrivialComments},
+		{"comment and reindent", header + "-\treturn 1\n+  return 1\n-# old\n+# new\n", TrivialComments},
+		{"code change", header + "-\treturn 1\n+\treturn 2\n", ""},
+		{"code commented out", header + "-\treturn 1\n+\t// return 1\n", ""},
+		{"markdown bullet", "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n-* old\n+* new\n", ""},
+		{"binary", "diff --git a/logo.png b/logo.png\nBinary files a/logo.png and b/logo.png differ\n", ""},
+		{"rename", "diff --git a/a.go b/b.go\nsimilarity index 100%\nrename from a.go\nrename to b.go\n", ""},
+		{"one real file", header + "+// note\n" + "diff --git a/b.go b/b.go\n--- a/b.go\n+++ b/b.go\n-x := 1\n+x := 2\n", ""},
+	}
+
+	for _, tt := range tests {
+		if got := TrivialDiff(tt.diff, nil); got != tt.want {
+			t.Errorf("%s: TrivialDiff = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTrivialDiffCustomPrefixes(t *testing.T) {
+	diff := "diff --git a/q.sql b/q.sql\n--- a/q.sql\n+++ b/q.sql\n+REM note\n"
+	if got := TrivialDiff(diff, nil); got != "" {
+		t.Errorf("expected real change with default prefixes, got %q", got)
+	}
+	if got := TrivialDiff(diff, []string{"REM"}); got != TrivialComments {
+		t.Errorf("expected comment change with custom prefix, got %q", got)
+	}
+}
@@ -0,0 +1,178 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// apiBreakingChanges fails a repo when the AI changed a protobuf or OpenAPI
+// contract in a backwards-incompatible way, using buf and oasdiff.
+type apiBreakingChanges struct{}
+
+func (apiBreakingChanges) Name() string { return config.VerifyAPIBreakingChanges }
+
+func (apiBreakingChanges) Verify(ctx context.Context, repoPath string, changed []string) (Report, error) {
+	var warnings, breaking []string
+
+	if modules := protoModules(repoPath, changed); len(modules) > 0 {
+		if _, err := exec.LookPath("buf"); err != nil {
+			warnings = append(warnings, "buf not installed, proto breaking-change check skipped")
+		} else {
+			for _, dir := range modules {
+				out, err := checkProto(ctx, repoPath, dir)
+				if err != nil {
+					return Report{Warnings: warnings}, err
+				}
+				if out != "" {
+					breaking = append(breaking, out)
+				}
+			}
+		}
+	}
+
+	if specs := openAPISpecs(repoPath, changed); len(specs) > 0 {
+		if _, err := exec.LookPath("oasdiff"); err != nil {
+			warnings = append(warnings, "oasdiff not installed, OpenAPI breaking-change check skipped")
+		} else {
+			for _, spec := range specs {
+				out, err := checkOpenAPI(ctx, repoPath, spec)
+				if err != nil {
+					return Report{Warnings: warnings}, err
+				}
+				if out != "" {
+					breaking = append(breaking, spec+":\n"+out)
+				}
+			}
+		}
+	}
+
+	if len(breaking) > 0 {
+		return Report{Warnings: warnings}, fmt.Errorf("breaking API changes:\n%s", strings.Join(breaking, "\n"))
+	}
+	return Report{Warnings: warnings}, nil
+}
+
+// protoModules returns the buf module directories containing changed .proto
+// files: the nearest ancestor with a buf.yaml, or the repo root.
+func protoModules(repoPath string, changed []string) []string {
+	seen := make(map[string]bool)
+	var modules []string
+	for _, file := range changed {
+		if path.Ext(file) != ".proto" {
+			continue
+		}
+		dir, ok := nearestDirWith(repoPath, file, "buf.yaml")
+		if !ok {
+			dir = "."
+		}
+		if !seen[dir] {
+			seen[dir] = true
+			modules = append(modules, dir)
+		}
+	}
+	return modules
+}
+
+// openAPISpecs returns the changed YAML/JSON files that existed in HEAD and
+// look like OpenAPI or Swagger documents.
+func openAPISpecs(repoPath string, changed []string) []string {
+	var specs []string
+	for _, file := range changed {
+		switch strings.ToLower(path.Ext(file)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(repoPath, file))
+		if err != nil {
+			continue // deleted; buf/oasdiff cannot compare a missing file
+		}
+		if isOpenAPI(data) {
+			specs = append(specs, file)
+		}
+	}
+	return specs
+}
+
+// isOpenAPI reports whether a document declares an openapi or swagger version
+// at the top level.
+func isOpenAPI(data []byte) bool {
+	if len(data) > 4096 {
+		data = data[:4096]
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		for _, key := range []string{"openapi", "swagger"} {
+			if bytes.HasPrefix(line, []byte(key+":")) || bytes.HasPrefix(line, []byte(`"`+key+`":`)) || bytes.HasPrefix(line, []byte(`{"`+key+`":`)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkProto runs buf breaking for a module against HEAD and returns its
+// findings, or "" when there are none.
+func checkProto(ctx context.Context, repoPath, dir string) (string, error) {
+	against := ".git#ref=HEAD"
+	if dir != "." {
+		against += ",subdir=" + dir
+	}
+	cmd := exec.CommandContext(ctx, "buf", "breaking", dir, "--against", against)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	return toolFindings("buf breaking", output, err)
+}
+
+// checkOpenAPI compares a spec with its HEAD version using oasdiff and returns
+// its findings, or "" when there are none.
+func checkOpenAPI(ctx context.Context, repoPath, spec string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "show", "HEAD:"+spec)
+	cmd.Dir = repoPath
+	base, err := cmd.Output()
+	if err != nil {
+		return "", nil // new spec: nothing to break
+	}
+
+	tmp, err := os.CreateTemp("", "copycat-openapi-base-*"+path.Ext(spec))
+	if err != nil {
+		return "", fmt.Errorf("failed to write base spec: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(base); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write base spec: %w", err)
+	}
+	tmp.Close()
+
+	cmd = exec.CommandContext(ctx, "oasdiff", "breaking", tmp.Name(), spec, "--fail-on", "ERR")
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	return toolFindings("oasdiff breaking", output, err)
+}
+
+// toolFindings turns a checker's result into findings: a non-zero exit with
+// output means breaking changes were found.
+func toolFindings(tool string, output []byte, err error) (string, error) {
+	if err == nil {
+		return "", nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return "", fmt.Errorf("%s failed: %w", tool, err)
+	}
+	out := strings.TrimSpace(string(output))
+	if out == "" {
+		return "", fmt.Errorf("%s failed: %w", tool, err)
+	}
+	return out, nil
+}
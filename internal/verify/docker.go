@@ -0,0 +1,52 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// buildOutputLines is how much docker build output is kept when a build fails.
+const buildOutputLines = 50
+
+// dockerBuild builds the repo's root Dockerfile, since many services only
+// break at image build time.
+type dockerBuild struct{}
+
+func (dockerBuild) Name() string { return config.VerifyDockerBuild }
+
+func (dockerBuild) Verify(ctx context.Context, repoPath string, _ []string) (Report, error) {
+	if _, err := os.Stat(filepath.Join(repoPath, "Dockerfile")); err != nil {
+		return Report{}, nil
+	}
+	if _, err := exec.LookPath("docker"); err != nil {
+		return Report{Warnings: []string{"docker not installed, image build skipped"}}, nil
+	}
+
+	// A stable tag per repo keeps the layer cache warm across runs
+	cmd := exec.CommandContext(ctx, "docker", "build", "--progress=plain", "-t", imageTag(repoPath), ".")
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return Report{}, fmt.Errorf("docker build failed: %v\n%s", err, tail(output, buildOutputLines))
+	}
+	return Report{}, nil
+}
+
+// imageTag names the verification image after the repo directory.
+func imageTag(repoPath string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return '-'
+	}, filepath.Base(repoPath))
+	return "copycat-verify/" + strings.Trim(name, ".-_") + ":latest"
+}
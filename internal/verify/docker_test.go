@@ -0,0 +1,43 @@
+package verify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImageTag(t *testing.T) {
+	tests := []struct {
+		repoPath string
+		want     string
+	}{
+		{"repos/payments-api", "copycat-verify/payments-api:latest"},
+		{"repos/api@release-1.x", "copycat-verify/api-release-1.x:latest"},
+	}
+	for _, tt := range tests {
+		if got := imageTag(tt.repoPath); got != tt.want {
+			t.Errorf("imageTag(%q) = %q, want %q", tt.repoPath, got, tt.want)
+		}
+	}
+}
+
+func TestDockerBuildWithoutDockerfile(t *testing.T) {
+	report, err := dockerBuild{}.Verify(context.Background(), t.TempDir(), nil)
+	if err != nil || len(report.Warnings) != 0 {
+		t.Errorf("expected repos without a Dockerfile to be skipped, got %+v, %v", report, err)
+	}
+}
+
+func TestDockerBuildWithoutDocker(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM scratch\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report, err := dockerBuild{}.Verify(context.Background(), dir, nil)
+	if err != nil || len(report.Warnings) != 1 {
+		t.Errorf("expected missing docker to be a warning, got %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,161 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// failureLines is how much tool output is kept when a check fails.
+const failureLines = 20
+
+// terraformPlan runs `terraform plan` in every directory with changed
+// Terraform files and adds the plan summary to the PR description.
+type terraformPlan struct{}
+
+func (terraformPlan) Name() string { return config.VerifyTerraformPlan }
+
+func (terraformPlan) Verify(ctx context.Context, repoPath string, changed []string) (Report, error) {
+	dirs := terraformDirs(repoPath, changed)
+	if len(dirs) == 0 {
+		return Report{}, nil
+	}
+	if _, err := exec.LookPath("terraform"); err != nil {
+		return Report{Warnings: []string{"terraform not installed, plan skipped"}}, nil
+	}
+
+	var rows []string
+	for _, dir := range dirs {
+		cmd := exec.CommandContext(ctx, "terraform", "-chdir="+dir, "init", "-input=false", "-no-color")
+		cmd.Dir = repoPath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return Report{}, fmt.Errorf("terraform init failed in %s: %v\n%s", dir, err, tail(output, failureLines))
+		}
+
+		// -detailed-exitcode exits 2 when the plan has changes
+		cmd = exec.CommandContext(ctx, "terraform", "-chdir="+dir, "plan", "-input=false", "-no-color", "-lock=false", "-detailed-exitcode")
+		cmd.Dir = repoPath
+		output, err := cmd.CombinedOutput()
+		var exitErr *exec.ExitError
+		if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 2) {
+			return Report{}, fmt.Errorf("terraform plan failed in %s: %v\n%s", dir, err, tail(output, failureLines))
+		}
+		rows = append(rows, fmt.Sprintf("| `%s` | %s |", dir, planSummary(string(output))))
+	}
+
+	summary := "### Terraform plan\n\n| Directory | Result |\n|---|---|\n" + strings.Join(rows, "\n")
+	return Report{Summary: summary}, nil
+}
+
+// terraformDirs returns the directories containing changed .tf or .tfvars files.
+func terraformDirs(repoPath string, changed []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, file := range changed {
+		switch path.Ext(file) {
+		case ".tf", ".tfvars":
+		default:
+			continue
+		}
+		dir := path.Dir(file)
+		if _, err := os.Stat(filepath.Join(repoPath, dir)); err != nil || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// planSummary extracts the one-line outcome from terraform plan output.
+func planSummary(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Plan: ") || strings.HasPrefix(line, "No changes.") {
+			return line
+		}
+	}
+	return "plan succeeded"
+}
+
+// helmTemplate renders every chart with changed files using `helm template`
+// and adds the rendered resource counts to the PR description.
+type helmTemplate struct{}
+
+func (helmTemplate) Name() string { return config.VerifyHelmTemplate }
+
+func (helmTemplate) Verify(ctx context.Context, repoPath string, changed []string) (Report, error) {
+	charts := helmCharts(repoPath, changed)
+	if len(charts) == 0 {
+		return Report{}, nil
+	}
+	if _, err := exec.LookPath("helm"); err != nil {
+		return Report{Warnings: []string{"helm not installed, template check skipped"}}, nil
+	}
+
+	var rows []string
+	for _, chart := range charts {
+		cmd := exec.CommandContext(ctx, "helm", "template", "copycat", chart)
+		cmd.Dir = repoPath
+		output, err := cmd.Output()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				output = append(output, exitErr.Stderr...)
+			}
+			return Report{}, fmt.Errorf("helm template failed for %s: %v\n%s", chart, err, tail(output, failureLines))
+		}
+		rows = append(rows, fmt.Sprintf("| `%s` | %s |", chart, resourceSummary(string(output))))
+	}
+
+	summary := "### Helm template\n\n| Chart | Rendered resources |\n|---|---|\n" + strings.Join(rows, "\n")
+	return Report{Summary: summary}, nil
+}
+
+// helmCharts returns the chart directories (those with a Chart.yaml) that
+// contain changed files.
+func helmCharts(repoPath string, changed []string) []string {
+	seen := make(map[string]bool)
+	var charts []string
+	for _, file := range changed {
+		chart, ok := nearestDirWith(repoPath, file, "Chart.yaml")
+		if !ok || seen[chart] {
+			continue
+		}
+		seen[chart] = true
+		charts = append(charts, chart)
+	}
+	return charts
+}
+
+// resourceSummary counts rendered manifests by kind, e.g. "2 Deployment, 1 Service".
+func resourceSummary(manifests string) string {
+	counts := make(map[string]int)
+	for _, line := range strings.Split(manifests, "\n") {
+		if kind, ok := strings.CutPrefix(line, "kind: "); ok {
+			counts[strings.TrimSpace(kind)]++
+		}
+	}
+	if len(counts) == 0 {
+		return "no resources"
+	}
+
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	parts := make([]string, len(kinds))
+	for i, kind := range kinds {
+		parts[i] = fmt.Sprintf("%d %s", counts[kind], kind)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,81 @@
+package verify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTerraformDirs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "infra", "prod"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	dirs := terraformDirs(dir, []string{"infra/prod/main.tf", "infra/prod/prod.tfvars", "main.tf", "gone/old.tf", "README.md"})
+	if strings.Join(dirs, ",") != "infra/prod,." {
+		t.Errorf("unexpected dirs: %v", dirs)
+	}
+}
+
+func TestPlanSummary(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"Refreshing state...\n\nPlan: 1 to add, 2 to change, 0 to destroy.\n", "Plan: 1 to add, 2 to change, 0 to destroy."},
+		{"No changes. Your infrastructure matches the configuration.\n", "No changes. Your infrastructure matches the configuration."},
+		{"something else\n", "plan succeeded"},
+	}
+	for _, tt := range tests {
+		if got := planSummary(tt.output); got != tt.want {
+			t.Errorf("planSummary(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestHelmCharts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "charts", "api", "templates"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "charts", "api", "Chart.yaml"), []byte("name: api\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	charts := helmCharts(dir, []string{"charts/api/templates/deploy.yaml", "charts/api/values.yaml", "main.go"})
+	if strings.Join(charts, ",") != "charts/api" {
+		t.Errorf("unexpected charts: %v", charts)
+	}
+}
+
+func TestResourceSummary(t *testing.T) {
+	manifests := "---\nkind: Service\n---\nkind: Deployment\nspec:\n  template:\n    kind: ignored\n---\nkind: Service\n"
+	if got := resourceSummary(manifests); got != "1 Deployment, 2 Service" {
+		t.Errorf("unexpected summary: %q", got)
+	}
+	if got := resourceSummary(""); got != "no resources" {
+		t.Errorf("unexpected summary for empty output: %q", got)
+	}
+}
+
+func TestInfraVerifiersWithoutTools(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []Verifier{terraformPlan{}, helmTemplate{}} {
+		report, err := v.Verify(context.Background(), dir, []string{"main.tf", "values.yaml"})
+		if err != nil {
+			t.Fatalf("%s: expected missing tool to be a warning, got %v", v.Name(), err)
+		}
+		if len(report.Warnings) != 1 || report.Summary != "" {
+			t.Errorf("%s: unexpected report %+v", v.Name(), report)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// Report is what verifiers found in a repo that did not stop the push.
+type Report struct {
+	Warnings []string // checks that could not run
+	Summary  string   // markdown added to the PR description, if any
+}
+
+// Verifier checks the AI's uncommitted changes in a repo before they are pushed.
+type Verifier interface {
+	// Name identifies the verifier in config and in results.
+	Name() string
+	// Verify inspects the working tree against HEAD. It returns an error when
+	// the changes must not be pushed.
+	Verify(ctx context.Context, repoPath string, changed []string) (Report, error)
+}
+
+// builtins are the verifiers that can be enabled by name under `verify:`.
+var builtins = map[string]Verifier{
+	config.VerifyAPIBreakingChanges: apiBreakingChanges{},
+	config.VerifyTerraformPlan:      terraformPlan{},
+	config.VerifyHelmTemplate:       helmTemplate{},
+	config.VerifyDockerBuild:        dockerBuild{},
+}
+
+// Lookup returns the built-in verifiers with the given names, ignoring unknown names.
+func Lookup(names []string) []Verifier {
+	var verifiers []Verifier
+	for _, name := range names {
+		if v, ok := builtins[name]; ok {
+			verifiers = append(verifiers, v)
+		}
+	}
+	return verifiers
+}
+
+// Run runs every verifier against the repo and stops at the first failure.
+func Run(ctx context.Context, repoPath string, verifiers []Verifier) (Report, error) {
+	var report Report
+	if len(verifiers) == 0 {
+		return report, nil
+	}
+
+	changed, err := changedFiles(ctx, repoPath)
+	if err != nil {
+		return report, err
+	}
+
+	var summaries []string
+	for _, v := range verifiers {
+		r, err := v.Verify(ctx, repoPath, changed)
+		report.Warnings = append(report.Warnings, r.Warnings...)
+		if r.Summary != "" {
+			summaries = append(summaries, r.Summary)
+		}
+		if err != nil {
+			return report, fmt.Errorf("%s: %w", v.Name(), err)
+		}
+	}
+	report.Summary = strings.Join(summaries, "\n\n")
+	return report, nil
+}
+
+// changedFiles lists files changed in the working tree or index relative to
+// HEAD, including untracked files.
+func changedFiles(ctx context.Context, repoPath string) ([]string, error) {
+	var files []string
+	for _, args := range [][]string{
+		{"diff", "--name-only", "HEAD"},
+		{"ls-files", "--others", "--exclude-standard"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = repoPath
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list changed files: %w", err)
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				files = append(files, line)
+			}
+		}
+	}
+	return files, nil
+}
+
+// nearestDirWith returns the closest directory containing file (relative to
+// repoPath) that holds marker, walking up to the repo root.
+func nearestDirWith(repoPath, file, marker string) (string, bool) {
+	for dir := path.Dir(file); ; dir = path.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(repoPath, dir, marker)); err == nil {
+			return dir, true
+		}
+		if dir == "." || dir == "/" {
+			return "", false
+		}
+	}
+}
+
+// tail returns the last n lines of output.
+func tail(output []byte, n int) string {
+	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
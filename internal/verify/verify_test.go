@@ -0,0 +1,125 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recordingVerifier records the changed files it was given.
+type recordingVerifier struct {
+	changed []string
+	err     error
+}
+
+func (v *recordingVerifier) Name() string { return "recording" }
+
+func (v *recordingVerifier) Verify(_ context.Context, _ string, changed []string) (Report, error) {
+	v.changed = changed
+	return Report{Warnings: []string{"checked"}, Summary: "all good"}, v.err
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, out)
+		}
+	}
+	write := func(rel, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("api.proto", "syntax = \"proto3\";\n")
+	write("README.md", "docs\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "initial")
+
+	write("api.proto", "syntax = \"proto3\";\npackage x;\n")
+	write("new.yaml", "openapi: 3.0.0\n")
+
+	v := &recordingVerifier{}
+	report, err := Run(context.Background(), dir, []Verifier{v, v})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if strings.Join(v.changed, ",") != "api.proto,new.yaml" {
+		t.Errorf("unexpected changed files: %v", v.changed)
+	}
+	if len(report.Warnings) != 2 {
+		t.Errorf("expected verifier warnings to be returned, got %v", report.Warnings)
+	}
+	if report.Summary != "all good\n\nall good" {
+		t.Errorf("expected summaries to be joined, got %q", report.Summary)
+	}
+
+	v.err = errors.New("boom")
+	if _, err := Run(context.Background(), dir, []Verifier{v}); err == nil || !strings.Contains(err.Error(), "recording: boom") {
+		t.Errorf("expected failure to name the verifier, got %v", err)
+	}
+}
+
+func TestIsOpenAPI(t *testing.T) {
+	tests := []struct {
+		data string
+		want bool
+	}{
+		{"openapi: 3.0.3\ninfo:\n  title: x\n", true},
+		{"swagger: \"2.0\"\n", true},
+		{"{\n  \"openapi\": \"3.1.0\"\n}", true},
+		{`{"openapi":"3.1.0"}`, true},
+		{"apiVersion: apps/v1\nkind: Deployment\n", false},
+	}
+	for _, tt := range tests {
+		if got := isOpenAPI([]byte(tt.data)); got != tt.want {
+			t.Errorf("isOpenAPI(%q) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestProtoModules(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "proto", "payments"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "proto", "buf.yaml"), []byte("version: v2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	modules := protoModules(dir, []string{"proto/payments/a.proto", "proto/b.proto", "other/c.proto", "main.go"})
+	if strings.Join(modules, ",") != "proto,." {
+		t.Errorf("unexpected modules: %v", modules)
+	}
+}
+
+func TestAPIBreakingChangesWithoutTools(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "openapi.yaml"), []byte("openapi: 3.0.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := apiBreakingChanges{}.Verify(context.Background(), dir, []string{"api.proto", "openapi.yaml"})
+	if err != nil {
+		t.Fatalf("expected missing tools to be a warning, got %v", err)
+	}
+	if len(report.Warnings) != 2 {
+		t.Errorf("expected a warning per missing tool, got %v", report.Warnings)
+	}
+}
@@ -0,0 +1,243 @@
+// Package webhook POSTs a JSON event to a configured URL as a run starts,
+// as each repo finishes and when the run ends, so runs can be followed by
+// observability and change management systems.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+// Delivery limits; a receiver that is down delays events, never the run.
+const (
+	requestTimeout = 10 * time.Second
+	maxAttempts    = 3
+	queueSize      = 256
+)
+
+// drainTimeout is how long Finish waits for queued events to be delivered
+// before giving up on them.
+var drainTimeout = 5 * time.Second
+
+// retryDelay is how long to wait before trying an event again.
+var retryDelay = 2 * time.Second
+
+// Event is the JSON body POSTed for each step of a run. Events of a run
+// share its RunID and arrive in the order they happened.
+type Event struct {
+	Type       string    `json:"type"` // one of the config.Event* types
+	RunID      string    `json:"run_id"`
+	Time       time.Time `json:"time"`
+	Action     string    `json:"action"`                // local, assessment or issues
+	Title      string    `json:"title,omitempty"`       // of the run's PRs or issues
+	CampaignID string    `json:"campaign_id,omitempty"` // set with --campaign
+	Repo       string    `json:"repo,omitempty"`        // repo events only
+	URL        string    `json:"url,omitempty"`         // the repo's PR or issue
+	Error      string    `json:"error,omitempty"`       // why the repo failed
+	Status     string    `json:"status,omitempty"`      // the repo's final status line
+	Repos      int       `json:"repos,omitempty"`       // repos selected for the run
+	Succeeded  *int      `json:"succeeded,omitempty"`   // run.finished only
+	Failed     *int      `json:"failed,omitempty"`      // run.finished only
+	Skipped    *int      `json:"skipped,omitempty"`     // run.finished only
+}
+
+// RunInfo describes a run in its events.
+type RunInfo struct {
+	Action     string
+	Title      string
+	CampaignID string
+	Repos      int
+}
+
+// Run sends a run's events in the background, one at a time and in order.
+type Run struct {
+	cfg     config.Webhook
+	info    RunInfo
+	id      string
+	onError func(error)
+
+	events   chan Event
+	finished chan struct{} // closed once run.finished is queued
+	done     chan struct{} // closed once delivery stopped
+	ctx      context.Context
+	cancel   context.CancelFunc // abandons the events still being delivered
+
+	mu                         sync.Mutex
+	succeeded, failed, skipped int
+	closed                     bool
+}
+
+// Start sends the run.started event of a new run and returns the Run its
+// other events are sent through. onError hears about events that couldn't
+// be delivered.
+func Start(cfg config.Webhook, info RunInfo, onError func(error)) *Run {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Run{
+		cfg:      cfg,
+		info:     info,
+		id:       uuid.New().String(),
+		onError:  onError,
+		events:   make(chan Event, queueSize),
+		finished: make(chan struct{}),
+		done:     make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	go r.deliverAll()
+	r.emit(Event{Type: config.EventRunStarted, Repos: info.Repos})
+	return r
+}
+
+// RepoDone sends the repo.succeeded, repo.failed or repo.skipped event of a
+// finished repo.
+func (r *Run) RepoDone(repo, url, status string, success, skipped bool, err error) {
+	event := Event{Repo: repo, URL: url, Status: status}
+	r.mu.Lock()
+	switch {
+	case success:
+		event.Type = config.EventRepoSucceeded
+		r.succeeded++
+	case skipped:
+		event.Type = config.EventRepoSkipped
+		r.skipped++
+	default:
+		event.Type = config.EventRepoFailed
+		r.failed++
+		if err != nil {
+			event.Error = err.Error()
+		}
+	}
+	r.mu.Unlock()
+	r.emit(event)
+}
+
+// Finish sends the run.finished event with the run's tally, and waits a few
+// seconds for the events still queued to be delivered before abandoning them.
+func (r *Run) Finish() {
+	defer r.cancel()
+	r.mu.Lock()
+	succeeded, failed, skipped := r.succeeded, r.failed, r.skipped
+	r.mu.Unlock()
+	r.emit(Event{Type: config.EventRunFinished, Repos: r.info.Repos, Succeeded: &succeeded, Failed: &failed, Skipped: &skipped})
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	close(r.finished)
+
+	select {
+	case <-r.done:
+	case <-time.After(drainTimeout):
+		r.onError(fmt.Errorf("gave up waiting for webhook events to be delivered"))
+	}
+}
+
+// emit queues event for delivery, when the webhook wants its type. Events
+// after the run finished are dropped, and so are events that don't fit in
+// the queue while the receiver is slow, since the run never waits for it.
+func (r *Run) emit(event Event) {
+	r.mu.Lock()
+	closed := r.closed
+	r.mu.Unlock()
+	if closed || !r.cfg.Wants(event.Type) {
+		return
+	}
+	event.RunID = r.id
+	event.Time = time.Now().UTC()
+	event.Action = r.info.Action
+	event.Title = r.info.Title
+	event.CampaignID = r.info.CampaignID
+	select {
+	case r.events <- event:
+	default:
+		r.onError(fmt.Errorf("dropped a %s webhook event: %d events are already waiting to be sent", event.Type, queueSize))
+	}
+}
+
+// deliverAll sends queued events until the run finished and its events ran
+// out, or Finish gave up on them.
+func (r *Run) deliverAll() {
+	defer close(r.done)
+	for {
+		select {
+		case event := <-r.events:
+			r.send(event)
+		case <-r.finished:
+			for {
+				select {
+				case event := <-r.events:
+					r.send(event)
+				default:
+					return
+				}
+			}
+		}
+		if r.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// send delivers event, trying again a few times before reporting it lost.
+func (r *Run) send(event Event) {
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = deliver(r.ctx, r.cfg, event); err == nil || r.ctx.Err() != nil {
+			return
+		}
+		if attempt < maxAttempts {
+			select {
+			case <-time.After(retryDelay):
+			case <-r.ctx.Done():
+				return
+			}
+		}
+	}
+	r.onError(fmt.Errorf("failed to send the %s webhook event: %w", event.Type, err))
+}
+
+// deliver POSTs one event. Any answer other than a 2xx response is an error.
+func deliver(ctx context.Context, cfg config.Webhook, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Copycat-Event", event.Type)
+	if cfg.TokenEnv != "" {
+		token := strings.TrimSpace(os.Getenv(cfg.TokenEnv))
+		if token == "" {
+			return fmt.Errorf("set %s to authenticate with the webhook receiver", cfg.TokenEnv)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook receiver unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("webhook receiver returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/config"
+)
+
+func TestRunEvents(t *testing.T) {
+	var mu sync.Mutex
+	var events []Event
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer hook-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil || r.Header.Get("X-Copycat-Event") != event.Type {
+			http.Error(w, "bad event", http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+	t.Setenv("HOOK_TOKEN", "hook-token")
+
+	var failures []error
+	run := Start(config.Webhook{URL: receiver.URL, TokenEnv: "HOOK_TOKEN"}, RunInfo{Action: "local", Title: "Bump Go", CampaignID: "go-1.23", Repos: 3}, func(err error) {
+		failures = append(failures, err)
+	})
+	run.RepoDone("payments-api", "https://github.com/acme/payments-api/pull/7", "Done ✅", true, false, nil)
+	run.RepoDone("ledger-service", "", "Failed ⚠️ tests failed", false, false, errors.New("tests failed"))
+	run.RepoDone("web", "", "No changes", false, true, nil)
+	run.Finish()
+
+	if len(failures) > 0 {
+		t.Fatalf("unexpected delivery failures: %v", failures)
+	}
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type)
+		if e.RunID != events[0].RunID || e.Action != "local" || e.Title != "Bump Go" || e.CampaignID != "go-1.23" {
+			t.Errorf("expected every event to describe the run, got %+v", e)
+		}
+	}
+	if got := strings.Join(types, " "); got != "run.started repo.succeeded repo.failed repo.skipped run.finished" {
+		t.Fatalf("events = %s", got)
+	}
+	if e := events[1]; e.Repo != "payments-api" || e.URL != "https://github.com/acme/payments-api/pull/7" {
+		t.Errorf("unexpected repo.succeeded event %+v", e)
+	}
+	if e := events[2]; e.Error != "tests failed" {
+		t.Errorf("expected the failure's reason, got %+v", e)
+	}
+	if e := events[4]; e.Repos != 3 || *e.Succeeded != 1 || *e.Failed != 1 || *e.Skipped != 1 {
+		t.Errorf("unexpected tally %+v", e)
+	}
+}
+
+func TestRunEventsFiltered(t *testing.T) {
+	var types []string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		types = append(types, r.Header.Get("X-Copycat-Event"))
+	}))
+	defer receiver.Close()
+
+	run := Start(config.Webhook{URL: receiver.URL, Events: []string{config.EventRepoFailed}}, RunInfo{Action: "local"}, func(error) {})
+	run.RepoDone("payments-api", "", "", true, false, nil)
+	run.RepoDone("ledger-service", "", "", false, false, nil)
+	run.Finish()
+
+	if strings.Join(types, " ") != "repo.failed" {
+		t.Errorf("expected only the configured events, got %v", types)
+	}
+}
+
+func TestRunEventsUndelivered(t *testing.T) {
+	retryDelay = 0
+	attempts := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	var failures []error
+	run := Start(config.Webhook{URL: receiver.URL, Events: []string{config.EventRunStarted}}, RunInfo{Action: "local"}, func(err error) {
+		failures = append(failures, err)
+	})
+	run.Finish()
+
+	if attempts != maxAttempts {
+		t.Errorf("expected %d attempts, got %d", maxAttempts, attempts)
+	}
+	if len(failures) != 1 || !strings.Contains(failures[0].Error(), "down for maintenance") {
+		t.Errorf("expected the failure to be reported, got %v", failures)
+	}
+}
+
+func TestRunEventsReceiverHanging(t *testing.T) {
+	drainTimeout = 100 * time.Millisecond
+	release := make(chan struct{})
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer receiver.Close()
+	defer close(release)
+
+	var mu sync.Mutex
+	var failures []error
+	run := Start(config.Webhook{URL: receiver.URL}, RunInfo{Action: "local"}, func(err error) {
+		mu.Lock()
+		failures = append(failures, err)
+		mu.Unlock()
+	})
+
+	started := time.Now()
+	for i := 0; i < queueSize+10; i++ {
+		run.RepoDone(fmt.Sprintf("repo-%d", i), "", "", true, false, nil)
+	}
+	run.Finish()
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("expected a hanging receiver not to hold up the run, took %s", elapsed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var dropped, gaveUp bool
+	for _, err := range failures {
+		dropped = dropped || strings.Contains(err.Error(), "dropped a repo.succeeded webhook event")
+		gaveUp = gaveUp || strings.Contains(err.Error(), "gave up waiting")
+	}
+	if !dropped || !gaveUp {
+		t.Errorf("expected dropped events and the abandoned queue to be reported, got %v", failures)
+	}
+}
@@ -1,30 +1,63 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"maps"
+	"math/rand/v2"
 	"os"
-	"os/exec"
+	"slices"
 	"strings"
 	"sync"
+	"text/tabwriter"
+	"time"
 
+	"github.com/saltpay/copycat/v2/internal/actions"
 	"github.com/saltpay/copycat/v2/internal/ai"
+	"github.com/saltpay/copycat/v2/internal/alerts"
+	"github.com/saltpay/copycat/v2/internal/artifacts"
 	"github.com/saltpay/copycat/v2/internal/cmd"
 	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/example"
 	"github.com/saltpay/copycat/v2/internal/filesystem"
+	"github.com/saltpay/copycat/v2/internal/flaky"
 	"github.com/saltpay/copycat/v2/internal/git"
+	"github.com/saltpay/copycat/v2/internal/handoff"
+	"github.com/saltpay/copycat/v2/internal/healthcheck"
+	"github.com/saltpay/copycat/v2/internal/history"
+	"github.com/saltpay/copycat/v2/internal/hooks"
+	"github.com/saltpay/copycat/v2/internal/i18n"
 	"github.com/saltpay/copycat/v2/internal/input"
+	"github.com/saltpay/copycat/v2/internal/issuetemplate"
+	"github.com/saltpay/copycat/v2/internal/jira"
+	"github.com/saltpay/copycat/v2/internal/license"
 	"github.com/saltpay/copycat/v2/internal/permission"
+	"github.com/saltpay/copycat/v2/internal/policy"
+	"github.com/saltpay/copycat/v2/internal/report"
+	"github.com/saltpay/copycat/v2/internal/sarif"
 	"github.com/saltpay/copycat/v2/internal/slack"
+	"github.com/saltpay/copycat/v2/internal/throttle"
+	"github.com/saltpay/copycat/v2/internal/timeline"
+	"github.com/saltpay/copycat/v2/internal/util"
+	"github.com/saltpay/copycat/v2/internal/verify"
+	"github.com/saltpay/copycat/v2/internal/webhook"
 )
 
 const (
 	reposDir = "repos"
 )
 
+// gitHub and aiAgent do a run's GitHub and AI tool work. Tests swap in fakes.
+var (
+	gitHub  git.Provider = git.GH{}
+	aiAgent ai.Agent     = ai.CLI{}
+)
+
 // appConfig holds the loaded configuration (used for saving after sync).
 var appConfig *config.Config
 
@@ -36,17 +69,49 @@ var projectsPath string
 
 // ProcessJob represents a single project processing job
 type ProcessJob struct {
-	Ctx             context.Context
-	Project         config.Project
-	AITool          *config.AITool
-	AppConfig       config.Config
-	PRTitle         string
-	VibeCodePrompt  string
+	Ctx            context.Context
+	Project        config.Project
+	AITool         *config.AITool
+	AppConfig      config.Config
+	PRTitle        string
+	VibeCodePrompt string
+	// PromptContext is repo-specific context appended to the prompt (e.g. scanner findings)
+	PromptContext   string
 	BranchStrategy  string
 	SpecifiedBranch string
+	AutoMerge       bool              // enable auto-merge on the PR, unless it is a draft
+	UpdatePR        bool              // replace the title and description of a PR already open from the branch
+	PRMetadata      config.PRMetadata // labels and milestone put on the PR
 	MCPConfigPath   string
-	IgnoreFiles     []string
-	UpdateStatus    func(status string)
+	// TempChanges are undone before committing, so they only affect the AI step
+	TempChanges    []config.TempChange
+	ProtectedPaths []string
+	// Hooks run in the repo before and after the AI step; failing ones fail it
+	Hooks config.Hooks
+	// AllowLineEndingChanges skips reverting line-ending-only churn
+	AllowLineEndingChanges bool
+	ReviewTool             *config.AITool
+	TrivialChanges         config.TrivialChangesConfig
+	// LicenseHeader replaces the AI step with adding license headers when set
+	LicenseHeader *config.LicenseHeaderConfig
+	// Actions are deterministic edits made before the AI tool runs; with
+	// ActionsOnly set (the run has no prompt) they replace it
+	Actions     *config.ActionPipeline
+	ActionsOnly bool
+	// TriageAlerts feeds the repo's open security alerts to the AI
+	TriageAlerts bool
+	Verifiers    []verify.Verifier // built-in checks run before pushing
+	// VerifyCommand must pass on the changes; VerifyFailure says whether a
+	// failure blocks the PR or opens it as a draft
+	VerifyCommand string
+	VerifyFailure string
+	DependsOn     []string // selected repos that must finish first
+	Clones        *sharedClones
+	PRPacer       *prPacer // shared by the run's jobs
+	UpdateStatus  func(status string)
+	LogLine       func(line string) // receives the AI tool's output as it runs
+	// RequestReviewDecision asks the user whether to push changes the review tool rejected.
+	RequestReviewDecision func(reasons string) bool
 }
 
 // ProcessResult represents the result of processing a single project
@@ -57,6 +122,20 @@ type ProcessResult struct {
 	Error    error
 	PRURL    string
 	AIOutput string
+	Diff     string
+	Warnings []string
+	// UpdatedPR is set when the changes went onto a PR already open from the branch
+	UpdatedPR bool
+	// PRDescription is passed to downstream repos that depend on this one
+	PRDescription string
+	NoChanges     bool              // skipped because the AI made no changes
+	Outputs       map[string]string // values captured for downstream prompts
+	Alerts        []alerts.Outcome  // per-alert outcomes of an alert triage run
+	Tool          string            // AI tool that made the changes, after any fallbacks
+	Usage         ai.Usage          // tokens and cost reported by the AI tool runs
+	GitLog        string            // git commands run and their output
+	GitHubLog     string            // GitHub API requests made and their responses
+	Timeline      []timeline.Phase  // how long each phase of the pipeline took
 }
 
 func main() {
@@ -81,16 +160,62 @@ func main() {
 				log.Fatal(err)
 			}
 			return
+		case "slack":
+			if len(os.Args) < 3 {
+				log.Fatal("Usage: copycat slack <connect|disconnect|status>")
+			}
+			if err := cmd.RunSlack(os.Args[2]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "demo":
+			if err := cmd.RunDemo(); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "campaign":
+			if err := cmd.RunCampaign(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "health-check":
+			if err := runHealthChecks(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "issues":
+			if err := runIssues(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "git-credential":
+			// Run by git for HTTPS clones, see github.clone_protocol
+			if len(os.Args) < 3 {
+				log.Fatal("Usage: copycat git-credential <get|store|erase>")
+			}
+			if err := git.RunCredentialHelper(context.Background(), os.Args[2], os.Stdin, os.Stdout); err != nil {
+				log.Fatal(err)
+			}
+			return
 		case "permission-handler":
 			if err := permission.RunMCPHandler(); err != nil {
 				log.Fatal(err)
 			}
 			return
+		case "permission-hook":
+			// Run by tools with the hook permission adapter before each tool call
+			os.Exit(permission.RunPermissionHook(os.Stdin, os.Stdout, os.Stderr))
 		}
 	}
 
 	// Parse command-line flags
 	parallelism := flag.Int("parallel", 0, "number of repositories to process in parallel (overrides config.yaml)")
+	sarifPath := flag.String("sarif", "", "SARIF report (e.g. from CodeQL or Snyk) whose findings each repo should fix")
+	flakyPath := flag.String("flaky", "", "flaky test results (saved by \"Detect Flaky Tests\") whose tests each repo should fix")
+	resumePath := flag.String("resume", "", "handoff bundle of a paused run to resume (exported with s at a checkpoint)")
+	examplePR := flag.String("example", "", "URL of a PR where the change was made by hand once; its diff is given to the AI as the example to follow")
+	campaignID := flag.String("campaign", "", "campaign ID to tag the run with; 'copycat campaign report <id>' rolls up every run tagged with it")
+	manifestPath := flag.String("manifest", "", "file listing the repos of a run, one per line, or a YAML file with repos and context; needed for runs above run_limits.max_repos")
 	flag.Parse()
 
 	filesystem.DeleteWorkspace()
@@ -119,6 +244,7 @@ func main() {
 			log.Fatal("Failed to load configuration:", err)
 		}
 	}
+	applySettings(appConfig)
 
 	// Load projects from separate file, or fetch if empty/missing
 	projects, projectsErr := config.LoadProjects(projectsPath)
@@ -129,6 +255,11 @@ func main() {
 			log.Fatal("Failed to fetch projects:", err)
 		}
 	}
+	// Repos excluded since projects.yaml was written never reach the selector
+	projects = appConfig.GitHub.WithoutExcluded(projects)
+
+	// Monorepo services are selected and notified one by one
+	projects = config.ExpandServices(projects)
 
 	// CLI flag overrides config value
 	if *parallelism > 0 {
@@ -139,23 +270,121 @@ func main() {
 	}
 	par := appConfig.Parallelism
 
+	// A SARIF report narrows the selection to repos with findings and gives
+	// each one its findings as prompt context
+	var promptContext map[string]string
+	if *sarifPath != "" {
+		projects, promptContext, err = loadSARIF(*sarifPath, projects)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// So do the results of a flaky test detection run
+	if *flakyPath != "" {
+		var flakyContext map[string]string
+		projects, flakyContext, err = loadFlakyTests(*flakyPath, projects)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if promptContext == nil {
+			promptContext = make(map[string]string)
+		}
+		for repo, context := range flakyContext {
+			promptContext[repo] += context
+		}
+	}
+
+	// A manifest narrows the selection to the repos it lists and lets them
+	// run past max_repos. Its context goes to every repo it lists.
+	var manifest config.Manifest
+	if *manifestPath != "" {
+		projects, manifest, err = loadManifest(*manifestPath, projects)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if note := manifest.PromptContext(); note != "" {
+			if promptContext == nil {
+				promptContext = make(map[string]string)
+			}
+			for _, project := range projects {
+				promptContext[project.Repo] += note
+			}
+		}
+	}
+
+	// A reference PR shows the AI how the change was made once by hand
+	if *examplePR != "" {
+		var exampleContext string
+		projects, exampleContext, err = loadExample(*examplePR, projects)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if promptContext == nil {
+			promptContext = make(map[string]string)
+		}
+		for _, project := range projects {
+			promptContext[project.Repo] += exampleContext
+		}
+	}
+
+	// A handoff bundle picks up another operator's paused run
+	var resumed *input.ResumedRun
+	if *resumePath != "" {
+		resumed, err = loadHandoff(*resumePath, projects)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if *campaignID != "" {
+			resumed.Wizard.CampaignID = *campaignID
+		}
+	}
+
 	dashCfg := input.DashboardConfig{
 		Projects:      projects,
 		AIToolsConfig: &appConfig.AIToolsConfig,
 		GitHubConfig:  appConfig.GitHub,
 		AppConfig:     *appConfig,
 		Parallelism:   par,
-		FetchProjects: func() ([]config.Project, error) {
-			return fetchAndSyncProjects(appConfig.GitHub)
+		FetchProjects: func(discovery config.DiscoveryFilter) ([]config.Project, error) {
+			githubCfg := appConfig.GitHub
+			githubCfg.Discovery = discovery
+			projects, err := fetchAndSyncProjects(githubCfg)
+			return config.ExpandServices(projects), err
 		},
 		ProcessRepos: func(sender *input.StatusSender, selectedProjects []config.Project, setup *input.WizardResult) {
-			processReposWithSender(sender, selectedProjects, setup, *appConfig, par)
+			processReposWithSender(sender, selectedProjects, setup, *appConfig, par, promptContext)
 		},
 		AssessRepos: func(sender *input.StatusSender, selectedProjects []config.Project, setup *input.WizardResult) {
 			assessReposWithSender(sender, selectedProjects, setup, *appConfig, par)
 		},
-		SendSlackNotifications:      slack.SendNotifications,
-		SendSlackAssessmentFindings: slack.SendAssessmentFindings,
+		CreateIssues: func(sender *input.StatusSender, selectedProjects []config.Project, setup *input.WizardResult) {
+			createIssuesWithSender(sender, selectedProjects, setup, *appConfig, par)
+		},
+		IssueTemplates: issueTemplatesFor(appConfig.GitHub.Organization),
+		SlackToken:     slack.ResolveToken(),
+		SendSlackNotifications: func(projects []config.Project, prTitle, prompt string, prURLs, ciStatus map[string]string, token string, onStatus func(string)) {
+			slack.SendNotifications(projects, prTitle, prompt, prURLs, ciStatus, token, appConfig.Slack, onStatus)
+		},
+		SendSlackAssessmentFindings: func(projects []config.Project, question string, findings map[string]string, token string, onStatus func(string)) {
+			slack.SendAssessmentFindings(projects, question, findings, token, appConfig.Slack, onStatus)
+		},
+		SendSlackIssueNotifications: func(projects []config.Project, issueTitle, prompt string, issueURLs map[string]string, token string, onStatus func(string)) {
+			slack.SendIssueNotifications(projects, issueTitle, prompt, issueURLs, token, appConfig.Slack, onStatus)
+		},
+		CheckSlackChannels:   slack.CheckChannels,
+		CreateJiraTickets:    createJiraTicketsFor(appConfig.Jira),
+		WatchChecks:          watchChecksFor(appConfig.CIChecks),
+		StartRemoteApprovals: remoteApprovalsFor(appConfig.Slack.Approvals),
+		CampaignProgress:     campaignProgress,
+		SaveProfile:          saveProfile,
+		PromptHistory:        promptHistory,
+		AssessmentHistory:    assessmentHistory,
+		ExportRun:            exportHandoff,
+		Resume:               resumed,
+		CampaignID:           *campaignID,
+		Manifest:             manifest.Repos,
+		ContextFiles:         manifest.Files(),
 	}
 
 	result, err := input.RunDashboard(dashCfg)
@@ -173,9 +402,125 @@ func main() {
 		filesystem.DeleteEmptyWorkspace()
 	}
 
+	if len(result.ProcessResults) > 0 {
+		if path, err := writeRunReport(result); err != nil {
+			fmt.Printf("⚠ Failed to write report: %v\n", err)
+		} else {
+			fmt.Printf("✓ Report written to %s\n", path)
+		}
+	}
+
+	if result.HandoffPath != "" {
+		fmt.Printf("✓ Run exported to %s\n  Resume it with: copycat -resume %s\n", result.HandoffPath, result.HandoffPath)
+	}
+
 	fmt.Println("\nDone!")
 }
 
+// exportHandoff saves a paused run's bundle in the handoffs directory.
+func exportHandoff(bundle handoff.Bundle) (string, error) {
+	dir, err := config.HandoffsDir()
+	if err != nil {
+		return "", err
+	}
+	return handoff.Save(dir, bundle)
+}
+
+// loadHandoff reads a handoff bundle and matches its pending repos with
+// projects, warning about the ones that are no longer there.
+func loadHandoff(path string, projects []config.Project) (*input.ResumedRun, error) {
+	bundle, err := handoff.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Organization != appConfig.GitHub.Organization {
+		return nil, fmt.Errorf("the handoff bundle is for organization %q, but copycat is configured for %q", bundle.Organization, appConfig.GitHub.Organization)
+	}
+	resumed, missing, err := input.NewResumedRun(bundle, projects, &appConfig.AIToolsConfig)
+	for _, key := range missing {
+		fmt.Printf("⚠ %s is pending in the handoff bundle but not in projects.yaml; skipping it\n", key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	name := bundle.Wizard.PRTitle
+	if name == "" {
+		name = bundle.Wizard.Action
+	}
+	fmt.Printf("Resuming %q exported by %s on %s: %d done, %d to go\n",
+		name, bundle.ExportedBy, bundle.ExportedAt.Local().Format("Jan 2 15:04"), len(bundle.Completed), len(resumed.Projects))
+	return resumed, nil
+}
+
+// saveProfile stores a named project selection in config.yaml.
+// The file is reloaded first so CLI overrides applied to appConfig are not persisted.
+func saveProfile(name string, repos []string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string][]string)
+	}
+	cfg.Profiles[name] = repos
+	return cfg.Save(configPath)
+}
+
+// writeRunReport renders the dashboard results as an HTML report in the reports directory.
+func writeRunReport(result *input.DashboardResult) (string, error) {
+	dir, err := config.ReportsDir()
+	if err != nil {
+		return "", err
+	}
+
+	run := report.Run{
+		Action:  result.Action,
+		Summary: result.AssessmentSummary,
+	}
+	if result.WizardResult != nil {
+		run.Prompt = result.WizardResult.Prompt
+		run.Title = result.WizardResult.PRTitle
+	}
+	run.Campaign = result.Campaign
+	if run.Campaign == nil && result.Action == "local" && run.Title != "" {
+		if campaign, err := campaignProgress(run.Title); err == nil && len(campaign.Points) > 0 {
+			run.Campaign = &campaign
+		}
+	}
+	if run.Title == "" {
+		run.Title = i18n.T("Copycat assessment")
+	}
+
+	for _, project := range result.SelectedProjects {
+		repo := report.RepoResult{
+			Repo:    project.Key(),
+			Outcome: report.OutcomeCancelled,
+			Status:  "Not processed",
+			Finding: result.AssessmentFindings[project.Key()],
+		}
+		if done, ok := result.ProcessResults[project.Key()]; ok {
+			repo.Status = done.Status
+			repo.PRURL = done.PRURL
+			repo.Diff = done.Diff
+			repo.Timeline = done.Timeline
+			switch {
+			case done.Skipped:
+				repo.Outcome = report.OutcomeSkipped
+			case done.Success:
+				repo.Outcome = report.OutcomeSucceeded
+			default:
+				repo.Outcome = report.OutcomeFailed
+				if done.Error != nil {
+					repo.Status = done.Error.Error()
+				}
+			}
+		}
+		run.Repos = append(run.Repos, repo)
+	}
+
+	return report.Write(dir, run)
+}
+
 func handleFirstRun(configPath string) (*config.Config, error) {
 	fmt.Println("Welcome to Copycat!")
 	fmt.Printf("Configuration now follows XDG structure: %s\n", configPath)
@@ -249,7 +594,7 @@ func fetchAndSyncProjects(githubCfg config.GitHubConfig) ([]config.Project, erro
 		fmt.Printf("\nFetching all repositories from %s...\n", githubCfg.Organization)
 	}
 
-	fetchedProjects, err := git.FetchRepositories(githubCfg)
+	fetchedProjects, err := gitHub.FetchRepositories(githubCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -276,60 +621,253 @@ func fetchAndSyncProjects(githubCfg config.GitHubConfig) ([]config.Project, erro
 	return mergedProjects, nil
 }
 
-// mergeProjects merges fetched projects with existing ones, preserving manual edits.
+// mergeProjects merges fetched projects with existing ones, preserving manual
+// edits. A repo with several entries, one per monorepo path, keeps them all.
 func mergeProjects(existing, fetched []config.Project) []config.Project {
 	// Build a map of existing projects by repo name
-	existingMap := make(map[string]config.Project)
+	existingMap := make(map[string][]config.Project)
 	for _, p := range existing {
-		existingMap[p.Repo] = p
+		existingMap[p.Repo] = append(existingMap[p.Repo], p)
 	}
 
 	// Merge: use fetched data but preserve slack_room from existing
 	merged := make([]config.Project, 0, len(fetched))
-	for _, fp := range fetched {
-		if ep, ok := existingMap[fp.Repo]; ok {
+	for _, fetchedProject := range fetched {
+		entries := existingMap[fetchedProject.Repo]
+		if len(entries) == 0 {
+			merged = append(merged, fetchedProject)
+			continue
+		}
+		for _, ep := range entries {
+			fp := fetchedProject
 			// Preserve slack_room if it was set manually
 			if fp.SlackRoom == "" && ep.SlackRoom != "" {
 				fp.SlackRoom = ep.SlackRoom
 			}
+			// Teams, reviewers, dependencies, outputs, target branches, prompt notes, services, hooks, verify commands, push URLs and paths are only declared locally
+			fp.Team = ep.Team
+			fp.Reviewers = ep.Reviewers
+			fp.DependsOn = ep.DependsOn
+			fp.Outputs = ep.Outputs
+			fp.TargetBranches = ep.TargetBranches
+			fp.PromptNotes = ep.PromptNotes
+			fp.Services = ep.Services
+			fp.Hooks = ep.Hooks
+			fp.VerifyCommand = ep.VerifyCommand
+			fp.PushURL = ep.PushURL
+			fp.Path = ep.Path
+			merged = append(merged, fp)
 		}
-		merged = append(merged, fp)
 	}
 
 	return merged
 }
 
+// sharedClones lets the jobs for every target branch or service of a repo
+// share a single clone, each working in its own worktree.
+type sharedClones struct {
+	mu    sync.Mutex
+	repos map[string]*sharedClone
+}
+
+type sharedClone struct {
+	mu      sync.Mutex // serializes clone and worktree commands on the repo
+	path    string
+	pending int  // jobs that have not released the clone yet
+	partial bool // every job is scoped to a path, so blobs are fetched on demand
+}
+
+func newSharedClones(projects []config.Project) *sharedClones {
+	c := &sharedClones{repos: make(map[string]*sharedClone)}
+	for _, p := range projects {
+		if !sharesClone(p) {
+			continue
+		}
+		if c.repos[p.Repo] == nil {
+			c.repos[p.Repo] = &sharedClone{partial: true}
+		}
+		c.repos[p.Repo].pending++
+		c.repos[p.Repo].partial = c.repos[p.Repo].partial && sparsePath(p) != ""
+	}
+	return c
+}
+
+func (c *sharedClones) get(repo string) *sharedClone {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.repos[repo]
+}
+
+// AddWorktree clones the repo into clonePath unless already cloned, then adds
+// a worktree at worktreePath checked out at branch, holding only sparsePath
+// when set.
+func (c *sharedClones) AddWorktree(ctx context.Context, repo, repoURL, clonePath, worktreePath, branch, sparsePath string) error {
+	clone := c.get(repo)
+	clone.mu.Lock()
+	defer clone.mu.Unlock()
+
+	if clone.path == "" {
+		if _, err := os.Stat(clonePath); os.IsNotExist(err) {
+			args := []string{"--no-checkout"}
+			if clone.partial {
+				// Only fetch the files the sparse worktrees check out
+				args = append(args, "--filter=blob:none")
+			}
+			if err := gitHub.Clone(ctx, repoURL, clonePath, args...); err != nil {
+				filesystem.DeleteDirectory(clonePath)
+				return err
+			}
+		}
+		clone.path = clonePath
+	}
+	return git.AddWorktree(ctx, clonePath, worktreePath, branch, sparsePath)
+}
+
+// Release marks one job for repo as finished and deletes the shared clone once
+// every job targeting the repo is done.
+func (c *sharedClones) Release(repo string) {
+	clone := c.get(repo)
+	clone.mu.Lock()
+	defer clone.mu.Unlock()
+
+	clone.pending--
+	if clone.pending == 0 && clone.path != "" {
+		filesystem.DeleteDirectory(clone.path)
+		clone.path = ""
+	}
+}
+
+// sharesClone reports whether a job works in a worktree of a clone shared with
+// the repo's other jobs, one per target branch or monorepo service.
+func sharesClone(p config.Project) bool {
+	return p.TargetBranch != "" || p.Service != ""
+}
+
+// sparsePath returns the directory a job's worktree is limited to, or "" when
+// the job needs the whole repo.
+func sparsePath(p config.Project) string {
+	dir := strings.Trim(p.Path, "/")
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// workDir returns the directory a job works in. Jobs that share a clone, and
+// services assessed side by side, each get their own next to the repo's.
+func workDir(p config.Project) string {
+	var parts []string
+	if p.Service != "" {
+		parts = append(parts, p.Service)
+	}
+	if p.TargetBranch != "" {
+		parts = append(parts, p.TargetBranch)
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("%s/%s", reposDir, p.Repo)
+	}
+	return fmt.Sprintf("%s/%s@%s", reposDir, p.Repo, util.CreateSlugFromTitle(strings.Join(parts, " ")))
+}
+
 // errCancelled is a sentinel error for cancelled projects.
 var errCancelled = fmt.Errorf("cancelled")
 
 // processProject handles the processing of a single project
-func processProject(job ProcessJob) ProcessResult {
-	ctx := job.Ctx
+func processProject(job ProcessJob) (result ProcessResult) {
+	// git and GitHub output is kept apart from the AI's for the log viewer
+	transcript := &git.Transcript{}
+	ctx := git.WithTranscript(job.Ctx, transcript)
+	defer func() {
+		result.GitLog, result.GitHubLog = transcript.Git(), transcript.API()
+	}()
+	phases := timeline.NewRecorder()
+	defer func() { result.Timeline = phases.Stop() }()
 	project := job.Project
 	targetPath := fmt.Sprintf("%s/%s", reposDir, project.Repo)
+	repoURL := job.AppConfig.GitHub.CloneURL(project.Repo)
 
 	cleanup := func() {
 		filesystem.DeleteDirectory(targetPath)
 	}
 
+	// Jobs targeting a base branch or a service work in a worktree of a clone
+	// shared by the repo's jobs; the clone itself is released by the worker
+	clonePath := targetPath
+	if sharesClone(project) {
+		targetPath = workDir(project)
+		cleanup = func() {
+			if err := git.RemoveWorktree(context.Background(), clonePath, targetPath); err != nil {
+				filesystem.DeleteDirectory(targetPath)
+			}
+		}
+	}
+
 	// Check for cancellation before each major step
 	if ctx.Err() != nil {
 		return ProcessResult{Project: project, Success: false, Error: errCancelled}
 	}
 
+	// Alert triage gives the AI the repo's open security alerts
+	var warnings []string
+	var openAlerts []git.Alert
+	if job.TriageAlerts {
+		job.UpdateStatus("Fetching security alerts...")
+		phases.Start("alerts")
+		found, alertWarnings, err := gitHub.FetchOpenAlerts(ctx, job.AppConfig.GitHub.Organization, project.Repo)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: err}
+		}
+		warnings = append(warnings, alertWarnings...)
+		if len(found) == 0 {
+			return ProcessResult{Project: project, Skipped: true, Error: errors.New("no open security alerts"), Warnings: warnings}
+		}
+		openAlerts = found
+		job.VibeCodePrompt += alerts.Prompt(found)
+	}
+
 	// Clone the repository if it doesn't exist
 	job.UpdateStatus("Cloning...")
-	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
-		repoURL := fmt.Sprintf("git@github.com:%s/%s.git", job.AppConfig.GitHub.Organization, project.Repo)
-		cmd := exec.CommandContext(ctx, "git", "clone", repoURL, targetPath)
-		output, err := cmd.CombinedOutput()
-		if err != nil {
+	phases.Start("clone")
+	if sharesClone(project) {
+		baseBranch := project.TargetBranch
+		if baseBranch == "" {
+			baseBranch = "HEAD" // the default branch
+		}
+		if err := job.Clones.AddWorktree(ctx, project.Repo, repoURL, clonePath, targetPath, baseBranch, sparsePath(project)); err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: err}
+		}
+	} else if _, err := os.Stat(targetPath); os.IsNotExist(err) {
+		if err := gitHub.Clone(ctx, repoURL, targetPath); err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: err}
+		}
+	}
+
+	// Brand new repos have no branch to start from
+	if err := git.CheckHead(ctx, targetPath); err != nil {
+		if !errors.Is(err, git.ErrEmptyRepo) || job.AppConfig.EmptyRepos != config.EmptyReposScaffold {
+			cleanup()
+			return ProcessResult{Project: project, Skipped: true, Error: err, Warnings: warnings}
+		}
+		job.UpdateStatus("Creating initial commit...")
+		if err := gitHub.InitializeRepo(ctx, project, targetPath, job.AppConfig.CommitSigning); err != nil {
 			cleanup()
 			if ctx.Err() != nil {
 				return ProcessResult{Project: project, Success: false, Error: errCancelled}
 			}
-			return ProcessResult{Project: project, Success: false, Error: fmt.Errorf("clone failed: %v (%s)", err, string(output))}
+			return ProcessResult{Project: project, Success: false, Error: err}
 		}
+		warnings = append(warnings, "repo had no commits, so a README was pushed as its initial commit")
 	}
 
 	if ctx.Err() != nil {
@@ -339,7 +877,18 @@ func processProject(job ProcessJob) ProcessResult {
 
 	// Select or create branch based on strategy
 	job.UpdateStatus("Creating branch...")
-	branchName, err := git.SelectOrCreateBranch(ctx, targetPath, job.PRTitle, job.BranchStrategy, job.SpecifiedBranch)
+	phases.Start("branch")
+	branchTitle, specifiedBranch := job.PRTitle, job.SpecifiedBranch
+	// Keep head branches distinct across the services and target branches of one repo
+	if project.Service != "" {
+		branchTitle += " " + project.Service
+		specifiedBranch += "-" + util.CreateSlugFromTitle(project.Service)
+	}
+	if project.TargetBranch != "" {
+		branchTitle += " " + project.TargetBranch
+		specifiedBranch += "-" + util.CreateSlugFromTitle(project.TargetBranch)
+	}
+	branchName, err := git.SelectOrCreateBranch(ctx, targetPath, branchTitle, job.BranchStrategy, specifiedBranch)
 	if err != nil {
 		cleanup()
 		if ctx.Err() != nil {
@@ -353,44 +902,82 @@ func processProject(job ProcessJob) ProcessResult {
 		return ProcessResult{Project: project, Success: false, Error: errCancelled}
 	}
 
-	// Remove agent instruction files before running AI tool
-	var removedFiles []ai.RemovedFile
-	if len(job.IgnoreFiles) > 0 {
-		removedFiles = ai.RemoveInstructionFiles(ctx, targetPath, job.IgnoreFiles)
-	}
-
-	// Run AI tool
-	job.UpdateStatus("Running AI agent...")
-	aiOutput, err := ai.VibeCode(ctx, job.AITool, job.VibeCodePrompt, targetPath, job.MCPConfigPath, project.Repo)
-	if err != nil {
-		cleanup()
-		if ctx.Err() != nil {
-			return ProcessResult{Project: project, Success: false, Error: errCancelled}
+	// Make the run's temporary file changes before running AI tool
+	var tempBackups []ai.TempBackup
+	if len(job.TempChanges) > 0 {
+		tempBackups, err = ai.ApplyTempChanges(ctx, targetPath, job.TempChanges)
+		if err != nil {
+			ai.RestoreTempChanges(ctx, targetPath, tempBackups)
+			cleanup()
+			return ProcessResult{Project: project, Success: false, Error: fmt.Errorf("temporary file changes failed: %v", err)}
 		}
-		return ProcessResult{Project: project, Success: false, Error: fmt.Errorf("AI tool failed: %v\n%s", err, lastLines(aiOutput, 5)), AIOutput: aiOutput}
 	}
 
-	if ctx.Err() != nil {
-		cleanup()
-		return ProcessResult{Project: project, Success: false, Error: errCancelled}
+	if job.Hooks.Pre != "" {
+		job.UpdateStatus("Running pre hook...")
+		phases.Start("pre hook")
+		if err := hooks.Run(ctx, targetPath, "pre hook", job.Hooks.Pre, job.LogLine); err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: err}
+		}
 	}
 
-	// Restore agent instruction files before committing
-	if len(removedFiles) > 0 {
-		if restoreErr := ai.RestoreInstructionFiles(ctx, targetPath, removedFiles); restoreErr != nil {
-			log.Printf("⚠️ Failed to restore instruction files for %s: %v", project.Repo, restoreErr)
+	var actionLines []string
+	if job.Actions != nil {
+		job.UpdateStatus(fmt.Sprintf("Running %s actions...", job.Actions.Name))
+		phases.Start("actions")
+		actionLines, err = actions.Apply(ctx, targetPath, job.Actions.Steps)
+		if err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: fmt.Errorf("actions failed: %v", err)}
+		}
+		if !job.ActionsOnly {
+			job.VibeCodePrompt += actions.PromptNote(actionLines)
 		}
 	}
 
-	// Generate PR description
-	job.UpdateStatus("Generating PR description...")
-	prDescription, err := ai.GeneratePRDescription(ctx, job.AITool, project, aiOutput, targetPath)
-	if err != nil {
-		cleanup()
-		if ctx.Err() != nil {
-			return ProcessResult{Project: project, Success: false, Error: errCancelled}
+	var aiOutput, toolName string
+	if job.ActionsOnly {
+		aiOutput = actions.Summary(job.Actions.Name, actionLines)
+	} else if job.LicenseHeader != nil {
+		// Deterministic action: no AI tool involved
+		job.UpdateStatus("Adding license headers...")
+		phases.Start("license headers")
+		updated, err := license.Apply(ctx, targetPath, *job.LicenseHeader)
+		if err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: err}
 		}
-		return ProcessResult{Project: project, Success: false, Error: err}
+		aiOutput = licenseHeaderSummary(updated)
+	} else {
+		// Run AI tool, falling back to the next configured tool on failure
+		var run toolRun
+		phases.Start("AI")
+		run, err = runToolChain(ctx, job, targetPath, tempBackups)
+		aiOutput, job.AITool = run.Output, run.Tool
+		warnings = append(warnings, run.Warnings...)
+		// Every return from here on reports what the tool runs cost
+		defer func() { result.Usage = run.Usage }()
+		if err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			if errors.Is(err, ai.ErrTimeout) {
+				return ProcessResult{Project: project, Success: false, Error: fmt.Errorf("AI tool %w", err), AIOutput: aiOutput, Warnings: warnings}
+			}
+			return ProcessResult{Project: project, Success: false, Error: fmt.Errorf("AI tool failed: %v\n%s", err, lastLines(aiOutput, 5)), AIOutput: aiOutput, Warnings: warnings}
+		}
+		toolName = job.AITool.Name
 	}
 
 	if ctx.Err() != nil {
@@ -398,169 +985,1538 @@ func processProject(job ProcessJob) ProcessResult {
 		return ProcessResult{Project: project, Success: false, Error: errCancelled}
 	}
 
-	// Check if there are changes to commit
-	job.UpdateStatus("Checking for changes...")
-	output, err := git.CheckLocalChanges(ctx, targetPath)
-	if err != nil {
-		cleanup()
-		if ctx.Err() != nil {
-			return ProcessResult{Project: project, Success: false, Error: errCancelled}
+	// Undo temporary file changes before committing
+	if len(tempBackups) > 0 {
+		if restoreErr := ai.RestoreTempChanges(ctx, targetPath, tempBackups); restoreErr != nil {
+			log.Printf("⚠️ Failed to undo temporary file changes for %s: %v", project.Repo, restoreErr)
 		}
-		return ProcessResult{Project: project, Success: false, Error: err}
-	}
-	if len(output) == 0 {
-		cleanup()
-		return ProcessResult{Project: project, Skipped: true, Error: fmt.Errorf("no changes detected\n%s", lastLines(aiOutput, 5)), AIOutput: aiOutput}
 	}
 
-	if ctx.Err() != nil {
-		cleanup()
-		return ProcessResult{Project: project, Success: false, Error: errCancelled}
-	}
-
-	// Push changes
-	job.UpdateStatus("Pushing changes...")
-	err = git.PushChanges(ctx, project, targetPath, branchName, job.PRTitle)
-	if err != nil {
-		cleanup()
-		if ctx.Err() != nil {
-			return ProcessResult{Project: project, Success: false, Error: errCancelled}
+	if job.Hooks.Post != "" {
+		job.UpdateStatus("Running post hook...")
+		phases.Start("post hook")
+		if err := hooks.Run(ctx, targetPath, "post hook", job.Hooks.Post, job.LogLine); err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: err, AIOutput: aiOutput, Warnings: warnings}
 		}
-		return ProcessResult{Project: project, Success: false, Error: err}
 	}
 
-	if ctx.Err() != nil {
-		cleanup()
-		return ProcessResult{Project: project, Success: false, Error: errCancelled}
+	var alertOutcomes []alerts.Outcome
+	if job.TriageAlerts {
+		alertOutcomes = alerts.ParseOutcomes(aiOutput, openAlerts)
 	}
 
-	// Create pull request
-	job.UpdateStatus("Creating PR...")
-	prOutput, err := git.CreatePullRequest(ctx, project, targetPath, branchName, job.PRTitle, prDescription)
-	if err != nil {
-		cleanup()
-		if ctx.Err() != nil {
-			return ProcessResult{Project: project, Success: false, Error: errCancelled}
+	// Discard any AI edits to protected paths
+	if len(job.ProtectedPaths) > 0 {
+		job.UpdateStatus("Reverting protected paths...")
+		reverted, err := git.RevertProtectedPaths(ctx, targetPath, job.ProtectedPaths)
+		if err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: err, AIOutput: aiOutput}
+		}
+		if len(reverted) > 0 {
+			warnings = append(warnings, fmt.Sprintf("reverted protected paths: %s", strings.Join(reverted, ", ")))
 		}
-		return ProcessResult{Project: project, Success: false, Error: fmt.Errorf("PR creation failed: %v (%s)", err, string(prOutput))}
 	}
 
-	prURL := strings.TrimSpace(string(prOutput))
+	// Monorepo jobs only change their own subdirectory
+	if project.Path != "" {
+		job.UpdateStatus("Reverting changes outside " + project.Path + "...")
+		reverted, err := git.RevertOutsidePath(ctx, targetPath, project.Path)
+		if err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: err, AIOutput: aiOutput}
+		}
+		if len(reverted) > 0 {
+			warnings = append(warnings, fmt.Sprintf("reverted changes outside %s/: %s", strings.Trim(project.Path, "/"), strings.Join(reverted, ", ")))
+		}
+	}
 
-	// Clean up
-	job.UpdateStatus("Cleaning up...")
-	cleanup()
+	// Drop line ending churn so the diff only shows real edits
+	if !job.AllowLineEndingChanges {
+		job.UpdateStatus("Checking line endings...")
+		restored, converted, err := git.RestoreLineEndings(ctx, targetPath)
+		if err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: err, AIOutput: aiOutput}
+		}
+		if len(restored) > 0 {
+			warnings = append(warnings, fmt.Sprintf("reverted line-ending-only changes: %s", strings.Join(restored, ", ")))
+		}
+		if len(converted) > 0 {
+			warnings = append(warnings, fmt.Sprintf("restored original line endings: %s", strings.Join(converted, ", ")))
+		}
+	}
+
+	// Capture values that later repos in the run can use in their prompts
+	var outputs map[string]string
+	if len(project.Outputs) > 0 || strings.Contains(aiOutput, "COPYCAT_OUTPUT") {
+		job.UpdateStatus("Capturing outputs...")
+		outputs, err = artifacts.Capture(ctx, targetPath, project.Outputs, aiOutput)
+		if err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: err, AIOutput: aiOutput}
+		}
+	}
+
+	// Generate PR description
+	prDescription := aiOutput
+	if toolName != "" {
+		job.UpdateStatus("Generating PR description...")
+		phases.Start("describe")
+		prDescription, err = aiAgent.GeneratePRDescription(ctx, job.AITool, project, aiOutput, targetPath)
+		if err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: err}
+		}
+		if job.Actions != nil {
+			prDescription += "\n\n" + actions.Summary(job.Actions.Name, actionLines)
+		}
+	}
+	if dir := strings.Trim(project.Path, "/"); dir != "" {
+		prDescription = fmt.Sprintf("Scoped to `%s/` of this monorepo.\n\n", dir) + prDescription
+	}
+
+	if ctx.Err() != nil {
+		cleanup()
+		return ProcessResult{Project: project, Success: false, Error: errCancelled}
+	}
+
+	// Check if there are changes to commit
+	job.UpdateStatus("Checking for changes...")
+	phases.Start("verify")
+	output, err := git.CheckLocalChanges(ctx, targetPath)
+	if err != nil {
+		cleanup()
+		if ctx.Err() != nil {
+			return ProcessResult{Project: project, Success: false, Error: errCancelled}
+		}
+		return ProcessResult{Project: project, Success: false, Error: err}
+	}
+	if len(output) == 0 {
+		cleanup()
+		dismissAlerts(ctx, job, alertOutcomes)
+		return ProcessResult{Project: project, Skipped: true, NoChanges: true, Error: fmt.Errorf("no changes detected\n%s", lastLines(aiOutput, 5)), AIOutput: aiOutput, Warnings: warnings, Outputs: outputs, Alerts: alertOutcomes, Tool: toolName}
+	}
+
+	if ctx.Err() != nil {
+		cleanup()
+		return ProcessResult{Project: project, Success: false, Error: errCancelled}
+	}
+
+	// Capture the diff for the run report (best effort)
+	diff, _ := git.CaptureDiff(ctx, targetPath)
+
+	// Whitespace- or comment-only diffs rarely justify a PR
+	if job.TrivialChanges.Action != config.TrivialChangesAllow {
+		if kind := util.TrivialDiff(diff, job.TrivialChanges.CommentPrefixes); kind != "" {
+			if job.TrivialChanges.Action == config.TrivialChangesSkip {
+				cleanup()
+				return ProcessResult{Project: project, Skipped: true, Error: fmt.Errorf("only %s changed", kind), AIOutput: aiOutput, Diff: diff, Warnings: warnings}
+			}
+			warnings = append(warnings, fmt.Sprintf("diff only changes %s", kind))
+		}
+	}
+
+	// Built-in verifications stop changes that would break contracts, then the
+	// repo's own checks, e.g. its test suite, must pass on the changes. They
+	// run again if the changes are rebased before the push.
+	prOptions := git.PullRequestOptions{Labels: job.PRMetadata.Labels, Milestone: job.PRMetadata.Milestone}
+	var verifyNotes string // added to the PR description
+	verifyChanges := func() *ProcessResult {
+		const draftWarning = "verify command failed, PR opened as a draft"
+		verifyNotes, prOptions.Draft = "", false
+		if len(job.Verifiers) > 0 {
+			job.UpdateStatus("Verifying changes...")
+			report, err := verify.Run(ctx, targetPath, job.Verifiers)
+			for _, warning := range report.Warnings {
+				if !slices.Contains(warnings, warning) {
+					warnings = append(warnings, warning)
+				}
+			}
+			if err != nil {
+				cleanup()
+				if ctx.Err() != nil {
+					return &ProcessResult{Project: project, Success: false, Error: errCancelled}
+				}
+				return &ProcessResult{Project: project, Success: false, Error: fmt.Errorf("verification failed: %v", err), AIOutput: aiOutput, Diff: diff, Warnings: warnings}
+			}
+			if report.Summary != "" {
+				verifyNotes += "\n\n" + report.Summary
+			}
+		}
+
+		if job.VerifyCommand != "" {
+			job.UpdateStatus("Running verify command...")
+			if err := hooks.Run(ctx, targetPath, "verify command", job.VerifyCommand, job.LogLine); err != nil {
+				if ctx.Err() != nil {
+					cleanup()
+					return &ProcessResult{Project: project, Success: false, Error: errCancelled}
+				}
+				if job.VerifyFailure != config.VerifyFailureDraft {
+					cleanup()
+					return &ProcessResult{Project: project, Success: false, Error: err, AIOutput: aiOutput, Diff: diff, Warnings: warnings}
+				}
+				prOptions.Draft = true
+				verifyNotes += fmt.Sprintf("\n\n> [!WARNING]\n> `%s` failed on these changes, so this PR is a draft.\n\n```\n%s\n```", job.VerifyCommand, err)
+				if !slices.Contains(warnings, draftWarning) {
+					warnings = append(warnings, draftWarning)
+				}
+			}
+		}
+		return nil
+	}
+	if failed := verifyChanges(); failed != nil {
+		return *failed
+	}
+
+	// Have a second tool review the changes before anything is pushed
+	if job.ReviewTool != nil {
+		job.UpdateStatus(fmt.Sprintf("Reviewing changes with %s...", job.ReviewTool.Name))
+		phases.Start("review")
+		verdict, err := aiAgent.ReviewChanges(ctx, job.ReviewTool, job.VibeCodePrompt, diff, targetPath)
+		if err != nil {
+			if ctx.Err() != nil {
+				cleanup()
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			verdict = ai.ReviewVerdict{Approved: false, Reasons: err.Error()}
+		}
+		if !verdict.Approved {
+			job.UpdateStatus(fmt.Sprintf("Rejected by %s — awaiting decision...", job.ReviewTool.Name))
+			if !job.RequestReviewDecision(verdict.Reasons) {
+				cleanup()
+				if ctx.Err() != nil {
+					return ProcessResult{Project: project, Success: false, Error: errCancelled}
+				}
+				return ProcessResult{Project: project, Skipped: true, Error: fmt.Errorf("changes rejected by %s\n%s", job.ReviewTool.Name, lastLines(verdict.Reasons, 5)), AIOutput: aiOutput, Diff: diff, Warnings: warnings}
+			}
+			warnings = append(warnings, fmt.Sprintf("pushed despite rejection by %s", job.ReviewTool.Name))
+		}
+	}
+
+	// On long runs the base branch can move after the clone; the changes are
+	// rebased onto it and verified again rather than opening a stale PR
+	job.UpdateStatus("Checking the base branch...")
+	phases.Start("rebase")
+	moved, err := gitHub.RebaseOntoBase(ctx, project, targetPath)
+	if err != nil {
+		cleanup()
+		if ctx.Err() != nil {
+			return ProcessResult{Project: project, Success: false, Error: errCancelled}
+		}
+		return ProcessResult{Project: project, Success: false, Error: fmt.Errorf("base branch moved during the run: %v", err), AIOutput: aiOutput, Diff: diff, Warnings: warnings}
+	}
+	if moved {
+		warnings = append(warnings, "base branch moved during the run, changes rebased and verified again")
+		diff, _ = git.CaptureDiff(ctx, targetPath)
+		if failed := verifyChanges(); failed != nil {
+			return *failed
+		}
+	}
+	prDescription += verifyNotes
+
+	// The organization's review service has the last word on what is pushed,
+	// and its rejections can't be overridden from the dashboard
+	if policyCfg := job.AppConfig.PolicyReview; policyCfg.URL != "" {
+		job.UpdateStatus("Waiting for policy review...")
+		phases.Start("policy review")
+		verdict, err := policy.Review(ctx, policyCfg, policy.Request{
+			Organization: job.AppConfig.GitHub.Organization,
+			Repo:         project.Repo,
+			Path:         project.Path,
+			BaseBranch:   project.TargetBranch,
+			Branch:       branchName,
+			PRTitle:      job.PRTitle,
+			Prompt:       job.VibeCodePrompt,
+			Diff:         diff,
+		})
+		if err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: fmt.Errorf("policy review failed, not pushing: %v", err), AIOutput: aiOutput, Diff: diff, Warnings: warnings}
+		}
+		if !verdict.Approved {
+			cleanup()
+			return ProcessResult{Project: project, Skipped: true, Error: fmt.Errorf("changes rejected by policy review\n%s", lastLines(verdict.Reasons, 5)), AIOutput: aiOutput, Diff: diff, Warnings: warnings}
+		}
+	}
+
+	// Repos without a Slack room hear about the PR from GitHub instead
+	if job.AppConfig.GitHub.NotifiesOnGitHub(project) {
+		prOptions.Reviewers = project.Reviewers
+		prDescription += "\n\n" + git.ReviewerMentions(project.Reviewers)
+	}
+
+	if err := job.AppConfig.PRPolicy.Check(job.PRTitle, prDescription); err != nil {
+		cleanup()
+		return ProcessResult{Project: project, Success: false, Error: fmt.Errorf("not pushing: %v", err), AIOutput: aiOutput, Diff: diff, Warnings: warnings}
+	}
+
+	// Push changes
+	job.UpdateStatus("Pushing changes...")
+	phases.Start("push")
+	commitMessage, err := job.AppConfig.CommitMessage.Render(config.CommitMessageData{PRTitle: job.PRTitle, Repo: project.Repo, Branch: branchName})
+	if err != nil {
+		cleanup()
+		return ProcessResult{Project: project, Success: false, Error: err, AIOutput: aiOutput, Diff: diff, Warnings: warnings}
+	}
+	err = gitHub.PushChanges(ctx, project, targetPath, branchName, commitMessage, job.AppConfig.CommitSigning)
+	if err != nil {
+		cleanup()
+		if ctx.Err() != nil {
+			return ProcessResult{Project: project, Success: false, Error: errCancelled}
+		}
+		return ProcessResult{Project: project, Success: false, Error: err}
+	}
+
+	if ctx.Err() != nil {
+		cleanup()
+		return ProcessResult{Project: project, Success: false, Error: errCancelled}
+	}
+
+	// Dismissals only happen once the fixes for the other alerts are pushed
+	if len(alertOutcomes) > 0 {
+		dismissAlerts(ctx, job, alertOutcomes)
+		prDescription += "\n\n" + alerts.Table(alertOutcomes)
+	}
+
+	// A branch that already has an open PR gets the new commits on that PR
+	phases.Start("PR")
+	prURL, err := gitHub.FindPullRequest(ctx, project, targetPath, branchName)
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	updatedPR := prURL != ""
+	if updatedPR {
+		if job.UpdatePR {
+			job.UpdateStatus("Updating PR...")
+			if err := gitHub.UpdatePullRequest(ctx, prURL, job.PRTitle, prDescription); err != nil {
+				warnings = append(warnings, err.Error())
+			}
+		}
+	} else {
+		// Org automation reacts to every PR, so they are spaced out
+		if err := job.PRPacer.wait(ctx, job.UpdateStatus); err != nil {
+			cleanup()
+			return ProcessResult{Project: project, Success: false, Error: errCancelled}
+		}
+
+		// Create pull request
+		job.UpdateStatus("Creating PR...")
+		prOutput, err := gitHub.CreatePullRequest(ctx, project, targetPath, branchName, job.PRTitle, prDescription, prOptions)
+		if err != nil {
+			cleanup()
+			if ctx.Err() != nil {
+				return ProcessResult{Project: project, Success: false, Error: errCancelled}
+			}
+			return ProcessResult{Project: project, Success: false, Error: fmt.Errorf("PR creation failed: %v (%s)", err, string(prOutput))}
+		}
+		prURL = strings.TrimSpace(string(prOutput))
+	}
+
+	// Branch protection still decides when the PR merges; a draft waits for a person
+	if job.AutoMerge && !prOptions.Draft {
+		job.UpdateStatus("Enabling auto-merge...")
+		if err := gitHub.EnableAutoMerge(ctx, prURL); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+
+	// Clean up
+	job.UpdateStatus("Cleaning up...")
+	phases.Stop()
+	cleanup()
+
+	return ProcessResult{Project: project, Success: true, Error: nil, PRURL: prURL, UpdatedPR: updatedPR, AIOutput: aiOutput, Diff: diff, Warnings: warnings, PRDescription: prDescription, Outputs: outputs, Alerts: alertOutcomes, Tool: toolName}
+}
+
+// createJiraTicketsFor returns the dashboard's callback creating follow-up
+// tickets from assessment findings, or nil when Jira is not configured.
+func createJiraTicketsFor(cfg config.JiraConfig) func([]config.Project, string, map[string]string, bool, func(string)) {
+	if cfg.BaseURL == "" {
+		return nil
+	}
+	return func(projects []config.Project, question string, findings map[string]string, perTeam bool, onStatus func(string)) {
+		token := jira.ResolveToken()
+		if token == "" {
+			onStatus(fmt.Sprintf("⚠️  Set %s to create Jira tickets", jira.TokenEnv))
+			return
+		}
+		jira.CreateTickets(context.Background(), cfg, token, jira.Tickets(projects, question, findings, perTeam), onStatus)
+	}
+}
+
+// remoteApprovalsFor returns the dashboard's StartRemoteApprovals for cfg,
+// nil when permission requests aren't posted to Slack.
+func remoteApprovalsFor(cfg config.SlackApprovals) func(func(id string, approved bool, by string), func(error)) (input.RemoteApprover, error) {
+	if cfg.Channel == "" {
+		return nil
+	}
+	return func(onAnswer func(id string, approved bool, by string), onError func(error)) (input.RemoteApprover, error) {
+		token := slack.ResolveToken()
+		if token == "" {
+			return nil, errors.New("Slack is not connected; run 'copycat slack connect' or set SLACK_BOT_TOKEN")
+		}
+		approver, err := slack.StartApprover(token, cfg, onAnswer, onError)
+		if err != nil {
+			return nil, err
+		}
+		return approver, nil
+	}
+}
+
+// watchChecksFor returns the dashboard's WatchChecks for cfg, nil when runs
+// don't wait for checks.
+func watchChecksFor(cfg config.CIChecks) func(map[string]string, func(repo, status string)) {
+	if cfg.TimeoutMinutes <= 0 {
+		return nil
+	}
+	return func(prURLs map[string]string, onUpdate func(repo, status string)) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.TimeoutMinutes)*time.Minute)
+		defer cancel()
+		watchChecks(ctx, cfg.PollInterval(), prURLs, onUpdate)
+	}
+}
+
+// watchChecks polls the checks on each PR every interval until they finish,
+// one fails or ctx is done, calling onUpdate whenever a PR's CI status
+// changes, and with why when its checks can't be fetched.
+func watchChecks(ctx context.Context, interval time.Duration, prURLs map[string]string, onUpdate func(repo, status string)) {
+	pending := maps.Clone(prURLs)
+	last := make(map[string]string)
+	for {
+		for repo, url := range pending {
+			checks, err := gitHub.FetchChecks(ctx, url)
+			if ctx.Err() != nil {
+				break
+			}
+			if err != nil {
+				onUpdate(repo, fmt.Sprintf("CI unknown ⚠️ %v", err))
+				delete(pending, repo)
+				continue
+			}
+			if status := checks.Summary(); status != last[repo] {
+				onUpdate(repo, status)
+				last[repo] = status
+			}
+			// A single failed check already makes the PR red
+			if checks.Done() || checks.Failed > 0 {
+				delete(pending, repo)
+			}
+		}
+		if len(pending) == 0 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			for repo := range pending {
+				status := last[repo]
+				if status == "" {
+					status = "CI pending ⏳"
+				}
+				onUpdate(repo, status+", gave up waiting")
+			}
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// checkpoints decides whether a run pauses between batches: after every
+// batch, or with a budget, each time the spend passes the budget again.
+type checkpoints struct {
+	sender     *input.StatusSender
+	budget     config.Budget
+	nextBudget float64
+}
+
+func newCheckpoints(sender *input.StatusSender, budget config.Budget) *checkpoints {
+	return &checkpoints{sender: sender, budget: budget, nextBudget: budget.LimitUSD}
+}
+
+// wait blocks until the user continues, if the run pauses after a batch that
+// brought the spend to spent. It returns the prompt entered at the pause,
+// empty to keep the current one.
+func (c *checkpoints) wait(spent float64) string {
+	if c.sender.ResumeCh == nil {
+		return ""
+	}
+	overBudget := c.budget.LimitUSD > 0 && spent >= c.nextBudget
+	if !overBudget && c.budget.ReplaceRepoCheckpoints {
+		return ""
+	}
+	if overBudget {
+		c.sender.Pause(fmt.Sprintf("Budget reached: $%.2f spent of $%.2f.", spent, c.nextBudget))
+		c.nextBudget = c.budget.Next(spent)
+	}
+	return <-c.sender.ResumeCh
+}
+
+// prPacer spaces out PR creation across a run's workers: each PR takes the
+// next free slot, an interval after the previous one, plus random jitter.
+type prPacer struct {
+	pacing config.PRPacing
+	mu     sync.Mutex
+	next   time.Time // earliest time the next PR may be opened
+}
+
+// wait blocks until the job's PR slot comes up or ctx is cancelled.
+func (p *prPacer) wait(ctx context.Context, status func(string)) error {
+	if p == nil || p.pacing == (config.PRPacing{}) {
+		return nil
+	}
+	p.mu.Lock()
+	at := time.Now()
+	if at.Before(p.next) {
+		at = p.next
+	}
+	if p.pacing.JitterSeconds > 0 {
+		at = at.Add(rand.N(time.Duration(p.pacing.JitterSeconds) * time.Second))
+	}
+	p.next = at.Add(p.pacing.Interval())
+	p.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+	status(fmt.Sprintf("Waiting %s to open PR (pacing)...", delay.Round(time.Second)))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// watchRateLimits shows on the progress view when GitHub API requests wait
+// for a quota to reset, and warns up front about quotas already nearly used
+// up. The returned func stops watching.
+func watchRateLimits(sender *input.StatusSender) func() {
+	// best effort: GitHub Enterprise hosts may have rate limiting turned off
+	quotas, _ := gitHub.FetchRateLimits(context.Background())
+	for _, q := range quotas {
+		if (q.Resource == "core" || q.Resource == "graphql") && q.Low() {
+			sender.PostStatus(fmt.Sprintf("⏳ GitHub API %s quota low: %d of %d left until %s; requests will wait for it", q.Resource, q.Remaining, q.Limit, q.Reset.Local().Format("15:04")))
+		}
+	}
+	git.OnQuotaWait(func(q git.Quota, waiting bool) {
+		if waiting {
+			sender.RateLimited(q.Resource, q.Remaining, q.Limit, q.Reset)
+		} else {
+			sender.RateLimited(q.Resource, 0, 0, time.Time{})
+		}
+	})
+	return func() { git.OnQuotaWait(nil) }
+}
+
+// newLimiter returns the run's adaptive worker limit, or nil when
+// adaptive_parallelism is off and every worker always runs.
+func newLimiter(sender *input.StatusSender, cfg config.AdaptiveParallelism, workers int) *throttle.Limiter {
+	if !cfg.Enabled {
+		return nil
+	}
+	return throttle.New(cfg, workers, git.TransientFailures, func(limit int, reason string) {
+		sender.PostStatus(fmt.Sprintf("⚙️ Parallelism now %d of %d: %s", limit, workers, reason))
+	})
+}
+
+// aiSessions holds the session of each repo's last AI tool run, keyed by
+// project key, so that running the repo again (e.g. retrying it from the done
+// screen) continues where the tool left off instead of starting cold.
+var aiSessions sync.Map
+
+type aiSession struct {
+	tool string
+	id   string
+}
+
+// lastSession returns the session to continue for a run of tool on project,
+// if the tool can resume sessions and ran on the project before.
+func lastSession(project config.Project, tool *config.AITool) string {
+	if tool.ResumeFlag == "" {
+		return ""
+	}
+	if s, ok := aiSessions.Load(project.Key()); ok && s.(aiSession).tool == tool.Name {
+		return s.(aiSession).id
+	}
+	return ""
+}
+
+// rememberSession records the session a tool run on project reported, if any.
+func rememberSession(project config.Project, tool *config.AITool, output string) {
+	if id := ai.SessionID(output); id != "" {
+		aiSessions.Store(project.Key(), aiSession{tool: tool.Name, id: id})
+	}
+}
+
+// toolRun is the outcome of runToolChain.
+type toolRun struct {
+	Output   string         // answer of the last tool run
+	Tool     *config.AITool // the last tool run
+	Warnings []string       // one per fallback
+	Usage    ai.Usage       // summed over every tool run
+}
+
+// runToolChain runs the job's AI tool and, while it fails or leaves the repo
+// unchanged, each fallback tool in turn on a clean working tree.
+func runToolChain(ctx context.Context, job ProcessJob, targetPath string, tempBackups []ai.TempBackup) (toolRun, error) {
+	run := toolRun{Tool: job.AITool}
+	chain := job.AppConfig.ToolChain(job.AITool)
+
+	// Temporary file changes, the pre hook and the actions already show up as changes
+	var baseline []byte
+	if len(chain) > 1 {
+		var err error
+		if baseline, err = git.CheckLocalChanges(ctx, targetPath); err != nil {
+			return run, err
+		}
+	}
+
+	for i, tool := range chain {
+		if i == 0 {
+			job.UpdateStatus("Running AI agent...")
+		} else {
+			job.UpdateStatus(fmt.Sprintf("Retrying with %s...", tool.Name))
+		}
+		output, err := aiAgent.VibeCode(ctx, tool, job.VibeCodePrompt, targetPath, job.MCPConfigPath, lastSession(job.Project, tool), job.Project.Repo, job.AppConfig.ToolTimeout(tool), job.LogLine)
+		rememberSession(job.Project, tool, output)
+		usage, text := ai.ParseUsage(output, tool.Pricing)
+		run.Output, run.Tool, run.Usage = text, tool, run.Usage.Add(usage)
+		if ctx.Err() != nil || i == len(chain)-1 {
+			return run, err
+		}
+
+		reason := "failed"
+		if errors.Is(err, ai.ErrTimeout) {
+			reason = err.Error()
+		} else if err == nil {
+			status, err := git.CheckLocalChanges(ctx, targetPath)
+			if err != nil {
+				return run, err
+			}
+			if !bytes.Equal(status, baseline) {
+				return run, nil
+			}
+			reason = "made no changes"
+		}
+		run.Warnings = append(run.Warnings, fmt.Sprintf("%s %s, retried with %s", tool.Name, reason, chain[i+1].Name))
+
+		if err := git.DiscardChanges(ctx, targetPath); err != nil {
+			return run, err
+		}
+		ai.ReapplyTempChanges(targetPath, tempBackups)
+		// The fallback starts where the first tool did: after the pre hook
+		if job.Hooks.Pre != "" {
+			if err := hooks.Run(ctx, targetPath, "pre hook", job.Hooks.Pre, job.LogLine); err != nil {
+				return run, err
+			}
+		}
+		// and the actions
+		if job.Actions != nil {
+			if _, err := actions.Apply(ctx, targetPath, job.Actions.Steps); err != nil {
+				return run, err
+			}
+		}
+	}
+	return run, nil
+}
+
+// dismissAlerts dismisses the alerts the AI chose to dismiss. Alerts that
+// cannot be dismissed are marked open with the reason.
+func dismissAlerts(ctx context.Context, job ProcessJob, outcomes []alerts.Outcome) {
+	for i := range outcomes {
+		o := &outcomes[i]
+		if o.Action != alerts.ActionDismissed {
+			continue
+		}
+		job.UpdateStatus(fmt.Sprintf("Dismissing %s...", o.Alert.ID()))
+		if err := gitHub.DismissAlert(ctx, job.AppConfig.GitHub.Organization, job.Project.Repo, o.Alert, o.Reason, o.Justification); err != nil {
+			o.Action = alerts.ActionOpen
+			o.Error = err.Error()
+		}
+	}
+}
+
+// processReposWithSender runs the code-change workflow. promptContext holds
+// extra prompt context keyed by repo name, such as scanner findings.
+func processReposWithSender(sender *input.StatusSender, selectedProjects []config.Project, setup *input.WizardResult, appCfg config.Config, parallelism int, promptContext map[string]string) {
+	started := time.Now()
+	sender, finishEvents := runEvents(sender, setup, appCfg.Webhook, len(selectedProjects))
+	defer finishEvents()
+	filesystem.CreateWorkspace()
+
+	// Upstream repos must be processed before the repos that depend on them,
+	// including repos whose outputs the prompt references as {{repo.name}}
+	selectedProjects = withPromptDependencies(config.ExpandTargetBranches(selectedProjects), setup.Prompt)
+	ordered, err := config.OrderByDependencies(selectedProjects)
+	if err != nil {
+		for _, project := range selectedProjects {
+			sender.Done(input.ProjectDoneMsg{Repo: project.Key(), Status: fmt.Sprintf("Failed ⚠️ %v", err), Error: err})
+		}
+		return
+	}
+	selectedProjects = ordered
+	clones := newSharedClones(selectedProjects)
+	pacer := &prPacer{pacing: appCfg.PRPacing}
+	defer watchRateLimits(sender)()
+
+	var pipeline *config.ActionPipeline
+	if setup.Actions != "" {
+		var ok bool
+		if pipeline, ok = appCfg.ActionPipeline(setup.Actions); !ok {
+			err := fmt.Errorf("action pipeline %q is not configured", setup.Actions)
+			for _, project := range selectedProjects {
+				sender.Done(input.ProjectDoneMsg{Repo: project.Key(), Status: fmt.Sprintf("Failed ⚠️ %v", err), Error: err})
+			}
+			return
+		}
+	}
+
+	// A title the organization's bots would reject fails the run before any
+	// repo is worked on, and so does picking the review tool to make the
+	// changes it would review
+	err = appCfg.PRPolicy.CheckTitle(setup.PRTitle)
+	if err == nil && setup.AITool != nil && setup.AITool.Name == appCfg.ReviewTool {
+		err = fmt.Errorf("%s is the review tool, so it can't make the changes too; pick another AI tool", appCfg.ReviewTool)
+	}
+	if err != nil {
+		for _, project := range selectedProjects {
+			sender.Done(input.ProjectDoneMsg{Repo: project.Key(), Status: fmt.Sprintf("Failed ⚠️ %v", err), Error: err})
+		}
+		return
+	}
+
+	checkpoint := parallelism
+	if checkpoint < 5 {
+		checkpoint = 5
+	}
+
+	var jobs []ProcessJob
+	for _, project := range selectedProjects {
+		ctx, cancel := context.WithCancel(context.Background())
+		if sender.CancelRegistry != nil {
+			sender.CancelRegistry.Register(project.Key(), cancel)
+		} else {
+			cancel() // no registry; context unused, release immediately
+			ctx = context.Background()
+		}
+		var licenseHeader *config.LicenseHeaderConfig
+		if setup.LicenseHeader {
+			licenseHeader = &appCfg.LicenseHeader
+		}
+		var reviewTool *config.AITool
+		if appCfg.ReviewTool != "" {
+			reviewTool, _ = appCfg.ToolByName(appCfg.ReviewTool)
+		}
+		jobs = append(jobs, ProcessJob{
+			Ctx:                    ctx,
+			Project:                project,
+			AITool:                 setup.AITool,
+			AppConfig:              appCfg,
+			PRTitle:                setup.PRTitle,
+			VibeCodePrompt:         setup.Prompt,
+			PromptContext:          promptContext[project.Repo],
+			BranchStrategy:         setup.BranchStrategy,
+			SpecifiedBranch:        setup.BranchName,
+			AutoMerge:              setup.AutoMerge,
+			UpdatePR:               setup.UpdatePR,
+			PRMetadata:             config.PRMetadata{Labels: setup.PRLabels, Milestone: setup.PRMilestone},
+			MCPConfigPath:          sender.MCPConfigPath,
+			TempChanges:            tempChanges(setup, appCfg),
+			ProtectedPaths:         appCfg.ProtectedPaths,
+			Hooks:                  appCfg.HooksFor(project),
+			AllowLineEndingChanges: appCfg.AllowLineEndingChanges,
+			ReviewTool:             reviewTool,
+			TrivialChanges:         appCfg.TrivialChanges,
+			LicenseHeader:          licenseHeader,
+			Actions:                pipeline,
+			ActionsOnly:            pipeline != nil && setup.Prompt == "",
+			TriageAlerts:           setup.TriageAlerts,
+			Verifiers:              verify.Lookup(appCfg.Verify),
+			VerifyCommand:          appCfg.VerifyCommandFor(project),
+			VerifyFailure:          setup.VerifyFailure,
+			DependsOn:              config.SelectedDependencies(project, selectedProjects),
+			Clones:                 clones,
+			PRPacer:                pacer,
+		})
+	}
+
+	numWorkers := parallelism
+	if numWorkers > len(jobs) {
+		numWorkers = len(jobs)
+	}
+	limiter := newLimiter(sender, appCfg.AdaptiveParallelism, numWorkers)
+
+	var mu sync.Mutex
+	resultMap := make(map[string]ProcessResult)
+
+	// finished[key] is closed once the job's result is recorded; a repo
+	// targeting several branches has one key per branch
+	finished := make(map[string]chan struct{}, len(jobs))
+	keysByRepo := make(map[string][]string)
+	for _, job := range jobs {
+		key := job.Project.Key()
+		finished[key] = make(chan struct{})
+		keysByRepo[job.Project.Repo] = append(keysByRepo[job.Project.Repo], key)
+	}
+	resultsOf := func(repo string) []ProcessResult {
+		mu.Lock()
+		defer mu.Unlock()
+		var results []ProcessResult
+		for _, key := range keysByRepo[repo] {
+			results = append(results, resultMap[key])
+		}
+		return results
+	}
+
+	// Process in batches, pausing between them for user confirmation
+	pauses := newCheckpoints(sender, appCfg.Budget)
+	for batchStart := 0; batchStart < len(jobs); batchStart += checkpoint {
+		batchEnd := batchStart + checkpoint
+		if batchEnd > len(jobs) {
+			batchEnd = len(jobs)
+		}
+		batch := jobs[batchStart:batchEnd]
+
+		batchWorkers := numWorkers
+		if batchWorkers > len(batch) {
+			batchWorkers = len(batch)
+		}
+
+		jobCh := make(chan ProcessJob, len(batch))
+		var wg sync.WaitGroup
+
+		for w := 0; w < batchWorkers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for job := range jobCh {
+					repo := job.Project.Key()
+					job.UpdateStatus = func(status string) {
+						sender.UpdateStatus(repo, status)
+					}
+					job.LogLine = func(line string) {
+						sender.Log(repo, line)
+					}
+					if job.ReviewTool != nil {
+						reviewer := job.ReviewTool.Name
+						jobCtx := job.Ctx
+						job.RequestReviewDecision = func(reasons string) bool {
+							return sender.RequestReviewDecision(jobCtx, repo, reviewer, reasons)
+						}
+					}
+					var result ProcessResult
+					upstreamNotes, err := awaitUpstream(job, finished, keysByRepo, resultsOf)
+					switch {
+					case err == errCancelled:
+						result = ProcessResult{Project: job.Project, Error: errCancelled}
+					case err != nil:
+						result = ProcessResult{Project: job.Project, Skipped: true, Error: err}
+					default:
+						job.VibeCodePrompt = job.Project.WithPromptNotes(job.VibeCodePrompt + job.PromptContext + upstreamNotes)
+						var missing []string
+						job.VibeCodePrompt, missing = artifacts.Expand(job.VibeCodePrompt, job.Project.Repo, func(ref artifacts.Ref) (string, bool) {
+							for _, upstream := range resultsOf(ref.Repo) {
+								if value, ok := upstream.Outputs[ref.Name]; ok {
+									return value, true
+								}
+							}
+							return "", false
+						})
+						if len(missing) > 0 {
+							result = ProcessResult{Project: job.Project, Skipped: true, Error: fmt.Errorf("missing upstream outputs: %s", strings.Join(missing, ", "))}
+						} else if err := limiter.Acquire(job.Ctx, job.UpdateStatus); err != nil {
+							result = ProcessResult{Project: job.Project, Error: errCancelled}
+						} else {
+							result = processProject(job)
+							limiter.Release(!result.Success && !result.Skipped && result.Error != errCancelled)
+						}
+					}
+
+					if sharesClone(job.Project) {
+						clones.Release(job.Project.Repo)
+					}
+
+					mu.Lock()
+					resultMap[repo] = result
+					mu.Unlock()
+					close(finished[repo])
+
+					var status string
+					switch {
+					case result.Success && result.UpdatedPR:
+						status = fmt.Sprintf("Completed ✅ Updated PR: \033]8;;%s\033\\%s\033]8;;\033\\", result.PRURL, result.PRURL)
+					case result.Success:
+						status = fmt.Sprintf("Completed ✅ PR: \033]8;;%s\033\\%s\033]8;;\033\\", result.PRURL, result.PRURL)
+					case result.Skipped:
+						status = fmt.Sprintf("Skipped ⊘ %v", result.Error)
+					case result.Error == errCancelled:
+						status = "Cancelled ✗"
+					case errors.Is(result.Error, ai.ErrTimeout):
+						status = fmt.Sprintf("Failed ⏱ %v", result.Error)
+					default:
+						status = fmt.Sprintf("Failed ⚠️ %v", result.Error)
+					}
+					if result.Tool != "" && result.Tool != job.AITool.Name {
+						status += " 🔁 via " + result.Tool
+					}
+					if len(result.Outputs) > 0 {
+						status += " 📦 " + artifacts.Summary(result.Outputs)
+					}
+					aiOutput := result.AIOutput
+					if len(result.Alerts) > 0 {
+						status += " 🛡 " + alerts.Summary(result.Alerts)
+						aiOutput = alerts.Details(result.Alerts) + "\n\n" + aiOutput
+					}
+					if len(result.Warnings) > 0 {
+						status += " ⚠️ " + strings.Join(result.Warnings, "; ")
+					}
+					if !result.Usage.IsZero() {
+						status += " 💲 " + result.Usage.String()
+					}
+					sender.Done(input.ProjectDoneMsg{
+						Repo:      repo,
+						Status:    status,
+						Success:   result.Success,
+						Skipped:   result.Skipped,
+						PRURL:     result.PRURL,
+						Error:     result.Error,
+						AIOutput:  aiOutput,
+						GitLog:    result.GitLog,
+						GitHubLog: result.GitHubLog,
+						Timeline:  result.Timeline,
+						Diff:      result.Diff,
+						Usage:     result.Usage,
+					})
+				}
+			}()
+		}
+
+		for _, job := range batch {
+			jobCh <- job
+		}
+		close(jobCh)
+		wg.Wait()
+
+		// Wait for user confirmation before starting next batch
+		if batchEnd < len(jobs) {
+			var spent ai.Usage
+			for _, result := range resultMap {
+				spent = spent.Add(result.Usage)
+			}
+			newPrompt := pauses.wait(spent.CostUSD)
+			if newPrompt != "" {
+				for i := batchEnd; i < len(jobs); i++ {
+					jobs[i].VibeCodePrompt = newPrompt
+				}
+			}
+		}
+	}
+
+	reportInconsistencies(sender, resultMap, appCfg.ConsistencyPaths)
+	linkRelatedPRs(sender, setup, resultMap)
+	recordRun(setup, started, processedRepos(selectedProjects, resultMap))
+}
+
+// linkRelatedPRs adds a Related PRs section to the description of each PR
+// the run opened or rewrote, linking the run's other PRs, once they all
+// exist. PRs left as they were are linked from the others but not changed.
+func linkRelatedPRs(sender *input.StatusSender, setup *input.WizardResult, resultMap map[string]ProcessResult) {
+	var urls []string
+	for _, result := range resultMap {
+		if result.Success && result.PRURL != "" {
+			urls = append(urls, result.PRURL)
+		}
+	}
+	if len(urls) < 2 {
+		return
+	}
+	slices.Sort(urls)
+
+	linked := 0
+	for _, repo := range slices.Sorted(maps.Keys(resultMap)) {
+		result := resultMap[repo]
+		if !result.Success || result.PRURL == "" || (result.UpdatedPR && !setup.UpdatePR) {
+			continue
+		}
+		others := slices.DeleteFunc(slices.Clone(urls), func(url string) bool { return url == result.PRURL })
+		description := result.PRDescription + "\n\n" + git.RelatedPRs(others)
+		if err := gitHub.UpdatePullRequest(context.Background(), result.PRURL, setup.PRTitle, description); err != nil {
+			sender.PostStatus(fmt.Sprintf("⚠️ Failed to link related PRs from %s: %v", repo, err))
+			continue
+		}
+		linked++
+	}
+	if linked > 0 {
+		sender.PostStatus(fmt.Sprintf("🔗 Linked related PRs in %d PR descriptions", linked))
+	}
+}
+
+// reportInconsistencies flags the repos whose change to a file shared with
+// other repos diverged from the change most of them made, catching agents
+// that took their own route.
+func reportInconsistencies(sender *input.StatusSender, resultMap map[string]ProcessResult, patterns []string) {
+	diffs := make(map[string]string)
+	for repo, result := range resultMap {
+		if result.Success {
+			diffs[repo] = result.Diff
+		}
+	}
+	for _, outlier := range util.Inconsistencies(diffs, patterns) {
+		sender.PostStatus(fmt.Sprintf("⚠️ %s changed %s differently from %d other repos", outlier.Repo, outlier.Path, outlier.Agreed))
+	}
+}
+
+// tempChanges lists the run's temporary file changes: the configured agent
+// instruction files when the wizard ignores them, then the ones added in it.
+func tempChanges(setup *input.WizardResult, appCfg config.Config) []config.TempChange {
+	var changes []config.TempChange
+	if setup.IgnoreAgentInstructions {
+		for _, path := range appCfg.AgentInstructions {
+			changes = append(changes, config.TempChange{Path: path, Remove: true})
+		}
+	}
+	return append(changes, setup.TempChanges...)
+}
+
+// licenseHeaderSummary describes the files that were given a license header,
+// used as the PR description.
+func licenseHeaderSummary(files []string) string {
+	if len(files) == 0 {
+		return "All source files already have the license header."
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Adds the standard license header to %d file(s) that were missing it:\n\n", len(files))
+	for _, f := range files {
+		fmt.Fprintf(&b, "- `%s`\n", f)
+	}
+	return b.String()
+}
+
+// withPromptDependencies makes every project depend on the selected repos whose
+// outputs the prompt references, so producers run before their consumers.
+func withPromptDependencies(projects []config.Project, prompt string) []config.Project {
+	refs := artifacts.References(prompt)
+	if len(refs) == 0 {
+		return projects
+	}
+
+	result := make([]config.Project, len(projects))
+	for i, project := range projects {
+		deps := append([]string{}, project.DependsOn...)
+		for _, ref := range refs {
+			if ref.Repo != project.Repo && !slices.Contains(deps, ref.Repo) {
+				deps = append(deps, ref.Repo)
+			}
+		}
+		project.DependsOn = deps
+		result[i] = project
+	}
+	return result
+}
+
+// awaitUpstream blocks until every dependency of job has finished, on every
+// target branch it was selected for. It returns prompt context describing the
+// upstream changes, or an error if a dependency did not complete so the
+// downstream repo can be skipped.
+func awaitUpstream(job ProcessJob, finished map[string]chan struct{}, keysByRepo map[string][]string, resultsOf func(repo string) []ProcessResult) (string, error) {
+	if len(job.DependsOn) == 0 {
+		return "", nil
+	}
+
+	job.UpdateStatus(fmt.Sprintf("Waiting for %s...", strings.Join(job.DependsOn, ", ")))
+	var notes strings.Builder
+	notes.WriteString("\n\nThis repository depends on changes made earlier in this run:\n")
+	for _, dep := range job.DependsOn {
+		for _, key := range keysByRepo[dep] {
+			select {
+			case <-finished[key]:
+			case <-job.Ctx.Done():
+				return "", errCancelled
+			}
+		}
+
+		for _, upstream := range resultsOf(dep) {
+			key := upstream.Project.Key()
+			switch {
+			case upstream.Success:
+				notes.WriteString(fmt.Sprintf("- %s: %s\n  %s\n", key, upstream.PRURL, strings.ReplaceAll(strings.TrimSpace(upstream.PRDescription), "\n", "\n  ")))
+			case upstream.NoChanges:
+				notes.WriteString(fmt.Sprintf("- %s: no changes were needed\n", key))
+			default:
+				return "", fmt.Errorf("upstream %s did not complete", key)
+			}
+		}
+	}
+	return notes.String(), nil
+}
+
+// runEvents sends the run's events to the configured webhook, returning the
+// sender that also reports each repo's result to it and a function sending
+// the run.finished event.
+func runEvents(sender *input.StatusSender, setup *input.WizardResult, cfg config.Webhook, repos int) (*input.StatusSender, func()) {
+	if cfg.URL == "" {
+		return sender, func() {}
+	}
+	run := webhook.Start(cfg, webhook.RunInfo{
+		Action:     setup.Action,
+		Title:      setup.PRTitle,
+		CampaignID: setup.CampaignID,
+		Repos:      repos,
+	}, func(err error) {
+		sender.PostStatus(fmt.Sprintf("⚠️ %v", err))
+	})
+	sender = sender.OnDone(func(msg input.ProjectDoneMsg) {
+		run.RepoDone(msg.Repo, msg.PRURL, msg.Status, msg.Success, msg.Skipped, msg.Error)
+	})
+	return sender, run.Finish
+}
+
+// recordRun appends the outcome of a run to the run history, so campaign
+// progress can be charted and prompts reused across runs.
+func recordRun(setup *input.WizardResult, started time.Time, repos []history.RepoRecord) {
+	path, err := config.HistoryPath()
+	if err != nil {
+		log.Printf("⚠️ Failed to resolve history path: %v", err)
+		return
+	}
 
-	return ProcessResult{Project: project, Success: true, Error: nil, PRURL: prURL, AIOutput: aiOutput}
+	var total ai.Usage
+	for _, r := range repos {
+		if r.Usage != nil {
+			total = total.Add(*r.Usage)
+		}
+	}
+	rec := history.Record{
+		StartedAt:  started,
+		FinishedAt: time.Now(),
+		Action:     setup.Action,
+		Campaign:   setup.PRTitle,
+		CampaignID: setup.CampaignID,
+		Prompt:     setup.Prompt,
+		Repos:      repos,
+		Usage:      usageRecord(total),
+	}
+	if err := history.Append(path, rec); err != nil {
+		log.Printf("⚠️ Failed to record run history: %v", err)
+	}
 }
 
-func processReposWithSender(sender *input.StatusSender, selectedProjects []config.Project, setup *input.WizardResult, appCfg config.Config, parallelism int) {
-	filesystem.CreateWorkspace()
-
-	checkpoint := parallelism
-	if checkpoint < 5 {
-		checkpoint = 5
+// usageRecord returns usage for a history record, or nil when nothing was
+// reported.
+func usageRecord(usage ai.Usage) *ai.Usage {
+	if usage.IsZero() {
+		return nil
 	}
+	return &usage
+}
 
-	var jobs []ProcessJob
+// processedRepos converts code-change results into history records.
+func processedRepos(selectedProjects []config.Project, resultMap map[string]ProcessResult) []history.RepoRecord {
+	var repos []history.RepoRecord
 	for _, project := range selectedProjects {
-		ctx, cancel := context.WithCancel(context.Background())
-		if sender.CancelRegistry != nil {
-			sender.CancelRegistry.Register(project.Repo, cancel)
-		} else {
-			cancel() // no registry; context unused, release immediately
-			ctx = context.Background()
+		result, ok := resultMap[project.Key()]
+		outcome := "cancelled"
+		switch {
+		case !ok || result.Error == errCancelled:
+		case result.Success:
+			outcome = "succeeded"
+		case result.Skipped:
+			outcome = "skipped"
+		default:
+			outcome = "failed"
 		}
-		var ignoreFiles []string
-		if setup.IgnoreAgentInstructions {
-			ignoreFiles = appCfg.AgentInstructions
+		repos = append(repos, history.RepoRecord{Repo: project.Key(), Outcome: outcome, PRURL: result.PRURL, Tool: result.Tool, Usage: usageRecord(result.Usage)})
+	}
+	return repos
+}
+
+// promptHistory returns recently used prompts from the run history.
+func promptHistory() []history.PromptEntry {
+	path, err := config.HistoryPath()
+	if err != nil {
+		return nil
+	}
+	records, err := history.Load(path)
+	if err != nil {
+		return nil
+	}
+	return history.RecentPrompts(records, 20)
+}
+
+// loadSARIF groups a SARIF report's findings by repo and returns the projects
+// that have findings, with each repo's findings rendered as prompt context.
+func loadSARIF(path string, projects []config.Project) ([]config.Project, map[string]string, error) {
+	repos := make([]string, len(projects))
+	for i, project := range projects {
+		repos[i] = project.Repo
+	}
+	grouped, unmatched, err := sarif.Load(path, repos)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var withFindings []config.Project
+	promptContext := make(map[string]string)
+	total := 0
+	for _, project := range projects {
+		if findings := grouped[project.Repo]; len(findings) > 0 {
+			withFindings = append(withFindings, project)
+			promptContext[project.Repo] = sarif.PromptContext(findings)
+			total += len(findings)
 		}
-		jobs = append(jobs, ProcessJob{
-			Ctx:             ctx,
-			Project:         project,
-			AITool:          setup.AITool,
-			AppConfig:       appCfg,
-			PRTitle:         setup.PRTitle,
-			VibeCodePrompt:  setup.Prompt,
-			BranchStrategy:  setup.BranchStrategy,
-			SpecifiedBranch: setup.BranchName,
-			MCPConfigPath:   sender.MCPConfigPath,
-			IgnoreFiles:     ignoreFiles,
-		})
+	}
+	if len(withFindings) == 0 {
+		return nil, nil, fmt.Errorf("no findings in %s match a known project", path)
 	}
 
-	numWorkers := parallelism
-	if numWorkers > len(jobs) {
-		numWorkers = len(jobs)
+	fmt.Printf("Loaded %d findings for %d repositories from %s\n", total, len(withFindings), path)
+	if unmatched > 0 {
+		fmt.Printf("⚠️ %d findings did not match a known project and were ignored\n", unmatched)
 	}
+	return withFindings, promptContext, nil
+}
 
-	var mu sync.Mutex
-	resultMap := make(map[string]ProcessResult)
+// loadManifest reads a manifest (see config.LoadManifest) and returns the
+// projects it lists, with the dependencies it adds.
+func loadManifest(path string, projects []config.Project) ([]config.Project, config.Manifest, error) {
+	manifest, err := config.LoadManifest(path)
+	if err != nil {
+		return nil, manifest, err
+	}
 
-	// Process in batches, pausing between them for user confirmation
-	for batchStart := 0; batchStart < len(jobs); batchStart += checkpoint {
-		batchEnd := batchStart + checkpoint
-		if batchEnd > len(jobs) {
-			batchEnd = len(jobs)
+	var listed []config.Project
+	for _, project := range projects {
+		if slices.Contains(manifest.Repos, project.Key()) || slices.Contains(manifest.Repos, project.Repo) {
+			listed = append(listed, project)
 		}
-		batch := jobs[batchStart:batchEnd]
+	}
+	if len(listed) == 0 {
+		return nil, manifest, fmt.Errorf("no repos in %s match a known project", path)
+	}
+	listed = manifest.WithDependencies(listed)
+	fmt.Printf("Loaded %d repositories from %s\n", len(listed), path)
+	if n := len(manifest.Context); n > 0 {
+		fmt.Printf("Loaded %d context attachment(s) from %s\n", n, path)
+	}
+	return listed, manifest, nil
+}
 
-		batchWorkers := numWorkers
-		if batchWorkers > len(batch) {
-			batchWorkers = len(batch)
+// loadExample fetches the diff of the reference PR at url and returns the
+// projects other than the PR's own repo, which already has the change, with
+// the diff rendered as prompt context.
+func loadExample(url string, projects []config.Project) ([]config.Project, string, error) {
+	repo, err := example.Repo(url)
+	if err != nil {
+		return nil, "", err
+	}
+	diff, err := gitHub.FetchPullRequestDiff(context.Background(), url)
+	if err != nil {
+		return nil, "", err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return nil, "", fmt.Errorf("%s has no changes to learn from", url)
+	}
+
+	others := slices.DeleteFunc(slices.Clone(projects), func(p config.Project) bool { return p.Repo == repo })
+	fmt.Printf("Loaded the example change from %s (%d lines of diff)\n", url, strings.Count(diff, "\n"))
+	return others, example.PromptContext(url, diff), nil
+}
+
+// loadFlakyTests reads saved flaky test results and returns the projects with
+// suspected flaky tests, with each repo's tests rendered as prompt context.
+func loadFlakyTests(path string, projects []config.Project) ([]config.Project, map[string]string, error) {
+	saved, err := flaky.Load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Results are saved per project key; the services and target branches of
+	// a repo share its prompt
+	byRepo := make(map[string][]flaky.Test)
+	for key, tests := range saved {
+		repo, _, _ := strings.Cut(key, "@")
+		repo, _, _ = strings.Cut(repo, "/")
+		byRepo[repo] = append(byRepo[repo], tests...)
+	}
+
+	var withTests []config.Project
+	promptContext := make(map[string]string)
+	total := 0
+	for _, project := range projects {
+		if tests := byRepo[project.Repo]; len(tests) > 0 {
+			withTests = append(withTests, project)
+			promptContext[project.Repo] = flaky.PromptContext(tests)
+			total += len(tests)
 		}
+	}
+	if len(withTests) == 0 {
+		return nil, nil, fmt.Errorf("no flaky tests in %s match a known project", path)
+	}
 
-		jobCh := make(chan ProcessJob, len(batch))
-		var wg sync.WaitGroup
+	fmt.Printf("Loaded %d suspected flaky tests for %d repositories from %s\n", total, len(withTests), path)
+	return withTests, promptContext, nil
+}
 
-		for w := 0; w < batchWorkers; w++ {
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				for job := range jobCh {
-					repo := job.Project.Repo
-					job.UpdateStatus = func(status string) {
-						sender.UpdateStatus(repo, status)
-					}
-					result := processProject(job)
+// applySettings applies the configured GitHub and language settings.
+func applySettings(cfg *config.Config) {
+	git.SetHost(cfg.GitHub.Host)
+	git.SetCloneProtocol(cfg.GitHub.Protocol())
+	git.SetRetries(cfg.Retries)
+	git.SetRateLimit(cfg.RateLimit)
+	i18n.SetLanguage(cfg.Language)
+}
 
-					mu.Lock()
-					resultMap[repo] = result
-					mu.Unlock()
+// runIssues handles the `copycat issues [open|closed]` subcommand: it lists
+// the issues opened by past runs with where each stands now.
+func runIssues(args []string) error {
+	state := ""
+	if len(args) > 0 {
+		state = args[0]
+	}
+	if len(args) > 1 || (state != "" && state != "open" && state != "closed") {
+		return errors.New("usage: copycat issues [open|closed]")
+	}
 
-					var status string
-					switch {
-					case result.Success:
-						status = fmt.Sprintf("Completed ✅ PR: \033]8;;%s\033\\%s\033]8;;\033\\", result.PRURL, result.PRURL)
-					case result.Skipped:
-						status = fmt.Sprintf("Skipped ⊘ %v", result.Error)
-					case result.Error == errCancelled:
-						status = "Cancelled ✗"
-					default:
-						status = fmt.Sprintf("Failed ⚠️ %v", result.Error)
-					}
-					sender.Done(repo, status, result.Success, result.Skipped, result.PRURL, result.Error, result.AIOutput)
-				}
-			}()
+	var err error
+	if configPath, err = config.ConfigPath(); err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	applySettings(cfg)
+
+	historyPath, err := config.HistoryPath()
+	if err != nil {
+		return fmt.Errorf("failed to resolve history path: %w", err)
+	}
+	records, err := history.Load(historyPath)
+	if err != nil {
+		return err
+	}
+	issues := history.CreatedIssues(records)
+	if len(issues) == 0 {
+		fmt.Println("No issues have been opened by copycat yet.")
+		return nil
+	}
+	listIssues(context.Background(), os.Stdout, gitHub, issues, state)
+	return nil
+}
+
+// listIssues writes the issues grouped by the title of the run that opened
+// them, with where each stands on GitHub, keeping only those in state unless
+// it is empty.
+func listIssues(ctx context.Context, out io.Writer, provider git.Provider, issues []history.Issue, state string) {
+	counts := make(map[string]int)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	group := -1
+	for i, issue := range issues {
+		current := "unknown"
+		var details []string
+		if s, err := provider.FetchIssueState(ctx, issue.URL); err != nil {
+			details = append(details, err.Error())
+		} else {
+			current = s.State
+			if s.State == "closed" && s.StateReason != "" {
+				details = append(details, strings.ReplaceAll(s.StateReason, "_", " "))
+			}
+			if len(s.Assignees) > 0 {
+				details = append(details, "assigned to "+strings.Join(s.Assignees, ", "))
+			}
 		}
+		counts[current]++
+		if state != "" && current != state {
+			continue
+		}
+		if group < 0 || issues[group].Title != issue.Title {
+			if group >= 0 {
+				fmt.Fprintln(w)
+			}
+			group = i
+			fmt.Fprintf(w, "%s (%s)\n", issue.Title, issue.CreatedAt.Local().Format("2006-01-02"))
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", issue.Repo, current, issue.URL, strings.Join(details, "; "))
+	}
+	w.Flush()
 
-		for _, job := range batch {
-			jobCh <- job
+	var summary []string
+	for _, s := range []string{"open", "closed", "unknown"} {
+		if counts[s] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[s], s))
 		}
-		close(jobCh)
-		wg.Wait()
+	}
+	fmt.Fprintf(out, "\n%d issues: %s\n", len(issues), strings.Join(summary, ", "))
+}
 
-		// Wait for user confirmation before starting next batch
-		if batchEnd < len(jobs) && sender.ResumeCh != nil {
-			newPrompt := <-sender.ResumeCh
-			if newPrompt != "" {
-				for i := batchEnd; i < len(jobs); i++ {
-					jobs[i].VibeCodePrompt = newPrompt
+// runHealthChecks runs the configured health checks that are due, or the
+// named ones regardless of schedule, without the dashboard, and posts each
+// one's digest to its Slack room.
+func runHealthChecks(names []string) error {
+	var err error
+	if configPath, err = config.ConfigPath(); err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+	if projectsPath, err = config.ProjectsPath(); err != nil {
+		return fmt.Errorf("failed to get projects path: %w", err)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if len(cfg.HealthChecks) == 0 {
+		return fmt.Errorf("no health_checks defined in %s", configPath)
+	}
+	applySettings(cfg)
+	for _, name := range names {
+		if !slices.ContainsFunc(cfg.HealthChecks, func(h config.HealthCheck) bool { return h.Name == name }) {
+			return fmt.Errorf("unknown health check %q", name)
+		}
+	}
+	projects, err := config.LoadProjects(projectsPath)
+	if err != nil {
+		return fmt.Errorf("failed to load projects: %w", err)
+	}
+	projects = config.ExpandServices(cfg.GitHub.WithoutExcluded(projects))
+
+	token := slack.ResolveToken()
+	if token == "" {
+		return errors.New("Slack is not connected; run 'copycat slack connect' or set SLACK_BOT_TOKEN")
+	}
+	historyPath, err := config.HistoryPath()
+	if err != nil {
+		return err
+	}
+
+	filesystem.DeleteWorkspace()
+	defer filesystem.DeleteEmptyWorkspace()
+
+	for _, check := range cfg.HealthChecks {
+		if len(names) > 0 && !slices.Contains(names, check.Name) {
+			continue
+		}
+		records, err := history.Load(historyPath)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 && !healthcheck.Due(check, history.AssessmentRuns(records, check.Question), time.Now()) {
+			fmt.Printf("%s: not due yet\n", check.Name)
+			continue
+		}
+
+		selected := projects
+		if check.Profile != "" {
+			selected = nil
+			for _, p := range projects {
+				if slices.Contains(cfg.Profiles[check.Profile], p.Key()) {
+					selected = append(selected, p)
 				}
 			}
 		}
+		if len(selected) == 0 {
+			fmt.Printf("⚠️ %s: no projects to assess\n", check.Name)
+			continue
+		}
+		toolName := check.Tool
+		if toolName == "" {
+			toolName = cfg.Default
+		}
+		tool, _ := cfg.ToolByName(toolName)
+
+		fmt.Printf("%s: assessing %d projects...\n", check.Name, len(selected))
+		setup := &input.WizardResult{Action: "assessment", AITool: tool, Prompt: check.Question, StructuredAssessment: true}
+		var summary string
+		sender := input.NewHeadlessSender(func(line string) {
+			fmt.Printf("%s: %s\n", check.Name, line)
+		}, func(msg input.AssessmentResultMsg) {
+			summary = msg.Summary
+		})
+		assessReposWithSender(sender, selected, setup, *cfg, cfg.Parallelism)
+
+		records, err = history.Load(historyPath)
+		if err != nil {
+			return err
+		}
+		digest := healthcheck.Digest(check, history.AssessmentRuns(records, check.Question), summary)
+		postAt, err := slack.Deliver(token, check.SlackRoom, digest, cfg.Slack)
+		if err != nil {
+			fmt.Printf("⚠️ %s: failed to post digest to %s: %v\n", check.Name, check.SlackRoom, err)
+			continue
+		}
+		if !postAt.IsZero() {
+			fmt.Printf("✓ %s: digest scheduled for %s at %s (quiet hours)\n", check.Name, check.SlackRoom, postAt.Local().Format("Mon 15:04"))
+			continue
+		}
+		fmt.Printf("✓ %s: digest posted to %s\n", check.Name, check.SlackRoom)
+	}
+	return nil
+}
+
+// assessmentHistory returns the recorded runs of an assessment question, oldest first.
+func assessmentHistory(question string) ([]history.Record, error) {
+	path, err := config.HistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	records, err := history.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return history.AssessmentRuns(records, question), nil
+}
+
+// campaignProgress loads every PR opened under prTitle across recorded runs
+// and checks which have been merged.
+func campaignProgress(prTitle string) (history.Campaign, error) {
+	path, err := config.HistoryPath()
+	if err != nil {
+		return history.Campaign{}, err
+	}
+	records, err := history.Load(path)
+	if err != nil {
+		return history.Campaign{}, err
+	}
+
+	var states []history.PRState
+	var skipped []string
+	for _, url := range history.CampaignPRs(records, prTitle) {
+		createdAt, mergedAt, err := gitHub.FetchPullRequestTimes(context.Background(), url)
+		if err != nil {
+			skipped = append(skipped, err.Error())
+			continue
+		}
+		states = append(states, history.PRState{URL: url, CreatedAt: createdAt, MergedAt: mergedAt})
 	}
 
+	campaign := history.BuildCampaign(prTitle, states, time.Now())
+	campaign.Skipped = skipped
+	return campaign, nil
 }
 
 // AssessJob represents a single project assessment job.
@@ -570,8 +2526,11 @@ type AssessJob struct {
 	AITool       *config.AITool
 	AppConfig    config.Config
 	Prompt       string
-	IgnoreFiles  []string
+	Structured   bool
+	FlakyRuns    int // test suite runs when detecting flaky tests, 0 otherwise
+	TempChanges  []config.TempChange
 	UpdateStatus func(status string)
+	LogLine      func(line string) // receives the AI tool's output as it runs
 }
 
 // AssessResult represents the result of assessing a single project.
@@ -580,12 +2539,21 @@ type AssessResult struct {
 	Success bool
 	Error   error
 	Finding string
+
+	// Assessment is the structured answer, when one was asked for and given.
+	Assessment *ai.Assessment
+
+	// FlakyTests are the suspected flaky tests, when detecting them.
+	FlakyTests []flaky.Test
+
+	// Usage is the tokens and cost the AI tool reported.
+	Usage ai.Usage
 }
 
 func assessProject(job AssessJob) AssessResult {
 	ctx := job.Ctx
 	project := job.Project
-	targetPath := fmt.Sprintf("%s/%s", reposDir, project.Repo)
+	targetPath := workDir(project)
 
 	cleanup := func() {
 		filesystem.DeleteDirectory(targetPath)
@@ -598,15 +2566,13 @@ func assessProject(job AssessJob) AssessResult {
 	// Clone
 	job.UpdateStatus("Cloning...")
 	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
-		repoURL := fmt.Sprintf("git@github.com:%s/%s.git", job.AppConfig.GitHub.Organization, project.Repo)
-		cmd := exec.CommandContext(ctx, "git", "clone", repoURL, targetPath)
-		output, err := cmd.CombinedOutput()
-		if err != nil {
+		repoURL := job.AppConfig.GitHub.CloneURL(project.Repo)
+		if err := gitHub.Clone(ctx, repoURL, targetPath); err != nil {
 			cleanup()
 			if ctx.Err() != nil {
 				return AssessResult{Project: project, Error: errCancelled}
 			}
-			return AssessResult{Project: project, Error: fmt.Errorf("clone failed: %v (%s)", err, string(output))}
+			return AssessResult{Project: project, Error: err}
 		}
 	}
 
@@ -615,40 +2581,213 @@ func assessProject(job AssessJob) AssessResult {
 		return AssessResult{Project: project, Error: errCancelled}
 	}
 
-	// Remove agent instruction files before running assessment
-	if len(job.IgnoreFiles) > 0 {
-		ai.RemoveInstructionFiles(ctx, targetPath, job.IgnoreFiles)
+	// Make the run's temporary file changes before running assessment; the
+	// clone is removed afterwards, so they need no undoing
+	if len(job.TempChanges) > 0 {
+		if _, err := ai.ApplyTempChanges(ctx, targetPath, job.TempChanges); err != nil {
+			cleanup()
+			return AssessResult{Project: project, Error: fmt.Errorf("temporary file changes failed: %v", err)}
+		}
 	}
 
 	// Assess
 	job.UpdateStatus("Running assessment...")
-	finding, err := ai.Assess(ctx, job.AITool, job.Prompt, targetPath, project.Repo)
+	prompt := job.Prompt
+	if job.Structured {
+		prompt += ai.StructuredAssessmentPrompt
+	}
+	if job.FlakyRuns > 0 {
+		job.UpdateStatus(fmt.Sprintf("Running tests %d times...", job.FlakyRuns))
+		prompt += flaky.Prompt(job.FlakyRuns)
+	}
+	output, err := aiAgent.Assess(ctx, job.AITool, prompt, targetPath, lastSession(project, job.AITool), project.Repo, job.AppConfig.ToolTimeout(job.AITool), job.LogLine)
+	rememberSession(project, job.AITool, output)
+	usage, finding := ai.ParseUsage(output, job.AITool.Pricing)
 	if err != nil {
 		cleanup()
 		if ctx.Err() != nil {
-			return AssessResult{Project: project, Error: errCancelled}
+			return AssessResult{Project: project, Error: errCancelled, Usage: usage}
 		}
-		return AssessResult{Project: project, Error: fmt.Errorf("assessment failed: %v", err)}
+		if errors.Is(err, ai.ErrTimeout) {
+			return AssessResult{Project: project, Error: fmt.Errorf("assessment %w", err), Usage: usage}
+		}
+		return AssessResult{Project: project, Error: fmt.Errorf("assessment failed: %v", err), Usage: usage}
 	}
 
 	// Cleanup
 	job.UpdateStatus("Cleaning up...")
 	cleanup()
 
-	return AssessResult{Project: project, Success: true, Finding: strings.TrimSpace(finding)}
+	result := AssessResult{Project: project, Success: true, Finding: strings.TrimSpace(finding), Usage: usage}
+	if job.Structured {
+		if assessment, ok := ai.ParseAssessment(finding); ok {
+			result.Assessment = &assessment
+		}
+	}
+	if job.FlakyRuns > 0 {
+		result.FlakyTests = flaky.Parse(finding)
+	}
+	return result
+}
+
+// issueTemplatesFor returns the dashboard's IssueTemplates, reading a repo's
+// issue templates in org.
+func issueTemplatesFor(org string) func(config.Project) ([]issuetemplate.Template, error) {
+	return func(project config.Project) ([]issuetemplate.Template, error) {
+		return gitHub.IssueTemplates(context.Background(), org, project.Repo)
+	}
+}
+
+// createIssuesWithSender opens an issue in each selected repo, up to
+// parallelism at a time, reporting each repo's progress and result. Services
+// and paths of one monorepo share a single issue, reported against each.
+func createIssuesWithSender(sender *input.StatusSender, selectedProjects []config.Project, setup *input.WizardResult, appCfg config.Config, parallelism int) {
+	started := time.Now()
+	sender, finishEvents := runEvents(sender, setup, appCfg.Webhook, len(selectedProjects))
+	defer finishEvents()
+	defer watchRateLimits(sender)()
+
+	opts := git.IssueOptions{
+		Labels:    setup.PRLabels,
+		Milestone: setup.PRMilestone,
+	}
+
+	// A typo in an assignee would otherwise fail every issue, so they are
+	// checked against the first repo before any issue is opened
+	if len(setup.Assignees) > 0 && len(selectedProjects) > 0 {
+		sender.PostStatus("Checking assignees...")
+		assignees, err := gitHub.ResolveAssignees(context.Background(), appCfg.GitHub.Organization, selectedProjects[0].Repo, setup.Assignees)
+		if err != nil {
+			for _, project := range selectedProjects {
+				sender.Done(input.ProjectDoneMsg{Repo: project.Key(), Status: fmt.Sprintf("Failed ⚠️ %v", err), Error: err})
+			}
+			return
+		}
+		opts.Assignees = assignees
+	}
+
+	// An issue belongs to a repo, so every key selected in it is reported
+	// with the one issue
+	var repoNames []string
+	keysByRepo := make(map[string][]string)
+	for _, project := range selectedProjects {
+		if _, ok := keysByRepo[project.Repo]; !ok {
+			repoNames = append(repoNames, project.Repo)
+		}
+		keysByRepo[project.Repo] = append(keysByRepo[project.Repo], project.Key())
+	}
+
+	numWorkers := min(max(parallelism, 1), len(repoNames))
+	limiter := newLimiter(sender, appCfg.AdaptiveParallelism, numWorkers)
+
+	jobCh := make(chan string, len(repoNames))
+	for _, name := range repoNames {
+		jobCh <- name
+	}
+	close(jobCh)
+
+	var mu sync.Mutex
+	records := make(map[string]history.RepoRecord)
+
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobCh {
+				keys := keysByRepo[name]
+				updateStatus := func(status string) {
+					for _, key := range keys {
+						sender.UpdateStatus(key, status)
+					}
+				}
+				ctx, cancel := context.WithCancel(context.Background())
+				if sender.CancelRegistry != nil {
+					for _, key := range keys {
+						sender.CancelRegistry.Register(key, cancel)
+					}
+				}
+				transcript := &git.Transcript{}
+				ctx = git.WithTranscript(ctx, transcript)
+
+				var url string
+				err := limiter.Acquire(ctx, updateStatus)
+				if err == nil {
+					updateStatus("Creating issue...")
+					url, err = gitHub.CreateIssue(ctx, appCfg.GitHub.Organization, name, setup.PRTitle, setup.Prompt, opts)
+					limiter.Release(err != nil && ctx.Err() == nil)
+				}
+				cancelled := ctx.Err() != nil
+				cancel()
+
+				msg := input.ProjectDoneMsg{GitHubLog: transcript.API()}
+				var record history.RepoRecord
+				switch {
+				case err == nil:
+					msg.Success = true
+					msg.PRURL = url
+					msg.Status = fmt.Sprintf("Completed ✅ Issue: \033]8;;%s\033\\%s\033]8;;\033\\", url, url)
+					record.Outcome = "succeeded"
+					record.IssueURL = url
+				case cancelled:
+					msg.Error = errCancelled
+					msg.Status = "Cancelled ✗"
+					record.Outcome = "cancelled"
+				default:
+					msg.Error = err
+					msg.Status = fmt.Sprintf("Failed ⚠️ %v", err)
+					record.Outcome = "failed"
+				}
+				for _, key := range keys {
+					record.Repo = key
+					msg.Repo = key
+					mu.Lock()
+					records[key] = record
+					mu.Unlock()
+					sender.Done(msg)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var repos []history.RepoRecord
+	for _, project := range selectedProjects {
+		record, ok := records[project.Key()]
+		if !ok {
+			record = history.RepoRecord{Repo: project.Key(), Outcome: "cancelled"}
+		}
+		repos = append(repos, record)
+	}
+	recordRun(setup, started, repos)
 }
 
 func assessReposWithSender(sender *input.StatusSender, selectedProjects []config.Project, setup *input.WizardResult, appCfg config.Config, parallelism int) {
+	started := time.Now()
+	sender, finishEvents := runEvents(sender, setup, appCfg.Webhook, len(selectedProjects))
+	defer finishEvents()
 	filesystem.CreateWorkspace()
 
-	// Rewrite prompt for per-project use
-	sender.PostStatus("Rewriting question for per-project assessment...")
-	rewrittenPrompt, err := ai.RewritePromptForProject(context.Background(), setup.AITool, setup.Prompt)
-	if err != nil {
-		sender.PostStatus(fmt.Sprintf("⚠️ Failed to rewrite prompt, using original: %v", err))
-		rewrittenPrompt = setup.Prompt
-	} else {
-		sender.PostStatus(fmt.Sprintf("✓ Rewritten question: %s", rewrittenPrompt))
+	// Rewrite prompt for per-project use; the flaky test preset is already
+	// written for a single project
+	rewrittenPrompt := setup.Prompt
+	if !setup.FlakyTests {
+		sender.PostStatus("Rewriting question for per-project assessment...")
+		rewritten, err := aiAgent.RewritePromptForProject(context.Background(), setup.AITool, setup.Prompt)
+		if err != nil {
+			sender.PostStatus(fmt.Sprintf("⚠️ Failed to rewrite prompt, using original: %v", err))
+		} else {
+			rewrittenPrompt = rewritten
+			sender.PostStatus(fmt.Sprintf("✓ Rewritten question: %s", rewrittenPrompt))
+		}
+	}
+
+	flakyRuns := 0
+	if setup.FlakyTests {
+		flakyRuns = appCfg.FlakyTestRuns
+		if flakyRuns <= 0 {
+			flakyRuns = flaky.DefaultRuns
+		}
 	}
 
 	checkpoint := parallelism
@@ -660,22 +2799,20 @@ func assessReposWithSender(sender *input.StatusSender, selectedProjects []config
 	for _, project := range selectedProjects {
 		ctx, cancel := context.WithCancel(context.Background())
 		if sender.CancelRegistry != nil {
-			sender.CancelRegistry.Register(project.Repo, cancel)
+			sender.CancelRegistry.Register(project.Key(), cancel)
 		} else {
 			cancel()
 			ctx = context.Background()
 		}
-		var ignoreFiles []string
-		if setup.IgnoreAgentInstructions {
-			ignoreFiles = appCfg.AgentInstructions
-		}
 		jobs = append(jobs, AssessJob{
 			Ctx:         ctx,
 			Project:     project,
 			AITool:      setup.AITool,
 			AppConfig:   appCfg,
 			Prompt:      rewrittenPrompt,
-			IgnoreFiles: ignoreFiles,
+			Structured:  setup.StructuredAssessment,
+			FlakyRuns:   flakyRuns,
+			TempChanges: tempChanges(setup, appCfg),
 		})
 	}
 
@@ -683,10 +2820,16 @@ func assessReposWithSender(sender *input.StatusSender, selectedProjects []config
 	if numWorkers > len(jobs) {
 		numWorkers = len(jobs)
 	}
+	limiter := newLimiter(sender, appCfg.AdaptiveParallelism, numWorkers)
 
 	var mu sync.Mutex
 	findings := make(map[string]string)
+	assessments := make(map[string]ai.Assessment)
+	flakyTests := make(map[string][]flaky.Test)
+	outcomes := make(map[string]string)
+	usages := make(map[string]ai.Usage)
 
+	pauses := newCheckpoints(sender, appCfg.Budget)
 	for batchStart := 0; batchStart < len(jobs); batchStart += checkpoint {
 		batchEnd := batchStart + checkpoint
 		if batchEnd > len(jobs) {
@@ -707,24 +2850,63 @@ func assessReposWithSender(sender *input.StatusSender, selectedProjects []config
 			go func() {
 				defer wg.Done()
 				for job := range jobCh {
-					repo := job.Project.Repo
+					repo := job.Project.Key()
 					job.UpdateStatus = func(status string) {
 						sender.UpdateStatus(repo, status)
 					}
-					result := assessProject(job)
+					job.LogLine = func(line string) {
+						sender.Log(repo, line)
+					}
+					job.Prompt = job.Project.WithPromptNotes(job.Prompt)
+					var result AssessResult
+					if err := limiter.Acquire(job.Ctx, job.UpdateStatus); err != nil {
+						result = AssessResult{Project: job.Project, Error: errCancelled}
+					} else {
+						result = assessProject(job)
+						limiter.Release(!result.Success && result.Error != errCancelled)
+					}
 
 					var status string
+					outcome := "failed"
 					if result.Success {
 						mu.Lock()
 						findings[repo] = result.Finding
+						if result.Assessment != nil {
+							assessments[repo] = *result.Assessment
+						}
+						if job.FlakyRuns > 0 {
+							flakyTests[repo] = result.FlakyTests
+						}
 						mu.Unlock()
 						status = "Assessed ✅"
+						if result.Assessment != nil {
+							status = fmt.Sprintf("Assessed ✅ %s (%d)", result.Assessment.Status, result.Assessment.Score)
+						} else if job.FlakyRuns > 0 {
+							status = fmt.Sprintf("Assessed ✅ 🧪 %d suspected flaky", len(result.FlakyTests))
+						}
+						outcome = "succeeded"
 					} else if result.Error == errCancelled {
 						status = "Cancelled ✗"
+						outcome = "cancelled"
+					} else if errors.Is(result.Error, ai.ErrTimeout) {
+						status = fmt.Sprintf("Failed ⏱ %v", result.Error)
 					} else {
 						status = fmt.Sprintf("Failed ⚠️ %v", result.Error)
 					}
-					sender.Done(repo, status, result.Success, false, "", result.Error, "")
+					if !result.Usage.IsZero() {
+						status += " 💲 " + result.Usage.String()
+					}
+					mu.Lock()
+					outcomes[repo] = outcome
+					usages[repo] = result.Usage
+					mu.Unlock()
+					sender.Done(input.ProjectDoneMsg{
+						Repo:    repo,
+						Status:  status,
+						Success: result.Success,
+						Error:   result.Error,
+						Usage:   result.Usage,
+					})
 				}
 			}()
 		}
@@ -735,8 +2917,12 @@ func assessReposWithSender(sender *input.StatusSender, selectedProjects []config
 		close(jobCh)
 		wg.Wait()
 
-		if batchEnd < len(jobs) && sender.ResumeCh != nil {
-			newPrompt := <-sender.ResumeCh
+		if batchEnd < len(jobs) {
+			var spent ai.Usage
+			for _, usage := range usages {
+				spent = spent.Add(usage)
+			}
+			newPrompt := pauses.wait(spent.CostUSD)
 			if newPrompt != "" {
 				for i := batchEnd; i < len(jobs); i++ {
 					jobs[i].Prompt = newPrompt
@@ -745,18 +2931,51 @@ func assessReposWithSender(sender *input.StatusSender, selectedProjects []config
 		}
 	}
 
+	var repos []history.RepoRecord
+	for _, project := range selectedProjects {
+		outcome, ok := outcomes[project.Key()]
+		if !ok {
+			outcome = "cancelled"
+		}
+		record := history.RepoRecord{Repo: project.Key(), Outcome: outcome, Usage: usageRecord(usages[project.Key()])}
+		if assessment, ok := assessments[project.Key()]; ok {
+			record.Status = assessment.Status
+			record.Score = &assessment.Score
+		}
+		repos = append(repos, record)
+	}
+	recordRun(setup, started, repos)
+
 	// Summarize findings
 	if len(findings) > 0 {
 		sender.PostStatus("Summarizing findings across all projects...")
-		summary, err := ai.SummarizeFindings(context.Background(), setup.AITool, findings)
+		summary, err := aiAgent.SummarizeFindings(context.Background(), setup.AITool, findings)
 		if err != nil {
 			sender.PostStatus(fmt.Sprintf("⚠️ Failed to summarize findings: %v", err))
 			summary = "Summary generation failed."
 		}
-		sender.AssessmentResult(summary, findings)
+		if setup.FlakyTests {
+			summary = flaky.Report(flakyTests) + "\n\n" + summary
+			saveFlakyTests(sender, flakyTests)
+		}
+		sender.AssessmentResult(summary, findings, assessments)
 	} else {
-		sender.AssessmentResult("No projects were successfully assessed.", findings)
+		sender.AssessmentResult("No projects were successfully assessed.", findings, assessments)
+	}
+}
+
+// saveFlakyTests stores a detection run's results so a follow-up run can fix
+// them with -flaky.
+func saveFlakyTests(sender *input.StatusSender, byRepo map[string][]flaky.Test) {
+	path, err := config.FlakyTestsPath()
+	if err == nil {
+		err = flaky.Save(path, byRepo)
+	}
+	if err != nil {
+		sender.PostStatus(fmt.Sprintf("⚠️ Failed to save flaky tests: %v", err))
+		return
 	}
+	sender.PostStatus(fmt.Sprintf("✓ Flaky tests saved; run copycat -flaky %s to fix them", path))
 }
 
 // lastLines returns the last n non-empty lines from s.
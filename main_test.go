@@ -0,0 +1,918 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/saltpay/copycat/v2/internal/ai/aitest"
+	"github.com/saltpay/copycat/v2/internal/config"
+	"github.com/saltpay/copycat/v2/internal/git"
+	"github.com/saltpay/copycat/v2/internal/git/gittest"
+	"github.com/saltpay/copycat/v2/internal/history"
+	"github.com/saltpay/copycat/v2/internal/input"
+	"github.com/saltpay/copycat/v2/internal/issuetemplate"
+	"github.com/saltpay/copycat/v2/internal/jira"
+)
+
+// harness is a dashboard wired to the real workflows, with GitHub and the AI
+// tool faked. Runs happen in a temporary directory. configure, if given,
+// adjusts the default config first.
+type harness struct {
+	*input.Headless
+	github *gittest.Fake
+	agent  *aitest.Fake
+}
+
+func newHarness(t *testing.T, projects []config.Project, github *gittest.Fake, agent *aitest.Fake, configure ...func(*config.Config)) *harness {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	previousGitHub, previousAgent := gitHub, aiAgent
+	gitHub, aiAgent = github, agent
+	t.Cleanup(func() { gitHub, aiAgent = previousGitHub, previousAgent })
+
+	appCfg := config.DefaultConfig("fake-org")
+	appCfg.Tools = []config.AITool{{Name: "fake", Command: "fake"}}
+	appCfg.Default = "fake"
+	appCfg.Parallelism = 2
+	for _, fn := range configure {
+		fn(appCfg)
+	}
+
+	h := input.NewHeadless(input.DashboardConfig{
+		Projects:      projects,
+		AIToolsConfig: &appCfg.AIToolsConfig,
+		GitHubConfig:  appCfg.GitHub,
+		AppConfig:     *appCfg,
+		Parallelism:   appCfg.Parallelism,
+		FetchProjects: func(discovery config.DiscoveryFilter) ([]config.Project, error) {
+			githubCfg := appCfg.GitHub
+			githubCfg.Discovery = discovery
+			return github.FetchRepositories(githubCfg)
+		},
+		ProcessRepos: func(sender *input.StatusSender, selected []config.Project, setup *input.WizardResult) {
+			processReposWithSender(sender, selected, setup, *appCfg, appCfg.Parallelism, nil)
+		},
+		AssessRepos: func(sender *input.StatusSender, selected []config.Project, setup *input.WizardResult) {
+			assessReposWithSender(sender, selected, setup, *appCfg, appCfg.Parallelism)
+		},
+		CreateIssues: func(sender *input.StatusSender, selected []config.Project, setup *input.WizardResult) {
+			createIssuesWithSender(sender, selected, setup, *appCfg, appCfg.Parallelism)
+		},
+		IssueTemplates:    issueTemplatesFor(appCfg.GitHub.Organization),
+		WatchChecks:       watchChecksFor(appCfg.CIChecks),
+		CreateJiraTickets: createJiraTicketsFor(appCfg.Jira),
+	}, 120, 40)
+	return &harness{Headless: h, github: github, agent: agent}
+}
+
+// waitForText waits until the dashboard shows text.
+func (h *harness) waitForText(t *testing.T, text string) {
+	t.Helper()
+	if err := h.WaitFor(10*time.Second, func(view string) bool { return strings.Contains(view, text) }); err != nil {
+		t.Fatalf("waiting for %q: %v", text, err)
+	}
+}
+
+var harnessProjects = []config.Project{{Repo: "ledger-service"}, {Repo: "payments-api"}}
+
+func TestRunCodeChange(t *testing.T) {
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}}
+	agent := &aitest.Fake{
+		Files:  map[string]string{"CHANGELOG.md": "Bumped the timeout.\n"},
+		Output: "Bumped the timeout.",
+		Errors: map[string]error{"ledger-service": errors.New("exit status 1")},
+	}
+	h := newHarness(t, harnessProjects, github, agent)
+
+	// Select every project, then walk through the wizard
+	h.Press("a", "enter")
+	h.waitForText(t, "Perform Changes Locally")
+	h.Press("enter", "enter") // action, then a new branch per repo
+	h.Type("Bump the timeout")
+	h.Press("enter")
+	h.Type("Raise the HTTP timeout to 30s")
+	h.Press("ctrl+s", "enter") // keep agent instructions
+	h.waitForText(t, "Processing complete!")
+
+	prs := github.PullRequests()
+	if len(prs) != 1 || prs[0].Repo != "payments-api" || prs[0].Title != "Bump the timeout" {
+		t.Fatalf("expected one PR for payments-api, got %+v", prs)
+	}
+	if !strings.Contains(prs[0].Body, "Bumped the timeout.") {
+		t.Errorf("expected the AI output in the PR body, got %q", prs[0].Body)
+	}
+	pushes := github.Pushes()
+	if len(pushes) != 1 || pushes[0].Branch != prs[0].Branch || strings.Join(pushes[0].Files, ",") != "CHANGELOG.md" {
+		t.Errorf("unexpected pushes %+v", pushes)
+	}
+
+	result := h.Result()
+	if result == nil || result.Action != "local" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if r := result.ProcessResults["payments-api"]; !r.Success || r.PRURL != prs[0].URL {
+		t.Errorf("unexpected payments-api result %+v", r)
+	}
+	if r := result.ProcessResults["ledger-service"]; r.Success || !strings.Contains(r.Status, "exit status 1") {
+		t.Errorf("expected ledger-service to fail with the tool's error, got %+v", r)
+	}
+	for _, call := range agent.Calls() {
+		if call.Method == "VibeCode" && call.Prompt != "Raise the HTTP timeout to 30s" {
+			t.Errorf("unexpected prompt %q", call.Prompt)
+		}
+	}
+}
+
+func TestRunActionsWithoutAI(t *testing.T) {
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}}
+	agent := &aitest.Fake{}
+	h := newHarness(t, harnessProjects, github, agent, func(cfg *config.Config) {
+		cfg.Actions = []config.ActionPipeline{{Name: "docs", Steps: []config.Action{
+			{Type: config.ActionReplace, Files: []string{"README.md"}, Search: "# Service", Replace: "# Payments Service"},
+			{Type: config.ActionAddFile, Path: "CODEOWNERS", Content: "* @acme/payments\n"},
+		}}}
+	})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Run Actions")
+	h.Press("down", "down", "down", "down", "down", "enter") // the only pipeline is picked for you
+	h.Press("enter")                                         // a new branch per repo
+	h.Type("Tidy the docs")
+	h.Press("enter", "ctrl+s") // no prompt: actions only
+	h.waitForText(t, "Processing complete!")
+
+	prs := github.PullRequests()
+	if len(prs) != 2 {
+		t.Fatalf("expected a PR per repo, got %+v", prs)
+	}
+	if !strings.Contains(prs[0].Body, "Applies the docs actions:") || !strings.Contains(prs[0].Body, "- Wrote CODEOWNERS") {
+		t.Errorf("expected the actions in the PR body, got %q", prs[0].Body)
+	}
+	for _, push := range github.Pushes() {
+		if strings.Join(push.Files, ",") != "CODEOWNERS,README.md" {
+			t.Errorf("unexpected files pushed to %s: %v", push.Repo, push.Files)
+		}
+	}
+	if calls := agent.Calls(); len(calls) != 0 {
+		t.Errorf("expected no AI tool calls, got %+v", calls)
+	}
+}
+
+func TestRunHooks(t *testing.T) {
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}}
+	agent := &aitest.Fake{Files: map[string]string{"CHANGELOG.md": "Bumped the timeout.\n"}, Output: "Bumped the timeout."}
+	projects := []config.Project{
+		{Repo: "ledger-service", Hooks: &config.Hooks{Post: "echo 2 tests failed; exit 1"}},
+		{Repo: "payments-api"},
+	}
+	h := newHarness(t, projects, github, agent, func(cfg *config.Config) {
+		cfg.Hooks = config.Hooks{Pre: "echo 1.2.3 > VERSION", Post: "grep -q timeout CHANGELOG.md"}
+	})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Perform Changes Locally")
+	h.Press("enter", "enter")
+	h.Type("Bump the timeout")
+	h.Press("enter")
+	h.Type("Raise the HTTP timeout to 30s")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "Processing complete!")
+
+	prs := github.PullRequests()
+	if len(prs) != 1 || prs[0].Repo != "payments-api" {
+		t.Fatalf("expected one PR for payments-api, got %+v", prs)
+	}
+	if pushes := github.Pushes(); len(pushes) != 1 || strings.Join(pushes[0].Files, ",") != "CHANGELOG.md,VERSION" {
+		t.Errorf("expected the AI's and the pre hook's files to be pushed, got %+v", pushes)
+	}
+	r := h.Result().ProcessResults["ledger-service"]
+	if r.Success || !strings.Contains(r.Status, "post hook failed") || !strings.Contains(r.Status, "2 tests failed") {
+		t.Errorf("expected ledger-service to fail with the post hook's output, got %+v", r)
+	}
+}
+
+func TestRunVerifyCommandOpensDraft(t *testing.T) {
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}}
+	agent := &aitest.Fake{Files: map[string]string{"CHANGELOG.md": "Bumped the timeout.\n"}, Output: "Bumped the timeout."}
+	projects := []config.Project{
+		{Repo: "ledger-service", VerifyCommand: "echo 3 tests failed; exit 1"},
+		{Repo: "payments-api"},
+	}
+	h := newHarness(t, projects, github, agent, func(cfg *config.Config) {
+		cfg.VerifyCommand = "test -f CHANGELOG.md"
+	})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Perform Changes Locally")
+	h.Press("enter", "enter")
+	h.Type("Bump the timeout")
+	h.Press("enter")
+	h.Type("Raise the HTTP timeout to 30s")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "If Verification Fails")
+	h.Press("down", "enter") // open a draft PR with a note
+	h.waitForText(t, "Processing complete!")
+
+	prs := github.PullRequests()
+	if len(prs) != 2 {
+		t.Fatalf("expected a PR per repo, got %+v", prs)
+	}
+	for _, pr := range prs {
+		failed := pr.Repo == "ledger-service"
+		if pr.Draft != failed || strings.Contains(pr.Body, "3 tests failed") != failed {
+			t.Errorf("expected only ledger-service's PR to be a draft noting the failure, got %+v", pr)
+		}
+	}
+}
+
+func TestRunBlocksChangesRejectedByPolicyReview(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Repo string `json:"repo"`
+			Diff string `json:"diff"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Repo == "ledger-service" && strings.Contains(req.Diff, "CHANGELOG.md") {
+			_, _ = w.Write([]byte(`{"approved":false,"reasons":"ledger changes need a change ticket"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"approved":true}`))
+	}))
+	defer server.Close()
+
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}}
+	agent := &aitest.Fake{Files: map[string]string{"CHANGELOG.md": "Bumped the timeout.\n"}, Output: "Bumped the timeout."}
+	h := newHarness(t, harnessProjects, github, agent, func(cfg *config.Config) {
+		cfg.PolicyReview = config.PolicyReview{URL: server.URL}
+	})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Perform Changes Locally")
+	h.Press("enter", "enter")
+	h.Type("Bump the timeout")
+	h.Press("enter")
+	h.Type("Raise the HTTP timeout to 30s")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "Processing complete!")
+
+	prs := github.PullRequests()
+	if len(prs) != 1 || prs[0].Repo != "payments-api" {
+		t.Fatalf("expected a PR only for the approved repo, got %+v", prs)
+	}
+	if pushes := github.Pushes(); len(pushes) != 1 {
+		t.Errorf("expected the rejected changes not to be pushed, got %+v", pushes)
+	}
+	if r := h.Result().ProcessResults["ledger-service"]; r.Success || !strings.Contains(r.Status, "policy review") {
+		t.Errorf("expected ledger-service to be held back by the policy review, got %+v", r)
+	}
+}
+
+func TestRunVerifiesAgainWhenBaseMoves(t *testing.T) {
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}, Moved: map[string]bool{"ledger-service": true}}
+	agent := &aitest.Fake{Files: map[string]string{"CHANGELOG.md": "Bumped the timeout.\n"}, Output: "Bumped the timeout."}
+	runs := filepath.Join(t.TempDir(), "runs")
+	h := newHarness(t, harnessProjects, github, agent, func(cfg *config.Config) {
+		cfg.VerifyCommand = "basename \"$PWD\" >> " + runs
+	})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Perform Changes Locally")
+	h.Press("enter", "enter")
+	h.Type("Bump the timeout")
+	h.Press("enter")
+	h.Type("Raise the HTTP timeout to 30s")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "If Verification Fails")
+	h.Press("enter")
+	h.waitForText(t, "Processing complete!")
+
+	if prs := github.PullRequests(); len(prs) != 2 {
+		t.Fatalf("expected a PR per repo, got %+v", prs)
+	}
+	content, err := os.ReadFile(runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for _, line := range strings.Fields(string(content)) {
+		counts[line]++
+	}
+	if counts["ledger-service"] != 2 || counts["payments-api"] != 1 {
+		t.Errorf("expected the verify command to run again only where the base moved, got %v", counts)
+	}
+	if r := h.Result().ProcessResults["ledger-service"]; !strings.Contains(r.Status, "base branch moved") {
+		t.Errorf("expected a warning that the base moved, got %+v", r)
+	}
+}
+
+func TestRunRequestsReviewsWithoutSlackRoom(t *testing.T) {
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}}
+	agent := &aitest.Fake{Files: map[string]string{"go.sum": "bumped\n"}, Output: "Bumped the dependencies."}
+	projects := []config.Project{
+		{Repo: "ledger-service", SlackRoom: "#team-ledger", Reviewers: []string{"ledger-bot"}},
+		{Repo: "payments-api", Reviewers: []string{"@alice", "fake-org/payments"}},
+	}
+	h := newHarness(t, projects, github, agent, func(cfg *config.Config) {
+		cfg.GitHub.NotifyReviewers = true
+	})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Perform Changes Locally")
+	h.Press("enter", "enter")
+	h.Type("Bump dependencies")
+	h.Press("enter")
+	h.Type("Bump every dependency to its latest patch release")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "Processing complete!")
+
+	for _, pr := range github.PullRequests() {
+		if pr.Repo == "ledger-service" {
+			if len(pr.Reviewers) != 0 || strings.Contains(pr.Body, "cc @") {
+				t.Errorf("expected the repo with a Slack room to be left to Slack, got %+v", pr)
+			}
+			continue
+		}
+		if !slices.Equal(pr.Reviewers, []string{"@alice", "fake-org/payments"}) || !strings.Contains(pr.Body, "\n\ncc @alice @fake-org/payments") {
+			t.Errorf("expected reviews requested and mentioned on %s, got %+v", pr.Repo, pr)
+		}
+	}
+}
+
+func TestRunLabelsPRs(t *testing.T) {
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}}
+	agent := &aitest.Fake{Files: map[string]string{"go.sum": "bumped\n"}, Output: "Bumped the dependencies."}
+	h := newHarness(t, harnessProjects, github, agent, func(cfg *config.Config) {
+		cfg.PRMetadata = config.PRMetadata{Labels: []string{"automated"}}
+	})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Perform Changes Locally")
+	h.Press("enter", "enter")
+	h.Type("Bump dependencies")
+	h.Press("ctrl+l")
+	h.Type(", dependencies, automated")
+	h.Press("ctrl+l")
+	h.Type("Q3 cleanup")
+	h.Press("enter")
+	h.waitForText(t, "✓ Labels: automated, dependencies")
+	h.Type("Bump every dependency to its latest patch release")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "Processing complete!")
+
+	prs := github.PullRequests()
+	if len(prs) != len(harnessProjects) {
+		t.Fatalf("expected a PR per repo, got %+v", prs)
+	}
+	for _, pr := range prs {
+		if pr.Title != "Bump dependencies" || !slices.Equal(pr.Labels, []string{"automated", "dependencies"}) || pr.Milestone != "Q3 cleanup" {
+			t.Errorf("expected the labels and milestone from the wizard, got %+v", pr)
+		}
+	}
+}
+
+func TestRunHandlesEmptyRepos(t *testing.T) {
+	for _, mode := range []string{config.EmptyReposSkip, config.EmptyReposScaffold} {
+		t.Run(mode, func(t *testing.T) {
+			github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}, Empty: map[string]bool{"ledger-service": true}}
+			agent := &aitest.Fake{Files: map[string]string{"go.sum": "bumped\n"}, Output: "Bumped the dependencies."}
+			h := newHarness(t, harnessProjects, github, agent, func(cfg *config.Config) {
+				cfg.EmptyRepos = mode
+			})
+
+			h.Press("a", "enter")
+			h.waitForText(t, "Perform Changes Locally")
+			h.Press("enter", "enter")
+			h.Type("Bump dependencies")
+			h.Press("enter")
+			h.Type("Bump every dependency to its latest patch release")
+			h.Press("ctrl+s", "enter")
+			h.waitForText(t, "Processing complete!")
+
+			var repos []string
+			for _, pr := range github.PullRequests() {
+				repos = append(repos, pr.Repo)
+			}
+			slices.Sort(repos)
+			if mode == config.EmptyReposSkip {
+				if !slices.Equal(repos, []string{"payments-api"}) || !strings.Contains(h.View(), "uninitialized repo") {
+					t.Errorf("expected the empty repo to be skipped as uninitialized, got PRs for %v", repos)
+				}
+				return
+			}
+			pushes := github.Pushes()
+			if !slices.Equal(repos, []string{"ledger-service", "payments-api"}) || !slices.ContainsFunc(pushes, func(p gittest.Push) bool {
+				return p.Repo == "ledger-service" && p.Message == "Initial commit"
+			}) {
+				t.Errorf("expected the empty repo to get an initial commit and a PR, got PRs for %v and pushes %+v", repos, pushes)
+			}
+		})
+	}
+}
+
+func TestRunEnablesAutoMerge(t *testing.T) {
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}}
+	agent := &aitest.Fake{Files: map[string]string{"go.sum": "bumped\n"}, Output: "Bumped the dependencies."}
+	h := newHarness(t, harnessProjects, github, agent)
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Perform Changes Locally")
+	h.Press("enter", "enter")
+	h.Type("Bump dependencies")
+	h.Press("tab", "enter") // enable auto-merge
+	h.Type("Bump every dependency to its latest patch release")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "Processing complete!")
+
+	prs := github.PullRequests()
+	if len(prs) != 2 {
+		t.Fatalf("expected a PR per repo, got %+v", prs)
+	}
+	for _, pr := range prs {
+		if !pr.AutoMerge {
+			t.Errorf("expected auto-merge on %s", pr.Repo)
+		}
+	}
+}
+
+func TestRunUpdatesOpenPR(t *testing.T) {
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}}
+	agent := &aitest.Fake{Files: map[string]string{"go.sum": "bumped\n"}, Output: "Bumped the dependencies."}
+	existing, err := github.CreatePullRequest(context.Background(), config.Project{Repo: "payments-api"}, "", "copycat-deps", "Bump deps", "Old description", git.PullRequestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := newHarness(t, harnessProjects, github, agent)
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Perform Changes Locally")
+	h.Press("enter", "down", "enter") // reuse the branch if it exists
+	h.Type("copycat-deps")
+	h.Press("tab", "enter") // update open PRs
+	h.Type("Bump dependencies")
+	h.Press("enter")
+	h.Type("Bump every dependency to its latest patch release")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "Processing complete!")
+
+	prs := github.PullRequests()
+	if len(prs) != 2 {
+		t.Fatalf("expected one new PR and the existing one, got %+v", prs)
+	}
+	if prs[0].Title != "Bump dependencies" || prs[0].Body == "Old description" {
+		t.Errorf("expected the open PR's title and description to be updated, got %+v", prs[0])
+	}
+	r := h.Result().ProcessResults["payments-api"]
+	if !r.Success || r.PRURL != strings.TrimSpace(string(existing)) || !strings.Contains(r.Status, "Updated PR") {
+		t.Errorf("expected payments-api to report the updated PR, got %+v", r)
+	}
+}
+
+func TestRunWaitsForChecks(t *testing.T) {
+	github := &gittest.Fake{
+		Files:  map[string]string{"README.md": "# Service\n"},
+		Checks: map[string]git.Checks{"ledger-service": {Passed: 2}, "payments-api": {Passed: 1, Failed: 1, Failing: []string{"lint"}}},
+	}
+	agent := &aitest.Fake{Files: map[string]string{"CHANGELOG.md": "Bumped the timeout.\n"}, Output: "Bumped the timeout."}
+	h := newHarness(t, harnessProjects, github, agent, func(cfg *config.Config) {
+		cfg.CIChecks = config.CIChecks{TimeoutMinutes: 1}
+	})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Perform Changes Locally")
+	h.Press("enter", "enter")
+	h.Type("Bump the timeout")
+	h.Press("enter")
+	h.Type("Raise the HTTP timeout to 30s")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "CI red ❌ lint")
+	h.waitForText(t, "CI green: 1  CI red: 1")
+}
+
+func TestWatchChecksGivesUp(t *testing.T) {
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}, Checks: map[string]git.Checks{"payments-api": {Pending: 1}}}
+	previous := gitHub
+	gitHub = github
+	t.Cleanup(func() { gitHub = previous })
+	url, _ := github.CreatePullRequest(context.Background(), config.Project{Repo: "payments-api"}, "", "copycat", "Bump", "", git.PullRequestOptions{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var statuses []string
+	watchChecks(ctx, 10*time.Millisecond, map[string]string{"payments-api": strings.TrimSpace(string(url))}, func(repo, status string) {
+		statuses = append(statuses, status)
+	})
+	if len(statuses) != 2 || statuses[0] != "CI pending ⏳ 1 of 1" || statuses[1] != "CI pending ⏳ 1 of 1, gave up waiting" {
+		t.Errorf("expected the pending status, then giving up, got %q", statuses)
+	}
+}
+
+func TestWatchChecksReportsFailures(t *testing.T) {
+	previous := gitHub
+	gitHub = &gittest.Fake{}
+	t.Cleanup(func() { gitHub = previous })
+
+	var statuses []string
+	watchChecks(context.Background(), 10*time.Millisecond, map[string]string{"payments-api": "https://github.com/fake-org/payments-api/pull/1"}, func(repo, status string) {
+		statuses = append(statuses, status)
+	})
+	if len(statuses) != 1 || statuses[0] != "CI unknown ⚠️ no pull request https://github.com/fake-org/payments-api/pull/1" {
+		t.Errorf("expected why the checks couldn't be fetched, got %q", statuses)
+	}
+}
+
+func TestRunAssessment(t *testing.T) {
+	github := &gittest.Fake{}
+	agent := &aitest.Fake{Output: "Yes, with a 10s timeout."}
+	h := newHarness(t, harnessProjects, github, agent)
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Run Assessment")
+	h.Press("down", "enter")
+	h.Type("Do we set HTTP timeouts?")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "Assessment Complete!")
+
+	result := h.Result()
+	if result.AssessmentSummary != "Findings from ledger-service, payments-api." {
+		t.Errorf("unexpected summary %q", result.AssessmentSummary)
+	}
+	if got := result.AssessmentFindings["payments-api"]; got != "Yes, with a 10s timeout." {
+		t.Errorf("unexpected finding %q", got)
+	}
+	if len(github.Pushes()) != 0 || len(github.PullRequests()) != 0 {
+		t.Errorf("an assessment must not push or open PRs")
+	}
+}
+
+func TestRunLinksRelatedPRs(t *testing.T) {
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}}
+	agent := &aitest.Fake{Files: map[string]string{"CHANGELOG.md": "Bumped.\n"}, Output: "Bumped the timeout."}
+	h := newHarness(t, harnessProjects, github, agent)
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Perform Changes Locally")
+	h.Press("enter", "enter")
+	h.Type("Bump the timeout")
+	h.Press("enter")
+	h.Type("Raise the HTTP timeout to 30s")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "Linked related PRs in 2 PR descriptions")
+
+	prs := github.PullRequests()
+	if len(prs) != 2 {
+		t.Fatalf("expected a PR per repo, got %+v", prs)
+	}
+	for i, pr := range prs {
+		other := prs[1-i]
+		if !strings.Contains(pr.Body, "Bumped the timeout.") || !strings.Contains(pr.Body, "### Related PRs") {
+			t.Errorf("expected the description followed by the related PRs, got %q", pr.Body)
+		}
+		if !strings.Contains(pr.Body, "- "+other.URL) || strings.Contains(pr.Body, "- "+pr.URL) {
+			t.Errorf("expected %s to link %s but not itself, got %q", pr.Repo, other.URL, pr.Body)
+		}
+	}
+}
+
+func TestRunEnforcesPRPolicy(t *testing.T) {
+	github := &gittest.Fake{Files: map[string]string{"README.md": "# Service\n"}}
+	agent := &aitest.Fake{Files: map[string]string{"CHANGELOG.md": "Bumped.\n"}, Output: "Bumped the timeout."}
+	h := newHarness(t, harnessProjects[1:], github, agent, func(cfg *config.Config) {
+		cfg.PRPolicy = config.PRPolicy{RequiredTags: []string{"[automated]"}, DescriptionPattern: `Ticket: `}
+	})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Perform Changes Locally")
+	h.Press("enter", "enter")
+	h.Type("Bump the timeout")
+	h.Press("enter")
+	h.waitForText(t, "PR title must include [automated]")
+	h.Type(" [automated]")
+	h.Press("enter")
+	h.Type("Raise the HTTP timeout to 30s")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "Processing complete!")
+
+	if len(github.Pushes()) != 0 || len(github.PullRequests()) != 0 {
+		t.Errorf("expected nothing pushed for a description breaking the policy")
+	}
+	if r := h.Result().ProcessResults["payments-api"]; r.Success || !strings.Contains(r.Status, "not pushing: PR description must match Ticket: ") {
+		t.Errorf("expected the run to fail on the description, got %+v", r)
+	}
+}
+
+func TestRunCreatesIssues(t *testing.T) {
+	github := &gittest.Fake{IssueErrors: map[string]error{"ledger-service": errors.New("issues are disabled")}}
+	h := newHarness(t, harnessProjects, github, &aitest.Fake{})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Create GitHub Issues")
+	h.Press("down", "down", "down", "down", "enter")
+	h.Type("Enable Dependabot")
+	h.Press("ctrl+l")
+	h.Type("security")
+	h.Press("enter")
+	h.waitForText(t, "✓ Labels: security")
+	h.Type("Add a dependabot.yml covering every package ecosystem")
+	h.Press("ctrl+s")
+	h.waitForText(t, "Processing complete!")
+
+	issues := github.Issues()
+	if len(issues) != 1 || issues[0].Repo != "payments-api" || issues[0].Title != "Enable Dependabot" {
+		t.Fatalf("expected one issue for payments-api, got %+v", issues)
+	}
+	if !slices.Equal(issues[0].Labels, []string{"security"}) || !slices.Equal(issues[0].Assignees, []string{"copilot"}) {
+		t.Errorf("expected the labels from the wizard and copilot assigned, got %+v", issues[0])
+	}
+	if len(github.Pushes()) != 0 || len(github.PullRequests()) != 0 {
+		t.Errorf("creating issues must not push or open PRs")
+	}
+
+	result := h.Result()
+	if result == nil || result.Action != "issues" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if r := result.ProcessResults["payments-api"]; !r.Success || r.PRURL != issues[0].URL {
+		t.Errorf("unexpected payments-api result %+v", r)
+	}
+
+	// Retrying the failed repo opens its issue once it can be created
+	github.IssueErrors = nil
+	h.Press("r")
+	h.waitForText(t, "Processing complete!")
+	if issues := github.Issues(); len(issues) != 2 || issues[1].Repo != "ledger-service" {
+		t.Errorf("expected the retry to open the ledger-service issue, got %+v", issues)
+	}
+}
+
+func TestRunCreatesOneIssuePerMonorepo(t *testing.T) {
+	github := &gittest.Fake{}
+	projects := []config.Project{{Repo: "platform", Service: "billing"}, {Repo: "platform", Service: "ledger"}}
+	h := newHarness(t, projects, github, &aitest.Fake{})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Create GitHub Issues")
+	h.Press("down", "down", "down", "down", "enter")
+	h.Type("Enable Dependabot")
+	h.Press("ctrl+l", "enter")
+	h.Type("Add a dependabot.yml covering every package ecosystem")
+	h.Press("ctrl+s")
+	h.waitForText(t, "Processing complete!")
+
+	issues := github.Issues()
+	if len(issues) != 1 || issues[0].Repo != "platform" {
+		t.Fatalf("expected one issue for the monorepo, got %+v", issues)
+	}
+	for _, key := range []string{"platform/billing", "platform/ledger"} {
+		if r := h.Result().ProcessResults[key]; !r.Success || r.PRURL != issues[0].URL {
+			t.Errorf("expected %s to report the issue, got %+v", key, r)
+		}
+	}
+}
+
+func TestIssuesListsRecordedIssues(t *testing.T) {
+	github := &gittest.Fake{}
+	h := newHarness(t, harnessProjects, github, &aitest.Fake{})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Create GitHub Issues")
+	h.Press("down", "down", "down", "down", "enter")
+	h.Type("Enable Dependabot")
+	h.Press("ctrl+l", "enter")
+	h.Type("Add a dependabot.yml covering every package ecosystem")
+	h.Press("ctrl+s")
+	h.waitForText(t, "Processing complete!")
+
+	path, err := config.HistoryPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := history.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues := history.CreatedIssues(records)
+	if len(issues) != 2 || issues[0].Title != "Enable Dependabot" {
+		t.Fatalf("expected both issues to be recorded, got %+v", issues)
+	}
+
+	closed := github.Issues()[0].URL
+	github.IssueStates = map[string]git.IssueState{closed: {State: "closed", StateReason: "not_planned"}}
+	var out strings.Builder
+	listIssues(context.Background(), &out, github, issues, "closed")
+	if !strings.Contains(out.String(), closed) || !strings.Contains(out.String(), "not planned") {
+		t.Errorf("expected the closed issue listed with why, got:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), github.Issues()[1].URL) {
+		t.Errorf("expected the open issue to be filtered out, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "2 issues: 1 open, 1 closed") {
+		t.Errorf("expected a summary of both issues, got:\n%s", out.String())
+	}
+}
+
+func TestRunFillsIssueTemplate(t *testing.T) {
+	github := &gittest.Fake{Templates: map[string][]issuetemplate.Template{"ledger-service": {{
+		Name:   "Dependency bump",
+		Title:  "[deps]: ",
+		Labels: []string{"dependencies"},
+		Fields: []issuetemplate.Field{
+			{Type: issuetemplate.FieldInput, Label: "Package", Required: true},
+			{Type: issuetemplate.FieldDropdown, Label: "Severity", Options: []string{"low", "high"}},
+		},
+	}}}}
+	h := newHarness(t, harnessProjects, github, &aitest.Fake{}, func(cfg *config.Config) {
+		cfg.Issues.Templates = []issuetemplate.Template{{Name: "Chore", Body: "Tidy up"}}
+	})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Create GitHub Issues")
+	h.Press("down", "down", "down", "down", "enter")
+	h.Press("ctrl+t")
+	h.waitForText(t, "Dependency bump")
+	h.waitForText(t, "Chore")
+	// reopened, so the repo's template, read in the background, is first
+	h.Press("esc", "ctrl+t", "enter", "enter")
+	h.waitForText(t, "Package is required")
+	h.Type("lodash")
+	h.Press("enter")
+	h.waitForText(t, "Severity (2/2)")
+	h.Type("2")
+	h.Press("enter")
+	h.waitForText(t, "[deps]: ")
+	h.Type("Bump lodash")
+	h.Press("enter")
+	h.waitForText(t, "✓ Labels: dependencies")
+	h.Press("ctrl+s")
+	h.waitForText(t, "Processing complete!")
+
+	issues := github.Issues()
+	if len(issues) != 2 {
+		t.Fatalf("expected an issue per repo, got %+v", issues)
+	}
+	issue := issues[0]
+	if issue.Title != "[deps]: Bump lodash" || !slices.Equal(issue.Labels, []string{"dependencies"}) {
+		t.Errorf("expected the template's title and labels, got %+v", issue)
+	}
+	if !strings.Contains(issue.Body, "### Package\n\nlodash") || !strings.Contains(issue.Body, "### Severity\n\nhigh") {
+		t.Errorf("expected the filled in form as the description, got %q", issue.Body)
+	}
+}
+
+func TestRunChecksIssueAssignees(t *testing.T) {
+	for _, unassigned := range []map[string]bool{nil, {"joe": true}} {
+		github := &gittest.Fake{
+			Teams:      map[string][]string{"acme/payments": {"jane", "joe"}},
+			Unassigned: unassigned,
+		}
+		h := newHarness(t, harnessProjects, github, &aitest.Fake{}, func(cfg *config.Config) {
+			cfg.Issues.Assignees = []string{"acme/payments"}
+		})
+
+		h.Press("a", "enter")
+		h.waitForText(t, "Create GitHub Issues")
+		h.Press("down", "down", "down", "down", "enter")
+		h.Type("Enable Dependabot")
+		h.Press("enter")
+		h.waitForText(t, "✓ Assignees: @acme/payments")
+		h.Type("Add a dependabot.yml")
+		h.Press("ctrl+s")
+		h.waitForText(t, "Processing complete!")
+
+		issues := github.Issues()
+		if unassigned != nil {
+			if len(issues) != 0 {
+				t.Errorf("expected no issues with an assignee that cannot be assigned, got %+v", issues)
+			}
+			if r := h.Result().ProcessResults["payments-api"]; r.Success || !strings.Contains(r.Status, "joe cannot be assigned issues") {
+				t.Errorf("expected the run to fail on the assignee, got %+v", r)
+			}
+			continue
+		}
+		if len(issues) != 2 {
+			t.Fatalf("expected an issue per repo, got %+v", issues)
+		}
+		for _, issue := range issues {
+			if !slices.Equal(issue.Assignees, []string{"jane", "joe"}) {
+				t.Errorf("expected the team's members assigned, got %+v", issue)
+			}
+		}
+	}
+}
+
+func TestAssessmentCreatesJiraTickets(t *testing.T) {
+	var summaries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var issue struct {
+			Fields struct {
+				Summary string `json:"summary"`
+			} `json:"fields"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&issue)
+		summaries = append(summaries, issue.Fields.Summary)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"key":"OPS-%d"}`, len(summaries))
+	}))
+	defer server.Close()
+	t.Setenv(jira.TokenEnv, "secret")
+
+	agent := &aitest.Fake{Output: "No, requests can hang forever."}
+	h := newHarness(t, harnessProjects, &gittest.Fake{}, agent, func(cfg *config.Config) {
+		cfg.Jira = config.JiraConfig{BaseURL: server.URL, Email: "jane@example.com", Project: "OPS"}
+	})
+
+	h.Press("a", "enter")
+	h.waitForText(t, "Run Assessment")
+	h.Press("down", "enter")
+	h.Type("Do we set HTTP timeouts?")
+	h.Press("ctrl+s", "enter")
+	h.waitForText(t, "Assessment Complete!")
+
+	// Pick the first repo on the Projects tab
+	h.Press("2", "down", " ")
+	h.waitForText(t, "Jira: 1 repos picked, one per repo")
+	h.Press("J")
+	h.waitForText(t, "/browse/OPS-1")
+
+	if len(summaries) != 1 || !strings.HasSuffix(summaries[0], ": Do we set HTTP timeouts?") {
+		t.Errorf("expected one ticket for the picked repo, got %q", summaries)
+	}
+}
+
+func TestPRPacer(t *testing.T) {
+	pacer := &prPacer{pacing: config.PRPacing{PerMinute: 1200}} // one PR every 50ms
+	var statuses []string
+	status := func(s string) { statuses = append(statuses, s) }
+
+	start := time.Now()
+	for range 3 {
+		if err := pacer.wait(context.Background(), status); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected three PRs to take at least two intervals, took %s", elapsed)
+	}
+	if len(statuses) != 2 || !strings.Contains(statuses[0], "to open PR (pacing)") {
+		t.Errorf("expected the waiting PRs to say so, got %q", statuses)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := pacer.wait(ctx, status); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled wait to return, got %v", err)
+	}
+	var unpaced *prPacer
+	if err := unpaced.wait(ctx, status); err != nil {
+		t.Errorf("expected no pacing without a pacer, got %v", err)
+	}
+}
+
+func TestMergeProjectsKeepsPathEntries(t *testing.T) {
+	existing := []config.Project{
+		{Repo: "mono", Path: "apps/web", SlackRoom: "#web"},
+		{Repo: "mono", Path: "apps/admin"},
+	}
+	fetched := []config.Project{{Repo: "mono", Language: "TypeScript"}, {Repo: "ledger"}}
+
+	merged := mergeProjects(existing, fetched)
+	if len(merged) != 3 {
+		t.Fatalf("expected one entry per path plus the new repo, got %+v", merged)
+	}
+	if merged[0].Path != "apps/web" || merged[0].SlackRoom != "#web" || merged[1].Path != "apps/admin" || merged[1].Language != "TypeScript" {
+		t.Errorf("expected each path entry to keep its settings and get the fetched data, got %+v", merged[:2])
+	}
+}
+
+func TestLoadExample(t *testing.T) {
+	url := "https://github.com/fake/payments-api/pull/7"
+	previous := gitHub
+	gitHub = &gittest.Fake{Diffs: map[string]string{url: "diff --git a/go.mod b/go.mod\n-go 1.24\n+go 1.25\n"}}
+	t.Cleanup(func() { gitHub = previous })
+
+	projects, note, err := loadExample(url, harnessProjects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 1 || projects[0].Repo != "ledger-service" {
+		t.Errorf("expected the reference PR's own repo to be left out, got %+v", projects)
+	}
+	if !strings.Contains(note, "+go 1.25") {
+		t.Errorf("expected the diff in the prompt context, got %q", note)
+	}
+
+	if _, _, err := loadExample("https://github.com/fake/payments-api/pull/8", harnessProjects); err == nil {
+		t.Error("expected an unknown PR to fail")
+	}
+}